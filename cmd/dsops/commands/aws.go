@@ -0,0 +1,381 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/systmms/dsops/internal/atomicfile"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// awsCredentialRefreshWindow is how long before expiry cached credentials are
+// replaced. The AWS SDKs start refreshing 5 to 15 minutes before expiry, so
+// cached credentials must outlive that window.
+const awsCredentialRefreshWindow = 15 * time.Minute
+
+// awsCredentialStoreTypes are the store types that issue temporary AWS
+// credentials
+var awsCredentialStoreTypes = map[string]bool{
+	"aws.sts": true,
+	"aws.sso": true,
+}
+
+// awsCredentials is the output of an AWS credential_process
+type awsCredentials struct {
+	Version         int       `json:"Version"`
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// NewAWSCommand creates the parent 'aws' command
+func NewAWSCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "aws",
+		Short: "Share aws.sts and aws.sso credentials with AWS tooling",
+		Long: `Hand the temporary credentials of an aws.sts or aws.sso store to the AWS
+CLI, SDKs and other AWS tools.
+
+Examples:
+  dsops aws credential-process --store prod-deploy
+  dsops aws write-profile --store prod-deploy --profile prod`,
+	}
+
+	cmd.AddCommand(
+		NewAWSCredentialProcessCommand(cfg),
+		NewAWSWriteProfileCommand(cfg),
+	)
+
+	return cmd
+}
+
+// NewAWSCredentialProcessCommand creates the 'aws credential-process' command
+func NewAWSCredentialProcessCommand(cfg *config.Config) *cobra.Command {
+	var (
+		store   string
+		noCache bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "credential-process",
+		Short: "Print credentials in the AWS credential_process format",
+		Long: `Print the credentials of an aws.sts or aws.sso store as the JSON an AWS
+credential_process returns. Reference it from ~/.aws/config with an absolute
+config path, since AWS tools run it from any directory:
+
+  [profile prod]
+  credential_process = dsops --config /path/to/dsops.yaml aws credential-process --store prod-deploy
+
+Credentials are cached, readable by the owner only, in the user cache
+directory and reused until 15 minutes before they expire.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cachePath, err := openAWSCredentialStore(cfg, store)
+			if err != nil {
+				return err
+			}
+			if noCache {
+				cachePath = ""
+			}
+
+			creds, err := loadAWSCredentials(cmd.Context(), p, cachePath)
+			if err != nil {
+				return err
+			}
+
+			return json.NewEncoder(cmd.OutOrStdout()).Encode(creds)
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "", "aws.sts or aws.sso store to take credentials from")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Fetch new credentials instead of using cached ones")
+	_ = cmd.MarkFlagRequired("store")
+
+	return cmd
+}
+
+// NewAWSWriteProfileCommand creates the 'aws write-profile' command
+func NewAWSWriteProfileCommand(cfg *config.Config) *cobra.Command {
+	var (
+		store           string
+		profile         string
+		credentialsFile string
+		force           bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "write-profile",
+		Short: "Write store credentials to a profile in ~/.aws/credentials",
+		Long: `Write the credentials of an aws.sts or aws.sso store to a named profile in
+the AWS shared credentials file, for tools that cannot run a
+credential_process. The profile records when the credentials expire in
+x_security_token_expires; run the command again to renew them.
+
+Other profiles in the file are left as they are. A profile that dsops did not
+write is only replaced with --force.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if profile == "" {
+				profile = store
+			}
+			if credentialsFile == "" {
+				path, err := awsCredentialsFilePath()
+				if err != nil {
+					return err
+				}
+				credentialsFile = path
+			}
+
+			p, cachePath, err := openAWSCredentialStore(cfg, store)
+			if err != nil {
+				return err
+			}
+
+			creds, err := loadAWSCredentials(cmd.Context(), p, cachePath)
+			if err != nil {
+				return err
+			}
+
+			if err := writeAWSProfile(credentialsFile, profile, store, creds, force); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Wrote profile '%s' to %s (expires %s)\n",
+				profile, credentialsFile, creds.Expiration.Local().Format(time.RFC1123))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "", "aws.sts or aws.sso store to take credentials from")
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile name to write (default: the store name)")
+	cmd.Flags().StringVar(&credentialsFile, "credentials-file", "", "Shared credentials file (default: $AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials)")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace a profile that dsops did not write")
+	_ = cmd.MarkFlagRequired("store")
+
+	return cmd
+}
+
+// openAWSCredentialStore creates the provider of an aws.sts or aws.sso store
+// and returns the path its credentials are cached at
+func openAWSCredentialStore(cfg *config.Config, store string) (provider.Provider, string, error) {
+	if err := cfg.Load(); err != nil {
+		return nil, "", err
+	}
+
+	providerConfig, err := cfg.GetProvider(store)
+	if err != nil {
+		return nil, "", err
+	}
+	if !awsCredentialStoreTypes[providerConfig.Type] {
+		return nil, "", dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' (%s) does not issue AWS credentials", store, providerConfig.Type),
+			Suggestion: "Use an aws.sts or aws.sso store",
+		}
+	}
+
+	p, err := providers.NewRegistry().CreateProvider(store, providerConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create store '%s': %w", store, err)
+	}
+
+	cachePath, err := awsCredentialCachePath(cfg.Path, store, providerConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, cachePath, nil
+}
+
+// awsCredentialCachePath returns the cache file for a store. The name hashes
+// the config path and the store definition, so editing the store starts a
+// new cache.
+func awsCredentialCachePath(configPath, store string, providerConfig config.ProviderConfig) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%v", configPath, store, providerConfig)))
+	return filepath.Join(dir, "dsops", "aws", hex.EncodeToString(sum[:16])+".json"), nil
+}
+
+// loadAWSCredentials returns the cached credentials while they stay valid
+// beyond the refresh window, and otherwise fetches and caches new ones. An
+// empty cachePath disables the cache.
+func loadAWSCredentials(ctx context.Context, p provider.Provider, cachePath string) (*awsCredentials, error) {
+	if cachePath != "" {
+		if creds, err := readAWSCredentialCache(cachePath); err == nil && time.Until(creds.Expiration) > awsCredentialRefreshWindow {
+			return creds, nil
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	value, err := p.Resolve(ctx, provider.Reference{Provider: p.Name(), Key: "credentials"})
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(value.Value), &fields); err != nil {
+		return nil, fmt.Errorf("store '%s' returned malformed credentials: %w", p.Name(), err)
+	}
+	expiration, err := time.Parse(time.RFC3339, fields["Expiration"])
+	if err != nil {
+		return nil, fmt.Errorf("store '%s' returned an invalid expiration: %w", p.Name(), err)
+	}
+	creds := &awsCredentials{
+		Version:         1,
+		AccessKeyID:     fields["AccessKeyId"],
+		SecretAccessKey: fields["SecretAccessKey"],
+		SessionToken:    fields["SessionToken"],
+		Expiration:      expiration.UTC(),
+	}
+
+	if cachePath != "" {
+		data, err := json.Marshal(creds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal AWS credentials: %w", err)
+		}
+		if err := atomicfile.Write(cachePath, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to cache AWS credentials: %w", err)
+		}
+	}
+	return creds, nil
+}
+
+// readAWSCredentialCache reads credentials cached by loadAWSCredentials
+func readAWSCredentialCache(path string) (*awsCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var creds awsCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// awsCredentialsFilePath returns the AWS shared credentials file
+func awsCredentialsFilePath() (string, error) {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".aws", "credentials"), nil
+}
+
+// awsProfileMarker starts the comment dsops writes at the top of its profiles
+const awsProfileMarker = "# Written by dsops"
+
+// writeAWSProfile replaces or appends the profile in the credentials file,
+// keeping the rest of the file as it is
+func writeAWSProfile(path, profile, store string, creds *awsCredentials, force bool) error {
+	var lines []string
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	section := []string{
+		"[" + profile + "]",
+		fmt.Sprintf("%s from store '%s'; renew with: dsops aws write-profile --store %s --profile %s", awsProfileMarker, store, store, profile),
+		"aws_access_key_id = " + creds.AccessKeyID,
+		"aws_secret_access_key = " + creds.SecretAccessKey,
+		"aws_session_token = " + creds.SessionToken,
+		"x_security_token_expires = " + creds.Expiration.UTC().Format(time.RFC3339),
+	}
+
+	start, end := findINISection(lines, profile)
+	if start < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, section...)
+	} else {
+		if !force && !sectionWrittenByDsops(lines[start+1:end]) {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Profile '%s' in %s was not written by dsops", profile, path),
+				Suggestion: "Choose another --profile, or pass --force to replace it",
+			}
+		}
+		lines = append(lines[:start], append(section, lines[end:]...)...)
+	}
+
+	if err := atomicfile.Write(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// findINISection returns the line range of a section, from its header to the
+// next section. Comments and blank lines just before the next header belong
+// to that section. start is -1 when the section is missing.
+func findINISection(lines []string, name string) (start, end int) {
+	start = -1
+	for i, line := range lines {
+		header, ok := iniSectionHeader(line)
+		if !ok {
+			continue
+		}
+		if start >= 0 {
+			end = i
+			for end > start+1 && isINICommentOrBlank(lines[end-1]) {
+				end--
+			}
+			return start, end
+		}
+		if header == name {
+			start = i
+		}
+	}
+	return start, len(lines)
+}
+
+func iniSectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+func isINICommentOrBlank(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+func sectionWrittenByDsops(body []string) bool {
+	for _, line := range body {
+		if strings.HasPrefix(strings.TrimSpace(line), awsProfileMarker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+func newAWSCredentialFake(accessKeyID string, expiration time.Time) *fakes.FakeProvider {
+	creds, _ := json.Marshal(map[string]string{
+		"AccessKeyId":     accessKeyID,
+		"SecretAccessKey": "secret",
+		"SessionToken":    "session",
+		"Expiration":      expiration.Format(time.RFC3339),
+	})
+	return fakes.NewFakeProvider("prod-deploy").WithSecret("credentials", provider.SecretValue{Value: string(creds)})
+}
+
+func TestNewAWSCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewAWSCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "aws", cmd.Use)
+	names := []string{}
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"credential-process", "write-profile"}, names)
+}
+
+func TestLoadAWSCredentials_Cache(t *testing.T) {
+	t.Parallel()
+
+	cachePath := filepath.Join(t.TempDir(), "aws", "store.json")
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	creds, err := loadAWSCredentials(context.Background(), newAWSCredentialFake("AKIA1", expiration), cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, creds.Version)
+	assert.Equal(t, "AKIA1", creds.AccessKeyID)
+	assert.True(t, expiration.Equal(creds.Expiration))
+
+	info, err := os.Stat(cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Cached credentials are reused while they are valid beyond the window
+	creds, err = loadAWSCredentials(context.Background(), newAWSCredentialFake("AKIA2", expiration), cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, "AKIA1", creds.AccessKeyID)
+
+	// Near expiry they are replaced
+	stale, err := json.Marshal(awsCredentials{Version: 1, AccessKeyID: "AKIA1", Expiration: time.Now().Add(5 * time.Minute)})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cachePath, stale, 0600))
+	creds, err = loadAWSCredentials(context.Background(), newAWSCredentialFake("AKIA3", expiration), cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, "AKIA3", creds.AccessKeyID)
+}
+
+func TestLoadAWSCredentials_Format(t *testing.T) {
+	t.Parallel()
+
+	creds, err := loadAWSCredentials(context.Background(), newAWSCredentialFake("AKIA1", time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)), "")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(creds)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Version":1,"AccessKeyId":"AKIA1","SecretAccessKey":"secret","SessionToken":"session","Expiration":"2030-01-02T03:04:05Z"}`, string(data))
+}
+
+func TestWriteAWSProfile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(path, []byte(`[default]
+aws_access_key_id = AKIADEFAULT
+aws_secret_access_key = long-term
+
+# staging keys
+[staging]
+aws_access_key_id = AKIASTAGING
+`), 0600))
+
+	creds := &awsCredentials{AccessKeyID: "AKIA1", SecretAccessKey: "secret", SessionToken: "session", Expiration: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)}
+	require.NoError(t, writeAWSProfile(path, "prod", "prod-deploy", creds, false))
+
+	// Writing again replaces the profile in place
+	creds.AccessKeyID = "AKIA2"
+	require.NoError(t, writeAWSProfile(path, "prod", "prod-deploy", creds, false))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Equal(t, 1, strings.Count(content, "[prod]"))
+	assert.NotContains(t, content, "AKIA1")
+	assert.Contains(t, content, "aws_access_key_id = AKIA2")
+	assert.Contains(t, content, "x_security_token_expires = 2030-01-02T03:04:05Z")
+	assert.Contains(t, content, "aws_access_key_id = AKIADEFAULT")
+	assert.Contains(t, content, "# staging keys\n[staging]\naws_access_key_id = AKIASTAGING")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A profile dsops did not write needs --force
+	err = writeAWSProfile(path, "default", "prod-deploy", creds, false)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "was not written by dsops")
+
+	require.NoError(t, writeAWSProfile(path, "default", "prod-deploy", creds, true))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "AKIADEFAULT")
+	assert.Contains(t, string(data), "# staging keys\n[staging]")
+}
+
+func TestAWSCredentialProcess_RequiresAWSStore(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+
+	_, _, err := openAWSCredentialStore(cfg, "literal-store")
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "does not issue AWS credentials")
+}
+
+func TestAWSCredentialCachePath(t *testing.T) {
+	t.Parallel()
+
+	sts := config.ProviderConfig{Type: "aws.sts", Config: map[string]interface{}{"assume_role": "arn:aws:iam::1:role/a"}}
+	a, err := awsCredentialCachePath("dsops.yaml", "prod", sts)
+	require.NoError(t, err)
+	b, err := awsCredentialCachePath("dsops.yaml", "prod", sts)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	other := config.ProviderConfig{Type: "aws.sts", Config: map[string]interface{}{"assume_role": "arn:aws:iam::1:role/b"}}
+	c, err := awsCredentialCachePath("dsops.yaml", "prod", other)
+	require.NoError(t, err)
+	assert.NotEqual(t, a, c, "editing the store starts a new cache")
+}
@@ -0,0 +1,274 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/certscan"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/resolve"
+)
+
+// NewCertsCommand creates the certs command
+func NewCertsCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Inspect certificates held in secrets",
+		Long: `Inspect X.509 certificates resolved from your secret stores.
+
+Available Commands:
+  scan    Report certificates and their time to expiry`,
+	}
+
+	cmd.AddCommand(newCertsScanCommand(cfg))
+
+	return cmd
+}
+
+// certsScanOptions holds the flags of dsops certs scan
+type certsScanOptions struct {
+	envNames     []string
+	warnDays     int
+	criticalDays int
+	failOn       string
+	format       string
+	notify       bool
+	dataDir      string
+}
+
+func newCertsScanCommand(cfg *config.Config) *cobra.Command {
+	opts := certsScanOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Report certificates and their time to expiry",
+		Long: `Resolve an environment and inspect every value that holds an X.509
+certificate or certificate bundle.
+
+Recognised values:
+  PEM      one or more CERTIFICATE blocks, keys and other blocks are ignored
+  DER      base64 DER certificates
+  PKCS#12  base64 bundles; set metadata.pkcs12_password on the variable to
+           the name of the variable holding the password
+
+Each certificate is reported with its subject, SANs, issuer and days to
+expiry, and classified as ok, warning (--warn-days), critical
+(--critical-days) or expired. The command exits non-zero when a certificate
+reaches --fail-on or a variable cannot be inspected, for use in CI.
+
+Examples:
+  # Scan production
+  dsops certs scan --env production
+
+  # Fail CI on anything expiring within 30 days
+  dsops certs scan --env production --fail-on warning
+
+  # Send certificate_expiring events to the configured notification providers
+  dsops certs scan --env production --notify`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCertsScan(cfg, opts)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.envNames, "env", nil, "Environment to scan (repeatable, default: all)")
+	cmd.Flags().IntVar(&opts.warnDays, "warn-days", certscan.DefaultWarnDays, "Report certificates expiring within this many days as warnings")
+	cmd.Flags().IntVar(&opts.criticalDays, "critical-days", certscan.DefaultCriticalDays, "Report certificates expiring within this many days as critical")
+	cmd.Flags().StringVar(&opts.failOn, "fail-on", "critical", "Exit non-zero at this level: warning, critical, expired, none")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Output format: table, json")
+	cmd.Flags().BoolVar(&opts.notify, "notify", false, "Send certificate_expiring events for warning or worse certificates")
+	cmd.Flags().StringVar(&opts.dataDir, "data-dir", "./dsops-data", "Path to dsops-data repository (optional)")
+
+	return cmd
+}
+
+func runCertsScan(cfg *config.Config, opts certsScanOptions) error {
+	if opts.format != "table" && opts.format != "json" {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported format: %s", opts.format),
+			Suggestion: "Use --format table or --format json",
+		}
+	}
+	switch certscan.Level(opts.failOn) {
+	case certscan.LevelWarning, certscan.LevelCritical, certscan.LevelExpired, "none":
+	default:
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported --fail-on level: %s", opts.failOn),
+			Suggestion: "Use warning, critical, expired or none",
+		}
+	}
+	if opts.criticalDays < 0 || opts.warnDays < opts.criticalDays {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid thresholds: --warn-days %d, --critical-days %d", opts.warnDays, opts.criticalDays),
+			Suggestion: "--critical-days must be zero or more and no greater than --warn-days",
+		}
+	}
+
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	resolver := resolve.New(cfg)
+	if err := registerProviders(resolver, cfg, opts.dataDir); err != nil {
+		return fmt.Errorf("failed to register providers: %w", err)
+	}
+
+	envNames := opts.envNames
+	if len(envNames) == 0 {
+		for name := range cfg.Definition.Envs {
+			envNames = append(envNames, name)
+		}
+		sort.Strings(envNames)
+	}
+
+	now := time.Now()
+	scanner := certscan.NewScanner(certscan.Options{
+		WarnDays:     opts.warnDays,
+		CriticalDays: opts.criticalDays,
+		Now:          now,
+	})
+
+	ctx := context.Background()
+	for _, envName := range envNames {
+		env, err := cfg.GetEnvironment(envName)
+		if err != nil {
+			return err
+		}
+
+		// Variables that fail to resolve are reported by the scan; only
+		// failures before resolution, such as policy violations, stop it
+		resolved, err := resolver.Resolve(ctx, envName)
+		if resolved == nil && err != nil {
+			return err
+		}
+		scanner.ScanEnv(envName, env, resolved)
+	}
+	report := scanner.Report()
+
+	if opts.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		outputCertsTable(os.Stdout, report)
+	}
+
+	if opts.notify {
+		if err := sendCertificateNotifications(cfg, report, now); err != nil {
+			fmt.Fprintf(os.Stderr, "\n⚠️  Failed to send notifications: %v\n", err)
+		}
+	}
+
+	return certsError(report, certscan.Level(opts.failOn))
+}
+
+// sendCertificateNotifications delivers one certificate_expiring event per
+// variable with a certificate at warning or worse
+func sendCertificateNotifications(cfg *config.Config, report *certscan.Report, now time.Time) error {
+	notifier, err := newNotificationManager(cfg.Definition.Notifications)
+	if err != nil {
+		return dserrors.ConfigError{
+			Field:      "notifications",
+			Message:    err.Error(),
+			Suggestion: "Fix the notifications section in dsops.yaml",
+		}
+	}
+	if notifier == nil {
+		return dserrors.UserError{
+			Message:    "No notification providers configured",
+			Suggestion: "Add slack, email, pagerduty or webhooks under notifications in dsops.yaml",
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	sent, failed := 0, 0
+	for _, event := range certscan.Events(report, certscan.LevelWarning, now) {
+		for _, result := range notifier.Deliver(ctx, event) {
+			if result.Err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "⚠️  %s: %s/%s: %v\n", result.Provider, event.Environment, event.Service, result.Err)
+				continue
+			}
+			sent++
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\n📣 Sent %d certificate notification(s)", sent)
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, ", %d failed", failed)
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// certsError returns an error when a certificate reaches the fail-on level
+// or a variable could not be inspected, so CI fails
+func certsError(report *certscan.Report, failOn certscan.Level) error {
+	if failOn == "none" {
+		return nil
+	}
+
+	failing := 0
+	for _, f := range report.Findings {
+		if f.Level == certscan.LevelError || f.Level.AtLeast(failOn) {
+			failing++
+		}
+	}
+	if failing == 0 {
+		return nil
+	}
+	return fmt.Errorf("certificate scan failed: %d finding(s) at %s or worse", failing, failOn)
+}
+
+func outputCertsTable(out io.Writer, report *certscan.Report) {
+	if report.Certificates() == 0 && report.Count(certscan.LevelError) == 0 {
+		_, _ = fmt.Fprintf(out, "✓ No certificates found in %d variable(s)\n", report.Scanned)
+		return
+	}
+
+	if report.Certificates() > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "LEVEL\tENV\tVARIABLE\tSUBJECT\tSANS\tISSUER\tEXPIRES\tDAYS")
+		_, _ = fmt.Fprintln(w, "-----\t---\t--------\t-------\t----\t------\t-------\t----")
+		for _, f := range report.Findings {
+			if f.Certificate == nil {
+				continue
+			}
+			variable := f.Variable
+			if f.Position > 0 {
+				variable = fmt.Sprintf("%s[%d]", f.Variable, f.Position)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+				f.Level, f.Env, variable, valueOrDash(f.Certificate.Subject),
+				valueOrDash(strings.Join(f.Certificate.SANs, ",")), valueOrDash(f.Certificate.Issuer),
+				f.Certificate.NotAfter.UTC().Format("2006-01-02"), f.DaysToExpiry)
+		}
+		_ = w.Flush()
+	}
+
+	if report.Count(certscan.LevelError) > 0 {
+		_, _ = fmt.Fprintln(out, "\nCould not inspect:")
+		for _, f := range report.Findings {
+			if f.Level == certscan.LevelError {
+				_, _ = fmt.Fprintf(out, "  %s/%s: %s\n", f.Env, f.Variable, f.Message)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\nCertificate summary: %d certificate(s) in %d variable(s): %d expired, %d critical, %d warning, %d ok, %d error(s)\n",
+		report.Certificates(), report.Scanned, report.Count(certscan.LevelExpired), report.Count(certscan.LevelCritical),
+		report.Count(certscan.LevelWarning), report.Count(certscan.LevelOK), report.Count(certscan.LevelError))
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/certscan"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/protocol"
+)
+
+func TestNewCertsCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewCertsCommand(&config.Config{Logger: logging.New(false, true)})
+	assert.Equal(t, "certs", cmd.Use)
+
+	scan, _, err := cmd.Find([]string{"scan"})
+	require.NoError(t, err)
+	assert.Equal(t, "scan", scan.Use)
+	for _, flag := range []string{"env", "warn-days", "critical-days", "fail-on", "format", "notify", "data-dir"} {
+		assert.NotNil(t, scan.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+}
+
+func TestRunCertsScan_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Logger: logging.New(false, true)}
+	valid := certsScanOptions{format: "table", failOn: "critical", warnDays: 30, criticalDays: 7}
+
+	tests := map[string]struct {
+		modify func(o *certsScanOptions)
+		want   string
+	}{
+		"format":     {func(o *certsScanOptions) { o.format = "xml" }, "Unsupported format"},
+		"fail-on":    {func(o *certsScanOptions) { o.failOn = "ok" }, "Unsupported --fail-on level"},
+		"thresholds": {func(o *certsScanOptions) { o.criticalDays = 60 }, "Invalid thresholds"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			err := runCertsScan(cfg, opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestOutputCertsTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no certificates", func(t *testing.T) {
+		var out bytes.Buffer
+		report := &certscan.Report{Scanned: 4}
+		outputCertsTable(&out, report)
+		assert.Contains(t, out.String(), "No certificates found in 4 variable(s)")
+		assert.NoError(t, certsError(report, certscan.LevelWarning))
+	})
+
+	t.Run("findings", func(t *testing.T) {
+		notAfter := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+		report := &certscan.Report{Scanned: 3, Findings: []certscan.Finding{
+			{Env: "prod", Variable: "TLS", Level: certscan.LevelWarning, DaysToExpiry: 9, Certificate: &protocol.CertificateInfo{
+				Subject: "CN=api.example.com", Issuer: "CN=Example CA", SANs: []string{"DNS:api.example.com", "IP:10.0.0.1"}, NotAfter: notAfter,
+			}},
+			{Env: "prod", Variable: "TLS", Position: 1, Level: certscan.LevelOK, DaysToExpiry: 400, Certificate: &protocol.CertificateInfo{
+				Subject: "CN=Example CA", Issuer: "CN=Example CA", NotAfter: notAfter.AddDate(1, 0, 0),
+			}},
+			{Env: "prod", Variable: "BROKEN", Level: certscan.LevelError, Message: "could not be resolved: denied"},
+		}}
+
+		var out bytes.Buffer
+		outputCertsTable(&out, report)
+		assert.Contains(t, out.String(), "CN=api.example.com")
+		assert.Contains(t, out.String(), "DNS:api.example.com,IP:10.0.0.1")
+		assert.Contains(t, out.String(), "TLS[1]")
+		assert.Contains(t, out.String(), "2026-06-10")
+		assert.Contains(t, out.String(), "prod/BROKEN: could not be resolved: denied")
+		assert.Contains(t, out.String(), "2 certificate(s) in 3 variable(s): 0 expired, 0 critical, 1 warning, 1 ok, 1 error(s)")
+
+		err := certsError(report, certscan.LevelWarning)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 finding(s) at warning or worse")
+
+		// Errors fail the scan at any level except none
+		err = certsError(report, certscan.LevelExpired)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 finding(s)")
+		assert.NoError(t, certsError(report, "none"))
+	})
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+func NewDeleteCommand(cfg *config.Config) *cobra.Command {
+	var (
+		store   string
+		key     string
+		envName string
+		force   bool
+		yes     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a secret from a secret store",
+		Long: `Delete a secret, or a single field of a JSON secret, from a writable store.
+
+Stores with soft deletion keep the secret recoverable: AWS Secrets Manager
+uses its recovery window, Azure Key Vault soft-deletes, and Vault KV v2
+deletes only the latest version. --force skips recovery where the store
+supports it (AWS force delete, Azure purge, Vault KV v2 metadata delete).
+
+You are always asked to confirm unless --yes is given. Writes are checked
+against the same policies as 'dsops set'.
+
+Examples:
+  dsops delete --store aws-prod --key myapp/old-api-key
+  dsops delete --store vault --key secret/data/app#legacy_token
+  dsops delete --store aws-prod --key myapp/leaked --force --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDelete(cfg, store, key, envName, force, yes)
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "", "Secret store to delete from (required)")
+	cmd.Flags().StringVar(&key, "key", "", "Secret key within the store (required)")
+	cmd.Flags().StringVar(&envName, "env", "", "Environment whose policy rules apply")
+	cmd.Flags().BoolVar(&force, "force", false, "Delete immediately without a recovery window")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	_ = cmd.MarkFlagRequired("store")
+	_ = cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+func runDelete(cfg *config.Config, store, key, envName string, force, yes bool) error {
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	writer, _, err := openWriter(cfg, store, envName)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		prompt := fmt.Sprintf("Delete %s from %s?", key, store)
+		if force {
+			prompt = fmt.Sprintf("Permanently delete %s from %s? This cannot be undone.", key, store)
+		}
+		confirmed, err := confirmWrite(cfg, prompt, isTerminal(os.Stdin))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, "Delete cancelled")
+			return nil
+		}
+	}
+
+	err = writer.DeleteSecret(context.Background(), provider.Reference{Provider: store, Key: key}, provider.DeleteOptions{Force: force})
+	if err != nil {
+		if provider.IsNotFound(err) {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Secret '%s' not found in store '%s'", key, store),
+				Suggestion: "Check the key and store name",
+				Err:        err,
+			}
+		}
+		return fmt.Errorf("failed to delete %s from %s: %w", key, store, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "✅ Deleted %s from %s\n", key, store)
+	return nil
+}
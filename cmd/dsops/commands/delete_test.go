@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+)
+
+func TestNewDeleteCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewDeleteCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "delete", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	for _, flag := range []string{"store", "key", "env", "force", "yes"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+}
+
+func TestRunDelete_ChecksPolicyAndConfirmation(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+
+	err := runDelete(cfg, "vault-store", "secret/data/app", "production", false, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only by policy")
+
+	err = runDelete(cfg, "prod-vault", "secret/data/prod", "", false, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only by policy", "leaving out --env must not bypass policies")
+
+	err = runDelete(cfg, "literal-store", "KEY", "", false, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is read-only")
+
+	// Non-interactive without --yes must not delete
+	err = runDelete(cfg, "vault-store", "secret/data/app", "", false, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires confirmation")
+}
@@ -251,6 +251,21 @@ func getSuggestions(providerType string, err error) []string {
 			suggestions = append(suggestions, "Ensure 'op' command is in your PATH")
 		}
 
+	case "bitwarden.secretsmanager":
+		suggestions = append(suggestions, "Create a machine account access token in Bitwarden Secrets Manager")
+		if contains(err.Error(), "auth") {
+			suggestions = append(suggestions, "Check that the access token has not been revoked or expired")
+			suggestions = append(suggestions, "For self-hosted servers set server_url")
+		}
+
+	case "onepassword.connect", "onepassword.serviceaccount":
+		if contains(err.Error(), "401") || contains(err.Error(), "auth") {
+			suggestions = append(suggestions, "Check that the token is valid and has access to the vaults you use")
+		}
+		if providerType == "onepassword.connect" {
+			suggestions = append(suggestions, "Check that the Connect server at host is reachable")
+		}
+
 	case "aws.secretsmanager":
 		suggestions = append(suggestions, "Configure AWS credentials via CLI, env vars, or IAM roles")
 		if contains(err.Error(), "authentication") || contains(err.Error(), "credentials") {
@@ -267,6 +282,17 @@ func getSuggestions(providerType string, err error) []string {
 		suggestions = append(suggestions, "Run: aws configure")
 		suggestions = append(suggestions, "Or set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
 
+	case "aws.sts":
+		suggestions = append(suggestions, "Configure the source credentials via CLI, env vars, or profile")
+		if contains(err.Error(), "role_chain") || contains(err.Error(), "assume role") {
+			suggestions = append(suggestions, "Check that each role's trust policy allows the previous role in role_chain")
+			suggestions = append(suggestions, "Check external_id for roles that require one")
+		}
+		if contains(err.Error(), "MFA") || contains(err.Error(), "MultiFactorAuthentication") {
+			suggestions = append(suggestions, "Check mfa_serial_number and enter a fresh MFA code")
+			suggestions = append(suggestions, "Or set mfa_token_command to a command that prints the code")
+		}
+
 	case "keychain":
 		var keychainErr *providers.KeychainError
 		if errors.As(err, &keychainErr) {
@@ -276,6 +302,7 @@ func getSuggestions(providerType string, err error) []string {
 					suggestions = append(suggestions, "Ensure the secret exists in your keychain")
 					suggestions = append(suggestions, "On macOS: Add via Keychain Access app")
 					suggestions = append(suggestions, fmt.Sprintf("Or use: security add-generic-password -a \"%s\" -s \"%s\" -w", keychainErr.Account, keychainErr.Service))
+					suggestions = append(suggestions, fmt.Sprintf("Or use: dsops keychain set %s/%s", keychainErr.Service, keychainErr.Account))
 				} else if errors.Is(keychainErr.Err, providers.ErrKeychainAccessDenied) {
 					suggestions = append(suggestions, "Keychain access was denied")
 					suggestions = append(suggestions, "On macOS: Check Keychain Access permissions")
@@ -286,6 +313,7 @@ func getSuggestions(providerType string, err error) []string {
 				} else if errors.Is(keychainErr.Err, providers.ErrKeychainHeadless) {
 					suggestions = append(suggestions, "Keychain access requires a GUI session")
 					suggestions = append(suggestions, "Set up a keyring daemon for headless use")
+					suggestions = append(suggestions, "Or set backend: file on the store to use the encrypted file keychain")
 				}
 			}
 		} else {
@@ -299,6 +327,7 @@ func getSuggestions(providerType string, err error) []string {
 			if strings.Contains(errStr, "headless") || strings.Contains(errStr, "GUI") {
 				suggestions = append(suggestions, "Keychain access requires a GUI session")
 				suggestions = append(suggestions, "Set up a keyring daemon for headless use")
+				suggestions = append(suggestions, "Or set backend: file on the store to use the encrypted file keychain")
 			}
 			if strings.Contains(errStr, "not found") {
 				suggestions = append(suggestions, "Ensure the secret exists in your keychain")
@@ -332,6 +332,18 @@ func TestGetSuggestions(t *testing.T) {
 			err:          fmt.Errorf("missing region"),
 			wantContains: []string{"AWS_REGION", "dsops.yaml"},
 		},
+		{
+			name:         "aws sts role chain",
+			providerType: "aws.sts",
+			err:          fmt.Errorf("Failed to assume role 2 of 2 in the role chain: AccessDenied"),
+			wantContains: []string{"trust policy", "external_id"},
+		},
+		{
+			name:         "aws sts mfa",
+			providerType: "aws.sts",
+			err:          fmt.Errorf("MultiFactorAuthentication failed"),
+			wantContains: []string{"mfa_serial_number", "mfa_token_command"},
+		},
 		{
 			name:         "unknown provider",
 			providerType: "unknown",
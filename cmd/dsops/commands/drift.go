@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/drift"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/resolve"
+)
+
+func NewDriftCommand(cfg *config.Config) *cobra.Command {
+	var (
+		envNames []string
+		scan     []string
+		format   string
+		dataDir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare dsops.yaml with the secrets in your stores",
+		Long: `Check that every secret referenced in dsops.yaml exists and is healthy.
+
+Each referenced secret is described (never read) concurrently. Reported:
+  missing             the key or pinned version does not exist
+  stale               last updated longer ago than policies.max_secret_age
+  deprecated_version  a version pin points at a disabled or deprecated version
+  unreferenced        a secret under a --scan prefix that no environment uses
+  error               the store could not be queried
+
+The command exits non-zero when anything is found, for use in CI.
+
+Examples:
+  # Check all environments
+  dsops drift
+
+  # Check production and look for unused secrets under a prefix
+  dsops drift --env production --scan aws-prod/myapp/
+
+  # JSON for CI
+  dsops drift --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDrift(cfg, envNames, scan, format, dataDir)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&envNames, "env", nil, "Environment to check (repeatable, default: all)")
+	cmd.Flags().StringArrayVar(&scan, "scan", nil, "Store prefix to scan for unreferenced secrets, as store[/prefix] (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+	cmd.Flags().StringVar(&dataDir, "data-dir", "./dsops-data", "Path to dsops-data repository (optional)")
+
+	return cmd
+}
+
+func runDrift(cfg *config.Config, envNames, scan []string, format, dataDir string) error {
+	if format != "table" && format != "json" {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported format: %s", format),
+			Suggestion: "Use --format table or --format json",
+		}
+	}
+
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	resolver := resolve.New(cfg)
+	if err := registerProviders(resolver, cfg, dataDir); err != nil {
+		return fmt.Errorf("failed to register providers: %w", err)
+	}
+
+	report, err := drift.NewChecker(cfg, resolver.GetProvider).Check(context.Background(), drift.Options{
+		Envs: envNames,
+		Scan: scan,
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		outputDriftTable(os.Stdout, report)
+	}
+
+	return driftError(report)
+}
+
+// driftError returns an error when the report has findings so CI fails
+func driftError(report *drift.Report) error {
+	if len(report.Findings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("drift detected: %d finding(s)", len(report.Findings))
+}
+
+func outputDriftTable(out io.Writer, report *drift.Report) {
+	if len(report.Findings) == 0 {
+		_, _ = fmt.Fprintf(out, "✓ No drift: %d reference(s) checked", report.Checked)
+		if report.Scanned > 0 {
+			_, _ = fmt.Fprintf(out, ", %d stored secret(s) scanned", report.Scanned)
+		}
+		_, _ = fmt.Fprintln(out)
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tENV\tVARIABLE\tSTORE\tKEY\tDETAILS")
+	_, _ = fmt.Fprintln(w, "----\t---\t--------\t-----\t---\t-------")
+	for _, f := range report.Findings {
+		key := f.Key
+		if f.Version != "" {
+			key += "@" + f.Version
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			f.Kind, valueOrDash(f.Env), valueOrDash(f.Variable), f.Store, key, f.Message)
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintf(out, "\nDrift summary: %d missing, %d stale, %d deprecated version(s), %d unreferenced, %d error(s)\n",
+		report.Count(drift.KindMissing), report.Count(drift.KindStale), report.Count(drift.KindDeprecatedVersion),
+		report.Count(drift.KindUnreferenced), report.Count(drift.KindError))
+}
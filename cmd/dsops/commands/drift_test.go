@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/drift"
+	"github.com/systmms/dsops/internal/logging"
+)
+
+func TestNewDriftCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewDriftCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "drift", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	for _, flag := range []string{"env", "scan", "format", "data-dir"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+
+	plan := NewPlanCommand(&config.Config{Logger: logging.New(false, true)})
+	assert.NotNil(t, plan.Flags().Lookup("check"))
+}
+
+func TestRunDrift_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	err := runDrift(&config.Config{Logger: logging.New(false, true)}, nil, nil, "xml", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported format")
+}
+
+func TestOutputDriftTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean", func(t *testing.T) {
+		var out bytes.Buffer
+		report := &drift.Report{Checked: 3, Scanned: 5}
+		outputDriftTable(&out, report)
+		assert.Contains(t, out.String(), "No drift: 3 reference(s) checked, 5 stored secret(s) scanned")
+		assert.NoError(t, driftError(report))
+	})
+
+	t.Run("findings", func(t *testing.T) {
+		var out bytes.Buffer
+		report := &drift.Report{Checked: 2, Findings: []drift.Finding{
+			{Kind: drift.KindMissing, Env: "prod", Variable: "DB", Store: "aws", Key: "app/db", Message: "secret not found"},
+			{Kind: drift.KindUnreferenced, Store: "aws", Key: "app/orphan", Version: "v2", Message: "not referenced"},
+		}}
+		outputDriftTable(&out, report)
+		assert.Contains(t, out.String(), "app/orphan@v2")
+		assert.Contains(t, out.String(), "1 missing, 0 stale, 0 deprecated version(s), 1 unreferenced, 0 error(s)")
+
+		err := driftError(report)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 finding(s)")
+	})
+}
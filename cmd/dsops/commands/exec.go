@@ -3,11 +3,14 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/spf13/cobra"
+	"github.com/systmms/dsops/internal/audit"
 	"github.com/systmms/dsops/internal/config"
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/execenv"
+	"github.com/systmms/dsops/internal/lease"
 	"github.com/systmms/dsops/internal/resolve"
 	"github.com/systmms/dsops/internal/secure"
 )
@@ -28,6 +31,10 @@ func NewExecCommand(cfg *config.Config) *cobra.Command {
 secret providers. Secrets are injected into the child process environment 
 and never written to disk.
 
+Leased secrets, such as Vault dynamic database credentials, are renewed
+while the command runs and revoked when it exits. Lease IDs, never values,
+are recorded in the audit log.
+
 The command must be separated from dsops arguments with '--'.
 
 Examples:
@@ -71,8 +78,21 @@ Examples:
 				}
 			}
 
-			// Resolve secrets
+			// Keep leased credentials (Vault dynamic secrets) valid while the
+			// command runs and revoke them when it exits. The keeper starts
+			// before resolving so leases issued by a resolution that fails are
+			// revoked on the way out. Exec exits the process with the child's
+			// code, so OnExit revokes as well.
 			ctx := context.Background()
+			keeper := lease.NewKeeper(cfg.Logger, auditLogPath(cfg), resolver.GetRegisteredProviders())
+			var releaseOnce sync.Once
+			release := func() {
+				releaseOnce.Do(func() { keeper.Stop() })
+			}
+			keeper.Start(ctx)
+			defer release()
+
+			// Resolve secrets
 			resolved, err := resolver.Resolve(ctx, envName)
 			if err != nil {
 				// The resolver already returns user-friendly errors
@@ -106,6 +126,9 @@ Examples:
 
 			cfg.Logger.Info("Successfully resolved %d environment variables", len(environment))
 
+			// Start keeping the leases issued while resolving
+			keeper.Collect()
+
 			// Wrap secrets in SecureBuffers for secure handling
 			// This ensures secrets are encrypted in memory until needed
 			secureEnv := make(map[string]*secure.SecureBuffer)
@@ -150,6 +173,9 @@ Examples:
 				WorkingDir:        workingDir,
 				Timeout:           timeout,
 			}
+			if len(keeper.Leases()) > 0 {
+				options.OnExit = release
+			}
 
 			return executor.Exec(ctx, options)
 		},
@@ -165,3 +191,12 @@ Examples:
 
 	return cmd
 }
+
+// auditLogPath returns policies.audit_logging.log_path, or the audit log
+// shared with incident tracking
+func auditLogPath(cfg *config.Config) string {
+	if audit := cfg.GetPolicyEnforcer().GetAuditConfig(); audit != nil && audit.LogPath != "" {
+		return audit.LogPath
+	}
+	return audit.LogName
+}
@@ -21,16 +21,19 @@ func NewGuardCommand(cfg *config.Config) *cobra.Command {
 
 Subcommands:
   repo       Scan repository for committed secrets
+  purge      Rewrite git history to remove leaked secrets
   gitignore  Check .gitignore patterns for secret files
 
 Examples:
   dsops guard repo                    # Scan current repository
   dsops guard repo --path /some/repo  # Scan specific repository
+  dsops guard purge --scan            # Preview removing findings from history
   dsops guard gitignore              # Check .gitignore patterns`,
 	}
 
 	cmd.AddCommand(
 		NewGuardRepoCommand(cfg),
+		NewGuardPurgeCommand(cfg),
 		NewGuardGitignoreCommand(cfg),
 	)
 
@@ -179,7 +182,7 @@ func scanRepository(repoPath string, scanAll, verbose, exitCode bool, customPatt
 
 	fmt.Println("🛡️  Recommendations:")
 	fmt.Println("  1. Review these findings and remove any real secrets")
-	fmt.Println("  2. Use 'git filter-branch' or 'BFG Repo-Cleaner' to purge secrets")
+	fmt.Println("  2. Use 'dsops guard purge --scan' to remove them from git history")
 	fmt.Println("  3. Rotate any exposed credentials immediately")
 	fmt.Println("  4. Use dsops for proper secret management going forward")
 
@@ -278,41 +281,40 @@ type SecretFinding struct {
 	Match       string
 }
 
+// getSecretPatterns returns the built-in detection patterns. Each pattern
+// that matches a value names it with a "secret" group so purge can remove the
+// value and leave the surrounding key name in place.
 func getSecretPatterns() []string {
 	return []string{
-		`(?i)(api[_-]?key|apikey)\s*[:=]\s*['""]?[a-zA-Z0-9]{16,}['""]?`,
-		`(?i)(secret[_-]?key|secretkey)\s*[:=]\s*['""]?[a-zA-Z0-9]{16,}['""]?`,
-		`(?i)(access[_-]?token|accesstoken)\s*[:=]\s*['""]?[a-zA-Z0-9]{16,}['""]?`,
-		`(?i)(auth[_-]?token|authtoken)\s*[:=]\s*['""]?[a-zA-Z0-9]{16,}['""]?`,
-		`(?i)password\s*[:=]\s*['""]?[a-zA-Z0-9!@#$%^&*]{8,}['""]?`,
-		`(?i)passwd\s*[:=]\s*['""]?[a-zA-Z0-9!@#$%^&*]{8,}['""]?`,
-		`(?i)private[_-]?key\s*[:=]`,
-		`-----BEGIN [A-Z ]+PRIVATE KEY-----`,
-		`(?i)aws[_-]?access[_-]?key[_-]?id\s*[:=]\s*['""]?AKIA[A-Z0-9]{16}['""]?`,
-		`(?i)aws[_-]?secret[_-]?access[_-]?key\s*[:=]\s*['""]?[A-Za-z0-9/+=]{40}['""]?`,
-		`(?i)github[_-]?token\s*[:=]\s*['""]?gh[ps]_[A-Za-z0-9]{36}['""]?`,
-		`(?i)slack[_-]?token\s*[:=]\s*['""]?xox[baprs]-[A-Za-z0-9-]{10,}['""]?`,
-		`(?i)discord[_-]?token\s*[:=]\s*['""]?[MN][A-Za-z\d]{23}\.[A-Za-z\d-_]{6}\.[A-Za-z\d-_]{27}['""]?`,
-		`(?i)google[_-]?api[_-]?key\s*[:=]\s*['""]?AIza[A-Za-z0-9-_]{35}['""]?`,
-		`(?i)stripe[_-]?key\s*[:=]\s*['""]?sk_live_[A-Za-z0-9]{24}['""]?`,
-		`(?i)twilio[_-]?auth[_-]?token\s*[:=]\s*['""]?[A-Za-z0-9]{32}['""]?`,
-		`(?i)mailgun[_-]?api[_-]?key\s*[:=]\s*['""]?key-[A-Za-z0-9]{32}['""]?`,
-		`(?i)sendgrid[_-]?api[_-]?key\s*[:=]\s*['""]?SG\.[A-Za-z0-9-_]{22}\.[A-Za-z0-9-_]{43}['""]?`,
+		`(?i)(api[_-]?key|apikey)\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9]{16,})['""]?`,
+		`(?i)(secret[_-]?key|secretkey)\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9]{16,})['""]?`,
+		`(?i)(access[_-]?token|accesstoken)\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9]{16,})['""]?`,
+		`(?i)(auth[_-]?token|authtoken)\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9]{16,})['""]?`,
+		`(?i)password\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9!@#$%^&*]{8,})['""]?`,
+		`(?i)passwd\s*[:=]\s*['""]?(?P<secret>[a-zA-Z0-9!@#$%^&*]{8,})['""]?`,
+		`(?i)private[_-]?key\s*[:=]\s*['""]?(?P<secret>[A-Za-z0-9/+=_-]{16,})?`,
+		privateKeyHeaderPattern,
+		`(?i)aws[_-]?access[_-]?key[_-]?id\s*[:=]\s*['""]?(?P<secret>AKIA[A-Z0-9]{16})['""]?`,
+		`(?i)aws[_-]?secret[_-]?access[_-]?key\s*[:=]\s*['""]?(?P<secret>[A-Za-z0-9/+=]{40})['""]?`,
+		`(?i)github[_-]?token\s*[:=]\s*['""]?(?P<secret>gh[ps]_[A-Za-z0-9]{36})['""]?`,
+		`(?i)slack[_-]?token\s*[:=]\s*['""]?(?P<secret>xox[baprs]-[A-Za-z0-9-]{10,})['""]?`,
+		`(?i)discord[_-]?token\s*[:=]\s*['""]?(?P<secret>[MN][A-Za-z\d]{23}\.[A-Za-z\d-_]{6}\.[A-Za-z\d-_]{27})['""]?`,
+		`(?i)google[_-]?api[_-]?key\s*[:=]\s*['""]?(?P<secret>AIza[A-Za-z0-9-_]{35})['""]?`,
+		`(?i)stripe[_-]?key\s*[:=]\s*['""]?(?P<secret>sk_live_[A-Za-z0-9]{24})['""]?`,
+		`(?i)twilio[_-]?auth[_-]?token\s*[:=]\s*['""]?(?P<secret>[A-Za-z0-9]{32})['""]?`,
+		`(?i)mailgun[_-]?api[_-]?key\s*[:=]\s*['""]?(?P<secret>key-[A-Za-z0-9]{32})['""]?`,
+		`(?i)sendgrid[_-]?api[_-]?key\s*[:=]\s*['""]?(?P<secret>SG\.[A-Za-z0-9-_]{22}\.[A-Za-z0-9-_]{43})['""]?`,
 	}
 }
 
+// privateKeyHeaderPattern detects the first line of a PEM private key. The
+// key itself spans several lines, so purge removes the whole block instead.
+const privateKeyHeaderPattern = `-----BEGIN [A-Z ]*PRIVATE KEY-----`
+
 func scanCommitsForSecrets(repoPath, commitRange string, patterns []string, verbose bool) ([]SecretFinding, error) {
 	var findings []SecretFinding
 
-	// Compile regex patterns
-	var regexes []*regexp.Regexp
-	for _, pattern := range patterns {
-		if regex, err := regexp.Compile(pattern); err == nil {
-			regexes = append(regexes, regex)
-		} else if verbose {
-			fmt.Printf("Warning: Invalid regex pattern: %s\n", pattern)
-		}
-	}
+	regexes := compileSecretPatterns(patterns, verbose)
 
 	// Get git log with file changes
 	var cmd *exec.Cmd
@@ -357,18 +359,27 @@ func scanCommitsForSecrets(repoPath, commitRange string, patterns []string, verb
 	return findings, nil
 }
 
+// compileSecretPatterns compiles detection patterns, skipping invalid ones
+func compileSecretPatterns(patterns []string, verbose bool) []*regexp.Regexp {
+	var regexes []*regexp.Regexp
+	for _, pattern := range patterns {
+		if regex, err := regexp.Compile(pattern); err == nil {
+			regexes = append(regexes, regex)
+		} else if verbose {
+			fmt.Printf("Warning: Invalid regex pattern: %s\n", pattern)
+		}
+	}
+	return regexes
+}
+
 func scanFileInCommit(repoPath, commit, filename string, regexes []*regexp.Regexp, verbose bool) []SecretFinding {
 	var findings []SecretFinding
 
-	// Get file content at specific commit
-	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commit, filename))
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	content, err := fileAtCommit(repoPath, commit, filename)
 	if err != nil {
 		return findings // File might be deleted or binary
 	}
 
-	content := string(output)
 	lines := strings.Split(content, "\n")
 
 	// Scan each line with each regex
@@ -392,6 +403,17 @@ func scanFileInCommit(repoPath, commit, filename string, regexes []*regexp.Regex
 	return findings
 }
 
+// fileAtCommit returns the content of a file as of a commit
+func fileAtCommit(repoPath, commit, filename string) (string, error) {
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commit, filename))
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 func isGitRepository(path string) bool {
 	gitDir := filepath.Join(path, ".git")
 	if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/guard"
+	"github.com/systmms/dsops/internal/incident"
+)
+
+func NewGuardPurgeCommand(cfg *config.Config) *cobra.Command {
+	var (
+		path        string
+		scan        bool
+		patterns    []string
+		secretsFile string
+		incidentID  string
+		apply       bool
+		yes         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Rewrite git history to remove leaked secrets",
+		Long: `Rewrite git history so leaked strings are replaced with ***REMOVED***.
+
+The strings to remove come from one or more sources:
+  --scan            Findings from scanning all history, as in 'dsops guard repo --all'
+  --incident ID     Findings in the commits and files recorded on a leak incident
+  --secrets-file F  Literal strings, one per line ('-' reads stdin)
+
+Only the secret value of a finding is removed, so 'API_KEY=abc...' becomes
+'API_KEY=***REMOVED***'. PEM private keys are removed from their BEGIN line
+to their END line. A --pattern can name the value with a (?P<secret>...)
+group; without one the whole match is removed.
+
+By default purge is a dry run that reports the affected commits and refs.
+With --apply, every local branch and tag is rewritten through
+git fast-export and git fast-import. The original refs are kept under
+refs/dsops/backup/ so the rewrite can be undone. Nothing is pushed and no
+network access is needed.
+
+Examples:
+  dsops guard purge --scan                          # Preview
+  dsops guard purge --scan --apply                  # Rewrite history
+  dsops guard purge --incident INC-20250118-12345 --apply
+  dsops guard purge --secrets-file leaked.txt --apply --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGuardPurge(cfg, path, scan, patterns, secretsFile, incidentID, apply, yes)
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "path", ".", "Repository path")
+	cmd.Flags().BoolVar(&scan, "scan", false, "Purge findings from scanning all history")
+	cmd.Flags().StringArrayVar(&patterns, "pattern", nil, "Additional regex patterns for --scan and --incident; a (?P<secret>...) group selects the value to remove")
+	cmd.Flags().StringVar(&secretsFile, "secrets-file", "", "File of literal strings to purge, one per line ('-' for stdin)")
+	cmd.Flags().StringVar(&incidentID, "incident", "", "Purge findings from a leak incident's commits and files")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Rewrite history (default is a dry run)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runGuardPurge(cfg *config.Config, repoPath string, scan bool, patterns []string, secretsFile, incidentID string, apply, yes bool) error {
+	if !isGitRepository(repoPath) {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Not a git repository: %s", repoPath),
+			Suggestion: "Navigate to a git repository or specify --path to one",
+		}
+	}
+
+	if !scan && secretsFile == "" && incidentID == "" {
+		return dserrors.UserError{
+			Message:    "Nothing to purge",
+			Suggestion: "Use --scan, --incident or --secrets-file to choose the strings to remove",
+		}
+	}
+
+	detection := append(getSecretPatterns(), patterns...)
+
+	var secrets []string
+
+	if secretsFile != "" {
+		fromFile, err := readPurgeSecrets(secretsFile)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, fromFile...)
+	}
+
+	var (
+		manager *incident.Manager
+		report  *incident.Report
+	)
+	if incidentID != "" {
+		manager = incident.NewManager(".")
+		var err error
+		report, err = manager.LoadReport(incidentID)
+		if err != nil {
+			return fmt.Errorf("failed to load incident: %w", err)
+		}
+		if len(report.AffectedCommits) == 0 {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Incident %s has no affected commits", report.ID),
+				Suggestion: fmt.Sprintf("Add them with 'dsops leak update %s --commit <hash>' or use --scan", report.ID),
+			}
+		}
+		secrets = append(secrets, scanIncidentCommits(repoPath, report, detection)...)
+	}
+
+	if scan {
+		fmt.Println("🔍 Scanning all history for secrets...")
+		findings, err := scanCommitsForSecrets(repoPath, "--all", detection, false)
+		if err != nil {
+			return err
+		}
+		regexes := compileSecretPatterns(detection, false)
+		scanned := make(map[string]bool)
+		for _, finding := range findings {
+			key := finding.Commit + ":" + finding.File
+			if scanned[key] {
+				continue
+			}
+			scanned[key] = true
+			secrets = append(secrets, secretsInFile(repoPath, finding.Commit, finding.File, regexes)...)
+		}
+	}
+
+	secrets = uniqueStrings(secrets)
+	if len(secrets) == 0 {
+		fmt.Println("✅ No leaked strings found, nothing to purge")
+		return nil
+	}
+
+	ctx := context.Background()
+	preview, err := guard.Purge(ctx, guard.PurgeOptions{RepoPath: repoPath, Secrets: secrets, DryRun: true})
+	if err != nil {
+		return fmt.Errorf("failed to analyze history: %w", err)
+	}
+
+	// Never print the leaked strings themselves
+	fmt.Printf("\nLeaked strings: %d\n", len(secrets))
+	if len(preview.AffectedCommits) == 0 {
+		fmt.Println("✅ None of the strings appear in local branches or tags")
+		return nil
+	}
+
+	fmt.Printf("File versions to rewrite: %d\n", preview.BlobsRewritten)
+	fmt.Printf("Messages to rewrite: %d\n", preview.MessagesRewritten)
+	fmt.Printf("\nAffected commits (%d):\n", len(preview.AffectedCommits))
+	for _, commit := range preview.AffectedCommits {
+		fmt.Printf("  • %s\n", shortCommit(commit))
+	}
+	fmt.Printf("\nAffected refs (%d):\n", len(preview.AffectedRefs))
+	for _, ref := range preview.AffectedRefs {
+		fmt.Printf("  • %s\n", ref)
+	}
+
+	if !apply {
+		fmt.Println("\nDry run: history was not changed. Re-run with --apply to rewrite it.")
+		return nil
+	}
+
+	if !yes {
+		if cfg.NonInteractive {
+			return dserrors.UserError{
+				Message:    "Rewriting history requires confirmation",
+				Suggestion: "Re-run with --yes to rewrite without prompting",
+			}
+		}
+
+		fmt.Printf("\nRewrite every local branch and tag? [y/N]: ")
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Purge cancelled")
+			return nil
+		}
+	}
+
+	result, err := guard.Purge(ctx, guard.PurgeOptions{RepoPath: repoPath, Secrets: secrets})
+	if err != nil {
+		return dserrors.CommandError{
+			Command:    "git fast-import",
+			Message:    err.Error(),
+			Suggestion: "Original refs are kept under " + guard.BackupRefPrefix + " if the rewrite started",
+		}
+	}
+
+	fmt.Printf("\n✅ Rewrote %d commit(s) across %d ref(s)\n", len(result.AffectedCommits), len(result.AffectedRefs))
+	fmt.Printf("   Original refs saved under %s\n", result.BackupRef)
+
+	if report != nil {
+		action := fmt.Sprintf("Purged %d leaked string(s) from git history (backup: %s)", len(secrets), result.BackupRef)
+		if err := manager.AddAction(report, action); err != nil {
+			cfg.Logger.Warn("Failed to record purge on incident %s: %v", report.ID, err)
+		}
+	}
+
+	fmt.Println("\nNext steps:")
+	fmt.Println("  • Review the rewritten history: git log -p")
+	fmt.Println("  • Force-push branches and tags: git push --force --all && git push --force --tags")
+	fmt.Println("  • Ask collaborators to re-clone; old clones and forks still contain the secrets")
+	fmt.Printf("  • When satisfied, delete the backup: git for-each-ref --format='delete %%(refname)' %s | git update-ref --stdin\n", result.BackupRef)
+	fmt.Println("  • Then drop the old objects: git reflog expire --expire=now --all && git gc --prune=now")
+	fmt.Println("  • Rotate the leaked credentials; rewriting history does not revoke them")
+
+	return nil
+}
+
+// readPurgeSecrets reads literal strings, one per line, skipping blank lines
+func readPurgeSecrets(path string) ([]string, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Cannot read secrets file: %s", path),
+				Suggestion: "Check the path passed to --secrets-file",
+				Err:        err,
+			}
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	var secrets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			secrets = append(secrets, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// scanIncidentCommits scans the files of an incident's affected commits.
+// The incident's affected files are used when recorded, otherwise every
+// file changed by the commit is scanned.
+func scanIncidentCommits(repoPath string, report *incident.Report, patterns []string) []string {
+	regexes := compileSecretPatterns(patterns, false)
+
+	var secrets []string
+	for _, commit := range report.AffectedCommits {
+		files := report.AffectedFiles
+		if len(files) == 0 {
+			cmd := exec.Command("git", "show", "--name-only", "--format=", commit)
+			cmd.Dir = repoPath
+			output, err := cmd.Output()
+			if err != nil {
+				continue
+			}
+			files = strings.Fields(string(output))
+		}
+
+		for _, file := range files {
+			secrets = append(secrets, secretsInFile(repoPath, commit, file, regexes)...)
+		}
+	}
+
+	return secrets
+}
+
+// privateKeyBlock matches a PEM private key from its BEGIN line to its END
+// line, or to the end of the file when the key was committed truncated
+var privateKeyBlock = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----(?:.*?-----END [A-Z ]*PRIVATE KEY-----|.*)`)
+
+// secretsInFile returns the strings to purge from a file as of a commit
+func secretsInFile(repoPath, commit, filename string, regexes []*regexp.Regexp) []string {
+	content, err := fileAtCommit(repoPath, commit, filename)
+	if err != nil {
+		return nil
+	}
+	return extractPurgeSecrets(content, regexes)
+}
+
+// extractPurgeSecrets returns the secret values in content. PEM private keys
+// are returned as whole blocks. For other matches the "secret" group is
+// returned when the pattern has one, and the whole match otherwise.
+func extractPurgeSecrets(content string, regexes []*regexp.Regexp) []string {
+	secrets := privateKeyBlock.FindAllString(content, -1)
+	content = privateKeyBlock.ReplaceAllString(content, "")
+
+	for _, line := range strings.Split(content, "\n") {
+		for _, regex := range regexes {
+			group := regex.SubexpIndex("secret")
+			for _, match := range regex.FindAllStringSubmatch(line, -1) {
+				if group < 0 {
+					secrets = append(secrets, match[0])
+				} else if match[group] != "" {
+					secrets = append(secrets, match[group])
+				}
+			}
+		}
+	}
+
+	return secrets
+}
+
+// uniqueStrings returns the non-empty strings in sorted order without duplicates
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// shortCommit abbreviates a commit ID for display
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -212,7 +212,7 @@ if ! dsops guard repo --path "$temp_dir" --exit-code 2>/dev/null; then
     echo "   1. Remove secrets from staged files"
     echo "   2. Use dsops to manage secrets properly"
     echo "   3. Add secret files to .gitignore"
-    echo "   4. Consider using git filter-branch if secrets are in history"
+    echo "   4. Run dsops guard purge if secrets are in history"
     echo ""
     echo "🔧 To bypass this check (NOT RECOMMENDED):"
     echo "   git commit --no-verify -m \"your message\""
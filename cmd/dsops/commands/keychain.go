@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// NewKeychainCommand creates the keychain command
+func NewKeychainCommand(cfg *config.Config) *cobra.Command {
+	var store string
+
+	cmd := &cobra.Command{
+		Use:   "keychain",
+		Short: "Manage entries in the keychain store",
+		Long: `Add, remove and list entries in a keychain store.
+
+Entries are addressed as service/account, the same form used in
+'from: { store: "store://keychain/service/account" }'.
+
+--store selects a keychain store from dsops.yaml. Without it the only
+keychain store in dsops.yaml is used, or a default store when there is none.
+The store's backend setting decides where entries live:
+  system  macOS Keychain or the Linux Secret Service
+  file    an age-encrypted file, for SSH sessions and CI runners
+  auto    the system keychain when usable without a GUI, otherwise the file
+
+Available Commands:
+  set     Store an entry
+  rm      Remove an entry
+  ls      List entries (file backend only)`,
+	}
+
+	cmd.PersistentFlags().StringVar(&store, "store", "", "Keychain store from dsops.yaml (default: the only keychain store)")
+
+	cmd.AddCommand(
+		newKeychainSetCommand(cfg, &store),
+		newKeychainRmCommand(cfg, &store),
+		newKeychainLsCommand(cfg, &store),
+	)
+
+	return cmd
+}
+
+func newKeychainSetCommand(cfg *config.Config, store *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <service/account>",
+		Short: "Store an entry in the keychain",
+		Long: `Store an entry in the keychain, replacing any existing value.
+
+The value is read from stdin. When stdin is a terminal you are prompted
+twice and the input is not echoed. A single trailing newline is removed
+from piped input.
+
+Examples:
+  dsops keychain set myapp/database-password
+  gh auth token | dsops keychain set github.com/token
+  DSOPS_KEYCHAIN_PASSPHRASE=... dsops keychain set --store ci-keys deploy/api-key < key.txt`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kc, name, err := openKeychainStore(cfg, *store)
+			if err != nil {
+				return err
+			}
+
+			value, err := readSecretValue(os.Stdin, isTerminal(os.Stdin))
+			if err != nil {
+				return err
+			}
+
+			ref := provider.Reference{Provider: name, Key: args[0]}
+			if _, err := kc.PutSecret(context.Background(), ref, value, provider.WriteOptions{}); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "✅ Stored %s in %s (%s keychain)\n", args[0], name, kc.Backend())
+			return nil
+		},
+	}
+}
+
+func newKeychainRmCommand(cfg *config.Config, store *string) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:     "rm <service/account>",
+		Aliases: []string{"delete"},
+		Short:   "Remove an entry from the keychain",
+		Long: `Remove an entry from the keychain. You are asked to confirm unless --yes
+is given.
+
+Examples:
+  dsops keychain rm myapp/old-token
+  dsops keychain rm --store ci-keys deploy/api-key --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kc, name, err := openKeychainStore(cfg, *store)
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				confirmed, err := confirmWrite(cfg, fmt.Sprintf("Remove %s from %s?", args[0], name), isTerminal(os.Stdin))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(os.Stderr, "Remove cancelled")
+					return nil
+				}
+			}
+
+			ref := provider.Reference{Provider: name, Key: args[0]}
+			if err := kc.DeleteSecret(context.Background(), ref, provider.DeleteOptions{}); err != nil {
+				if provider.IsNotFound(err) {
+					return dserrors.UserError{
+						Message:    fmt.Sprintf("Entry '%s' not found in keychain store '%s'", args[0], name),
+						Suggestion: "Run 'dsops keychain ls' to see the stored entries",
+						Err:        err,
+					}
+				}
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "✅ Removed %s from %s\n", args[0], name)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func newKeychainLsCommand(cfg *config.Config, store *string) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "ls [prefix]",
+		Short: "List keychain entries",
+		Long: `List the entries in the keychain without their values.
+
+Only the encrypted file backend can be listed; the macOS Keychain and the
+Secret Service do not enumerate their items to dsops.
+
+Examples:
+  dsops keychain ls
+  dsops keychain ls myapp/
+  dsops keychain ls --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix := ""
+			if len(args) == 1 {
+				prefix = args[0]
+			}
+			return runKeychainLs(cfg, *store, prefix, format, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+
+	return cmd
+}
+
+func runKeychainLs(cfg *config.Config, store, prefix, format string, out io.Writer) error {
+	if format != "table" && format != "json" {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported format: %s", format),
+			Suggestion: "Use --format table or --format json",
+		}
+	}
+
+	kc, name, err := openKeychainStore(cfg, store)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := listAllSecrets(context.Background(), kc, prefix, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return outputLsJSON(out, secrets)
+	}
+	target := name
+	if prefix != "" {
+		target += "/" + prefix
+	}
+	return outputLsTable(out, target, secrets)
+}
+
+// openKeychainStore creates the keychain store named by --store, the only
+// keychain store in dsops.yaml, or a default keychain store when there is
+// no dsops.yaml or it defines none
+func openKeychainStore(cfg *config.Config, store string) (*providers.KeychainProvider, string, error) {
+	// The keychain commands work without a dsops.yaml unless --store is given
+	if err := cfg.Load(); err != nil && store != "" {
+		return nil, "", err
+	}
+
+	if store == "" {
+		var candidates []string
+		if cfg.Definition != nil {
+			for name, sc := range cfg.Definition.SecretStores {
+				if sc.Type == "keychain" {
+					candidates = append(candidates, name)
+				}
+			}
+		}
+		sort.Strings(candidates)
+
+		switch len(candidates) {
+		case 0:
+			return providers.NewKeychainProvider("keychain", nil), "keychain", nil
+		case 1:
+			store = candidates[0]
+		default:
+			return nil, "", dserrors.UserError{
+				Message:    "dsops.yaml defines several keychain stores",
+				Details:    fmt.Sprintf("Keychain stores: %v", candidates),
+				Suggestion: "Choose one with --store",
+			}
+		}
+	}
+
+	providerConfig, err := cfg.GetProvider(store)
+	if err != nil {
+		return nil, "", err
+	}
+	if providerConfig.Type != "keychain" {
+		return nil, "", dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' is a %s store, not a keychain store", store, providerConfig.Type),
+			Suggestion: "Use 'dsops set', 'dsops delete' and 'dsops ls' for other store types",
+		}
+	}
+
+	p, err := providers.NewRegistry().CreateProvider(store, providerConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create store '%s': %w", store, err)
+	}
+	kc, ok := p.(*providers.KeychainProvider)
+	if !ok {
+		return nil, "", fmt.Errorf("store '%s' is not a keychain provider", store)
+	}
+
+	return kc, store, nil
+}
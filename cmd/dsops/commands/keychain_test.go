@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+)
+
+func TestNewKeychainCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewKeychainCommand(&config.Config{Logger: logging.New(false, true)})
+	assert.Equal(t, "keychain", cmd.Use)
+	assert.NotNil(t, cmd.PersistentFlags().Lookup("store"))
+
+	for _, name := range []string{"set", "rm", "ls"} {
+		sub, _, err := cmd.Find([]string{name})
+		require.NoError(t, err)
+		assert.Contains(t, sub.Use, name)
+	}
+
+	rm, _, err := cmd.Find([]string{"rm"})
+	require.NoError(t, err)
+	assert.NotNil(t, rm.Flags().Lookup("yes"))
+}
+
+func TestOpenKeychainStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "dsops.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 0
+secretStores:
+  dev-keys:
+    type: keychain
+    backend: file
+    file: `+filepath.Join(dir, "keychain.age")+`
+  other-keys:
+    type: keychain
+    backend: file
+  vault:
+    type: literal
+envs: {}
+`), 0600))
+
+	newCfg := func() *config.Config {
+		return &config.Config{Path: configPath, Logger: logging.New(false, true)}
+	}
+
+	kc, name, err := openKeychainStore(newCfg(), "dev-keys")
+	require.NoError(t, err)
+	assert.Equal(t, "dev-keys", name)
+	assert.Equal(t, "file", kc.Backend())
+
+	_, _, err = openKeychainStore(newCfg(), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "several keychain stores")
+
+	_, _, err = openKeychainStore(newCfg(), "vault")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a keychain store")
+
+	var out bytes.Buffer
+	err = runKeychainLs(newCfg(), "dev-keys", "", "xml", &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported format")
+
+	// A missing keychain file lists as empty
+	require.NoError(t, runKeychainLs(newCfg(), "dev-keys", "", "json", &out))
+	assert.Contains(t, out.String(), "[]")
+}
@@ -2,15 +2,19 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/duration"
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/incident"
+	"github.com/systmms/dsops/internal/rotation/notifications"
 )
 
 func NewLeakCommand(cfg *config.Config) *cobra.Command {
@@ -20,17 +24,22 @@ func NewLeakCommand(cfg *config.Config) *cobra.Command {
 		Long: `Manage security incidents including secret leaks, compromised credentials, and policy violations.
 
 Subcommands:
-  report  Create a new incident report
-  list    List all incidents
-  show    Show details of a specific incident
-  update  Update an existing incident
-  resolve Mark an incident as resolved
+  report    Create a new incident report
+  list      List all incidents
+  show      Show details of a specific incident
+  update    Update an existing incident
+  resolve   Mark an incident as resolved
+  remediate Rotate the secrets affected by an incident
+  export    Export an incident for a postmortem
 
 Examples:
   dsops leak report                    # Interactive incident reporting
   dsops leak list                      # Show all incidents
+  dsops leak list --severity critical,high --since 7d
   dsops leak show INC-20250118-12345   # Show specific incident
-  dsops leak resolve INC-20250118-12345`,
+  dsops leak resolve INC-20250118-12345
+  dsops leak remediate INC-20250118-12345
+  dsops leak export INC-20250118-12345 --format md`,
 	}
 
 	cmd.AddCommand(
@@ -39,6 +48,8 @@ Examples:
 		NewLeakShowCommand(cfg),
 		NewLeakUpdateCommand(cfg),
 		NewLeakResolveCommand(cfg),
+		NewLeakRemediateCommand(cfg),
+		NewLeakExportCommand(cfg),
 	)
 
 	return cmd
@@ -53,6 +64,7 @@ func NewLeakReportCommand(cfg *config.Config) *cobra.Command {
 		files        []string
 		secrets      []string
 		commits      []string
+		assignees    []string
 		notify       bool
 	)
 
@@ -128,6 +140,7 @@ Incident types:
 
 			// Add standard actions based on type
 			report.ActionsRequired = getStandardActions(incidentType, severity)
+			report.Assign(manager.Actor(), assignees...)
 
 			// Save updated report
 			if err := manager.SaveReport(report); err != nil {
@@ -141,7 +154,7 @@ Incident types:
 
 			// Send notifications if requested
 			if notify {
-				if err := sendIncidentNotifications(cfg, manager, report); err != nil {
+				if err := sendIncidentNotifications(cfg, manager, report, notifications.EventTypeIncidentOpened); err != nil {
 					fmt.Printf("\n⚠️  Failed to send notifications: %v\n", err)
 				}
 			}
@@ -149,6 +162,9 @@ Incident types:
 			fmt.Println("\nNext steps:")
 			fmt.Printf("  • View details: dsops leak show %s\n", report.ID)
 			fmt.Printf("  • Update status: dsops leak update %s\n", report.ID)
+			if len(report.AffectedSecrets) > 0 {
+				fmt.Printf("  • Rotate affected secrets: dsops leak remediate %s\n", report.ID)
+			}
 			fmt.Printf("  • Mark resolved: dsops leak resolve %s\n", report.ID)
 
 			return nil
@@ -162,6 +178,7 @@ Incident types:
 	cmd.Flags().StringArrayVar(&files, "file", nil, "Affected file paths")
 	cmd.Flags().StringArrayVar(&secrets, "secret", nil, "Affected secret names")
 	cmd.Flags().StringArrayVar(&commits, "commit", nil, "Affected commit hashes")
+	cmd.Flags().StringSliceVar(&assignees, "assign", nil, "People responsible for the incident")
 	cmd.Flags().BoolVarP(&notify, "notify", "n", false, "Send notifications (Slack, GitHub)")
 
 	return cmd
@@ -169,9 +186,12 @@ Incident types:
 
 func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 	var (
-		showAll      bool
-		filterType   string
-		filterStatus string
+		showAll        bool
+		filterType     string
+		filterStatus   string
+		filterSeverity []string
+		since          string
+		olderThan      string
 	)
 
 	cmd := &cobra.Command{
@@ -181,6 +201,31 @@ func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manager := incident.NewManager(".")
 
+			for _, sev := range filterSeverity {
+				if !isValidSeverity(sev) {
+					return dserrors.UserError{
+						Message:    fmt.Sprintf("Invalid severity: %s", sev),
+						Suggestion: "Use one or more of: critical, high, medium, low",
+					}
+				}
+			}
+
+			var maxAge, minAge time.Duration
+			if since != "" {
+				d, err := duration.Parse(since)
+				if err != nil {
+					return dserrors.UserError{Message: err.Error(), Suggestion: "Use --since 24h, 7d or 2w"}
+				}
+				maxAge = d
+			}
+			if olderThan != "" {
+				d, err := duration.Parse(olderThan)
+				if err != nil {
+					return dserrors.UserError{Message: err.Error(), Suggestion: "Use --older-than 24h, 7d or 2w"}
+				}
+				minAge = d
+			}
+
 			// Get reports
 			reports, err := manager.ListReports()
 			if err != nil {
@@ -193,6 +238,7 @@ func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 			}
 
 			// Filter reports
+			now := time.Now()
 			var filtered []*incident.Report
 			for _, report := range reports {
 				// Filter by status
@@ -208,6 +254,20 @@ func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 					continue
 				}
 
+				// Filter by severity
+				if len(filterSeverity) > 0 && !containsString(filterSeverity, report.Severity) {
+					continue
+				}
+
+				// Filter by age
+				age := report.Age(now)
+				if maxAge > 0 && age > maxAge {
+					continue
+				}
+				if minAge > 0 && age < minAge {
+					continue
+				}
+
 				filtered = append(filtered, report)
 			}
 
@@ -217,16 +277,21 @@ func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 			}
 
 			// Display reports
-			fmt.Printf("%-20s %-15s %-10s %-10s %s\n", "ID", "TYPE", "SEVERITY", "STATUS", "TITLE")
-			fmt.Println(strings.Repeat("-", 80))
+			fmt.Printf("%-20s %-15s %-10s %-14s %-6s %s\n", "ID", "TYPE", "SEVERITY", "STATUS", "AGE", "TITLE")
+			fmt.Println(strings.Repeat("-", 90))
 
 			for _, report := range filtered {
-				fmt.Printf("%-20s %-15s %-10s %-10s %s\n",
+				title := report.Title
+				if overdue := len(report.OverdueActions(now)); overdue > 0 {
+					title = fmt.Sprintf("%s (%d overdue)", title, overdue)
+				}
+				fmt.Printf("%-20s %-15s %-10s %-14s %-6s %s\n",
 					report.ID,
 					report.Type,
 					report.Severity,
 					report.Status,
-					report.Title,
+					formatIncidentAge(report.Age(now)),
+					title,
 				)
 			}
 
@@ -239,6 +304,9 @@ func NewLeakListCommand(cfg *config.Config) *cobra.Command {
 	cmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all incidents including resolved")
 	cmd.Flags().StringVar(&filterType, "type", "", "Filter by incident type")
 	cmd.Flags().StringVar(&filterStatus, "status", "", "Filter by status (open|investigating|resolved)")
+	cmd.Flags().StringSliceVar(&filterSeverity, "severity", nil, "Filter by severity (critical,high,medium,low)")
+	cmd.Flags().StringVar(&since, "since", "", "Only incidents created within this age (e.g. 24h, 7d)")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Only incidents older than this age (e.g. 30d)")
 
 	return cmd
 }
@@ -270,6 +338,10 @@ func NewLeakShowCommand(cfg *config.Config) *cobra.Command {
 				fmt.Printf("Resolved:    %s\n", report.ResolvedAt.Format(time.RFC3339))
 			}
 
+			if len(report.Assignees) > 0 {
+				fmt.Printf("Assignees:   %s\n", strings.Join(report.Assignees, ", "))
+			}
+
 			fmt.Printf("\nDescription:\n%s\n", report.Description)
 
 			// Show affected resources
@@ -296,9 +368,22 @@ func NewLeakShowCommand(cfg *config.Config) *cobra.Command {
 
 			// Show actions
 			if len(report.ActionsRequired) > 0 {
+				overdue := make(map[string]bool)
+				for _, action := range report.OverdueActions(time.Now()) {
+					overdue[action] = true
+				}
+
 				fmt.Printf("\nActions Required (%d):\n", len(report.ActionsRequired))
-				for _, action := range report.ActionsRequired {
-					fmt.Printf("  □ %s\n", action)
+				for i, action := range report.ActionsRequired {
+					fmt.Printf("  %d. □ %s", i+1, action)
+					if due, ok := report.DueDates[action]; ok {
+						fmt.Printf(" (due %s", due.Format("2006-01-02 15:04"))
+						if overdue[action] {
+							fmt.Print(", OVERDUE")
+						}
+						fmt.Print(")")
+					}
+					fmt.Println()
 				}
 			}
 
@@ -325,6 +410,14 @@ func NewLeakShowCommand(cfg *config.Config) *cobra.Command {
 				}
 			}
 
+			// Show timeline
+			if len(report.Timeline) > 0 {
+				fmt.Printf("\nTimeline:\n")
+				for _, entry := range report.Timeline {
+					fmt.Printf("  %s  %-10s %s\n", entry.Timestamp.Format("2006-01-02 15:04"), entry.Actor, entry.Message)
+				}
+			}
+
 			// Show resolution
 			if report.Status == "resolved" && report.ResolutionNotes != "" {
 				fmt.Printf("\nResolution Notes:\n%s\n", report.ResolutionNotes)
@@ -344,14 +437,24 @@ func NewLeakUpdateCommand(cfg *config.Config) *cobra.Command {
 		addFile   []string
 		addSecret []string
 		addCommit []string
+		assign    []string
+		unassign  []string
+		due       []string
 		notify    bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "update [incident-id]",
 		Short: "Update an incident",
-		Long:  `Update an existing incident with new information or status changes.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Update an existing incident with new information or status changes.
+
+Every change is recorded on the incident timeline with who made it and when.
+Due dates refer to required actions by the number shown in 'dsops leak show'.
+
+Examples:
+  dsops leak update INC-20250118-12345 --status investigating --assign alice
+  dsops leak update INC-20250118-12345 --due 2=2025-01-20 --due 3=48h`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			incidentID := args[0]
 			manager := incident.NewManager(".")
@@ -360,6 +463,7 @@ func NewLeakUpdateCommand(cfg *config.Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to load incident: %w", err)
 			}
+			actor := manager.Actor()
 
 			// Update status
 			if status != "" {
@@ -369,16 +473,27 @@ func NewLeakUpdateCommand(cfg *config.Config) *cobra.Command {
 						Suggestion: "Use one of: open, investigating, resolved",
 					}
 				}
-				report.Status = status
+				report.SetStatus(actor, status)
 			}
 
 			// Add new resources
-			report.AffectedFiles = append(report.AffectedFiles, addFile...)
-			report.AffectedSecrets = append(report.AffectedSecrets, addSecret...)
-			report.AffectedCommits = append(report.AffectedCommits, addCommit...)
+			report.AddAffected(actor, "files", addFile)
+			report.AddAffected(actor, "secrets", addSecret)
+			report.AddAffected(actor, "commits", addCommit)
 
 			// Add actions taken
-			report.ActionsTaken = append(report.ActionsTaken, addAction...)
+			for _, action := range addAction {
+				report.AddActionTaken(actor, action)
+			}
+
+			// Ownership and due dates
+			report.Assign(actor, assign...)
+			report.Unassign(actor, unassign...)
+			for _, spec := range due {
+				if err := setIncidentDueDate(report, actor, spec); err != nil {
+					return err
+				}
+			}
 
 			// Save updated report
 			if err := manager.UpdateReport(report); err != nil {
@@ -389,7 +504,7 @@ func NewLeakUpdateCommand(cfg *config.Config) *cobra.Command {
 
 			// Send notifications if requested
 			if notify {
-				if err := sendIncidentNotifications(cfg, manager, report); err != nil {
+				if err := sendIncidentNotifications(cfg, manager, report, incidentUpdateEvent(report)); err != nil {
 					fmt.Printf("\n⚠️  Failed to send notifications: %v\n", err)
 				}
 			}
@@ -403,6 +518,9 @@ func NewLeakUpdateCommand(cfg *config.Config) *cobra.Command {
 	cmd.Flags().StringArrayVar(&addFile, "file", nil, "Add affected file")
 	cmd.Flags().StringArrayVar(&addSecret, "secret", nil, "Add affected secret")
 	cmd.Flags().StringArrayVar(&addCommit, "commit", nil, "Add affected commit")
+	cmd.Flags().StringSliceVar(&assign, "assign", nil, "Assign people to the incident")
+	cmd.Flags().StringSliceVar(&unassign, "unassign", nil, "Remove people from the incident")
+	cmd.Flags().StringArrayVar(&due, "due", nil, "Set a due date for a required action (N=YYYY-MM-DD or N=48h)")
 	cmd.Flags().BoolVarP(&notify, "notify", "n", false, "Send update notifications")
 
 	return cmd
@@ -450,7 +568,7 @@ func NewLeakResolveCommand(cfg *config.Config) *cobra.Command {
 
 			// Send notifications if requested
 			if notify {
-				if err := sendIncidentNotifications(cfg, manager, report); err != nil {
+				if err := sendIncidentNotifications(cfg, manager, report, notifications.EventTypeIncidentResolved); err != nil {
 					fmt.Printf("\n⚠️  Failed to send notifications: %v\n", err)
 				}
 			}
@@ -491,6 +609,57 @@ func isValidStatus(status string) bool {
 	return false
 }
 
+// setIncidentDueDate applies a --due value of the form N=DATE, where N is the
+// 1-based number of a required action
+func setIncidentDueDate(report *incident.Report, actor, spec string) error {
+	index, value, ok := strings.Cut(spec, "=")
+	if !ok {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid --due value: %s", spec),
+			Suggestion: "Use N=DATE, e.g. --due 2=2025-01-20 or --due 2=48h",
+		}
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(index))
+	if err != nil || n < 1 || n > len(report.ActionsRequired) {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid required action number: %s", index),
+			Suggestion: fmt.Sprintf("Run 'dsops leak show %s' to see the numbered required actions", report.ID),
+		}
+	}
+
+	dueDate, err := incident.ParseDueDate(strings.TrimSpace(value), time.Now())
+	if err != nil {
+		return dserrors.UserError{
+			Message:    err.Error(),
+			Suggestion: "Use YYYY-MM-DD, RFC3339, or a duration like 48h or 3d",
+		}
+	}
+
+	return report.SetDueDate(actor, report.ActionsRequired[n-1], dueDate)
+}
+
+// formatIncidentAge formats an incident age compactly for list output
+func formatIncidentAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func getStandardActions(incidentType, severity string) []string {
 	actions := []string{}
 
@@ -614,7 +783,7 @@ func interactiveIncidentReport(cfg *config.Config, notify bool) error {
 
 	// Send notifications if requested
 	if notify {
-		if err := sendIncidentNotifications(cfg, manager, report); err != nil {
+		if err := sendIncidentNotifications(cfg, manager, report, notifications.EventTypeIncidentOpened); err != nil {
 			fmt.Printf("\n⚠️  Failed to send notifications: %v\n", err)
 		}
 	}
@@ -622,24 +791,60 @@ func interactiveIncidentReport(cfg *config.Config, notify bool) error {
 	return nil
 }
 
-func sendIncidentNotifications(cfg *config.Config, manager *incident.Manager, report *incident.Report) error {
-	// Load notification config from dsops.yaml
-	if cfg.Definition == nil || cfg.Definition.Policies == nil {
-		return fmt.Errorf("no notification configuration found")
+// incidentUpdateEvent returns the notification event for an updated report
+func incidentUpdateEvent(report *incident.Report) notifications.EventType {
+	if report.Status == "resolved" {
+		return notifications.EventTypeIncidentResolved
 	}
+	return notifications.EventTypeIncidentUpdated
+}
 
-	// TODO: Add notification config to policies section
-	// For now, we'll use environment variables
+// incidentNotificationConfig returns the notifications section of dsops.yaml.
+// DSOPS_SLACK_WEBHOOK stands in for its Slack provider when none is
+// configured, so incidents reach Slack once through the same provider.
+func incidentNotificationConfig(definition *config.Definition) *config.NotificationConfig {
+	var notificationConfig config.NotificationConfig
+	if definition != nil && definition.Notifications != nil {
+		notificationConfig = *definition.Notifications
+	}
 
-	notifConfig := incident.NotificationConfig{}
+	if webhook := os.Getenv("DSOPS_SLACK_WEBHOOK"); webhook != "" && notificationConfig.Slack == nil {
+		notificationConfig.Slack = &config.SlackNotificationConfig{WebhookURL: webhook}
+	}
+	return &notificationConfig
+}
+
+func sendIncidentNotifications(cfg *config.Config, manager *incident.Manager, report *incident.Report, eventType notifications.EventType) error {
+	// Leak commands do not load dsops.yaml up front; a missing config only
+	// means the environment-based channels below are used
+	if cfg.Definition == nil {
+		_ = cfg.Load()
+	}
+
+	var records []incident.NotificationRecord
 
-	// Check for Slack webhook
-	if webhook := os.Getenv("DSOPS_SLACK_WEBHOOK"); webhook != "" {
-		notifConfig.Slack = &incident.SlackConfig{
-			WebhookURL: webhook,
+	// Publish through the providers configured under notifications in dsops.yaml
+	notifier, err := newNotificationManager(incidentNotificationConfig(cfg.Definition))
+	if err != nil {
+		return dserrors.ConfigError{
+			Field:      "notifications",
+			Message:    err.Error(),
+			Suggestion: "Fix the notifications section in dsops.yaml or DSOPS_SLACK_WEBHOOK",
+		}
+	}
+	if notifier != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		published, err := manager.Publish(ctx, notifier, report, eventType)
+		cancel()
+		records = append(records, published...)
+		if err != nil {
+			return err
 		}
 	}
 
+	// GitHub issues can also be configured through the environment
+	notifConfig := incident.NotificationConfig{}
+
 	// Check for GitHub config
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
 		owner := os.Getenv("GITHUB_OWNER")
@@ -654,16 +859,25 @@ func sendIncidentNotifications(cfg *config.Config, manager *incident.Manager, re
 		}
 	}
 
-	// Send notifications
-	notifier := incident.NewNotifier(notifConfig)
-	records := notifier.SendNotifications(report)
+	// Open GitHub issues
+	issues := incident.NewNotifier(notifConfig)
+	issueRecords := issues.SendNotifications(report)
 
 	// Update report with notification records
-	for _, record := range records {
+	for _, record := range issueRecords {
 		if err := manager.AddNotification(report, record.Channel, record.Success, record.Details); err != nil {
 			return fmt.Errorf("failed to record notification: %w", err)
 		}
 	}
+	records = append(records, issueRecords...)
+
+	if len(records) == 0 {
+		return dserrors.UserError{
+			Message:    "No notification channels configured",
+			Suggestion: "Add a notifications section to dsops.yaml or set DSOPS_SLACK_WEBHOOK",
+			Details:    "Incidents are published to the Slack, email, PagerDuty and webhook providers under notifications",
+		}
+	}
 
 	// Report results
 	successCount := 0
@@ -676,7 +890,7 @@ func sendIncidentNotifications(cfg *config.Config, manager *incident.Manager, re
 		}
 	}
 
-	if successCount == 0 && len(records) > 0 {
+	if successCount == 0 {
 		return fmt.Errorf("all notifications failed")
 	}
 
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/incident"
+)
+
+func NewLeakExportCommand(cfg *config.Config) *cobra.Command {
+	var (
+		format string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [incident-id]",
+		Short: "Export an incident for a postmortem",
+		Long: `Export an incident report with its full timeline, assignees and action
+due dates.
+
+Formats:
+  md    Markdown postmortem document
+  json  Raw incident report
+
+Examples:
+  dsops leak export INC-20250118-12345 --format md > postmortem.md
+  dsops leak export INC-20250118-12345 --format json --output incident.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != incident.ExportMarkdown && format != incident.ExportJSON {
+				return dserrors.UserError{
+					Message:    fmt.Sprintf("Invalid export format: %s", format),
+					Suggestion: "Use --format md or --format json",
+				}
+			}
+
+			manager := incident.NewManager(".")
+			report, err := manager.LoadReport(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load incident: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer func() { _ = f.Close() }()
+				out = f
+			}
+
+			if err := incident.Export(out, report, format); err != nil {
+				return fmt.Errorf("failed to export incident: %w", err)
+			}
+
+			if output != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "✅ Exported incident %s to %s\n", report.ID, output)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", incident.ExportMarkdown, "Export format (md|json)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+
+	return cmd
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/incident"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/pkg/rotation"
+)
+
+func NewLeakRemediateCommand(cfg *config.Config) *cobra.Command {
+	var (
+		envName  string
+		strategy string
+		yes      bool
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "remediate [incident-id]",
+		Short: "Rotate the secrets affected by an incident",
+		Long: `Map the affected secrets of an incident to variables in dsops.yaml and
+rotate them through the rotation engine.
+
+Affected secrets are matched against variable names, provider keys and
+"<provider>/<key>" references. The remediation plan is shown first and
+rotations only run after confirmation (or --yes). Every rotation is recorded
+in the incident's actions taken, and the incident is resolved once all
+rotations complete and verify.
+
+Examples:
+  dsops leak remediate INC-20250118-12345 --dry-run
+  dsops leak remediate INC-20250118-12345 --env production
+  dsops leak remediate INC-20250118-12345 --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLeakRemediate(cfg, args[0], envName, strategy, yes, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&envName, "env", "", "Only remediate variables in this environment")
+	cmd.Flags().StringVar(&strategy, "strategy", string(rotation.StrategyEmergency), "Rotation strategy to use")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the remediation plan without rotating")
+
+	return cmd
+}
+
+func runLeakRemediate(cfg *config.Config, incidentID, envName, strategy string, yes, dryRun bool) error {
+	manager := incident.NewManager(".")
+
+	report, err := manager.LoadReport(incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to load incident: %w", err)
+	}
+
+	if report.Status == "resolved" {
+		fmt.Printf("Incident %s is already resolved\n", incidentID)
+		return nil
+	}
+
+	if len(report.AffectedSecrets) == 0 {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Incident %s has no affected secrets", incidentID),
+			Suggestion: fmt.Sprintf("Add them with: dsops leak update %s --secret <name>", incidentID),
+		}
+	}
+
+	if err := cfg.Load(); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	envs := cfg.Definition.Envs
+	if envName != "" {
+		env, err := cfg.GetEnvironment(envName)
+		if err != nil {
+			return err
+		}
+		envs = map[string]config.Environment{envName: env}
+	}
+
+	targets, unmatched := incident.MatchAffectedSecrets(report, envs)
+
+	// Display plan
+	fmt.Printf("=== Remediation Plan: %s ===\n\n", report.ID)
+	fmt.Printf("Strategy: %s\n\n", strategy)
+
+	configuredProviders := cfg.ListAllProviders()
+	var missingProviders []string
+	for _, target := range targets {
+		marker := "↻"
+		if _, ok := configuredProviders[target.Provider]; !ok {
+			marker = "✗"
+			missingProviders = append(missingProviders, target.Provider)
+		}
+		fmt.Printf("  %s %s  [affected: %s]\n", marker, target, target.Secret)
+	}
+
+	for _, secret := range unmatched {
+		fmt.Printf("  ? %s  (no matching variable in dsops.yaml)\n", secret)
+	}
+
+	if len(targets) == 0 {
+		return dserrors.UserError{
+			Message:    "No affected secrets match variables in dsops.yaml",
+			Suggestion: "Record affected secrets by variable name or provider key, or rotate them manually",
+		}
+	}
+
+	if len(missingProviders) > 0 {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Providers not configured: %s", strings.Join(missingProviders, ", ")),
+			Suggestion: "Add the providers to the 'secretStores:' section of your dsops.yaml",
+		}
+	}
+
+	logger := cfg.Logger
+
+	// Only create the providers the remediation touches
+	targetProviders := make(map[string]config.ProviderConfig)
+	for _, target := range targets {
+		targetProviders[target.Provider] = configuredProviders[target.Provider]
+	}
+	providerInstances, err := createSecretsProviderInstances(targetProviders, providers.NewRegistry())
+	if err != nil {
+		return fmt.Errorf("failed to create providers: %w", err)
+	}
+
+	engine := newBuiltinRotationEngine(cfg, providerInstances)
+
+	rotationStrategy, err := engine.GetStrategy(strategy)
+	if err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Rotation strategy '%s' is not available", strategy),
+			Suggestion: fmt.Sprintf("Available strategies: %s", strings.Join(engine.ListStrategies(), ", ")),
+			Err:        err,
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run: %d rotation(s) planned, nothing changed\n", len(targets))
+		return nil
+	}
+
+	if !yes {
+		if cfg.NonInteractive {
+			return dserrors.UserError{
+				Message:    "Remediation requires confirmation",
+				Suggestion: "Re-run with --yes to rotate without prompting",
+			}
+		}
+
+		fmt.Printf("\nRotate %d secret(s)? [y/N]: ", len(targets))
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Remediation cancelled")
+			return nil
+		}
+	}
+
+	if report.Status == "open" {
+		report.SetStatus(manager.Actor(), "investigating")
+	}
+
+	ctx := context.Background()
+	fmt.Println()
+
+	verified := 0
+	for _, target := range targets {
+		ok, action := remediateTarget(ctx, engine, rotationStrategy, report, target, strategy, logger)
+		if ok {
+			verified++
+			fmt.Printf("✅ %s\n", action)
+		} else {
+			fmt.Printf("❌ %s\n", action)
+		}
+
+		if err := manager.AddAction(report, action); err != nil {
+			return fmt.Errorf("failed to record action: %w", err)
+		}
+	}
+
+	fmt.Printf("\n%d of %d rotation(s) verified\n", verified, len(targets))
+
+	if verified < len(targets) || len(unmatched) > 0 {
+		if len(unmatched) > 0 {
+			fmt.Printf("⚠️  %d affected secret(s) must be rotated manually\n", len(unmatched))
+		}
+		fmt.Printf("Incident %s remains %s\n", report.ID, report.Status)
+		if verified < len(targets) {
+			return fmt.Errorf("%d rotation(s) failed", len(targets)-verified)
+		}
+		return nil
+	}
+
+	notes := fmt.Sprintf("All %d affected secret(s) rotated with the %s strategy by dsops leak remediate", len(targets), strategy)
+	if err := manager.ResolveReport(report, notes); err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	fmt.Printf("✅ Resolved incident: %s\n", report.ID)
+	return nil
+}
+
+// remediateTarget rotates and verifies a single target, returning whether it
+// succeeded and the action description to record on the incident
+func remediateTarget(ctx context.Context, engine rotation.RotationEngine, strategy rotation.SecretValueRotator, report *incident.Report, target incident.RemediationTarget, strategyName string, logger *logging.Logger) (bool, string) {
+	secretInfo := rotation.SecretInfo{
+		Key:      target.Variable,
+		Provider: target.Provider,
+		ProviderRef: provider.Reference{
+			Provider: target.Provider,
+			Key:      target.Key,
+			Version:  target.Version,
+		},
+		SecretType: inferSecretType(target.Variable, strategyName, config.Variable{}),
+		Metadata: map[string]string{
+			"compromised": "true",
+			"incident_id": report.ID,
+			"environment": target.Environment,
+		},
+	}
+
+	request := rotation.RotationRequest{
+		Secret:   secretInfo,
+		Strategy: strategyName,
+		Force:    true,
+		Config: map[string]interface{}{
+			"environment": target.Environment,
+			"incident_id": report.ID,
+		},
+	}
+
+	result, err := engine.Rotate(ctx, request)
+	if err != nil {
+		logger.Error("Failed to rotate %s: %v", logging.Secret(target.Variable), err)
+		return false, fmt.Sprintf("Rotation of %s failed: %v", target, err)
+	}
+
+	if result.Status != rotation.StatusCompleted || result.NewSecretRef == nil {
+		reason := result.Error
+		if reason == "" {
+			reason = fmt.Sprintf("status %s", result.Status)
+		}
+		return false, fmt.Sprintf("Rotation of %s failed: %s", target, reason)
+	}
+
+	verifyRequest := rotation.VerificationRequest{
+		Secret:       secretInfo,
+		NewSecretRef: *result.NewSecretRef,
+		Timeout:      30 * time.Second,
+	}
+	if err := strategy.Verify(ctx, verifyRequest); err != nil {
+		return false, fmt.Sprintf("Rotated %s to version %s but verification failed: %v", target, result.NewSecretRef.Version, err)
+	}
+
+	return true, fmt.Sprintf("Rotated %s with %s strategy (new version %s, verified)", target, strategyName, result.NewSecretRef.Version)
+}
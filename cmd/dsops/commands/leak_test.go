@@ -1,11 +1,18 @@
 package commands
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/incident"
 	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/rotation/notifications"
 )
 
 func TestNewLeakCommand(t *testing.T) {
@@ -80,6 +87,9 @@ func TestNewLeakListCommand(t *testing.T) {
 	assert.NotNil(t, flags.Lookup("all"))
 	assert.NotNil(t, flags.Lookup("type"))
 	assert.NotNil(t, flags.Lookup("status"))
+	assert.NotNil(t, flags.Lookup("severity"))
+	assert.NotNil(t, flags.Lookup("since"))
+	assert.NotNil(t, flags.Lookup("older-than"))
 }
 
 func TestNewLeakShowCommand(t *testing.T) {
@@ -114,6 +124,9 @@ func TestNewLeakUpdateCommand(t *testing.T) {
 	assert.NotNil(t, flags.Lookup("file"))
 	assert.NotNil(t, flags.Lookup("secret"))
 	assert.NotNil(t, flags.Lookup("commit"))
+	assert.NotNil(t, flags.Lookup("assign"))
+	assert.NotNil(t, flags.Lookup("unassign"))
+	assert.NotNil(t, flags.Lookup("due"))
 	assert.NotNil(t, flags.Lookup("notify"))
 }
 
@@ -247,3 +260,99 @@ func TestGetStandardActions_CriticalIncludesNotify(t *testing.T) {
 	actions = getStandardActions("secret-leak", "high")
 	assert.Contains(t, actions[0], "Notify security team")
 }
+
+func TestNewLeakRemediateCommand(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Logger: logging.New(false, true),
+	}
+
+	cmd := NewLeakRemediateCommand(cfg)
+
+	assert.Equal(t, "remediate [incident-id]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+
+	// Verify flags exist
+	flags := cmd.Flags()
+	assert.NotNil(t, flags.Lookup("env"))
+	assert.NotNil(t, flags.Lookup("yes"))
+	assert.NotNil(t, flags.Lookup("dry-run"))
+	strategy := flags.Lookup("strategy")
+	assert.NotNil(t, strategy)
+	assert.Equal(t, "emergency", strategy.DefValue)
+}
+
+func TestNewLeakExportCommand(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Logger: logging.New(false, true),
+	}
+
+	cmd := NewLeakExportCommand(cfg)
+
+	assert.Equal(t, "export [incident-id]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+
+	format := cmd.Flags().Lookup("format")
+	assert.NotNil(t, format)
+	assert.Equal(t, "md", format.DefValue)
+	assert.NotNil(t, cmd.Flags().Lookup("output"))
+}
+
+func TestSetIncidentDueDate(t *testing.T) {
+	t.Parallel()
+
+	report := &incident.Report{
+		ID:              "INC-1",
+		ActionsRequired: []string{"Rotate the credential", "Review access logs"},
+	}
+
+	require.NoError(t, setIncidentDueDate(report, "alice", "2=2025-01-20"))
+	due, ok := report.DueDates["Review access logs"]
+	require.True(t, ok)
+	assert.Equal(t, "2025-01-20", due.Format("2006-01-02"))
+
+	for _, spec := range []string{"2025-01-20", "0=48h", "3=48h", "x=48h", "1=tomorrow"} {
+		err := setIncidentDueDate(report, "alice", spec)
+		assert.Error(t, err, "spec %q should be rejected", spec)
+	}
+}
+
+func TestFormatIncidentAge(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "45m", formatIncidentAge(45*time.Minute))
+	assert.Equal(t, "5h", formatIncidentAge(5*time.Hour+10*time.Minute))
+	assert.Equal(t, "3d", formatIncidentAge(80*time.Hour))
+}
+
+func TestSendIncidentNotifications_SlackWebhookEnv(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("DSOPS_SLACK_WEBHOOK", server.URL)
+	t.Setenv("GITHUB_TOKEN", "")
+
+	manager := incident.NewManager(t.TempDir())
+	report, err := manager.CreateReport("secret_leak", "high", "Leaked key", "", nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{Logger: logging.New(false, true), Definition: &config.Definition{}}
+	require.NoError(t, sendIncidentNotifications(cfg, manager, report, notifications.EventTypeIncidentOpened))
+	assert.Equal(t, int32(1), posts.Load(), "the webhook must be posted to once")
+
+	// A Slack provider in dsops.yaml takes precedence over the environment
+	// rather than posting a second message
+	cfg.Definition.Notifications = &config.NotificationConfig{
+		Slack: &config.SlackNotificationConfig{WebhookURL: server.URL + "/configured"},
+	}
+	assert.Equal(t, server.URL+"/configured", incidentNotificationConfig(cfg.Definition).Slack.WebhookURL)
+	require.NoError(t, sendIncidentNotifications(cfg, manager, report, notifications.EventTypeIncidentUpdated))
+	assert.Equal(t, int32(2), posts.Load())
+}
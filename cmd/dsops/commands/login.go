@@ -1,41 +1,79 @@
 package commands
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/systmms/dsops/internal/config"
 	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+	"golang.org/x/term"
 )
 
+// loginTimeout bounds a native login, which waits for the user to finish
+// signing in in a browser
+const loginTimeout = 10 * time.Minute
+
 func NewLoginCommand(cfg *config.Config) *cobra.Command {
 	var (
 		listProviders bool
 		interactive   bool
+		noBrowser     bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "login [provider]",
-		Short: "Assist with provider authentication",
-		Long: `Help authenticate with secret providers by providing step-by-step guidance.
+		Short: "Sign in to a secret store",
+		Long: `Sign in to a secret store defined in dsops.yaml, or show how to
+authenticate with a provider type.
+
+For stores whose type has a native login flow, dsops signs in itself and
+saves the session where later runs (and the vendor CLI) find it:
+
+  aws.sso                 IAM Identity Center device authorization, saved
+                          to the AWS CLI's SSO cache (~/.aws/sso/cache)
+  azure.keyvault, azure.identity, azure
+                          Device code login for stores with
+                          use_device_code: true
+  vault                   auth_method oidc (browser), userpass, ldap or
+                          token, saved to ~/.vault-token
 
-This command provides authentication instructions and can optionally run 
-authentication commands for supported providers.
+For other stores, and for provider type names, dsops shows the
+authentication steps and can run the vendor CLI with --interactive.
 
 Examples:
   dsops login                  # Show all available providers
-  dsops login bitwarden       # Show Bitwarden authentication steps
-  dsops login 1password       # Show 1Password authentication steps  
+  dsops login my-sso           # Sign in to the store named my-sso
+  dsops login vault --no-browser
+  dsops login bitwarden        # Show Bitwarden authentication steps
+  dsops login 1password        # Show 1Password authentication steps
   dsops login aws              # Show AWS authentication steps
-  dsops login --interactive    # Interactive provider selection`,
+  dsops login aws --interactive  # Run 'aws configure'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if listProviders || len(args) == 0 {
 				return showAvailableProviders()
 			}
 
+			// Store names from dsops.yaml take precedence over provider types
+			if err := cfg.Load(); err == nil {
+				if storeConfig, err := cfg.GetProvider(args[0]); err == nil {
+					prompt := &terminalPrompt{out: cmd.ErrOrStderr(), in: cmd.InOrStdin()}
+					if !noBrowser {
+						prompt.openBrowser = openBrowser
+					}
+					return loginStore(cmd.Context(), cfg, cmd.OutOrStdout(), prompt, args[0], storeConfig, interactive)
+				}
+			}
+
 			providerType := strings.ToLower(args[0])
 			return authenticateProvider(providerType, interactive)
 		},
@@ -43,10 +81,120 @@ Examples:
 
 	cmd.Flags().BoolVarP(&listProviders, "list", "l", false, "List all available providers")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Run authentication commands interactively")
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Print login URLs instead of opening a browser")
 
 	return cmd
 }
 
+// loginStore signs in to a configured store with its native login flow, or
+// shows the authentication steps for its type when it has none
+func loginStore(ctx context.Context, cfg *config.Config, out io.Writer, prompt *terminalPrompt, name string, storeConfig config.ProviderConfig, interactive bool) error {
+	p, err := providers.NewRegistry().CreateProvider(name, storeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create store '%s': %w", name, err)
+	}
+
+	authenticator, ok := p.(provider.Authenticator)
+	if !ok {
+		if _, known := loginGuides[strings.ToLower(storeConfig.Type)]; !known {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Store '%s' (%s) has no login flow", name, storeConfig.Type),
+				Suggestion: "Configure the store's credentials as described in its documentation, then run 'dsops doctor'",
+			}
+		}
+		return authenticateProvider(strings.ToLower(storeConfig.Type), interactive)
+	}
+
+	if cfg.NonInteractive {
+		return dserrors.UserError{
+			Message:    "dsops login needs an interactive terminal",
+			Suggestion: "Run 'dsops login " + name + "' without --non-interactive",
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	_, _ = fmt.Fprintf(out, "🔐 Signing in to store '%s' (%s)\n", name, storeConfig.Type)
+	if err := authenticator.Login(ctx, prompt); err != nil {
+		return err
+	}
+
+	if err := p.Validate(ctx); err != nil {
+		_, _ = fmt.Fprintf(out, "⚠️  Signed in to store '%s', but it could not be validated: %v\n", name, err)
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "✅ Signed in to store '%s'\n", name)
+	return nil
+}
+
+// terminalPrompt implements provider.LoginPrompt on the terminal
+type terminalPrompt struct {
+	out         io.Writer
+	in          io.Reader
+	openBrowser func(url string) error
+}
+
+// Visit shows the login URL and code, and opens the URL in a browser when
+// one is available
+func (p *terminalPrompt) Visit(url, code string) {
+	_, _ = fmt.Fprintln(p.out)
+	if code != "" {
+		_, _ = fmt.Fprintf(p.out, "Open %s and enter the code: %s\n", url, code)
+	} else {
+		_, _ = fmt.Fprintf(p.out, "Open %s to sign in\n", url)
+	}
+	if p.openBrowser != nil && p.openBrowser(url) == nil {
+		_, _ = fmt.Fprintln(p.out, "(opened in your browser)")
+	}
+	_, _ = fmt.Fprintln(p.out, "Waiting for the login to complete...")
+}
+
+// ReadSecret reads a value without echo on a terminal, or one line of input
+// otherwise
+func (p *terminalPrompt) ReadSecret(prompt string) (string, error) {
+	_, _ = fmt.Fprintf(p.out, "%s: ", prompt)
+
+	if f, ok := p.in.(*os.File); ok && isTerminal(f) {
+		value, err := term.ReadPassword(int(f.Fd()))
+		_, _ = fmt.Fprintln(p.out)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", strings.ToLower(prompt), err)
+		}
+		return string(value), nil
+	}
+
+	line, err := bufio.NewReader(p.in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read %s: %w", strings.ToLower(prompt), err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// openBrowser opens url in the default browser without waiting for it
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return fmt.Errorf("no display")
+		}
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
 func showAvailableProviders() error {
 	fmt.Println("🔐 Available Secret Providers:")
 	fmt.Println()
@@ -82,32 +230,41 @@ func showAvailableProviders() error {
 	return nil
 }
 
+// loginGuides maps provider types and their aliases to the authentication
+// steps shown for them
+var loginGuides = map[string]func(interactive bool) error{
+	"bitwarden":          authenticateBitwarden,
+	"bw":                 authenticateBitwarden,
+	"1password":          authenticateOnePassword,
+	"onepassword":        authenticateOnePassword,
+	"op":                 authenticateOnePassword,
+	"aws":                authenticateAWS,
+	"aws-secretsmanager": authenticateAWS,
+	"aws.secretsmanager": authenticateAWS,
+	"aws.ssm":            authenticateAWSSSM,
+	"aws.sts":            authenticateAWSSTS,
+	"aws.sso":            authenticateAWSSSO,
+	"gcp":                authenticateGCP,
+	"google":             authenticateGCP,
+	"google-cloud":       authenticateGCP,
+	"gcp.secretmanager":  authenticateGCP,
+	"azure":              authenticateAzure,
+	"az":                 authenticateAzure,
+	"azure.keyvault":     authenticateAzure,
+	"azure.identity":     authenticateAzure,
+	"vault":              authenticateVault,
+	"hashicorp-vault":    authenticateVault,
+}
+
 func authenticateProvider(providerType string, interactive bool) error {
-	switch providerType {
-	case "bitwarden", "bw":
-		return authenticateBitwarden(interactive)
-	case "1password", "onepassword", "op":
-		return authenticateOnePassword(interactive)
-	case "aws", "aws-secretsmanager":
-		return authenticateAWS(interactive)
-	case "aws.ssm":
-		return authenticateAWSSSM(interactive)
-	case "aws.sts":
-		return authenticateAWSSTS(interactive)
-	case "aws.sso":
-		return authenticateAWSSSO(interactive)
-	case "gcp", "google", "google-cloud", "gcp.secretmanager":
-		return authenticateGCP(interactive)
-	case "azure", "az", "azure.keyvault", "azure.identity":
-		return authenticateAzure(interactive)
-	case "vault", "hashicorp-vault":
-		return authenticateVault(interactive)
-	default:
+	guide, ok := loginGuides[providerType]
+	if !ok {
 		return dserrors.UserError{
 			Message:    fmt.Sprintf("Unknown provider: %s", providerType),
-			Suggestion: "Run 'dsops login --list' to see available providers",
+			Suggestion: "Run 'dsops login --list' to see available providers, or name a store from dsops.yaml",
 		}
 	}
+	return guide(interactive)
 }
 
 func authenticateBitwarden(interactive bool) error {
@@ -1,10 +1,19 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 )
 
@@ -25,6 +34,7 @@ func TestNewLoginCommand(t *testing.T) {
 	flags := cmd.Flags()
 	assert.NotNil(t, flags.Lookup("list"))
 	assert.NotNil(t, flags.Lookup("interactive"))
+	assert.NotNil(t, flags.Lookup("no-browser"))
 }
 
 func TestShowAvailableProviders(t *testing.T) {
@@ -140,3 +150,99 @@ func TestAuthenticateAWSSSO_NonInteractive(t *testing.T) {
 	err := authenticateAWSSSO(false)
 	assert.NoError(t, err)
 }
+
+func TestLoginCommand_VaultStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_USERPASS_PASSWORD", "")
+
+	var password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/userpass/login/alice":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			password = body["password"]
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": "login-token", "lease_duration": 3600},
+			})
+		case "/v1/auth/token/lookup-self":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"ttl": 3600}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "dsops.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: 0
+secretStores:
+  team-vault:
+    type: vault
+    address: `+server.URL+`
+    auth_method: userpass
+    userpass_username: alice
+`), 0600))
+
+	cmd := NewLoginCommand(&config.Config{Path: path, Logger: logging.New(false, true)})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("hunter2\n"))
+	cmd.SetArgs([]string{"team-vault", "--no-browser"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "hunter2", password)
+	assert.Contains(t, out.String(), "Password for alice")
+	assert.Contains(t, out.String(), "Signed in to store 'team-vault'")
+
+	token, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	require.NoError(t, err)
+	assert.Equal(t, "login-token", string(token))
+}
+
+func TestLoginStore_NoLoginFlow(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+	storeConfig, err := cfg.GetProvider("literal-store")
+	require.NoError(t, err)
+
+	err = loginStore(t.Context(), cfg, &bytes.Buffer{}, &terminalPrompt{}, "literal-store", storeConfig, false)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "has no login flow")
+}
+
+func TestLoginStore_NonInteractive(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+	storeConfig, err := cfg.GetProvider("vault-store")
+	require.NoError(t, err)
+
+	err = loginStore(t.Context(), cfg, &bytes.Buffer{}, &terminalPrompt{}, "vault-store", storeConfig, false)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "interactive terminal")
+}
+
+func TestTerminalPrompt(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	var opened string
+	prompt := &terminalPrompt{out: &out, in: strings.NewReader("s3cret\r\n"), openBrowser: func(url string) error {
+		opened = url
+		return nil
+	}}
+
+	prompt.Visit("https://device.example.com", "ABCD-EFGH")
+	assert.Equal(t, "https://device.example.com", opened)
+	assert.Contains(t, out.String(), "enter the code: ABCD-EFGH")
+
+	value, err := prompt.ReadSecret("Vault token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+}
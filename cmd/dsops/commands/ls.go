@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// lsEntry is the JSON form of a listed secret
+type lsEntry struct {
+	Key         string            `json:"key"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func NewLsCommand(cfg *config.Config) *cobra.Command {
+	var (
+		format   string
+		limit    int
+		pageSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ls <store>[/prefix]",
+		Short: "List secrets in a secret store",
+		Long: `List the secrets in a secret store without reading their values.
+
+The argument is a store name from dsops.yaml, optionally followed by a
+slash and a key prefix. Listed keys can be used as-is in 'from:' references.
+For Vault, Akeyless and pass the prefix is a folder; keys ending in "/" are
+sub-folders you can list in turn.
+
+Listable store types: aws.secretsmanager, aws.ssm, gcp.secretmanager,
+azure.keyvault, vault, doppler, infisical, akeyless, pass, sops,
+kubernetes, bitwarden.secretsmanager, onepassword.connect,
+onepassword.serviceaccount, file.dotenv, file.json, file.yaml and keychain
+(backend: file).
+
+Examples:
+  # Everything in a store
+  dsops ls aws-prod
+
+  # Only keys under a prefix
+  dsops ls aws-prod/myapp/
+
+  # Browse Vault KV v2
+  dsops ls vault/secret/data/myapp/
+
+  # JSON for scripting
+  dsops ls ssm --format json --limit 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLs(cfg, args[0], format, limit, pageSize, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of secrets to list (0 for all)")
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "Secrets to request per page (0 for the store default)")
+
+	return cmd
+}
+
+func runLs(cfg *config.Config, target, format string, limit, pageSize int, out io.Writer) error {
+	if format != "table" && format != "json" {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported format: %s", format),
+			Suggestion: "Use --format table or --format json",
+		}
+	}
+
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	store, prefix, _ := strings.Cut(target, "/")
+	lister, err := openLister(cfg, store)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := listAllSecrets(context.Background(), lister, prefix, limit, pageSize)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", target, err)
+	}
+
+	if format == "json" {
+		return outputLsJSON(out, secrets)
+	}
+	return outputLsTable(out, target, secrets)
+}
+
+// openLister creates the named store and ensures it supports listing
+func openLister(cfg *config.Config, store string) (provider.Lister, error) {
+	providerConfig, err := cfg.GetProvider(store)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := providers.NewRegistry().CreateProvider(store, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store '%s': %w", store, err)
+	}
+
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' (%s) does not support listing", store, providerConfig.Type),
+			Suggestion: "Listable store types: aws.secretsmanager, aws.ssm, gcp.secretmanager, azure.keyvault, vault, doppler, infisical, akeyless, pass, sops, kubernetes, bitwarden.secretsmanager, onepassword.connect, onepassword.serviceaccount, file.dotenv, file.json, file.yaml, keychain (backend: file)",
+		}
+	}
+
+	return lister, nil
+}
+
+// listAllSecrets follows page tokens until the listing is complete or limit
+// secrets have been collected
+func listAllSecrets(ctx context.Context, lister provider.Lister, prefix string, limit, pageSize int) ([]provider.SecretInfo, error) {
+	opts := provider.ListOptions{Prefix: prefix, PageSize: pageSize}
+
+	var secrets []provider.SecretInfo
+	for {
+		page, err := lister.ListSecrets(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, page.Secrets...)
+
+		if limit > 0 && len(secrets) >= limit {
+			return secrets[:limit], nil
+		}
+		if page.NextPageToken == "" || page.NextPageToken == opts.PageToken {
+			return secrets, nil
+		}
+		opts.PageToken = page.NextPageToken
+	}
+}
+
+func outputLsJSON(out io.Writer, secrets []provider.SecretInfo) error {
+	entries := make([]lsEntry, len(secrets))
+	for i, s := range secrets {
+		entries[i] = lsEntry{
+			Key:         s.Key,
+			Description: s.Description,
+			Type:        s.Type,
+			Version:     s.Version,
+			Tags:        s.Tags,
+		}
+		if !s.UpdatedAt.IsZero() {
+			updatedAt := s.UpdatedAt
+			entries[i].UpdatedAt = &updatedAt
+		}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode listing: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+func outputLsTable(out io.Writer, target string, secrets []provider.SecretInfo) error {
+	if len(secrets) == 0 {
+		_, _ = fmt.Fprintf(out, "No secrets found in %s\n", target)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tTYPE\tVERSION\tUPDATED\tDESCRIPTION")
+	for _, s := range secrets {
+		updated := "-"
+		if !s.UpdatedAt.IsZero() {
+			updated = s.UpdatedAt.Local().Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			s.Key,
+			valueOrDash(s.Type),
+			valueOrDash(shortVersion(s.Version)),
+			updated,
+			valueOrDash(s.Description),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%d secret(s)\n", len(secrets))
+	return nil
+}
+
+// shortVersion truncates long opaque version IDs for table output
+func shortVersion(version string) string {
+	if len(version) > 12 {
+		return version[:12] + "…"
+	}
+	return version
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
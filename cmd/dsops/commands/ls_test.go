@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// pagedLister serves a fixed set of secrets through provider.PageSecrets
+type pagedLister struct {
+	secrets []provider.SecretInfo
+	calls   int
+}
+
+func (l *pagedLister) ListSecrets(_ context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	l.calls++
+	return provider.PageSecrets(l.secrets, opts)
+}
+
+func TestNewLsCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewLsCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "ls <store>[/prefix]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	for _, flag := range []string{"format", "limit", "page-size"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+	assert.Equal(t, "table", cmd.Flags().Lookup("format").DefValue)
+}
+
+func TestOpenLister(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+
+	lister, err := openLister(cfg, "vault-store")
+	require.NoError(t, err)
+	assert.NotNil(t, lister)
+
+	_, err = openLister(cfg, "literal-store")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support listing")
+
+	_, err = openLister(cfg, "missing")
+	assert.Error(t, err)
+}
+
+func TestListAllSecrets(t *testing.T) {
+	t.Parallel()
+
+	lister := &pagedLister{secrets: []provider.SecretInfo{
+		{Key: "app/a"}, {Key: "app/b"}, {Key: "app/c"}, {Key: "other/d"},
+	}}
+
+	secrets, err := listAllSecrets(context.Background(), lister, "app/", 0, 1)
+	require.NoError(t, err)
+	assert.Len(t, secrets, 3)
+	assert.Equal(t, 3, lister.calls, "every page should be fetched")
+
+	lister.calls = 0
+	secrets, err = listAllSecrets(context.Background(), lister, "", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []provider.SecretInfo{{Key: "app/a"}, {Key: "app/b"}}, secrets)
+	assert.Equal(t, 2, lister.calls, "listing stops once the limit is reached")
+}
+
+func TestLsOutput(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	secrets := []provider.SecretInfo{
+		{Key: "db", Type: "SecureString", Version: "3", UpdatedAt: updated, Description: "Database"},
+		{Key: "api", Version: "a1b2c3d4-e5f6-7890-abcd-ef1234567890"},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputLsTable(&table, "ssm", secrets))
+	assert.Contains(t, table.String(), "KEY")
+	assert.Contains(t, table.String(), "SecureString")
+	assert.Contains(t, table.String(), "a1b2c3d4-e5f…")
+	assert.Contains(t, table.String(), "2 secret(s)")
+
+	var empty bytes.Buffer
+	require.NoError(t, outputLsTable(&empty, "ssm/none", nil))
+	assert.Equal(t, "No secrets found in ssm/none\n", empty.String())
+
+	var out bytes.Buffer
+	require.NoError(t, outputLsJSON(&out, secrets))
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "db", entries[0]["key"])
+	assert.Equal(t, "2026-03-01T12:00:00Z", entries[0]["updated_at"])
+	assert.NotContains(t, entries[1], "updated_at")
+	assert.NotContains(t, entries[1], "description")
+}
+
+func TestRunLs_RejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	err := runLs(&config.Config{Logger: logging.New(false, true)}, "store", "yaml", 0, 0, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported format")
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/rotation/notifications"
+)
+
+// newNotificationManager builds a notification manager with every provider
+// configured under notifications in dsops.yaml. The same manager carries
+// rotation and incident events. It returns nil when nothing is configured.
+func newNotificationManager(notificationConfig *config.NotificationConfig) (*notifications.Manager, error) {
+	if notificationConfig == nil {
+		return nil, nil
+	}
+
+	var providers []notifications.NotificationProvider
+
+	if slack := notificationConfig.Slack; slack != nil {
+		slackConfig := &notifications.SlackNotificationConfig{
+			WebhookURL: slack.WebhookURL,
+			Channel:    slack.Channel,
+			Events:     slack.Events,
+		}
+		if slack.Mentions != nil {
+			slackConfig.Mentions = &notifications.SlackMentionConfig{
+				OnFailure:  slack.Mentions.OnFailure,
+				OnRollback: slack.Mentions.OnRollback,
+			}
+		}
+		provider, err := notifications.CreateSlackProvider(slackConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slack notification config: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	if email := notificationConfig.Email; email != nil {
+		provider, err := notifications.CreateEmailProvider(&notifications.EmailNotificationConfig{
+			SMTP: notifications.SMTPConfigInput{
+				Host:     email.SMTP.Host,
+				Port:     email.SMTP.Port,
+				Username: email.SMTP.Username,
+				Password: email.SMTP.Password,
+				TLS:      email.SMTP.TLS,
+			},
+			From:      email.From,
+			To:        email.To,
+			Events:    email.Events,
+			BatchMode: email.BatchMode,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid email notification config: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	if pd := notificationConfig.PagerDuty; pd != nil {
+		provider, err := notifications.CreatePagerDutyProvider(&notifications.PagerDutyNotificationConfig{
+			IntegrationKey: pd.IntegrationKey,
+			ServiceID:      pd.ServiceID,
+			Severity:       pd.Severity,
+			Events:         pd.Events,
+			AutoResolve:    pd.AutoResolve,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid pagerduty notification config: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	for _, webhook := range notificationConfig.Webhooks {
+		webhookConfig := &notifications.WebhookNotificationConfig{
+			Name:            webhook.Name,
+			URL:             webhook.URL,
+			Method:          webhook.Method,
+			Headers:         webhook.Headers,
+			Events:          webhook.Events,
+			PayloadTemplate: webhook.PayloadTemplate,
+			TimeoutSeconds:  webhook.TimeoutSeconds,
+		}
+		if webhook.Retry != nil {
+			webhookConfig.Retry = &notifications.WebhookRetryConfig{
+				MaxAttempts: webhook.Retry.MaxAttempts,
+				Backoff:     webhook.Retry.Backoff,
+			}
+		}
+		provider, err := notifications.CreateWebhookProvider(webhookConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook notification config %q: %w", webhook.Name, err)
+		}
+		providers = append(providers, provider)
+	}
+
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	manager := notifications.NewManager(0)
+	for _, provider := range providers {
+		manager.RegisterProvider(provider)
+	}
+
+	return manager, nil
+}
+
+// startNotificationManager starts a notification manager for the providers
+// configured in dsops.yaml. Configuration errors are logged and leave the
+// manager without providers, since notifications are best-effort. Callers
+// must Stop the returned manager to flush queued events.
+func startNotificationManager(ctx context.Context, cfg *config.Config) *notifications.Manager {
+	var notificationConfig *config.NotificationConfig
+	if cfg.Definition != nil {
+		notificationConfig = cfg.Definition.Notifications
+	}
+
+	manager, err := newNotificationManager(notificationConfig)
+	if err != nil {
+		cfg.Logger.Warn("Notifications disabled: %v", err)
+	}
+	if manager == nil {
+		manager = notifications.NewManager(0)
+	}
+
+	manager.Start(ctx)
+	return manager
+}
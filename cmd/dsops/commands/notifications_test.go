@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+)
+
+func TestNewNotificationManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil config", func(t *testing.T) {
+		t.Parallel()
+		manager, err := newNotificationManager(nil)
+		require.NoError(t, err)
+		assert.Nil(t, manager)
+	})
+
+	t.Run("empty config", func(t *testing.T) {
+		t.Parallel()
+		manager, err := newNotificationManager(&config.NotificationConfig{})
+		require.NoError(t, err)
+		assert.Nil(t, manager)
+	})
+
+	t.Run("all providers", func(t *testing.T) {
+		t.Parallel()
+		manager, err := newNotificationManager(&config.NotificationConfig{
+			Slack: &config.SlackNotificationConfig{
+				WebhookURL: "https://hooks.slack.com/services/T000/B000/XXX",
+				Mentions:   &config.SlackMentions{OnFailure: []string{"@oncall"}},
+			},
+			Email: &config.EmailNotificationConfig{
+				SMTP: config.SMTPConfig{Host: "smtp.example.com", Port: 587},
+				From: "dsops@example.com",
+				To:   []string{"security@example.com"},
+			},
+			PagerDuty: &config.PagerDutyNotificationConfig{
+				IntegrationKey: "key",
+				Events:         []string{"incident_opened", "incident_resolved"},
+			},
+			Webhooks: []config.WebhookNotificationConfig{
+				{Name: "siem", URL: "https://siem.example.com/hook", Retry: &config.WebhookRetryConfig{MaxAttempts: 2}},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, manager)
+
+		var names []string
+		for _, provider := range manager.Providers() {
+			names = append(names, provider.Name())
+		}
+		assert.Equal(t, []string{"slack", "email", "pagerduty", "webhook:siem"}, names)
+	})
+
+	t.Run("invalid provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := newNotificationManager(&config.NotificationConfig{
+			PagerDuty: &config.PagerDutyNotificationConfig{},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pagerduty")
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/drift"
 	"github.com/systmms/dsops/internal/dsopsdata"
 	"github.com/systmms/dsops/internal/providers"
 	"github.com/systmms/dsops/internal/resolve"
@@ -24,6 +25,7 @@ func NewPlanCommand(cfg *config.Config) *cobra.Command {
 		envName    string
 		outputJSON bool
 		dataDir    string
+		check      bool
 	)
 
 	cmd := &cobra.Command{
@@ -31,7 +33,12 @@ func NewPlanCommand(cfg *config.Config) *cobra.Command {
 		Short: "Show what secrets will be resolved (no values shown)",
 		Long: `Plan shows which variables will be resolved and from which sources, 
 without fetching actual secret values. This is useful for debugging 
-configuration and verifying provider connectivity.`,
+configuration and verifying provider connectivity. Variables whose 'from'
+is a list of fallbacks show each fallback and its 'on' condition below them.
+
+With --check, every referenced secret is also described in its store to
+report missing keys, stale secrets and deprecated version pins. See
+'dsops drift' for checking all environments and scanning for unused secrets.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Load configuration
 			if err := cfg.Load(); err != nil {
@@ -53,17 +60,41 @@ configuration and verifying provider connectivity.`,
 				return fmt.Errorf("failed to plan: %w", err)
 			}
 
+			var report *drift.Report
+			if check {
+				report, err = drift.NewChecker(cfg, resolver.GetProvider).Check(ctx, drift.Options{Envs: []string{envName}})
+				if err != nil {
+					return fmt.Errorf("failed to check secrets: %w", err)
+				}
+			}
+
 			// Output results
 			if outputJSON {
-				return outputPlanJSON(result)
+				if err := outputPlanJSON(result, report); err != nil {
+					return err
+				}
+				if report != nil {
+					return driftError(report)
+				}
+				return nil
+			}
+
+			planErr := outputPlanTable(result, cfg, envName)
+			if report != nil {
+				fmt.Printf("\nSecret check:\n")
+				outputDriftTable(os.Stdout, report)
+				if planErr == nil {
+					planErr = driftError(report)
+				}
 			}
-			return outputPlanTable(result, cfg, envName)
+			return planErr
 		},
 	}
 
 	cmd.Flags().StringVar(&envName, "env", "", "Environment name to plan (required)")
 	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
 	cmd.Flags().StringVar(&dataDir, "data-dir", "./dsops-data", "Path to dsops-data repository (optional)")
+	cmd.Flags().BoolVar(&check, "check", false, "Describe each referenced secret and report missing, stale or deprecated ones")
 	_ = cmd.MarkFlagRequired("env")
 
 	return cmd
@@ -174,8 +205,9 @@ func registerProviders(resolver *resolve.Resolver, cfg *config.Config, dataDir s
 	return nil
 }
 
-// outputPlanJSON outputs the plan result as JSON
-func outputPlanJSON(result *resolve.PlanResult) error {
+// outputPlanJSON outputs the plan result as JSON, with the secret check
+// report when --check was given
+func outputPlanJSON(result *resolve.PlanResult, report *drift.Report) error {
 	output := map[string]interface{}{
 		"variables": result.Variables,
 		"errors":    make([]string, len(result.Errors)),
@@ -190,6 +222,10 @@ func outputPlanJSON(result *resolve.PlanResult) error {
 		output["errors"].([]string)[i] = err.Error()
 	}
 
+	if report != nil {
+		output["check"] = report
+	}
+
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
@@ -233,6 +269,15 @@ func outputPlanTable(result *resolve.PlanResult, cfg *config.Config, envName str
 			optional,
 			status,
 		)
+
+		// Fallbacks follow on their own rows, in the order they are tried
+		for _, fallback := range variable.Fallbacks {
+			fallbackStatus := ""
+			if fallback.Error != nil {
+				fallbackStatus = "✗ ERROR"
+			}
+			_, _ = fmt.Fprintf(w, "\t↳ %s (on %s)\t\t\t%s\n", fallback.Source, fallback.On, fallbackStatus)
+		}
 	}
 
 	_ = w.Flush()
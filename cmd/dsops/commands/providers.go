@@ -35,6 +35,9 @@ Shows both built-in provider types and configured provider instances.`,
 
 			for _, providerType := range supportedTypes {
 				description := getProviderDescription(providerType)
+				if path, ok := registry.PluginPath(providerType); ok {
+					description = "Plugin at " + path
+				}
 				_, _ = fmt.Fprintf(w, "%s\t%s\n", providerType, description)
 			}
 			_ = w.Flush()
@@ -88,27 +91,38 @@ Shows both built-in provider types and configured provider instances.`,
 // getProviderDescription returns a description for a provider type
 func getProviderDescription(providerType string) string {
 	descriptions := map[string]string{
-		"literal":            "Static literal values for testing",
-		"mock":               "Mock provider for testing and development",
-		"json":               "JSON provider with test data for transforms",
-		"bitwarden":          "Bitwarden password manager via CLI",
-		"aws.secretsmanager": "AWS Secrets Manager via SDK",
-		"aws.ssm":            "AWS Systems Manager Parameter Store",
-		"aws.sts":            "AWS STS for temporary credentials",
-		"aws.sso":            "AWS IAM Identity Center (SSO)",
-		"aws":                "AWS unified provider (intelligent routing)",
-		"gcp.secretmanager":  "Google Cloud Secret Manager",
-		"gcp":                "GCP unified provider (intelligent routing)",
-		"azure.keyvault":     "Azure Key Vault",
-		"azure.identity":     "Azure Managed Identity / Service Principal",
-		"azure":              "Azure unified provider (intelligent routing)",
-		"onepassword":        "1Password password manager via CLI",
-		"vault":              "HashiCorp Vault",
-		"doppler":            "Doppler centralized secrets management",
-		"pass":               "pass (zx2c4) Unix password manager",
-		"keychain":           "OS native keychain (macOS Keychain, Linux Secret Service)",
-		"infisical":          "Infisical open-source secret management platform",
-		"akeyless":           "Akeyless enterprise zero-knowledge secret management",
+		"literal":                    "Static literal values for testing",
+		"mock":                       "Mock provider for testing and development",
+		"json":                       "Deprecated alias of file.json",
+		"bitwarden":                  "Bitwarden password manager via CLI",
+		"bitwarden.secretsmanager":   "Bitwarden Secrets Manager via API (machine access token)",
+		"aws.secretsmanager":         "AWS Secrets Manager via SDK",
+		"aws.ssm":                    "AWS Systems Manager Parameter Store",
+		"aws.sts":                    "AWS STS for temporary credentials",
+		"aws.sso":                    "AWS IAM Identity Center (SSO)",
+		"aws":                        "AWS unified provider (intelligent routing)",
+		"gcp.secretmanager":          "Google Cloud Secret Manager",
+		"gcp":                        "GCP unified provider (intelligent routing)",
+		"azure.keyvault":             "Azure Key Vault",
+		"azure.identity":             "Azure Managed Identity / Service Principal",
+		"azure":                      "Azure unified provider (intelligent routing)",
+		"onepassword":                "1Password password manager via CLI",
+		"onepassword.connect":        "1Password Connect server via API",
+		"onepassword.serviceaccount": "1Password via service account token, no CLI",
+		"vault":                      "HashiCorp Vault",
+		"doppler":                    "Doppler centralized secrets management",
+		"pass":                       "pass (zx2c4) Unix password manager",
+		"keychain":                   "OS native keychain (macOS Keychain, Linux Secret Service) or an encrypted file",
+		"infisical":                  "Infisical open-source secret management platform",
+		"akeyless":                   "Akeyless enterprise zero-knowledge secret management",
+		"sops":                       "SOPS/age encrypted files, decrypted offline",
+		"kubernetes":                 "Kubernetes Secrets via kubeconfig or in-cluster service account",
+		"consul":                     "Consul KV store with ACL tokens and mTLS",
+		"etcd":                       "etcd v3 key-value store with password auth and mTLS",
+		"file.dotenv":                "Local .env file, optionally encrypted with age or GPG",
+		"file.json":                  "Local JSON file, optionally encrypted with age or GPG",
+		"file.yaml":                  "Local YAML file, optionally encrypted with age or GPG",
+		"plugin":                     "Out-of-process provider plugin (command: executable)",
 	}
 
 	if desc, exists := descriptions[providerType]; exists {
@@ -141,6 +155,13 @@ func getProviderDetails(providerType string) []string {
 			"Requires authentication: bw login && bw unlock",
 			"Key format: 'item-name.field' or 'item-id.field'",
 		},
+		"bitwarden.secretsmanager": {
+			"Bitwarden Secrets Manager over HTTP, no CLI or unlocked vault needed",
+			"Authenticates with a machine account access token (access_token or BWS_ACCESS_TOKEN)",
+			"Secrets are decrypted locally; all readable secrets are fetched in one batch",
+			"Cloud or self-hosted via server_url",
+			"Key format: 'SECRET_NAME' or the secret's ID",
+		},
 		"aws.secretsmanager": {
 			"Uses AWS SDK v2 for direct API access",
 			"Supports JSON secrets with field extraction",
@@ -155,6 +176,18 @@ func getProviderDetails(providerType string) []string {
 			"Key format: 'item-name.field' or 'op://vault/item/field'",
 			"Supports vault-specific access",
 		},
+		"onepassword.connect": {
+			"Reads items from a 1Password Connect server over HTTP",
+			"Needs host and token (or OP_CONNECT_HOST and OP_CONNECT_TOKEN)",
+			"Each item is fetched once, however many of its fields are used",
+			"Key format: 'vault/item[/section][/field]', op:// prefix optional",
+		},
+		"onepassword.serviceaccount": {
+			"Reads items with a 1Password service account, no op CLI needed",
+			"Token from token or OP_SERVICE_ACCOUNT_TOKEN",
+			"Each item is fetched once, however many of its fields are used",
+			"Key format: 'vault/item[/section][/field]', op:// prefix optional",
+		},
 		"aws.ssm": {
 			"AWS Systems Manager Parameter Store",
 			"Supports standard and SecureString parameters",
@@ -209,7 +242,9 @@ func getProviderDetails(providerType string) []string {
 			"Supports versioned secrets and HSM-backed storage",
 			"JSON path extraction with # syntax",
 			"Managed Identity and Service Principal authentication",
-			"Key format: 'secret-name/version' or 'secret-name#.json.path'",
+			"Certificates as PEM fields and public keys with cert: and key: prefixes",
+			"Rotates secrets, certificates (via policy) and keys",
+			"Key format: 'secret-name/version', 'secret-name#.json.path' or 'cert:name/fullchain'",
 		},
 		"azure.identity": {
 			"Azure Managed Identity and Service Principal tokens",
@@ -243,6 +278,8 @@ func getProviderDetails(providerType string) []string {
 			"OS native credential storage",
 			"macOS: Keychain Services (hardware-backed on Apple Silicon)",
 			"Linux: Secret Service D-Bus API (gnome-keyring, KWallet)",
+			"backend: file stores an age-encrypted file for headless machines",
+			"Manage entries with dsops keychain set/rm/ls",
 			"Works offline with no external dependencies",
 			"Key format: 'service-name/account-name'",
 			"Supports Touch ID authentication on macOS",
@@ -263,6 +300,56 @@ func getProviderDetails(providerType string) []string {
 			"Key format: '/path/to/secret[@vN]'",
 			"Self-hosted or cloud-hosted gateway",
 		},
+		"sops": {
+			"Decrypts SOPS files encrypted with age, fully offline",
+			"YAML and JSON files; the MAC is verified on every read",
+			"Age identity from age_key_file, SOPS_AGE_KEY(_FILE) or the keychain",
+			"Writes re-encrypt values so the file stays editable with sops",
+			"Key format: dotted path, e.g. 'database.password' or 'hosts.0'",
+		},
+		"kubernetes": {
+			"Reads v1 Secrets from any cluster kubectl can reach",
+			"Auth: kubeconfig tokens, client certificates, exec plugins or in-cluster service account",
+			"Key format: 'namespace/name#key' or 'name#key' (store namespace)",
+			"Without '#key' the whole Secret is returned as JSON",
+			"Render Secret manifests with 'dsops render --format k8s-secret'",
+		},
+		"consul": {
+			"Reads keys from the Consul KV store over the HTTP API",
+			"Auth: ACL token or token file, TLS and mTLS client certificates",
+			"Key format: 'path/to/key' or 'path/to/key#.json.field', under the store prefix",
+			"Version is the key's ModifyIndex; changes can be watched with blocking queries",
+		},
+		"etcd": {
+			"Reads keys from etcd v3 through its JSON gateway",
+			"Auth: username/password, TLS and mTLS client certificates",
+			"Key format: 'path/to/key' or 'path/to/key#.json.field', under the store prefix",
+			"Version is the key's mod_revision; changes can be watched with etcd watches",
+		},
+		"file.dotenv": {
+			"Reads KEY=value lines from a local file ('path'), relative to dsops.yaml",
+			"Key format: 'VARIABLE_NAME' or 'VARIABLE_NAME#.json.field'",
+			"Files ending in .age or .gpg are decrypted with age or gpg (decrypt: to override)",
+			"Warns when the file is readable by every user",
+		},
+		"file.json": {
+			"Reads a local JSON file ('path'), relative to dsops.yaml",
+			"Key format: dotted path such as 'database.password' or 'hosts.0'",
+			"Files ending in .age or .gpg are decrypted with age or gpg (decrypt: to override)",
+			"Warns when the file is readable by every user",
+		},
+		"file.yaml": {
+			"Reads a local YAML file ('path'), relative to dsops.yaml",
+			"Key format: dotted path such as 'database.password' or 'hosts.0'",
+			"Files ending in .age or .gpg are decrypted with age or gpg (decrypt: to override)",
+			"Warns when the file is readable by every user",
+		},
+		"plugin": {
+			"Runs an external provider binary named by 'command' (and 'args')",
+			"Other settings are passed to the plugin; timeout_ms bounds every call",
+			"Plugins in the plugin directory are available as their own types",
+			"Write plugins with the github.com/systmms/dsops/pkg/plugin SDK",
+		},
 	}
 
 	if detail, exists := details[providerType]; exists {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers/kubernetes"
 	"github.com/systmms/dsops/internal/resolve"
 	"github.com/systmms/dsops/internal/template"
 )
@@ -20,6 +23,14 @@ func NewRenderCommand(cfg *config.Config) *cobra.Command {
 		templatePath string
 		ttl          string
 		permissions  string
+		secretName   string
+		namespace    string
+		secretType   string
+		sealedCert   string
+		sealedScope  string
+		apply        bool
+		kubeconfig   string
+		kubeContext  string
 	)
 
 	cmd := &cobra.Command{
@@ -31,20 +42,39 @@ The output format is auto-detected from the file extension, or can be specified
 explicitly with --format.
 
 Supported formats:
-  dotenv   - .env file format (default)
-  json     - JSON object with variables
-  yaml     - YAML object with variables  
-  template - Custom Go template
+  dotenv     - .env file format (default)
+  json       - JSON object with variables
+  yaml       - YAML object with variables  
+  template   - Custom Go template
+  k8s-secret - Kubernetes v1 Secret manifest
+  sealed     - Bitnami SealedSecret manifest, safe to commit
+
+The Kubernetes formats name the Secret after the environment unless --name
+is given. With --apply the manifest is sent to the cluster with server-side
+apply, and --out becomes optional. The sealed format encrypts each value
+for the sealed-secrets controller, using --sealed-cert or the certificate
+fetched from the controller in kube-system.
 
 Examples:
   dsops render --env development --out .env.development
   dsops render --env production --out config.json --format json
   dsops render --env staging --out app.yaml --format yaml
-  dsops render --env prod --out k8s-secret.yaml --template secret.tmpl`,
+  dsops render --env prod --out k8s-secret.yaml --template secret.tmpl
+
+  # Kubernetes Secret applied straight to the current context
+  dsops render --env prod --format k8s-secret --name app-secrets --namespace prod --apply
+
+  # SealedSecret to commit alongside the other manifests
+  dsops render --env prod --format sealed --name app-secrets --namespace prod \
+    --sealed-cert pub-cert.pem --out deploy/app-secrets.sealed.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Validate required flags
-			if outputPath == "" {
-				return fmt.Errorf("--out flag is required for security (explicit opt-in to write files)")
+			kubernetesFormat := format == "k8s-secret" || format == "sealed"
+			if outputPath == "" && !apply {
+				return fmt.Errorf(`required flag(s) "out" not set; --out is required for security (explicit opt-in to write files) unless --apply is used`)
+			}
+			if apply && !kubernetesFormat {
+				return fmt.Errorf("--apply needs --format k8s-secret or --format sealed")
 			}
 
 			// Parse TTL if provided
@@ -73,7 +103,7 @@ Examples:
 			}
 
 			// Check output path policy
-			if cfg.HasPolicies() {
+			if outputPath != "" && cfg.HasPolicies() {
 				enforcer := cfg.GetPolicyEnforcer()
 				if err := enforcer.ValidateOutputPath(outputPath); err != nil {
 					return fmt.Errorf("output path policy violation: %w", err)
@@ -143,11 +173,54 @@ Examples:
 				Permissions: perms,
 			}
 
-			if err := renderer.Render(renderOptions); err != nil {
-				return fmt.Errorf("failed to render: %w", err)
+			var kubeClient *kubernetes.Client
+			if kubernetesFormat {
+				target := kubernetesTarget{
+					name:        secretName,
+					namespace:   namespace,
+					secretType:  secretType,
+					sealedCert:  sealedCert,
+					sealedScope: sealedScope,
+					apply:       apply,
+					connection:  kubernetes.ConnectionOptions{Kubeconfig: kubeconfig, Context: kubeContext},
+				}
+				if target.name == "" {
+					target.name = strings.ToLower(envName)
+				}
+				renderOptions.Kubernetes, kubeClient, err = target.options(ctx, format)
+				if err != nil {
+					return err
+				}
 			}
 
-			// Security reminder
+			if outputPath != "" {
+				if err := renderer.Render(renderOptions); err != nil {
+					return fmt.Errorf("failed to render: %w", err)
+				}
+			}
+
+			if apply {
+				manifest, err := renderer.RenderContent(renderOptions)
+				if err != nil {
+					return fmt.Errorf("failed to render: %w", err)
+				}
+				if err := kubeClient.Apply(ctx, manifest); err != nil {
+					return dserrors.UserError{
+						Message:    fmt.Sprintf("Failed to apply %s to %s", renderOptions.Kubernetes.Name, kubeClient.Host()),
+						Details:    err.Error(),
+						Suggestion: "Check that you can create and patch secrets in the namespace ('kubectl auth can-i patch secrets')",
+					}
+				}
+				cfg.Logger.Info("Applied %s %s/%s with %d keys", format, renderOptions.Kubernetes.Namespace, renderOptions.Kubernetes.Name, len(variables))
+				if outputPath == "" {
+					return nil
+				}
+			}
+
+			// Security reminder; sealed output is encrypted and meant to be committed
+			if format == "sealed" {
+				return nil
+			}
 			cfg.Logger.Warn("File contains secrets - ensure it's added to .gitignore")
 			if ttlDuration == 0 {
 				cfg.Logger.Info("Consider using --ttl flag for auto-deletion of temporary files")
@@ -159,13 +232,89 @@ Examples:
 
 	cmd.Flags().StringVar(&envName, "env", "", "Environment name to render (required)")
 	cmd.Flags().StringVar(&outputPath, "out", "", "Output file path (required for security)")
-	cmd.Flags().StringVar(&format, "format", "", "Output format (dotenv|json|yaml|template, auto-detected from extension)")
+	cmd.Flags().StringVar(&format, "format", "", "Output format (dotenv|json|yaml|template|k8s-secret|sealed, auto-detected from extension)")
 	cmd.Flags().StringVar(&templatePath, "template", "", "Template file path (required for template format)")
 	cmd.Flags().StringVar(&ttl, "ttl", "", "Auto-delete file after duration (e.g., '10m', '1h')")
 	cmd.Flags().StringVar(&permissions, "permissions", "0600", "File permissions in octal (default: 0600)")
+	cmd.Flags().StringVar(&secretName, "name", "", "Kubernetes Secret name (default: the environment name)")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "Kubernetes namespace (default: the kubeconfig context's namespace)")
+	cmd.Flags().StringVar(&secretType, "secret-type", "", "Kubernetes Secret type (default: Opaque)")
+	cmd.Flags().StringVar(&sealedCert, "sealed-cert", "", "Sealed-secrets controller certificate (default: fetched from the cluster)")
+	cmd.Flags().StringVar(&sealedScope, "sealed-scope", "", "Sealing scope: strict, namespace-wide or cluster-wide (default: strict)")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Apply the Kubernetes manifest to the cluster with server-side apply")
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (default: $KUBECONFIG or ~/.kube/config)")
+	cmd.Flags().StringVar(&kubeContext, "context", "", "kubeconfig context to use")
 
 	_ = cmd.MarkFlagRequired("env")
-	_ = cmd.MarkFlagRequired("out")
 
 	return cmd
 }
+
+// sealedSecretsCertPath is where kubeseal fetches the controller's
+// certificate, through the API server's service proxy
+const sealedSecretsCertPath = "/api/v1/namespaces/kube-system/services/http:sealed-secrets-controller:/proxy/v1/cert.pem"
+
+// kubernetesTarget holds the render flags for the Kubernetes formats
+type kubernetesTarget struct {
+	name        string
+	namespace   string
+	secretType  string
+	sealedCert  string
+	sealedScope string
+	apply       bool
+	connection  kubernetes.ConnectionOptions
+}
+
+// options builds the manifest options, connecting to the cluster only when
+// something is needed from it: the apply target, the default namespace or
+// the sealing certificate
+func (t kubernetesTarget) options(ctx context.Context, format string) (template.KubernetesOptions, *kubernetes.Client, error) {
+	opts := template.KubernetesOptions{
+		Name:        t.name,
+		Namespace:   t.namespace,
+		Type:        t.secretType,
+		SealedScope: t.sealedScope,
+	}
+
+	sealed := format == "sealed"
+	needsNamespace := opts.Namespace == "" && (t.apply || (sealed && opts.SealedScope != template.SealedScopeClusterWide))
+	needsCert := sealed && t.sealedCert == ""
+
+	var client *kubernetes.Client
+	if t.apply || needsNamespace || needsCert {
+		var err error
+		client, err = kubernetes.NewClient(t.connection)
+		if err != nil {
+			return opts, nil, dserrors.UserError{
+				Message:    "Failed to load Kubernetes configuration",
+				Details:    err.Error(),
+				Suggestion: "Pass --kubeconfig and --context, or use --namespace and --sealed-cert to render without a cluster",
+			}
+		}
+	}
+	if needsNamespace {
+		opts.Namespace = client.Namespace()
+	}
+
+	if sealed {
+		if t.sealedCert != "" {
+			cert, err := os.ReadFile(t.sealedCert)
+			if err != nil {
+				return opts, nil, fmt.Errorf("failed to read sealing certificate: %w", err)
+			}
+			opts.SealingCert = cert
+		} else {
+			cert, err := client.Get(ctx, sealedSecretsCertPath)
+			if err != nil {
+				return opts, nil, dserrors.UserError{
+					Message:    "Failed to fetch the sealed-secrets certificate from the cluster",
+					Details:    err.Error(),
+					Suggestion: "Export it with 'kubeseal --fetch-cert > pub-cert.pem' and pass --sealed-cert pub-cert.pem",
+				}
+			}
+			opts.SealingCert = cert
+		}
+	}
+
+	return opts, client, nil
+}
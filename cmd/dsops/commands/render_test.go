@@ -3,6 +3,8 @@ package commands
 import (
 	"bytes"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -395,3 +397,73 @@ func captureRenderOutput(t *testing.T, cmd *cobra.Command, args []string) string
 
 	return buf.String()
 }
+
+func TestRenderCommand_KubernetesSecret(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "dsops.yaml")
+
+	configData := &config.Definition{
+		Version: 0,
+		Envs: map[string]config.Environment{
+			"Prod": {
+				"API_KEY": {
+					Literal: "test-api-key-123",
+				},
+			},
+		},
+	}
+
+	configBytes, _ := yaml.Marshal(configData)
+	require.NoError(t, os.WriteFile(configPath, configBytes, 0644))
+
+	cfg := &config.Config{
+		Path:   configPath,
+		Logger: logging.New(false, true),
+	}
+	require.NoError(t, cfg.Load())
+
+	// A fake API server records what --apply sends
+	var appliedPath, appliedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		appliedPath, appliedBody = r.URL.Path, string(body)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	kubeconfig := filepath.Join(tempDir, "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`current-context: test
+clusters:
+- name: test
+  cluster:
+    server: `+server.URL+`
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+    namespace: apps
+users:
+- name: test
+  user:
+    token: abc
+`), 0600))
+
+	outputPath := filepath.Join(tempDir, "secret.yaml")
+	cmd := NewRenderCommand(cfg)
+	_ = captureRenderOutput(t, cmd, []string{"--env", "Prod", "--format", "k8s-secret", "--kubeconfig", kubeconfig, "--apply", "--out", outputPath})
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "kind: Secret")
+	assert.Contains(t, string(content), "name: prod")
+	assert.Contains(t, string(content), "namespace: apps")
+	assert.Equal(t, "/api/v1/namespaces/apps/secrets/prod", appliedPath)
+	assert.Equal(t, string(content), appliedBody)
+
+	// --apply needs a Kubernetes format
+	cmd = NewRenderCommand(cfg)
+	cmd.SetArgs([]string{"--env", "Prod", "--apply"})
+	err = cmd.Execute()
+	assert.ErrorContains(t, err, "--apply needs")
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/systmms/dsops/internal/config"
 	dserrors "github.com/systmms/dsops/internal/errors"
-	"github.com/systmms/dsops/internal/rotation/notifications"
 	"github.com/systmms/dsops/internal/rotation/rollback"
 	"github.com/systmms/dsops/internal/rotation/storage"
 )
@@ -177,8 +176,7 @@ func executeRollback(cfg *config.Config, service, environment, version, reason s
 	fmt.Println("\nExecuting rollback...")
 
 	// Initialize notification manager (best-effort)
-	notifier := notifications.NewManager(0)
-	notifier.Start(context.Background())
+	notifier := startNotificationManager(context.Background(), cfg)
 	defer notifier.Stop()
 
 	// Initialize rollback manager
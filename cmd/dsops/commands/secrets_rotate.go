@@ -11,6 +11,7 @@ import (
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/internal/rotation/notifications"
 	"github.com/systmms/dsops/pkg/provider"
 	"github.com/systmms/dsops/pkg/rotation"
 )
@@ -140,25 +141,16 @@ func runSecretsRotate(cfg *config.Config, envName string, keys []string, strateg
 		return fmt.Errorf("failed to create providers: %w", err)
 	}
 
-	// Create rotation engine and strategy registry
-	rotationEngine := rotation.NewRotationEngine(logger)
-	strategyRegistry := rotation.NewStrategyRegistry(logger)
+	// Create rotation engine with all built-in strategies
+	rotationEngine := newBuiltinRotationEngine(cfg, providerInstances)
 
-	// Register available strategies
-	for _, strategyName := range strategyRegistry.ListStrategies() {
-		rotationStrategy, err := strategyRegistry.CreateStrategy(strategyName)
-		if err != nil {
-			logger.Warn("Failed to create strategy %s: %v", strategyName, err)
-			continue
-		}
-		if err := rotationEngine.RegisterStrategy(rotationStrategy); err != nil {
-			logger.Warn("Failed to register strategy %s: %v", strategyName, err)
-		}
-	}
+	ctx := context.Background()
+	notifier := startNotificationManager(ctx, cfg)
+	defer notifier.Stop()
+	rotationEngine.SetNotifier(notifier)
 
 	// Process each key
 	var rotationResults []rotation.RotationResult
-	ctx := context.Background()
 	for _, key := range keys {
 		result, err := rotateSecretValueWithEngine(ctx, env, key, strategy, newValueSpec, providerInstances, rotationEngine, logger, dryRun, force)
 		if err != nil {
@@ -317,6 +309,51 @@ func displayEngineRotationResults(results []rotation.RotationResult, logger *log
 	return nil
 }
 
+// newBuiltinRotationEngine creates a rotation engine with every built-in
+// strategy registered and the given providers available to provider-aware
+// strategies. When PagerDuty is configured under notifications in dsops.yaml,
+// the emergency strategy pages through it.
+func newBuiltinRotationEngine(cfg *config.Config, providerInstances map[string]provider.Provider) *rotation.DefaultRotationEngine {
+	logger := cfg.Logger
+	rotationEngine := rotation.NewRotationEngine(logger)
+	rotationEngine.SetProviders(providerInstances)
+	strategyRegistry := rotation.NewStrategyRegistry(logger)
+
+	for _, strategyName := range strategyRegistry.ListStrategies() {
+		rotationStrategy, err := strategyRegistry.CreateStrategy(strategyName)
+		if err != nil {
+			logger.Warn("Failed to create strategy %s: %v", strategyName, err)
+			continue
+		}
+		if err := rotationEngine.RegisterStrategy(rotationStrategy); err != nil {
+			logger.Warn("Failed to register strategy %s: %v", strategyName, err)
+		}
+	}
+
+	if cfg.Definition != nil && cfg.Definition.Notifications != nil && cfg.Definition.Notifications.PagerDuty != nil {
+		pd := cfg.Definition.Notifications.PagerDuty
+		severity := pd.Severity
+		if severity == "" {
+			severity = string(notifications.SeverityCritical)
+		}
+
+		pager, err := notifications.CreatePagerDutyProvider(&notifications.PagerDutyNotificationConfig{
+			IntegrationKey: pd.IntegrationKey,
+			ServiceID:      pd.ServiceID,
+			Severity:       severity,
+		})
+		if err != nil {
+			logger.Warn("PagerDuty not available for emergency rotation: %v", err)
+		} else if strategy, err := rotationEngine.GetStrategy(string(rotation.StrategyEmergency)); err == nil {
+			if emergency, ok := strategy.(*rotation.EmergencyRotator); ok {
+				emergency.SetPager(pager)
+			}
+		}
+	}
+
+	return rotationEngine
+}
+
 func getSecretsEnvNames(envs map[string]config.Environment) []string {
 	names := make([]string, 0, len(envs))
 	for name := range envs {
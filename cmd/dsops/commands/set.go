@@ -0,0 +1,346 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+func NewSetCommand(cfg *config.Config) *cobra.Command {
+	var (
+		store       string
+		key         string
+		envName     string
+		description string
+		tags        []string
+		meta        []string
+		yes         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Write a secret to a secret store",
+		Long: `Create or update a secret in a writable secret store.
+
+The value is read from stdin. When stdin is a terminal you are prompted
+twice and the input is not echoed. A single trailing newline is removed
+from piped input. The value is never printed or logged.
+
+Any store whose provider supports writing can be used; writing to a
+read-only store fails with the list of writable stores in dsops.yaml. A key
+with a field ("name#field" for Vault, "name#.field" for AWS, GCP and Azure)
+updates that field of a JSON secret and keeps the others.
+
+Writes are checked against the policies in dsops.yaml. --env selects the
+environment rules to apply: allowed and blocked providers, read_only, and
+require_approval, which asks for confirmation unless --yes is given. Without
+--env the rules of every environment that reads from the store apply.
+
+Examples:
+  # Prompt for the value
+  dsops set --store aws-prod --key myapp/stripe-key
+
+  # Pipe the value in
+  op read op://vault/item/password | dsops set --store vault --key secret/data/app#db_password
+
+  # Add a description, tags and store-specific metadata
+  dsops set --store aws-prod --key myapp/api-key --description "Partner API key" \
+    --tag team=payments --meta kms_key_id=alias/secrets < key.txt
+
+  # Apply production policies
+  dsops set --store vault --key secret/data/prod/app#token --env production --yes < token.txt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSet(cfg, store, key, envName, description, tags, meta, yes)
+		},
+	}
+
+	cmd.Flags().StringVar(&store, "store", "", "Secret store to write to (required)")
+	cmd.Flags().StringVar(&key, "key", "", "Secret key within the store (required)")
+	cmd.Flags().StringVar(&envName, "env", "", "Environment whose policy rules apply")
+	cmd.Flags().StringVar(&description, "description", "", "Description stored with the secret")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Tag to attach as key=value (repeatable)")
+	cmd.Flags().StringArrayVar(&meta, "meta", nil, "Store-specific setting as key=value, e.g. kms_key_id, type, tier, content_type (repeatable)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	_ = cmd.MarkFlagRequired("store")
+	_ = cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+func runSet(cfg *config.Config, store, key, envName, description string, tags, meta []string, yes bool) error {
+	tagMap, err := parseKeyValueFlags("tag", tags)
+	if err != nil {
+		return err
+	}
+	metaMap, err := parseKeyValueFlags("meta", meta)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	writer, envNames, err := openWriter(cfg, store, envName)
+	if err != nil {
+		return err
+	}
+
+	interactive := isTerminal(os.Stdin)
+	value, err := readSecretValue(os.Stdin, interactive)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.GetPolicyEnforcer().ValidateSecretValue(string(value)); err != nil {
+		return err
+	}
+
+	if approvalEnv := requiringApproval(cfg, envNames); approvalEnv != "" && !yes {
+		prompt := fmt.Sprintf("Environment '%s' requires approval. Write %s to %s?", approvalEnv, key, store)
+		confirmed, err := confirmWrite(cfg, prompt, interactive)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(os.Stderr, "Write cancelled")
+			return nil
+		}
+	}
+
+	version, err := writer.PutSecret(context.Background(), provider.Reference{Provider: store, Key: key}, value, provider.WriteOptions{
+		Description: description,
+		Tags:        tagMap,
+		Metadata:    metaMap,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s to %s: %w", key, store, err)
+	}
+
+	if version != "" {
+		fmt.Fprintf(os.Stderr, "✅ Wrote %s to %s (version %s)\n", key, store, version)
+	} else {
+		fmt.Fprintf(os.Stderr, "✅ Wrote %s to %s\n", key, store)
+	}
+
+	return nil
+}
+
+// openWriter creates the named store, checks write policies and ensures the
+// store is writable. It returns the environments whose rules were applied:
+// the one named by --env, or otherwise every environment with a variable
+// that reads from the store.
+func openWriter(cfg *config.Config, store, envName string) (provider.Writer, []string, error) {
+	providerConfig, err := cfg.GetProvider(store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envNames, err := policyEnvironments(cfg, store, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enforcer := cfg.GetPolicyEnforcer()
+	if err := enforcer.ValidateWrite("", providerConfig.Type); err != nil {
+		return nil, nil, err
+	}
+	for _, name := range envNames {
+		if err := enforcer.ValidateWrite(name, providerConfig.Type); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	registry := providers.NewRegistry()
+	p, err := registry.CreateProvider(store, providerConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create store '%s': %w", store, err)
+	}
+
+	writer, ok := p.(provider.Writer)
+	if !ok {
+		suggestion := "Write to a store whose provider supports writing secrets"
+		if writable := writableStores(cfg, registry); len(writable) > 0 {
+			suggestion = "Writable stores: " + strings.Join(writable, ", ")
+		}
+		return nil, nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' (%s) is read-only", store, providerConfig.Type),
+			Suggestion: suggestion,
+		}
+	}
+
+	return writer, envNames, nil
+}
+
+// writableStores returns the stores in dsops.yaml whose type accepts writes
+func writableStores(cfg *config.Config, registry *providers.Registry) []string {
+	var names []string
+	for name, storeConfig := range cfg.Definition.SecretStores {
+		if registry.IsWritable(storeConfig.Type) {
+			names = append(names, fmt.Sprintf("%s (%s)", name, storeConfig.Type))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// policyEnvironments returns the environments whose policy rules guard a
+// write to store. Without --env that is every environment with a variable,
+// fallbacks included, that reads from the store, so leaving out --env cannot
+// bypass read_only or require_approval.
+func policyEnvironments(cfg *config.Config, store, envName string) ([]string, error) {
+	if envName != "" {
+		if _, err := cfg.GetEnvironment(envName); err != nil {
+			return nil, err
+		}
+		return []string{envName}, nil
+	}
+
+	var envNames []string
+	for name, env := range cfg.Definition.Envs {
+		if environmentUsesStore(env, store) {
+			envNames = append(envNames, name)
+		}
+	}
+	sort.Strings(envNames)
+	return envNames, nil
+}
+
+// environmentUsesStore reports whether any variable in env reads from store
+func environmentUsesStore(env config.Environment, store string) bool {
+	for _, variable := range env {
+		for _, source := range variable.Sources() {
+			if !source.IsServiceReference() && source.GetEffectiveProvider() == store {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requiringApproval returns the first environment whose policy requires
+// approval for changes, or "" when none does
+func requiringApproval(cfg *config.Config, envNames []string) string {
+	for _, name := range envNames {
+		if cfg.GetPolicyEnforcer().RequiresApproval(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// readSecretValue reads a secret from r. On a terminal the value is read
+// twice without echo; otherwise all input is read and one trailing newline
+// is removed.
+func readSecretValue(r io.Reader, interactive bool) ([]byte, error) {
+	if interactive {
+		f, ok := r.(*os.File)
+		if !ok {
+			return nil, fmt.Errorf("interactive input requires a terminal")
+		}
+		fd := int(f.Fd())
+
+		fmt.Fprint(os.Stderr, "Enter value: ")
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value: %w", err)
+		}
+
+		fmt.Fprint(os.Stderr, "Confirm value: ")
+		confirm, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value: %w", err)
+		}
+
+		if !bytes.Equal(value, confirm) {
+			return nil, dserrors.UserError{
+				Message:    "Values do not match",
+				Suggestion: "Run the command again and enter the same value twice",
+			}
+		}
+		if len(value) == 0 {
+			return nil, dserrors.UserError{
+				Message:    "Secret value is empty",
+				Suggestion: "Enter a value, or use 'dsops delete' to remove a secret",
+			}
+		}
+		return value, nil
+	}
+
+	value, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read value from stdin: %w", err)
+	}
+
+	value = bytes.TrimSuffix(value, []byte("\n"))
+	value = bytes.TrimSuffix(value, []byte("\r"))
+
+	if len(value) == 0 {
+		return nil, dserrors.UserError{
+			Message:    "Secret value is empty",
+			Suggestion: "Pipe the value on stdin, e.g. 'dsops set --store <store> --key <key> < value.txt'",
+		}
+	}
+
+	return value, nil
+}
+
+// confirmWrite asks for a y/N confirmation on stdin. Prompting needs an
+// interactive terminal; otherwise the caller must pass --yes.
+func confirmWrite(cfg *config.Config, prompt string, interactive bool) (bool, error) {
+	if cfg.NonInteractive || !interactive {
+		return false, dserrors.UserError{
+			Message:    "This change requires confirmation",
+			Suggestion: "Re-run with --yes to confirm without prompting",
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", prompt)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// parseKeyValueFlags parses repeated key=value flag values
+func parseKeyValueFlags(flag string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]string, len(values))
+	for _, value := range values {
+		k, v, ok := strings.Cut(value, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Invalid --%s value: %q", flag, value),
+				Suggestion: fmt.Sprintf("Use --%s key=value", flag),
+			}
+		}
+		result[k] = v
+	}
+
+	return result, nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+)
+
+const writeTestConfig = `version: 0
+secretStores:
+  literal-store:
+    type: literal
+  vault-store:
+    type: vault
+    address: http://127.0.0.1:8200
+  prod-vault:
+    type: vault
+    address: http://127.0.0.1:8200
+  prod-fallback:
+    type: vault
+    address: http://127.0.0.1:8200
+  review-vault:
+    type: vault
+    address: http://127.0.0.1:8200
+policies:
+  environment_rules:
+    production:
+      read_only: true
+    staging:
+      allowed_providers: [aws.secretsmanager]
+    review:
+      require_approval: true
+envs:
+  production:
+    DB_PASSWORD:
+      from:
+        - { provider: prod-vault, key: secret/data/prod#db }
+        - { provider: prod-fallback, key: secret/data/fallback#db }
+  staging: {}
+  review:
+    API_TOKEN:
+      from: { provider: review-vault, key: secret/data/review#token }
+`
+
+func loadWriteTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "dsops.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(writeTestConfig), 0600))
+
+	cfg := &config.Config{Path: path, Logger: logging.New(false, true), NonInteractive: true}
+	require.NoError(t, cfg.Load())
+	return cfg
+}
+
+func TestNewSetCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewSetCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "set", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	for _, flag := range []string{"store", "key", "env", "description", "tag", "meta", "yes"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+}
+
+func TestOpenWriter(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadWriteTestConfig(t)
+
+	t.Run("writable_store", func(t *testing.T) {
+		writer, envNames, err := openWriter(cfg, "vault-store", "")
+		require.NoError(t, err)
+		assert.NotNil(t, writer)
+		assert.Empty(t, envNames, "no environment reads from vault-store")
+	})
+
+	t.Run("read_only_store_type", func(t *testing.T) {
+		_, _, err := openWriter(cfg, "literal-store", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is read-only")
+		assert.Contains(t, err.Error(), "Writable stores: prod-fallback (vault), prod-vault (vault), review-vault (vault), vault-store (vault)")
+	})
+
+	t.Run("unknown_store", func(t *testing.T) {
+		_, _, err := openWriter(cfg, "missing", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("read_only_environment", func(t *testing.T) {
+		_, _, err := openWriter(cfg, "vault-store", "production")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only by policy")
+	})
+
+	t.Run("provider_not_allowed_for_environment", func(t *testing.T) {
+		_, _, err := openWriter(cfg, "vault-store", "staging")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not allowed for environment 'staging'")
+	})
+
+	t.Run("unknown_environment", func(t *testing.T) {
+		_, _, err := openWriter(cfg, "vault-store", "nope")
+		assert.Error(t, err)
+	})
+
+	t.Run("without_env_applies_environments_using_the_store", func(t *testing.T) {
+		for _, store := range []string{"prod-vault", "prod-fallback"} {
+			_, _, err := openWriter(cfg, store, "")
+			require.Error(t, err, store)
+			assert.Contains(t, err.Error(), "'production' is read-only by policy")
+		}
+
+		_, envNames, err := openWriter(cfg, "review-vault", "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"review"}, envNames)
+		assert.Equal(t, "review", requiringApproval(cfg, envNames))
+	})
+}
+
+func TestReadSecretValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := readSecretValue(strings.NewReader("s3cr3t\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(value))
+
+	value, err = readSecretValue(strings.NewReader("line1\nline2\r\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "line1\nline2", string(value))
+
+	value, err = readSecretValue(strings.NewReader("keep\n\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "keep\n", string(value), "only one trailing newline is removed")
+
+	_, err = readSecretValue(strings.NewReader("\n"), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty")
+
+	_, err = readSecretValue(strings.NewReader("x"), true)
+	assert.Error(t, err, "interactive input needs a terminal")
+}
+
+func TestConfirmWrite_RequiresYesWithoutTerminal(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Logger: logging.New(false, true)}
+	_, err := confirmWrite(cfg, "Write?", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires confirmation")
+
+	cfg.NonInteractive = true
+	_, err = confirmWrite(cfg, "Write?", true)
+	assert.Error(t, err)
+}
+
+func TestParseKeyValueFlags(t *testing.T) {
+	t.Parallel()
+
+	result, err := parseKeyValueFlags("tag", []string{"team=payments", "note=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "note": "a=b", "empty": ""}, result)
+
+	result, err = parseKeyValueFlags("tag", nil)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = parseKeyValueFlags("meta", []string{"novalue"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--meta")
+
+	_, err = parseKeyValueFlags("tag", []string{"=value"})
+	assert.Error(t, err)
+}
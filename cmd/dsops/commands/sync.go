@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/internal/resolve"
+	"github.com/systmms/dsops/internal/secretsync"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+type syncOptions struct {
+	from       string
+	to         string
+	keys       []string
+	include    []string
+	exclude    []string
+	keyCase    string
+	onConflict string
+	envName    string
+	dryRun     bool
+	yes        bool
+	format     string
+}
+
+// syncPlanOutput is the JSON form of a sync plan
+type syncPlanOutput struct {
+	Name       string            `json:"name"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	OnConflict string            `json:"on_conflict"`
+	Items      []secretsync.Item `json:"items"`
+	Summary    map[string]int    `json:"summary"`
+}
+
+func NewSyncCommand(cfg *config.Config) *cobra.Command {
+	var opts syncOptions
+
+	cmd := &cobra.Command{
+		Use:   "sync [job...]",
+		Short: "Copy secrets from one secret store to another",
+		Long: `Copy secrets between secret stores, for migrations and mirroring.
+
+Jobs are defined in the 'sync:' section of dsops.yaml. Without arguments all
+jobs run; name jobs to run only those. --from and --to run a one-off sync
+without a config entry.
+
+Each run first plans the sync by reading both stores and comparing value
+fingerprints. The plan shows keys, versions and fingerprints only, never
+values. Unchanged secrets are not rewritten, so re-running a sync is safe.
+
+When the destination already holds a different value, on_conflict decides:
+fail (default) refuses to write anything, skip leaves the destination as is,
+and overwrite replaces it.
+
+Example dsops.yaml:
+  sync:
+    doppler-to-aws:
+      from: doppler
+      to: aws-prod/myapp/
+      exclude: ["DOPPLER_*"]
+      key_case: kebab
+      map:
+        DATABASE_URL: db/url
+      on_conflict: overwrite
+    vault-dr:
+      from: vault/secret/data/app/
+      to: gcp-dr/app-
+      include: ["db*", "api*"]
+
+Examples:
+  # Show what every job would change
+  dsops sync --dry-run
+
+  # Run one job without prompting
+  dsops sync doppler-to-aws --yes
+
+  # One-off sync
+  dsops sync --from doppler --to aws-prod/myapp/ --key-case kebab --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(cfg, args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.from, "from", "", "Source as store[/prefix] for a one-off sync")
+	cmd.Flags().StringVar(&opts.to, "to", "", "Destination as store[/prefix] for a one-off sync")
+	cmd.Flags().StringArrayVar(&opts.keys, "key", nil, "Source key to sync (repeatable, one-off sync only)")
+	cmd.Flags().StringArrayVar(&opts.include, "include", nil, "Glob of source keys to include (repeatable, one-off sync only)")
+	cmd.Flags().StringArrayVar(&opts.exclude, "exclude", nil, "Glob of source keys to exclude (repeatable, one-off sync only)")
+	cmd.Flags().StringVar(&opts.keyCase, "key-case", "", "Destination key case: upper, lower, kebab, snake (one-off sync only)")
+	cmd.Flags().StringVar(&opts.onConflict, "on-conflict", "", "Override the conflict policy: fail, skip, overwrite")
+	cmd.Flags().StringVar(&opts.envName, "env", "", "Environment whose write policies apply")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the plan without writing")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Apply without prompting")
+	cmd.Flags().StringVar(&opts.format, "format", "table", "Plan output format: table, json")
+
+	return cmd
+}
+
+func runSync(cfg *config.Config, args []string, opts syncOptions) error {
+	if opts.format != "table" && opts.format != "json" {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unsupported format: %s", opts.format),
+			Suggestion: "Use --format table or --format json",
+		}
+	}
+
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+
+	jobs, err := selectSyncJobs(cfg, args, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var plans []syncPlanOutput
+
+	for _, job := range jobs {
+		syncer, err := newSyncer(cfg, job)
+		if err != nil {
+			return fmt.Errorf("sync '%s': %w", job.Name, err)
+		}
+
+		plan, err := syncer.Plan(ctx)
+		if err != nil {
+			return fmt.Errorf("sync '%s': %w", job.Name, err)
+		}
+
+		if opts.format == "json" {
+			plans = append(plans, syncPlanToOutput(plan))
+		} else {
+			outputSyncPlanTable(os.Stdout, plan)
+		}
+
+		if opts.dryRun || !plan.HasChanges() {
+			continue
+		}
+
+		if !opts.yes {
+			n := plan.Count(secretsync.ActionCreate) + plan.Count(secretsync.ActionUpdate)
+			prompt := fmt.Sprintf("Write %d secret(s) to %s?", n, job.To)
+			confirmed, err := confirmWrite(cfg, prompt, isTerminal(os.Stdin))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintf(os.Stderr, "Sync '%s' cancelled\n", job.Name)
+				continue
+			}
+		}
+
+		result, err := syncer.Apply(ctx, plan)
+		if err != nil {
+			if result.Created+result.Updated > 0 {
+				fmt.Fprintf(os.Stderr, "⚠️  Sync '%s' stopped after writing %d secret(s); re-run to continue\n",
+					job.Name, result.Created+result.Updated)
+			}
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "✅ Sync '%s': %d created, %d updated\n", job.Name, result.Created, result.Updated)
+	}
+
+	if opts.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plans)
+	}
+
+	return nil
+}
+
+// selectSyncJobs returns the one-off job described by flags, or the named
+// (or all) jobs from the sync section
+func selectSyncJobs(cfg *config.Config, args []string, opts syncOptions) ([]secretsync.Job, error) {
+	var jobs []secretsync.Job
+
+	if opts.from != "" || opts.to != "" {
+		if len(args) > 0 {
+			return nil, dserrors.UserError{
+				Message:    "Cannot combine job names with --from/--to",
+				Suggestion: "Either name jobs from the 'sync:' section or use --from and --to",
+			}
+		}
+
+		job, err := secretsync.JobFromConfig("cli", config.SyncConfig{
+			From:       opts.from,
+			To:         opts.to,
+			Keys:       opts.keys,
+			Include:    opts.include,
+			Exclude:    opts.exclude,
+			KeyCase:    opts.keyCase,
+			OnConflict: opts.onConflict,
+			Env:        opts.envName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []secretsync.Job{job}, nil
+	}
+
+	if len(opts.keys) > 0 || len(opts.include) > 0 || len(opts.exclude) > 0 || opts.keyCase != "" {
+		return nil, dserrors.UserError{
+			Message:    "--key, --include, --exclude and --key-case only apply to one-off syncs",
+			Suggestion: "Use them with --from and --to, or set them in the 'sync:' section",
+		}
+	}
+
+	defined := cfg.Definition.Sync
+	if len(defined) == 0 {
+		return nil, dserrors.ConfigError{
+			Field:      "sync",
+			Message:    "no sync jobs defined",
+			Suggestion: "Add a 'sync:' section to dsops.yaml, or use --from and --to",
+		}
+	}
+
+	names := args
+	if len(names) == 0 {
+		for name := range defined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		sc, ok := defined[name]
+		if !ok {
+			var available []string
+			for n := range defined {
+				available = append(available, n)
+			}
+			sort.Strings(available)
+			return nil, dserrors.ConfigError{
+				Field:      "sync",
+				Value:      name,
+				Message:    "sync job not found",
+				Suggestion: fmt.Sprintf("Available sync jobs: %s", strings.Join(available, ", ")),
+			}
+		}
+
+		if opts.onConflict != "" {
+			sc.OnConflict = opts.onConflict
+		}
+		if opts.envName != "" {
+			sc.Env = opts.envName
+		}
+
+		job, err := secretsync.JobFromConfig(name, sc)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// newSyncer opens both stores of a job and registers them with a resolver
+func newSyncer(cfg *config.Config, job secretsync.Job) (*secretsync.Syncer, error) {
+	writer, _, err := openWriter(cfg, job.To.Store, job.Env)
+	if err != nil {
+		return nil, err
+	}
+	dest, ok := writer.(provider.Provider)
+	if !ok {
+		return nil, fmt.Errorf("store '%s' is not a provider", job.To.Store)
+	}
+
+	resolver := resolve.New(cfg)
+	resolver.RegisterProvider(job.To.Store, dest)
+
+	if job.From.Store != job.To.Store {
+		sourceConfig, err := cfg.GetProvider(job.From.Store)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.GetPolicyEnforcer().ValidateProviderType(sourceConfig.Type); err != nil {
+			return nil, err
+		}
+		source, err := providers.NewRegistry().CreateProvider(job.From.Store, sourceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store '%s': %w", job.From.Store, err)
+		}
+		resolver.RegisterProvider(job.From.Store, source)
+	}
+
+	return secretsync.New(resolver, job, cfg.GetPolicyEnforcer())
+}
+
+func syncPlanToOutput(plan *secretsync.Plan) syncPlanOutput {
+	items := plan.Items
+	if items == nil {
+		items = []secretsync.Item{}
+	}
+
+	summary := make(map[string]int)
+	for _, action := range []secretsync.Action{
+		secretsync.ActionCreate, secretsync.ActionUpdate, secretsync.ActionUnchanged,
+		secretsync.ActionSkip, secretsync.ActionConflict,
+	} {
+		summary[string(action)] = plan.Count(action)
+	}
+
+	return syncPlanOutput{
+		Name:       plan.Job.Name,
+		From:       plan.Job.From.String(),
+		To:         plan.Job.To.String(),
+		OnConflict: string(plan.Job.OnConflict),
+		Items:      items,
+		Summary:    summary,
+	}
+}
+
+func outputSyncPlanTable(out io.Writer, plan *secretsync.Plan) {
+	_, _ = fmt.Fprintf(out, "Sync '%s': %s → %s (on_conflict: %s)\n\n",
+		plan.Job.Name, plan.Job.From, plan.Job.To, plan.Job.OnConflict)
+
+	if len(plan.Items) == 0 {
+		_, _ = fmt.Fprintln(out, "No secrets matched")
+		_, _ = fmt.Fprintln(out)
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ACTION\tSOURCE\tDESTINATION\tVERSION\tFINGERPRINT")
+	for _, item := range plan.Items {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			item.Action,
+			item.SourceKey,
+			item.DestinationKey,
+			syncChange(shortVersion(item.DestVersion), shortVersion(item.SourceVersion)),
+			syncChange(item.DestFingerprint, item.SourceFingerprint),
+		)
+	}
+	_ = w.Flush()
+
+	_, _ = fmt.Fprintf(out, "\n%s\n\n", plan.Summary())
+}
+
+// syncChange shows a destination → source transition, or a single value
+// when they match or the destination is missing
+func syncChange(dest, source string) string {
+	switch {
+	case dest == "" && source == "":
+		return "-"
+	case dest == "" || dest == source:
+		return valueOrDash(source)
+	default:
+		return dest + " → " + valueOrDash(source)
+	}
+}
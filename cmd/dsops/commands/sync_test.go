@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/secretsync"
+)
+
+const syncTestConfig = `version: 0
+secretStores:
+  doppler:
+    type: doppler
+    token: dp.st.dev.test
+    project: myapp
+    config: dev
+  aws-prod:
+    type: aws.secretsmanager
+    region: us-east-1
+sync:
+  doppler-to-aws:
+    from: doppler
+    to: aws-prod/myapp/
+    key_case: kebab
+  mirror:
+    from: aws-prod/myapp/
+    to: aws-prod/backup/
+    on_conflict: skip
+envs: {}
+`
+
+func loadSyncTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "dsops.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(syncTestConfig), 0600))
+
+	cfg := &config.Config{Path: path, Logger: logging.New(false, true), NonInteractive: true}
+	require.NoError(t, cfg.Load())
+	return cfg
+}
+
+func TestNewSyncCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := NewSyncCommand(&config.Config{Logger: logging.New(false, true)})
+
+	assert.Equal(t, "sync [job...]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	for _, flag := range []string{"from", "to", "key", "include", "exclude", "key-case", "on-conflict", "env", "dry-run", "yes", "format"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "flag %s should exist", flag)
+	}
+}
+
+func TestSelectSyncJobs(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadSyncTestConfig(t)
+
+	t.Run("all_jobs_sorted", func(t *testing.T) {
+		jobs, err := selectSyncJobs(cfg, nil, syncOptions{})
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		assert.Equal(t, "doppler-to-aws", jobs[0].Name)
+		assert.Equal(t, "mirror", jobs[1].Name)
+		assert.Equal(t, secretsync.ConflictFail, jobs[0].OnConflict)
+	})
+
+	t.Run("named_job_with_override", func(t *testing.T) {
+		jobs, err := selectSyncJobs(cfg, []string{"mirror"}, syncOptions{onConflict: "overwrite"})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, secretsync.ConflictOverwrite, jobs[0].OnConflict)
+		assert.Equal(t, secretsync.Endpoint{Store: "aws-prod", Prefix: "backup/"}, jobs[0].To)
+	})
+
+	t.Run("unknown_job", func(t *testing.T) {
+		_, err := selectSyncJobs(cfg, []string{"nope"}, syncOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "doppler-to-aws, mirror")
+	})
+
+	t.Run("one_off", func(t *testing.T) {
+		jobs, err := selectSyncJobs(cfg, nil, syncOptions{from: "doppler", to: "aws-prod", keys: []string{"API_KEY"}})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, []string{"API_KEY"}, jobs[0].Keys)
+	})
+
+	t.Run("one_off_with_job_names", func(t *testing.T) {
+		_, err := selectSyncJobs(cfg, []string{"mirror"}, syncOptions{from: "doppler", to: "aws-prod"})
+		assert.Error(t, err)
+	})
+
+	t.Run("one_off_flags_without_from", func(t *testing.T) {
+		_, err := selectSyncJobs(cfg, nil, syncOptions{keyCase: "upper"})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewSyncer_RequiresWritableDestination(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadSyncTestConfig(t)
+	job, err := secretsync.JobFromConfig("reverse", config.SyncConfig{From: "aws-prod", To: "doppler"})
+	require.NoError(t, err)
+
+	_, err = newSyncer(cfg, job)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is read-only")
+}
+
+func TestOutputSyncPlanTable(t *testing.T) {
+	t.Parallel()
+
+	job, err := secretsync.JobFromConfig("job", config.SyncConfig{From: "doppler", To: "aws/app/"})
+	require.NoError(t, err)
+
+	plan := &secretsync.Plan{Job: job, Items: []secretsync.Item{
+		{SourceKey: "A", DestinationKey: "app/a", Action: secretsync.ActionCreate, SourceFingerprint: "sha256:aaaa"},
+		{SourceKey: "B", DestinationKey: "app/b", Action: secretsync.ActionConflict,
+			SourceFingerprint: "sha256:bbbb", DestFingerprint: "sha256:cccc", DestVersion: "v1"},
+	}}
+
+	var out bytes.Buffer
+	outputSyncPlanTable(&out, plan)
+	assert.Contains(t, out.String(), "doppler → aws/app/")
+	assert.Contains(t, out.String(), "sha256:cccc → sha256:bbbb")
+	assert.Contains(t, out.String(), "1 to create, 0 to update, 0 unchanged, 0 skipped, 1 conflicts")
+
+	output := syncPlanToOutput(plan)
+	assert.Equal(t, 1, output.Summary["conflict"])
+	assert.Equal(t, "aws/app/", output.To)
+}
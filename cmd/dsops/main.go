@@ -64,9 +64,17 @@ launches commands with ephemeral environment variables.`,
 		commands.NewRenderCommand(cfg),
 		commands.NewExecCommand(cfg),
 		commands.NewGetCommand(cfg),
+		commands.NewSetCommand(cfg),
+		commands.NewDeleteCommand(cfg),
+		commands.NewLsCommand(cfg),
+		commands.NewSyncCommand(cfg),
+		commands.NewDriftCommand(cfg),
+		commands.NewCertsCommand(cfg),
+		commands.NewKeychainCommand(cfg),
 		commands.NewDoctorCommand(cfg),
 		commands.NewProvidersCommand(cfg),
 		commands.NewLoginCommand(cfg),
+		commands.NewAWSCommand(cfg),
 		commands.NewShredCommand(cfg),
 		commands.NewGuardCommand(cfg),
 		commands.NewInstallHookCommand(cfg),
@@ -0,0 +1,120 @@
+// Package agekey finds the age identities that decrypt local secret files.
+// The sops store and file stores with decrypt: age share it, so a key set up
+// for one works for the other.
+package agekey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// KeychainLookup reads a secret from the OS keychain by "service/account"
+type KeychainLookup func(ctx context.Context, ref string) (string, error)
+
+// Sources are the store settings that point at age identities
+type Sources struct {
+	KeyFile  string         // age_key_file
+	Keychain string         // age_key_keychain, as service/account
+	Lookup   KeychainLookup // Reads Keychain; may be nil when no keychain is available
+}
+
+// Load collects the age identities from every source that is configured or
+// present, in this order:
+//
+//  1. age_key_file in the store config
+//  2. SOPS_AGE_KEY (the key itself) and SOPS_AGE_KEY_FILE
+//  3. age_key_keychain, a "service/account" keychain item
+//  4. sops' default key file, <user config dir>/sops/age/keys.txt
+//
+// It also returns the names of the sources that contributed identities.
+func Load(ctx context.Context, src Sources) ([]age.Identity, []string, error) {
+	var (
+		identities []age.Identity
+		sources    []string
+	)
+
+	add := func(source, keys string) error {
+		parsed, err := age.ParseIdentities(strings.NewReader(keys))
+		if err != nil {
+			return fmt.Errorf("invalid age identities in %s: %w", source, err)
+		}
+		identities = append(identities, parsed...)
+		sources = append(sources, source)
+		return nil
+	}
+	addFile := func(source, path string, required bool) error {
+		data, err := os.ReadFile(expandHome(path))
+		if err != nil {
+			if !required && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("failed to read age key file %s: %w", path, err)
+		}
+		return add(source, string(data))
+	}
+
+	if src.KeyFile != "" {
+		if err := addFile("age_key_file", src.KeyFile, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	if keys := os.Getenv("SOPS_AGE_KEY"); keys != "" {
+		if err := add("SOPS_AGE_KEY", keys); err != nil {
+			return nil, nil, err
+		}
+	}
+	if path := os.Getenv("SOPS_AGE_KEY_FILE"); path != "" {
+		if err := addFile("SOPS_AGE_KEY_FILE", path, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	if src.Keychain != "" {
+		if src.Lookup == nil {
+			return nil, nil, fmt.Errorf("age_key_keychain is set but no keychain is available")
+		}
+		keys, err := src.Lookup(ctx, src.Keychain)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read age key from keychain item %s: %w", src.Keychain, err)
+		}
+		if err := add("keychain item "+src.Keychain, keys); err != nil {
+			return nil, nil, err
+		}
+	}
+	if path := DefaultKeyFile(); path != "" {
+		if err := addFile(path, path, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(identities) == 0 {
+		return nil, nil, fmt.Errorf("no age identities found")
+	}
+	return identities, sources, nil
+}
+
+// DefaultKeyFile returns the key file sops uses when nothing is configured
+func DefaultKeyFile() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "sops", "age", "keys.txt")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sops", "age", "keys.txt")
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
@@ -0,0 +1,73 @@
+package agekey
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	return identity
+}
+
+func writeKeyFile(t *testing.T, dir string, identity *age.X25519Identity) string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	path := filepath.Join(dir, "keys.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# test key\n"+identity.String()+"\n"), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("SOPS_AGE_KEY", newIdentity(t).String())
+	t.Setenv("SOPS_AGE_KEY_FILE", writeKeyFile(t, t.TempDir(), newIdentity(t)))
+	defaultFile := writeKeyFile(t, filepath.Join(configHome, "sops", "age"), newIdentity(t))
+
+	identities, sources, err := Load(context.Background(), Sources{
+		KeyFile:  writeKeyFile(t, t.TempDir(), newIdentity(t)),
+		Keychain: "dsops/age-key",
+		Lookup: func(ctx context.Context, ref string) (string, error) {
+			return newIdentity(t).String(), nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, identities, 5)
+	assert.Equal(t, []string{
+		"age_key_file",
+		"SOPS_AGE_KEY",
+		"SOPS_AGE_KEY_FILE",
+		"keychain item dsops/age-key",
+		defaultFile,
+	}, sources)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SOPS_AGE_KEY", "")
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+	ctx := context.Background()
+
+	_, _, err := Load(ctx, Sources{})
+	assert.EqualError(t, err, "no age identities found", "the default key file is optional")
+
+	_, _, err = Load(ctx, Sources{KeyFile: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.ErrorContains(t, err, "failed to read age key file")
+
+	_, _, err = Load(ctx, Sources{Keychain: "dsops/age-key"})
+	assert.ErrorContains(t, err, "no keychain is available")
+
+	_, _, err = Load(ctx, Sources{Keychain: "dsops/age-key", Lookup: func(ctx context.Context, ref string) (string, error) {
+		return "not a key", nil
+	}})
+	assert.ErrorContains(t, err, "invalid age identities in keychain item dsops/age-key")
+}
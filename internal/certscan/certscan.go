@@ -0,0 +1,417 @@
+// Package certscan inspects resolved secret values for X.509 certificates
+// and reports how close they are to expiry.
+//
+// A value is recognised as a certificate when it holds PEM certificate
+// blocks, base64 DER certificates, or a base64 PKCS#12 bundle. PKCS#12
+// passwords come from another variable in the same environment, named by the
+// variable's pkcs12_password metadata. Values that are not certificates are
+// skipped.
+package certscan
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/resolve"
+	"github.com/systmms/dsops/internal/rotation/notifications"
+	"github.com/systmms/dsops/pkg/protocol"
+)
+
+// PasswordMetadataKey is the variable metadata key naming the variable that
+// holds a PKCS#12 bundle's password
+const PasswordMetadataKey = "pkcs12_password"
+
+// Default expiry thresholds in days
+const (
+	DefaultWarnDays     = 30
+	DefaultCriticalDays = 7
+)
+
+// Level classifies a certificate by time to expiry
+type Level string
+
+const (
+	LevelOK       Level = "ok"
+	LevelWarning  Level = "warning"
+	LevelCritical Level = "critical"
+	LevelExpired  Level = "expired"
+	LevelError    Level = "error"
+)
+
+// levelOrder sorts findings with the most urgent first
+var levelOrder = map[Level]int{
+	LevelExpired:  0,
+	LevelCritical: 1,
+	LevelError:    2,
+	LevelWarning:  3,
+	LevelOK:       4,
+}
+
+// AtLeast reports whether l is as urgent as threshold. Errors are not on
+// the expiry scale and never reach a threshold.
+func (l Level) AtLeast(threshold Level) bool {
+	if l == LevelError || threshold == LevelError {
+		return false
+	}
+	return levelOrder[l] <= levelOrder[threshold]
+}
+
+// Format is the encoding a certificate was found in
+type Format string
+
+const (
+	FormatPEM    Format = "pem"
+	FormatDER    Format = "der"
+	FormatPKCS12 Format = "pkcs12"
+)
+
+// Finding is one certificate found in a variable, or a variable that could
+// not be inspected
+type Finding struct {
+	Env      string `json:"env"`
+	Variable string `json:"variable"`
+	Source   string `json:"source,omitempty"`
+	Format   Format `json:"format,omitempty"`
+	// Position is the certificate's index within the value; 0 is the first
+	Position     int                       `json:"position"`
+	Certificate  *protocol.CertificateInfo `json:"certificate,omitempty"`
+	DaysToExpiry int                       `json:"days_to_expiry"`
+	Level        Level                     `json:"level"`
+	Message      string                    `json:"message,omitempty"`
+}
+
+// Report is the result of a certificate scan
+type Report struct {
+	// Scanned is the number of variables inspected
+	Scanned  int       `json:"scanned"`
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings at the given level
+func (r *Report) Count(level Level) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// Certificates returns the number of certificates found
+func (r *Report) Certificates() int {
+	return len(r.Findings) - r.Count(LevelError)
+}
+
+// Options sets the expiry thresholds of a scan
+type Options struct {
+	// WarnDays is the number of days before expiry a certificate is reported
+	// as a warning
+	WarnDays int
+
+	// CriticalDays is the number of days before expiry a certificate is
+	// reported as critical
+	CriticalDays int
+
+	// Now is the reference time for expiry. Zero means time.Now().
+	Now time.Time
+}
+
+// Scanner inspects resolved environments for certificates
+type Scanner struct {
+	opts   Options
+	report *Report
+}
+
+// NewScanner creates a Scanner with the given thresholds
+func NewScanner(opts Options) *Scanner {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return &Scanner{opts: opts, report: &Report{Findings: []Finding{}}}
+}
+
+// ScanEnv inspects the resolved variables of one environment. env supplies
+// the variable definitions for PKCS#12 password lookups.
+func (s *Scanner) ScanEnv(envName string, env config.Environment, resolved map[string]resolve.ResolvedVariable) {
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variable := resolved[name]
+		s.report.Scanned++
+
+		base := Finding{Env: envName, Variable: name, Source: variable.Source}
+		if variable.Error != nil {
+			// An optional variable that does not resolve has nothing to inspect
+			if env[name].Optional {
+				continue
+			}
+			base.Level = LevelError
+			base.Message = fmt.Sprintf("could not be resolved: %v", variable.Error)
+			s.report.Findings = append(s.report.Findings, base)
+			continue
+		}
+
+		password, err := pkcs12Password(name, env, resolved)
+		if err != nil {
+			base.Level = LevelError
+			base.Message = err.Error()
+			s.report.Findings = append(s.report.Findings, base)
+			continue
+		}
+
+		certs, format, err := parseCertificates(variable.Value, password)
+		if err != nil {
+			base.Level = LevelError
+			base.Message = err.Error()
+			s.report.Findings = append(s.report.Findings, base)
+			continue
+		}
+
+		for i, cert := range certs {
+			f := base
+			f.Format = format
+			f.Position = i
+			f.Certificate, f.DaysToExpiry, f.Level = s.inspect(cert)
+			s.report.Findings = append(s.report.Findings, f)
+		}
+	}
+}
+
+// Report returns the findings of every scanned environment, most urgent first
+func (s *Scanner) Report() *Report {
+	sort.SliceStable(s.report.Findings, func(i, j int) bool {
+		a, b := s.report.Findings[i], s.report.Findings[j]
+		if levelOrder[a.Level] != levelOrder[b.Level] {
+			return levelOrder[a.Level] < levelOrder[b.Level]
+		}
+		if a.DaysToExpiry != b.DaysToExpiry {
+			return a.DaysToExpiry < b.DaysToExpiry
+		}
+		if a.Env != b.Env {
+			return a.Env < b.Env
+		}
+		if a.Variable != b.Variable {
+			return a.Variable < b.Variable
+		}
+		return a.Position < b.Position
+	})
+	return s.report
+}
+
+// inspect summarises a certificate and classifies it against the thresholds
+func (s *Scanner) inspect(cert *x509.Certificate) (*protocol.CertificateInfo, int, Level) {
+	remaining := cert.NotAfter.Sub(s.opts.Now)
+	days := int(math.Floor(remaining.Hours() / 24))
+
+	level := LevelOK
+	switch {
+	case remaining <= 0:
+		level = LevelExpired
+	case remaining < time.Duration(s.opts.CriticalDays)*24*time.Hour:
+		level = LevelCritical
+	case remaining < time.Duration(s.opts.WarnDays)*24*time.Hour:
+		level = LevelWarning
+	}
+
+	info := &protocol.CertificateInfo{
+		SerialNumber: cert.SerialNumber.String(),
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		DNSNames:     cert.DNSNames,
+		SANs:         subjectAltNames(cert),
+		Status:       string(level),
+	}
+	return info, days, level
+}
+
+// subjectAltNames lists every SAN of a certificate with an OpenSSL-style
+// type prefix, so IP, email and URI names are reported next to DNS names
+func subjectAltNames(cert *x509.Certificate) []string {
+	var sans []string
+	for _, name := range cert.DNSNames {
+		sans = append(sans, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, "IP:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, "URI:"+uri.String())
+	}
+	return sans
+}
+
+// pkcs12Password returns the password named by a variable's pkcs12_password
+// metadata, or nil when the variable has none
+func pkcs12Password(name string, env config.Environment, resolved map[string]resolve.ResolvedVariable) (*string, error) {
+	passwordVar := env[name].Metadata[PasswordMetadataKey]
+	if passwordVar == "" {
+		return nil, nil
+	}
+
+	password, ok := resolved[passwordVar]
+	if !ok {
+		return nil, fmt.Errorf("%s names %s, which is not a variable in this environment", PasswordMetadataKey, passwordVar)
+	}
+	if password.Error != nil {
+		return nil, fmt.Errorf("PKCS#12 password variable %s could not be resolved: %v", passwordVar, password.Error)
+	}
+	return &password.Value, nil
+}
+
+// parseCertificates extracts the certificates from a value. It returns no
+// certificates and no error when the value does not hold any. A non-nil
+// password means the value must be a PKCS#12 bundle.
+func parseCertificates(value string, password *string) ([]*x509.Certificate, Format, error) {
+	trimmed := strings.TrimSpace(value)
+
+	if password == nil && strings.Contains(trimmed, "-----BEGIN ") {
+		certs, err := parsePEM([]byte(trimmed))
+		return certs, FormatPEM, err
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(trimmed), ""))
+	if err != nil || len(der) == 0 {
+		if password != nil {
+			return nil, "", fmt.Errorf("PKCS#12 bundle is not valid base64")
+		}
+		return nil, "", nil
+	}
+
+	if password != nil {
+		certs, err := parsePKCS12(der, *password)
+		if err != nil {
+			return nil, "", err
+		}
+		return certs, FormatPKCS12, nil
+	}
+
+	// Both DER certificates and PKCS#12 bundles are ASN.1 sequences
+	if der[0] != 0x30 {
+		return nil, "", nil
+	}
+	if certs, err := x509.ParseCertificates(der); err == nil && len(certs) > 0 {
+		return certs, FormatDER, nil
+	}
+	// Bundles without a password are common; anything else is not a certificate
+	if certs, err := parsePKCS12(der, ""); err == nil {
+		return certs, FormatPKCS12, nil
+	}
+	return nil, "", nil
+}
+
+// parsePEM parses every CERTIFICATE block, ignoring keys and other blocks
+func parsePEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PEM certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 && bytes.Contains(data, []byte("-----BEGIN CERTIFICATE-----")) {
+		return nil, fmt.Errorf("malformed PEM certificate block")
+	}
+	return certs, nil
+}
+
+// parsePKCS12 returns the certificates in a PKCS#12 bundle, leaf first.
+// Bundles without a private key are read as trust stores.
+func parsePKCS12(der []byte, password string) ([]*x509.Certificate, error) {
+	_, leaf, chain, err := pkcs12.DecodeChain(der, password)
+	if err == nil {
+		return append([]*x509.Certificate{leaf}, chain...), nil
+	}
+	if certs, tsErr := pkcs12.DecodeTrustStore(der, password); tsErr == nil && len(certs) > 0 {
+		return certs, nil
+	}
+
+	var unsupported pkcs12.NotImplementedError
+	if errors.As(err, &unsupported) {
+		return nil, fmt.Errorf("PKCS#12 bundle uses an unsupported algorithm: %w", err)
+	}
+	return nil, fmt.Errorf("failed to decode PKCS#12 bundle: %w", err)
+}
+
+// Events returns one certificate_expiring event per variable whose most
+// urgent certificate is at or past minLevel
+func Events(report *Report, minLevel Level, now time.Time) []notifications.RotationEvent {
+	seen := make(map[string]bool)
+	var events []notifications.RotationEvent
+
+	// Findings are sorted most urgent first, so the first one per variable wins
+	for _, f := range report.Findings {
+		if f.Certificate == nil || !f.Level.AtLeast(minLevel) {
+			continue
+		}
+		id := f.Env + "\x00" + f.Variable
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		events = append(events, notifications.RotationEvent{
+			Type:        notifications.EventTypeCertificateExpiring,
+			Service:     f.Variable,
+			Environment: f.Env,
+			Status:      notifications.StatusFailure,
+			Severity:    string(f.Level),
+			Summary:     f.Summary(),
+			Timestamp:   now,
+			Metadata: map[string]string{
+				"subject":        f.Certificate.Subject,
+				"issuer":         f.Certificate.Issuer,
+				"serial_number":  f.Certificate.SerialNumber,
+				"not_after":      f.Certificate.NotAfter.UTC().Format(time.RFC3339),
+				"days_to_expiry": fmt.Sprintf("%d", f.DaysToExpiry),
+				"dns_names":      strings.Join(f.Certificate.DNSNames, ","),
+				"sans":           strings.Join(f.Certificate.SANs, ","),
+				"source":         f.Source,
+			},
+		})
+	}
+	return events
+}
+
+// Summary describes the certificate's expiry in one line
+func (f Finding) Summary() string {
+	if f.Certificate == nil {
+		return f.Message
+	}
+
+	date := f.Certificate.NotAfter.UTC().Format("2006-01-02")
+	if f.Level == LevelExpired {
+		return fmt.Sprintf("%s expired %d day(s) ago on %s", f.Certificate.Subject, -f.DaysToExpiry, date)
+	}
+	return fmt.Sprintf("%s expires in %d day(s) on %s", f.Certificate.Subject, f.DaysToExpiry, date)
+}
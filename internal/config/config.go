@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	dserrors "github.com/systmms/dsops/internal/errors"
@@ -33,6 +34,7 @@ type Definition struct {
 	Policies      *policy.PolicyConfig         `yaml:"policies,omitempty"`
 	Notifications *NotificationConfig          `yaml:"notifications,omitempty"` // Rotation notifications
 	Metrics       *MetricsConfig               `yaml:"metrics,omitempty"`       // Prometheus metrics
+	Sync          map[string]SyncConfig        `yaml:"sync,omitempty"`          // Store-to-store sync jobs
 }
 
 // SecretStoreConfig holds secret store-specific configuration
@@ -62,6 +64,7 @@ type Environment map[string]Variable
 // Variable represents a single environment variable configuration with new reference types
 type Variable struct {
 	From      *Reference        `yaml:"from"`
+	Fallbacks []Reference       `yaml:"-"` // Further entries of a 'from' list, tried in order when From fails
 	Literal   string            `yaml:"literal"`
 	Transform string            `yaml:"transform"`
 	Optional  bool              `yaml:"optional"`
@@ -78,8 +81,19 @@ type Reference struct {
 	Provider string `yaml:"provider,omitempty"`
 	Key      string `yaml:"key,omitempty"`
 	Version  string `yaml:"version,omitempty"`
+
+	// On is the failure of the previous source that lets a fallback be
+	// tried: not_found, unavailable or any (default)
+	On string `yaml:"on,omitempty"`
 }
 
+// Fallback conditions for the 'on' setting of a 'from' list entry
+const (
+	FallbackOnNotFound    = "not_found"   // The previous store answered that the secret does not exist
+	FallbackOnUnavailable = "unavailable" // The previous store could not be reached: network error, timeout or server error
+	FallbackOnAny         = "any"
+)
+
 // ProviderRef references a provider and key (legacy compatibility)
 type ProviderRef struct {
 	Provider string `yaml:"provider"`
@@ -157,10 +171,82 @@ func (c *Config) Load() error {
 		}
 	}
 
+	if err := def.validateFallbacks(); err != nil {
+		return err
+	}
+	def.resolveFilePaths(filepath.Dir(c.Path))
+
 	c.Definition = &def
 	return nil
 }
 
+// validateFallbacks checks the 'on' conditions of 'from' lists
+func (d *Definition) validateFallbacks() error {
+	for envName, env := range d.Envs {
+		for varName, variable := range env {
+			if variable.From != nil && variable.From.On != "" {
+				return dserrors.ConfigError{
+					Field:      fmt.Sprintf("envs.%s.%s.from[0].on", envName, varName),
+					Value:      variable.From.On,
+					Message:    "the first source of a variable is always tried",
+					Suggestion: "Remove 'on' from the first entry and set it on the fallbacks that follow",
+				}
+			}
+			for i, fallback := range variable.Fallbacks {
+				switch fallback.On {
+				case "", FallbackOnNotFound, FallbackOnUnavailable, FallbackOnAny:
+				default:
+					return dserrors.ConfigError{
+						Field:      fmt.Sprintf("envs.%s.%s.from[%d].on", envName, varName, i+1),
+						Value:      fallback.On,
+						Message:    "unknown fallback condition",
+						Suggestion: "Use on: not_found, unavailable or any",
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolveFilePaths makes relative paths of file backed stores (file.dotenv,
+// file.json, file.yaml, the deprecated json alias and sops) relative to the
+// directory holding dsops.yaml, so commands behave the same from any working
+// directory
+func (d *Definition) resolveFilePaths(dir string) {
+	resolve := func(storeType string, cfg map[string]interface{}) {
+		key := filePathKey(storeType)
+		if key == "" {
+			return
+		}
+		path, ok := cfg[key].(string)
+		if !ok || path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+			return
+		}
+		cfg[key] = filepath.Join(dir, path)
+	}
+
+	for _, store := range d.SecretStores {
+		resolve(store.Type, store.Config)
+	}
+	for _, provider := range d.Providers {
+		resolve(provider.Type, provider.Config)
+	}
+}
+
+// filePathKey returns the setting that names the file a store reads, or ""
+// for stores that are not backed by a local file
+func filePathKey(storeType string) string {
+	switch {
+	case strings.HasPrefix(storeType, "file."), storeType == "json":
+		return "path"
+	case storeType == "sops":
+		return "file"
+	default:
+		return ""
+	}
+}
+
 // GetEnvironment returns the configuration for a specific environment
 func (c *Config) GetEnvironment(name string) (Environment, error) {
 	if c.Definition == nil {
@@ -298,6 +384,47 @@ func (c *Config) GetService(name string) (ServiceConfig, error) {
 	return ServiceConfig{}, fmt.Errorf("service %s not found", name)
 }
 
+// UnmarshalYAML accepts 'from' as a single reference or as an ordered list
+// of references. The first entry of a list becomes From and the rest
+// Fallbacks.
+func (v *Variable) UnmarshalYAML(node *yaml.Node) error {
+	type plain Variable
+
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value != "from" || value.Kind != yaml.SequenceNode {
+				continue
+			}
+
+			var chain []Reference
+			if err := value.Decode(&chain); err != nil {
+				return err
+			}
+			rest := *node
+			rest.Content = append(append([]*yaml.Node{}, node.Content[:i]...), node.Content[i+2:]...)
+			if err := rest.Decode((*plain)(v)); err != nil {
+				return err
+			}
+			if len(chain) > 0 {
+				v.From = &chain[0]
+				v.Fallbacks = chain[1:]
+			}
+			return nil
+		}
+	}
+
+	return node.Decode((*plain)(v))
+}
+
+// Sources returns the variable's references in the order they are tried
+func (v Variable) Sources() []Reference {
+	if v.From == nil {
+		return nil
+	}
+	return append([]Reference{*v.From}, v.Fallbacks...)
+}
+
 // Reference methods
 
 // IsLegacyFormat returns true if this reference uses the old provider+key format
@@ -315,6 +442,19 @@ func (r *Reference) IsServiceReference() bool {
 	return r.Service != ""
 }
 
+// FallbackApplies reports whether this reference, as a fallback, should be
+// tried after the previous source failed. failure is FallbackOnNotFound or
+// FallbackOnUnavailable when the failure was one of those, and empty for
+// any other failure such as denied access or a configuration error.
+func (r *Reference) FallbackApplies(failure string) bool {
+	switch r.On {
+	case FallbackOnNotFound, FallbackOnUnavailable:
+		return failure == r.On
+	default:
+		return true
+	}
+}
+
 // ToSecretRef converts a Reference to a SecretRef (if it's a store reference)
 func (r *Reference) ToSecretRef() (secretstore.SecretRef, error) {
 	if r.IsStoreReference() {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 )
 
@@ -224,3 +225,147 @@ func TestConvertLegacyReference(t *testing.T) {
 		})
 	}
 }
+
+func TestDefinition_Load_ResolvesFileStorePaths(t *testing.T) {
+	configContent := `version: 0
+
+secretStores:
+  local:
+    type: file.dotenv
+    path: .env.local
+  shared:
+    type: file.yaml
+    path: /etc/app/secrets.yaml
+  home:
+    type: file.json
+    path: ~/secrets.json
+  sops:
+    type: sops
+    file: secrets.enc.yaml
+  legacy:
+    type: json
+    path: secrets.json
+  vault:
+    type: vault
+    path: secret/app
+`
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "dsops.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	config := &Config{Path: configPath, Logger: logging.New(false, false)}
+	require.NoError(t, config.Load())
+
+	stores := config.Definition.SecretStores
+	assert.Equal(t, filepath.Join(tmpDir, ".env.local"), stores["local"].Config["path"])
+	assert.Equal(t, "/etc/app/secrets.yaml", stores["shared"].Config["path"])
+	assert.Equal(t, "~/secrets.json", stores["home"].Config["path"])
+	assert.Equal(t, filepath.Join(tmpDir, "secrets.enc.yaml"), stores["sops"].Config["file"])
+	assert.Equal(t, filepath.Join(tmpDir, "secrets.json"), stores["legacy"].Config["path"])
+	assert.Equal(t, "secret/app", stores["vault"].Config["path"], "only file backed stores are rewritten")
+}
+
+func TestDefinition_Load_FallbackChains(t *testing.T) {
+	configContent := `version: 0
+
+secretStores:
+  vault:
+    type: vault
+  aws:
+    type: aws.secretsmanager
+  cache:
+    type: file.json
+    path: cache.json.age
+
+envs:
+  production:
+    DATABASE_PASSWORD:
+      optional: true
+      from:
+        - store: store://vault/database/password
+        - store: store://aws/prod/database#.password
+          on: unavailable
+        - store: store://cache/database.password
+    API_KEY:
+      from: { store: "store://vault/api/key" }
+`
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "dsops.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	config := &Config{Path: configPath, Logger: logging.New(false, false)}
+	require.NoError(t, config.Load())
+
+	env := config.Definition.Envs["production"]
+	chained := env["DATABASE_PASSWORD"]
+	assert.True(t, chained.Optional)
+	require.NotNil(t, chained.From)
+	assert.Equal(t, "store://vault/database/password", chained.From.Store)
+	require.Len(t, chained.Fallbacks, 2)
+	assert.Equal(t, FallbackOnUnavailable, chained.Fallbacks[0].On)
+	assert.Equal(t, "aws", chained.Fallbacks[0].GetEffectiveProvider())
+	assert.Equal(t, "", chained.Fallbacks[1].On)
+
+	sources := chained.Sources()
+	require.Len(t, sources, 3)
+	assert.Equal(t, "cache", sources[2].GetEffectiveProvider())
+
+	single := env["API_KEY"]
+	require.NotNil(t, single.From)
+	assert.Empty(t, single.Fallbacks)
+	assert.Len(t, single.Sources(), 1)
+}
+
+func TestDefinition_Load_FallbackConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  string
+		field string
+	}{
+		{
+			name: "unknown condition",
+			from: `
+        - store: store://vault/db
+        - store: store://aws/db
+          on: timeout`,
+			field: "envs.prod.DB.from[1].on",
+		},
+		{
+			name: "condition on first source",
+			from: `
+        - store: store://vault/db
+          on: any
+        - store: store://aws/db`,
+			field: "envs.prod.DB.from[0].on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configContent := "version: 0\nenvs:\n  prod:\n    DB:\n      from:" + tt.from + "\n"
+			configPath := filepath.Join(t.TempDir(), "dsops.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+			config := &Config{Path: configPath, Logger: logging.New(false, false)}
+			err := config.Load()
+			var configErr dserrors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, tt.field, configErr.Field)
+		})
+	}
+}
+
+func TestReference_FallbackApplies(t *testing.T) {
+	assert.True(t, (&Reference{On: FallbackOnNotFound}).FallbackApplies(FallbackOnNotFound))
+	assert.False(t, (&Reference{On: FallbackOnNotFound}).FallbackApplies(FallbackOnUnavailable))
+	assert.True(t, (&Reference{On: FallbackOnUnavailable}).FallbackApplies(FallbackOnUnavailable))
+	assert.False(t, (&Reference{On: FallbackOnUnavailable}).FallbackApplies(FallbackOnNotFound))
+	assert.True(t, (&Reference{}).FallbackApplies(FallbackOnNotFound))
+	assert.True(t, (&Reference{On: FallbackOnAny}).FallbackApplies(""))
+
+	// Denied access and configuration errors are neither condition
+	assert.False(t, (&Reference{On: FallbackOnNotFound}).FallbackApplies(""))
+	assert.False(t, (&Reference{On: FallbackOnUnavailable}).FallbackApplies(""))
+}
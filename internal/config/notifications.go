@@ -1,6 +1,6 @@
 package config
 
-// NotificationConfig holds configuration for rotation notifications.
+// NotificationConfig holds configuration for rotation and incident notifications.
 type NotificationConfig struct {
 	// Slack configuration for Slack webhook notifications.
 	Slack *SlackNotificationConfig `yaml:"slack,omitempty"`
@@ -24,8 +24,9 @@ type SlackNotificationConfig struct {
 	// Channel is the Slack channel to post to (optional, uses webhook default).
 	Channel string `yaml:"channel,omitempty"`
 
-	// Events specifies which rotation events trigger notifications.
-	// Valid values: started, completed, failed, rollback.
+	// Events specifies which rotation and incident events trigger notifications.
+	// Valid values: started, completed, failed, rollback, incident_opened,
+	// incident_updated, incident_resolved, certificate_expiring.
 	// If empty, all events are sent.
 	Events []string `yaml:"events,omitempty"`
 
@@ -35,7 +36,8 @@ type SlackNotificationConfig struct {
 
 // SlackMentions defines who to mention for specific event types.
 type SlackMentions struct {
-	// OnFailure lists Slack handles to mention when rotation fails.
+	// OnFailure lists Slack handles to mention when rotation fails or an
+	// incident is opened.
 	// Examples: ["@oncall", "@platform-team"]
 	OnFailure []string `yaml:"on_failure,omitempty"`
 
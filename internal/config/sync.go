@@ -0,0 +1,63 @@
+package config
+
+// SyncConfig describes a store-to-store sync job run by 'dsops sync'.
+//
+// Example:
+//
+//	sync:
+//	  doppler-to-aws:
+//	    from: doppler
+//	    to: aws-prod/myapp/
+//	    exclude: ["DOPPLER_*"]
+//	    key_case: kebab
+//	    on_conflict: overwrite
+type SyncConfig struct {
+	// From is the source as store[/prefix]. Only keys under the prefix are
+	// synced, and the prefix is removed before mapping.
+	From string `yaml:"from"`
+
+	// To is the destination as store[/prefix]. The prefix is prepended to
+	// every mapped key. The store must be writable.
+	To string `yaml:"to"`
+
+	// Keys lists source keys (relative to the from prefix) to sync. Required
+	// when the source store cannot list secrets; otherwise all listed keys
+	// are synced.
+	Keys []string `yaml:"keys,omitempty"`
+
+	// Include and Exclude are glob patterns matched against source keys
+	// relative to the from prefix. Exclude wins over Include.
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// Map renames individual keys: source key -> destination key, both
+	// relative to their prefixes. Mapped keys skip Rename and KeyCase.
+	Map map[string]string `yaml:"map,omitempty"`
+
+	// Rename rules are applied in order; the first matching rule wins.
+	Rename []SyncRenameRule `yaml:"rename,omitempty"`
+
+	// KeyCase converts destination keys: upper, lower, kebab or snake.
+	KeyCase string `yaml:"key_case,omitempty"`
+
+	// Transform is applied to every value before it is written, using the
+	// same syntax as variable transforms.
+	Transform string `yaml:"transform,omitempty"`
+
+	// OnConflict decides what happens when the destination already holds a
+	// different value: fail (default), skip or overwrite.
+	OnConflict string `yaml:"on_conflict,omitempty"`
+
+	// Tags are attached to every secret written by this job.
+	Tags map[string]string `yaml:"tags,omitempty"`
+
+	// Env selects the environment whose write policies apply.
+	Env string `yaml:"env,omitempty"`
+}
+
+// SyncRenameRule rewrites keys matching a regular expression. Replace may
+// refer to capture groups as $1 or ${name}.
+type SyncRenameRule struct {
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+}
@@ -0,0 +1,431 @@
+// Package drift compares the secrets referenced in dsops.yaml with what the
+// secret stores actually hold.
+//
+// The Checker describes every referenced secret without reading its value
+// and reports keys that are missing, secrets older than the max_secret_age
+// policy, version pins pointing at deprecated versions, and secrets under a
+// scanned store prefix that no environment references.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/duration"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Kind classifies a drift finding
+type Kind string
+
+const (
+	KindMissing           Kind = "missing"
+	KindStale             Kind = "stale"
+	KindDeprecatedVersion Kind = "deprecated_version"
+	KindUnreferenced      Kind = "unreferenced"
+	KindError             Kind = "error"
+)
+
+// kindOrder sorts findings with the most urgent first
+var kindOrder = map[Kind]int{
+	KindError:             0,
+	KindMissing:           1,
+	KindDeprecatedVersion: 2,
+	KindStale:             3,
+	KindUnreferenced:      4,
+}
+
+// Finding is a single difference between configuration and a store
+type Finding struct {
+	Kind      Kind       `json:"kind"`
+	Env       string     `json:"env,omitempty"`
+	Variable  string     `json:"variable,omitempty"`
+	Store     string     `json:"store"`
+	Key       string     `json:"key"`
+	Version   string     `json:"version,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Message   string     `json:"message"`
+}
+
+// Report is the result of a drift check
+type Report struct {
+	Checked  int       `json:"checked"`
+	Scanned  int       `json:"scanned"`
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings of the given kind
+func (r *Report) Count(kind Kind) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Options selects what a drift check covers
+type Options struct {
+	// Envs limits reference checks to these environments. Empty checks all.
+	Envs []string
+
+	// Scan lists store[/prefix] locations to search for unreferenced secrets.
+	Scan []string
+
+	// Now is the reference time for staleness. Zero means time.Now().
+	Now time.Time
+}
+
+// ProviderLookup returns the provider registered under a store name
+type ProviderLookup func(name string) (provider.Provider, bool)
+
+// Checker runs drift checks against registered providers
+type Checker struct {
+	cfg    *config.Config
+	lookup ProviderLookup
+}
+
+// NewChecker creates a Checker. lookup is typically resolver.GetProvider.
+func NewChecker(cfg *config.Config, lookup ProviderLookup) *Checker {
+	return &Checker{cfg: cfg, lookup: lookup}
+}
+
+// usage is one variable referencing a secret
+type usage struct {
+	env      string
+	variable string
+	optional bool
+	ref      provider.Reference
+}
+
+// Check describes every referenced secret concurrently and scans the
+// requested prefixes
+func (c *Checker) Check(ctx context.Context, opts Options) (*Report, error) {
+	if c.cfg.Definition == nil {
+		return nil, fmt.Errorf("configuration not loaded")
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	envs := opts.Envs
+	if len(envs) == 0 {
+		for name := range c.cfg.Definition.Envs {
+			envs = append(envs, name)
+		}
+		sort.Strings(envs)
+	}
+
+	maxAges := make(map[string]time.Duration, len(envs))
+	var usages []usage
+	for _, envName := range envs {
+		env, err := c.cfg.GetEnvironment(envName)
+		if err != nil {
+			return nil, err
+		}
+
+		maxAge, err := c.maxSecretAge(envName)
+		if err != nil {
+			return nil, err
+		}
+		maxAges[envName] = maxAge
+
+		usages = append(usages, envUsages(envName, env)...)
+	}
+
+	report := &Report{Findings: []Finding{}}
+	metas, errs := c.describeAll(ctx, usages)
+
+	for _, u := range usages {
+		id := refID(u.ref)
+		report.Checked++
+
+		finding := Finding{Env: u.env, Variable: u.variable, Store: u.ref.Provider, Key: u.ref.Key, Version: u.ref.Version}
+		if err := errs[id]; err != nil {
+			finding.Kind = KindError
+			finding.Message = err.Error()
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+
+		meta := metas[id]
+		if !meta.Exists {
+			finding.Kind = KindMissing
+			finding.Message = "secret does not exist"
+			if u.ref.Version != "" {
+				finding.Message = fmt.Sprintf("version %s does not exist", u.ref.Version)
+			}
+			if u.optional {
+				finding.Message += " (variable is optional)"
+			}
+			report.Findings = append(report.Findings, finding)
+			continue
+		}
+
+		if !meta.UpdatedAt.IsZero() {
+			updatedAt := meta.UpdatedAt
+			finding.UpdatedAt = &updatedAt
+		}
+
+		if u.ref.Version != "" && meta.Deprecated {
+			f := finding
+			f.Kind = KindDeprecatedVersion
+			f.Message = fmt.Sprintf("pinned version %s is deprecated", u.ref.Version)
+			report.Findings = append(report.Findings, f)
+		}
+
+		if maxAge := maxAges[u.env]; maxAge > 0 && !meta.UpdatedAt.IsZero() {
+			if age := now.Sub(meta.UpdatedAt); age > maxAge {
+				f := finding
+				f.Kind = KindStale
+				f.Message = fmt.Sprintf("last updated %s ago, max_secret_age is %s", formatAge(age), formatAge(maxAge))
+				report.Findings = append(report.Findings, f)
+			}
+		}
+	}
+
+	if len(opts.Scan) > 0 {
+		referenced := c.referencedKeys()
+		for _, location := range opts.Scan {
+			findings, scanned, err := c.scan(ctx, location, referenced)
+			if err != nil {
+				return nil, err
+			}
+			report.Scanned += scanned
+			report.Findings = append(report.Findings, findings...)
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Env != b.Env {
+			return a.Env < b.Env
+		}
+		if a.Variable != b.Variable {
+			return a.Variable < b.Variable
+		}
+		return a.Store+"/"+a.Key < b.Store+"/"+b.Key
+	})
+
+	return report, nil
+}
+
+// describeAll calls Describe once per distinct reference, with at most ten
+// calls in flight
+func (c *Checker) describeAll(ctx context.Context, usages []usage) (map[string]provider.Metadata, map[string]error) {
+	metas := make(map[string]provider.Metadata)
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 10)
+	seen := make(map[string]bool)
+
+	for _, u := range usages {
+		id := refID(u.ref)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		go func(ref provider.Reference) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			meta, err := c.describe(ctx, ref)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[refID(ref)] = err
+				return
+			}
+			metas[refID(ref)] = meta
+		}(u.ref)
+	}
+
+	wg.Wait()
+	return metas, errs
+}
+
+func (c *Checker) describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	p, ok := c.lookup(ref.Provider)
+	if !ok {
+		return provider.Metadata{}, fmt.Errorf("store %s is not registered", ref.Provider)
+	}
+
+	timeout := 30 * time.Second
+	if pc, err := c.cfg.GetProvider(ref.Provider); err == nil {
+		timeout = time.Duration(pc.GetProviderTimeout()) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	meta, err := p.Describe(ctx, ref)
+	if err != nil && provider.IsNotFound(err) {
+		return provider.Metadata{Exists: false}, nil
+	}
+	return meta, err
+}
+
+// scan lists a store prefix and reports keys no environment references
+func (c *Checker) scan(ctx context.Context, location string, referenced map[string]map[string]bool) ([]Finding, int, error) {
+	store, prefix, _ := strings.Cut(location, "/")
+
+	p, ok := c.lookup(store)
+	if !ok {
+		return nil, 0, dserrors.ConfigError{
+			Field:      "scan",
+			Value:      location,
+			Message:    "store is not registered",
+			Suggestion: "Use a store name from the 'secretStores:' section",
+		}
+	}
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return nil, 0, dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' does not support listing", store),
+			Suggestion: "Remove it from --scan; only listable stores can be scanned for unreferenced secrets",
+		}
+	}
+
+	var findings []Finding
+	scanned := 0
+	opts := provider.ListOptions{Prefix: prefix}
+	for {
+		page, err := lister.ListSecrets(ctx, opts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list %s: %w", location, err)
+		}
+
+		for _, info := range page.Secrets {
+			if strings.HasSuffix(info.Key, "/") {
+				continue
+			}
+			scanned++
+			if referenced[store][info.Key] {
+				continue
+			}
+
+			f := Finding{
+				Kind:    KindUnreferenced,
+				Store:   store,
+				Key:     info.Key,
+				Version: info.Version,
+				Message: "not referenced by any environment",
+			}
+			if !info.UpdatedAt.IsZero() {
+				updatedAt := info.UpdatedAt
+				f.UpdatedAt = &updatedAt
+			}
+			findings = append(findings, f)
+		}
+
+		if page.NextPageToken == "" || page.NextPageToken == opts.PageToken {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+
+	return findings, scanned, nil
+}
+
+// referencedKeys returns the base keys referenced by any environment, by store
+func (c *Checker) referencedKeys() map[string]map[string]bool {
+	referenced := make(map[string]map[string]bool)
+	for envName, env := range c.cfg.Definition.Envs {
+		for _, u := range envUsages(envName, env) {
+			if referenced[u.ref.Provider] == nil {
+				referenced[u.ref.Provider] = make(map[string]bool)
+			}
+			referenced[u.ref.Provider][baseKey(u.ref.Key)] = true
+		}
+	}
+	return referenced
+}
+
+func (c *Checker) maxSecretAge(envName string) (time.Duration, error) {
+	value := c.cfg.GetPolicyEnforcer().MaxSecretAge(envName)
+	if value == "" {
+		return 0, nil
+	}
+
+	age, err := duration.Parse(value)
+	if err != nil {
+		return 0, dserrors.ConfigError{
+			Field:      "policies.max_secret_age",
+			Value:      value,
+			Message:    "invalid duration",
+			Suggestion: "Use a duration like 720h, 90d or 12w",
+		}
+	}
+	return age, nil
+}
+
+// envUsages returns the store references made by an environment's variables,
+// fallback sources included
+func envUsages(envName string, env config.Environment) []usage {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var usages []usage
+	for _, name := range names {
+		variable := env[name]
+		for _, source := range variable.Sources() {
+			if source.IsServiceReference() {
+				continue
+			}
+
+			store := source.GetEffectiveProvider()
+			legacy := source.ToLegacyProviderRef()
+			if store == "" || legacy.Key == "" {
+				continue
+			}
+
+			usages = append(usages, usage{
+				env:      envName,
+				variable: name,
+				optional: variable.Optional,
+				ref:      provider.Reference{Provider: store, Key: legacy.Key, Version: legacy.Version},
+			})
+		}
+	}
+	return usages
+}
+
+// baseKey strips a field selector such as "#.password" or "#password"
+func baseKey(key string) string {
+	if i := strings.Index(key, "#"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+func refID(ref provider.Reference) string {
+	return ref.Provider + "\x00" + ref.Key + "\x00" + ref.Version
+}
+
+// formatAge renders a duration in days when it is at least a day
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return d.Round(time.Minute).String()
+}
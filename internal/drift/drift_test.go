@@ -0,0 +1,173 @@
+package drift_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/drift"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/policy"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+type listingProvider struct {
+	*fakes.FakeProvider
+	secrets []provider.SecretInfo
+}
+
+func (l *listingProvider) ListSecrets(_ context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	return provider.PageSecrets(l.secrets, opts)
+}
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func driftConfig() *config.Config {
+	ref := func(store, key, version string) *config.Reference {
+		return &config.Reference{Provider: store, Key: key, Version: version}
+	}
+
+	return &config.Config{
+		Logger: logging.New(false, true),
+		Definition: &config.Definition{
+			SecretStores: map[string]config.SecretStoreConfig{
+				"aws":   {Type: "aws.secretsmanager"},
+				"vault": {Type: "vault"},
+			},
+			Policies: &policy.PolicyConfig{
+				MaxSecretAge: "90d",
+				EnvironmentRules: map[string]*policy.EnvironmentPolicy{
+					"dev": {MaxSecretAge: "365d"},
+				},
+			},
+			Envs: map[string]config.Environment{
+				"prod": {
+					"DB_PASSWORD": {From: ref("aws", "app/db#.password", "")},
+					"API_KEY":     {From: ref("aws", "app/api", "")},
+					"OLD_TOKEN":   {From: ref("aws", "app/token", "v1")},
+					"MISSING":     {From: ref("aws", "app/missing", ""), Optional: true},
+					"BROKEN":      {From: ref("vault", "secret/data/x", "")},
+					"LITERAL":     {Literal: "x"},
+				},
+				"dev": {
+					"API_KEY": {From: ref("aws", "app/api", "")},
+				},
+			},
+		},
+	}
+}
+
+func newDriftChecker(t *testing.T) *drift.Checker {
+	t.Helper()
+	return newDriftCheckerFor(t, driftConfig())
+}
+
+func newDriftCheckerFor(t *testing.T, cfg *config.Config) *drift.Checker {
+	t.Helper()
+
+	aws := &listingProvider{
+		FakeProvider: fakes.NewFakeProvider("aws").
+			WithMetadata("app/db#.password", provider.Metadata{Exists: true, UpdatedAt: now.AddDate(0, 0, -10)}).
+			WithMetadata("app/api", provider.Metadata{Exists: true, UpdatedAt: now.AddDate(0, 0, -200)}).
+			WithMetadata("app/token", provider.Metadata{Exists: true, Version: "v1", Deprecated: true}),
+		secrets: []provider.SecretInfo{{Key: "app/db"}, {Key: "app/api"}, {Key: "app/token"}, {Key: "app/orphan"}, {Key: "other/x"}},
+	}
+	vault := fakes.NewFakeProvider("vault").WithError("secret/data/x", errors.New("permission denied"))
+
+	providers := map[string]provider.Provider{"aws": aws, "vault": vault}
+	return drift.NewChecker(cfg, func(name string) (provider.Provider, bool) {
+		p, ok := providers[name]
+		return p, ok
+	})
+}
+
+func kinds(report *drift.Report) map[string]drift.Kind {
+	result := make(map[string]drift.Kind)
+	for _, f := range report.Findings {
+		result[f.Env+"/"+f.Variable+"/"+f.Key] = f.Kind
+	}
+	return result
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	report, err := newDriftChecker(t).Check(context.Background(), drift.Options{
+		Scan: []string{"aws/app/"},
+		Now:  now,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Checked)
+	assert.Equal(t, 4, report.Scanned)
+	assert.Equal(t, map[string]drift.Kind{
+		"prod/BROKEN/secret/data/x": drift.KindError,
+		"prod/MISSING/app/missing":  drift.KindMissing,
+		"prod/OLD_TOKEN/app/token":  drift.KindDeprecatedVersion,
+		"prod/API_KEY/app/api":      drift.KindStale,
+		"//app/orphan":              drift.KindUnreferenced,
+	}, kinds(report), "dev allows 365 days, so its API_KEY is not stale")
+
+	assert.Equal(t, drift.KindError, report.Findings[0].Kind, "errors sort first")
+	assert.Contains(t, report.Findings[1].Message, "optional")
+}
+
+func TestCheck_SingleEnvironment(t *testing.T) {
+	t.Parallel()
+
+	report, err := newDriftChecker(t).Check(context.Background(), drift.Options{Envs: []string{"dev"}, Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Empty(t, report.Findings)
+
+	_, err = newDriftChecker(t).Check(context.Background(), drift.Options{Envs: []string{"nope"}})
+	assert.Error(t, err)
+}
+
+func TestCheck_ScanErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := newDriftChecker(t).Check(context.Background(), drift.Options{Scan: []string{"vault/secret/"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support listing")
+
+	_, err = newDriftChecker(t).Check(context.Background(), drift.Options{Scan: []string{"nope"}})
+	assert.Error(t, err)
+}
+
+func TestCheck_Fallbacks(t *testing.T) {
+	t.Parallel()
+
+	cfg := driftConfig()
+	cfg.Definition.Policies = nil
+	cfg.Definition.Envs = map[string]config.Environment{
+		"prod": {
+			"API_KEY": {
+				From: &config.Reference{Provider: "aws", Key: "app/api"},
+				Fallbacks: []config.Reference{
+					{Provider: "aws", Key: "app/token"},
+					{Provider: "vault", Key: "secret/data/x"},
+				},
+			},
+		},
+	}
+
+	report, err := newDriftCheckerFor(t, cfg).Check(context.Background(), drift.Options{
+		Scan: []string{"aws/app/"},
+		Now:  now,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Checked, "every fallback source is described")
+	assert.Equal(t, map[string]drift.Kind{
+		"prod/API_KEY/secret/data/x": drift.KindError,
+		"//app/db":                   drift.KindUnreferenced,
+		"//app/orphan":               drift.KindUnreferenced,
+	}, kinds(report), "app/token is referenced as a fallback")
+}
@@ -0,0 +1,37 @@
+// Package duration parses the human-friendly durations accepted by dsops
+// flags and configuration, such as "36h", "7d" or "2w".
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Day and Week are the units Parse accepts beyond time.ParseDuration
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Parse parses a non-negative duration such as "36h", "7d" or "2w". Days and
+// weeks are accepted in addition to time.ParseDuration units.
+func Parse(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": Day, "w": Week} {
+		if strings.HasSuffix(value, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(value, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration: %s", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s (use a duration like 36h, 7d or 2w)", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,31 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]time.Duration{
+		"36h":  36 * time.Hour,
+		"7d":   7 * Day,
+		"2w":   2 * Week,
+		"90m":  90 * time.Minute,
+		" 1d ": Day,
+	}
+	for input, want := range tests {
+		got, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "abc", "-1d", "xd", "-5m"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"sort"
 	"strings"
 	"syscall"
@@ -36,6 +37,7 @@ type ExecOptions struct {
 	PrintVars         bool                            // Print resolved variables (names only, values masked)
 	WorkingDir        string                          // Working directory for the command
 	Timeout           int                             // Timeout in seconds (0 for no timeout)
+	OnExit            func()                          // Called after the command exits, before dsops exits
 }
 
 // Exec runs a command with the provided environment variables
@@ -129,7 +131,11 @@ func (e *Executor) Exec(ctx context.Context, options ExecOptions) error {
 	// Run the command
 	// Note: SecureBuffers are already destroyed above, so secrets are not
 	// held in parent memory during child execution.
-	if err := cmd.Run(); err != nil {
+	err = e.run(cmd, options.OnExit != nil)
+	if options.OnExit != nil {
+		options.OnExit()
+	}
+	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			// Preserve the exit code from the child process
 			if status, ok := exitError.Sys().(syscall.WaitStatus); ok {
@@ -147,6 +153,40 @@ func (e *Executor) Exec(ctx context.Context, options ExecOptions) error {
 	return nil
 }
 
+// run starts the command and waits for it. With holdSignals, dsops ignores
+// Ctrl-C and forwards SIGTERM so it outlives the child and can clean up after
+// it; the child receives Ctrl-C from the terminal directly.
+func (e *Executor) run(cmd *exec.Cmd, holdSignals bool) error {
+	if !holdSignals {
+		return cmd.Run()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig != os.Interrupt {
+					_ = cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return cmd.Wait()
+}
+
 // buildEnvironment creates the environment slice for the child process
 func (e *Executor) buildEnvironment(dsopsVars map[string]string, allowOverride bool) ([]string, error) {
 	// Start with current environment
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -574,3 +575,23 @@ func TestExecutor_Exec_CommandNotFound(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestExecutor_Exec_OnExit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true is not available")
+	}
+
+	executor := createTestExecutor()
+
+	called := 0
+	err := executor.Exec(context.Background(), ExecOptions{
+		Command:     []string{"true"},
+		Environment: map[string]string{"DSOPS_TEST": "1"},
+		OnExit:      func() { called++ },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, called)
+}
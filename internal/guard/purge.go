@@ -0,0 +1,393 @@
+// Package guard rewrites git history to remove leaked secrets.
+//
+// Purge streams the repository through git fast-export, replaces every
+// leaked string in file contents and commit or tag messages with
+// RemovedPlaceholder, and loads the result back with git fast-import. It
+// works entirely on the local repository and never contacts a remote.
+package guard
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RemovedPlaceholder replaces every leaked string in rewritten history
+const RemovedPlaceholder = "***REMOVED***"
+
+// BackupRefPrefix is where the original refs are kept after a purge
+const BackupRefPrefix = "refs/dsops/backup"
+
+// PurgeOptions configures a history rewrite
+type PurgeOptions struct {
+	// RepoPath is the path to the git repository
+	RepoPath string
+
+	// Secrets are the literal strings to remove from history
+	Secrets []string
+
+	// DryRun reports what would change without rewriting anything
+	DryRun bool
+}
+
+// PurgeResult describes the effect of a purge
+type PurgeResult struct {
+	// AffectedCommits are the original IDs of commits whose files or
+	// message contained a leaked string
+	AffectedCommits []string
+
+	// AffectedRefs are the branches and tags that contain an affected commit
+	AffectedRefs []string
+
+	// BlobsRewritten is the number of file versions that were changed
+	BlobsRewritten int
+
+	// MessagesRewritten is the number of commit and tag messages that were changed
+	MessagesRewritten int
+
+	// BackupRef is the prefix under which the original refs were saved.
+	// Empty for dry runs or when nothing changed.
+	BackupRef string
+}
+
+// Purge replaces leaked strings throughout the history of every local
+// branch and tag. In dry-run mode the repository is only read.
+func Purge(ctx context.Context, opts PurgeOptions) (*PurgeResult, error) {
+	secrets := normalizeSecrets(opts.Secrets)
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("no strings to purge")
+	}
+
+	refs, err := listRefs(ctx, opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("repository has no branches or tags")
+	}
+
+	refNames := make([]string, 0, len(refs))
+	for name := range refs {
+		refNames = append(refNames, name)
+	}
+	sort.Strings(refNames)
+
+	// First pass: find what would change without touching the repository
+	stats, err := runExport(ctx, opts.RepoPath, refNames, secrets, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{
+		AffectedCommits:   stats.affectedCommits(),
+		BlobsRewritten:    stats.blobsRewritten,
+		MessagesRewritten: stats.messagesRewritten,
+	}
+
+	result.AffectedRefs, err = refsContaining(ctx, opts.RepoPath, result.AffectedCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun || len(result.AffectedCommits) == 0 {
+		return result, nil
+	}
+
+	// The checked-out files are reset to the rewritten HEAD afterwards, so
+	// refuse to run over uncommitted work
+	status, err := git(ctx, opts.RepoPath, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(status) != "" {
+		return nil, fmt.Errorf("working tree has uncommitted changes; commit or stash them before purging")
+	}
+
+	backup := fmt.Sprintf("%s/%s", BackupRefPrefix, time.Now().UTC().Format("20060102T150405Z"))
+	for _, name := range refNames {
+		backupName := backup + "/" + strings.TrimPrefix(name, "refs/")
+		if _, err := git(ctx, opts.RepoPath, "update-ref", backupName, refs[name]); err != nil {
+			return nil, fmt.Errorf("failed to create backup ref %s: %w", backupName, err)
+		}
+	}
+	result.BackupRef = backup
+
+	// Second pass: stream the rewritten history into fast-import
+	importCmd := exec.CommandContext(ctx, "git", "fast-import", "--force", "--quiet")
+	importCmd.Dir = opts.RepoPath
+	var importErr bytes.Buffer
+	importCmd.Stderr = &importErr
+	stdin, err := importCmd.StdinPipe()
+	if err != nil {
+		return result, err
+	}
+	if err := importCmd.Start(); err != nil {
+		return result, fmt.Errorf("failed to start git fast-import: %w", err)
+	}
+
+	_, exportErr := runExport(ctx, opts.RepoPath, refNames, secrets, stdin)
+	_ = stdin.Close()
+	waitErr := importCmd.Wait()
+
+	if exportErr != nil {
+		return result, fmt.Errorf("history rewrite failed, original refs are under %s: %w", backup, exportErr)
+	}
+	if waitErr != nil {
+		return result, fmt.Errorf("git fast-import failed, original refs are under %s: %v: %s", backup, waitErr, strings.TrimSpace(importErr.String()))
+	}
+
+	// Bring the checked-out files in line with the rewritten branch
+	if _, err := git(ctx, opts.RepoPath, "symbolic-ref", "-q", "HEAD"); err == nil {
+		if _, err := git(ctx, opts.RepoPath, "reset", "--hard", "--quiet", "HEAD"); err != nil {
+			return result, fmt.Errorf("history rewritten but failed to update working tree: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// streamStats accumulates what a rewrite changed
+type streamStats struct {
+	commits           map[string]bool
+	blobsRewritten    int
+	messagesRewritten int
+}
+
+func (s *streamStats) affectedCommits() []string {
+	commits := make([]string, 0, len(s.commits))
+	for commit := range s.commits {
+		commits = append(commits, commit)
+	}
+	sort.Strings(commits)
+	return commits
+}
+
+// runExport runs git fast-export over refs and writes the rewritten stream to w
+func runExport(ctx context.Context, repoPath string, refs []string, secrets []string, w io.Writer) (*streamStats, error) {
+	args := []string{
+		"fast-export",
+		"--show-original-ids",
+		"--signed-tags=strip",
+		"--tag-of-filtered-object=rewrite",
+		"--fake-missing-tagger",
+		"--reencode=yes",
+	}
+	args = append(args, refs...)
+
+	exportCmd := exec.CommandContext(ctx, "git", args...)
+	exportCmd.Dir = repoPath
+	var exportErr bytes.Buffer
+	exportCmd.Stderr = &exportErr
+	stdout, err := exportCmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := exportCmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start git fast-export: %w", err)
+	}
+
+	stats, rewriteErr := rewriteStream(stdout, w, secrets)
+	if rewriteErr != nil {
+		_ = exportCmd.Process.Kill()
+		_ = exportCmd.Wait()
+		return nil, rewriteErr
+	}
+	if err := exportCmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git fast-export failed: %v: %s", err, strings.TrimSpace(exportErr.String()))
+	}
+
+	return stats, nil
+}
+
+// rewriteStream copies a fast-export stream from r to w, replacing secrets
+// in every data block and recording which commits were affected
+func rewriteStream(r io.Reader, w io.Writer, secrets []string) (*streamStats, error) {
+	pairs := make([]string, 0, len(secrets)*2)
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, RemovedPlaceholder)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	stats := &streamStats{commits: make(map[string]bool)}
+	modifiedMarks := make(map[string]bool)
+
+	var (
+		kind      string // blob, commit or tag
+		mark      string
+		commitID  string
+		commitKey string // mark or original id identifying the current commit
+	)
+
+	markAffected := func() {
+		if kind != "commit" {
+			return
+		}
+		if commitID != "" {
+			stats.commits[commitID] = true
+		} else if commitKey != "" {
+			stats.commits[commitKey] = true
+		}
+	}
+
+	in := bufio.NewReaderSize(r, 64*1024)
+	out := bufio.NewWriterSize(w, 64*1024)
+
+	for {
+		line, err := in.ReadString('\n')
+		if err == io.EOF && line == "" {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read fast-export stream: %w", err)
+		}
+
+		trimmed := strings.TrimSuffix(line, "\n")
+		switch {
+		case trimmed == "blob":
+			kind, mark, commitID, commitKey = "blob", "", "", ""
+		case strings.HasPrefix(trimmed, "commit "):
+			kind, mark, commitID, commitKey = "commit", "", "", ""
+		case strings.HasPrefix(trimmed, "tag "):
+			kind, mark, commitID, commitKey = "tag", "", "", ""
+		case strings.HasPrefix(trimmed, "reset "):
+			kind = ""
+		case strings.HasPrefix(trimmed, "mark "):
+			mark = strings.TrimPrefix(trimmed, "mark ")
+			if kind == "commit" {
+				commitKey = mark
+			}
+		case strings.HasPrefix(trimmed, "original-oid "):
+			if kind == "commit" {
+				commitID = strings.TrimPrefix(trimmed, "original-oid ")
+			}
+		case strings.HasPrefix(trimmed, "M ") && kind == "commit":
+			// M <mode> <dataref> <path>
+			fields := strings.SplitN(trimmed, " ", 4)
+			if len(fields) == 4 && modifiedMarks[fields[2]] {
+				markAffected()
+			}
+		case strings.HasPrefix(trimmed, "data "):
+			size := strings.TrimPrefix(trimmed, "data ")
+			if strings.HasPrefix(size, "<<") {
+				return nil, fmt.Errorf("unsupported delimited data in fast-export stream")
+			}
+			n, convErr := strconv.Atoi(size)
+			if convErr != nil || n < 0 {
+				return nil, fmt.Errorf("invalid data length in fast-export stream: %q", size)
+			}
+
+			payload := make([]byte, n)
+			if _, err := io.ReadFull(in, payload); err != nil {
+				return nil, fmt.Errorf("truncated data in fast-export stream: %w", err)
+			}
+
+			rewritten := replacer.Replace(string(payload))
+			if rewritten != string(payload) {
+				switch kind {
+				case "blob":
+					stats.blobsRewritten++
+					if mark != "" {
+						modifiedMarks[mark] = true
+					}
+				case "commit":
+					stats.messagesRewritten++
+					markAffected()
+				case "tag":
+					stats.messagesRewritten++
+				}
+			}
+
+			if _, err := fmt.Fprintf(out, "data %d\n%s", len(rewritten), rewritten); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if _, err := out.WriteString(line); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// listRefs returns local branches and tags with the objects they point to
+func listRefs(ctx context.Context, repoPath string) (map[string]string, error) {
+	output, err := git(ctx, repoPath, "for-each-ref", "--format=%(refname) %(objectname)", "refs/heads", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		name, oid, ok := strings.Cut(line, " ")
+		if ok {
+			refs[name] = oid
+		}
+	}
+	return refs, nil
+}
+
+// refsContaining returns the branches and tags that contain any of the commits
+func refsContaining(ctx context.Context, repoPath string, commits []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		output, err := git(ctx, repoPath, "for-each-ref", "--format=%(refname)", "--contains", commit, "refs/heads", "refs/tags")
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range strings.Fields(output) {
+			seen[ref] = true
+		}
+	}
+
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// git runs a git command in the repository and returns its output
+func git(ctx context.Context, repoPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// normalizeSecrets drops empty and duplicate strings and orders longer
+// strings first so a secret that contains another is replaced whole
+func normalizeSecrets(secrets []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, secret := range secrets {
+		if secret == "" || secret == RemovedPlaceholder || seen[secret] {
+			continue
+		}
+		seen[secret] = true
+		result = append(result, secret)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i]) > len(result[j])
+	})
+	return result
+}
@@ -0,0 +1,138 @@
+package incident
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	ExportMarkdown = "md"
+	ExportJSON     = "json"
+)
+
+// Export writes the report in the given format for use in postmortems
+func Export(w io.Writer, report *Report, format string) error {
+	switch strings.ToLower(format) {
+	case ExportMarkdown, "markdown":
+		return exportMarkdown(w, report, time.Now())
+	case ExportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	default:
+		return fmt.Errorf("unsupported export format: %s (use md or json)", format)
+	}
+}
+
+// exportMarkdown renders the report as a Markdown postmortem document
+func exportMarkdown(w io.Writer, report *Report, now time.Time) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Incident %s: %s\n\n", report.ID, mdEscape(report.Title))
+
+	b.WriteString("| Field | Value |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Type | %s |\n", mdEscape(report.Type))
+	fmt.Fprintf(&b, "| Severity | %s |\n", mdEscape(report.Severity))
+	fmt.Fprintf(&b, "| Status | %s |\n", mdEscape(report.Status))
+	fmt.Fprintf(&b, "| Created | %s |\n", report.Timestamp.Format(time.RFC3339))
+	if report.ResolvedAt != nil {
+		fmt.Fprintf(&b, "| Resolved | %s |\n", report.ResolvedAt.Format(time.RFC3339))
+		fmt.Fprintf(&b, "| Time to resolve | %s |\n", report.ResolvedAt.Sub(report.Timestamp).Round(time.Minute))
+	}
+	if len(report.Assignees) > 0 {
+		fmt.Fprintf(&b, "| Assignees | %s |\n", mdEscape(strings.Join(report.Assignees, ", ")))
+	}
+
+	if report.Description != "" {
+		fmt.Fprintf(&b, "\n## Description\n\n%s\n", report.Description)
+	}
+
+	writeList := func(title string, items []string, code bool) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, item := range items {
+			if code {
+				fmt.Fprintf(&b, "- `%s`\n", item)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", mdEscape(item))
+			}
+		}
+	}
+	writeList("Affected Files", report.AffectedFiles, true)
+	writeList("Affected Secrets", report.AffectedSecrets, true)
+	writeList("Affected Commits", report.AffectedCommits, true)
+
+	if len(report.ActionsRequired) > 0 {
+		overdue := make(map[string]bool)
+		for _, action := range report.OverdueActions(now) {
+			overdue[action] = true
+		}
+
+		b.WriteString("\n## Required Actions\n\n")
+		for _, action := range report.ActionsRequired {
+			line := mdEscape(action)
+			if due, ok := report.DueDates[action]; ok {
+				line = fmt.Sprintf("%s (due %s", line, due.Format(time.RFC3339))
+				if overdue[action] {
+					line += ", **overdue**"
+				}
+				line += ")"
+			}
+			fmt.Fprintf(&b, "- [ ] %s\n", line)
+		}
+	}
+
+	if len(report.ActionsTaken) > 0 {
+		b.WriteString("\n## Actions Taken\n\n")
+		for _, action := range report.ActionsTaken {
+			fmt.Fprintf(&b, "- [x] %s\n", mdEscape(action))
+		}
+	}
+
+	if len(report.Timeline) > 0 {
+		b.WriteString("\n## Timeline\n\n| Time | Actor | Event | Details |\n|---|---|---|---|\n")
+		for _, entry := range report.Timeline {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				entry.Timestamp.Format(time.RFC3339),
+				mdEscape(entry.Actor),
+				entry.Event,
+				mdEscape(entry.Message),
+			)
+		}
+	}
+
+	if len(report.NotificationsSent) > 0 {
+		b.WriteString("\n## Notifications\n\n")
+		for _, notif := range report.NotificationsSent {
+			status := "sent"
+			if !notif.Success {
+				status = "failed"
+			}
+			fmt.Fprintf(&b, "- %s %s at %s", mdEscape(notif.Channel), status, notif.Timestamp.Format(time.RFC3339))
+			if notif.Details != "" {
+				fmt.Fprintf(&b, " (%s)", mdEscape(notif.Details))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if report.ResolutionNotes != "" {
+		fmt.Fprintf(&b, "\n## Resolution\n\n%s\n", report.ResolutionNotes)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mdEscape keeps user text from breaking Markdown tables and lists
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -7,13 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
-)
 
-const (
-	IncidentDirName = ".dsops/incidents"
-	AuditLogName    = ".dsops/audit.log"
+	"github.com/systmms/dsops/internal/audit"
 )
 
+const IncidentDirName = ".dsops/incidents"
+
 // Report represents a security incident report
 type Report struct {
 	ID          string            `json:"id"`
@@ -30,8 +29,13 @@ type Report struct {
 	AffectedCommits []string `json:"affected_commits,omitempty"`
 
 	// Response actions
-	ActionsRequired []string `json:"actions_required"`
-	ActionsTaken    []string `json:"actions_taken,omitempty"`
+	ActionsRequired []string             `json:"actions_required"`
+	ActionsTaken    []string             `json:"actions_taken,omitempty"`
+	DueDates        map[string]time.Time `json:"due_dates,omitempty"` // required action -> due date
+
+	// Ownership and history
+	Assignees []string        `json:"assignees,omitempty"`
+	Timeline  []TimelineEntry `json:"timeline,omitempty"`
 
 	// Notification status
 	NotificationsSent []NotificationRecord `json:"notifications_sent,omitempty"`
@@ -54,6 +58,7 @@ type NotificationRecord struct {
 type Manager struct {
 	incidentDir string
 	auditPath   string
+	actor       string
 }
 
 // NewManager creates a new incident manager
@@ -64,10 +69,21 @@ func NewManager(baseDir string) *Manager {
 
 	return &Manager{
 		incidentDir: filepath.Join(baseDir, IncidentDirName),
-		auditPath:   filepath.Join(baseDir, AuditLogName),
+		auditPath:   filepath.Join(baseDir, audit.LogName),
+		actor:       CurrentActor(),
 	}
 }
 
+// Actor returns the name recorded on timeline entries made by this manager
+func (m *Manager) Actor() string {
+	return m.actor
+}
+
+// SetActor overrides the name recorded on timeline entries
+func (m *Manager) SetActor(actor string) {
+	m.actor = actor
+}
+
 // CreateReport creates a new incident report
 func (m *Manager) CreateReport(incidentType, severity, title, description string, details map[string]string) (*Report, error) {
 	// Ensure incident directory exists
@@ -88,6 +104,7 @@ func (m *Manager) CreateReport(incidentType, severity, title, description string
 		Details:     details,
 		Status:      "open",
 	}
+	report.Record(m.actor, TimelineCreated, fmt.Sprintf("Reported %s incident (%s)", incidentType, severity))
 
 	// Save report
 	if err := m.SaveReport(report); err != nil {
@@ -196,15 +213,23 @@ func (m *Manager) AddNotification(report *Report, channel string, success bool,
 	}
 
 	report.NotificationsSent = append(report.NotificationsSent, record)
+	if success {
+		report.Record(m.actor, TimelineNotification, fmt.Sprintf("Notified %s", channel))
+	} else {
+		report.Record(m.actor, TimelineNotification, fmt.Sprintf("Failed to notify %s: %s", channel, details))
+	}
 	return m.UpdateReport(report)
 }
 
 // ResolveReport marks an incident as resolved
 func (m *Manager) ResolveReport(report *Report, resolutionNotes string) error {
 	now := time.Now()
-	report.Status = "resolved"
+	report.SetStatus(m.actor, "resolved")
 	report.ResolvedAt = &now
 	report.ResolutionNotes = resolutionNotes
+	if resolutionNotes != "" {
+		report.Record(m.actor, TimelineResolved, resolutionNotes)
+	}
 
 	if err := m.UpdateReport(report); err != nil {
 		return err
@@ -220,14 +245,7 @@ func (m *Manager) ResolveReport(report *Report, resolutionNotes string) error {
 
 // logToAudit writes an entry to the audit log
 func (m *Manager) logToAudit(report *Report, action string) error {
-	// Ensure audit directory exists
-	auditDir := filepath.Dir(m.auditPath)
-	if err := os.MkdirAll(auditDir, 0700); err != nil {
-		return fmt.Errorf("failed to create audit directory: %w", err)
-	}
-
-	// Create audit entry
-	entry := map[string]interface{}{
+	return audit.Append(m.auditPath, map[string]interface{}{
 		"timestamp":   time.Now().Format(time.RFC3339),
 		"action":      action,
 		"incident_id": report.ID,
@@ -235,26 +253,7 @@ func (m *Manager) logToAudit(report *Report, action string) error {
 		"severity":    report.Severity,
 		"title":       report.Title,
 		"status":      report.Status,
-	}
-
-	// Convert to JSON
-	data, err := json.Marshal(entry)
-	if err != nil {
-		return fmt.Errorf("failed to marshal audit entry: %w", err)
-	}
-
-	// Append to audit log
-	f, err := os.OpenFile(m.auditPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open audit log: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	if _, err := fmt.Fprintf(f, "%s\n", data); err != nil {
-		return fmt.Errorf("failed to write audit log: %w", err)
-	}
-
-	return nil
+	})
 }
 
 // generateIncidentID creates a unique incident ID
@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/audit"
 )
 
 func TestNewManager(t *testing.T) {
@@ -75,7 +76,7 @@ func TestManager_CreateReport(t *testing.T) {
 	assert.FileExists(t, reportPath)
 
 	// Verify audit log was created
-	auditPath := filepath.Join(tmpDir, AuditLogName)
+	auditPath := filepath.Join(tmpDir, audit.LogName)
 	assert.FileExists(t, auditPath)
 }
 
@@ -421,9 +422,9 @@ func TestNewNotifier(t *testing.T) {
 	t.Parallel()
 
 	config := NotificationConfig{
-		Slack: &SlackConfig{
-			WebhookURL: "https://hooks.slack.com/test",
-			Channel:    "#security",
+		GitHub: &GitHubConfig{
+			Owner:      "systmms",
+			Repository: "dsops",
 		},
 	}
 
@@ -447,128 +448,6 @@ func TestNotifier_SendNotifications_NoChannels(t *testing.T) {
 	assert.Empty(t, records)
 }
 
-func TestNotifier_SendSlackNotification_Success(t *testing.T) {
-	t.Parallel()
-
-	// Create mock Slack server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
-
-		var payload map[string]interface{}
-		err := json.NewDecoder(r.Body).Decode(&payload)
-		require.NoError(t, err)
-
-		// Verify message structure
-		assert.Contains(t, payload, "attachments")
-		assert.Equal(t, "#security", payload["channel"])
-		assert.Equal(t, "dsops-bot", payload["username"])
-
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-
-	config := NotificationConfig{
-		Slack: &SlackConfig{
-			WebhookURL: server.URL,
-			Channel:    "#security",
-			Username:   "dsops-bot",
-		},
-	}
-
-	notifier := NewNotifier(config)
-	report := &Report{
-		ID:              "INC-test",
-		Timestamp:       time.Now(),
-		Type:            "secret_leak",
-		Severity:        "critical",
-		Title:           "Test Incident",
-		Description:     "Test description",
-		AffectedFiles:   []string{"config.yaml"},
-		ActionsRequired: []string{"Rotate credentials"},
-		Status:          "open",
-	}
-
-	record := notifier.sendSlackNotification(report)
-	assert.True(t, record.Success)
-	assert.Equal(t, "slack", record.Channel)
-}
-
-func TestNotifier_SendSlackNotification_NoWebhook(t *testing.T) {
-	t.Parallel()
-
-	config := NotificationConfig{
-		Slack: &SlackConfig{
-			WebhookURL: "",
-		},
-	}
-
-	notifier := NewNotifier(config)
-	report := &Report{ID: "INC-test"}
-
-	record := notifier.sendSlackNotification(report)
-	assert.False(t, record.Success)
-	assert.Contains(t, record.Details, "No Slack webhook URL configured")
-}
-
-func TestNotifier_SendSlackNotification_HTTPError(t *testing.T) {
-	t.Parallel()
-
-	// Create mock server that returns error
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-
-	config := NotificationConfig{
-		Slack: &SlackConfig{
-			WebhookURL: server.URL,
-		},
-	}
-
-	notifier := NewNotifier(config)
-	report := &Report{ID: "INC-test"}
-
-	record := notifier.sendSlackNotification(report)
-	assert.False(t, record.Success)
-	assert.Contains(t, record.Details, "status 500")
-}
-
-func TestNotifier_BuildSlackMessage_Severities(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		severity      string
-		expectedColor string
-	}{
-		{"critical", "#FF0000"},
-		{"high", "#FF8C00"},
-		{"medium", "#FFD700"},
-		{"low", "#00CED1"},
-		{"unknown", "#808080"},
-	}
-
-	notifier := NewNotifier(NotificationConfig{Slack: &SlackConfig{}})
-
-	for _, tc := range tests {
-		t.Run(tc.severity, func(t *testing.T) {
-			report := &Report{
-				ID:        "INC-test",
-				Timestamp: time.Now(),
-				Severity:  tc.severity,
-				Title:     "Test",
-				Type:      "test",
-				Status:    "open",
-			}
-
-			message := notifier.buildSlackMessage(report)
-			attachments := message["attachments"].([]interface{})
-			attachment := attachments[0].(map[string]interface{})
-			assert.Equal(t, tc.expectedColor, attachment["color"])
-		})
-	}
-}
-
 func TestNotifier_SendGitHubNotification_Success(t *testing.T) {
 	t.Parallel()
 
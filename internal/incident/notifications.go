@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -13,17 +12,9 @@ import (
 
 // NotificationConfig holds configuration for incident notifications
 type NotificationConfig struct {
-	Slack  *SlackConfig  `yaml:"slack,omitempty"`
 	GitHub *GitHubConfig `yaml:"github,omitempty"`
 }
 
-// SlackConfig holds Slack webhook configuration
-type SlackConfig struct {
-	WebhookURL string `yaml:"webhook_url"`
-	Channel    string `yaml:"channel,omitempty"`
-	Username   string `yaml:"username,omitempty"`
-}
-
 // GitHubConfig holds GitHub integration configuration
 type GitHubConfig struct {
 	Token      string   `yaml:"token"`            // GitHub personal access token
@@ -46,12 +37,6 @@ func NewNotifier(config NotificationConfig) *Notifier {
 func (n *Notifier) SendNotifications(report *Report) []NotificationRecord {
 	var records []NotificationRecord
 
-	// Send to Slack
-	if n.config.Slack != nil {
-		record := n.sendSlackNotification(report)
-		records = append(records, record)
-	}
-
 	// Send to GitHub
 	if n.config.GitHub != nil {
 		record := n.sendGitHubNotification(report)
@@ -61,145 +46,6 @@ func (n *Notifier) SendNotifications(report *Report) []NotificationRecord {
 	return records
 }
 
-// sendSlackNotification sends a notification to Slack
-func (n *Notifier) sendSlackNotification(report *Report) NotificationRecord {
-	record := NotificationRecord{
-		Channel:   "slack",
-		Timestamp: time.Now(),
-	}
-
-	// Override webhook URL from environment if set
-	webhookURL := n.config.Slack.WebhookURL
-	if envURL := os.Getenv("DSOPS_SLACK_WEBHOOK"); envURL != "" {
-		webhookURL = envURL
-	}
-
-	if webhookURL == "" {
-		record.Success = false
-		record.Details = "No Slack webhook URL configured"
-		return record
-	}
-
-	// Validate URL scheme to prevent SSRF
-	parsedURL, parseErr := url.Parse(webhookURL)
-	if parseErr != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
-		record.Success = false
-		record.Details = "Invalid webhook URL: only http and https schemes are allowed"
-		return record
-	}
-
-	// Build Slack message
-	message := n.buildSlackMessage(report)
-
-	// Send to Slack
-	data, err := json.Marshal(message)
-	if err != nil {
-		record.Success = false
-		record.Details = fmt.Sprintf("Failed to marshal message: %v", err)
-		return record
-	}
-
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(data)) // #nosec G704 -- webhook URL is from trusted config with scheme validated above
-	if err != nil {
-		record.Success = false
-		record.Details = fmt.Sprintf("Failed to send: %v", err)
-		return record
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		record.Success = false
-		record.Details = fmt.Sprintf("Slack returned status %d", resp.StatusCode)
-		return record
-	}
-
-	record.Success = true
-	return record
-}
-
-// buildSlackMessage creates a Slack message from an incident report
-func (n *Notifier) buildSlackMessage(report *Report) map[string]interface{} {
-	// Determine color based on severity
-	color := "#808080" // gray for unknown
-	switch report.Severity {
-	case "critical":
-		color = "#FF0000" // red
-	case "high":
-		color = "#FF8C00" // dark orange
-	case "medium":
-		color = "#FFD700" // gold
-	case "low":
-		color = "#00CED1" // dark turquoise
-	}
-
-	// Build fields
-	var fields []map[string]interface{}
-	fields = append(fields, map[string]interface{}{
-		"title": "Incident ID",
-		"value": report.ID,
-		"short": true,
-	})
-	fields = append(fields, map[string]interface{}{
-		"title": "Severity",
-		"value": report.Severity,
-		"short": true,
-	})
-	fields = append(fields, map[string]interface{}{
-		"title": "Type",
-		"value": report.Type,
-		"short": true,
-	})
-	fields = append(fields, map[string]interface{}{
-		"title": "Status",
-		"value": report.Status,
-		"short": true,
-	})
-
-	// Add affected resources
-	if len(report.AffectedFiles) > 0 {
-		fields = append(fields, map[string]interface{}{
-			"title": "Affected Files",
-			"value": strings.Join(report.AffectedFiles, "\n"),
-			"short": false,
-		})
-	}
-
-	// Add actions required
-	if len(report.ActionsRequired) > 0 {
-		fields = append(fields, map[string]interface{}{
-			"title": "Actions Required",
-			"value": "• " + strings.Join(report.ActionsRequired, "\n• "),
-			"short": false,
-		})
-	}
-
-	attachment := map[string]interface{}{
-		"color":     color,
-		"title":     fmt.Sprintf("🚨 Security Incident: %s", report.Title),
-		"text":      report.Description,
-		"fields":    fields,
-		"footer":    "dsops incident response",
-		"ts":        report.Timestamp.Unix(),
-		"mrkdwn_in": []string{"text", "fields"},
-	}
-
-	message := map[string]interface{}{
-		"attachments": []interface{}{attachment},
-	}
-
-	// Add channel if configured
-	if n.config.Slack.Channel != "" {
-		message["channel"] = n.config.Slack.Channel
-	}
-
-	// Add username if configured
-	if n.config.Slack.Username != "" {
-		message["username"] = n.config.Slack.Username
-	}
-
-	return message
-}
-
 // sendGitHubNotification creates a GitHub issue for the incident
 func (n *Notifier) sendGitHubNotification(report *Report) NotificationRecord {
 	record := NotificationRecord{
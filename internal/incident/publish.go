@@ -0,0 +1,80 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/systmms/dsops/internal/rotation/notifications"
+)
+
+// NewEvent converts an incident report into a notification event so incidents
+// can be published through the same providers as rotation events
+func NewEvent(report *Report, eventType notifications.EventType) notifications.RotationEvent {
+	metadata := map[string]string{
+		"incident_id":   report.ID,
+		"incident_type": report.Type,
+		"title":         report.Title,
+		"status":        report.Status,
+	}
+	if len(report.AffectedSecrets) > 0 {
+		metadata["affected_secrets"] = fmt.Sprintf("%d", len(report.AffectedSecrets))
+	}
+	if len(report.AffectedFiles) > 0 {
+		metadata["affected_files"] = fmt.Sprintf("%d", len(report.AffectedFiles))
+	}
+	if len(report.AffectedCommits) > 0 {
+		metadata["affected_commits"] = fmt.Sprintf("%d", len(report.AffectedCommits))
+	}
+	if len(report.ActionsTaken) > 0 {
+		metadata["last_action"] = report.ActionsTaken[len(report.ActionsTaken)-1]
+	}
+	if report.ResolutionNotes != "" {
+		metadata["resolution_notes"] = report.ResolutionNotes
+	}
+
+	status := notifications.StatusFailure
+	if eventType == notifications.EventTypeIncidentResolved {
+		status = notifications.StatusSuccess
+	}
+
+	return notifications.RotationEvent{
+		Type:        eventType,
+		Service:     report.ID,
+		Environment: report.Details["environment"],
+		Status:      status,
+		Metadata:    metadata,
+		Timestamp:   time.Now(),
+		InitiatedBy: os.Getenv("USER"),
+		Severity:    report.Severity,
+		Summary:     report.Title,
+	}
+}
+
+// Publish delivers an incident event through the notification manager and
+// records the outcome for each provider on the report
+func (m *Manager) Publish(ctx context.Context, notifier *notifications.Manager, report *Report, eventType notifications.EventType) ([]NotificationRecord, error) {
+	results := notifier.Deliver(ctx, NewEvent(report, eventType))
+
+	records := make([]NotificationRecord, 0, len(results))
+	for _, result := range results {
+		record := NotificationRecord{
+			Channel:   result.Provider,
+			Timestamp: time.Now(),
+			Success:   result.Err == nil,
+		}
+		if result.Err != nil {
+			record.Details = result.Err.Error()
+		} else {
+			record.Details = string(eventType)
+		}
+		records = append(records, record)
+
+		if err := m.AddNotification(report, record.Channel, record.Success, record.Details); err != nil {
+			return records, fmt.Errorf("failed to record notification: %w", err)
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,88 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/rotation/notifications"
+)
+
+// recordingProvider is a notifications.NotificationProvider that keeps sent events
+type recordingProvider struct {
+	name   string
+	err    error
+	events []notifications.RotationEvent
+}
+
+func (p *recordingProvider) Name() string { return p.name }
+
+func (p *recordingProvider) Send(ctx context.Context, event notifications.RotationEvent) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func (p *recordingProvider) SupportsEvent(eventType notifications.EventType) bool { return true }
+
+func (p *recordingProvider) Validate(ctx context.Context) error { return nil }
+
+func TestNewEvent(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{
+		ID:              "incident-1",
+		Type:            "secret-leak",
+		Severity:        "critical",
+		Title:           "AWS keys committed",
+		Status:          "open",
+		Details:         map[string]string{"environment": "production"},
+		AffectedSecrets: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"},
+	}
+
+	event := NewEvent(report, notifications.EventTypeIncidentOpened)
+
+	assert.Equal(t, notifications.EventTypeIncidentOpened, event.Type)
+	assert.Equal(t, "incident-1", event.Service)
+	assert.Equal(t, "production", event.Environment)
+	assert.Equal(t, "critical", event.Severity)
+	assert.Equal(t, "AWS keys committed", event.Summary)
+	assert.Equal(t, notifications.StatusFailure, event.Status)
+	assert.Equal(t, "2", event.Metadata["affected_secrets"])
+	assert.NotContains(t, event.Metadata, "AWS_SECRET_ACCESS_KEY", "secret names are counted, not listed")
+
+	resolved := NewEvent(report, notifications.EventTypeIncidentResolved)
+	assert.Equal(t, notifications.StatusSuccess, resolved.Status)
+}
+
+func TestManager_Publish(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewManager(t.TempDir())
+	report, err := mgr.CreateReport("secret-leak", "high", "Leak", "", nil)
+	require.NoError(t, err)
+
+	pager := &recordingProvider{name: "pagerduty"}
+	email := &recordingProvider{name: "email", err: errors.New("smtp unavailable")}
+
+	notifier := notifications.NewManager(0)
+	notifier.RegisterProvider(pager)
+	notifier.RegisterProvider(email)
+
+	records, err := mgr.Publish(context.Background(), notifier, report, notifications.EventTypeIncidentOpened)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.True(t, records[0].Success)
+	assert.False(t, records[1].Success)
+	assert.Equal(t, "smtp unavailable", records[1].Details)
+
+	require.Len(t, pager.events, 1)
+	assert.Equal(t, report.ID, pager.events[0].Service)
+
+	loaded, err := mgr.LoadReport(report.ID)
+	require.NoError(t, err)
+	require.Len(t, loaded.NotificationsSent, 2)
+	assert.Equal(t, "pagerduty", loaded.NotificationsSent[0].Channel)
+	assert.Equal(t, "email", loaded.NotificationsSent[1].Channel)
+}
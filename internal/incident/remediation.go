@@ -0,0 +1,110 @@
+package incident
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/systmms/dsops/internal/config"
+)
+
+// RemediationTarget maps an affected secret recorded in an incident to the
+// dsops.yaml variable that references it
+type RemediationTarget struct {
+	Secret      string `json:"secret"` // Name as recorded in AffectedSecrets
+	Environment string `json:"environment"`
+	Variable    string `json:"variable"`
+	Provider    string `json:"provider"`
+	Key         string `json:"key"`
+	Version     string `json:"version,omitempty"`
+}
+
+// String returns a human readable description of the target
+func (t RemediationTarget) String() string {
+	return fmt.Sprintf("%s/%s (%s:%s)", t.Environment, t.Variable, t.Provider, t.Key)
+}
+
+// MatchAffectedSecrets maps the affected secrets of a report to variables in
+// the given environments. An affected secret matches a variable when it equals
+// the variable name, the provider key, or "<provider>/<key>". Fallback sources
+// are matched like the primary one, and a variable name match targets every
+// source since any of them may hold the leaked value. Literal variables are
+// never matched since there is nothing to rotate. Secrets that match no
+// variable are returned as unmatched.
+func MatchAffectedSecrets(report *Report, envs map[string]config.Environment) ([]RemediationTarget, []string) {
+	var targets []RemediationTarget
+	var unmatched []string
+
+	envNames := make([]string, 0, len(envs))
+	for name := range envs {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	for _, secret := range report.AffectedSecrets {
+		matched := false
+
+		for _, envName := range envNames {
+			env := envs[envName]
+
+			varNames := make([]string, 0, len(env))
+			for name := range env {
+				varNames = append(varNames, name)
+			}
+			sort.Strings(varNames)
+
+			for _, varName := range varNames {
+				for _, source := range env[varName].Sources() {
+					ref := source.ToLegacyProviderRef()
+					if ref.Provider == "" {
+						continue
+					}
+
+					if !secretMatchesVariable(secret, varName, ref) {
+						continue
+					}
+
+					targets = append(targets, RemediationTarget{
+						Secret:      secret,
+						Environment: envName,
+						Variable:    varName,
+						Provider:    ref.Provider,
+						Key:         ref.Key,
+						Version:     ref.Version,
+					})
+					matched = true
+				}
+			}
+		}
+
+		if !matched {
+			unmatched = append(unmatched, secret)
+		}
+	}
+
+	return targets, unmatched
+}
+
+// secretMatchesVariable reports whether an affected secret name refers to the variable
+func secretMatchesVariable(secret, varName string, ref config.ProviderRef) bool {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return false
+	}
+
+	if strings.EqualFold(secret, varName) {
+		return true
+	}
+
+	if ref.Key != "" && secret == ref.Key {
+		return true
+	}
+
+	return secret == ref.Provider+"/"+ref.Key
+}
+
+// AddAction records an action taken in response to the incident
+func (m *Manager) AddAction(report *Report, action string) error {
+	report.AddActionTaken(m.actor, action)
+	return m.UpdateReport(report)
+}
@@ -0,0 +1,136 @@
+package incident
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+)
+
+func TestMatchAffectedSecrets(t *testing.T) {
+	t.Parallel()
+
+	envs := map[string]config.Environment{
+		"production": {
+			"DATABASE_PASSWORD": {From: &config.Reference{Provider: "vault", Key: "prod/db/password"}},
+			"API_KEY":           {From: &config.Reference{Store: "store://aws/prod/api-key"}},
+			"LOG_LEVEL":         {Literal: "info"},
+		},
+		"staging": {
+			"DATABASE_PASSWORD": {From: &config.Reference{Provider: "vault", Key: "staging/db/password"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		secrets       []string
+		wantTargets   []string
+		wantUnmatched []string
+	}{
+		{
+			name:        "variable name matches all environments",
+			secrets:     []string{"DATABASE_PASSWORD"},
+			wantTargets: []string{"production/DATABASE_PASSWORD", "staging/DATABASE_PASSWORD"},
+		},
+		{
+			name:        "variable name is case insensitive",
+			secrets:     []string{"api_key"},
+			wantTargets: []string{"production/API_KEY"},
+		},
+		{
+			name:        "provider key",
+			secrets:     []string{"staging/db/password"},
+			wantTargets: []string{"staging/DATABASE_PASSWORD"},
+		},
+		{
+			name:        "provider and key",
+			secrets:     []string{"aws/prod/api-key"},
+			wantTargets: []string{"production/API_KEY"},
+		},
+		{
+			name:          "literal variables are not rotated",
+			secrets:       []string{"LOG_LEVEL", "UNKNOWN"},
+			wantUnmatched: []string{"LOG_LEVEL", "UNKNOWN"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := &Report{AffectedSecrets: tc.secrets}
+			targets, unmatched := MatchAffectedSecrets(report, envs)
+
+			var got []string
+			for _, target := range targets {
+				got = append(got, target.Environment+"/"+target.Variable)
+			}
+			assert.Equal(t, tc.wantTargets, got)
+			assert.Equal(t, tc.wantUnmatched, unmatched)
+		})
+	}
+}
+
+func TestMatchAffectedSecrets_TargetFields(t *testing.T) {
+	t.Parallel()
+
+	envs := map[string]config.Environment{
+		"production": {
+			"DB_PASS": {From: &config.Reference{Provider: "vault", Key: "db/password", Version: "3"}},
+		},
+	}
+
+	targets, unmatched := MatchAffectedSecrets(&Report{AffectedSecrets: []string{"DB_PASS"}}, envs)
+	require.Len(t, targets, 1)
+	assert.Empty(t, unmatched)
+
+	target := targets[0]
+	assert.Equal(t, "DB_PASS", target.Secret)
+	assert.Equal(t, "vault", target.Provider)
+	assert.Equal(t, "db/password", target.Key)
+	assert.Equal(t, "3", target.Version)
+	assert.Equal(t, "production/DB_PASS (vault:db/password)", target.String())
+}
+
+func TestManager_AddAction(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewManager(t.TempDir())
+	report, err := mgr.CreateReport("secret-leak", "high", "Leak", "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.AddAction(report, "Rotated DB_PASS"))
+
+	loaded, err := mgr.LoadReport(report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Rotated DB_PASS"}, loaded.ActionsTaken)
+}
+
+func TestMatchAffectedSecrets_Fallbacks(t *testing.T) {
+	t.Parallel()
+
+	envs := map[string]config.Environment{
+		"production": {
+			"API_KEY": {
+				From:      &config.Reference{Provider: "vault", Key: "prod/api-key"},
+				Fallbacks: []config.Reference{{Provider: "aws", Key: "backup/api-key"}},
+			},
+		},
+	}
+
+	targets, unmatched := MatchAffectedSecrets(&Report{AffectedSecrets: []string{"aws/backup/api-key"}}, envs)
+	require.Len(t, targets, 1)
+	assert.Empty(t, unmatched)
+	assert.Equal(t, "production/API_KEY (aws:backup/api-key)", targets[0].String())
+
+	targets, _ = MatchAffectedSecrets(&Report{AffectedSecrets: []string{"API_KEY"}}, envs)
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.String())
+	}
+	assert.Equal(t, []string{
+		"production/API_KEY (vault:prod/api-key)",
+		"production/API_KEY (aws:backup/api-key)",
+	}, got, "a variable name match rotates every source")
+}
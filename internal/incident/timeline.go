@@ -0,0 +1,186 @@
+package incident
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/systmms/dsops/internal/duration"
+)
+
+// Timeline event kinds
+const (
+	TimelineCreated       = "created"
+	TimelineStatusChanged = "status_changed"
+	TimelineActionTaken   = "action_taken"
+	TimelineAssigned      = "assigned"
+	TimelineUnassigned    = "unassigned"
+	TimelineDueDateSet    = "due_date_set"
+	TimelineAffectedAdded = "affected_added"
+	TimelineNotification  = "notification"
+	TimelineResolved      = "resolved"
+)
+
+// TimelineEntry records a single change to an incident
+type TimelineEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Event     string    `json:"event"`
+	Message   string    `json:"message"`
+}
+
+// CurrentActor returns the name recorded as the actor for local changes
+func CurrentActor() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	if user := os.Getenv("USERNAME"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+// Record appends an entry to the incident timeline
+func (r *Report) Record(actor, event, message string) {
+	r.Timeline = append(r.Timeline, TimelineEntry{
+		Timestamp: time.Now(),
+		Actor:     actor,
+		Event:     event,
+		Message:   message,
+	})
+}
+
+// SetStatus changes the incident status and records the transition
+func (r *Report) SetStatus(actor, status string) {
+	if status == "" || status == r.Status {
+		return
+	}
+	r.Record(actor, TimelineStatusChanged, fmt.Sprintf("Status changed from %s to %s", r.Status, status))
+	r.Status = status
+}
+
+// AddActionTaken records an action taken in response to the incident
+func (r *Report) AddActionTaken(actor, action string) {
+	r.ActionsTaken = append(r.ActionsTaken, action)
+	r.Record(actor, TimelineActionTaken, action)
+}
+
+// AddAffected records newly discovered affected resources. Kind is one of
+// files, secrets or commits.
+func (r *Report) AddAffected(actor, kind string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	switch kind {
+	case "files":
+		r.AffectedFiles = append(r.AffectedFiles, items...)
+	case "secrets":
+		r.AffectedSecrets = append(r.AffectedSecrets, items...)
+	case "commits":
+		r.AffectedCommits = append(r.AffectedCommits, items...)
+	default:
+		return
+	}
+
+	r.Record(actor, TimelineAffectedAdded, fmt.Sprintf("Added %d affected %s", len(items), kind))
+}
+
+// Assign adds assignees to the incident, ignoring people already assigned
+func (r *Report) Assign(actor string, assignees ...string) {
+	for _, assignee := range assignees {
+		assignee = strings.TrimSpace(assignee)
+		if assignee == "" || r.IsAssigned(assignee) {
+			continue
+		}
+		r.Assignees = append(r.Assignees, assignee)
+		r.Record(actor, TimelineAssigned, fmt.Sprintf("Assigned %s", assignee))
+	}
+}
+
+// Unassign removes assignees from the incident
+func (r *Report) Unassign(actor string, assignees ...string) {
+	for _, assignee := range assignees {
+		for i, existing := range r.Assignees {
+			if existing == assignee {
+				r.Assignees = append(r.Assignees[:i], r.Assignees[i+1:]...)
+				r.Record(actor, TimelineUnassigned, fmt.Sprintf("Unassigned %s", assignee))
+				break
+			}
+		}
+	}
+}
+
+// IsAssigned returns true if the person is assigned to the incident
+func (r *Report) IsAssigned(assignee string) bool {
+	for _, existing := range r.Assignees {
+		if existing == assignee {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDueDate sets the due date for a required action
+func (r *Report) SetDueDate(actor, action string, due time.Time) error {
+	found := false
+	for _, required := range r.ActionsRequired {
+		if required == action {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("action is not required by incident %s: %s", r.ID, action)
+	}
+
+	if r.DueDates == nil {
+		r.DueDates = make(map[string]time.Time)
+	}
+	r.DueDates[action] = due
+	r.Record(actor, TimelineDueDateSet, fmt.Sprintf("Due %s: %s", due.Format(time.RFC3339), action))
+
+	return nil
+}
+
+// OverdueActions returns required actions whose due date has passed, sorted
+// by due date. Resolved incidents have no overdue actions.
+func (r *Report) OverdueActions(now time.Time) []string {
+	if r.Status == "resolved" {
+		return nil
+	}
+
+	var overdue []string
+	for action, due := range r.DueDates {
+		if due.Before(now) {
+			overdue = append(overdue, action)
+		}
+	}
+	sort.Slice(overdue, func(i, j int) bool {
+		return r.DueDates[overdue[i]].Before(r.DueDates[overdue[j]])
+	})
+
+	return overdue
+}
+
+// Age returns how long ago the incident was created
+func (r *Report) Age(now time.Time) time.Duration {
+	return now.Sub(r.Timestamp)
+}
+
+// ParseDueDate parses a due date given as YYYY-MM-DD, RFC3339, or an age
+// relative to now such as "48h" or "3d"
+func ParseDueDate(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := duration.Parse(value); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid due date: %s (use YYYY-MM-DD, RFC3339, or a duration like 48h or 3d)", value)
+}
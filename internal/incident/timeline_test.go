@@ -0,0 +1,144 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Timeline(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewManager(t.TempDir())
+	mgr.SetActor("alice")
+
+	report, err := mgr.CreateReport("secret-leak", "high", "Leak", "", nil)
+	require.NoError(t, err)
+	report.ActionsRequired = []string{"Rotate the credential"}
+
+	report.SetStatus("bob", "investigating")
+	report.SetStatus("bob", "investigating") // unchanged status is not recorded
+	report.Assign("bob", "carol", "dave", "carol")
+	report.Unassign("bob", "dave")
+	report.AddAffected("bob", "secrets", []string{"DB_PASSWORD"})
+	report.AddActionTaken("bob", "Rotated DB_PASSWORD")
+	require.NoError(t, mgr.UpdateReport(report))
+	require.NoError(t, mgr.ResolveReport(report, "Credential rotated"))
+
+	loaded, err := mgr.LoadReport(report.ID)
+	require.NoError(t, err)
+
+	var events []string
+	for _, entry := range loaded.Timeline {
+		events = append(events, entry.Event)
+		assert.False(t, entry.Timestamp.IsZero())
+	}
+	assert.Equal(t, []string{
+		TimelineCreated,
+		TimelineStatusChanged,
+		TimelineAssigned,
+		TimelineAssigned,
+		TimelineUnassigned,
+		TimelineAffectedAdded,
+		TimelineActionTaken,
+		TimelineStatusChanged,
+		TimelineResolved,
+	}, events)
+
+	assert.Equal(t, "alice", loaded.Timeline[0].Actor)
+	assert.Equal(t, "bob", loaded.Timeline[1].Actor)
+	assert.Equal(t, "Status changed from open to investigating", loaded.Timeline[1].Message)
+	assert.Equal(t, []string{"carol"}, loaded.Assignees)
+	assert.Equal(t, "resolved", loaded.Status)
+}
+
+func TestReport_DueDates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC)
+	report := &Report{
+		ID:              "INC-1",
+		Status:          "open",
+		ActionsRequired: []string{"Rotate", "Review logs", "Purge history"},
+	}
+
+	require.NoError(t, report.SetDueDate("alice", "Review logs", now.Add(-time.Hour)))
+	require.NoError(t, report.SetDueDate("alice", "Rotate", now.Add(-2*time.Hour)))
+	require.NoError(t, report.SetDueDate("alice", "Purge history", now.Add(time.Hour)))
+	assert.Error(t, report.SetDueDate("alice", "Not required", now))
+
+	assert.Equal(t, []string{"Rotate", "Review logs"}, report.OverdueActions(now))
+
+	report.Status = "resolved"
+	assert.Empty(t, report.OverdueActions(now))
+}
+
+func TestParseDueDate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC)
+
+	due, err := ParseDueDate("48h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(48*time.Hour), due)
+
+	due, err = ParseDueDate("2025-01-20T09:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), due)
+
+	due, err = ParseDueDate("2025-01-20", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-20", due.Format("2006-01-02"))
+
+	_, err = ParseDueDate("next week", now)
+	assert.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 1, 18, 10, 0, 0, 0, time.UTC)
+	resolved := created.Add(3 * time.Hour)
+	report := &Report{
+		ID:              "INC-1",
+		Timestamp:       created,
+		Type:            "secret-leak",
+		Severity:        "critical",
+		Title:           "Keys | pasted in chat",
+		Status:          "resolved",
+		ResolvedAt:      &resolved,
+		AffectedSecrets: []string{"AWS_SECRET_ACCESS_KEY"},
+		ActionsRequired: []string{"Rotate the credential"},
+		ActionsTaken:    []string{"Rotated key"},
+		Assignees:       []string{"alice"},
+		DueDates:        map[string]time.Time{"Rotate the credential": created.Add(time.Hour)},
+		Timeline: []TimelineEntry{
+			{Timestamp: created, Actor: "alice", Event: TimelineCreated, Message: "Reported secret-leak incident (critical)"},
+		},
+		ResolutionNotes: "Key rotated and history purged",
+	}
+
+	var md bytes.Buffer
+	require.NoError(t, Export(&md, report, ExportMarkdown))
+	out := md.String()
+	assert.Contains(t, out, "# Incident INC-1: Keys \\| pasted in chat")
+	assert.Contains(t, out, "| Time to resolve | 3h0m0s |")
+	assert.Contains(t, out, "| Assignees | alice |")
+	assert.Contains(t, out, "- [ ] Rotate the credential (due 2025-01-18T11:00:00Z)")
+	assert.Contains(t, out, "- [x] Rotated key")
+	assert.Contains(t, out, "| alice | created | Reported secret-leak incident (critical) |")
+	assert.Contains(t, out, "## Resolution")
+
+	var js bytes.Buffer
+	require.NoError(t, Export(&js, report, ExportJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, report.ID, decoded.ID)
+	assert.Len(t, decoded.Timeline, 1)
+
+	assert.Error(t, Export(&js, report, "pdf"))
+}
@@ -0,0 +1,55 @@
+// Package jsonfield selects a field from a JSON value by a dotted path, the
+// "#field" part of a reference into key/value stores that hold JSON documents.
+package jsonfield
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Extract selects a dotted path (".a.b" or "a.b") from a JSON value. Numeric
+// parts index into arrays. Strings are returned as-is, null as an empty
+// string, and objects, arrays and other values as JSON.
+func Extract(value, path string) (string, error) {
+	var current interface{}
+	if err := json.Unmarshal([]byte(value), &current); err != nil {
+		return "", fmt.Errorf("value is not JSON: %w", err)
+	}
+
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if part == "" {
+			continue
+		}
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return "", fmt.Errorf("field %s not found", part)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return "", fmt.Errorf("invalid array index: %s", part)
+			}
+			current = v[index]
+		default:
+			return "", fmt.Errorf("cannot traverse path at: %s", part)
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		out, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal field: %w", err)
+		}
+		return string(out), nil
+	}
+}
@@ -0,0 +1,42 @@
+package jsonfield
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	doc := `{"db":{"hosts":["a","b"],"password":"s3cret","port":5432,"tls":null}}`
+	tests := map[string]string{
+		".db.password": "s3cret",
+		"db.password":  "s3cret",
+		"db.port":      "5432",
+		"db.hosts.1":   "b",
+		"db.hosts":     `["a","b"]`,
+		"db.tls":       "",
+		"":             doc,
+	}
+	for path, want := range tests {
+		got, err := Extract(doc, path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, got, path)
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	t.Parallel()
+
+	doc := `{"db":{"password":"s3cret","hosts":["a"]}}`
+	_, err := Extract("plain text", "a")
+	assert.ErrorContains(t, err, "value is not JSON")
+	_, err = Extract(doc, "db.user")
+	assert.EqualError(t, err, "field user not found")
+	_, err = Extract(doc, "db.hosts.3")
+	assert.EqualError(t, err, "invalid array index: 3")
+	_, err = Extract(doc, "db.password.length")
+	assert.EqualError(t, err, "cannot traverse path at: length")
+}
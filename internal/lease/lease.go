@@ -0,0 +1,275 @@
+// Package lease keeps leased secrets, such as Vault dynamic database
+// credentials, valid while dsops exec runs a command, and revokes them when
+// the command exits.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/systmms/dsops/internal/audit"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+const (
+	// DefaultRetryInterval is how long to wait after a failed renewal
+	DefaultRetryInterval = 10 * time.Second
+
+	// DefaultRevokeTimeout bounds revoking every lease on exit
+	DefaultRevokeTimeout = 15 * time.Second
+)
+
+// Keeper renews and revokes the leases issued while resolving an environment.
+// Start it before resolving so that leases issued by a resolution that then
+// fails are still revoked by Stop.
+type Keeper struct {
+	logger        *logging.Logger
+	auditPath     string
+	managers      []manager
+	retryInterval time.Duration
+
+	mu      sync.Mutex
+	held    []held
+	seen    map[string]bool
+	ctx     context.Context
+	stopped bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// manager is a provider that issues leases and the store name it is
+// registered under
+type manager struct {
+	store   string
+	manager provider.LeaseManager
+}
+
+// held is a lease and the provider that issued it
+type held struct {
+	store   string
+	manager provider.LeaseManager
+	lease   provider.Lease
+}
+
+// NewKeeper tracks the leases of providers that implement
+// provider.LeaseManager, starting with the leases they already hold. Lease
+// events are appended to the JSON lines audit log at auditPath; an empty
+// path disables auditing.
+func NewKeeper(logger *logging.Logger, auditPath string, providers map[string]provider.Provider) *Keeper {
+	k := &Keeper{
+		logger:        logger,
+		auditPath:     auditPath,
+		retryInterval: DefaultRetryInterval,
+		seen:          make(map[string]bool),
+	}
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if lm, ok := providers[name].(provider.LeaseManager); ok {
+			k.managers = append(k.managers, manager{store: name, manager: lm})
+		}
+	}
+
+	k.collect()
+	return k
+}
+
+// Leases returns the leases being kept
+func (k *Keeper) Leases() []provider.Lease {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	leases := make([]provider.Lease, len(k.held))
+	for i, h := range k.held {
+		leases[i] = h.lease
+	}
+	return leases
+}
+
+// Start records the leases in the audit log and renews each renewable lease
+// in the background until Stop is called. Leases issued later are picked up
+// by Collect.
+func (k *Keeper) Start(ctx context.Context) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.ctx, k.cancel = context.WithCancel(ctx)
+	for _, h := range k.held {
+		k.keep(h)
+	}
+}
+
+// Collect picks up leases issued since the Keeper was created or last
+// collected and, once started, begins keeping them
+func (k *Keeper) Collect() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, h := range k.collect() {
+		if k.ctx != nil && !k.stopped {
+			k.keep(h)
+		}
+	}
+}
+
+// collect adds the leases it has not seen yet to held and returns them.
+// Callers hold k.mu, except NewKeeper.
+func (k *Keeper) collect() []held {
+	var added []held
+	for _, m := range k.managers {
+		for _, lease := range m.manager.Leases() {
+			if k.seen[lease.ID] {
+				continue
+			}
+			k.seen[lease.ID] = true
+			h := held{store: m.store, manager: m.manager, lease: lease}
+			k.held = append(k.held, h)
+			added = append(added, h)
+		}
+	}
+	return added
+}
+
+// keep audits a lease and renews it in the background when it is renewable.
+// Callers hold k.mu.
+func (k *Keeper) keep(h held) {
+	k.audit("lease_issued", h.store, h.lease, "")
+	k.logger.Info("Leased %s from %s (lease %s, ttl %s)", h.lease.Path, h.store, h.lease.ID, h.lease.Duration)
+
+	if !h.lease.Renewable || h.lease.Duration <= 0 {
+		k.logger.Debug("Lease %s is not renewable and expires at %s", h.lease.ID, h.lease.ExpiresAt().Format(time.RFC3339))
+		return
+	}
+
+	ctx := k.ctx
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		k.renew(ctx, h)
+	}()
+}
+
+// Stop ends renewal and revokes every lease, including leases issued since
+// the last Collect. Revocation failures are logged and returned; the
+// credentials then stay valid until their TTL runs out.
+func (k *Keeper) Stop() []error {
+	k.mu.Lock()
+	k.stopped = true
+	if k.cancel != nil {
+		k.cancel()
+	}
+	for _, h := range k.collect() {
+		k.audit("lease_issued", h.store, h.lease, "")
+	}
+	leases := append([]held(nil), k.held...)
+	k.mu.Unlock()
+
+	k.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRevokeTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, h := range leases {
+		if err := h.manager.RevokeLease(ctx, h.lease.ID); err != nil {
+			k.logger.Warn("Failed to revoke lease %s from %s: %v", h.lease.ID, h.store, err)
+			k.audit("lease_revoke_failed", h.store, h.lease, err.Error())
+			errs = append(errs, fmt.Errorf("revoke %s: %w", h.lease.ID, err))
+			continue
+		}
+		k.logger.Debug("Revoked lease %s from %s", h.lease.ID, h.store)
+		k.audit("lease_revoked", h.store, h.lease, "")
+	}
+	return errs
+}
+
+// renew extends a lease when two thirds of its duration has passed, asking
+// each time for the duration it was first issued with
+func (k *Keeper) renew(ctx context.Context, h held) {
+	lease := h.lease
+	increment := lease.Duration
+
+	for {
+		wait := time.Until(lease.IssuedAt.Add(lease.Duration * 2 / 3))
+		if !k.sleep(ctx, wait) {
+			return
+		}
+
+		renewed, err := h.manager.RenewLease(ctx, lease.ID, increment)
+		for err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if time.Now().After(lease.ExpiresAt()) {
+				k.logger.Warn("Lease %s from %s expired; its credentials no longer work", lease.ID, h.store)
+				k.audit("lease_expired", h.store, lease, err.Error())
+				return
+			}
+			k.logger.Warn("Failed to renew lease %s from %s, retrying: %v", lease.ID, h.store, err)
+			if !k.sleep(ctx, k.retryInterval) {
+				return
+			}
+			renewed, err = h.manager.RenewLease(ctx, lease.ID, increment)
+		}
+
+		k.audit("lease_renewed", h.store, renewed, "")
+		k.logger.Debug("Renewed lease %s from %s (ttl %s)", renewed.ID, h.store, renewed.Duration)
+
+		if renewed.Duration <= 0 || !renewed.Renewable {
+			k.logger.Warn("Lease %s from %s reached its maximum TTL and expires at %s",
+				renewed.ID, h.store, renewed.ExpiresAt().Format(time.RFC3339))
+			return
+		}
+		lease = renewed
+	}
+}
+
+// sleep waits for d and reports whether the context is still live
+func (k *Keeper) sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// audit appends a lease event to the audit log. Entries carry lease IDs and
+// paths, never secret values.
+func (k *Keeper) audit(action, store string, lease provider.Lease, detail string) {
+	if k.auditPath == "" {
+		return
+	}
+
+	entry := map[string]interface{}{
+		"timestamp":   time.Now().Format(time.RFC3339),
+		"action":      action,
+		"store":       store,
+		"path":        lease.Path,
+		"lease_id":    lease.ID,
+		"ttl_seconds": int(lease.Duration.Seconds()),
+	}
+	if detail != "" {
+		entry["error"] = detail
+	}
+
+	if err := audit.Append(k.auditPath, entry); err != nil {
+		k.logger.Debug("Failed to write audit log: %v", err)
+	}
+}
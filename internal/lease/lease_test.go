@@ -0,0 +1,224 @@
+package lease
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+// leasingProvider is a fake provider that hands out leases
+type leasingProvider struct {
+	*fakes.FakeProvider
+
+	mu        sync.Mutex
+	leases    []provider.Lease
+	renewals  int
+	renewErr  error
+	revoked   []string
+	revokeErr error
+}
+
+func (l *leasingProvider) Leases() []provider.Lease {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]provider.Lease(nil), l.leases...)
+}
+
+func (l *leasingProvider) issue(lease provider.Lease) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.leases = append(l.leases, lease)
+}
+
+func (l *leasingProvider) RenewLease(_ context.Context, leaseID string, increment time.Duration) (provider.Lease, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.renewals++
+	if l.renewErr != nil {
+		return provider.Lease{}, l.renewErr
+	}
+	return provider.Lease{ID: leaseID, Provider: "vault", Path: "database/creds/app", Duration: increment, Renewable: true, IssuedAt: time.Now()}, nil
+}
+
+func (l *leasingProvider) RevokeLease(_ context.Context, leaseID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.revokeErr != nil {
+		return l.revokeErr
+	}
+	l.revoked = append(l.revoked, leaseID)
+	return nil
+}
+
+func (l *leasingProvider) renewCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.renewals
+}
+
+func newLeasingProvider(duration time.Duration) *leasingProvider {
+	return &leasingProvider{
+		FakeProvider: fakes.NewFakeProvider("vault"),
+		leases: []provider.Lease{{
+			ID:        "database/creds/app/abc123",
+			Provider:  "vault",
+			Path:      "database/creds/app",
+			Duration:  duration,
+			Renewable: true,
+			IssuedAt:  time.Now(),
+		}},
+	}
+}
+
+func readAudit(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestKeeper_RenewsAndRevokes(t *testing.T) {
+	t.Parallel()
+
+	vault := newLeasingProvider(150 * time.Millisecond)
+	auditPath := filepath.Join(t.TempDir(), ".dsops", "audit.log")
+	keeper := NewKeeper(logging.New(false, true), auditPath, map[string]provider.Provider{
+		"vault":  vault,
+		"static": fakes.NewFakeProvider("static"),
+	})
+	require.Len(t, keeper.Leases(), 1)
+
+	keeper.Start(context.Background())
+	assert.Eventually(t, func() bool { return vault.renewCount() >= 2 }, 2*time.Second, 10*time.Millisecond)
+
+	assert.Empty(t, keeper.Stop())
+	assert.Equal(t, []string{"database/creds/app/abc123"}, vault.revoked)
+
+	entries := readAudit(t, auditPath)
+	require.GreaterOrEqual(t, len(entries), 4)
+	assert.Equal(t, "lease_issued", entries[0]["action"])
+	assert.Equal(t, "lease_renewed", entries[1]["action"])
+	assert.Equal(t, "lease_revoked", entries[len(entries)-1]["action"])
+	for _, entry := range entries {
+		assert.Equal(t, "database/creds/app/abc123", entry["lease_id"])
+		assert.Equal(t, "vault", entry["store"])
+		assert.NotContains(t, entry, "value")
+	}
+}
+
+func TestKeeper_RenewFailureRetriesUntilExpiry(t *testing.T) {
+	t.Parallel()
+
+	vault := newLeasingProvider(90 * time.Millisecond)
+	vault.renewErr = errors.New("permission denied")
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+
+	keeper := NewKeeper(logging.New(false, true), auditPath, map[string]provider.Provider{"vault": vault})
+	keeper.retryInterval = 20 * time.Millisecond
+	keeper.Start(context.Background())
+
+	assert.Eventually(t, func() bool {
+		for _, entry := range readAudit(t, auditPath) {
+			if entry["action"] == "lease_expired" {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Greater(t, vault.renewCount(), 1, "failed renewals are retried")
+
+	assert.Empty(t, keeper.Stop())
+}
+
+func TestKeeper_RevokeFailure(t *testing.T) {
+	t.Parallel()
+
+	vault := newLeasingProvider(time.Hour)
+	vault.revokeErr = errors.New("connection refused")
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+
+	keeper := NewKeeper(logging.New(false, true), auditPath, map[string]provider.Provider{"vault": vault})
+	keeper.Start(context.Background())
+
+	errs := keeper.Stop()
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "connection refused")
+
+	entries := readAudit(t, auditPath)
+	assert.Equal(t, "lease_revoke_failed", entries[len(entries)-1]["action"])
+	assert.Equal(t, 0, vault.renewCount(), "an hour-long lease is not renewed yet")
+}
+
+func TestKeeper_NoLeases(t *testing.T) {
+	t.Parallel()
+
+	keeper := NewKeeper(logging.New(false, true), "", map[string]provider.Provider{"static": fakes.NewFakeProvider("static")})
+	assert.Empty(t, keeper.Leases())
+	assert.Empty(t, keeper.Stop())
+}
+
+func TestKeeper_CollectsLeasesIssuedAfterStart(t *testing.T) {
+	t.Parallel()
+
+	vault := newLeasingProvider(time.Hour)
+	later := provider.Lease{ID: "database/creds/app/def456", Provider: "vault", Path: "database/creds/app", Duration: time.Hour, IssuedAt: time.Now()}
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+
+	keeper := NewKeeper(logging.New(false, true), auditPath, map[string]provider.Provider{"vault": vault})
+	keeper.Start(context.Background())
+	require.Len(t, keeper.Leases(), 1)
+
+	vault.issue(later)
+	keeper.Collect()
+	keeper.Collect()
+	require.Len(t, keeper.Leases(), 2)
+
+	assert.Empty(t, keeper.Stop())
+	assert.Equal(t, []string{"database/creds/app/abc123", "database/creds/app/def456"}, vault.revoked)
+
+	var issued []interface{}
+	for _, entry := range readAudit(t, auditPath) {
+		if entry["action"] == "lease_issued" {
+			issued = append(issued, entry["lease_id"])
+		}
+	}
+	assert.Equal(t, []interface{}{"database/creds/app/abc123", "database/creds/app/def456"}, issued)
+}
+
+func TestKeeper_StopRevokesUncollectedLeases(t *testing.T) {
+	t.Parallel()
+
+	// A resolution that fails part way has issued leases nobody collected
+	vault := &leasingProvider{FakeProvider: fakes.NewFakeProvider("vault")}
+	keeper := NewKeeper(logging.New(false, true), "", map[string]provider.Provider{"vault": vault})
+	keeper.Start(context.Background())
+	assert.Empty(t, keeper.Leases())
+
+	vault.issue(provider.Lease{ID: "database/creds/app/abc123", Duration: time.Hour, IssuedAt: time.Now()})
+
+	assert.Empty(t, keeper.Stop())
+	assert.Equal(t, []string{"database/creds/app/abc123"}, vault.revoked)
+}
@@ -19,6 +19,7 @@ type PolicyConfig struct {
 	SecretComplexity  *ComplexityPolicy `yaml:"secret_complexity,omitempty"`  // Secret value complexity requirements
 	ForbiddenPatterns []string          `yaml:"forbidden_patterns,omitempty"` // Regex patterns that secrets must not match
 	RequiredPatterns  []string          `yaml:"required_patterns,omitempty"`  // Regex patterns that secrets must match
+	MaxSecretAge      string            `yaml:"max_secret_age,omitempty"`     // Secrets older than this are reported as stale (e.g. 90d)
 
 	// Environment policies
 	EnvironmentRules map[string]*EnvironmentPolicy `yaml:"environment_rules,omitempty"` // Per-environment restrictions
@@ -46,6 +47,8 @@ type EnvironmentPolicy struct {
 	BlockedProviders []string `yaml:"blocked_providers,omitempty"` // Environment-specific provider blacklist
 	RequireApproval  bool     `yaml:"require_approval,omitempty"`  // Require manual approval for this env
 	MaxSecrets       int      `yaml:"max_secrets,omitempty"`       // Maximum number of secrets allowed
+	ReadOnly         bool     `yaml:"read_only,omitempty"`         // Block dsops set/delete/sync for this env
+	MaxSecretAge     string   `yaml:"max_secret_age,omitempty"`    // Overrides the global max_secret_age
 }
 
 // OutputPolicy defines file output restrictions
@@ -232,6 +235,46 @@ func (pe *PolicyEnforcer) ValidateEnvironmentSecretCount(envName string, secretC
 	return nil
 }
 
+// ValidateWrite checks whether secrets may be written to or deleted from a
+// provider type in the given environment
+func (pe *PolicyEnforcer) ValidateWrite(envName, providerType string) error {
+	if err := pe.ValidateProviderType(providerType); err != nil {
+		return err
+	}
+
+	if envName == "" {
+		return nil
+	}
+
+	if err := pe.ValidateEnvironmentProvider(envName, providerType); err != nil {
+		return err
+	}
+
+	if envPolicy, exists := pe.config.EnvironmentRules[envName]; exists && envPolicy.ReadOnly {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Environment '%s' is read-only by policy", envName),
+			Suggestion: "Write secrets through your approved change process or update the environment policy",
+		}
+	}
+
+	return nil
+}
+
+// RequiresApproval returns whether changes to an environment need manual approval
+func (pe *PolicyEnforcer) RequiresApproval(envName string) bool {
+	envPolicy, exists := pe.config.EnvironmentRules[envName]
+	return exists && envPolicy.RequireApproval
+}
+
+// MaxSecretAge returns the configured maximum secret age for an
+// environment, falling back to the global setting. Empty means no limit.
+func (pe *PolicyEnforcer) MaxSecretAge(envName string) string {
+	if envPolicy, exists := pe.config.EnvironmentRules[envName]; exists && envPolicy.MaxSecretAge != "" {
+		return envPolicy.MaxSecretAge
+	}
+	return pe.config.MaxSecretAge
+}
+
 // ShouldAudit returns whether an operation should be audited
 func (pe *PolicyEnforcer) ShouldAudit() bool {
 	return pe.config.AuditLogging != nil && pe.config.AuditLogging.Enabled
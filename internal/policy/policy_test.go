@@ -459,6 +459,80 @@ func TestPolicyEnforcer_ValidateEnvironmentSecretCount(t *testing.T) {
 	})
 }
 
+func TestPolicyEnforcer_ValidateWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allows_write_when_no_restrictions", func(t *testing.T) {
+		t.Parallel()
+		enforcer := NewPolicyEnforcer(nil)
+		err := enforcer.ValidateWrite("production", "vault")
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects_globally_blocked_provider", func(t *testing.T) {
+		t.Parallel()
+		config := &PolicyConfig{
+			BlockedProviders: []string{"literal"},
+		}
+		enforcer := NewPolicyEnforcer(config)
+
+		err := enforcer.ValidateWrite("", "literal")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "blocked by policy")
+	})
+
+	t.Run("rejects_provider_blocked_for_environment", func(t *testing.T) {
+		t.Parallel()
+		config := &PolicyConfig{
+			EnvironmentRules: map[string]*EnvironmentPolicy{
+				"production": {AllowedProviders: []string{"vault"}},
+			},
+		}
+		enforcer := NewPolicyEnforcer(config)
+
+		err := enforcer.ValidateWrite("production", "pass")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not allowed for environment 'production'")
+
+		err = enforcer.ValidateWrite("production", "vault")
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects_read_only_environment", func(t *testing.T) {
+		t.Parallel()
+		config := &PolicyConfig{
+			EnvironmentRules: map[string]*EnvironmentPolicy{
+				"production": {ReadOnly: true},
+			},
+		}
+		enforcer := NewPolicyEnforcer(config)
+
+		err := enforcer.ValidateWrite("production", "vault")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+
+		err = enforcer.ValidateWrite("staging", "vault")
+		assert.NoError(t, err)
+	})
+}
+
+func TestPolicyEnforcer_RequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	config := &PolicyConfig{
+		EnvironmentRules: map[string]*EnvironmentPolicy{
+			"production": {RequireApproval: true},
+			"staging":    {MaxSecrets: 10},
+		},
+	}
+	enforcer := NewPolicyEnforcer(config)
+
+	assert.True(t, enforcer.RequiresApproval("production"))
+	assert.False(t, enforcer.RequiresApproval("staging"))
+	assert.False(t, enforcer.RequiresApproval("development"))
+	assert.False(t, NewPolicyEnforcer(nil).RequiresApproval("production"))
+}
+
 func TestPolicyEnforcer_ShouldAudit(t *testing.T) {
 	t.Parallel()
 
@@ -544,12 +618,14 @@ func TestEnvironmentPolicy_struct(t *testing.T) {
 		BlockedProviders: []string{"literal"},
 		RequireApproval:  true,
 		MaxSecrets:       100,
+		ReadOnly:         true,
 	}
 
 	assert.Equal(t, []string{"vault"}, policy.AllowedProviders)
 	assert.Equal(t, []string{"literal"}, policy.BlockedProviders)
 	assert.True(t, policy.RequireApproval)
 	assert.Equal(t, 100, policy.MaxSecrets)
+	assert.True(t, policy.ReadOnly)
 }
 
 func TestOutputPolicy_struct(t *testing.T) {
@@ -137,6 +137,32 @@ func (p *AkeylessProvider) Describe(ctx context.Context, ref provider.Reference)
 	}, nil
 }
 
+// ListSecrets lists items under the folder containing opts.Prefix, e.g.
+// "/prod/" or "/prod/db-". Returned keys are full item paths.
+func (p *AkeylessProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	folder := "/"
+	if idx := strings.LastIndex(opts.Prefix, "/"); idx > 0 {
+		folder = opts.Prefix[:idx]
+	}
+
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return provider.ListResult{}, fmt.Errorf("failed to authenticate with akeyless: %w", err)
+	}
+
+	paths, err := p.client.ListItems(ctx, token, folder)
+	if err != nil {
+		return provider.ListResult{}, fmt.Errorf("failed to list akeyless items: %w", err)
+	}
+
+	secrets := make([]provider.SecretInfo, len(paths))
+	for i, path := range paths {
+		secrets[i] = provider.SecretInfo{Key: path}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
 // Capabilities returns the provider's supported features
 func (p *AkeylessProvider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
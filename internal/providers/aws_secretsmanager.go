@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,6 +24,9 @@ type SecretsManagerClientAPI interface {
 	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
 	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
 	UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error)
 }
 
 // AWSSecretsManagerProvider implements the provider interface for AWS Secrets Manager
@@ -195,7 +199,7 @@ func (aws *AWSSecretsManagerProvider) Describe(ctx context.Context, ref provider
 		return provider.Metadata{}, aws.handleError(err, secretName)
 	}
 
-	return provider.Metadata{
+	metadata := provider.Metadata{
 		Exists:    true,
 		Version:   aws.getLatestVersionId(result),
 		UpdatedAt: aws.getLastChangedDate(result),
@@ -207,7 +211,31 @@ func (aws *AWSSecretsManagerProvider) Describe(ctx context.Context, ref provider
 			"kms_key_id":      aws.getKMSKeyId(result),
 			"replica_regions": strings.Join(aws.getReplicaRegions(result), ","),
 		},
-	}, nil
+	}
+
+	if ref.Version != "" && ref.Version != "latest" {
+		metadata.Version = ref.Version
+		metadata.Deprecated = isDeprecatedVersion(result.VersionIdsToStages, ref.Version)
+	}
+
+	return metadata, nil
+}
+
+// isDeprecatedVersion reports whether a pinned version ID or staging label
+// no longer has staging labels attached. Secrets Manager treats unlabelled
+// versions as deprecated and removes them eventually.
+func isDeprecatedVersion(versionsToStages map[string][]string, version string) bool {
+	if isVersionId(version) {
+		return len(versionsToStages[version]) == 0
+	}
+	for _, stages := range versionsToStages {
+		for _, stage := range stages {
+			if stage == version {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 // Capabilities returns AWS Secrets Manager provider capabilities
@@ -395,30 +423,62 @@ func (aws *AWSSecretsManagerProvider) CreateNewVersion(ctx context.Context, ref
 	return "latest", nil
 }
 
-// DeprecateVersion marks an old version as deprecated by removing it from AWSCURRENT stage
+// DeprecateVersion removes every staging label except AWSCURRENT from a
+// version. Versions without labels are deprecated and cleaned up by AWS.
 func (aws *AWSSecretsManagerProvider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
 	secretName := ref.Key
 
-	// AWS Secrets Manager automatically moves the old version to AWSPENDING when a new version
-	// becomes AWSCURRENT. We can optionally remove AWSPENDING stage to fully deprecate.
+	result, err := aws.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: &secretName})
+	if err != nil {
+		return fmt.Errorf("failed to describe secret: %w", err)
+	}
+
+	for _, stage := range result.VersionIdsToStages[version] {
+		if stage == "AWSCURRENT" {
+			// AWS only moves AWSCURRENT by promoting another version
+			continue
+		}
 
-	input := &secretsmanager.UpdateSecretVersionStageInput{
-		SecretId:            &secretName,
-		VersionStage:        stringPtr("AWSPENDING"),
-		RemoveFromVersionId: &version,
+		input := &secretsmanager.UpdateSecretVersionStageInput{
+			SecretId:            &secretName,
+			VersionStage:        stringPtr(stage),
+			RemoveFromVersionId: &version,
+		}
+		if _, err := aws.client.UpdateSecretVersionStage(ctx, input); err != nil {
+			// Don't fail if the version is already deprecated or doesn't exist
+			if strings.Contains(err.Error(), "InvalidVersionStage") ||
+				strings.Contains(err.Error(), "InvalidParameterValue") {
+				continue
+			}
+			return fmt.Errorf("failed to deprecate secret version: %w", err)
+		}
 	}
 
-	_, err := aws.client.UpdateSecretVersionStage(ctx, input)
+	return nil
+}
+
+// ListVersions returns the versions of a secret that still carry a staging
+// label, such as AWSCURRENT or AWSPREVIOUS
+func (aws *AWSSecretsManagerProvider) ListVersions(ctx context.Context, ref provider.Reference) ([]string, error) {
+	secretName := ref.Key
+
+	result, err := aws.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: &secretName})
 	if err != nil {
-		// Don't fail if the version is already deprecated or doesn't exist
-		if strings.Contains(err.Error(), "InvalidVersionStage") ||
-			strings.Contains(err.Error(), "InvalidParameterValue") {
-			return nil
+		if isNotFoundError(err) {
+			return nil, &provider.NotFoundError{Provider: aws.name, Key: ref.Key}
 		}
-		return fmt.Errorf("failed to deprecate secret version: %w", err)
+		return nil, fmt.Errorf("failed to describe secret: %w", err)
 	}
 
-	return nil
+	var versions []string
+	for version, stages := range result.VersionIdsToStages {
+		if len(stages) > 0 {
+			versions = append(versions, version)
+		}
+	}
+	sort.Strings(versions)
+
+	return versions, nil
 }
 
 // GetRotationMetadata returns metadata about rotation capabilities for a secret
@@ -472,6 +532,228 @@ func (aws *AWSSecretsManagerProvider) GetRotationMetadata(ctx context.Context, r
 	return metadata, nil
 }
 
+// Write support implementation
+
+// PutSecret creates or updates a secret in AWS Secrets Manager.
+// A key with a JSON path ("name#.field") updates that field of a JSON secret.
+func (aws *AWSSecretsManagerProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	secretName, jsonPath := aws.parseKey(ref.Key)
+	secretString := string(value)
+	exists := true
+
+	if jsonPath != "" {
+		current, err := aws.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretName),
+		})
+		if err != nil && !isNotFoundError(err) {
+			return "", aws.handleError(err, secretName)
+		}
+
+		doc := ""
+		if err != nil {
+			exists = false
+		} else if current.SecretString != nil {
+			doc = *current.SecretString
+		}
+
+		secretString, err = setJSONField(doc, jsonPath, secretString)
+		if err != nil {
+			return "", fmt.Errorf("failed to update field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+	} else {
+		_, err := aws.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+			SecretId: aws.String(secretName),
+		})
+		if err != nil {
+			if !isNotFoundError(err) {
+				return "", aws.handleError(err, secretName)
+			}
+			exists = false
+		}
+	}
+
+	if !exists {
+		input := &secretsmanager.CreateSecretInput{
+			Name:         aws.String(secretName),
+			SecretString: aws.String(secretString),
+			Tags:         aws.tags(opts.Tags),
+		}
+		if opts.Description != "" {
+			input.Description = aws.String(opts.Description)
+		}
+		if kmsKeyID := opts.Metadata["kms_key_id"]; kmsKeyID != "" {
+			input.KmsKeyId = aws.String(kmsKeyID)
+		}
+
+		result, err := aws.client.CreateSecret(ctx, input)
+		if err != nil {
+			return "", aws.handleError(err, secretName)
+		}
+		if result.VersionId != nil {
+			return *result.VersionId, nil
+		}
+		return "", nil
+	}
+
+	input := &secretsmanager.UpdateSecretInput{
+		SecretId:     aws.String(secretName),
+		SecretString: aws.String(secretString),
+	}
+	if opts.Description != "" {
+		input.Description = aws.String(opts.Description)
+	}
+	if kmsKeyID := opts.Metadata["kms_key_id"]; kmsKeyID != "" {
+		input.KmsKeyId = aws.String(kmsKeyID)
+	}
+
+	result, err := aws.client.UpdateSecret(ctx, input)
+	if err != nil {
+		return "", aws.handleError(err, secretName)
+	}
+
+	if len(opts.Tags) > 0 {
+		if _, err := aws.client.TagResource(ctx, &secretsmanager.TagResourceInput{
+			SecretId: aws.String(secretName),
+			Tags:     aws.tags(opts.Tags),
+		}); err != nil {
+			return "", fmt.Errorf("secret updated but tagging failed: %w", aws.handleError(err, secretName))
+		}
+	}
+
+	if result.VersionId != nil {
+		return *result.VersionId, nil
+	}
+	return "", nil
+}
+
+// DeleteSecret deletes a secret from AWS Secrets Manager, or a single field
+// of a JSON secret. Deleted secrets stay recoverable for the default recovery
+// window unless opts.Force is set.
+func (aws *AWSSecretsManagerProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	secretName, jsonPath := aws.parseKey(ref.Key)
+
+	if jsonPath != "" {
+		current, err := aws.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretName),
+		})
+		if err != nil {
+			return aws.handleError(err, secretName)
+		}
+
+		doc := ""
+		if current.SecretString != nil {
+			doc = *current.SecretString
+		}
+		updated, found, err := deleteJSONField(doc, jsonPath)
+		if err != nil {
+			return fmt.Errorf("failed to remove field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+		if !found {
+			return &provider.NotFoundError{Provider: aws.name, Key: ref.Key}
+		}
+
+		_, err = aws.client.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
+			SecretId:     aws.String(secretName),
+			SecretString: aws.String(updated),
+		})
+		if err != nil {
+			return aws.handleError(err, secretName)
+		}
+		return nil
+	}
+
+	input := &secretsmanager.DeleteSecretInput{
+		SecretId: aws.String(secretName),
+	}
+	if opts.Force {
+		input.ForceDeleteWithoutRecovery = aws.Bool(true)
+	}
+
+	if _, err := aws.client.DeleteSecret(ctx, input); err != nil {
+		return aws.handleError(err, secretName)
+	}
+	return nil
+}
+
+// ListSecrets lists secrets whose names start with opts.Prefix. Values are
+// never read; only the ListSecrets API is used.
+func (aws *AWSSecretsManagerProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	input := &secretsmanager.ListSecretsInput{
+		SortOrder: types.SortOrderTypeAsc,
+	}
+	if opts.Prefix != "" {
+		input.Filters = []types.Filter{
+			{Key: types.FilterNameStringTypeName, Values: []string{opts.Prefix}},
+		}
+	}
+	if opts.PageSize > 0 {
+		input.MaxResults = aws.Int32(int32(opts.PageSize))
+	}
+	if opts.PageToken != "" {
+		input.NextToken = aws.String(opts.PageToken)
+	}
+
+	output, err := aws.client.ListSecrets(ctx, input)
+	if err != nil {
+		return provider.ListResult{}, aws.handleError(err, opts.Prefix)
+	}
+
+	result := provider.ListResult{
+		Secrets: make([]provider.SecretInfo, 0, len(output.SecretList)),
+	}
+	for _, entry := range output.SecretList {
+		info := provider.SecretInfo{
+			Key: *entry.Name,
+		}
+		if entry.Description != nil {
+			info.Description = *entry.Description
+		}
+		if entry.LastChangedDate != nil {
+			info.UpdatedAt = *entry.LastChangedDate
+		}
+		for versionID, stages := range entry.SecretVersionsToStages {
+			for _, stage := range stages {
+				if stage == "AWSCURRENT" {
+					info.Version = versionID
+				}
+			}
+		}
+		if len(entry.Tags) > 0 {
+			info.Tags = make(map[string]string, len(entry.Tags))
+			for _, tag := range entry.Tags {
+				if tag.Key != nil && tag.Value != nil {
+					info.Tags[*tag.Key] = *tag.Value
+				}
+			}
+		}
+		result.Secrets = append(result.Secrets, info)
+	}
+	if output.NextToken != nil {
+		result.NextPageToken = *output.NextToken
+	}
+
+	return result, nil
+}
+
+// tags converts a tag map to Secrets Manager tags in a stable order
+func (aws *AWSSecretsManagerProvider) tags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return result
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
@@ -524,3 +806,7 @@ func (aws *AWSSecretsManagerProvider) String(s string) *string {
 func (aws *AWSSecretsManagerProvider) Int32(i int32) *int32 {
 	return &i
 }
+
+func (aws *AWSSecretsManagerProvider) Bool(b bool) *bool {
+	return &b
+}
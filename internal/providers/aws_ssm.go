@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -20,6 +21,9 @@ import (
 type SSMClientAPI interface {
 	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 	DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
+	PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
+	DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error)
+	AddTagsToResource(ctx context.Context, params *ssm.AddTagsToResourceInput, optFns ...func(*ssm.Options)) (*ssm.AddTagsToResourceOutput, error)
 }
 
 // AWSSSMProvider implements the Provider interface for AWS Systems Manager Parameter Store
@@ -175,16 +179,13 @@ func (p *AWSSSMProvider) Resolve(ctx context.Context, ref provider.Reference) (p
 	result, err := p.client.GetParameter(ctx, input)
 	if err != nil {
 		if isParameterNotFoundError(err) {
-			return provider.SecretValue{}, dserrors.UserError{
-				Message:    fmt.Sprintf("Parameter not found: %s", parameterName),
-				Suggestion: "Check that the parameter exists and you have ssm:GetParameter permission",
-				Details:    err.Error(),
-			}
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
 		}
 		return provider.SecretValue{}, dserrors.UserError{
 			Message:    "Failed to get parameter from SSM",
 			Details:    err.Error(),
 			Suggestion: getSSMErrorSuggestion(err),
+			Err:        err,
 		}
 	}
 
@@ -293,6 +294,157 @@ func (p *AWSSSMProvider) Validate(ctx context.Context) error {
 	return nil
 }
 
+// ListSecrets lists parameters whose names start with opts.Prefix. The
+// configured parameter prefix is applied and stripped from returned keys.
+func (p *AWSSSMProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	namePrefix := p.config.ParameterPrefix + opts.Prefix
+
+	input := &ssm.DescribeParametersInput{}
+	if namePrefix != "" {
+		input.ParameterFilters = []types.ParameterStringFilter{
+			{
+				Key:    aws.String("Name"),
+				Option: aws.String("BeginsWith"),
+				Values: []string{namePrefix},
+			},
+		}
+	}
+	if opts.PageSize > 0 {
+		input.MaxResults = aws.Int32(int32(opts.PageSize))
+	}
+	if opts.PageToken != "" {
+		input.NextToken = aws.String(opts.PageToken)
+	}
+
+	output, err := p.client.DescribeParameters(ctx, input)
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Failed to list parameters in SSM",
+			Details:    err.Error(),
+			Suggestion: getSSMErrorSuggestion(err),
+		}
+	}
+
+	result := provider.ListResult{
+		Secrets: make([]provider.SecretInfo, 0, len(output.Parameters)),
+	}
+	for _, param := range output.Parameters {
+		info := provider.SecretInfo{
+			Key:  strings.TrimPrefix(aws.ToString(param.Name), p.config.ParameterPrefix),
+			Type: string(param.Type),
+		}
+		if param.Description != nil {
+			info.Description = *param.Description
+		}
+		if param.Version != 0 {
+			info.Version = fmt.Sprintf("%d", param.Version)
+		}
+		if param.LastModifiedDate != nil {
+			info.UpdatedAt = *param.LastModifiedDate
+		}
+		if param.Tier != "" {
+			info.Tags = map[string]string{"tier": string(param.Tier)}
+		}
+		result.Secrets = append(result.Secrets, info)
+	}
+	if output.NextToken != nil {
+		result.NextPageToken = *output.NextToken
+	}
+
+	return result, nil
+}
+
+// PutSecret creates or overwrites a parameter in SSM Parameter Store.
+// Parameters are written as SecureString unless metadata "type" says otherwise.
+func (p *AWSSSMProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	parameterName := ref.Key
+	if p.config.ParameterPrefix != "" {
+		parameterName = p.config.ParameterPrefix + parameterName
+	}
+
+	paramType := types.ParameterTypeSecureString
+	if t := opts.Metadata["type"]; t != "" {
+		paramType = types.ParameterType(t)
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(parameterName),
+		Value:     aws.String(string(value)),
+		Type:      paramType,
+		Overwrite: aws.Bool(true),
+	}
+	if opts.Description != "" {
+		input.Description = aws.String(opts.Description)
+	}
+	if kmsKeyID := opts.Metadata["kms_key_id"]; kmsKeyID != "" {
+		input.KeyId = aws.String(kmsKeyID)
+	}
+	if tier := opts.Metadata["tier"]; tier != "" {
+		input.Tier = types.ParameterTier(tier)
+	}
+
+	result, err := p.client.PutParameter(ctx, input)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    "Failed to put parameter to SSM",
+			Details:    err.Error(),
+			Suggestion: getSSMErrorSuggestion(err),
+		}
+	}
+
+	if len(opts.Tags) > 0 {
+		keys := make([]string, 0, len(opts.Tags))
+		for key := range opts.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		tags := make([]types.Tag, 0, len(keys))
+		for _, key := range keys {
+			tags = append(tags, types.Tag{Key: aws.String(key), Value: aws.String(opts.Tags[key])})
+		}
+
+		if _, err := p.client.AddTagsToResource(ctx, &ssm.AddTagsToResourceInput{
+			ResourceId:   aws.String(parameterName),
+			ResourceType: types.ResourceTypeForTaggingParameter,
+			Tags:         tags,
+		}); err != nil {
+			return "", dserrors.UserError{
+				Message:    "Parameter written but tagging failed",
+				Details:    err.Error(),
+				Suggestion: "Check IAM permissions: ssm:AddTagsToResource",
+			}
+		}
+	}
+
+	return fmt.Sprintf("%d", result.Version), nil
+}
+
+// DeleteSecret deletes a parameter from SSM Parameter Store.
+// Parameter Store has no recovery window, so opts.Force has no effect.
+func (p *AWSSSMProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	parameterName := ref.Key
+	if p.config.ParameterPrefix != "" {
+		parameterName = p.config.ParameterPrefix + parameterName
+	}
+
+	_, err := p.client.DeleteParameter(ctx, &ssm.DeleteParameterInput{
+		Name: aws.String(parameterName),
+	})
+	if err != nil {
+		if isParameterNotFoundError(err) {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return dserrors.UserError{
+			Message:    "Failed to delete parameter from SSM",
+			Details:    err.Error(),
+			Suggestion: getSSMErrorSuggestion(err),
+		}
+	}
+
+	return nil
+}
+
 // isParameterNotFoundError checks if the error is a parameter not found error
 func isParameterNotFoundError(err error) bool {
 	return strings.Contains(err.Error(), "ParameterNotFound")
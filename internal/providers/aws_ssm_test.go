@@ -1,6 +1,8 @@
 package providers_test
 
 import (
+	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/systmms/dsops/internal/providers"
 	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
 	"github.com/systmms/dsops/tests/testutil"
 )
 
@@ -73,3 +76,16 @@ func TestAWSSSMKeyFormats(t *testing.T) {
 		assert.NotEmpty(t, key)
 	}
 }
+
+func TestAWSSSMResolveMissingParameter(t *testing.T) {
+	t.Parallel()
+
+	p, err := providers.NewAWSSSMProvider("ssm", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSSMClient(fakes.NewFakeSSMClient()))
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "/missing"})
+	var notFound *provider.NotFoundError
+	require.True(t, errors.As(err, &notFound), "got %v", err)
+	assert.Equal(t, "/missing", notFound.Key)
+}
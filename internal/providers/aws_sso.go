@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sso"
 	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/pkg/provider"
@@ -27,6 +29,10 @@ type AWSSSOProvider struct {
 	logger     *logging.Logger
 	config     SSOConfig
 	cache      *ssoCredentialCache
+
+	// pollInterval overrides the device authorization polling interval the
+	// service asks for (used in tests)
+	pollInterval time.Duration
 }
 
 // SSOConfig holds AWS SSO-specific configuration
@@ -47,14 +53,23 @@ type ssoCredentialCache struct {
 	accessToken string
 }
 
-// ssoTokenCache represents the cached SSO token structure
+// ssoTokenCache represents the cached SSO token structure, in the format the
+// AWS CLI and SDKs share. The client registration and refresh token let the
+// access token be refreshed without another browser login.
 type ssoTokenCache struct {
-	StartURL    string    `json:"startUrl"`
-	Region      string    `json:"region"`
-	AccessToken string    `json:"accessToken"`
-	ExpiresAt   time.Time `json:"expiresAt"`
+	StartURL              string    `json:"startUrl"`
+	Region                string    `json:"region"`
+	AccessToken           string    `json:"accessToken"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	ClientID              string    `json:"clientId,omitempty"`
+	ClientSecret          string    `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt time.Time `json:"registrationExpiresAt,omitzero"`
+	RefreshToken          string    `json:"refreshToken,omitempty"`
 }
 
+// ssoDeviceGrantType is the OAuth grant type of the device authorization flow
+const ssoDeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
 // NewAWSSSOProvider creates a new AWS SSO provider
 func NewAWSSSOProvider(name string, configMap map[string]interface{}) (*AWSSSOProvider, error) {
 	logger := logging.New(false, false)
@@ -230,21 +245,172 @@ func (p *AWSSSOProvider) getAccessToken(ctx context.Context) (string, error) {
 
 	// Token expired or not found
 	if p.config.RefreshToken && token != nil {
-		// TODO: Implement token refresh via OIDC
-		// For now, return error requiring re-login
-		return "", fmt.Errorf("SSO token expired, please re-authenticate")
+		refreshed, err := p.refreshToken(ctx, token)
+		if err != nil {
+			return "", fmt.Errorf("SSO token expired and could not be refreshed: %w", err)
+		}
+		return refreshed.AccessToken, nil
 	}
 
 	return "", fmt.Errorf("no valid SSO token found")
 }
 
-// loadCachedToken loads the SSO token from cache
-func (p *AWSSSOProvider) loadCachedToken() (*ssoTokenCache, error) {
-	// Calculate cache file name (SHA1 of start URL)
+// refreshToken exchanges the cached refresh token for a new access token and
+// updates the cache
+func (p *AWSSSOProvider) refreshToken(ctx context.Context, token *ssoTokenCache) (*ssoTokenCache, error) {
+	if token.RefreshToken == "" || token.ClientID == "" || token.ClientSecret == "" {
+		return nil, fmt.Errorf("the cached SSO session has no refresh token")
+	}
+	if !token.RegistrationExpiresAt.IsZero() && time.Now().After(token.RegistrationExpiresAt) {
+		return nil, fmt.Errorf("the SSO client registration expired at %s", token.RegistrationExpiresAt.Format(time.RFC3339))
+	}
+
+	p.logger.Debug("Refreshing SSO access token for %s", p.config.StartURL)
+	out, err := p.oidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     aws.String(token.ClientID),
+		ClientSecret: aws.String(token.ClientSecret),
+		GrantType:    aws.String("refresh_token"),
+		RefreshToken: aws.String(token.RefreshToken),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed := *token
+	refreshed.AccessToken = aws.ToString(out.AccessToken)
+	refreshed.ExpiresAt = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second).UTC().Truncate(time.Second)
+	if out.RefreshToken != nil {
+		refreshed.RefreshToken = *out.RefreshToken
+	}
+	if err := p.saveCachedToken(&refreshed); err != nil {
+		return nil, err
+	}
+	return &refreshed, nil
+}
+
+// Login signs in to IAM Identity Center with the device authorization flow:
+// dsops registers a client, the user approves the code in a browser, and the
+// token is written to the SSO cache shared with the AWS CLI
+func (p *AWSSSOProvider) Login(ctx context.Context, prompt provider.LoginPrompt) error {
+	token, err := p.deviceLogin(ctx, prompt)
+	if err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("AWS SSO login for store '%s' failed", p.name),
+			Details:    err.Error(),
+			Suggestion: getSSOErrorSuggestion(err),
+		}
+	}
+	if err := p.saveCachedToken(token); err != nil {
+		return err
+	}
+
+	// Role credentials from an earlier session are no longer wanted
+	p.cache = &ssoCredentialCache{}
+	return nil
+}
+
+// deviceLogin runs the device authorization flow and returns the new token
+func (p *AWSSSOProvider) deviceLogin(ctx context.Context, prompt provider.LoginPrompt) (*ssoTokenCache, error) {
+	client, err := p.oidcClient.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String("dsops"),
+		ClientType: aws.String("public"),
+		Scopes:     []string{"sso:account:access"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the SSO client: %w", err)
+	}
+
+	auth, err := p.oidcClient.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     client.ClientId,
+		ClientSecret: client.ClientSecret,
+		StartUrl:     aws.String(p.config.StartURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the device authorization: %w", err)
+	}
+
+	verificationURL := aws.ToString(auth.VerificationUriComplete)
+	if verificationURL == "" {
+		verificationURL = aws.ToString(auth.VerificationUri)
+	}
+	prompt.Visit(verificationURL, aws.ToString(auth.UserCode))
+
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if p.pollInterval > 0 {
+		interval = p.pollInterval
+	}
+	deadline := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+
+	for {
+		out, err := p.oidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     client.ClientId,
+			ClientSecret: client.ClientSecret,
+			GrantType:    aws.String(ssoDeviceGrantType),
+			DeviceCode:   auth.DeviceCode,
+		})
+		if err == nil {
+			return &ssoTokenCache{
+				StartURL:              p.config.StartURL,
+				Region:                p.oidcClient.Options().Region,
+				AccessToken:           aws.ToString(out.AccessToken),
+				ExpiresAt:             time.Now().Add(time.Duration(out.ExpiresIn) * time.Second).UTC().Truncate(time.Second),
+				ClientID:              aws.ToString(client.ClientId),
+				ClientSecret:          aws.ToString(client.ClientSecret),
+				RegistrationExpiresAt: time.Unix(client.ClientSecretExpiresAt, 0).UTC(),
+				RefreshToken:          aws.ToString(out.RefreshToken),
+			}, nil
+		}
+
+		var pending *ssooidctypes.AuthorizationPendingException
+		var slowDown *ssooidctypes.SlowDownException
+		switch {
+		case errors.As(err, &pending):
+		case errors.As(err, &slowDown):
+			interval += 5 * time.Second
+		default:
+			return nil, fmt.Errorf("failed to get the SSO token: %w", err)
+		}
+
+		if auth.ExpiresIn > 0 && time.Now().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("the device code expired before the login was approved")
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the login to be approved: %w", ctx.Err())
+		}
+	}
+}
+
+// cacheFile returns the SSO cache file for the start URL: the SHA1 of the
+// URL, as the AWS CLI names it
+func (p *AWSSSOProvider) cacheFile() string {
 	hash := fmt.Sprintf("%x", sha1.Sum([]byte(p.config.StartURL)))
-	cacheFile := filepath.Join(p.config.CachePath, hash+".json")
+	return filepath.Join(p.config.CachePath, hash+".json")
+}
 
-	data, err := os.ReadFile(cacheFile)
+// saveCachedToken writes the SSO token to the cache, readable by the owner
+// only
+func (p *AWSSSOProvider) saveCachedToken(token *ssoTokenCache) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the SSO token: %w", err)
+	}
+	if err := os.MkdirAll(p.config.CachePath, 0700); err != nil {
+		return fmt.Errorf("failed to create the SSO cache directory: %w", err)
+	}
+	if err := os.WriteFile(p.cacheFile(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write the SSO token cache: %w", err)
+	}
+	return nil
+}
+
+// loadCachedToken loads the SSO token from cache
+func (p *AWSSSOProvider) loadCachedToken() (*ssoTokenCache, error) {
+	data, err := os.ReadFile(p.cacheFile())
 	if err != nil {
 		return nil, err
 	}
@@ -343,16 +509,16 @@ func (p *AWSSSOProvider) Validate(ctx context.Context) error {
 	if err != nil || token == nil {
 		return dserrors.UserError{
 			Message:    "No SSO session found",
-			Suggestion: "Run 'aws sso login --sso-session " + p.name + "' to authenticate",
+			Suggestion: "Run 'dsops login " + p.name + "' to authenticate",
 			Details:    "SSO requires browser-based authentication",
 		}
 	}
 
-	// Check if token is expired
-	if time.Now().After(token.ExpiresAt) {
+	// Check if token is expired and cannot be refreshed
+	if time.Now().After(token.ExpiresAt) && (!p.config.RefreshToken || token.RefreshToken == "") {
 		return dserrors.UserError{
 			Message:    "SSO session expired",
-			Suggestion: "Run 'aws sso login' to re-authenticate",
+			Suggestion: "Run 'dsops login " + p.name + "' to re-authenticate",
 			Details:    fmt.Sprintf("Token expired at %s", token.ExpiresAt.Format(time.RFC3339)),
 		}
 	}
@@ -366,7 +532,7 @@ func getSSOErrorSuggestion(err error) string {
 
 	switch {
 	case strings.Contains(errStr, "UnauthorizedException"):
-		return "Your SSO session may have expired. Run 'aws sso login' to re-authenticate"
+		return "Your SSO session may have expired. Run 'dsops login' to re-authenticate"
 	case strings.Contains(errStr, "AccessDeniedException"):
 		return "You don't have permission to assume this role. Check with your SSO administrator"
 	case strings.Contains(errStr, "ResourceNotFoundException"):
@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// fakeSSO stands in for the IAM Identity Center OIDC and portal endpoints.
+// The first pending token polls answer authorization_pending.
+type fakeSSO struct {
+	t       *testing.T
+	mu      sync.Mutex
+	pending int
+	grants  []string
+}
+
+func (f *fakeSSO) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost {
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+	write := func(v interface{}) { _ = json.NewEncoder(w).Encode(v) }
+
+	switch r.URL.Path {
+	case "/client/register":
+		assert.Equal(f.t, "dsops", body["clientName"])
+		write(map[string]interface{}{"clientId": "cid", "clientSecret": "csecret", "clientSecretExpiresAt": time.Now().Add(90 * 24 * time.Hour).Unix()})
+	case "/device_authorization":
+		assert.Equal(f.t, "https://example.awsapps.com/start", body["startUrl"])
+		write(map[string]interface{}{
+			"deviceCode": "dev", "userCode": "ABCD-EFGH", "interval": 1, "expiresIn": 600,
+			"verificationUri": "https://device.sso.us-east-1.amazonaws.com/", "verificationUriComplete": "https://device.sso.us-east-1.amazonaws.com/?user_code=ABCD-EFGH",
+		})
+	case "/token":
+		grant := body["grantType"].(string)
+		f.grants = append(f.grants, grant)
+		if grant == ssoDeviceGrantType && f.pending > 0 {
+			f.pending--
+			w.Header().Set("X-Amzn-ErrorType", "AuthorizationPendingException")
+			w.WriteHeader(http.StatusBadRequest)
+			write(map[string]interface{}{"error": "authorization_pending"})
+			return
+		}
+		if grant == "refresh_token" {
+			assert.Equal(f.t, "refresh-1", body["refreshToken"])
+			write(map[string]interface{}{"accessToken": "access-2", "expiresIn": 3600, "refreshToken": "refresh-2"})
+			return
+		}
+		write(map[string]interface{}{"accessToken": "access-1", "expiresIn": 3600, "refreshToken": "refresh-1"})
+	case "/federation/credentials":
+		token := r.Header.Get("X-Amz-Sso_bearer_token")
+		write(map[string]interface{}{"roleCredentials": map[string]interface{}{
+			"accessKeyId": "AKIA-" + token, "secretAccessKey": "secret", "sessionToken": "session",
+			"expiration": time.Now().Add(time.Hour).UnixMilli(),
+		}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type ssoPrompt struct{ url, code string }
+
+func (p *ssoPrompt) Visit(url, code string)                   { p.url, p.code = url, code }
+func (p *ssoPrompt) ReadSecret(prompt string) (string, error) { return "", nil }
+
+func newTestSSOProvider(t *testing.T, fake *fakeSSO) *AWSSSOProvider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+
+	endpoint := aws.String(server.URL)
+	return &AWSSSOProvider{
+		name:       "sso",
+		ssoClient:  sso.New(sso.Options{Region: "us-east-1", BaseEndpoint: endpoint}),
+		oidcClient: ssooidc.New(ssooidc.Options{Region: "us-east-1", BaseEndpoint: endpoint}),
+		logger:     logging.New(false, false),
+		config: SSOConfig{
+			StartURL:     "https://example.awsapps.com/start",
+			AccountID:    "123456789012",
+			RoleName:     "Developer",
+			CachePath:    t.TempDir(),
+			RefreshToken: true,
+		},
+		cache:        &ssoCredentialCache{},
+		pollInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestAWSSSOProvider_Login(t *testing.T) {
+	fake := &fakeSSO{t: t, pending: 2}
+	p := newTestSSOProvider(t, fake)
+	ctx := context.Background()
+
+	assert.Error(t, p.Validate(ctx))
+
+	prompt := &ssoPrompt{}
+	var authenticator provider.Authenticator = p
+	require.NoError(t, authenticator.Login(ctx, prompt))
+	assert.Equal(t, "ABCD-EFGH", prompt.code)
+	assert.Contains(t, prompt.url, "user_code=ABCD-EFGH")
+	assert.Equal(t, []string{ssoDeviceGrantType, ssoDeviceGrantType, ssoDeviceGrantType}, fake.grants)
+
+	// The cache file is the one the AWS CLI reads
+	data, err := os.ReadFile(p.cacheFile())
+	require.NoError(t, err)
+	var cached map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &cached))
+	assert.Equal(t, "https://example.awsapps.com/start", cached["startUrl"])
+	assert.Equal(t, "us-east-1", cached["region"])
+	assert.Equal(t, "access-1", cached["accessToken"])
+	assert.Equal(t, "refresh-1", cached["refreshToken"])
+	assert.Equal(t, "cid", cached["clientId"])
+	assert.Regexp(t, `^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ$`, cached["expiresAt"])
+	info, err := os.Stat(p.cacheFile())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, p.Validate(ctx))
+	value, err := p.Resolve(ctx, provider.Reference{Key: "access_key_id"})
+	require.NoError(t, err)
+	assert.Equal(t, "AKIA-access-1", value.Value)
+}
+
+func TestAWSSSOProvider_RefreshToken(t *testing.T) {
+	fake := &fakeSSO{t: t}
+	p := newTestSSOProvider(t, fake)
+	require.NoError(t, p.saveCachedToken(&ssoTokenCache{
+		StartURL:              p.config.StartURL,
+		Region:                "us-east-1",
+		AccessToken:           "access-1",
+		ExpiresAt:             time.Now().Add(-time.Minute),
+		ClientID:              "cid",
+		ClientSecret:          "csecret",
+		RegistrationExpiresAt: time.Now().Add(time.Hour),
+		RefreshToken:          "refresh-1",
+	}))
+
+	require.NoError(t, p.Validate(context.Background()))
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "access_key_id"})
+	require.NoError(t, err)
+	assert.Equal(t, "AKIA-access-2", value.Value)
+	assert.Equal(t, []string{"refresh_token"}, fake.grants)
+
+	token, err := p.loadCachedToken()
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-2", token.RefreshToken)
+	assert.True(t, token.ExpiresAt.After(time.Now()))
+
+	// Without a refresh token the session has to be renewed with dsops login
+	token.RefreshToken = ""
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, p.saveCachedToken(token))
+	_, err = p.getAccessToken(context.Background())
+	assert.ErrorContains(t, err, "no refresh token")
+}
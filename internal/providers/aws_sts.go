@@ -1,28 +1,45 @@
 package providers
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/aws-sdk-go-v2/service/sts/types"
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/pkg/provider"
+	"golang.org/x/term"
 )
 
+// STSClientAPI defines the AWS STS operations used by AWSSTSProvider
+// This allows for mocking in tests
+type STSClientAPI interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
 // AWSSTSProvider implements the Provider interface for AWS STS (Security Token Service)
 type AWSSTSProvider struct {
 	name   string
-	client *sts.Client
+	client STSClientAPI
 	logger *logging.Logger
 	config STSConfig
 	cache  *stsCredentialCache
+
+	// readMFACode asks for the current code of an MFA device
+	readMFACode func(serial string) (string, error)
 }
 
 // STSConfig holds AWS STS-specific configuration
@@ -35,18 +52,52 @@ type STSConfig struct {
 	Duration        int32  // in seconds
 	SerialNumber    string // For MFA
 	TokenCode       string // For MFA
+	TokenCommand    string // Prints an MFA code, e.g. from a hardware key
+	SourceIdentity  string
 	Policy          string // Session policy JSON
 	Tags            map[string]string
+	TransitiveTags  []string
+	CachePath       string // Directory of the session cache; empty disables it
+
+	// RoleChain lists the roles assumed in turn, each with the credentials
+	// of the previous one. Without role_chain it holds the single role
+	// configured with assume_role.
+	RoleChain []STSRoleHop
 }
 
-// stsCredentialCache caches temporary credentials
-type stsCredentialCache struct {
-	credentials *sts.AssumeRoleOutput
-	expiresAt   time.Time
+// STSRoleHop is one role of a role chain
+type STSRoleHop struct {
+	RoleARN        string
+	SessionName    string
+	ExternalID     string
+	Duration       int32
+	SerialNumber   string
+	Policy         string
+	Tags           map[string]string
+	TransitiveTags []string
+}
+
+const (
+	// stsExpiryWindow is how long before expiry a session is replaced
+	stsExpiryWindow = 5 * time.Minute
+
+	// stsMaxChainedDuration is the longest session AWS issues to a role
+	// assumed with the credentials of another role
+	stsMaxChainedDuration = 3600
+)
+
+// STSProviderOption is a functional option for configuring STS providers
+type STSProviderOption func(*AWSSTSProvider)
+
+// WithSTSClient sets a custom STS client (for testing)
+func WithSTSClient(client STSClientAPI) STSProviderOption {
+	return func(p *AWSSTSProvider) {
+		p.client = client
+	}
 }
 
 // NewAWSSTSProvider creates a new AWS STS provider
-func NewAWSSTSProvider(name string, configMap map[string]interface{}) (*AWSSTSProvider, error) {
+func NewAWSSTSProvider(name string, configMap map[string]interface{}, opts ...STSProviderOption) (*AWSSTSProvider, error) {
 	logger := logging.New(false, false)
 
 	config := STSConfig{
@@ -63,6 +114,8 @@ func NewAWSSTSProvider(name string, configMap map[string]interface{}) (*AWSSTSPr
 	}
 	if role, ok := configMap["assume_role"].(string); ok {
 		config.AssumeRole = role
+	} else if role, ok := configMap["role_arn"].(string); ok {
+		config.AssumeRole = role
 	}
 	if sessionName, ok := configMap["role_session_name"].(string); ok {
 		config.RoleSessionName = sessionName
@@ -79,40 +132,206 @@ func NewAWSSTSProvider(name string, configMap map[string]interface{}) (*AWSSTSPr
 	if tokenCode, ok := configMap["mfa_token_code"].(string); ok {
 		config.TokenCode = tokenCode
 	}
+	if tokenCommand, ok := configMap["mfa_token_command"].(string); ok {
+		config.TokenCommand = tokenCommand
+	}
+	if sourceIdentity, ok := configMap["source_identity"].(string); ok {
+		config.SourceIdentity = sourceIdentity
+	}
 	if policy, ok := configMap["session_policy"].(string); ok {
 		config.Policy = policy
 	}
-	if tags, ok := configMap["tags"].(map[string]interface{}); ok {
-		config.Tags = make(map[string]string)
-		for k, v := range tags {
-			if strVal, ok := v.(string); ok {
-				config.Tags[k] = strVal
+	if cachePath, ok := configMap["cache_path"].(string); ok {
+		config.CachePath = cachePath
+	} else if dir, err := os.UserCacheDir(); err == nil {
+		config.CachePath = filepath.Join(dir, "dsops", "aws", "sts")
+	}
+	config.Tags = parseSTSTags(configMap["tags"])
+	config.TransitiveTags = parseStringList(configMap["transitive_tag_keys"])
+
+	if chain, ok := configMap["role_chain"].([]interface{}); ok {
+		if config.AssumeRole != "" {
+			return nil, dserrors.ConfigError{
+				Field:      "role_chain",
+				Message:    "assume_role and role_chain cannot both be set",
+				Suggestion: "Make the role the last entry of role_chain",
 			}
 		}
+		for i, entry := range chain {
+			hopMap, ok := entry.(map[string]interface{})
+			if !ok {
+				return nil, dserrors.ConfigError{
+					Field:      fmt.Sprintf("role_chain[%d]", i),
+					Message:    "role_chain entries must be maps",
+					Suggestion: "Give each role as role_arn: plus optional settings",
+				}
+			}
+			config.RoleChain = append(config.RoleChain, parseSTSRoleHop(hopMap, config, i))
+		}
+	} else if config.AssumeRole != "" {
+		config.RoleChain = []STSRoleHop{{
+			RoleARN:        config.AssumeRole,
+			SessionName:    config.RoleSessionName,
+			ExternalID:     config.ExternalID,
+			Duration:       config.Duration,
+			SerialNumber:   config.SerialNumber,
+			Policy:         config.Policy,
+			Tags:           config.Tags,
+			TransitiveTags: config.TransitiveTags,
+		}}
 	}
 
 	// Validate required configuration
-	if config.AssumeRole == "" {
+	if len(config.RoleChain) == 0 {
 		return nil, dserrors.ConfigError{
 			Field:      "assume_role",
-			Message:    "assume_role is required for STS provider",
-			Suggestion: "Provide the ARN of the role to assume",
+			Message:    "assume_role or role_chain is required for STS provider",
+			Suggestion: "Provide the ARN of the role to assume, or the roles to assume in turn as role_chain",
 		}
 	}
+	if err := validateRoleChain(config.RoleChain, configMap["role_chain"] != nil); err != nil {
+		return nil, err
+	}
 
-	// Create AWS client
-	client, err := createSTSClient(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create STS client: %w", err)
+	p := &AWSSTSProvider{
+		name:        name,
+		logger:      logger,
+		config:      config,
+		cache:       &stsCredentialCache{},
+		readMFACode: promptMFACode,
 	}
 
-	return &AWSSTSProvider{
-		name:   name,
-		client: client,
-		logger: logger,
-		config: config,
-		cache:  &stsCredentialCache{},
-	}, nil
+	// Apply options (allows mock client injection)
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// If no client was provided via options, create real client
+	if p.client == nil {
+		client, err := createSTSClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create STS client: %w", err)
+		}
+		p.client = client
+	}
+
+	return p, nil
+}
+
+// parseSTSRoleHop reads one role_chain entry. The first role defaults to the
+// store's external ID, MFA device, session policy and tags; every role
+// defaults to the store's session name and duration, capped at an hour for
+// chained roles.
+func parseSTSRoleHop(hopMap map[string]interface{}, config STSConfig, index int) STSRoleHop {
+	hop := STSRoleHop{
+		SessionName: config.RoleSessionName,
+		Duration:    config.Duration,
+	}
+	if index == 0 {
+		hop.ExternalID = config.ExternalID
+		hop.SerialNumber = config.SerialNumber
+		hop.Policy = config.Policy
+		hop.Tags = config.Tags
+		hop.TransitiveTags = config.TransitiveTags
+	} else if hop.Duration > stsMaxChainedDuration {
+		hop.Duration = stsMaxChainedDuration
+	}
+
+	if role, ok := hopMap["role_arn"].(string); ok {
+		hop.RoleARN = role
+	}
+	if sessionName, ok := hopMap["role_session_name"].(string); ok {
+		hop.SessionName = sessionName
+	}
+	if externalID, ok := hopMap["external_id"].(string); ok {
+		hop.ExternalID = externalID
+	}
+	if duration, ok := hopMap["duration"].(int); ok {
+		hop.Duration = int32(duration)
+	}
+	if serialNumber, ok := hopMap["mfa_serial_number"].(string); ok {
+		hop.SerialNumber = serialNumber
+	}
+	if policy, ok := hopMap["session_policy"].(string); ok {
+		hop.Policy = policy
+	}
+	if tags := parseSTSTags(hopMap["tags"]); tags != nil {
+		hop.Tags = tags
+	}
+	if keys := parseStringList(hopMap["transitive_tag_keys"]); keys != nil {
+		hop.TransitiveTags = keys
+	}
+	return hop
+}
+
+// validateRoleChain checks the roles against the limits of AssumeRole
+func validateRoleChain(chain []STSRoleHop, fromRoleChain bool) error {
+	for i, hop := range chain {
+		prefix := ""
+		if fromRoleChain {
+			prefix = fmt.Sprintf("role_chain[%d].", i)
+		}
+		if hop.RoleARN == "" {
+			return dserrors.ConfigError{
+				Field:      prefix + "role_arn",
+				Message:    "role_arn is required for each role in role_chain",
+				Suggestion: "Provide the ARN of the role to assume",
+			}
+		}
+		if !strings.HasPrefix(hop.RoleARN, "arn:") {
+			return dserrors.ConfigError{
+				Field:      prefix + "role_arn",
+				Value:      hop.RoleARN,
+				Message:    "role must be an ARN",
+				Suggestion: "Use the form arn:aws:iam::123456789012:role/RoleName",
+			}
+		}
+		if hop.Duration < 900 || hop.Duration > 43200 {
+			return dserrors.ConfigError{
+				Field:      prefix + "duration",
+				Value:      hop.Duration,
+				Message:    "duration must be between 900 and 43200 seconds",
+				Suggestion: "Use at most the maximum session duration of the role",
+			}
+		}
+		if i > 0 && hop.Duration > stsMaxChainedDuration {
+			return dserrors.ConfigError{
+				Field:      prefix + "duration",
+				Value:      hop.Duration,
+				Message:    "roles assumed with the credentials of another role are limited to 3600 seconds",
+				Suggestion: "Lower the duration of this role to 3600 or less",
+			}
+		}
+	}
+	return nil
+}
+
+func parseSTSTags(value interface{}) map[string]string {
+	tags, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string)
+	for k, v := range tags {
+		if strVal, ok := v.(string); ok {
+			result[k] = strVal
+		}
+	}
+	return result
+}
+
+func parseStringList(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
 }
 
 // createSTSClient creates an AWS STS client with the given configuration
@@ -146,67 +365,202 @@ func (p *AWSSTSProvider) Name() string {
 
 // Resolve fetches temporary credentials from STS
 func (p *AWSSTSProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
-	// Check cache first
-	if p.cache.credentials != nil && time.Now().Before(p.cache.expiresAt) {
-		return p.getCredentialValue(ref.Key)
+	result, err := p.assumeChain(ctx)
+	if err != nil {
+		return provider.SecretValue{}, err
 	}
+	return p.getCredentialValue(result, ref.Key)
+}
 
-	// Assume role to get new credentials
-	input := &sts.AssumeRoleInput{
-		RoleArn:         aws.String(p.config.AssumeRole),
-		RoleSessionName: aws.String(p.config.RoleSessionName),
-		DurationSeconds: aws.Int32(p.config.Duration),
+// assumeChain returns the session of the last role in the chain. Cached
+// sessions are reused while they are valid, and an expired chain is resumed
+// from the last role whose session is still valid, so a long-lived
+// MFA-protected jump role is not assumed again for each account role. The
+// sessions are also kept on disk, so later runs resume the chain too.
+func (p *AWSSTSProvider) assumeChain(ctx context.Context) (*sts.AssumeRoleOutput, error) {
+	p.cache.mu.Lock()
+	defer p.cache.mu.Unlock()
+
+	if !p.cache.loaded {
+		p.cache.loaded = true
+		p.cache.sessions = p.loadSessions()
 	}
 
-	if p.config.ExternalID != "" {
-		input.ExternalId = aws.String(p.config.ExternalID)
+	chain := p.config.RoleChain
+	sessions := p.cache.sessions
+	if len(sessions) > len(chain) {
+		sessions = nil
 	}
 
-	if p.config.SerialNumber != "" && p.config.TokenCode != "" {
-		input.SerialNumber = aws.String(p.config.SerialNumber)
-		input.TokenCode = aws.String(p.config.TokenCode)
+	// Find the last hop with a valid session
+	start := 0
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if stsSessionValid(sessions[i]) {
+			start = i + 1
+			break
+		}
 	}
+	sessions = sessions[:start]
 
-	if p.config.Policy != "" {
-		input.Policy = aws.String(p.config.Policy)
+	for i := start; i < len(chain); i++ {
+		var caller *sts.AssumeRoleOutput
+		if i > 0 {
+			caller = sessions[i-1]
+		}
+		result, err := p.assumeHop(ctx, i, caller)
+		if err != nil {
+			p.cache.sessions = sessions
+			if len(sessions) > start {
+				p.saveSessions(sessions)
+			}
+			return nil, err
+		}
+		sessions = append(sessions, result)
 	}
 
-	if len(p.config.Tags) > 0 {
-		var tags []types.Tag
-		for k, v := range p.config.Tags {
-			tags = append(tags, types.Tag{
+	p.cache.sessions = sessions
+	if start < len(chain) {
+		p.saveSessions(sessions)
+	}
+	return sessions[len(sessions)-1], nil
+}
+
+// assumeHop assumes one role of the chain with the session of the previous
+// role, or with the source credentials for the first role
+func (p *AWSSTSProvider) assumeHop(ctx context.Context, index int, caller *sts.AssumeRoleOutput) (*sts.AssumeRoleOutput, error) {
+	hop := p.config.RoleChain[index]
+
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(hop.RoleARN),
+		RoleSessionName: aws.String(hop.SessionName),
+		DurationSeconds: aws.Int32(hop.Duration),
+	}
+	if hop.ExternalID != "" {
+		input.ExternalId = aws.String(hop.ExternalID)
+	}
+	if hop.Policy != "" {
+		input.Policy = aws.String(hop.Policy)
+	}
+	// The source identity persists through the rest of the chain
+	if index == 0 && p.config.SourceIdentity != "" {
+		input.SourceIdentity = aws.String(p.config.SourceIdentity)
+	}
+	if len(hop.Tags) > 0 {
+		keys := make([]string, 0, len(hop.Tags))
+		for k := range hop.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			input.Tags = append(input.Tags, types.Tag{
 				Key:   aws.String(k),
-				Value: aws.String(v),
+				Value: aws.String(hop.Tags[k]),
 			})
 		}
-		input.Tags = tags
+		input.TransitiveTagKeys = hop.TransitiveTags
+	}
+	if hop.SerialNumber != "" {
+		code, err := p.mfaCode(ctx, hop.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		input.SerialNumber = aws.String(hop.SerialNumber)
+		input.TokenCode = aws.String(code)
 	}
 
-	p.logger.Debug("Assuming role: %s", logging.Secret(p.config.AssumeRole))
+	var optFns []func(*sts.Options)
+	if caller != nil && caller.Credentials != nil {
+		creds := credentials.NewStaticCredentialsProvider(
+			aws.ToString(caller.Credentials.AccessKeyId),
+			aws.ToString(caller.Credentials.SecretAccessKey),
+			aws.ToString(caller.Credentials.SessionToken),
+		)
+		optFns = append(optFns, func(o *sts.Options) { o.Credentials = creds })
+	}
 
-	result, err := p.client.AssumeRole(ctx, input)
+	p.logger.Debug("Assuming role: %s", logging.Secret(hop.RoleARN))
+
+	result, err := p.client.AssumeRole(ctx, input, optFns...)
 	if err != nil {
-		return provider.SecretValue{}, dserrors.UserError{
-			Message:    "Failed to assume role",
+		message := fmt.Sprintf("Failed to assume role %s", hop.RoleARN)
+		if len(p.config.RoleChain) > 1 {
+			message = fmt.Sprintf("Failed to assume role %d of %d in the role chain (%s)", index+1, len(p.config.RoleChain), hop.RoleARN)
+		}
+		return nil, dserrors.UserError{
+			Message:    message,
 			Details:    err.Error(),
 			Suggestion: getSTSErrorSuggestion(err),
 		}
 	}
+	if result.Credentials == nil {
+		return nil, fmt.Errorf("no credentials returned for role %s", hop.RoleARN)
+	}
+	return result, nil
+}
+
+// mfaCode returns an MFA code from mfa_token_code, mfa_token_command or the
+// terminal, in that order
+func (p *AWSSTSProvider) mfaCode(ctx context.Context, serial string) (string, error) {
+	if p.config.TokenCode != "" {
+		return p.config.TokenCode, nil
+	}
+
+	if p.config.TokenCommand != "" {
+		out, err := exec.CommandContext(ctx, "sh", "-c", p.config.TokenCommand).Output()
+		if err != nil {
+			return "", dserrors.UserError{
+				Message:    "mfa_token_command failed",
+				Details:    err.Error(),
+				Suggestion: "Check that the command prints the current MFA code",
+			}
+		}
+		code := strings.TrimSpace(string(out))
+		if code == "" {
+			return "", dserrors.UserError{
+				Message:    "mfa_token_command printed no MFA code",
+				Suggestion: "Check that the command prints the current MFA code",
+			}
+		}
+		return code, nil
+	}
+
+	code, err := p.readMFACode(serial)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(code), nil
+}
 
-	// Cache the credentials
-	p.cache.credentials = result
-	p.cache.expiresAt = *result.Credentials.Expiration
+// promptMFACode asks for an MFA code on the terminal
+func promptMFACode(serial string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("An MFA code is required for %s", serial),
+			Suggestion: "Run dsops in a terminal to enter it, or set mfa_token_command to a command that prints it",
+		}
+	}
 
-	return p.getCredentialValue(ref.Key)
+	_, _ = fmt.Fprintf(os.Stderr, "MFA code for %s: ", serial)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read MFA code: %w", err)
+	}
+	return line, nil
+}
+
+// stsSessionValid reports whether a session is usable beyond the expiry window
+func stsSessionValid(session *sts.AssumeRoleOutput) bool {
+	return session != nil && session.Credentials != nil && session.Credentials.Expiration != nil &&
+		time.Until(*session.Credentials.Expiration) > stsExpiryWindow
 }
 
 // getCredentialValue extracts the requested credential component
-func (p *AWSSTSProvider) getCredentialValue(key string) (provider.SecretValue, error) {
-	if p.cache.credentials == nil || p.cache.credentials.Credentials == nil {
+func (p *AWSSTSProvider) getCredentialValue(session *sts.AssumeRoleOutput, key string) (provider.SecretValue, error) {
+	if session == nil || session.Credentials == nil {
 		return provider.SecretValue{}, fmt.Errorf("no credentials available")
 	}
 
-	creds := p.cache.credentials.Credentials
+	creds := session.Credentials
 	var value string
 
 	switch key {
@@ -219,8 +573,8 @@ func (p *AWSSTSProvider) getCredentialValue(key string) (provider.SecretValue, e
 	case "expiration":
 		value = creds.Expiration.Format(time.RFC3339)
 	case "assumed_role_arn":
-		if p.cache.credentials.AssumedRoleUser != nil {
-			value = *p.cache.credentials.AssumedRoleUser.Arn
+		if session.AssumedRoleUser != nil {
+			value = *session.AssumedRoleUser.Arn
 		}
 	case "credentials", "all":
 		// Return all credentials as JSON
@@ -243,7 +597,7 @@ func (p *AWSSTSProvider) getCredentialValue(key string) (provider.SecretValue, e
 	}
 
 	metadata := map[string]string{
-		"source":     fmt.Sprintf("sts:%s", p.config.AssumeRole),
+		"source":     fmt.Sprintf("sts:%s", p.finalRole().RoleARN),
 		"expires_at": creds.Expiration.Format(time.RFC3339),
 	}
 
@@ -255,16 +609,30 @@ func (p *AWSSTSProvider) getCredentialValue(key string) (provider.SecretValue, e
 
 // Describe returns metadata about the STS provider
 func (p *AWSSTSProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	tags := map[string]string{
+		"role_arn":     p.finalRole().RoleARN,
+		"session_name": p.finalRole().SessionName,
+	}
+	if len(p.config.RoleChain) > 1 {
+		roles := make([]string, 0, len(p.config.RoleChain))
+		for _, hop := range p.config.RoleChain {
+			roles = append(roles, hop.RoleARN)
+		}
+		tags["role_chain"] = strings.Join(roles, " -> ")
+	}
+
 	return provider.Metadata{
 		Exists: true,
 		Type:   "sts-credentials",
-		Tags: map[string]string{
-			"role_arn":     p.config.AssumeRole,
-			"session_name": p.config.RoleSessionName,
-		},
+		Tags:   tags,
 	}, nil
 }
 
+// finalRole returns the role whose credentials the store provides
+func (p *AWSSTSProvider) finalRole() STSRoleHop {
+	return p.config.RoleChain[len(p.config.RoleChain)-1]
+}
+
 // Capabilities returns the provider's capabilities
 func (p *AWSSTSProvider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
@@ -277,7 +645,8 @@ func (p *AWSSTSProvider) Capabilities() provider.Capabilities {
 	}
 }
 
-// Validate checks if the provider is properly configured and accessible
+// Validate checks the source credentials and assumes each role of the chain,
+// asking for MFA codes where needed
 func (p *AWSSTSProvider) Validate(ctx context.Context) error {
 	// Get caller identity to validate credentials
 	input := &sts.GetCallerIdentityInput{}
@@ -291,6 +660,10 @@ func (p *AWSSTSProvider) Validate(ctx context.Context) error {
 		}
 	}
 
+	if _, err := p.assumeChain(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -299,6 +672,8 @@ func getSTSErrorSuggestion(err error) string {
 	errStr := err.Error()
 
 	switch {
+	case strings.Contains(errStr, "MultiFactorAuthentication"):
+		return "Check mfa_serial_number and enter a fresh MFA code; each code can only be used once"
 	case strings.Contains(errStr, "AccessDenied"):
 		return "Check that you have permission to assume the role and the trust policy allows your principal"
 	case strings.Contains(errStr, "InvalidParameterValue"):
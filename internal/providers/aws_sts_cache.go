@@ -0,0 +1,154 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/systmms/dsops/internal/atomicfile"
+)
+
+// stsCredentialCache caches the session of each hop, so a chain is resumed
+// from the last hop that is still valid. The sessions are loaded from the
+// cache file on first use.
+type stsCredentialCache struct {
+	mu       sync.Mutex
+	loaded   bool
+	sessions []*sts.AssumeRoleOutput
+}
+
+// stsCachedSession is one hop's session as written to the cache file
+type stsCachedSession struct {
+	RoleARN         string    `json:"RoleArn"`
+	AssumedRoleARN  string    `json:"AssumedRoleArn,omitempty"`
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// sessionCacheFile returns the cache file of the chain, or "" when the cache
+// is disabled. The name hashes the source credentials and every setting
+// that changes what a role's session may do; session names are left out
+// because they default to a new name on each run.
+func (p *AWSSTSProvider) sessionCacheFile() string {
+	if p.config.CachePath == "" {
+		return ""
+	}
+
+	profile := p.config.Profile
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	key := struct {
+		Profile        string
+		AccessKeyID    string
+		Region         string
+		SourceIdentity string
+		Chain          []STSRoleHop
+	}{
+		Profile:        profile,
+		AccessKeyID:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		Region:         p.config.Region,
+		SourceIdentity: p.config.SourceIdentity,
+	}
+	for _, hop := range p.config.RoleChain {
+		hop.SessionName = ""
+		key.Chain = append(key.Chain, hop)
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return filepath.Join(p.config.CachePath, hex.EncodeToString(sum[:16])+".json")
+}
+
+// loadSessions reads the cached sessions of the chain, up to the first one
+// that is missing, expired or for another role. A missing or unreadable
+// cache starts the chain from its first role, and a cache with no usable
+// session is removed.
+func (p *AWSSTSProvider) loadSessions() []*sts.AssumeRoleOutput {
+	path := p.sessionCacheFile()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached []stsCachedSession
+	if err := json.Unmarshal(data, &cached); err != nil {
+		p.logger.Debug("Ignoring unreadable STS session cache %s: %v", path, err)
+		_ = os.Remove(path)
+		return nil
+	}
+
+	var sessions []*sts.AssumeRoleOutput
+	for i, c := range cached {
+		if i >= len(p.config.RoleChain) || c.RoleARN != p.config.RoleChain[i].RoleARN {
+			break
+		}
+		session := &sts.AssumeRoleOutput{
+			Credentials: &types.Credentials{
+				AccessKeyId:     aws.String(c.AccessKeyID),
+				SecretAccessKey: aws.String(c.SecretAccessKey),
+				SessionToken:    aws.String(c.SessionToken),
+				Expiration:      aws.Time(c.Expiration),
+			},
+		}
+		if c.AssumedRoleARN != "" {
+			session.AssumedRoleUser = &types.AssumedRoleUser{Arn: aws.String(c.AssumedRoleARN)}
+		}
+		if !stsSessionValid(session) {
+			break
+		}
+		sessions = append(sessions, session)
+	}
+	if len(sessions) == 0 {
+		_ = os.Remove(path)
+	}
+	return sessions
+}
+
+// saveSessions writes the sessions of the chain to the cache file, readable
+// by the owner only. Failing to write the cache only costs the next run a
+// new session, so it is logged rather than returned.
+func (p *AWSSTSProvider) saveSessions(sessions []*sts.AssumeRoleOutput) {
+	path := p.sessionCacheFile()
+	if path == "" {
+		return
+	}
+
+	cached := make([]stsCachedSession, 0, len(sessions))
+	for i, session := range sessions {
+		creds := session.Credentials
+		c := stsCachedSession{
+			RoleARN:         p.config.RoleChain[i].RoleARN,
+			AccessKeyID:     aws.ToString(creds.AccessKeyId),
+			SecretAccessKey: aws.ToString(creds.SecretAccessKey),
+			SessionToken:    aws.ToString(creds.SessionToken),
+			Expiration:      aws.ToTime(creds.Expiration).UTC(),
+		}
+		if session.AssumedRoleUser != nil {
+			c.AssumedRoleARN = aws.ToString(session.AssumedRoleUser.Arn)
+		}
+		cached = append(cached, c)
+	}
+
+	data, err := json.Marshal(cached)
+	if err == nil {
+		err = atomicfile.Write(path, data, 0600)
+	}
+	if err != nil {
+		p.logger.Debug("Failed to write STS session cache %s: %v", path, err)
+	}
+}
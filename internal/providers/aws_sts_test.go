@@ -0,0 +1,272 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+const (
+	jumpRole    = "arn:aws:iam::111111111111:role/Jump"
+	accountRole = "arn:aws:iam::222222222222:role/Deploy"
+	mfaDevice   = "arn:aws:iam::111111111111:mfa/developer"
+)
+
+// newChainFake trusts the source credentials with MFA for the jump role, and
+// the jump role with an external ID for the account role
+func newChainFake(accountSession time.Duration) *fakes.FakeSTSClient {
+	fake := fakes.NewFakeSTSClient()
+	fake.AddRole(jumpRole, fakes.FakeSTSRole{MFASerial: mfaDevice, MFACode: "123456"})
+	fake.AddRole(accountRole, fakes.FakeSTSRole{TrustedCaller: jumpRole, ExternalID: "ext-1", SessionDuration: accountSession})
+	return fake
+}
+
+func chainConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"region":          "us-east-1",
+		"source_identity": "developer",
+		"role_chain": []interface{}{
+			map[string]interface{}{
+				"role_arn":          jumpRole,
+				"mfa_serial_number": mfaDevice,
+				"duration":          43200,
+				"tags":              map[string]interface{}{"team": "platform"},
+				"transitive_tag_keys": []interface{}{
+					"team",
+				},
+			},
+			map[string]interface{}{
+				"role_arn":    accountRole,
+				"external_id": "ext-1",
+			},
+		},
+	}
+}
+
+func newChainProvider(t *testing.T, fake *fakes.FakeSTSClient, config map[string]interface{}) (*AWSSTSProvider, *[]string) {
+	t.Helper()
+	if _, ok := config["cache_path"]; !ok {
+		config["cache_path"] = t.TempDir()
+	}
+	p, err := NewAWSSTSProvider("prod", config, WithSTSClient(fake))
+	require.NoError(t, err)
+
+	prompts := &[]string{}
+	p.readMFACode = func(serial string) (string, error) {
+		*prompts = append(*prompts, serial)
+		return "123456\n", nil
+	}
+	return p, prompts
+}
+
+func TestAWSSTSProvider_RoleChain(t *testing.T) {
+	fake := newChainFake(0)
+	p, prompts := newChainProvider(t, fake, chainConfig())
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+
+	var creds map[string]string
+	require.NoError(t, json.Unmarshal([]byte(value.Value), &creds))
+	assert.Equal(t, "ASIAFAKE0002", creds["AccessKeyId"], "the credentials are those of the last role")
+	assert.Equal(t, "sts:"+accountRole, value.Metadata["source"])
+
+	require.Len(t, fake.Calls, 2)
+	jump, account := fake.Calls[0], fake.Calls[1]
+	assert.Equal(t, "", jump.Caller)
+	assert.Equal(t, mfaDevice, aws.ToString(jump.Input.SerialNumber))
+	assert.Equal(t, "developer", aws.ToString(jump.Input.SourceIdentity))
+	assert.Equal(t, int32(43200), aws.ToInt32(jump.Input.DurationSeconds))
+	require.Len(t, jump.Input.Tags, 1)
+	assert.Equal(t, "team", aws.ToString(jump.Input.Tags[0].Key))
+	assert.Equal(t, []string{"team"}, jump.Input.TransitiveTagKeys)
+
+	assert.Equal(t, jumpRole, account.Caller, "the account role is assumed with the jump role's session")
+	assert.Equal(t, "ext-1", aws.ToString(account.Input.ExternalId))
+	assert.Nil(t, account.Input.SourceIdentity, "the source identity carries over from the first role")
+	assert.Equal(t, int32(3600), aws.ToInt32(account.Input.DurationSeconds), "chained roles default to an hour")
+
+	assert.Equal(t, []string{mfaDevice}, *prompts)
+
+	// Cached sessions are reused
+	_, err = p.Resolve(ctx, provider.Reference{Key: "access_key_id"})
+	require.NoError(t, err)
+	assert.Len(t, fake.Calls, 2)
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, jumpRole+" -> "+accountRole, meta.Tags["role_chain"])
+}
+
+func TestAWSSTSProvider_RoleChainResumesFromValidHop(t *testing.T) {
+	// Sessions of the account role expire within the expiry window
+	fake := newChainFake(time.Minute)
+	p, prompts := newChainProvider(t, fake, chainConfig())
+	ctx := context.Background()
+
+	_, err := p.Resolve(ctx, provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+	_, err = p.Resolve(ctx, provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+
+	// Only the account role is assumed again, without a new MFA code
+	assert.Equal(t, []string{jumpRole, accountRole, accountRole}, fake.RolesAssumed())
+	assert.Equal(t, jumpRole, fake.Calls[2].Caller)
+	assert.Len(t, *prompts, 1)
+}
+
+func TestAWSSTSProvider_RoleChainPersistsSessions(t *testing.T) {
+	// Sessions of the account role expire within the expiry window
+	fake := newChainFake(time.Minute)
+	config := chainConfig()
+	config["cache_path"] = t.TempDir()
+	p, prompts := newChainProvider(t, fake, config)
+
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+
+	path := p.sessionCacheFile()
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// A later run resumes the chain from the jump role's cached session
+	next, nextPrompts := newChainProvider(t, fake, config)
+	_, err = next.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{jumpRole, accountRole, accountRole}, fake.RolesAssumed())
+	assert.Equal(t, jumpRole, fake.Calls[2].Caller)
+	assert.Len(t, *prompts, 1)
+	assert.Empty(t, *nextPrompts)
+
+	// A changed chain does not reuse the sessions
+	config["role_chain"].([]interface{})[1].(map[string]interface{})["session_policy"] = `{"Version":"2012-10-17"}`
+	changed, _ := newChainProvider(t, fake, config)
+	assert.NotEqual(t, path, changed.sessionCacheFile())
+
+	// A cache without a usable session is removed
+	require.NoError(t, os.WriteFile(path, []byte(`[{"RoleArn":"`+jumpRole+`","Expiration":"2020-01-01T00:00:00Z"}]`), 0600))
+	assert.Empty(t, next.loadSessions())
+	assert.NoFileExists(t, path)
+}
+
+func TestAWSSTSProvider_RoleChainFailure(t *testing.T) {
+	fake := newChainFake(0)
+	config := chainConfig()
+	config["role_chain"].([]interface{})[1].(map[string]interface{})["external_id"] = "wrong"
+	p, _ := newChainProvider(t, fake, config)
+
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "role 2 of 2")
+	assert.Contains(t, userErr.Message, accountRole)
+
+	// The jump role's session is kept for the next attempt
+	p.config.RoleChain[1].ExternalID = "ext-1"
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{jumpRole, accountRole, accountRole}, fake.RolesAssumed())
+}
+
+func TestAWSSTSProvider_MFATokenCommand(t *testing.T) {
+	fake := newChainFake(0)
+	config := chainConfig()
+	config["mfa_token_command"] = "echo 123456"
+	p, prompts := newChainProvider(t, fake, config)
+
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	require.NoError(t, err)
+	assert.Empty(t, *prompts)
+	assert.Equal(t, "123456", aws.ToString(fake.Calls[0].Input.TokenCode))
+
+	// Without a cached session the failing command is run
+	config["mfa_token_command"] = "false"
+	delete(config, "cache_path")
+	p, _ = newChainProvider(t, newChainFake(0), config)
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "credentials"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "mfa_token_command failed", userErr.Message)
+}
+
+func TestAWSSTSProvider_SingleRole(t *testing.T) {
+	fake := fakes.NewFakeSTSClient()
+	fake.AddRole(accountRole, fakes.FakeSTSRole{ExternalID: "ext-1"})
+
+	p, err := NewAWSSTSProvider("prod", map[string]interface{}{
+		"assume_role": accountRole,
+		"external_id": "ext-1",
+		"tags":        map[string]interface{}{"b": "2", "a": "1"},
+		"cache_path":  t.TempDir(),
+	}, WithSTSClient(fake))
+	require.NoError(t, err)
+
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "assumed_role_arn"})
+	require.NoError(t, err)
+	assert.Contains(t, value.Value, accountRole)
+	require.Len(t, fake.Calls, 1)
+	assert.Equal(t, "a", aws.ToString(fake.Calls[0].Input.Tags[0].Key), "tags are sent in a stable order")
+}
+
+func TestAWSSTSProvider_Validate(t *testing.T) {
+	fake := newChainFake(0)
+	p, prompts := newChainProvider(t, fake, chainConfig())
+
+	require.NoError(t, p.Validate(context.Background()))
+	assert.Equal(t, []string{jumpRole, accountRole}, fake.RolesAssumed(), "doctor walks the whole chain")
+	assert.Len(t, *prompts, 1)
+
+	fake.CallerIdentityErr = errors.New("ExpiredToken: the security token included in the request is expired")
+	assert.ErrorContains(t, p.Validate(context.Background()), "Failed to validate AWS credentials")
+}
+
+func TestNewAWSSTSProvider_ConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		field  string
+	}{
+		{"no role", map[string]interface{}{}, "assume_role"},
+		{"both", map[string]interface{}{
+			"assume_role": accountRole,
+			"role_chain":  []interface{}{map[string]interface{}{"role_arn": jumpRole}},
+		}, "role_chain"},
+		{"missing role_arn", map[string]interface{}{
+			"role_chain": []interface{}{map[string]interface{}{"external_id": "x"}},
+		}, "role_chain[0].role_arn"},
+		{"not an ARN", map[string]interface{}{"assume_role": "Deploy"}, "role_arn"},
+		{"chained duration", map[string]interface{}{
+			"role_chain": []interface{}{
+				map[string]interface{}{"role_arn": jumpRole},
+				map[string]interface{}{"role_arn": accountRole, "duration": 7200},
+			},
+		}, "role_chain[1].duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewAWSSTSProvider("prod", tt.config, WithSTSClient(fakes.NewFakeSTSClient()))
+			var configErr dserrors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, tt.field, configErr.Field)
+		})
+	}
+}
@@ -32,6 +32,7 @@ type AzureIdentityConfig struct {
 	UseManagedIdentity bool
 	UserAssignedID     string
 	Scope              string // Default scope for token requests
+	UseDeviceCode      bool   // Use the account signed in with dsops login
 }
 
 // NewAzureIdentityProvider creates a new Azure Identity provider
@@ -65,6 +66,9 @@ func NewAzureIdentityProvider(name string, configMap map[string]interface{}) (*A
 	if scope, ok := configMap["scope"].(string); ok {
 		config.Scope = scope
 	}
+	if useDeviceCode, ok := configMap["use_device_code"].(bool); ok {
+		config.UseDeviceCode = useDeviceCode
+	}
 
 	// Create Azure credential
 	credential, err := createAzureCredential(config)
@@ -86,7 +90,10 @@ func createAzureCredential(config AzureIdentityConfig) (azcore.TokenCredential,
 	var err error
 
 	// Determine authentication method
-	if config.UseManagedIdentity {
+	if config.UseDeviceCode {
+		// Account signed in with dsops login
+		cred, err = newAzureLoginCredential(config.TenantID)
+	} else if config.UseManagedIdentity {
 		// Managed Identity (system-assigned or user-assigned)
 		if config.UserAssignedID != "" {
 			// User-assigned managed identity
@@ -295,6 +302,12 @@ func getAzureIdentityErrorSuggestion(err error) string {
 	}
 }
 
+// Login signs in to Azure with a device code for stores with
+// use_device_code
+func (p *AzureIdentityProvider) Login(ctx context.Context, prompt provider.LoginPrompt) error {
+	return azureDeviceCodeLogin(ctx, p.name, p.config.TenantID, p.config.UseDeviceCode, []string{p.config.Scope}, prompt)
+}
+
 // NewAzureIdentityProviderFactory creates an Azure Identity provider factory
 func NewAzureIdentityProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
 	return NewAzureIdentityProvider(name, config)
@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -10,7 +11,11 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
@@ -21,18 +26,45 @@ import (
 // This allows for mocking in tests
 type AzureKeyVaultClientAPI interface {
 	GetSecret(ctx context.Context, name string, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
-	// Note: NewListSecretPropertiesPager is excluded from the interface for now
-	// as it returns a complex pager type that's difficult to mock.
-	// For validation testing, we'll use GetSecret instead.
+	SetSecret(ctx context.Context, name string, parameters azsecrets.SetSecretParameters, options *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error)
+	DeleteSecret(ctx context.Context, name string, options *azsecrets.DeleteSecretOptions) (azsecrets.DeleteSecretResponse, error)
+	PurgeDeletedSecret(ctx context.Context, name string, options *azsecrets.PurgeDeletedSecretOptions) (azsecrets.PurgeDeletedSecretResponse, error)
+	UpdateSecretProperties(ctx context.Context, name string, version string, parameters azsecrets.UpdateSecretPropertiesParameters, options *azsecrets.UpdateSecretPropertiesOptions) (azsecrets.UpdateSecretPropertiesResponse, error)
+	NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse]
+	NewListSecretPropertiesVersionsPager(name string, options *azsecrets.ListSecretPropertiesVersionsOptions) *runtime.Pager[azsecrets.ListSecretPropertiesVersionsResponse]
+}
+
+// AzureKeyVaultCertificatesAPI defines the Key Vault certificate operations
+// used for cert: references and certificate rotation
+type AzureKeyVaultCertificatesAPI interface {
+	GetCertificate(ctx context.Context, name string, version string, options *azcertificates.GetCertificateOptions) (azcertificates.GetCertificateResponse, error)
+	GetCertificatePolicy(ctx context.Context, name string, options *azcertificates.GetCertificatePolicyOptions) (azcertificates.GetCertificatePolicyResponse, error)
+	CreateCertificate(ctx context.Context, name string, parameters azcertificates.CreateCertificateParameters, options *azcertificates.CreateCertificateOptions) (azcertificates.CreateCertificateResponse, error)
+	GetCertificateOperation(ctx context.Context, name string, options *azcertificates.GetCertificateOperationOptions) (azcertificates.GetCertificateOperationResponse, error)
+	UpdateCertificate(ctx context.Context, name string, version string, parameters azcertificates.UpdateCertificateParameters, options *azcertificates.UpdateCertificateOptions) (azcertificates.UpdateCertificateResponse, error)
+}
+
+// AzureKeyVaultKeysAPI defines the Key Vault key operations used for key:
+// references and key rotation
+type AzureKeyVaultKeysAPI interface {
+	GetKey(ctx context.Context, name string, version string, options *azkeys.GetKeyOptions) (azkeys.GetKeyResponse, error)
+	RotateKey(ctx context.Context, name string, options *azkeys.RotateKeyOptions) (azkeys.RotateKeyResponse, error)
+	UpdateKey(ctx context.Context, name string, version string, parameters azkeys.UpdateKeyParameters, options *azkeys.UpdateKeyOptions) (azkeys.UpdateKeyResponse, error)
 }
 
 // AzureKeyVaultProvider implements the Provider interface for Azure Key Vault
 type AzureKeyVaultProvider struct {
-	name     string
-	client   AzureKeyVaultClientAPI
-	logger   *logging.Logger
-	config   AzureKeyVaultConfig
-	vaultURL string
+	name         string
+	client       AzureKeyVaultClientAPI
+	certificates AzureKeyVaultCertificatesAPI
+	keys         AzureKeyVaultKeysAPI
+	logger       *logging.Logger
+	config       AzureKeyVaultConfig
+	vaultURL     string
+
+	// certPollInterval is how often a pending certificate operation is
+	// checked during rotation
+	certPollInterval time.Duration
 }
 
 // AzureKeyVaultConfig holds Azure Key Vault-specific configuration
@@ -44,6 +76,7 @@ type AzureKeyVaultConfig struct {
 	CertificatePath    string
 	UseManagedIdentity bool
 	UserAssignedID     string // For user-assigned managed identity
+	UseDeviceCode      bool   // Use the account signed in with dsops login
 }
 
 // AzureProviderOption is a functional option for configuring Azure providers
@@ -56,6 +89,20 @@ func WithAzureKeyVaultClient(client AzureKeyVaultClientAPI) AzureProviderOption
 	}
 }
 
+// WithAzureCertificatesClient sets a custom Key Vault certificates client (for testing)
+func WithAzureCertificatesClient(client AzureKeyVaultCertificatesAPI) AzureProviderOption {
+	return func(p *AzureKeyVaultProvider) {
+		p.certificates = client
+	}
+}
+
+// WithAzureKeysClient sets a custom Key Vault keys client (for testing)
+func WithAzureKeysClient(client AzureKeyVaultKeysAPI) AzureProviderOption {
+	return func(p *AzureKeyVaultProvider) {
+		p.keys = client
+	}
+}
+
 // NewAzureKeyVaultProvider creates a new Azure Key Vault provider
 func NewAzureKeyVaultProvider(name string, configMap map[string]interface{}, opts ...AzureProviderOption) (*AzureKeyVaultProvider, error) {
 	logger := logging.New(false, false)
@@ -86,6 +133,9 @@ func NewAzureKeyVaultProvider(name string, configMap map[string]interface{}, opt
 	if userAssignedID, ok := configMap["user_assigned_identity_id"].(string); ok {
 		config.UserAssignedID = userAssignedID
 	}
+	if useDeviceCode, ok := configMap["use_device_code"].(bool); ok {
+		config.UseDeviceCode = useDeviceCode
+	}
 
 	// Validate required configuration
 	if config.VaultURL == "" {
@@ -106,10 +156,11 @@ func NewAzureKeyVaultProvider(name string, configMap map[string]interface{}, opt
 	}
 
 	p := &AzureKeyVaultProvider{
-		name:     name,
-		logger:   logger,
-		config:   config,
-		vaultURL: config.VaultURL,
+		name:             name,
+		logger:           logger,
+		config:           config,
+		vaultURL:         config.VaultURL,
+		certPollInterval: 2 * time.Second,
 	}
 
 	// Apply options (allows mock client injection)
@@ -117,25 +168,43 @@ func NewAzureKeyVaultProvider(name string, configMap map[string]interface{}, opt
 		opt(p)
 	}
 
-	// If no client was provided via options, create real client
-	if p.client == nil {
-		client, err := createAzureKeyVaultClient(config)
+	// Create real clients for anything not provided via options
+	if p.client == nil || p.certificates == nil || p.keys == nil {
+		cred, err := createAzureKeyVaultCredential(config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure Key Vault client: %w", err)
 		}
-		p.client = client
+		if p.client == nil {
+			if p.client, err = azsecrets.NewClient(config.VaultURL, cred, nil); err != nil {
+				return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+			}
+		}
+		if p.certificates == nil {
+			if p.certificates, err = azcertificates.NewClient(config.VaultURL, cred, nil); err != nil {
+				return nil, fmt.Errorf("failed to create Key Vault certificates client: %w", err)
+			}
+		}
+		if p.keys == nil {
+			if p.keys, err = azkeys.NewClient(config.VaultURL, cred, nil); err != nil {
+				return nil, fmt.Errorf("failed to create Key Vault keys client: %w", err)
+			}
+		}
 	}
 
 	return p, nil
 }
 
-// createAzureKeyVaultClient creates an Azure Key Vault client with appropriate authentication
-func createAzureKeyVaultClient(config AzureKeyVaultConfig) (*azsecrets.Client, error) {
+// createAzureKeyVaultCredential creates the credential shared by the Key
+// Vault secrets, certificates and keys clients
+func createAzureKeyVaultCredential(config AzureKeyVaultConfig) (azcore.TokenCredential, error) {
 	var cred azcore.TokenCredential
 	var err error
 
 	// Determine authentication method
-	if config.UseManagedIdentity {
+	if config.UseDeviceCode {
+		// Account signed in with dsops login
+		cred, err = newAzureLoginCredential(config.TenantID)
+	} else if config.UseManagedIdentity {
 		// Managed Identity (system-assigned or user-assigned)
 		if config.UserAssignedID != "" {
 			// User-assigned managed identity
@@ -164,13 +233,7 @@ func createAzureKeyVaultClient(config AzureKeyVaultConfig) (*azsecrets.Client, e
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
-	// Create Key Vault client
-	client, err := azsecrets.NewClient(config.VaultURL, cred, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
-	}
-
-	return client, nil
+	return cred, nil
 }
 
 // readCertificateFile reads certificate data from file
@@ -182,6 +245,13 @@ func (p *AzureKeyVaultProvider) Name() string {
 
 // Resolve fetches a secret from Azure Key Vault
 func (p *AzureKeyVaultProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	if obj, ok := p.parseObjectReference(ref); ok {
+		if obj.kind == "cert" {
+			return p.resolveCertificate(ctx, obj)
+		}
+		return p.resolveKey(ctx, obj)
+	}
+
 	secretName, version, jsonPath := p.parseReference(ref.Key)
 
 	p.logger.Debug("Accessing Azure Key Vault secret: %s", logging.Secret(secretName))
@@ -197,10 +267,14 @@ func (p *AzureKeyVaultProvider) Resolve(ctx context.Context, ref provider.Refere
 	}
 
 	if err != nil {
+		if isAzureNotFoundError(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
 		return provider.SecretValue{}, dserrors.UserError{
 			Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
 			Details:    err.Error(),
 			Suggestion: getAzureErrorSuggestion(err),
+			Err:        classifyAzureError(p.name, err),
 		}
 	}
 
@@ -248,6 +322,16 @@ func (p *AzureKeyVaultProvider) Resolve(ctx context.Context, ref provider.Refere
 	}, nil
 }
 
+// parseObjectReference parses cert: and key: references, applying the
+// reference's pinned version
+func (p *AzureKeyVaultProvider) parseObjectReference(ref provider.Reference) (azureObjectRef, bool) {
+	obj, ok := parseAzureObjectReference(ref.Key)
+	if ok && ref.Version != "" {
+		obj.version = ref.Version
+	}
+	return obj, ok
+}
+
 // parseReference parses Azure Key Vault secret references
 func (p *AzureKeyVaultProvider) parseReference(ref string) (secretName, version, jsonPath string) {
 	// Check for JSON path extraction (secret-name#.json.path)
@@ -323,7 +407,17 @@ func extractJSONPathAzure(jsonStr, path string) (string, error) {
 
 // Describe returns metadata about a secret without fetching its value
 func (p *AzureKeyVaultProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	if obj, ok := p.parseObjectReference(ref); ok {
+		if obj.kind == "cert" {
+			return p.describeCertificate(ctx, obj)
+		}
+		return p.describeKey(ctx, obj)
+	}
+
 	secretName, version, _ := p.parseReference(ref.Key)
+	if ref.Version != "" {
+		version = ref.Version
+	}
 
 	var resp azsecrets.GetSecretResponse
 	var err error
@@ -356,8 +450,21 @@ func (p *AzureKeyVaultProvider) Describe(ctx context.Context, ref provider.Refer
 		if resp.Attributes.Created != nil {
 			metadata.UpdatedAt = *resp.Attributes.Created
 		}
+		if resp.Attributes.Updated != nil {
+			metadata.UpdatedAt = *resp.Attributes.Updated
+		}
+	}
+
+	// A pinned version is deprecated once it has been disabled or has expired
+	if version != "" {
+		metadata.Version = version
+		if attrs := resp.Attributes; attrs != nil {
+			disabled := attrs.Enabled != nil && !*attrs.Enabled
+			expired := attrs.Expires != nil && attrs.Expires.Before(time.Now())
+			metadata.Deprecated = disabled || expired
+		}
 	}
-	// Note: Version and tags would need separate API calls
+	// Note: Tags would need a separate API call
 
 	return metadata, nil
 }
@@ -376,33 +483,352 @@ func (p *AzureKeyVaultProvider) Capabilities() provider.Capabilities {
 
 // Validate checks if the provider is properly configured and accessible
 func (p *AzureKeyVaultProvider) Validate(ctx context.Context) error {
-	// For real clients, we need to check if it's the concrete type that has the pager method
-	// For mock clients used in tests, we'll skip pager-based validation
+	// Test by listing secrets (requires minimal permissions)
+	pager := p.client.NewListSecretPropertiesPager(nil)
+
+	// Try to get the first page
+	if _, err := pager.NextPage(ctx); err != nil {
+		return dserrors.UserError{
+			Message:    "Failed to connect to Azure Key Vault",
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
 
-	// Type assertion to check if we have the real client
-	if realClient, ok := p.client.(*azsecrets.Client); ok {
-		// Test by listing secrets (requires minimal permissions)
-		pager := realClient.NewListSecretPropertiesPager(nil)
+	return nil
+}
+
+// PutSecret sets a secret in Azure Key Vault, creating a new version.
+// Key Vault has no description field, so the description is stored as a tag.
+func (p *AzureKeyVaultProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		return "", p.readOnlyObjectError(obj)
+	}
+
+	secretName, _, jsonPath := p.parseReference(ref.Key)
+	secretValue := string(value)
+
+	if jsonPath != "" {
+		doc := ""
+		resp, err := p.client.GetSecret(ctx, secretName, "", nil)
+		if err != nil && !isAzureNotFoundError(err) {
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getAzureErrorSuggestion(err),
+			}
+		}
+		if err == nil && resp.Value != nil {
+			doc = *resp.Value
+		}
+
+		secretValue, err = setJSONField(doc, jsonPath, secretValue)
+		if err != nil {
+			return "", fmt.Errorf("failed to update field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+	}
+
+	params := azsecrets.SetSecretParameters{
+		Value: &secretValue,
+	}
+	if contentType := opts.Metadata["content_type"]; contentType != "" {
+		params.ContentType = &contentType
+	}
+	if len(opts.Tags) > 0 || opts.Description != "" {
+		params.Tags = make(map[string]*string, len(opts.Tags)+1)
+		for k, v := range opts.Tags {
+			v := v
+			params.Tags[k] = &v
+		}
+		if opts.Description != "" {
+			description := opts.Description
+			params.Tags["description"] = &description
+		}
+	}
+
+	resp, err := p.client.SetSecret(ctx, secretName, params, nil)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to set secret: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
+
+	if resp.ID != nil {
+		return resp.ID.Version(), nil
+	}
+	return "", nil
+}
+
+// ListSecrets lists secrets whose names start with opts.Prefix. Key Vault
+// pages do not expose continuation tokens, so all pages are read and then
+// paged locally.
+func (p *AzureKeyVaultProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	var secrets []provider.SecretInfo
 
-		// Try to get the first page
-		_, err := pager.NextPage(ctx)
+	pager := p.client.NewListSecretPropertiesPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
 		if err != nil {
+			return provider.ListResult{}, dserrors.UserError{
+				Message:    "Failed to list secrets in Azure Key Vault",
+				Details:    err.Error(),
+				Suggestion: getAzureErrorSuggestion(err),
+			}
+		}
+
+		for _, props := range page.Value {
+			if props == nil || props.ID == nil {
+				continue
+			}
+			name := props.ID.Name()
+			if !strings.HasPrefix(name, opts.Prefix) {
+				continue
+			}
+
+			info := provider.SecretInfo{Key: name}
+			if props.ContentType != nil {
+				info.Type = *props.ContentType
+			}
+			if props.Attributes != nil && props.Attributes.Updated != nil {
+				info.UpdatedAt = *props.Attributes.Updated
+			}
+			if len(props.Tags) > 0 {
+				info.Tags = make(map[string]string, len(props.Tags))
+				for k, v := range props.Tags {
+					if v == nil {
+						continue
+					}
+					if k == "description" {
+						info.Description = *v
+						continue
+					}
+					info.Tags[k] = *v
+				}
+			}
+			secrets = append(secrets, info)
+		}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// DeleteSecret deletes a secret from Azure Key Vault. With soft-delete enabled
+// the secret stays recoverable unless opts.Force purges it as well.
+func (p *AzureKeyVaultProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		return p.readOnlyObjectError(obj)
+	}
+
+	secretName, _, jsonPath := p.parseReference(ref.Key)
+
+	if jsonPath != "" {
+		resp, err := p.client.GetSecret(ctx, secretName, "", nil)
+		if err != nil {
+			if isAzureNotFoundError(err) {
+				return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+			}
 			return dserrors.UserError{
-				Message:    "Failed to connect to Azure Key Vault",
+				Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
 				Details:    err.Error(),
 				Suggestion: getAzureErrorSuggestion(err),
 			}
 		}
+
+		doc := ""
+		if resp.Value != nil {
+			doc = *resp.Value
+		}
+		updated, found, err := deleteJSONField(doc, jsonPath)
+		if err != nil {
+			return fmt.Errorf("failed to remove field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+		if !found {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+
+		if _, err := p.client.SetSecret(ctx, secretName, azsecrets.SetSecretParameters{
+			Value:       &updated,
+			ContentType: resp.ContentType,
+			Tags:        resp.Tags,
+		}, nil); err != nil {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to set secret: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getAzureErrorSuggestion(err),
+			}
+		}
+		return nil
+	}
+
+	if _, err := p.client.DeleteSecret(ctx, secretName, nil); err != nil {
+		if isAzureNotFoundError(err) {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to delete secret: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
+
+	if opts.Force {
+		if _, err := p.client.PurgeDeletedSecret(ctx, secretName, nil); err != nil {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Secret deleted but purge failed: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: "Purging needs the 'Purge' secret permission and may fail while the deletion is still in progress; retry shortly",
+			}
+		}
 	}
-	// For mock clients, validation passes by default
-	// Tests can inject errors via the mock if needed
 
 	return nil
 }
 
+// CreateNewVersion creates a new version of a secret, certificate or key.
+// Certificates are reissued from their certificate policy and keys from
+// their rotation policy; for those Key Vault generates the new material and
+// newValue is ignored.
+func (p *AzureKeyVaultProvider) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		if obj.kind == "cert" {
+			return p.rotateCertificate(ctx, obj, meta)
+		}
+		return p.rotateKey(ctx, obj)
+	}
+
+	// Carry the content type and tags over to the new version
+	secretName, _, _ := p.parseReference(ref.Key)
+	opts := provider.WriteOptions{Tags: map[string]string{}, Metadata: map[string]string{}}
+	current, err := p.client.GetSecret(ctx, secretName, "", nil)
+	if err != nil && !isAzureNotFoundError(err) {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
+	if err == nil {
+		for k, v := range current.Tags {
+			if v != nil {
+				opts.Tags[k] = *v
+			}
+		}
+		if current.ContentType != nil {
+			opts.Metadata["content_type"] = *current.ContentType
+		}
+	}
+	for k, v := range meta {
+		opts.Tags[k] = v
+	}
+
+	return p.PutSecret(ctx, ref, newValue, opts)
+}
+
+// DeprecateVersion disables an old version of a secret, certificate or key.
+// Disabled versions can be re-enabled in the portal if a rollback is needed.
+func (p *AzureKeyVaultProvider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		if obj.kind == "cert" {
+			return p.deprecateCertificate(ctx, obj, version)
+		}
+		return p.deprecateKey(ctx, obj, version)
+	}
+
+	secretName, _, _ := p.parseReference(ref.Key)
+	_, err := p.client.UpdateSecretProperties(ctx, secretName, version, azsecrets.UpdateSecretPropertiesParameters{
+		SecretAttributes: &azsecrets.SecretAttributes{Enabled: to.Ptr(false)},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to disable secret version: %w", err)
+	}
+	return nil
+}
+
+// ListVersions returns the enabled versions of a secret. Certificates and
+// keys are not supported.
+func (p *AzureKeyVaultProvider) ListVersions(ctx context.Context, ref provider.Reference) ([]string, error) {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		return nil, fmt.Errorf("listing versions of Key Vault %ss is not supported", obj.kind)
+	}
+
+	secretName, _, _ := p.parseReference(ref.Key)
+	pager := p.client.NewListSecretPropertiesVersionsPager(secretName, nil)
+
+	var versions []string
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			if isAzureNotFoundError(err) {
+				return nil, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+			}
+			return nil, fmt.Errorf("failed to list secret versions: %w", err)
+		}
+		for _, props := range page.Value {
+			if props == nil || props.ID == nil {
+				continue
+			}
+			if props.Attributes != nil && props.Attributes.Enabled != nil && !*props.Attributes.Enabled {
+				continue
+			}
+			versions = append(versions, props.ID.Version())
+		}
+	}
+
+	return versions, nil
+}
+
+// GetRotationMetadata returns metadata about rotation capabilities for a
+// secret, certificate or key
+func (p *AzureKeyVaultProvider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	if obj, ok := parseAzureObjectReference(ref.Key); ok {
+		if obj.kind == "cert" {
+			return p.certificateRotationMetadata(ctx, obj)
+		}
+		return p.keyRotationMetadata(ctx, obj)
+	}
+
+	secretName, _, _ := p.parseReference(ref.Key)
+	resp, err := p.client.GetSecret(ctx, secretName, "", nil)
+	if err != nil {
+		return provider.RotationMetadata{}, fmt.Errorf("failed to get secret metadata: %w", err)
+	}
+
+	metadata := provider.RotationMetadata{
+		SupportsRotation:   true,
+		SupportsVersioning: true,
+		MaxValueLength:     25 * 1024, // Key Vault secret size limit
+		MinValueLength:     1,
+		Constraints: map[string]string{
+			"provider":  "azure-keyvault",
+			"vault_url": p.vaultURL,
+		},
+	}
+	if resp.Attributes != nil {
+		metadata.LastRotated = resp.Attributes.Updated
+	}
+	if interval := resp.Tags["rotation_interval"]; interval != nil {
+		metadata.RotationInterval = *interval
+	}
+
+	return metadata, nil
+}
+
 // isAzureNotFoundError checks if the error indicates a secret was not found
 func isAzureNotFoundError(err error) bool {
-	return strings.Contains(err.Error(), "SecretNotFound") || strings.Contains(err.Error(), "404")
+	return strings.Contains(err.Error(), "SecretNotFound") || strings.Contains(err.Error(), "CertificateNotFound") ||
+		strings.Contains(err.Error(), "KeyNotFound") || strings.Contains(err.Error(), "404")
+}
+
+// classifyAzureError marks Key Vault server errors as
+// provider.UnavailableError so fallbacks with on: unavailable apply
+func classifyAzureError(name string, err error) error {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode >= 500 {
+		return &provider.UnavailableError{Provider: name, Err: err}
+	}
+	return err
 }
 
 // getAzureErrorSuggestion provides helpful suggestions based on Azure errors
@@ -427,6 +853,12 @@ func getAzureErrorSuggestion(err error) string {
 	}
 }
 
+// Login signs in to Azure with a device code for stores with
+// use_device_code
+func (p *AzureKeyVaultProvider) Login(ctx context.Context, prompt provider.LoginPrompt) error {
+	return azureDeviceCodeLogin(ctx, p.name, p.config.TenantID, p.config.UseDeviceCode, []string{"https://vault.azure.net/.default"}, prompt)
+}
+
 // NewAzureKeyVaultProviderFactory creates an Azure Key Vault provider factory
 func NewAzureKeyVaultProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
 	return NewAzureKeyVaultProvider(name, config)
@@ -0,0 +1,552 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha1" // #nosec G505 -- thumbprints are SHA-1 by definition
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Fields of a cert: reference. "pem" is the default and holds the private
+// key followed by the full chain, the layout most TLS servers accept.
+var azureCertificateFields = []string{"pem", "cert", "chain", "fullchain", "key"}
+
+// Fields of a key: reference. Key Vault never releases private key
+// material, so only the public key is available.
+var azureKeyFields = []string{"pem", "jwk"}
+
+// azureCertificateTimeout bounds how long certificate rotation waits for
+// Key Vault to issue the new version
+const azureCertificateTimeout = 10 * time.Minute
+
+// azureObjectRef is a parsed cert: or key: reference
+type azureObjectRef struct {
+	kind    string // "cert" or "key"
+	name    string
+	version string
+	field   string
+}
+
+// parseAzureObjectReference parses cert:<name>[/<version>][/<field>] and
+// key:<name>[/<version>][/<field>] references. Key Vault object names only
+// contain letters, digits and dashes and versions are 32 hex characters, so
+// a single segment after the name is a field when it names one.
+func parseAzureObjectReference(ref string) (azureObjectRef, bool) {
+	kind, rest, found := strings.Cut(ref, ":")
+	if !found || (kind != "cert" && kind != "key") {
+		return azureObjectRef{}, false
+	}
+
+	fields := azureCertificateFields
+	if kind == "key" {
+		fields = azureKeyFields
+	}
+
+	obj := azureObjectRef{kind: kind}
+	parts := strings.SplitN(rest, "/", 3)
+	obj.name = parts[0]
+	switch len(parts) {
+	case 2:
+		if slices.Contains(fields, parts[1]) {
+			obj.field = parts[1]
+		} else {
+			obj.version = parts[1]
+		}
+	case 3:
+		obj.version = parts[1]
+		obj.field = parts[2]
+	}
+	return obj, true
+}
+
+// checkField validates the field of an object reference against the fields
+// its kind supports, defaulting to the first one
+func (o *azureObjectRef) checkField() error {
+	fields := azureCertificateFields
+	if o.kind == "key" {
+		fields = azureKeyFields
+	}
+	if o.field == "" {
+		o.field = fields[0]
+		return nil
+	}
+	if !slices.Contains(fields, o.field) {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Unknown %s field '%s'", o.kind, o.field),
+			Suggestion: fmt.Sprintf("Use one of: %s", strings.Join(fields, ", ")),
+		}
+	}
+	return nil
+}
+
+// readOnlyObjectError is returned when dsops set/delete target a cert: or
+// key: reference
+func (p *AzureKeyVaultProvider) readOnlyObjectError(obj azureObjectRef) error {
+	kind := "Certificates"
+	if obj.kind == "key" {
+		kind = "Keys"
+	}
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("%s cannot be written directly: %s:%s", kind, obj.kind, obj.name),
+		Suggestion: "Rotate the object instead; Key Vault generates new versions from the certificate policy or key rotation policy",
+	}
+}
+
+// certificateBundle is the PEM form of a certificate's backing secret
+type certificateBundle struct {
+	key   *pem.Block
+	leaf  *x509.Certificate
+	chain []*x509.Certificate
+}
+
+// parseCertificateBundle decodes the secret backing a Key Vault certificate.
+// Depending on the certificate policy it is either PEM or base64 PKCS#12.
+func parseCertificateBundle(value, contentType string) (*certificateBundle, error) {
+	var blocks []*pem.Block
+	if contentType == "application/x-pkcs12" {
+		pfx, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PKCS#12 encoding: %w", err)
+		}
+		key, leaf, chain, err := pkcs12.DecodeChain(pfx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode PKCS#12 bundle: %w", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode private key: %w", err)
+		}
+		blocks = append(blocks, &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		for _, cert := range append([]*x509.Certificate{leaf}, chain...) {
+			blocks = append(blocks, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		}
+	} else {
+		rest := []byte(value)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+	}
+
+	bundle := &certificateBundle{}
+	var certs []*x509.Certificate
+	var signer crypto.Signer
+	for _, block := range blocks {
+		switch {
+		case block.Type == "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate: %w", err)
+			}
+			certs = append(certs, cert)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			key, err := parseAnyPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode private key: %w", err)
+			}
+			bundle.key = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+			signer, _ = key.(crypto.Signer)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found in bundle")
+	}
+
+	// The leaf is the certificate matching the private key; PKCS#12 bundles
+	// do not guarantee it comes first
+	leaf := 0
+	if signer != nil {
+		for i, cert := range certs {
+			if pub, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); ok && pub.Equal(signer.Public()) {
+				leaf = i
+				break
+			}
+		}
+	}
+	bundle.leaf = certs[leaf]
+	for i, cert := range certs {
+		if i != leaf {
+			bundle.chain = append(bundle.chain, cert)
+		}
+	}
+	return bundle, nil
+}
+
+// parseAnyPrivateKey parses PKCS#8, PKCS#1 and SEC 1 private keys
+func parseAnyPrivateKey(der []byte) (any, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key format")
+}
+
+// field renders one field of the bundle as PEM
+func (b *certificateBundle) field(name string) (string, error) {
+	var buf bytes.Buffer
+	writeCert := func(cert *x509.Certificate) {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	switch name {
+	case "cert":
+		writeCert(b.leaf)
+	case "chain":
+		for _, cert := range b.chain {
+			writeCert(cert)
+		}
+	case "fullchain":
+		writeCert(b.leaf)
+		for _, cert := range b.chain {
+			writeCert(cert)
+		}
+	case "key", "pem":
+		if b.key == nil {
+			return "", dserrors.UserError{
+				Message:    "Certificate has no private key",
+				Suggestion: "Only certificates with an exportable key include the private key; use the cert or fullchain field",
+			}
+		}
+		_ = pem.Encode(&buf, b.key)
+		if name == "pem" {
+			writeCert(b.leaf)
+			for _, cert := range b.chain {
+				writeCert(cert)
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+// certificateThumbprint returns the SHA-1 thumbprint of a certificate in the
+// upper-case hex form shown by the Azure portal
+func certificateThumbprint(der []byte) string {
+	sum := sha1.Sum(der) // #nosec G401 -- thumbprints are SHA-1 by definition
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// certificateTags returns the metadata dsops reports for a certificate
+func certificateTags(cert *x509.Certificate) map[string]string {
+	tags := map[string]string{
+		"thumbprint": certificateThumbprint(cert.Raw),
+		"subject":    cert.Subject.String(),
+		"issuer":     cert.Issuer.String(),
+		"not_before": cert.NotBefore.UTC().Format(time.RFC3339),
+		"expires_at": cert.NotAfter.UTC().Format(time.RFC3339),
+		"serial":     cert.SerialNumber.Text(16),
+	}
+	if len(cert.DNSNames) > 0 {
+		tags["dns_names"] = strings.Join(cert.DNSNames, ",")
+	}
+	return tags
+}
+
+// resolveCertificate returns a field of a certificate. The certificate and
+// its private key are read from the backing secret, which needs only the
+// secrets 'Get' permission.
+func (p *AzureKeyVaultProvider) resolveCertificate(ctx context.Context, obj azureObjectRef) (provider.SecretValue, error) {
+	if err := obj.checkField(); err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	p.logger.Debug("Accessing Azure Key Vault certificate: %s", logging.Secret(obj.name))
+
+	resp, err := p.client.GetSecret(ctx, obj.name, obj.version, nil)
+	if err != nil {
+		if isAzureNotFoundError(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: "cert:" + obj.name}
+		}
+		return provider.SecretValue{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to access certificate: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+			Err:        classifyAzureError(p.name, err),
+		}
+	}
+	if resp.Value == nil {
+		return provider.SecretValue{}, fmt.Errorf("certificate has no value")
+	}
+
+	contentType := ""
+	if resp.ContentType != nil {
+		contentType = *resp.ContentType
+	}
+	bundle, err := parseCertificateBundle(*resp.Value, contentType)
+	if err != nil {
+		return provider.SecretValue{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to read certificate: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: "Check that the name refers to a Key Vault certificate rather than a plain secret",
+		}
+	}
+
+	value, err := bundle.field(obj.field)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	metadata := certificateTags(bundle.leaf)
+	metadata["source"] = fmt.Sprintf("azure-kv:cert:%s", obj.name)
+	metadata["vault_url"] = p.vaultURL
+	if resp.ID != nil {
+		metadata["version"] = resp.ID.Version()
+	}
+
+	return provider.SecretValue{
+		Value:    value,
+		Version:  metadata["version"],
+		Metadata: metadata,
+	}, nil
+}
+
+// describeCertificate returns certificate metadata, including its
+// thumbprint and expiry, without reading the private key
+func (p *AzureKeyVaultProvider) describeCertificate(ctx context.Context, obj azureObjectRef) (provider.Metadata, error) {
+	resp, err := p.certificates.GetCertificate(ctx, obj.name, obj.version, nil)
+	if err != nil {
+		if isAzureNotFoundError(err) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, fmt.Errorf("failed to describe certificate: %w", err)
+	}
+
+	metadata := provider.Metadata{
+		Exists: true,
+		Type:   "azure-certificate",
+		Tags: map[string]string{
+			"source":    fmt.Sprintf("azure-kv:cert:%s", obj.name),
+			"vault_url": p.vaultURL,
+		},
+	}
+	if resp.ID != nil {
+		metadata.Version = resp.ID.Version()
+	}
+	if len(resp.CER) > 0 {
+		cert, err := x509.ParseCertificate(resp.CER)
+		if err != nil {
+			return provider.Metadata{}, fmt.Errorf("failed to parse certificate %s: %w", obj.name, err)
+		}
+		for k, v := range certificateTags(cert) {
+			metadata.Tags[k] = v
+		}
+	} else if len(resp.X509Thumbprint) > 0 {
+		metadata.Tags["thumbprint"] = strings.ToUpper(hex.EncodeToString(resp.X509Thumbprint))
+	}
+	for k, v := range resp.Tags {
+		if v != nil {
+			metadata.Tags["tag_"+k] = *v
+		}
+	}
+
+	if attrs := resp.Attributes; attrs != nil {
+		if attrs.Created != nil {
+			metadata.UpdatedAt = *attrs.Created
+		}
+		if attrs.Updated != nil {
+			metadata.UpdatedAt = *attrs.Updated
+		}
+		if attrs.Expires != nil {
+			metadata.Tags["expires_at"] = attrs.Expires.UTC().Format(time.RFC3339)
+		}
+		if obj.version != "" {
+			disabled := attrs.Enabled != nil && !*attrs.Enabled
+			expired := attrs.Expires != nil && attrs.Expires.Before(time.Now())
+			metadata.Deprecated = disabled || expired
+		}
+	}
+
+	return metadata, nil
+}
+
+// rotateCertificate creates a new certificate version from the
+// certificate's current policy and waits for Key Vault to issue it
+func (p *AzureKeyVaultProvider) rotateCertificate(ctx context.Context, obj azureObjectRef, meta map[string]string) (string, error) {
+	policy, err := p.certificates.GetCertificatePolicy(ctx, obj.name, nil)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to read certificate policy: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
+
+	// Keep the current tags and record the rotation metadata alongside them
+	tags := map[string]*string{}
+	if current, err := p.certificates.GetCertificate(ctx, obj.name, "", nil); err == nil {
+		for k, v := range current.Tags {
+			tags[k] = v
+		}
+	}
+	for k, v := range meta {
+		tags[k] = to.Ptr(v)
+	}
+
+	if _, err := p.certificates.CreateCertificate(ctx, obj.name, azcertificates.CreateCertificateParameters{
+		CertificatePolicy: &policy.CertificatePolicy,
+		Tags:              tags,
+	}, nil); err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to create certificate version: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, azureCertificateTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(p.certPollInterval)
+	defer ticker.Stop()
+	for {
+		op, err := p.certificates.GetCertificateOperation(ctx, obj.name, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to check certificate operation for %s: %w", obj.name, err)
+		}
+
+		status := ""
+		if op.Status != nil {
+			status = *op.Status
+		}
+		switch {
+		case op.Error != nil:
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Certificate issuance failed: %s", obj.name),
+				Details:    op.Error.Error(),
+				Suggestion: "Check the certificate issuer configuration and the policy's subject and key settings",
+			}
+		case strings.EqualFold(status, "completed"):
+			cert, err := p.certificates.GetCertificate(ctx, obj.name, "", nil)
+			if err != nil {
+				return "", fmt.Errorf("failed to read rotated certificate %s: %w", obj.name, err)
+			}
+			if cert.ID == nil {
+				return "", nil
+			}
+			return cert.ID.Version(), nil
+		case !strings.EqualFold(status, "inProgress"):
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Certificate operation for %s ended with status '%s'", obj.name, status),
+				Suggestion: "Check the pending certificate operation in the Azure portal",
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Timed out waiting for certificate %s to be issued", obj.name),
+				Details:    ctx.Err().Error(),
+				Suggestion: "Certificates from external CAs may need manual approval or merging; the pending operation stays open in Key Vault",
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// deprecateCertificate disables an old certificate version
+func (p *AzureKeyVaultProvider) deprecateCertificate(ctx context.Context, obj azureObjectRef, version string) error {
+	_, err := p.certificates.UpdateCertificate(ctx, obj.name, version, azcertificates.UpdateCertificateParameters{
+		CertificateAttributes: &azcertificates.CertificateAttributes{Enabled: to.Ptr(false)},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to disable certificate version: %w", err)
+	}
+	return nil
+}
+
+// certificateRotationMetadata describes how a certificate is rotated,
+// including when its policy schedules the next automatic renewal
+func (p *AzureKeyVaultProvider) certificateRotationMetadata(ctx context.Context, obj azureObjectRef) (provider.RotationMetadata, error) {
+	resp, err := p.certificates.GetCertificate(ctx, obj.name, "", nil)
+	if err != nil {
+		return provider.RotationMetadata{}, fmt.Errorf("failed to get certificate metadata: %w", err)
+	}
+
+	metadata := provider.RotationMetadata{
+		SupportsRotation:   true,
+		SupportsVersioning: true,
+		Constraints: map[string]string{
+			"provider":  "azure-keyvault",
+			"vault_url": p.vaultURL,
+			// New versions are issued from the policy; supplied values are ignored
+			"value_source": "certificate_policy",
+		},
+	}
+
+	var expires *time.Time
+	if attrs := resp.Attributes; attrs != nil {
+		metadata.LastRotated = attrs.Created
+		expires = attrs.Expires
+	}
+
+	policy := resp.Policy
+	if policy == nil {
+		return metadata, nil
+	}
+	if policy.IssuerParameters != nil && policy.IssuerParameters.Name != nil {
+		metadata.Constraints["issuer"] = *policy.IssuerParameters.Name
+	}
+	if kp := policy.KeyProperties; kp != nil {
+		if kp.KeyType != nil {
+			metadata.Constraints["key_type"] = string(*kp.KeyType)
+		}
+		if kp.KeySize != nil {
+			metadata.Constraints["key_size"] = strconv.Itoa(int(*kp.KeySize))
+		}
+	}
+	if x509Props := policy.X509CertificateProperties; x509Props != nil && x509Props.ValidityInMonths != nil {
+		metadata.Constraints["validity_in_months"] = strconv.Itoa(int(*x509Props.ValidityInMonths))
+	}
+
+	// The next rotation is the policy's auto-renew trigger
+	for _, action := range policy.LifetimeActions {
+		if action == nil || action.Action == nil || action.Action.ActionType == nil ||
+			*action.Action.ActionType != azcertificates.CertificatePolicyActionAutoRenew || action.Trigger == nil {
+			continue
+		}
+		metadata.Constraints["auto_renew"] = "true"
+		if expires == nil {
+			break
+		}
+		switch {
+		case action.Trigger.DaysBeforeExpiry != nil:
+			next := expires.AddDate(0, 0, -int(*action.Trigger.DaysBeforeExpiry))
+			metadata.NextRotation = &next
+		case action.Trigger.LifetimePercentage != nil && metadata.LastRotated != nil:
+			lifetime := expires.Sub(*metadata.LastRotated)
+			next := metadata.LastRotated.Add(lifetime * time.Duration(*action.Trigger.LifetimePercentage) / 100)
+			metadata.NextRotation = &next
+		}
+		break
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,288 @@
+package providers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// resolveKey returns the public half of a Key Vault key as PEM or as a JWK
+func (p *AzureKeyVaultProvider) resolveKey(ctx context.Context, obj azureObjectRef) (provider.SecretValue, error) {
+	if err := obj.checkField(); err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	p.logger.Debug("Accessing Azure Key Vault key: %s", logging.Secret(obj.name))
+
+	resp, err := p.keys.GetKey(ctx, obj.name, obj.version, nil)
+	if err != nil {
+		if isAzureNotFoundError(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: "key:" + obj.name}
+		}
+		return provider.SecretValue{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to access key: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: getAzureErrorSuggestion(err),
+			Err:        classifyAzureError(p.name, err),
+		}
+	}
+	if resp.Key == nil {
+		return provider.SecretValue{}, fmt.Errorf("key has no key material")
+	}
+
+	var value string
+	if obj.field == "jwk" {
+		value, err = publicJWK(resp.Key)
+	} else {
+		value, err = publicKeyPEM(resp.Key)
+	}
+	if err != nil {
+		return provider.SecretValue{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to read key: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: "Only RSA and EC keys have a public key; symmetric keys never leave Key Vault",
+		}
+	}
+
+	metadata := map[string]string{
+		"source":    fmt.Sprintf("azure-kv:key:%s", obj.name),
+		"vault_url": p.vaultURL,
+	}
+	if resp.Key.Kty != nil {
+		metadata["key_type"] = string(*resp.Key.Kty)
+	}
+	if resp.Key.KID != nil {
+		metadata["version"] = resp.Key.KID.Version()
+	}
+
+	return provider.SecretValue{
+		Value:    value,
+		Version:  metadata["version"],
+		Metadata: metadata,
+	}, nil
+}
+
+// describeKey returns key metadata without the key material
+func (p *AzureKeyVaultProvider) describeKey(ctx context.Context, obj azureObjectRef) (provider.Metadata, error) {
+	resp, err := p.keys.GetKey(ctx, obj.name, obj.version, nil)
+	if err != nil {
+		if isAzureNotFoundError(err) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, fmt.Errorf("failed to describe key: %w", err)
+	}
+
+	metadata := provider.Metadata{
+		Exists: true,
+		Type:   "azure-key",
+		Tags: map[string]string{
+			"source":    fmt.Sprintf("azure-kv:key:%s", obj.name),
+			"vault_url": p.vaultURL,
+		},
+	}
+	if key := resp.Key; key != nil {
+		if key.KID != nil {
+			metadata.Version = key.KID.Version()
+		}
+		if key.Kty != nil {
+			metadata.Tags["key_type"] = string(*key.Kty)
+		}
+		if key.Crv != nil {
+			metadata.Tags["curve"] = string(*key.Crv)
+		}
+		if len(key.N) > 0 {
+			metadata.Tags["key_size"] = fmt.Sprintf("%d", new(big.Int).SetBytes(key.N).BitLen())
+		}
+		var ops []string
+		for _, op := range key.KeyOps {
+			if op != nil {
+				ops = append(ops, string(*op))
+			}
+		}
+		if len(ops) > 0 {
+			metadata.Tags["key_ops"] = strings.Join(ops, ",")
+		}
+	}
+	for k, v := range resp.Tags {
+		if v != nil {
+			metadata.Tags["tag_"+k] = *v
+		}
+	}
+
+	if attrs := resp.Attributes; attrs != nil {
+		if attrs.Created != nil {
+			metadata.UpdatedAt = *attrs.Created
+		}
+		if attrs.Updated != nil {
+			metadata.UpdatedAt = *attrs.Updated
+		}
+		if attrs.Expires != nil {
+			metadata.Tags["expires_at"] = attrs.Expires.UTC().Format(time.RFC3339)
+		}
+		if obj.version != "" {
+			disabled := attrs.Enabled != nil && !*attrs.Enabled
+			expired := attrs.Expires != nil && attrs.Expires.Before(time.Now())
+			metadata.Deprecated = disabled || expired
+		}
+	}
+
+	return metadata, nil
+}
+
+// rotateKey creates a new key version with the key's rotation policy
+func (p *AzureKeyVaultProvider) rotateKey(ctx context.Context, obj azureObjectRef) (string, error) {
+	resp, err := p.keys.RotateKey(ctx, obj.name, nil)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to rotate key: %s", obj.name),
+			Details:    err.Error(),
+			Suggestion: "Rotating a key needs the 'Rotate' key permission",
+		}
+	}
+	if resp.Key == nil || resp.Key.KID == nil {
+		return "", nil
+	}
+	return resp.Key.KID.Version(), nil
+}
+
+// deprecateKey disables an old key version
+func (p *AzureKeyVaultProvider) deprecateKey(ctx context.Context, obj azureObjectRef, version string) error {
+	_, err := p.keys.UpdateKey(ctx, obj.name, version, azkeys.UpdateKeyParameters{
+		KeyAttributes: &azkeys.KeyAttributes{Enabled: to.Ptr(false)},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to disable key version: %w", err)
+	}
+	return nil
+}
+
+// keyRotationMetadata describes how a key is rotated
+func (p *AzureKeyVaultProvider) keyRotationMetadata(ctx context.Context, obj azureObjectRef) (provider.RotationMetadata, error) {
+	resp, err := p.keys.GetKey(ctx, obj.name, "", nil)
+	if err != nil {
+		return provider.RotationMetadata{}, fmt.Errorf("failed to get key metadata: %w", err)
+	}
+
+	metadata := provider.RotationMetadata{
+		SupportsRotation:   true,
+		SupportsVersioning: true,
+		Constraints: map[string]string{
+			"provider":  "azure-keyvault",
+			"vault_url": p.vaultURL,
+			// New key material is generated by Key Vault; supplied values are ignored
+			"value_source": "key_rotation_policy",
+		},
+	}
+	if resp.Key != nil && resp.Key.Kty != nil {
+		metadata.Constraints["key_type"] = string(*resp.Key.Kty)
+	}
+	if resp.Attributes != nil {
+		metadata.LastRotated = resp.Attributes.Created
+	}
+	return metadata, nil
+}
+
+// jwkPublicKey converts the public half of a JSON web key to a crypto key
+func jwkPublicKey(key *azkeys.JSONWebKey) (any, error) {
+	kty := ""
+	if key.Kty != nil {
+		kty = string(*key.Kty)
+	}
+
+	switch azkeys.KeyType(kty) {
+	case azkeys.KeyTypeRSA, azkeys.KeyTypeRSAHSM:
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(key.N),
+			E: int(new(big.Int).SetBytes(key.E).Int64()),
+		}, nil
+	case azkeys.KeyTypeEC, azkeys.KeyTypeECHSM:
+		var curve elliptic.Curve
+		if key.Crv != nil {
+			switch *key.Crv {
+			case azkeys.CurveNameP256:
+				curve = elliptic.P256()
+			case azkeys.CurveNameP384:
+				curve = elliptic.P384()
+			case azkeys.CurveNameP521:
+				curve = elliptic.P521()
+			}
+		}
+		if curve == nil {
+			return nil, fmt.Errorf("unsupported elliptic curve")
+		}
+		// Parsing the uncompressed point checks that it lies on the curve
+		point := append([]byte{4}, padBytes(key.X, (curve.Params().BitSize+7)/8)...)
+		point = append(point, padBytes(key.Y, (curve.Params().BitSize+7)/8)...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC public key: %w", err)
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("key type %q has no public key", kty)
+	}
+}
+
+// padBytes left-pads b with zeros to size bytes
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
+
+// publicKeyPEM encodes the public half of a JSON web key as a PKIX PEM block
+func publicKeyPEM(key *azkeys.JSONWebKey) (string, error) {
+	pub, err := jwkPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// publicJWK returns the public members of a JSON web key as JSON
+func publicJWK(key *azkeys.JSONWebKey) (string, error) {
+	if _, err := jwkPublicKey(key); err != nil {
+		return "", err
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwk := map[string]string{"kty": strings.TrimSuffix(string(*key.Kty), "-HSM")}
+	if key.KID != nil {
+		jwk["kid"] = string(*key.KID)
+	}
+	if len(key.N) > 0 {
+		jwk["n"] = b64(key.N)
+		jwk["e"] = b64(key.E)
+	}
+	if key.Crv != nil {
+		jwk["crv"] = string(*key.Crv)
+		jwk["x"] = b64(key.X)
+		jwk["y"] = b64(key.Y)
+	}
+
+	data, err := json.Marshal(jwk)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWK: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+func TestAzureKeyVaultProvider_Keys(t *testing.T) {
+	p, _, _, keys := newAzureObjectsProvider(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	keys.AddKey("signing", "v1", azkeys.JSONWebKey{
+		Kty:    to.Ptr(azkeys.KeyTypeRSAHSM),
+		N:      rsaKey.N.Bytes(),
+		E:      big.NewInt(int64(rsaKey.E)).Bytes(),
+		KeyOps: []*azkeys.KeyOperation{to.Ptr(azkeys.KeyOperationSign), to.Ptr(azkeys.KeyOperationVerify)},
+	})
+	ecdhKey, err := ecKey.ECDH()
+	require.NoError(t, err)
+	point := ecdhKey.PublicKey().Bytes()
+	keys.AddKey("jwt", "v1", azkeys.JSONWebKey{
+		Kty: to.Ptr(azkeys.KeyTypeEC),
+		Crv: to.Ptr(azkeys.CurveNameP384),
+		X:   point[1:49],
+		Y:   point[49:],
+	})
+	keys.AddKey("wrap", "v1", azkeys.JSONWebKey{Kty: to.Ptr(azkeys.KeyTypeOctHSM)})
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "key:signing"})
+	require.NoError(t, err)
+	block, _ := pem.Decode([]byte(value.Value))
+	require.NotNil(t, block)
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(pub))
+	assert.Equal(t, "v1", value.Version)
+
+	value, err = p.Resolve(ctx, provider.Reference{Key: "key:jwt/v1/pem"})
+	require.NoError(t, err)
+	block, _ = pem.Decode([]byte(value.Value))
+	require.NotNil(t, block)
+	pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(pub))
+
+	value, err = p.Resolve(ctx, provider.Reference{Key: "key:jwt/jwk"})
+	require.NoError(t, err)
+	var jwk map[string]string
+	require.NoError(t, json.Unmarshal([]byte(value.Value), &jwk))
+	assert.Equal(t, "EC", jwk["kty"])
+	assert.Equal(t, "P-384", jwk["crv"])
+	assert.NotContains(t, jwk, "d")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "key:wrap"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Suggestion, "symmetric keys never leave Key Vault")
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "key:signing"})
+	require.NoError(t, err)
+	assert.Equal(t, "azure-key", meta.Type)
+	assert.Equal(t, "2048", meta.Tags["key_size"])
+	assert.Equal(t, "sign,verify", meta.Tags["key_ops"])
+
+	version, err := p.CreateNewVersion(ctx, provider.Reference{Key: "key:signing"}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", version)
+	require.NoError(t, p.DeprecateVersion(ctx, provider.Reference{Key: "key:signing"}, "v1"))
+	assert.False(t, *keys.Keys["signing"][0].Attributes.Enabled)
+}
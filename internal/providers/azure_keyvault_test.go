@@ -1,13 +1,17 @@
 package providers_test
 
 import (
+	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/systmms/dsops/internal/providers"
 	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
 	"github.com/systmms/dsops/tests/testutil"
 )
 
@@ -73,3 +77,36 @@ func TestAzureKeyVaultKeyFormats(t *testing.T) {
 		assert.NotEmpty(t, key)
 	}
 }
+
+func TestAzureKeyVaultResolveMissingSecret(t *testing.T) {
+	t.Parallel()
+
+	p, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(fakes.NewFakeAzureKeyVaultClient()))
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "missing-secret"})
+	var notFound *provider.NotFoundError
+	require.True(t, errors.As(err, &notFound), "got %v", err)
+	assert.Equal(t, "missing-secret", notFound.Key)
+}
+
+func TestAzureKeyVaultResolveServerErrorIsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeAzureKeyVaultClient()
+	client.AddError("down", &azcore.ResponseError{StatusCode: 503, ErrorCode: "ServiceUnavailable"})
+	client.AddError("denied", &azcore.ResponseError{StatusCode: 403, ErrorCode: "Forbidden"})
+	p, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(client))
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "down"})
+	assert.True(t, provider.IsUnavailable(err), "got %v", err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "denied"})
+	require.Error(t, err)
+	assert.False(t, provider.IsUnavailable(err), "denied access is an answer from the store")
+}
@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/systmms/dsops/internal/atomicfile"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// azureCLIClientID is the public client dsops signs in as with a device
+// code: the Azure CLI's, which azidentity's DeviceCodeCredential also uses
+const azureCLIClientID = "04b07795-8ddb-461a-bbee-02f9e1bf7b46"
+
+// azureLoginCachePath is the token cache written by dsops login for Azure
+// stores with use_device_code. Like the Azure CLI's msal_token_cache.json it
+// is a plain file readable by the owner only.
+func azureLoginCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(dir, "dsops", "azure", "msal_token_cache.json"), nil
+}
+
+// azureTokenCacheFile persists the MSAL token cache to a file
+type azureTokenCacheFile struct {
+	path string
+}
+
+// Replace loads the cache from the file; a missing file is an empty cache
+func (f azureTokenCacheFile) Replace(ctx context.Context, c cache.Unmarshaler, hints cache.ReplaceHints) error {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the Azure token cache: %w", err)
+	}
+	return c.Unmarshal(data)
+}
+
+// Export writes the cache to the file, replacing it atomically
+func (f azureTokenCacheFile) Export(ctx context.Context, c cache.Marshaler, hints cache.ExportHints) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(f.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write the Azure token cache: %w", err)
+	}
+	return nil
+}
+
+// azureLoginCredential is an azcore.TokenCredential for the account signed
+// in with dsops login. Tokens come from the cached refresh token, so it never
+// prompts; an expired login has to be renewed with dsops login.
+type azureLoginCredential struct {
+	tenantID string
+	client   public.Client
+}
+
+// newAzureLoginCredential creates the credential for a tenant. An empty
+// tenant accepts any work or school account.
+func newAzureLoginCredential(tenantID string) (*azureLoginCredential, error) {
+	path, err := azureLoginCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	authority := "organizations"
+	if tenantID != "" {
+		authority = tenantID
+	}
+	client, err := public.New(azureCLIClientID,
+		public.WithAuthority("https://login.microsoftonline.com/"+authority),
+		public.WithCache(azureTokenCacheFile{path: path}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure login client: %w", err)
+	}
+
+	return &azureLoginCredential{tenantID: tenantID, client: client}, nil
+}
+
+// GetToken returns an access token for the signed-in account
+func (c *azureLoginCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	account, err := c.account(ctx)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+
+	result, err := c.client.AcquireTokenSilent(ctx, opts.Scopes, public.WithSilentAccount(account))
+	if err != nil {
+		return azcore.AccessToken{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Azure login for %s has expired", account.PreferredUsername),
+			Details:    err.Error(),
+			Suggestion: "Run 'dsops login <store>' to sign in again",
+		}
+	}
+	return azcore.AccessToken{Token: result.AccessToken, ExpiresOn: result.ExpiresOn}, nil
+}
+
+// account returns the cached account for the tenant
+func (c *azureLoginCredential) account(ctx context.Context) (public.Account, error) {
+	accounts, err := c.client.Accounts(ctx)
+	if err != nil {
+		return public.Account{}, fmt.Errorf("failed to read the Azure token cache: %w", err)
+	}
+	for _, account := range accounts {
+		if c.tenantID == "" || account.Realm == c.tenantID {
+			return account, nil
+		}
+	}
+
+	details := "No account is signed in"
+	if c.tenantID != "" {
+		details = fmt.Sprintf("No account is signed in to tenant %s", c.tenantID)
+	}
+	return public.Account{}, dserrors.UserError{
+		Message:    "No Azure login found",
+		Details:    details,
+		Suggestion: "Run 'dsops login <store>' for a store with use_device_code: true",
+	}
+}
+
+// login signs in with a device code and caches the tokens
+func (c *azureLoginCredential) login(ctx context.Context, scopes []string, prompt provider.LoginPrompt) error {
+	code, err := c.client.AcquireTokenByDeviceCode(ctx, scopes)
+	if err != nil {
+		return fmt.Errorf("failed to start the device code login: %w", err)
+	}
+
+	prompt.Visit(code.Result.VerificationURL, code.Result.UserCode)
+
+	if _, err := code.AuthenticationResult(ctx); err != nil {
+		return fmt.Errorf("device code login failed: %w", err)
+	}
+	return nil
+}
+
+// azureDeviceCodeLogin runs dsops login for an Azure store
+func azureDeviceCodeLogin(ctx context.Context, store, tenantID string, useDeviceCode bool, scopes []string, prompt provider.LoginPrompt) error {
+	if !useDeviceCode {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Store '%s' does not use a dsops login", store),
+			Suggestion: "Set use_device_code: true on the store to sign in with a device code, or use 'az login' with use_managed_identity: false",
+		}
+	}
+
+	credential, err := newAzureLoginCredential(tenantID)
+	if err != nil {
+		return err
+	}
+	if err := credential.login(ctx, scopes, prompt); err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Azure login for store '%s' failed", store),
+			Details:    err.Error(),
+			Suggestion: "Check tenant_id and that your account can sign in to the tenant",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+type memoryCache struct{ data []byte }
+
+func (c *memoryCache) Marshal() ([]byte, error) { return c.data, nil }
+func (c *memoryCache) Unmarshal(data []byte) error {
+	c.data = data
+	return nil
+}
+
+func TestAzureTokenCacheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "azure", "msal_token_cache.json")
+	file := azureTokenCacheFile{path: path}
+	ctx := context.Background()
+
+	// A missing file is an empty cache
+	loaded := &memoryCache{}
+	require.NoError(t, file.Replace(ctx, loaded, cache.ReplaceHints{}))
+	assert.Nil(t, loaded.data)
+
+	require.NoError(t, file.Export(ctx, &memoryCache{data: []byte(`{"AccessToken":{}}`)}, cache.ExportHints{}))
+	require.NoError(t, file.Replace(ctx, loaded, cache.ReplaceHints{}))
+	assert.Equal(t, `{"AccessToken":{}}`, string(loaded.data))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestAzureLoginCredential_NotSignedIn(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	credential, err := newAzureLoginCredential("00000000-0000-0000-0000-000000000001")
+	require.NoError(t, err)
+
+	_, err = credential.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "No Azure login found", userErr.Message)
+	assert.Contains(t, userErr.Details, "00000000-0000-0000-0000-000000000001")
+	assert.Contains(t, userErr.Suggestion, "dsops login")
+}
+
+func TestAzureKeyVaultProvider_LoginRequiresDeviceCode(t *testing.T) {
+	p, err := NewAzureKeyVaultProvider("kv", map[string]interface{}{
+		"vault_url": "https://example.vault.azure.net/",
+	}, WithAzureKeyVaultClient(fakes.NewFakeAzureKeyVaultClient()))
+	require.NoError(t, err)
+
+	var authenticator provider.Authenticator = p
+	err = authenticator.Login(context.Background(), nil)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "does not use a dsops login")
+	assert.Contains(t, userErr.Suggestion, "use_device_code: true")
+}
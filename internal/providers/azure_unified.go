@@ -16,6 +16,7 @@ type AzureUnifiedProvider struct {
 	logger         *logging.Logger
 	providers      map[string]provider.Provider
 	defaultService string
+	config         UnifiedAzureConfig
 }
 
 // UnifiedAzureConfig holds configuration for the unified Azure provider
@@ -25,6 +26,7 @@ type UnifiedAzureConfig struct {
 	ClientSecret       string
 	UseManagedIdentity bool
 	UserAssignedID     string
+	UseDeviceCode      bool   // Use the account signed in with dsops login
 	DefaultService     string // Default service if not specified in reference
 
 	// Service-specific configs
@@ -60,6 +62,9 @@ func NewAzureUnifiedProvider(name string, configMap map[string]interface{}) (*Az
 	if userAssignedID, ok := configMap["user_assigned_identity_id"].(string); ok {
 		config.UserAssignedID = userAssignedID
 	}
+	if useDeviceCode, ok := configMap["use_device_code"].(bool); ok {
+		config.UseDeviceCode = useDeviceCode
+	}
 	if defaultService, ok := configMap["default_service"].(string); ok {
 		config.DefaultService = defaultService
 	}
@@ -104,6 +109,7 @@ func NewAzureUnifiedProvider(name string, configMap map[string]interface{}) (*Az
 		logger:         logger,
 		providers:      providers,
 		defaultService: config.DefaultService,
+		config:         config,
 	}, nil
 }
 
@@ -123,6 +129,9 @@ func getAzureCommonConfig(config UnifiedAzureConfig) map[string]interface{} {
 	if config.UserAssignedID != "" {
 		common["user_assigned_identity_id"] = config.UserAssignedID
 	}
+	if config.UseDeviceCode {
+		common["use_device_code"] = true
+	}
 	return common
 }
 
@@ -290,6 +299,12 @@ func (p *AzureUnifiedProvider) Validate(ctx context.Context) error {
 	return nil
 }
 
+// Login signs in to Azure with a device code for stores with
+// use_device_code. The login is shared by all Azure services of the store.
+func (p *AzureUnifiedProvider) Login(ctx context.Context, prompt provider.LoginPrompt) error {
+	return azureDeviceCodeLogin(ctx, p.name, p.config.TenantID, p.config.UseDeviceCode, []string{"https://management.azure.com/.default"}, prompt)
+}
+
 // NewAzureUnifiedProviderFactory creates an Azure unified provider factory
 func NewAzureUnifiedProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
 	return NewAzureUnifiedProvider(name, config)
@@ -0,0 +1,438 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers/contracts"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// bitwardenSMBatchSize bounds the number of secrets fetched per request
+const bitwardenSMBatchSize = 100
+
+var bitwardenSMIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// BitwardenSMProvider implements the provider interface for Bitwarden
+// Secrets Manager over its HTTP API, authenticating with a machine account
+// access token. Unlike the bitwarden store it needs no CLI or unlocked vault.
+//
+// On first use the provider lists every secret the machine account can read
+// (or those of project_id) and fetches their values in batches, so resolving
+// many secrets costs a handful of requests. Decrypted values are kept in
+// memory for the life of the process only.
+type BitwardenSMProvider struct {
+	name       string
+	config     BitwardenSMConfig
+	client     contracts.BitwardenSMClient
+	tokenCache *TokenCache
+
+	mu      sync.Mutex
+	orgKey  *bitwardenSMKey
+	orgID   string
+	secrets []bitwardenSMEntry
+}
+
+// bitwardenSMEntry is a decrypted secret
+type bitwardenSMEntry struct {
+	ID        string
+	Name      string
+	Value     string
+	Note      string
+	ProjectID string
+	UpdatedAt time.Time
+}
+
+// NewBitwardenSMProvider creates a new Bitwarden Secrets Manager provider
+func NewBitwardenSMProvider(name string, config map[string]interface{}) (*BitwardenSMProvider, error) {
+	cfg := parseBitwardenSMConfig(config)
+
+	client, err := newBitwardenSMHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitwardenSMProvider{
+		name:       name,
+		config:     cfg,
+		client:     client,
+		tokenCache: NewTokenCache(),
+	}, nil
+}
+
+// NewBitwardenSMProviderWithClient creates a Bitwarden Secrets Manager provider with a custom client.
+// This is primarily for testing, allowing the HTTP client to be mocked.
+func NewBitwardenSMProviderWithClient(name string, config map[string]interface{}, client contracts.BitwardenSMClient) *BitwardenSMProvider {
+	return &BitwardenSMProvider{
+		name:       name,
+		config:     parseBitwardenSMConfig(config),
+		client:     client,
+		tokenCache: NewTokenCache(),
+	}
+}
+
+// Name returns the provider name
+func (p *BitwardenSMProvider) Name() string {
+	return p.name
+}
+
+// Resolve retrieves a secret by ID or name
+func (p *BitwardenSMProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	secret, err := p.lookup(ctx, ref.Key)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	return provider.SecretValue{
+		Value:     secret.Value,
+		UpdatedAt: secret.UpdatedAt,
+		Metadata: map[string]string{
+			"provider":   p.name,
+			"secret_id":  secret.ID,
+			"project_id": secret.ProjectID,
+		},
+	}, nil
+}
+
+// Describe returns metadata about a secret without returning its value
+func (p *BitwardenSMProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	secret, err := p.lookup(ctx, ref.Key)
+	if err != nil {
+		var notFound *provider.NotFoundError
+		if errors.As(err, &notFound) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, err
+	}
+
+	return provider.Metadata{
+		Exists:    true,
+		UpdatedAt: secret.UpdatedAt,
+		Type:      "secret",
+		Tags: map[string]string{
+			"id":         secret.ID,
+			"name":       secret.Name,
+			"project_id": secret.ProjectID,
+		},
+	}, nil
+}
+
+// ListSecrets lists the names of the secrets the machine account can read
+func (p *BitwardenSMProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	listed, err := p.client.ListSecrets(ctx, token, p.orgID, p.config.ProjectID)
+	if err != nil {
+		return provider.ListResult{}, fmt.Errorf("failed to list bitwarden secrets: %w", err)
+	}
+
+	secrets := make([]provider.SecretInfo, 0, len(listed))
+	for _, s := range listed {
+		name, err := p.orgKey.decryptString(s.Key)
+		if err != nil {
+			return provider.ListResult{}, &BitwardenSMError{Op: "decrypt", Message: fmt.Sprintf("secret %s", s.ID), Err: err}
+		}
+		secrets = append(secrets, provider.SecretInfo{Key: name, UpdatedAt: s.UpdatedAt})
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// Capabilities returns the provider's supported features
+func (p *BitwardenSMProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false,
+		SupportsMetadata:   true,
+		SupportsWatching:   false,
+		SupportsBinary:     false,
+		RequiresAuth:       true,
+		AuthMethods:        []string{"access_token"},
+	}
+}
+
+// Validate checks that the access token is accepted and secrets can be listed
+func (p *BitwardenSMProvider) Validate(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return fmt.Errorf("bitwarden secrets manager validation failed: %w", err)
+	}
+	if _, err := p.client.ListSecrets(ctx, token, p.orgID, p.config.ProjectID); err != nil {
+		return fmt.Errorf("bitwarden secrets manager validation failed: %w", err)
+	}
+	return nil
+}
+
+// lookup finds a secret by ID or name, loading all secrets on first use
+func (p *BitwardenSMProvider) lookup(ctx context.Context, key string) (*bitwardenSMEntry, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("bitwarden secret reference cannot be empty")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.secrets == nil {
+		if err := p.loadSecrets(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+
+	byID := bitwardenSMIDPattern.MatchString(key)
+	var matches []*bitwardenSMEntry
+	for i := range p.secrets {
+		s := &p.secrets[i]
+		if (byID && strings.EqualFold(s.ID, key)) || (!byID && s.Name == key) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("%d Bitwarden secrets are named '%s'", len(matches), key),
+			Suggestion: "Reference the secret by its ID, or set project_id on the store",
+		}
+	case byID:
+		// The ID may be outside project_id; fetch it directly
+		return p.fetchByID(ctx, token, key)
+	}
+
+	return nil, &provider.NotFoundError{Provider: p.name, Key: key}
+}
+
+// loadSecrets lists the secrets in scope and fetches their values in batches
+func (p *BitwardenSMProvider) loadSecrets(ctx context.Context, token string) error {
+	listed, err := p.client.ListSecrets(ctx, token, p.orgID, p.config.ProjectID)
+	if err != nil {
+		return &BitwardenSMError{Op: "list", Message: err.Error(), Err: err}
+	}
+
+	ids := make([]string, len(listed))
+	for i, s := range listed {
+		ids[i] = s.ID
+	}
+
+	secrets := make([]bitwardenSMEntry, 0, len(ids))
+	for start := 0; start < len(ids); start += bitwardenSMBatchSize {
+		end := min(start+bitwardenSMBatchSize, len(ids))
+		batch, err := p.client.GetSecretsByIDs(ctx, token, ids[start:end])
+		if err != nil {
+			return &BitwardenSMError{Op: "fetch", Message: err.Error(), Err: err}
+		}
+		for _, s := range batch {
+			entry, err := p.decryptSecret(s)
+			if err != nil {
+				return err
+			}
+			secrets = append(secrets, entry)
+		}
+	}
+
+	p.secrets = secrets
+	return nil
+}
+
+// fetchByID retrieves a secret that was not part of the listing
+func (p *BitwardenSMProvider) fetchByID(ctx context.Context, token, id string) (*bitwardenSMEntry, error) {
+	secret, err := p.client.GetSecret(ctx, token, id)
+	if err != nil {
+		if errors.Is(err, ErrBitwardenSMSecretNotFound) {
+			return nil, &provider.NotFoundError{Provider: p.name, Key: id}
+		}
+		return nil, &BitwardenSMError{Op: "fetch", Message: err.Error(), Err: err}
+	}
+
+	entry, err := p.decryptSecret(*secret)
+	if err != nil {
+		return nil, err
+	}
+	p.secrets = append(p.secrets, entry)
+	return &p.secrets[len(p.secrets)-1], nil
+}
+
+// decryptSecret decrypts the name, value and note of a secret
+func (p *BitwardenSMProvider) decryptSecret(s contracts.BitwardenSMSecret) (bitwardenSMEntry, error) {
+	entry := bitwardenSMEntry{ID: s.ID, ProjectID: s.ProjectID, UpdatedAt: s.UpdatedAt}
+
+	fields := []struct {
+		enc string
+		out *string
+	}{{s.Key, &entry.Name}, {s.Value, &entry.Value}, {s.Note, &entry.Note}}
+	for _, f := range fields {
+		if f.enc == "" {
+			continue
+		}
+		plain, err := p.orgKey.decryptString(f.enc)
+		if err != nil {
+			return bitwardenSMEntry{}, &BitwardenSMError{Op: "decrypt", Message: fmt.Sprintf("secret %s", s.ID), Err: err}
+		}
+		*f.out = plain
+	}
+	return entry, nil
+}
+
+// getToken returns a cached API token or logs in with the access token.
+// Logging in also releases the organization key. Callers hold p.mu.
+func (p *BitwardenSMProvider) getToken(ctx context.Context) (string, error) {
+	// Check cache first
+	if token, ok := p.tokenCache.Get(); ok {
+		return token, nil
+	}
+
+	accessToken := p.config.AccessToken
+	if accessToken == "" {
+		accessToken = os.Getenv("BWS_ACCESS_TOKEN")
+	}
+	if accessToken == "" {
+		return "", dserrors.ConfigError{
+			Field:      "access_token",
+			Message:    fmt.Sprintf("store '%s' has no Bitwarden access token", p.name),
+			Suggestion: "Set access_token on the store or export BWS_ACCESS_TOKEN",
+		}
+	}
+
+	parsed, err := parseBitwardenSMAccessToken(accessToken)
+	if err != nil {
+		return "", dserrors.ConfigError{
+			Field:      "access_token",
+			Message:    err.Error(),
+			Suggestion: "Create a machine account access token in Bitwarden Secrets Manager",
+		}
+	}
+
+	session, err := p.client.Login(ctx, parsed.ID, parsed.ClientSecret)
+	if err != nil {
+		return "", &BitwardenSMError{Op: "auth", Message: err.Error(), Err: err}
+	}
+
+	payload, err := parsed.key.decrypt(session.EncryptedPayload)
+	if err != nil {
+		return "", &BitwardenSMError{Op: "decrypt", Message: "organization key", Err: err}
+	}
+	var keys struct {
+		EncryptionKey string `json:"encryptionKey"`
+	}
+	if err := json.Unmarshal(payload, &keys); err != nil {
+		return "", &BitwardenSMError{Op: "decrypt", Message: "organization key", Err: err}
+	}
+	orgKey, err := parseBitwardenSMKey(keys.EncryptionKey)
+	if err != nil {
+		return "", &BitwardenSMError{Op: "decrypt", Message: "organization key", Err: err}
+	}
+
+	orgID := p.config.OrganizationID
+	if orgID == "" {
+		orgID, err = bitwardenSMOrganization(session.AccessToken)
+		if err != nil {
+			return "", &BitwardenSMError{Op: "auth", Message: err.Error(), Err: err}
+		}
+	}
+
+	p.orgKey = &orgKey
+	p.orgID = orgID
+	p.tokenCache.Set(session.AccessToken, session.ExpiresIn)
+
+	return session.AccessToken, nil
+}
+
+// bitwardenSMOrganization reads the organization claim of an API token.
+// The token is only inspected, not verified; the API verifies it.
+func bitwardenSMOrganization(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("unexpected API token format")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("unexpected API token format: %w", err)
+	}
+
+	var claims struct {
+		Organization string `json:"organization"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("unexpected API token format: %w", err)
+	}
+	if claims.Organization == "" {
+		return "", fmt.Errorf("API token has no organization; set organization_id")
+	}
+	return claims.Organization, nil
+}
+
+// parseBitwardenSMConfig parses configuration map into BitwardenSMConfig
+func parseBitwardenSMConfig(config map[string]interface{}) BitwardenSMConfig {
+	cfg := BitwardenSMConfig{
+		APIURL:      DefaultBitwardenAPIURL,
+		IdentityURL: DefaultBitwardenIdentityURL,
+		Timeout:     DefaultTimeout,
+	}
+
+	if config == nil {
+		return cfg
+	}
+
+	if token, ok := config["access_token"].(string); ok {
+		cfg.AccessToken = token
+	}
+
+	if orgID, ok := config["organization_id"].(string); ok {
+		cfg.OrganizationID = orgID
+	}
+
+	if projectID, ok := config["project_id"].(string); ok {
+		cfg.ProjectID = projectID
+	}
+
+	if serverURL, ok := config["server_url"].(string); ok && serverURL != "" {
+		cfg.ServerURL = strings.TrimSuffix(serverURL, "/")
+		cfg.APIURL = cfg.ServerURL + "/api"
+		cfg.IdentityURL = cfg.ServerURL + "/identity"
+	}
+
+	if apiURL, ok := config["api_url"].(string); ok && apiURL != "" {
+		cfg.APIURL = apiURL
+	}
+
+	if identityURL, ok := config["identity_url"].(string); ok && identityURL != "" {
+		cfg.IdentityURL = identityURL
+	}
+
+	if timeout, ok := config["timeout"].(string); ok {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			cfg.Timeout = d
+		}
+	}
+
+	if caCert, ok := config["ca_cert"].(string); ok {
+		cfg.CACert = caCert
+	}
+
+	return cfg
+}
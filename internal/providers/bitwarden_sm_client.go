@@ -0,0 +1,239 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/systmms/dsops/internal/providers/contracts"
+)
+
+// bitwardenSMHTTPClient implements BitwardenSMClient using the Bitwarden
+// identity and API services
+type bitwardenSMHTTPClient struct {
+	httpClient  *http.Client
+	apiURL      string
+	identityURL string
+}
+
+// newBitwardenSMHTTPClient creates a new HTTP client for Bitwarden Secrets Manager
+func newBitwardenSMHTTPClient(cfg BitwardenSMConfig) (*bitwardenSMHTTPClient, error) {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{},
+	}
+
+	// Configure custom CA if provided
+	if cfg.CACert != "" {
+		caCert, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+
+		transport.TLSClientConfig.RootCAs = caCertPool
+	}
+
+	return &bitwardenSMHTTPClient{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+		},
+		apiURL:      strings.TrimSuffix(cfg.APIURL, "/"),
+		identityURL: strings.TrimSuffix(cfg.IdentityURL, "/"),
+	}, nil
+}
+
+// Login exchanges the access token credentials for an API token
+func (c *bitwardenSMHTTPClient) Login(ctx context.Context, clientID, clientSecret string) (*contracts.BitwardenSMSession, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"scope":         {"api.secrets"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.identityURL+"/connect/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, &BitwardenSMError{
+			Op:         "auth",
+			StatusCode: resp.StatusCode,
+			Message:    string(bodyBytes),
+		}
+	}
+
+	var authResp struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		EncryptedPayload string `json:"encrypted_payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return nil, fmt.Errorf("failed to decode auth response: %w", err)
+	}
+
+	ttl := time.Duration(authResp.ExpiresIn) * time.Second
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+
+	return &contracts.BitwardenSMSession{
+		AccessToken:      authResp.AccessToken,
+		ExpiresIn:        ttl,
+		EncryptedPayload: authResp.EncryptedPayload,
+	}, nil
+}
+
+// bitwardenSMSecretModel is a secret as returned by the API
+type bitwardenSMSecretModel struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	ProjectID      string    `json:"projectId"`
+	Key            string    `json:"key"`
+	Value          string    `json:"value"`
+	Note           string    `json:"note"`
+	CreationDate   time.Time `json:"creationDate"`
+	RevisionDate   time.Time `json:"revisionDate"`
+	Projects       []struct {
+		ID string `json:"id"`
+	} `json:"projects"`
+}
+
+func (m bitwardenSMSecretModel) toSecret() contracts.BitwardenSMSecret {
+	projectID := m.ProjectID
+	if projectID == "" && len(m.Projects) > 0 {
+		projectID = m.Projects[0].ID
+	}
+	return contracts.BitwardenSMSecret{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		ProjectID:      projectID,
+		Key:            m.Key,
+		Value:          m.Value,
+		Note:           m.Note,
+		CreatedAt:      m.CreationDate,
+		UpdatedAt:      m.RevisionDate,
+	}
+}
+
+// ListSecrets lists secret identifiers of an organization or project
+func (c *bitwardenSMHTTPClient) ListSecrets(ctx context.Context, token, organizationID, projectID string) ([]contracts.BitwardenSMSecret, error) {
+	path := fmt.Sprintf("/organizations/%s/secrets", url.PathEscape(organizationID))
+	if projectID != "" {
+		path = fmt.Sprintf("/projects/%s/secrets", url.PathEscape(projectID))
+	}
+
+	var listResp struct {
+		Secrets []bitwardenSMSecretModel `json:"secrets"`
+	}
+	if err := c.do(ctx, "list", "GET", path, token, nil, &listResp); err != nil {
+		return nil, err
+	}
+
+	secrets := make([]contracts.BitwardenSMSecret, len(listResp.Secrets))
+	for i, s := range listResp.Secrets {
+		secrets[i] = s.toSecret()
+	}
+	return secrets, nil
+}
+
+// GetSecret retrieves a single secret by ID
+func (c *bitwardenSMHTTPClient) GetSecret(ctx context.Context, token, id string) (*contracts.BitwardenSMSecret, error) {
+	var secret bitwardenSMSecretModel
+	if err := c.do(ctx, "fetch", "GET", "/secrets/"+url.PathEscape(id), token, nil, &secret); err != nil {
+		return nil, err
+	}
+	result := secret.toSecret()
+	return &result, nil
+}
+
+// GetSecretsByIDs retrieves several secrets in one request
+func (c *bitwardenSMHTTPClient) GetSecretsByIDs(ctx context.Context, token string, ids []string) ([]contracts.BitwardenSMSecret, error) {
+	body := map[string][]string{"ids": ids}
+
+	var batchResp struct {
+		Data []bitwardenSMSecretModel `json:"data"`
+	}
+	if err := c.do(ctx, "fetch", "POST", "/secrets/get-by-ids", token, body, &batchResp); err != nil {
+		return nil, err
+	}
+
+	secrets := make([]contracts.BitwardenSMSecret, len(batchResp.Data))
+	for i, s := range batchResp.Data {
+		secrets[i] = s.toSecret()
+	}
+	return secrets, nil
+}
+
+// do sends an authenticated API request and decodes the JSON response
+func (c *bitwardenSMHTTPClient) do(ctx context.Context, op, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrBitwardenSMSecretNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return &BitwardenSMError{
+			Op:         op,
+			StatusCode: resp.StatusCode,
+			Message:    string(bodyBytes),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Ensure bitwardenSMHTTPClient implements contracts.BitwardenSMClient
+var _ contracts.BitwardenSMClient = (*bitwardenSMHTTPClient)(nil)
@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Bitwarden Secrets Manager encrypts secrets client-side. A machine account
+// access token carries a 16-byte seed from which the key protecting the
+// organization key is derived; the organization key in turn decrypts
+// secret names, values and notes. Only encryption type 2
+// (AES-256-CBC with HMAC-SHA256) is used by Secrets Manager.
+
+// bitwardenSMEncType is the only encrypted string type Secrets Manager issues
+const bitwardenSMEncType = "2"
+
+// bitwardenSMKey is a symmetric key pair: AES-256 for encryption and
+// HMAC-SHA256 for authentication
+type bitwardenSMKey struct {
+	encKey []byte
+	macKey []byte
+}
+
+// bitwardenSMAccessToken is a parsed machine account access token of the
+// form "0.<access token id>.<client secret>:<base64 key seed>"
+type bitwardenSMAccessToken struct {
+	ID           string
+	ClientSecret string
+	key          bitwardenSMKey
+}
+
+// parseBitwardenSMAccessToken splits an access token and derives the key
+// that decrypts the organization key
+func parseBitwardenSMAccessToken(token string) (*bitwardenSMAccessToken, error) {
+	credentials, seed, ok := strings.Cut(strings.TrimSpace(token), ":")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing encryption key", ErrBitwardenSMInvalidToken)
+	}
+
+	parts := strings.Split(credentials, ".")
+	if len(parts) != 3 || parts[0] != "0" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("%w: expected 0.<id>.<secret>:<key>", ErrBitwardenSMInvalidToken)
+	}
+
+	seedBytes, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(seedBytes) != 16 {
+		return nil, fmt.Errorf("%w: encryption key must be 16 bytes of base64", ErrBitwardenSMInvalidToken)
+	}
+
+	key, err := deriveBitwardenSMAccessKey(seedBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bitwardenSMAccessToken{ID: parts[1], ClientSecret: parts[2], key: key}, nil
+}
+
+// deriveBitwardenSMAccessKey stretches an access token seed into a key pair
+// with HMAC-SHA256 extraction and HKDF expansion
+func deriveBitwardenSMAccessKey(seed []byte) (bitwardenSMKey, error) {
+	mac := hmac.New(sha256.New, []byte("bitwarden-accesstoken"))
+	mac.Write(seed)
+	prk := mac.Sum(nil)
+
+	key, err := hkdf.Expand(sha256.New, prk, "sm-access-token", 64)
+	if err != nil {
+		return bitwardenSMKey{}, fmt.Errorf("failed to derive access token key: %w", err)
+	}
+	return bitwardenSMKey{encKey: key[:32], macKey: key[32:]}, nil
+}
+
+// parseBitwardenSMKey decodes a base64 64-byte key pair such as the
+// organization key
+func parseBitwardenSMKey(encoded string) (bitwardenSMKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return bitwardenSMKey{}, fmt.Errorf("invalid key encoding: %w", err)
+	}
+	if len(key) != 64 {
+		return bitwardenSMKey{}, fmt.Errorf("invalid key length %d", len(key))
+	}
+	return bitwardenSMKey{encKey: key[:32], macKey: key[32:]}, nil
+}
+
+// decryptString decrypts an encrypted string "2.<iv>|<data>|<mac>"
+func (k bitwardenSMKey) decryptString(encString string) (string, error) {
+	plaintext, err := k.decrypt(encString)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (k bitwardenSMKey) decrypt(encString string) ([]byte, error) {
+	encType, payload, ok := strings.Cut(encString, ".")
+	if !ok || encType != bitwardenSMEncType {
+		return nil, fmt.Errorf("unsupported encryption type %q", encType)
+	}
+
+	parts := strings.Split(payload, "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed encrypted string")
+	}
+
+	var decoded [3][]byte
+	for i, part := range parts {
+		b, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("malformed encrypted string: %w", err)
+		}
+		decoded[i] = b
+	}
+	iv, data, tag := decoded[0], decoded[1], decoded[2]
+
+	mac := hmac.New(sha256.New, k.macKey)
+	mac.Write(iv)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), tag) {
+		return nil, fmt.Errorf("MAC verification failed")
+	}
+
+	block, err := aes.NewCipher(k.encKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("malformed encrypted string")
+	}
+
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+	// Remove PKCS#7 padding
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, fmt.Errorf("invalid padding")
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
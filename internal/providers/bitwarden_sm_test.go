@@ -0,0 +1,376 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+const bitwardenSMTestOrg = "7f3c5b6e-1a2b-4c3d-8e9f-0a1b2c3d4e5f"
+
+// encrypt produces a type 2 encrypted string, as the Bitwarden clients do
+func (k bitwardenSMKey) encrypt(t *testing.T, plaintext []byte) string {
+	t.Helper()
+	iv := make([]byte, aes.BlockSize)
+	_, err := rand.Read(iv)
+	require.NoError(t, err)
+
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	block, err := aes.NewCipher(k.encKey)
+	require.NoError(t, err)
+	data := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, padded)
+
+	mac := hmac.New(sha256.New, k.macKey)
+	mac.Write(iv)
+	mac.Write(data)
+
+	enc := base64.StdEncoding.EncodeToString
+	return "2." + enc(iv) + "|" + enc(data) + "|" + enc(mac.Sum(nil))
+}
+
+type bitwardenSMTestSecret struct {
+	name, value, project string
+}
+
+// bitwardenSMServer fakes the Bitwarden identity and API services
+type bitwardenSMServer struct {
+	t           *testing.T
+	srv         *httptest.Server
+	accessToken string
+	orgKey      bitwardenSMKey
+
+	mu       sync.Mutex
+	secrets  map[string]bitwardenSMTestSecret
+	nextID   int
+	requests []string
+}
+
+func newBitwardenSMServer(t *testing.T) *bitwardenSMServer {
+	t.Helper()
+
+	seed := make([]byte, 16)
+	orgKeyBytes := make([]byte, 64)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	_, err = rand.Read(orgKeyBytes)
+	require.NoError(t, err)
+
+	s := &bitwardenSMServer{
+		t:           t,
+		accessToken: "0.ec2c1d46-6a4b-4751-a310-af9601317f2d.C2IgxjjLF7qSshsbwe8JGcbM075YXw:" + base64.StdEncoding.EncodeToString(seed),
+		orgKey:      bitwardenSMKey{encKey: orgKeyBytes[:32], macKey: orgKeyBytes[32:]},
+		secrets:     map[string]bitwardenSMTestSecret{},
+	}
+
+	accessKey, err := deriveBitwardenSMAccessKey(seed)
+	require.NoError(t, err)
+	payload, err := json.Marshal(map[string]string{"encryptionKey": base64.StdEncoding.EncodeToString(orgKeyBytes)})
+	require.NoError(t, err)
+	encryptedPayload := accessKey.encrypt(t, payload)
+
+	claims, err := json.Marshal(map[string]string{"organization": bitwardenSMTestOrg})
+	require.NoError(t, err)
+	apiToken := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /identity/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+		if r.FormValue("client_id") != "ec2c1d46-6a4b-4751-a310-af9601317f2d" || r.FormValue("client_secret") != "C2IgxjjLF7qSshsbwe8JGcbM075YXw" || r.FormValue("scope") != "api.secrets" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"access_token": apiToken, "expires_in": 3600, "token_type": "Bearer", "encrypted_payload": encryptedPayload})
+	})
+	authorized := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			s.record(r)
+			if r.Header.Get("Authorization") != "Bearer "+apiToken {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("GET /api/organizations/{org}/secrets", authorized(func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("org") != bitwardenSMTestOrg {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"secrets": s.list("")})
+	}))
+	mux.HandleFunc("GET /api/projects/{project}/secrets", authorized(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"secrets": s.list(r.PathValue("project"))})
+	}))
+	mux.HandleFunc("GET /api/secrets/{id}", authorized(func(w http.ResponseWriter, r *http.Request) {
+		model, ok := s.model(r.PathValue("id"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, model)
+	}))
+	mux.HandleFunc("POST /api/secrets/get-by-ids", authorized(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		data := []map[string]interface{}{}
+		for _, id := range body.IDs {
+			if model, ok := s.model(id); ok {
+				data = append(data, model)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"data": data})
+	}))
+
+	s.srv = httptest.NewServer(mux)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *bitwardenSMServer) record(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+}
+
+func (s *bitwardenSMServer) count(prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *bitwardenSMServer) add(name, value, project string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+	s.secrets[id] = bitwardenSMTestSecret{name: name, value: value, project: project}
+	return id
+}
+
+func (s *bitwardenSMServer) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, id)
+}
+
+func (s *bitwardenSMServer) list(project string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []map[string]interface{}{}
+	for id, secret := range s.secrets {
+		if project != "" && secret.project != project {
+			continue
+		}
+		out = append(out, map[string]interface{}{
+			"id":             id,
+			"organizationId": bitwardenSMTestOrg,
+			"projectId":      secret.project,
+			"key":            s.orgKey.encrypt(s.t, []byte(secret.name)),
+			"revisionDate":   "2026-01-02T03:04:05Z",
+		})
+	}
+	return out
+}
+
+func (s *bitwardenSMServer) model(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, ok := s.secrets[id]
+	if !ok {
+		return nil, false
+	}
+	return map[string]interface{}{
+		"id":             id,
+		"organizationId": bitwardenSMTestOrg,
+		"key":            s.orgKey.encrypt(s.t, []byte(secret.name)),
+		"value":          s.orgKey.encrypt(s.t, []byte(secret.value)),
+		"note":           s.orgKey.encrypt(s.t, []byte("")),
+		"revisionDate":   "2026-01-02T03:04:05Z",
+		"projects":       []map[string]string{{"id": secret.project}},
+	}, true
+}
+
+func (s *bitwardenSMServer) provider(t *testing.T, extra map[string]interface{}) *BitwardenSMProvider {
+	t.Helper()
+	config := map[string]interface{}{
+		"server_url":   s.srv.URL,
+		"access_token": s.accessToken,
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	p, err := NewBitwardenSMProvider("bws", config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestBitwardenSMProvider_Contract(t *testing.T) {
+	server := newBitwardenSMServer(t)
+
+	provider.RunContractTests(t, provider.ContractTest{
+		CreateProvider: func(t *testing.T) provider.Provider {
+			return server.provider(t, nil)
+		},
+		SetupTestSecret: func(t *testing.T, p provider.Provider) (string, func()) {
+			id := server.add("CONTRACT_SECRET", "contract-value", "")
+			return "CONTRACT_SECRET", func() { server.remove(id) }
+		},
+	})
+}
+
+func TestBitwardenSMProvider_BatchFetch(t *testing.T) {
+	server := newBitwardenSMServer(t)
+	server.add("DATABASE_URL", "postgres://db", "")
+	server.add("API_KEY", "sk-123", "")
+	apiID := server.add("STRIPE_KEY", "sk_live", "")
+
+	p := server.provider(t, nil)
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "DATABASE_URL"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://db", value.Value)
+
+	value, err = p.Resolve(ctx, provider.Reference{Key: "API_KEY"})
+	require.NoError(t, err)
+	assert.Equal(t, "sk-123", value.Value)
+
+	value, err = p.Resolve(ctx, provider.Reference{Key: apiID})
+	require.NoError(t, err)
+	assert.Equal(t, "sk_live", value.Value, "secrets resolve by ID too")
+	assert.Equal(t, apiID, value.Metadata["secret_id"])
+
+	// One login, one listing and one batch for all three secrets
+	assert.Equal(t, 1, server.count("POST /identity/connect/token"))
+	assert.Equal(t, 1, server.count("GET /api/organizations/"))
+	assert.Equal(t, 1, server.count("POST /api/secrets/get-by-ids"))
+	assert.Equal(t, 0, server.count("GET /api/secrets/"))
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "MISSING"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "MISSING"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestBitwardenSMProvider_Project(t *testing.T) {
+	server := newBitwardenSMServer(t)
+	server.add("DATABASE_URL", "postgres://staging", "staging")
+	server.add("DATABASE_URL", "postgres://prod", "prod")
+	prodOnly := server.add("PROD_ONLY", "x", "prod")
+
+	// Without a project the name is ambiguous
+	_, err := server.provider(t, nil).Resolve(context.Background(), provider.Reference{Key: "DATABASE_URL"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "2 Bitwarden secrets are named 'DATABASE_URL'")
+
+	p := server.provider(t, map[string]interface{}{"project_id": "staging"})
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "DATABASE_URL"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://staging", value.Value)
+	assert.Equal(t, 1, server.count("GET /api/projects/staging/secrets"))
+
+	// IDs outside the project are fetched directly
+	value, err = p.Resolve(context.Background(), provider.Reference{Key: prodOnly})
+	require.NoError(t, err)
+	assert.Equal(t, "x", value.Value)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 1)
+	assert.Equal(t, "DATABASE_URL", result.Secrets[0].Key)
+}
+
+func TestBitwardenSMProvider_AccessToken(t *testing.T) {
+	server := newBitwardenSMServer(t)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing", ""},
+		{"no key", "0.id.secret"},
+		{"bad version", "1.id.secret:" + base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		{"short key", "0.id.secret:" + base64.StdEncoding.EncodeToString(make([]byte, 8))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BWS_ACCESS_TOKEN", "")
+			p := server.provider(t, map[string]interface{}{"access_token": tt.token})
+			err := p.Validate(context.Background())
+			var configErr dserrors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, "access_token", configErr.Field)
+		})
+	}
+
+	t.Run("from environment", func(t *testing.T) {
+		t.Setenv("BWS_ACCESS_TOKEN", server.accessToken)
+		p := server.provider(t, map[string]interface{}{"access_token": ""})
+		require.NoError(t, p.Validate(context.Background()))
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		token := strings.Replace(server.accessToken, "C2Igx", "XXXXX", 1)
+		p := server.provider(t, map[string]interface{}{"access_token": token})
+		err := p.Validate(context.Background())
+		assert.ErrorContains(t, err, "bitwarden secrets manager auth error")
+	})
+}
+
+func TestBitwardenSMKey_Decrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := deriveBitwardenSMAccessKey(make([]byte, 16))
+	require.NoError(t, err)
+
+	enc := key.encrypt(t, []byte("hello"))
+	plain, err := key.decryptString(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", plain)
+
+	// A tampered MAC is rejected
+	tampered := enc[:len(enc)-4] + "AAA="
+	_, err = key.decryptString(tampered)
+	assert.ErrorContains(t, err, "MAC verification failed")
+
+	_, err = key.decryptString("0.abc")
+	assert.ErrorContains(t, err, "unsupported encryption type")
+}
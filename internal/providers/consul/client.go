@@ -0,0 +1,236 @@
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAddress is the Consul agent used when none is configured
+const DefaultAddress = "http://127.0.0.1:8500"
+
+// DefaultTimeout bounds each non-blocking API request
+const DefaultTimeout = 30 * time.Second
+
+// watchWait is how long the server holds one blocking query open
+const watchWait = 5 * time.Minute
+
+// ClientOptions configures the connection to a Consul agent
+type ClientOptions struct {
+	Address       string
+	Token         string
+	Datacenter    string
+	Namespace     string
+	CACert        string
+	ClientCert    string
+	ClientKey     string
+	TLSServerName string
+	TLSSkip       bool
+}
+
+// Client is a minimal Consul HTTP API client for the KV store. It speaks
+// plain HTTP(S) so dsops does not depend on the Consul SDK.
+type Client struct {
+	address string
+	opts    ClientOptions
+	http    *http.Client
+
+	// watchHTTP serves blocking queries, which outlive DefaultTimeout
+	watchHTTP *http.Client
+}
+
+// KVPair is one Consul KV entry. Value is decoded from base64.
+type KVPair struct {
+	Key         string `json:"Key"`
+	CreateIndex uint64 `json:"CreateIndex"`
+	ModifyIndex uint64 `json:"ModifyIndex"`
+	LockIndex   uint64 `json:"LockIndex"`
+	Flags       uint64 `json:"Flags"`
+	Session     string `json:"Session"`
+	Value       []byte `json:"Value"`
+}
+
+// StatusError is an error response from the Consul agent
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("consul API returned %d: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("consul API returned %d", e.Code)
+}
+
+// IsNotFound reports whether err is a 404 from the Consul agent
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// NewClient creates a client for the agent in opts
+func NewClient(opts ClientOptions) (*Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         opts.TLSServerName,
+		InsecureSkipVerify: opts.TLSSkip, // #nosec G402 -- only when tls_skip is set
+	}
+	if opts.CACert != "" {
+		caCert, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", opts.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	address := opts.Address
+	if address == "" {
+		address = DefaultAddress
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig}
+
+	return &Client{
+		address:   strings.TrimSuffix(address, "/"),
+		opts:      opts,
+		http:      &http.Client{Timeout: DefaultTimeout, Transport: transport},
+		watchHTTP: &http.Client{Timeout: watchWait + DefaultTimeout, Transport: transport},
+	}, nil
+}
+
+// Address is the agent URL
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Get reads one key. A missing key returns a StatusError with code 404.
+func (c *Client) Get(ctx context.Context, key string) (*KVPair, error) {
+	pair, _, err := c.get(ctx, c.http, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, &StatusError{Code: http.StatusNotFound, Message: "key not found"}
+	}
+	return pair, nil
+}
+
+// Wait performs a blocking query on one key: it returns once the agent's
+// index for the key passes index, or after the server's wait time. It
+// returns the key, nil when missing, and the index to pass next time.
+func (c *Client) Wait(ctx context.Context, key string, index uint64) (*KVPair, uint64, error) {
+	query := url.Values{}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", watchWait.String())
+	}
+	pair, next, err := c.get(ctx, c.watchHTTP, key, query)
+	if IsNotFound(err) {
+		return nil, next, nil
+	}
+	return pair, next, err
+}
+
+// Keys lists the keys under prefix, folded at "/" into folders ending in
+// "/". A prefix with no keys returns a StatusError with code 404.
+func (c *Client) Keys(ctx context.Context, prefix string) ([]string, error) {
+	// Consul treats "?keys=" like "?keys"
+	body, _, err := c.do(ctx, c.http, kvPath(prefix), url.Values{"keys": {""}, "separator": {"/"}})
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode key list: %w", err)
+	}
+	return keys, nil
+}
+
+func (c *Client) get(ctx context.Context, client *http.Client, key string, query url.Values) (*KVPair, uint64, error) {
+	body, index, err := c.do(ctx, client, kvPath(key), query)
+	if err != nil {
+		return nil, index, err
+	}
+	var pairs []KVPair
+	if err := json.Unmarshal(body, &pairs); err != nil {
+		return nil, index, fmt.Errorf("failed to decode key %s: %w", key, err)
+	}
+	for i := range pairs {
+		if pairs[i].Key == key {
+			return &pairs[i], index, nil
+		}
+	}
+	return nil, index, nil
+}
+
+// do sends a GET and returns the body and the X-Consul-Index header
+func (c *Client) do(ctx context.Context, client *http.Client, path string, query url.Values) ([]byte, uint64, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.opts.Datacenter != "" {
+		query.Set("dc", c.opts.Datacenter)
+	}
+	if c.opts.Namespace != "" {
+		query.Set("ns", c.opts.Namespace)
+	}
+	endpoint := c.address + path
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "dsops")
+	if c.opts.Token != "" {
+		req.Header.Set("X-Consul-Token", c.opts.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to reach %s: %w", c.address, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, index, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, index, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+	}
+	return respBody, index, nil
+}
+
+func kvPath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return "/v1/kv/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,359 @@
+// Package consul implements a secret store for the Consul KV store and the
+// small HTTP client dsops uses to read it.
+//
+// Keys are addressed as "path/to/key", relative to the store's prefix. A
+// key holding a JSON document can be narrowed to one field with
+// "path/to/key#.field.path". The ModifyIndex of a key is its version, and
+// Watch waits for it to change with Consul blocking queries.
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/jsonfield"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Config holds the Consul store configuration. Unset connection settings
+// fall back to the environment variables the consul CLI uses.
+type Config struct {
+	Address       string `yaml:"address"`         // Agent URL (default: $CONSUL_HTTP_ADDR, then http://127.0.0.1:8500)
+	Token         string `yaml:"token"`           // ACL token (default: $CONSUL_HTTP_TOKEN)
+	TokenFile     string `yaml:"token_file"`      // File holding the ACL token (default: $CONSUL_HTTP_TOKEN_FILE)
+	Datacenter    string `yaml:"datacenter"`      // Datacenter to query (default: the agent's)
+	Namespace     string `yaml:"namespace"`       // Consul Enterprise namespace (default: $CONSUL_NAMESPACE)
+	Prefix        string `yaml:"prefix"`          // Path prefix for every key, e.g. "config/myapp/"
+	CACert        string `yaml:"ca_cert"`         // Path to CA certificate (default: $CONSUL_CACERT)
+	ClientCert    string `yaml:"client_cert"`     // Path to client certificate for mTLS (default: $CONSUL_CLIENT_CERT)
+	ClientKey     string `yaml:"client_key"`      // Path to client key for mTLS (default: $CONSUL_CLIENT_KEY)
+	TLSServerName string `yaml:"tls_server_name"` // Server name to verify (default: $CONSUL_TLS_SERVER_NAME)
+	TLSSkip       bool   `yaml:"tls_skip"`        // Skip TLS verification (not recommended)
+}
+
+// Provider reads secrets from the Consul KV store
+type Provider struct {
+	name   string
+	config Config
+
+	mu     sync.Mutex
+	client *Client
+}
+
+// NewProvider creates a Consul store. The agent is contacted on first use.
+func NewProvider(name string, configMap map[string]interface{}) (*Provider, error) {
+	var config Config
+	for key, target := range map[string]*string{
+		"address":         &config.Address,
+		"token":           &config.Token,
+		"token_file":      &config.TokenFile,
+		"datacenter":      &config.Datacenter,
+		"namespace":       &config.Namespace,
+		"prefix":          &config.Prefix,
+		"ca_cert":         &config.CACert,
+		"client_cert":     &config.ClientCert,
+		"client_key":      &config.ClientKey,
+		"tls_server_name": &config.TLSServerName,
+	} {
+		if value, ok := configMap[key].(string); ok {
+			*target = value
+		}
+	}
+	if tlsSkip, ok := configMap["tls_skip"].(bool); ok {
+		config.TLSSkip = tlsSkip
+	}
+
+	for target, env := range map[*string]string{
+		&config.Address:       "CONSUL_HTTP_ADDR",
+		&config.TokenFile:     "CONSUL_HTTP_TOKEN_FILE",
+		&config.Namespace:     "CONSUL_NAMESPACE",
+		&config.CACert:        "CONSUL_CACERT",
+		&config.ClientCert:    "CONSUL_CLIENT_CERT",
+		&config.ClientKey:     "CONSUL_CLIENT_KEY",
+		&config.TLSServerName: "CONSUL_TLS_SERVER_NAME",
+	} {
+		if *target == "" {
+			*target = os.Getenv(env)
+		}
+	}
+	if config.Token == "" && config.TokenFile == "" {
+		config.Token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+
+	if (config.ClientCert == "") != (config.ClientKey == "") {
+		return nil, dserrors.ConfigError{
+			Field:      "client_cert",
+			Message:    "client_cert and client_key must be set together",
+			Suggestion: "Set both to the PEM files of the client certificate and its key",
+		}
+	}
+	if config.Address != "" && !strings.Contains(config.Address, "://") {
+		// CONSUL_HTTP_ADDR is often host:port
+		scheme := "http://"
+		if config.CACert != "" || config.ClientCert != "" || strings.EqualFold(os.Getenv("CONSUL_HTTP_SSL"), "true") {
+			scheme = "https://"
+		}
+		config.Address = scheme + config.Address
+	}
+
+	return &Provider{name: name, config: config}, nil
+}
+
+// NewProviderWithClient creates a Consul store with a given client (for testing)
+func NewProviderWithClient(name string, configMap map[string]interface{}, client *Client) (*Provider, error) {
+	p, err := NewProvider(name, configMap)
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the provider's capabilities
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false, // ModifyIndex is reported but old values cannot be read
+		SupportsMetadata:   true,
+		SupportsWatching:   true,
+		SupportsBinary:     true,
+		RequiresAuth:       false, // ACLs are optional in Consul
+		AuthMethods:        []string{"token", "certificate"},
+	}
+}
+
+// Validate checks that the agent is reachable and the token can list the
+// store's prefix
+func (p *Provider) Validate(ctx context.Context) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.Keys(ctx, p.config.Prefix); err != nil && !IsNotFound(err) {
+		return p.apiError(client, err, fmt.Sprintf("Cannot list Consul keys under '%s'", p.config.Prefix))
+	}
+	return nil
+}
+
+// Resolve reads a key, or one field of the JSON document it holds
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	pair, err := client.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return provider.SecretValue{}, p.apiError(client, err, fmt.Sprintf("Failed to read Consul key %s", key))
+	}
+
+	value := string(pair.Value)
+	if field != "" {
+		if value, err = jsonfield.Extract(value, field); err != nil {
+			return provider.SecretValue{}, dserrors.UserError{
+				Message:    fmt.Sprintf("Cannot select '%s' from Consul key %s", field, key),
+				Details:    err.Error(),
+				Suggestion: "Check that the key holds a JSON object with that field",
+			}
+		}
+	}
+
+	return provider.SecretValue{
+		Value:   value,
+		Version: strconv.FormatUint(pair.ModifyIndex, 10),
+		Metadata: map[string]string{
+			"provider":     p.name,
+			"key":          key,
+			"create_index": strconv.FormatUint(pair.CreateIndex, 10),
+			"flags":        strconv.FormatUint(pair.Flags, 10),
+		},
+	}, nil
+}
+
+// Describe returns key metadata without returning its value. The version is
+// the key's ModifyIndex.
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	pair, err := client.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, p.apiError(client, err, fmt.Sprintf("Failed to read Consul key %s", key))
+	}
+	return describePair(pair, field), nil
+}
+
+// Watch waits with blocking queries until the key's ModifyIndex differs
+// from version. Any write to the key counts as a change, including writes
+// that leave the selected field as it was.
+func (p *Provider) Watch(ctx context.Context, ref provider.Reference, version string) (provider.Metadata, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	var index uint64
+	for {
+		pair, next, err := client.Wait(ctx, key, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return provider.Metadata{}, ctx.Err()
+			}
+			return provider.Metadata{}, p.apiError(client, err, fmt.Sprintf("Failed to watch Consul key %s", key))
+		}
+
+		current := ""
+		if pair != nil {
+			current = strconv.FormatUint(pair.ModifyIndex, 10)
+		}
+		if current != version {
+			if pair == nil {
+				return provider.Metadata{Exists: false}, nil
+			}
+			return describePair(pair, field), nil
+		}
+
+		// Consul asks clients to restart from zero if the index goes
+		// backwards, and never to block on zero
+		switch {
+		case next < index:
+			index = 0
+		case next == 0:
+			index = 1
+		default:
+			index = next
+		}
+	}
+}
+
+func (p *Provider) getClient() (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	token := p.config.Token
+	if token == "" && p.config.TokenFile != "" {
+		data, err := os.ReadFile(p.config.TokenFile)
+		if err != nil {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to read Consul token file %s", p.config.TokenFile),
+				Details:    err.Error(),
+				Suggestion: "Check token_file on the store or CONSUL_HTTP_TOKEN_FILE",
+			}
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	client, err := NewClient(ClientOptions{
+		Address:       p.config.Address,
+		Token:         token,
+		Datacenter:    p.config.Datacenter,
+		Namespace:     p.config.Namespace,
+		CACert:        p.config.CACert,
+		ClientCert:    p.config.ClientCert,
+		ClientKey:     p.config.ClientKey,
+		TLSServerName: p.config.TLSServerName,
+		TLSSkip:       p.config.TLSSkip,
+	})
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    "Failed to configure the Consul client",
+			Details:    err.Error(),
+			Suggestion: "Check ca_cert, client_cert and client_key on the store",
+		}
+	}
+	p.client = client
+	return client, nil
+}
+
+// parseKey splits "path/to/key#field" and applies the store prefix
+func (p *Provider) parseKey(ref provider.Reference) (key, field string, err error) {
+	path, field, _ := strings.Cut(ref.Key, "#")
+	if field == "" {
+		field = ref.Field
+	}
+	path = strings.TrimPrefix(path, "/")
+	if path == "" || strings.HasSuffix(path, "/") {
+		return "", "", dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid Consul key reference: %s", ref.Key),
+			Suggestion: "Use 'path/to/key' or 'path/to/key#.field', e.g. 'myapp/database#.password'",
+		}
+	}
+	return p.config.Prefix + path, field, nil
+}
+
+func (p *Provider) apiError(client *Client, err error, message string) error {
+	userErr := dserrors.UserError{Message: message, Details: err.Error()}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Code {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			userErr.Suggestion = "The ACL token lacks key_prefix read on this path; check token or token_file on the store"
+		}
+	}
+	if userErr.Suggestion == "" {
+		userErr.Suggestion = fmt.Sprintf("Check that the Consul agent at %s is reachable with 'consul members'", client.Address())
+	}
+	return userErr
+}
+
+func describePair(pair *KVPair, field string) provider.Metadata {
+	size := len(pair.Value)
+	if field != "" {
+		value, err := jsonfield.Extract(string(pair.Value), field)
+		if err != nil {
+			return provider.Metadata{Exists: false}
+		}
+		size = len(value)
+	}
+
+	tags := map[string]string{
+		"create_index": strconv.FormatUint(pair.CreateIndex, 10),
+		"flags":        strconv.FormatUint(pair.Flags, 10),
+	}
+	if pair.Session != "" {
+		tags["session"] = pair.Session
+	}
+	return provider.Metadata{
+		Exists:      true,
+		Version:     strconv.FormatUint(pair.ModifyIndex, 10),
+		Size:        size,
+		Permissions: []string{"read"},
+		Tags:        tags,
+	}
+}
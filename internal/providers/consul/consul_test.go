@@ -0,0 +1,370 @@
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/testutil"
+)
+
+// fakeAgent is an httptest stand-in for the Consul KV API, including
+// blocking queries
+type fakeAgent struct {
+	t     *testing.T
+	token string
+
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced on every write
+	index   uint64
+	pairs   map[string]*KVPair
+	queries []string
+}
+
+func newFakeAgent(t *testing.T) *fakeAgent {
+	return &fakeAgent{t: t, changed: make(chan struct{}), index: 10, pairs: map[string]*KVPair{}}
+}
+
+func (f *fakeAgent) start() *httptest.Server {
+	server := httptest.NewServer(f)
+	f.t.Cleanup(server.Close)
+	return server
+}
+
+func (f *fakeAgent) put(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.index++
+	pair, ok := f.pairs[key]
+	if !ok {
+		pair = &KVPair{Key: key, CreateIndex: f.index}
+		f.pairs[key] = pair
+	}
+	pair.ModifyIndex, pair.Value = f.index, []byte(value)
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeAgent) delete(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.index++
+	delete(f.pairs, key)
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.token != "" && r.Header.Get("X-Consul-Token") != f.token {
+		http.Error(w, "Permission denied: token lacks key:read", http.StatusForbidden)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	query := r.URL.Query()
+
+	f.mu.Lock()
+	f.queries = append(f.queries, r.URL.RawQuery)
+	if query.Has("keys") {
+		var keys []string
+		for k := range f.pairs {
+			if strings.HasPrefix(k, key) {
+				keys = append(keys, k)
+			}
+		}
+		f.mu.Unlock()
+		if len(keys) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sort.Strings(keys)
+		_ = json.NewEncoder(w).Encode(keys)
+		return
+	}
+
+	// Blocking query: wait until the key's index passes the one given
+	wait, _ := strconv.ParseUint(query.Get("index"), 10, 64)
+	for wait > 0 && f.keyIndex(key) <= wait {
+		changed := f.changed
+		f.mu.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+		f.mu.Lock()
+	}
+	defer f.mu.Unlock()
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.keyIndex(key), 10))
+	pair, ok := f.pairs[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode([]*KVPair{pair})
+}
+
+// keyIndex is the index Consul reports for a key: its ModifyIndex, or the
+// KV index when it is missing. The caller holds f.mu.
+func (f *fakeAgent) keyIndex(key string) uint64 {
+	if pair, ok := f.pairs[key]; ok {
+		return pair.ModifyIndex
+	}
+	return f.index
+}
+
+func newTestProvider(t *testing.T, address string, config map[string]interface{}) *Provider {
+	t.Helper()
+
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	config["address"] = address
+	p, err := NewProvider("consul", config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestProvider_Resolve(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.token = "s3cret-token"
+	agent.put("config/myapp/api-key", "abc123")
+	agent.put("config/myapp/database", `{"username":"app","password":"hunter2","hosts":["db1","db2"]}`)
+	server := agent.start()
+
+	p := newTestProvider(t, server.URL, map[string]interface{}{
+		"token":      "s3cret-token",
+		"prefix":     "config/myapp/",
+		"datacenter": "dc2",
+	})
+	ctx := context.Background()
+
+	secret, err := p.Resolve(ctx, provider.Reference{Key: "api-key"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", secret.Value)
+	assert.Equal(t, "11", secret.Version)
+	assert.Equal(t, "config/myapp/api-key", secret.Metadata["key"])
+
+	secret, err = p.Resolve(ctx, provider.Reference{Key: "database#.password"})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", secret.Value)
+
+	secret, err = p.Resolve(ctx, provider.Reference{Key: "database", Field: "hosts.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "db2", secret.Value)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "database#.port"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Cannot select '.port'")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "folder/"})
+	assert.Contains(t, err.Error(), "Invalid Consul key reference")
+
+	assert.Contains(t, agent.queries[0], "dc=dc2")
+}
+
+func TestProvider_ACLDenied(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.token = "right"
+	agent.put("app/key", "value")
+	server := agent.start()
+
+	p := newTestProvider(t, server.URL, map[string]interface{}{"token": "wrong"})
+
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "app/key"})
+	require.Error(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Suggestion, "ACL token")
+
+	assert.Error(t, p.Validate(context.Background()))
+}
+
+func TestProvider_TokenFileAndValidate(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.token = "from-file"
+	server := agent.start()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("from-file\n"), 0600))
+
+	p := newTestProvider(t, server.URL, map[string]interface{}{"token_file": tokenFile, "prefix": "empty/"})
+	assert.NoError(t, p.Validate(context.Background()), "an empty prefix is valid")
+}
+
+func TestProvider_Describe(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.put("app/db", `{"password":"hunter2"}`)
+	server := agent.start()
+	p := newTestProvider(t, server.URL, nil)
+	ctx := context.Background()
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "app/db"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "11", meta.Version)
+	assert.Equal(t, len(`{"password":"hunter2"}`), meta.Size)
+	assert.Equal(t, "11", meta.Tags["create_index"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "app/db#.password"})
+	require.NoError(t, err)
+	assert.Equal(t, 7, meta.Size)
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "app/db#.user"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "app/missing"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestProvider_Watch(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.put("app/token", "v1")
+	agent.put("app/other", "x")
+	server := agent.start()
+
+	p := newTestProvider(t, server.URL, nil)
+	assert.True(t, p.Capabilities().SupportsWatching)
+	var _ provider.Watcher = p
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ref := provider.Reference{Key: "app/token"}
+
+	meta, err := p.Describe(ctx, ref)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		agent.put("app/other", "y") // wakes the query but does not change the key
+		time.Sleep(50 * time.Millisecond)
+		agent.put("app/token", "v2")
+	}()
+	changed, err := p.Watch(ctx, ref, meta.Version)
+	require.NoError(t, err)
+	assert.True(t, changed.Exists)
+	assert.Equal(t, "14", changed.Version)
+
+	// A stale version returns at once
+	again, err := p.Watch(ctx, ref, meta.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "14", again.Version)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		agent.delete("app/token")
+	}()
+	deleted, err := p.Watch(ctx, ref, changed.Version)
+	require.NoError(t, err)
+	assert.False(t, deleted.Exists)
+
+	// Watching a missing key waits for it to be created
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		agent.put("app/token", "v3")
+	}()
+	created, err := p.Watch(ctx, ref, "")
+	require.NoError(t, err)
+	assert.True(t, created.Exists)
+
+	short, stop := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stop()
+	_, err = p.Watch(short, ref, created.Version)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestProvider_MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	agent := newFakeAgent(t)
+	agent.put("app/key", "over-mtls")
+	server := httptest.NewUnstartedServer(agent)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	certFile, keyFile := writeClientCertificate(t, dir)
+
+	p := newTestProvider(t, server.URL, map[string]interface{}{"ca_cert": caFile, "client_cert": certFile, "client_key": keyFile})
+	secret, err := p.Resolve(context.Background(), provider.Reference{Key: "app/key"})
+	require.NoError(t, err)
+	assert.Equal(t, "over-mtls", secret.Value)
+
+	// Without a client certificate the handshake fails
+	p = newTestProvider(t, server.URL, map[string]interface{}{"ca_cert": caFile})
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "app/key"})
+	assert.Error(t, err)
+}
+
+func TestNewProvider_Config(t *testing.T) {
+	t.Setenv("CONSUL_HTTP_ADDR", "consul.internal:8501")
+	t.Setenv("CONSUL_HTTP_SSL", "true")
+	t.Setenv("CONSUL_HTTP_TOKEN", "env-token")
+
+	p, err := NewProvider("consul", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://consul.internal:8501", p.config.Address)
+	assert.Equal(t, "env-token", p.config.Token)
+
+	p, err = NewProvider("consul", map[string]interface{}{"address": "http://10.0.0.1:8500", "token": "mine"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://10.0.0.1:8500", p.config.Address)
+	assert.Equal(t, "mine", p.config.Token)
+
+	_, err = NewProvider("consul", map[string]interface{}{"client_cert": "cert.pem"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client_cert and client_key must be set together")
+}
+
+// writeClientCertificate writes a self-signed client certificate and key
+func writeClientCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	cert := testutil.NewCertificate(t, testutil.CertificateOptions{
+		CommonName:  "dsops-client",
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certFile, cert.CertPEM(), 0600))
+	require.NoError(t, os.WriteFile(keyFile, cert.KeyPEM(t), 0600))
+	return certFile, keyFile
+}
@@ -0,0 +1,48 @@
+package contracts
+
+import (
+	"context"
+	"time"
+)
+
+// BitwardenSMClient abstracts Bitwarden Secrets Manager API operations for testing.
+// Secret names, values and notes come back encrypted; the provider decrypts
+// them with the organization key released by Login.
+type BitwardenSMClient interface {
+	// Login exchanges a machine account access token for an API token
+	Login(ctx context.Context, clientID, clientSecret string) (*BitwardenSMSession, error)
+
+	// ListSecrets lists the secrets of an organization, or of a project when
+	// projectID is set
+	ListSecrets(ctx context.Context, token, organizationID, projectID string) ([]BitwardenSMSecret, error)
+
+	// GetSecret retrieves a single secret by ID
+	GetSecret(ctx context.Context, token, id string) (*BitwardenSMSecret, error)
+
+	// GetSecretsByIDs retrieves several secrets in one request
+	GetSecretsByIDs(ctx context.Context, token string, ids []string) ([]BitwardenSMSecret, error)
+}
+
+// BitwardenSMSession is the result of a machine account login
+type BitwardenSMSession struct {
+	AccessToken string
+	ExpiresIn   time.Duration
+
+	// EncryptedPayload holds the organization key, encrypted with a key
+	// derived from the access token
+	EncryptedPayload string
+}
+
+// BitwardenSMSecret represents a secret from Bitwarden Secrets Manager.
+// Key, Value and Note are encrypted strings; Value and Note are empty in
+// list results.
+type BitwardenSMSecret struct {
+	ID             string
+	OrganizationID string
+	ProjectID      string
+	Key            string
+	Value          string
+	Note           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
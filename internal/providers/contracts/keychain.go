@@ -2,11 +2,19 @@
 // These interfaces enable dependency injection for testing.
 package contracts
 
+import "time"
+
 // KeychainClient abstracts OS keychain operations for testing
 type KeychainClient interface {
 	// Query retrieves a secret from the keychain
 	Query(service, account string) ([]byte, error)
 
+	// Set stores a secret in the keychain, replacing any existing value
+	Set(service, account string, value []byte) error
+
+	// Delete removes a secret from the keychain
+	Delete(service, account string) error
+
 	// Validate checks if the keychain is accessible
 	Validate() error
 
@@ -17,6 +25,20 @@ type KeychainClient interface {
 	IsHeadless() bool
 }
 
+// KeychainLister is implemented by keychain backends that can enumerate
+// their items. The OS keychains cannot; the encrypted file keyring can.
+type KeychainLister interface {
+	// List returns every item in the keychain without its value
+	List() ([]KeychainItem, error)
+}
+
+// KeychainItem describes a keychain entry without its value
+type KeychainItem struct {
+	Service   string
+	Account   string
+	UpdatedAt time.Time
+}
+
 // KeychainReference represents a parsed keychain secret reference
 type KeychainReference struct {
 	Service string
@@ -0,0 +1,67 @@
+package contracts
+
+import (
+	"context"
+	"time"
+)
+
+// OnePasswordClient abstracts the 1Password Connect and service account APIs
+// for testing. Both address vaults and items by ID; the provider maps names
+// to IDs from the list calls.
+type OnePasswordClient interface {
+	// ListVaults lists the vaults the token can read
+	ListVaults(ctx context.Context) ([]OnePasswordVault, error)
+
+	// ListItems lists the items of a vault without their fields
+	ListItems(ctx context.Context, vaultID string) ([]OnePasswordItemSummary, error)
+
+	// GetItem retrieves an item with its fields
+	GetItem(ctx context.Context, vaultID, itemID string) (*OnePasswordItem, error)
+}
+
+// OnePasswordVault identifies a 1Password vault
+type OnePasswordVault struct {
+	ID   string
+	Name string
+}
+
+// OnePasswordItemSummary identifies an item in a vault listing
+type OnePasswordItemSummary struct {
+	ID        string
+	Title     string
+	Category  string
+	Tags      []string
+	UpdatedAt time.Time
+}
+
+// OnePasswordItem represents a 1Password item with its fields
+type OnePasswordItem struct {
+	ID        string
+	Title     string
+	Category  string
+	VaultID   string
+	Version   int
+	Tags      []string
+	Notes     string
+	URLs      []string
+	Sections  []OnePasswordSection
+	Fields    []OnePasswordItemField
+	UpdatedAt time.Time
+}
+
+// OnePasswordSection groups fields of an item
+type OnePasswordSection struct {
+	ID    string
+	Label string
+}
+
+// OnePasswordItemField is a single field of an item. SectionID is empty for
+// built-in fields such as username and password.
+type OnePasswordItemField struct {
+	ID        string
+	Label     string
+	Type      string
+	Purpose   string
+	SectionID string
+	Value     string
+}
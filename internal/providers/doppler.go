@@ -119,7 +119,7 @@ func (p *DopplerProvider) Resolve(ctx context.Context, ref provider.Reference) (
 				Message:    fmt.Sprintf("Secret '%s' not found in Doppler", secretName),
 				Suggestion: fmt.Sprintf("Verify the secret name exists in project '%s' config '%s'", p.config.Project, p.config.Config),
 				Details:    "You can list available secrets with: doppler secrets",
-				Err:        err,
+				Err:        &provider.NotFoundError{Provider: p.Name(), Key: ref.Key},
 			}
 		}
 
@@ -181,7 +181,7 @@ func (p *DopplerProvider) Describe(ctx context.Context, ref provider.Reference)
 			Message:    fmt.Sprintf("Secret '%s' not found in Doppler", secretName),
 			Suggestion: fmt.Sprintf("Verify the secret name exists in project '%s' config '%s'", p.config.Project, p.config.Config),
 			Details:    "You can list available secrets with: doppler secrets",
-			Err:        fmt.Errorf("secret not found"),
+			Err:        &provider.NotFoundError{Provider: p.Name(), Key: ref.Key},
 		}
 	}
 
@@ -194,6 +194,58 @@ func (p *DopplerProvider) Describe(ctx context.Context, ref provider.Reference)
 	}, nil
 }
 
+// ListSecrets lists secret names in the configured project and config.
+// Only names are requested from the Doppler CLI.
+func (p *DopplerProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	stdout, _, err := p.executeDoppler(ctx, "secrets", "--only-names", "--json")
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Failed to list secrets from Doppler",
+			Suggestion: "Check your network connection and Doppler service status",
+			Err:        err,
+		}
+	}
+
+	names, err := parseDopplerNames(stdout)
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Invalid response format from Doppler",
+			Suggestion: "This might be a temporary issue with the Doppler service",
+			Details:    "Failed to parse secrets list response",
+			Err:        err,
+		}
+	}
+
+	secrets := make([]provider.SecretInfo, len(names))
+	for i, name := range names {
+		secrets[i] = provider.SecretInfo{
+			Key:  name,
+			Tags: map[string]string{"project": p.config.Project, "config": p.config.Config},
+		}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// parseDopplerNames reads secret names from either a JSON object keyed by
+// name or a JSON array of names
+func parseDopplerNames(data []byte) ([]string, error) {
+	var byName map[string]json.RawMessage
+	if err := json.Unmarshal(data, &byName); err == nil {
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		return names, nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
 // executeDoppler runs a doppler command with proper environment setup.
 // This method handles authentication via environment variables.
 func (p *DopplerProvider) executeDoppler(ctx context.Context, args ...string) (stdout []byte, stderr []byte, err error) {
@@ -70,6 +70,7 @@ func TestDopplerProviderWithMockExecutor_Resolve(t *testing.T) {
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errContains)
+				assert.True(t, provider.IsNotFound(err), "missing Doppler secrets must be a NotFoundError")
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.wantValue, secret.Value)
@@ -123,6 +124,7 @@ func TestDopplerProviderWithMockExecutor_Describe(t *testing.T) {
 
 			if tt.wantErr {
 				require.Error(t, err)
+				assert.True(t, provider.IsNotFound(err), "missing Doppler secrets must be a NotFoundError")
 			} else {
 				require.NoError(t, err)
 				assert.True(t, meta.Exists)
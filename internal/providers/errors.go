@@ -110,6 +110,61 @@ var (
 	ErrAkeylessRateLimited    = fmt.Errorf("akeyless rate limited")
 )
 
+// BitwardenSMError wraps Bitwarden Secrets Manager API errors with context
+type BitwardenSMError struct {
+	Op         string // Operation: "auth", "decrypt", "fetch", "list"
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *BitwardenSMError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("bitwarden secrets manager %s error (status %d): %s", e.Op, e.StatusCode, e.Message)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("bitwarden secrets manager %s error: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("bitwarden secrets manager %s error: %s", e.Op, e.Message)
+}
+
+func (e *BitwardenSMError) Unwrap() error {
+	return e.Err
+}
+
+// Bitwarden Secrets Manager sentinel errors
+var (
+	ErrBitwardenSMSecretNotFound = fmt.Errorf("bitwarden secret not found")
+	ErrBitwardenSMInvalidToken   = fmt.Errorf("invalid bitwarden access token")
+)
+
+// OnePasswordAPIError wraps 1Password Connect and service account errors with context
+type OnePasswordAPIError struct {
+	Op         string // Operation: "list", "fetch"
+	StatusCode int
+	Message    string
+	Err        error
+}
+
+func (e *OnePasswordAPIError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("1password %s error (status %d): %s", e.Op, e.StatusCode, e.Message)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("1password %s error: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("1password %s error: %s", e.Op, e.Message)
+}
+
+func (e *OnePasswordAPIError) Unwrap() error {
+	return e.Err
+}
+
+// 1Password sentinel errors
+var (
+	ErrOnePasswordNotFound = fmt.Errorf("1password item not found")
+)
+
 // ToNotFoundError converts provider-specific errors to the standard NotFoundError
 func ToNotFoundError(providerName, key string, err error) provider.NotFoundError {
 	return provider.NotFoundError{
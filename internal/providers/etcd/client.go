@@ -0,0 +1,406 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEndpoint is the etcd member used when none is configured
+const DefaultEndpoint = "http://127.0.0.1:2379"
+
+// DefaultTimeout bounds each non-streaming API request
+const DefaultTimeout = 30 * time.Second
+
+// ClientOptions configures the connection to an etcd cluster
+type ClientOptions struct {
+	Endpoints     []string
+	Username      string
+	Password      string
+	CACert        string
+	ClientCert    string
+	ClientKey     string
+	TLSServerName string
+	TLSSkip       bool
+}
+
+// Client is a minimal etcd v3 client for the key-value API. It uses the
+// JSON gateway every etcd member serves next to gRPC, so dsops does not
+// depend on the etcd client library.
+type Client struct {
+	endpoints []string
+	opts      ClientOptions
+	http      *http.Client
+
+	// watchHTTP serves watch streams, which have no deadline
+	watchHTTP *http.Client
+
+	mu    sync.Mutex
+	token string
+	next  int // index of the endpoint to try first
+}
+
+// KeyValue is one etcd key. Key and Value are decoded from base64.
+type KeyValue struct {
+	Key            []byte `json:"key"`
+	Value          []byte `json:"value"`
+	CreateRevision int64s `json:"create_revision"`
+	ModRevision    int64s `json:"mod_revision"`
+	Version        int64s `json:"version"`
+	Lease          int64s `json:"lease"`
+}
+
+// ResponseHeader is the header of every etcd response
+type ResponseHeader struct {
+	ClusterID int64s `json:"cluster_id"`
+	MemberID  int64s `json:"member_id"`
+	Revision  int64s `json:"revision"`
+}
+
+// int64s decodes the int64 fields the JSON gateway sends as strings
+type int64s int64
+
+func (n *int64s) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = int64s(v)
+	return nil
+}
+
+// StatusError is an error response from etcd
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("etcd returned %d: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("etcd returned %d", e.Code)
+}
+
+// IsNotFound reports whether err is a missing key
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// NewClient creates a client for the cluster in opts
+func NewClient(opts ClientOptions) (*Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         opts.TLSServerName,
+		InsecureSkipVerify: opts.TLSSkip, // #nosec G402 -- only when tls_skip is set
+	}
+	if opts.CACert != "" {
+		caCert, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", opts.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	endpoints := make([]string, 0, len(opts.Endpoints))
+	for _, e := range opts.Endpoints {
+		if e = strings.TrimSuffix(strings.TrimSpace(e), "/"); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	if len(endpoints) == 0 {
+		endpoints = []string{DefaultEndpoint}
+	}
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig}
+
+	return &Client{
+		endpoints: endpoints,
+		opts:      opts,
+		http:      &http.Client{Timeout: DefaultTimeout, Transport: transport},
+		watchHTTP: &http.Client{Transport: transport},
+	}, nil
+}
+
+// Endpoints are the member URLs the client tries in turn
+func (c *Client) Endpoints() []string {
+	return c.endpoints
+}
+
+// Get reads one key. A missing key returns a StatusError with code 404.
+// The header revision is the store revision at the time of the read.
+func (c *Client) Get(ctx context.Context, key string) (*KeyValue, ResponseHeader, error) {
+	var resp struct {
+		Header ResponseHeader `json:"header"`
+		KVs    []KeyValue     `json:"kvs"`
+	}
+	if err := c.call(ctx, "/v3/kv/range", map[string]interface{}{"key": []byte(key)}, &resp); err != nil {
+		return nil, ResponseHeader{}, err
+	}
+	if len(resp.KVs) == 0 {
+		return nil, resp.Header, &StatusError{Code: http.StatusNotFound, Message: "key not found"}
+	}
+	return &resp.KVs[0], resp.Header, nil
+}
+
+// CountPrefix counts the keys starting with prefix without reading them
+func (c *Client) CountPrefix(ctx context.Context, prefix string) (int64, error) {
+	request := map[string]interface{}{
+		"key":        []byte(prefix),
+		"range_end":  prefixEnd(prefix),
+		"count_only": true,
+	}
+	var resp struct {
+		Count int64s `json:"count"`
+	}
+	if err := c.call(ctx, "/v3/kv/range", request, &resp); err != nil {
+		return 0, err
+	}
+	return int64(resp.Count), nil
+}
+
+// WatchEvent is a change to a watched key. Deleted events carry only the
+// key and the revision of the deletion.
+type WatchEvent struct {
+	Deleted bool
+	KV      KeyValue
+}
+
+// Watch streams the changes to key from startRevision until one arrives,
+// and returns it. It returns ctx.Err() when ctx is cancelled first.
+func (c *Client) Watch(ctx context.Context, key string, startRevision int64) (WatchEvent, error) {
+	request := map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key":            []byte(key),
+			"start_revision": startRevision,
+		},
+	}
+	body, err := c.open(ctx, c.watchHTTP, "/v3/watch", request)
+	if err != nil {
+		return WatchEvent{}, err
+	}
+	defer func() { _ = body.Close() }()
+
+	decoder := json.NewDecoder(body)
+	for {
+		var message struct {
+			Result struct {
+				Canceled     bool   `json:"canceled"`
+				CancelReason string `json:"cancel_reason"`
+				CompactRev   int64s `json:"compact_revision"`
+				Events       []struct {
+					Type string   `json:"type"`
+					KV   KeyValue `json:"kv"`
+				} `json:"events"`
+			} `json:"result"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := decoder.Decode(&message); err != nil {
+			if ctx.Err() != nil {
+				return WatchEvent{}, ctx.Err()
+			}
+			return WatchEvent{}, fmt.Errorf("watch stream ended: %w", err)
+		}
+		if message.Error != nil {
+			return WatchEvent{}, &StatusError{Code: http.StatusInternalServerError, Message: message.Error.Message}
+		}
+
+		result := message.Result
+		if result.Canceled {
+			if result.CompactRev > 0 {
+				return WatchEvent{}, fmt.Errorf("watch cancelled: revision %d has been compacted", startRevision)
+			}
+			return WatchEvent{}, fmt.Errorf("watch cancelled: %s", result.CancelReason)
+		}
+		if n := len(result.Events); n > 0 {
+			// Only the latest event of a batch matters; PUT is the zero
+			// value and omitted by the gateway
+			last := result.Events[n-1]
+			return WatchEvent{Deleted: last.Type == "DELETE", KV: last.KV}, nil
+		}
+	}
+}
+
+// call POSTs a JSON request and decodes the JSON response into out
+func (c *Client) call(ctx context.Context, path string, request, out interface{}) error {
+	body, err := c.open(ctx, c.http, path, request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
+
+// open POSTs a request to the first endpoint that answers, authenticating
+// first when a username is configured, and returns the response body. An
+// expired auth token is renewed once.
+func (c *Client) open(ctx context.Context, client *http.Client, path string, request interface{}) (io.ReadCloser, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.authToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err := c.post(ctx, client, path, payload, token)
+		var statusErr *StatusError
+		if attempt == 0 && token != "" && errors.As(err, &statusErr) && statusErr.Code == http.StatusUnauthorized {
+			c.mu.Lock()
+			c.token = ""
+			c.mu.Unlock()
+			continue
+		}
+		return body, err
+	}
+}
+
+// post sends payload to each endpoint in turn until one is reachable
+func (c *Client) post(ctx context.Context, client *http.Client, path string, payload []byte, token string) (io.ReadCloser, error) {
+	c.mu.Lock()
+	first := c.next
+	c.mu.Unlock()
+
+	var lastErr error
+	for i := range c.endpoints {
+		n := (first + i) % len(c.endpoints)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoints[n]+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "dsops")
+		if token != "" {
+			// etcd expects the bare token, not a bearer token
+			req.Header.Set("Authorization", token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = fmt.Errorf("failed to reach %s: %w", c.endpoints[n], err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.next = n
+		c.mu.Unlock()
+
+		if resp.StatusCode >= 300 {
+			defer func() { _ = resp.Body.Close() }()
+			return nil, statusError(resp)
+		}
+		return resp.Body, nil
+	}
+	return nil, lastErr
+}
+
+// authToken returns the auth token, authenticating when a username is set
+func (c *Client) authToken(ctx context.Context) (string, error) {
+	if c.opts.Username == "" {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	if token != "" {
+		return token, nil
+	}
+
+	payload, _ := json.Marshal(map[string]string{"name": c.opts.Username, "password": c.opts.Password})
+	body, err := c.post(ctx, c.http, "/v3/auth/authenticate", payload, "")
+	if err != nil {
+		return "", fmt.Errorf("etcd authentication failed: %w", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return "", fmt.Errorf("failed to decode authentication response: %w", err)
+	}
+	if resp.Token == "" {
+		return "", fmt.Errorf("etcd authentication returned no token; is auth enabled on the cluster?")
+	}
+
+	c.mu.Lock()
+	c.token = resp.Token
+	c.mu.Unlock()
+	return resp.Token, nil
+}
+
+// statusError decodes a gateway error. The gateway maps gRPC codes to HTTP
+// statuses: PermissionDenied to 403, Unauthenticated to 401.
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil {
+		switch {
+		case body.Message != "":
+			message = body.Message
+		case body.Error != "":
+			message = body.Error
+		}
+	}
+	return &StatusError{Code: resp.StatusCode, Message: message}
+}
+
+// prefixEnd is the range end that selects every key starting with prefix
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// No upper bound: every key from prefix on
+	return []byte{0}
+}
@@ -0,0 +1,356 @@
+// Package etcd implements a secret store for etcd v3 and the small client
+// dsops uses to read it.
+//
+// Keys are addressed as "path/to/key", relative to the store's prefix. A
+// key holding a JSON document can be narrowed to one field with
+// "path/to/key#.field.path". The mod_revision of a key is its version, and
+// Watch waits for it to change with an etcd watch.
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/jsonfield"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Config holds the etcd store configuration. Unset connection settings fall
+// back to the environment variables etcdctl uses.
+type Config struct {
+	Endpoints     []string `yaml:"endpoints"`       // Member URLs (default: $ETCDCTL_ENDPOINTS, then http://127.0.0.1:2379)
+	Username      string   `yaml:"username"`        // User for etcd auth (default: $ETCDCTL_USER, as user or user:password)
+	Password      string   `yaml:"password"`        // Password for etcd auth (default: $ETCDCTL_PASSWORD)
+	Prefix        string   `yaml:"prefix"`          // Key prefix, e.g. "/config/myapp/"
+	CACert        string   `yaml:"ca_cert"`         // Path to CA certificate (default: $ETCDCTL_CACERT)
+	ClientCert    string   `yaml:"client_cert"`     // Path to client certificate for mTLS (default: $ETCDCTL_CERT)
+	ClientKey     string   `yaml:"client_key"`      // Path to client key for mTLS (default: $ETCDCTL_KEY)
+	TLSServerName string   `yaml:"tls_server_name"` // Server name to verify
+	TLSSkip       bool     `yaml:"tls_skip"`        // Skip TLS verification (not recommended)
+}
+
+// Provider reads secrets from etcd
+type Provider struct {
+	name   string
+	config Config
+
+	mu     sync.Mutex
+	client *Client
+}
+
+// NewProvider creates an etcd store. The cluster is contacted on first use.
+func NewProvider(name string, configMap map[string]interface{}) (*Provider, error) {
+	var config Config
+	switch endpoints := configMap["endpoints"].(type) {
+	case string:
+		config.Endpoints = strings.Split(endpoints, ",")
+	case []interface{}:
+		for _, e := range endpoints {
+			if s, ok := e.(string); ok {
+				config.Endpoints = append(config.Endpoints, s)
+			}
+		}
+	case []string:
+		config.Endpoints = endpoints
+	}
+	for key, target := range map[string]*string{
+		"username":        &config.Username,
+		"password":        &config.Password,
+		"prefix":          &config.Prefix,
+		"ca_cert":         &config.CACert,
+		"client_cert":     &config.ClientCert,
+		"client_key":      &config.ClientKey,
+		"tls_server_name": &config.TLSServerName,
+	} {
+		if value, ok := configMap[key].(string); ok {
+			*target = value
+		}
+	}
+	if tlsSkip, ok := configMap["tls_skip"].(bool); ok {
+		config.TLSSkip = tlsSkip
+	}
+
+	if len(config.Endpoints) == 0 {
+		if endpoints := os.Getenv("ETCDCTL_ENDPOINTS"); endpoints != "" {
+			config.Endpoints = strings.Split(endpoints, ",")
+		}
+	}
+	for target, env := range map[*string]string{
+		&config.CACert:     "ETCDCTL_CACERT",
+		&config.ClientCert: "ETCDCTL_CERT",
+		&config.ClientKey:  "ETCDCTL_KEY",
+	} {
+		if *target == "" {
+			*target = os.Getenv(env)
+		}
+	}
+	if config.Username == "" {
+		// etcdctl accepts --user user:password
+		user, password, hasPassword := strings.Cut(os.Getenv("ETCDCTL_USER"), ":")
+		config.Username = user
+		if hasPassword && config.Password == "" {
+			config.Password = password
+		}
+	}
+	if config.Username != "" && config.Password == "" {
+		config.Password = os.Getenv("ETCDCTL_PASSWORD")
+	}
+
+	if (config.ClientCert == "") != (config.ClientKey == "") {
+		return nil, dserrors.ConfigError{
+			Field:      "client_cert",
+			Message:    "client_cert and client_key must be set together",
+			Suggestion: "Set both to the PEM files of the client certificate and its key",
+		}
+	}
+	for i, endpoint := range config.Endpoints {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint != "" && !strings.Contains(endpoint, "://") {
+			scheme := "http://"
+			if config.CACert != "" || config.ClientCert != "" {
+				scheme = "https://"
+			}
+			endpoint = scheme + endpoint
+		}
+		config.Endpoints[i] = endpoint
+	}
+
+	return &Provider{name: name, config: config}, nil
+}
+
+// NewProviderWithClient creates an etcd store with a given client (for testing)
+func NewProviderWithClient(name string, configMap map[string]interface{}, client *Client) (*Provider, error) {
+	p, err := NewProvider(name, configMap)
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the provider's capabilities
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false, // old revisions are readable only until compaction
+		SupportsMetadata:   true,
+		SupportsWatching:   true,
+		SupportsBinary:     true,
+		RequiresAuth:       false, // auth is optional in etcd
+		AuthMethods:        []string{"password", "certificate"},
+	}
+}
+
+// Validate checks that the cluster is reachable and the prefix can be read
+func (p *Provider) Validate(ctx context.Context) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.CountPrefix(ctx, p.config.Prefix); err != nil {
+		return p.apiError(client, err, fmt.Sprintf("Cannot read etcd keys under '%s'", p.config.Prefix))
+	}
+	return nil
+}
+
+// Resolve reads a key, or one field of the JSON document it holds
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	kv, _, err := client.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return provider.SecretValue{}, p.apiError(client, err, fmt.Sprintf("Failed to read etcd key %s", key))
+	}
+
+	value := string(kv.Value)
+	if field != "" {
+		if value, err = jsonfield.Extract(value, field); err != nil {
+			return provider.SecretValue{}, dserrors.UserError{
+				Message:    fmt.Sprintf("Cannot select '%s' from etcd key %s", field, key),
+				Details:    err.Error(),
+				Suggestion: "Check that the key holds a JSON object with that field",
+			}
+		}
+	}
+
+	return provider.SecretValue{
+		Value:   value,
+		Version: strconv.FormatInt(int64(kv.ModRevision), 10),
+		Metadata: map[string]string{
+			"provider":        p.name,
+			"key":             key,
+			"create_revision": strconv.FormatInt(int64(kv.CreateRevision), 10),
+			"version":         strconv.FormatInt(int64(kv.Version), 10),
+		},
+	}, nil
+}
+
+// Describe returns key metadata without returning its value. The version is
+// the key's mod_revision.
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	kv, _, err := client.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, p.apiError(client, err, fmt.Sprintf("Failed to read etcd key %s", key))
+	}
+	return describeKV(kv, field), nil
+}
+
+// Watch waits with an etcd watch until the key's mod_revision differs from
+// version. Any write to the key counts as a change, including writes that
+// leave the selected field as it was.
+func (p *Provider) Watch(ctx context.Context, ref provider.Reference, version string) (provider.Metadata, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	key, field, err := p.parseKey(ref)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	kv, header, err := client.Get(ctx, key)
+	switch {
+	case IsNotFound(err):
+		if version != "" {
+			return provider.Metadata{Exists: false}, nil
+		}
+	case err != nil:
+		return provider.Metadata{}, p.apiError(client, err, fmt.Sprintf("Failed to read etcd key %s", key))
+	case strconv.FormatInt(int64(kv.ModRevision), 10) != version:
+		return describeKV(kv, field), nil
+	}
+
+	// Watch from the revision after the read so no change is missed
+	event, err := client.Watch(ctx, key, int64(header.Revision)+1)
+	if err != nil {
+		if ctx.Err() != nil {
+			return provider.Metadata{}, ctx.Err()
+		}
+		return provider.Metadata{}, p.apiError(client, err, fmt.Sprintf("Failed to watch etcd key %s", key))
+	}
+	if event.Deleted {
+		return provider.Metadata{Exists: false}, nil
+	}
+	return describeKV(&event.KV, field), nil
+}
+
+func (p *Provider) getClient() (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+	client, err := NewClient(ClientOptions{
+		Endpoints:     p.config.Endpoints,
+		Username:      p.config.Username,
+		Password:      p.config.Password,
+		CACert:        p.config.CACert,
+		ClientCert:    p.config.ClientCert,
+		ClientKey:     p.config.ClientKey,
+		TLSServerName: p.config.TLSServerName,
+		TLSSkip:       p.config.TLSSkip,
+	})
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    "Failed to configure the etcd client",
+			Details:    err.Error(),
+			Suggestion: "Check ca_cert, client_cert and client_key on the store",
+		}
+	}
+	p.client = client
+	return client, nil
+}
+
+// parseKey splits "path/to/key#field" and applies the store prefix
+func (p *Provider) parseKey(ref provider.Reference) (key, field string, err error) {
+	path, field, _ := strings.Cut(ref.Key, "#")
+	if field == "" {
+		field = ref.Field
+	}
+	if path == "" {
+		return "", "", dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid etcd key reference: %s", ref.Key),
+			Suggestion: "Use 'path/to/key' or 'path/to/key#.field', e.g. 'myapp/database#.password'",
+		}
+	}
+	return p.config.Prefix + path, field, nil
+}
+
+func (p *Provider) apiError(client *Client, err error, message string) error {
+	userErr := dserrors.UserError{Message: message, Details: err.Error()}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Code {
+		case http.StatusUnauthorized:
+			userErr.Suggestion = "etcd rejected the credentials; check username and password on the store"
+		case http.StatusForbidden:
+			userErr.Suggestion = "The etcd user's roles do not grant read on this key; check 'etcdctl role get'"
+		}
+	}
+	if userErr.Suggestion == "" {
+		userErr.Suggestion = fmt.Sprintf("Check that etcd is reachable at %s with 'etcdctl endpoint health'",
+			strings.Join(client.Endpoints(), ","))
+	}
+	return userErr
+}
+
+func describeKV(kv *KeyValue, field string) provider.Metadata {
+	size := len(kv.Value)
+	if field != "" {
+		value, err := jsonfield.Extract(string(kv.Value), field)
+		if err != nil {
+			return provider.Metadata{Exists: false}
+		}
+		size = len(value)
+	}
+
+	tags := map[string]string{
+		"create_revision": strconv.FormatInt(int64(kv.CreateRevision), 10),
+		"version":         strconv.FormatInt(int64(kv.Version), 10),
+	}
+	if kv.Lease != 0 {
+		tags["lease"] = strconv.FormatInt(int64(kv.Lease), 16)
+	}
+	return provider.Metadata{
+		Exists:      true,
+		Version:     strconv.FormatInt(int64(kv.ModRevision), 10),
+		Size:        size,
+		Permissions: []string{"read"},
+		Tags:        tags,
+	}
+}
@@ -0,0 +1,432 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// fakeEvent is one entry of the fake's change history
+type fakeEvent struct {
+	deleted bool
+	kv      map[string]string
+}
+
+// fakeCluster is an httptest stand-in for the etcd v3 JSON gateway. Like
+// the real gateway it encodes int64 fields as strings.
+type fakeCluster struct {
+	t        *testing.T
+	username string
+	password string
+
+	mu       sync.Mutex
+	changed  chan struct{} // closed and replaced on every write
+	revision int64
+	keys     map[string]map[string]string
+	history  []fakeEvent
+	tokens   int
+	requests []string
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	return &fakeCluster{t: t, changed: make(chan struct{}), revision: 1, keys: map[string]map[string]string{}}
+}
+
+func (f *fakeCluster) start() *httptest.Server {
+	server := httptest.NewServer(f)
+	f.t.Cleanup(server.Close)
+	return server
+}
+
+func (f *fakeCluster) put(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revision++
+	rev := strconv.FormatInt(f.revision, 10)
+	kv, ok := f.keys[key]
+	if !ok {
+		kv = map[string]string{"create_revision": rev, "version": "0"}
+		f.keys[key] = kv
+	}
+	version, _ := strconv.Atoi(kv["version"])
+	kv["key"], kv["value"] = encode(key), encode(value)
+	kv["mod_revision"], kv["version"] = rev, strconv.Itoa(version+1)
+
+	f.history = append(f.history, fakeEvent{kv: copyKV(kv)})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeCluster) delete(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revision++
+	delete(f.keys, key)
+	f.history = append(f.history, fakeEvent{deleted: true, kv: map[string]string{
+		"key": encode(key), "mod_revision": strconv.FormatInt(f.revision, 10),
+	}})
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request map[string]json.RawMessage
+	require.NoError(f.t, json.NewDecoder(r.Body).Decode(&request))
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r.URL.Path)
+	if r.URL.Path == "/v3/auth/authenticate" {
+		var name, password string
+		_ = json.Unmarshal(request["name"], &name)
+		_ = json.Unmarshal(request["password"], &password)
+		if name != f.username || password != f.password {
+			f.mu.Unlock()
+			f.error(w, http.StatusBadRequest, "etcdserver: authentication failed, invalid user ID or password")
+			return
+		}
+		f.tokens++
+		token := "token-" + strconv.Itoa(f.tokens)
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+		return
+	}
+	authorized := f.username == "" || r.Header.Get("Authorization") == "token-"+strconv.Itoa(f.tokens)
+	f.mu.Unlock()
+	if !authorized {
+		f.error(w, http.StatusUnauthorized, "etcdserver: invalid auth token")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		f.rangeKeys(w, request)
+	case "/v3/watch":
+		f.watch(w, r, request)
+	default:
+		f.error(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+	}
+}
+
+func (f *fakeCluster) rangeKeys(w http.ResponseWriter, request map[string]json.RawMessage) {
+	var key, rangeEnd []byte
+	var countOnly bool
+	_ = json.Unmarshal(request["key"], &key)
+	_ = json.Unmarshal(request["range_end"], &rangeEnd)
+	_ = json.Unmarshal(request["count_only"], &countOnly)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if strings.HasPrefix(string(key), "forbidden/") {
+		f.error(w, http.StatusForbidden, "etcdserver: permission denied")
+		return
+	}
+
+	var kvs []map[string]string
+	for k, kv := range f.keys {
+		if k == string(key) || (rangeEnd != nil && k >= string(key) && (string(rangeEnd) == "\x00" || k < string(rangeEnd))) {
+			kvs = append(kvs, kv)
+		}
+	}
+	response := map[string]interface{}{
+		"header": map[string]string{"revision": strconv.FormatInt(f.revision, 10)},
+		"count":  strconv.Itoa(len(kvs)),
+	}
+	if !countOnly && len(kvs) > 0 {
+		response["kvs"] = kvs
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeCluster) watch(w http.ResponseWriter, r *http.Request, request map[string]json.RawMessage) {
+	var create struct {
+		Key           []byte `json:"key"`
+		StartRevision int64  `json:"start_revision"`
+	}
+	require.NoError(f.t, json.Unmarshal(request["create_request"], &create))
+
+	encoder := json.NewEncoder(w)
+	_ = encoder.Encode(map[string]interface{}{"result": map[string]interface{}{"created": true}})
+	w.(http.Flusher).Flush()
+
+	for {
+		f.mu.Lock()
+		var events []map[string]interface{}
+		for _, e := range f.history {
+			rev, _ := strconv.ParseInt(e.kv["mod_revision"], 10, 64)
+			if rev < create.StartRevision || e.kv["key"] != encode(string(create.Key)) {
+				continue
+			}
+			event := map[string]interface{}{"kv": e.kv}
+			if e.deleted {
+				event["type"] = "DELETE"
+			}
+			events = append(events, event)
+		}
+		changed := f.changed
+		f.mu.Unlock()
+
+		if len(events) > 0 {
+			_ = encoder.Encode(map[string]interface{}{"result": map[string]interface{}{"events": events}})
+			w.(http.Flusher).Flush()
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (f *fakeCluster) error(w http.ResponseWriter, code int, message string) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": message, "message": message, "code": code})
+}
+
+func encode(s string) string {
+	data, _ := json.Marshal([]byte(s))
+	return strings.Trim(string(data), `"`)
+}
+
+func copyKV(kv map[string]string) map[string]string {
+	out := make(map[string]string, len(kv))
+	for k, v := range kv {
+		out[k] = v
+	}
+	return out
+}
+
+func newTestProvider(t *testing.T, endpoints []interface{}, config map[string]interface{}) *Provider {
+	t.Helper()
+
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	config["endpoints"] = endpoints
+	p, err := NewProvider("etcd", config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestProvider_Resolve(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.put("/config/myapp/api-key", "abc123")
+	cluster.put("/config/myapp/database", `{"username":"app","password":"hunter2"}`)
+	server := cluster.start()
+
+	p := newTestProvider(t, []interface{}{server.URL}, map[string]interface{}{"prefix": "/config/myapp/"})
+	ctx := context.Background()
+
+	secret, err := p.Resolve(ctx, provider.Reference{Key: "api-key"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", secret.Value)
+	assert.Equal(t, "2", secret.Version)
+	assert.Equal(t, "/config/myapp/api-key", secret.Metadata["key"])
+
+	secret, err = p.Resolve(ctx, provider.Reference{Key: "database#.password"})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", secret.Value)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "database#.port"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Cannot select '.port'")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	require.NoError(t, p.Validate(ctx))
+}
+
+func TestProvider_Auth(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.username, cluster.password = "dsops", "s3cret"
+	cluster.put("app/key", "value")
+	server := cluster.start()
+
+	p := newTestProvider(t, []interface{}{server.URL}, map[string]interface{}{"username": "dsops", "password": "s3cret"})
+	ctx := context.Background()
+
+	secret, err := p.Resolve(ctx, provider.Reference{Key: "app/key"})
+	require.NoError(t, err)
+	assert.Equal(t, "value", secret.Value)
+
+	// An expired token is renewed once
+	cluster.mu.Lock()
+	cluster.tokens++
+	cluster.mu.Unlock()
+	_, err = p.Resolve(ctx, provider.Reference{Key: "app/key"})
+	require.NoError(t, err)
+	cluster.mu.Lock()
+	authentications := strings.Count(strings.Join(cluster.requests, " "), "/v3/auth/authenticate")
+	cluster.mu.Unlock()
+	assert.Equal(t, 2, authentications, "authenticated, then again after the token was rejected")
+
+	wrong := newTestProvider(t, []interface{}{server.URL}, map[string]interface{}{"username": "dsops", "password": "nope"})
+	_, err = wrong.Resolve(ctx, provider.Reference{Key: "app/key"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication failed")
+}
+
+func TestProvider_PermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeCluster(t).start()
+	p := newTestProvider(t, []interface{}{server.URL}, nil)
+
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "forbidden/key"})
+	require.Error(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Suggestion, "roles do not grant read")
+}
+
+func TestProvider_EndpointFailover(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.put("app/key", "value")
+	server := cluster.start()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	p := newTestProvider(t, []interface{}{downURL, server.URL}, nil)
+	secret, err := p.Resolve(context.Background(), provider.Reference{Key: "app/key"})
+	require.NoError(t, err)
+	assert.Equal(t, "value", secret.Value)
+}
+
+func TestProvider_Describe(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.put("app/db", `{"password":"hunter2"}`)
+	cluster.put("app/db", `{"password":"rotated"}`)
+	server := cluster.start()
+	p := newTestProvider(t, []interface{}{server.URL}, nil)
+	ctx := context.Background()
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "app/db"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "3", meta.Version)
+	assert.Equal(t, "2", meta.Tags["create_revision"])
+	assert.Equal(t, "2", meta.Tags["version"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "app/db#.password"})
+	require.NoError(t, err)
+	assert.Equal(t, len("rotated"), meta.Size)
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "app/missing"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestProvider_Watch(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeCluster(t)
+	cluster.put("app/token", "v1")
+	server := cluster.start()
+
+	p := newTestProvider(t, []interface{}{server.URL}, nil)
+	assert.True(t, p.Capabilities().SupportsWatching)
+	var _ provider.Watcher = p
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ref := provider.Reference{Key: "app/token"}
+
+	meta, err := p.Describe(ctx, ref)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cluster.put("app/other", "x") // a different key does not end the watch
+		time.Sleep(50 * time.Millisecond)
+		cluster.put("app/token", "v2")
+	}()
+	changed, err := p.Watch(ctx, ref, meta.Version)
+	require.NoError(t, err)
+	assert.True(t, changed.Exists)
+	assert.Equal(t, "4", changed.Version)
+
+	// A stale version returns at once
+	again, err := p.Watch(ctx, ref, meta.Version)
+	require.NoError(t, err)
+	assert.Equal(t, "4", again.Version)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cluster.delete("app/token")
+	}()
+	deleted, err := p.Watch(ctx, ref, changed.Version)
+	require.NoError(t, err)
+	assert.False(t, deleted.Exists)
+
+	// Watching a missing key waits for it to be created
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cluster.put("app/token", "v3")
+	}()
+	created, err := p.Watch(ctx, ref, "")
+	require.NoError(t, err)
+	assert.True(t, created.Exists)
+	assert.Equal(t, "1", created.Tags["version"])
+
+	short, stop := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stop()
+	_, err = p.Watch(short, ref, created.Version)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewProvider_Config(t *testing.T) {
+	t.Setenv("ETCDCTL_ENDPOINTS", "etcd-1:2379,etcd-2:2379")
+	t.Setenv("ETCDCTL_USER", "root:from-env")
+	t.Setenv("ETCDCTL_CACERT", "/etc/etcd/ca.pem")
+
+	p, err := NewProvider("etcd", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://etcd-1:2379", "https://etcd-2:2379"}, p.config.Endpoints)
+	assert.Equal(t, "root", p.config.Username)
+	assert.Equal(t, "from-env", p.config.Password)
+
+	p, err = NewProvider("etcd", map[string]interface{}{"endpoints": "http://10.0.0.1:2379", "username": "app", "password": "mine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://10.0.0.1:2379"}, p.config.Endpoints)
+	assert.Equal(t, "app", p.config.Username)
+	assert.Equal(t, "mine", p.config.Password)
+
+	_, err = NewProvider("etcd", map[string]interface{}{"client_key": "key.pem"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client_cert and client_key must be set together")
+}
+
+func TestPrefixEnd(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []byte("/config/myapp0"), prefixEnd("/config/myapp/"))
+	assert.Equal(t, []byte("b"), prefixEnd("a\xff"))
+	assert.Equal(t, []byte{0}, prefixEnd(""))
+}
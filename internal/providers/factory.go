@@ -15,6 +15,22 @@ type KeychainConfig struct {
 	// AccessGroup (macOS only) specifies the keychain access group
 	// for shared keychain items between applications
 	AccessGroup string `mapstructure:"access_group"`
+
+	// Backend selects auto (default), system or file. Auto uses the OS
+	// keychain when it is reachable without a GUI and the file otherwise.
+	Backend string `mapstructure:"backend"`
+
+	// File is the encrypted file keyring path
+	// (default: <user config dir>/dsops/keychain.age)
+	File string `mapstructure:"file"`
+
+	// IdentityFile is an age key file that encrypts the file keyring instead
+	// of a passphrase. It is generated on first write if missing.
+	IdentityFile string `mapstructure:"identity_file"`
+
+	// PassphraseEnv names the environment variable holding the file keyring
+	// passphrase (default: DSOPS_KEYCHAIN_PASSPHRASE)
+	PassphraseEnv string `mapstructure:"passphrase_env"`
 }
 
 // InfisicalConfig holds configuration for the Infisical provider
@@ -94,11 +110,60 @@ type AkeylessAuth struct {
 	GCPAudience string `mapstructure:"gcp_audience"`
 }
 
+// BitwardenSMConfig holds configuration for the Bitwarden Secrets Manager provider
+type BitwardenSMConfig struct {
+	// AccessToken is the machine account access token
+	// Defaults to the BWS_ACCESS_TOKEN environment variable
+	AccessToken string `mapstructure:"access_token"`
+
+	// OrganizationID scopes secret names to an organization
+	// Defaults to the organization of the machine account
+	OrganizationID string `mapstructure:"organization_id"`
+
+	// ProjectID limits secret names to one project
+	ProjectID string `mapstructure:"project_id"`
+
+	// ServerURL is the base URL of a self-hosted server; the API and identity
+	// services are expected at /api and /identity
+	ServerURL string `mapstructure:"server_url"`
+
+	// APIURL and IdentityURL override the service URLs individually
+	// Default to "https://api.bitwarden.com" and "https://identity.bitwarden.com"
+	APIURL      string `mapstructure:"api_url"`
+	IdentityURL string `mapstructure:"identity_url"`
+
+	// Timeout for API requests (default: 30s)
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// CACert is path to custom CA certificate for self-hosted instances
+	CACert string `mapstructure:"ca_cert"`
+}
+
+// OnePasswordAPIConfig holds configuration for the 1Password Connect and
+// service account providers
+type OnePasswordAPIConfig struct {
+	// Host is the 1Password Connect server URL (Connect only)
+	// Defaults to the OP_CONNECT_HOST environment variable
+	Host string `mapstructure:"host"`
+
+	// Token is the Connect access token or service account token
+	// Defaults to OP_CONNECT_TOKEN or OP_SERVICE_ACCOUNT_TOKEN
+	Token string `mapstructure:"token"`
+
+	// Timeout for API requests (default: 30s)
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// CACert is path to custom CA certificate for the Connect server
+	CACert string `mapstructure:"ca_cert"`
+}
+
 // Default values for provider configurations
 const (
-	DefaultInfisicalHost   = "https://app.infisical.com"
-	DefaultAkeylessGateway = "https://api.akeyless.io"
-	DefaultTimeout         = 30 * time.Second
+	DefaultInfisicalHost        = "https://app.infisical.com"
+	DefaultAkeylessGateway      = "https://api.akeyless.io"
+	DefaultBitwardenAPIURL      = "https://api.bitwarden.com"
+	DefaultBitwardenIdentityURL = "https://identity.bitwarden.com"
+	DefaultTimeout              = 30 * time.Second
 )
 
 // NewKeychainProviderFunc is the factory function signature for keychain
@@ -0,0 +1,72 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/systmms/dsops/internal/agekey"
+	dserrors "github.com/systmms/dsops/internal/errors"
+)
+
+// decrypt returns the plaintext of a file according to the decrypt setting
+func (p *Provider) decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	switch p.config.Decrypt {
+	case DecryptAge:
+		plaintext, err := p.decryptAge(ctx, data)
+		if err != nil {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to decrypt %s with age", p.config.Path),
+				Details:    err.Error(),
+				Suggestion: "Set age_key_file, age_key_keychain, SOPS_AGE_KEY or SOPS_AGE_KEY_FILE to an age identity the file is encrypted for",
+				Err:        err,
+			}
+		}
+		return plaintext, nil
+	case DecryptGPG:
+		stdout, stderr, err := p.executor.Execute(ctx, "gpg", "--quiet", "--batch", "--decrypt", p.config.Path)
+		if err != nil {
+			details := strings.TrimSpace(string(stderr))
+			if details == "" {
+				details = err.Error()
+			}
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to decrypt %s with gpg", p.config.Path),
+				Details:    details,
+				Suggestion: "Check that your secret key is available with 'gpg --list-secret-keys' and that gpg-agent can unlock it",
+				Err:        err,
+			}
+		}
+		return stdout, nil
+	default:
+		return data, nil
+	}
+}
+
+// decryptAge opens a binary or ASCII-armored age file with the identities
+// the sops store would use, so keys set up for sops work as-is
+func (p *Provider) decryptAge(ctx context.Context, data []byte) ([]byte, error) {
+	identities, sources, err := agekey.Load(ctx, agekey.Sources{
+		KeyFile:  p.config.AgeKeyFile,
+		Keychain: p.config.AgeKeyKeychain,
+		Lookup:   p.keychain,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Debug("Loaded %d age identities from %s", len(identities), strings.Join(sources, ", "))
+
+	var src io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+	reader, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
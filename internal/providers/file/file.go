@@ -0,0 +1,418 @@
+// Package file implements secret stores backed by plain local files: .env,
+// JSON and YAML. They let a team start with a file next to dsops.yaml and
+// later move to a cloud store by changing only the store definition.
+//
+// Values in JSON and YAML files are addressed by dotted path
+// ("database.password", "hosts.0"); .env values by variable name. Files may
+// be encrypted as a whole with age or GPG, and are re-read whenever they
+// change on disk.
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/systmms/dsops/internal/agekey"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	pkgexec "github.com/systmms/dsops/pkg/exec"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Supported file formats, one per store type (file.dotenv, file.json, file.yaml)
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+	FormatYAML   = "yaml"
+)
+
+// Decryption modes
+const (
+	DecryptNone = "none"
+	DecryptAge  = "age"
+	DecryptGPG  = "gpg"
+)
+
+// Config holds the file store configuration
+type Config struct {
+	Path           string `yaml:"path"`             // File to read; relative paths are resolved from dsops.yaml's directory
+	Decrypt        string `yaml:"decrypt"`          // none, age or gpg (default: from the file extension)
+	AgeKeyFile     string `yaml:"age_key_file"`     // age identity file for decrypt: age
+	AgeKeyKeychain string `yaml:"age_key_keychain"` // Keychain item holding the age identity, as service/account
+}
+
+// Provider reads values from a local .env, JSON or YAML file
+type Provider struct {
+	name     string
+	format   string
+	config   Config
+	logger   *logging.Logger
+	executor pkgexec.CommandExecutor
+	keychain agekey.KeychainLookup
+
+	mu     sync.Mutex
+	cached *document
+	warned bool
+}
+
+// NewProvider creates a file store reading the given format
+func NewProvider(name, format string, configMap map[string]interface{}) (*Provider, error) {
+	return NewProviderWithExecutor(name, format, configMap, pkgexec.DefaultExecutor())
+}
+
+// NewProviderWithKeychain creates a file store that reads age_key_keychain
+// through keychain
+func NewProviderWithKeychain(name, format string, configMap map[string]interface{}, keychain agekey.KeychainLookup) (*Provider, error) {
+	p, err := NewProvider(name, format, configMap)
+	if err != nil {
+		return nil, err
+	}
+	p.keychain = keychain
+	return p, nil
+}
+
+// NewProviderWithExecutor creates a file store that runs gpg through the
+// given executor. Use it in tests.
+func NewProviderWithExecutor(name, format string, configMap map[string]interface{}, executor pkgexec.CommandExecutor) (*Provider, error) {
+	switch format {
+	case FormatDotenv, FormatJSON, FormatYAML:
+	default:
+		return nil, fmt.Errorf("unsupported file store format: %s", format)
+	}
+
+	var config Config
+	if path, ok := configMap["path"].(string); ok {
+		config.Path = expandHome(path)
+	}
+	if decrypt, ok := configMap["decrypt"].(string); ok {
+		config.Decrypt = strings.ToLower(decrypt)
+	}
+	if keyFile, ok := configMap["age_key_file"].(string); ok {
+		config.AgeKeyFile = expandHome(keyFile)
+	}
+	if item, ok := configMap["age_key_keychain"].(string); ok {
+		config.AgeKeyKeychain = item
+	}
+
+	if config.Path == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "path",
+			Message:    fmt.Sprintf("file.%s store requires a path", format),
+			Suggestion: fmt.Sprintf("Set 'path' to the file to read, e.g. path: %s", examplePath(format)),
+		}
+	}
+	if config.Decrypt == "" {
+		config.Decrypt = decryptFromExtension(config.Path)
+	}
+	switch config.Decrypt {
+	case DecryptNone, DecryptAge, DecryptGPG:
+	default:
+		return nil, dserrors.ConfigError{
+			Field:      "decrypt",
+			Value:      config.Decrypt,
+			Message:    "unsupported decryption mode",
+			Suggestion: "Supported modes: none, age, gpg",
+		}
+	}
+
+	return &Provider{
+		name:     name,
+		format:   format,
+		config:   config,
+		logger:   logging.New(false, false),
+		executor: executor,
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the provider's capabilities
+func (p *Provider) Capabilities() provider.Capabilities {
+	authMethods := []string{}
+	if p.config.Decrypt != DecryptNone {
+		authMethods = []string{p.config.Decrypt}
+	}
+	return provider.Capabilities{
+		SupportsVersioning: false, // History lives in version control
+		SupportsMetadata:   true,
+		SupportsWatching:   false,
+		SupportsBinary:     false,
+		RequiresAuth:       p.config.Decrypt != DecryptNone,
+		AuthMethods:        authMethods,
+	}
+}
+
+// Validate checks that the file can be read, decrypted and parsed
+func (p *Provider) Validate(ctx context.Context) error {
+	_, err := p.load(ctx)
+	return err
+}
+
+// Resolve returns the value at a key. A key naming a mapping or list returns
+// it as JSON; a '#field' selects a value from inside a JSON value.
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	doc, err := p.load(ctx)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	value, err := p.lookup(doc, ref)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	return provider.SecretValue{
+		Value:     value,
+		UpdatedAt: doc.modTime,
+		Metadata: map[string]string{
+			"provider": p.name,
+			"file":     p.config.Path,
+			"format":   p.format,
+		},
+	}, nil
+}
+
+// Describe reports whether a key exists, with the file's modification time
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	doc, err := p.load(ctx)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, err
+	}
+
+	value, err := p.lookup(doc, ref)
+	if err != nil {
+		var notFound *provider.NotFoundError
+		if errors.As(err, &notFound) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, err
+	}
+
+	valueType := "string"
+	key, field, _ := strings.Cut(ref.Key, "#")
+	if field == "" {
+		field = ref.Field
+	}
+	if node, ok := doc.get(key); ok && isTree(node) && field == "" {
+		valueType = "tree"
+	}
+
+	return provider.Metadata{
+		Exists:      true,
+		UpdatedAt:   doc.modTime,
+		Size:        len(value),
+		Type:        valueType,
+		Permissions: []string{"read"},
+		Tags: map[string]string{
+			"file":    p.config.Path,
+			"format":  p.format,
+			"decrypt": p.config.Decrypt,
+		},
+	}, nil
+}
+
+// ListSecrets lists every key the file defines: variable names for .env
+// files and the dotted paths of leaf values for JSON and YAML
+func (p *Provider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	doc, err := p.load(ctx)
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	var secrets []provider.SecretInfo
+	for _, key := range doc.keys() {
+		secrets = append(secrets, provider.SecretInfo{
+			Key:       key,
+			UpdatedAt: doc.modTime,
+		})
+	}
+	return provider.PageSecrets(secrets, opts)
+}
+
+// lookup finds the value for a reference, applying its field selector
+func (p *Provider) lookup(doc *document, ref provider.Reference) (string, error) {
+	key, field := ref.Key, ref.Field
+	if before, after, ok := strings.Cut(key, "#"); ok {
+		key, field = before, after
+	}
+	if key == "" {
+		return "", dserrors.UserError{
+			Message:    "File store key is empty",
+			Suggestion: keySuggestion(p.format),
+		}
+	}
+
+	node, ok := doc.get(key)
+	if !ok {
+		return "", &provider.NotFoundError{Provider: p.name, Key: key}
+	}
+	value, err := render(node)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s as JSON: %w", key, err)
+	}
+	if field == "" {
+		return value, nil
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Cannot select '%s' from %s", field, key),
+			Details:    "the value is not JSON",
+			Suggestion: "Remove the '#field' selector or store the value as JSON",
+		}
+	}
+	selected, ok := lookupPath(data, splitPath(field))
+	if !ok {
+		return "", &provider.NotFoundError{Provider: p.name, Key: key + "#" + field}
+	}
+	return render(selected)
+}
+
+// load returns the parsed file, reusing the cached copy while the file is
+// unchanged
+func (p *Provider) load(ctx context.Context) (*document, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.config.Path)
+	if err != nil {
+		return nil, p.fileError(err)
+	}
+	if !p.warned && worldReadable(info) {
+		p.warned = true
+		p.logger.Warn("%s is readable by every user on this machine (mode %04o); restrict it with 'chmod 600 %s'",
+			p.config.Path, info.Mode().Perm(), p.config.Path)
+	}
+	if p.cached != nil && p.cached.modTime.Equal(info.ModTime()) && p.cached.size == info.Size() {
+		return p.cached, nil
+	}
+
+	data, err := os.ReadFile(p.config.Path)
+	if err != nil {
+		return nil, p.fileError(err)
+	}
+	data, err = p.decrypt(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parse(p.format, data)
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to parse %s as %s", p.config.Path, p.format),
+			Details:    err.Error(),
+			Suggestion: fmt.Sprintf("Check the file's syntax, or use the store type that matches its format (file.%s)", formatFromExtension(p.config.Path)),
+			Err:        err,
+		}
+	}
+	doc.modTime = info.ModTime()
+	doc.size = info.Size()
+
+	p.cached = doc
+	return doc, nil
+}
+
+func (p *Provider) fileError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Secrets file not found: %s", p.config.Path),
+			Suggestion: "Check the 'path' setting; relative paths are resolved from the directory containing dsops.yaml",
+			Err:        err,
+		}
+	}
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("Failed to read secrets file %s", p.config.Path),
+		Details:    err.Error(),
+		Suggestion: "Check that the file exists and is readable",
+		Err:        err,
+	}
+}
+
+// worldReadable reports whether any user on the machine can read the file.
+// Windows does not use Unix permission bits, so it never warns there.
+func worldReadable(info os.FileInfo) bool {
+	return runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0
+}
+
+// decryptFromExtension infers the decryption mode from the outer extension,
+// e.g. secrets.json.age or .env.gpg
+func decryptFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".age":
+		return DecryptAge
+	case ".gpg", ".asc", ".pgp":
+		return DecryptGPG
+	default:
+		return DecryptNone
+	}
+}
+
+// formatFromExtension guesses a file's format, ignoring an encryption
+// extension
+func formatFromExtension(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if decryptFromExtension(path) != DecryptNone {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+	switch ext {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatDotenv
+	}
+}
+
+func examplePath(format string) string {
+	switch format {
+	case FormatJSON:
+		return "secrets.json"
+	case FormatYAML:
+		return "secrets.yaml"
+	default:
+		return ".env.local"
+	}
+}
+
+func keySuggestion(format string) string {
+	if format == FormatDotenv {
+		return "Use the variable name, e.g. 'DATABASE_URL'"
+	}
+	return "Use a dotted path such as 'database.password' or 'hosts.0'"
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// document is a parsed file. Values are kept as decoded: strings, numbers,
+// booleans, maps and slices.
+type document struct {
+	format  string
+	data    interface{}
+	modTime time.Time
+	size    int64
+}
@@ -0,0 +1,320 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/testutil"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func resolve(t *testing.T, p *Provider, key string) string {
+	t.Helper()
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: key})
+	require.NoError(t, err, key)
+	return value.Value
+}
+
+func TestProvider_Dotenv(t *testing.T) {
+	path := writeFile(t, ".env.local", `# Generated by dsops
+export DATABASE_URL=postgres://localhost/app   # local only
+API_KEY="sk-\"quoted\"\n"
+LITERAL='$NOT_EXPANDED'
+MULTILINE="line one
+line two"
+EMPTY=
+CONFIG={"user":"app","port":5432}
+`)
+
+	p, err := NewProvider("local", FormatDotenv, map[string]interface{}{"path": path})
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://localhost/app", resolve(t, p, "DATABASE_URL"))
+	assert.Equal(t, "sk-\"quoted\"\n", resolve(t, p, "API_KEY"))
+	assert.Equal(t, "$NOT_EXPANDED", resolve(t, p, "LITERAL"))
+	assert.Equal(t, "line one\nline two", resolve(t, p, "MULTILINE"))
+	assert.Equal(t, "", resolve(t, p, "EMPTY"))
+	assert.Equal(t, "app", resolve(t, p, "CONFIG#.user"))
+
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "CONFIG", Field: "port"})
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value.Value)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, value.UpdatedAt.Equal(info.ModTime()))
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "MISSING"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{Prefix: "A"})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 1)
+	assert.Equal(t, "API_KEY", result.Secrets[0].Key)
+}
+
+func TestProvider_JSONAndYAML(t *testing.T) {
+	jsonPath := writeFile(t, "secrets.json", `{
+  "database": {"password": "s3cret", "port": 5432, "replicas": ["db-1", "db-2"]},
+  "feature.flag": true,
+  "nothing": null
+}`)
+	yamlPath := writeFile(t, "secrets.yaml", `
+database:
+  password: s3cret
+  port: 5432
+  replicas: [db-1, db-2]
+feature.flag: true
+nothing: ~
+`)
+
+	for format, path := range map[string]string{FormatJSON: jsonPath, FormatYAML: yamlPath} {
+		t.Run(format, func(t *testing.T) {
+			p, err := NewProvider("local", format, map[string]interface{}{"path": path})
+			require.NoError(t, err)
+			require.NoError(t, p.Validate(context.Background()))
+
+			assert.Equal(t, "s3cret", resolve(t, p, "database.password"))
+			assert.Equal(t, "5432", resolve(t, p, "database.port"))
+			assert.Equal(t, "db-2", resolve(t, p, "database.replicas.1"))
+			assert.Equal(t, `["db-1","db-2"]`, resolve(t, p, "database.replicas"))
+			assert.Equal(t, "true", resolve(t, p, "feature.flag"))
+			assert.Equal(t, "", resolve(t, p, "nothing"))
+			assert.Equal(t, "s3cret", resolve(t, p, "database#.password"))
+
+			meta, err := p.Describe(context.Background(), provider.Reference{Key: "database"})
+			require.NoError(t, err)
+			assert.True(t, meta.Exists)
+			assert.Equal(t, "tree", meta.Type)
+			assert.Equal(t, format, meta.Tags["format"])
+
+			meta, err = p.Describe(context.Background(), provider.Reference{Key: "database.user"})
+			require.NoError(t, err)
+			assert.False(t, meta.Exists)
+
+			result, err := p.ListSecrets(context.Background(), provider.ListOptions{})
+			require.NoError(t, err)
+			var keys []string
+			for _, s := range result.Secrets {
+				keys = append(keys, s.Key)
+			}
+			assert.Equal(t, []string{
+				"database.password", "database.port", "database.replicas.0", "database.replicas.1",
+				"feature.flag", "nothing",
+			}, keys)
+		})
+	}
+}
+
+func TestProvider_ReloadsChangedFile(t *testing.T) {
+	path := writeFile(t, "secrets.yaml", "token: one\n")
+	p, err := NewProvider("local", FormatYAML, map[string]interface{}{"path": path})
+	require.NoError(t, err)
+	assert.Equal(t, "one", resolve(t, p, "token"))
+
+	require.NoError(t, os.WriteFile(path, []byte("token: second\n"), 0o600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Equal(t, "second", resolve(t, p, "token"))
+}
+
+func TestProvider_Errors(t *testing.T) {
+	_, err := NewProvider("local", FormatJSON, map[string]interface{}{})
+	var configErr dserrors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "path", configErr.Field)
+
+	_, err = NewProvider("local", FormatJSON, map[string]interface{}{"path": "x.json", "decrypt": "kms"})
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "decrypt", configErr.Field)
+
+	missing, err := NewProvider("local", FormatJSON, map[string]interface{}{"path": filepath.Join(t.TempDir(), "none.json")})
+	require.NoError(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, missing.Validate(context.Background()), &userErr)
+	assert.Contains(t, userErr.Message, "not found")
+	meta, err := missing.Describe(context.Background(), provider.Reference{Key: "a"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+
+	invalid, err := NewProvider("local", FormatJSON, map[string]interface{}{"path": writeFile(t, "bad.json", "{")})
+	require.NoError(t, err)
+	require.ErrorAs(t, invalid.Validate(context.Background()), &userErr)
+	assert.Contains(t, userErr.Message, "Failed to parse")
+
+	dotenv, err := NewProvider("local", FormatDotenv, map[string]interface{}{"path": writeFile(t, ".env", "PLAIN=text\n")})
+	require.NoError(t, err)
+	_, err = dotenv.Resolve(context.Background(), provider.Reference{Key: "PLAIN", Field: "user"})
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "Cannot select")
+}
+
+func TestProvider_AgeEncrypted(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SOPS_AGE_KEY", "")
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	keyFile := writeFile(t, "keys.txt", identity.String()+"\n")
+
+	encrypt := func(plaintext string, armored bool) []byte {
+		var buf bytes.Buffer
+		var dst io.Writer = &buf
+		var aw io.WriteCloser
+		if armored {
+			aw = armor.NewWriter(&buf)
+			dst = aw
+		}
+		enc, err := age.Encrypt(dst, identity.Recipient())
+		require.NoError(t, err)
+		_, err = enc.Write([]byte(plaintext))
+		require.NoError(t, err)
+		require.NoError(t, enc.Close())
+		if aw != nil {
+			require.NoError(t, aw.Close())
+		}
+		return buf.Bytes()
+	}
+
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "secrets.json.age")
+	require.NoError(t, os.WriteFile(binary, encrypt(`{"db":{"password":"from-age"}}`, false), 0o600))
+	armored := filepath.Join(dir, ".env.age")
+	require.NoError(t, os.WriteFile(armored, encrypt("TOKEN=armored\n", true), 0o600))
+
+	p, err := NewProvider("local", FormatJSON, map[string]interface{}{"path": binary, "age_key_file": keyFile})
+	require.NoError(t, err)
+	assert.Equal(t, []string{DecryptAge}, p.Capabilities().AuthMethods)
+	assert.Equal(t, "from-age", resolve(t, p, "db.password"))
+
+	// The same sources as the sops store: environment, keychain and sops'
+	// default key file
+	t.Setenv("SOPS_AGE_KEY_FILE", keyFile)
+	p, err = NewProvider("local", FormatDotenv, map[string]interface{}{"path": armored})
+	require.NoError(t, err)
+	assert.Equal(t, "armored", resolve(t, p, "TOKEN"))
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+
+	t.Setenv("SOPS_AGE_KEY", identity.String())
+	p, err = NewProvider("local", FormatDotenv, map[string]interface{}{"path": armored})
+	require.NoError(t, err)
+	assert.Equal(t, "armored", resolve(t, p, "TOKEN"))
+	t.Setenv("SOPS_AGE_KEY", "")
+
+	var asked string
+	p, err = NewProviderWithKeychain("local", FormatDotenv, map[string]interface{}{
+		"path":             armored,
+		"age_key_keychain": "dsops/age-key",
+	}, func(ctx context.Context, ref string) (string, error) {
+		asked = ref
+		return identity.String(), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "armored", resolve(t, p, "TOKEN"))
+	assert.Equal(t, "dsops/age-key", asked)
+
+	configHome := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "sops", "age"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, "sops", "age", "keys.txt"), []byte(identity.String()+"\n"), 0o600))
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	p, err = NewProvider("local", FormatDotenv, map[string]interface{}{"path": armored})
+	require.NoError(t, err)
+	assert.Equal(t, "armored", resolve(t, p, "TOKEN"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	p, err = NewProvider("local", FormatJSON, map[string]interface{}{
+		"path":         binary,
+		"age_key_file": writeFile(t, "other.txt", other.String()+"\n"),
+	})
+	require.NoError(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, p.Validate(context.Background()), &userErr)
+	assert.Contains(t, userErr.Message, "Failed to decrypt")
+}
+
+func TestProvider_GPGEncrypted(t *testing.T) {
+	path := writeFile(t, "secrets.yaml.gpg", "binary ciphertext")
+
+	mockExec := testutil.NewMockCommandExecutor()
+	mockExec.Responses["gpg --quiet --batch --decrypt "+path] = testutil.MockResponse{
+		Stdout: []byte("api:\n  key: from-gpg\n"),
+	}
+	p, err := NewProviderWithExecutor("local", FormatYAML, map[string]interface{}{"path": path}, mockExec)
+	require.NoError(t, err)
+	assert.Equal(t, "from-gpg", resolve(t, p, "api.key"))
+
+	// The decrypted file is cached until it changes on disk
+	assert.Equal(t, "from-gpg", resolve(t, p, "api.key"))
+	assert.Len(t, mockExec.RecordedCalls, 1)
+
+	failing := testutil.NewMockCommandExecutor()
+	failing.DefaultResponse = &testutil.MockResponse{
+		Stderr: []byte("gpg: decryption failed: No secret key"),
+		Err:    errors.New("exit status 2"),
+	}
+	p, err = NewProviderWithExecutor("local", FormatYAML, map[string]interface{}{"path": path}, failing)
+	require.NoError(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, p.Validate(context.Background()), &userErr)
+	assert.Equal(t, "gpg: decryption failed: No secret key", userErr.Details)
+}
+
+func TestWorldReadable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions only")
+	}
+	path := writeFile(t, ".env", "A=1\n")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.False(t, worldReadable(info))
+
+	require.NoError(t, os.Chmod(path, 0o644))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, worldReadable(info))
+}
+
+func TestDecryptFromExtension(t *testing.T) {
+	assert.Equal(t, DecryptAge, decryptFromExtension("secrets.json.age"))
+	assert.Equal(t, DecryptGPG, decryptFromExtension(".env.gpg"))
+	assert.Equal(t, DecryptGPG, decryptFromExtension("secrets.yaml.asc"))
+	assert.Equal(t, DecryptNone, decryptFromExtension("secrets.yaml"))
+
+	assert.Equal(t, FormatJSON, formatFromExtension("secrets.json.age"))
+	assert.Equal(t, FormatYAML, formatFromExtension("secrets.yml"))
+	assert.Equal(t, FormatDotenv, formatFromExtension(".env.local"))
+}
+
+func TestParseDotenv_Errors(t *testing.T) {
+	_, err := parseDotenv([]byte("NOT A LINE\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = parseDotenv([]byte("A=1\nB=\"open\n"))
+	assert.ErrorContains(t, err, "unterminated")
+
+	_, err = parseDotenv([]byte("C='open\n"))
+	assert.ErrorContains(t, err, "unterminated")
+}
@@ -0,0 +1,270 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parse decodes a file in the given format
+func parse(format string, data []byte) (*document, error) {
+	doc := &document{format: format}
+	switch format {
+	case FormatDotenv:
+		values, err := parseDotenv(data)
+		if err != nil {
+			return nil, err
+		}
+		tree := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			tree[key] = value
+		}
+		doc.data = tree
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc.data); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &doc.data); err != nil {
+			return nil, err
+		}
+	}
+	if doc.data == nil {
+		doc.data = map[string]interface{}{}
+	}
+	return doc, nil
+}
+
+// get returns the value for a key. .env keys are variable names; JSON and
+// YAML keys are dotted paths, where a key containing dots may also be
+// matched as a whole.
+func (d *document) get(key string) (interface{}, bool) {
+	if d.format == FormatDotenv {
+		value, ok := d.data.(map[string]interface{})[key]
+		return value, ok
+	}
+	if m, ok := d.data.(map[string]interface{}); ok {
+		if value, ok := m[key]; ok {
+			return value, true
+		}
+	}
+	return lookupPath(d.data, splitPath(key))
+}
+
+// keys lists the variable names or leaf paths in the file, sorted
+func (d *document) keys() []string {
+	var keys []string
+	var walk func(node interface{}, prefix string)
+	walk = func(node interface{}, prefix string) {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if len(v) == 0 && prefix != "" {
+				keys = append(keys, prefix)
+			}
+			for key, child := range v {
+				if d.format == FormatDotenv {
+					keys = append(keys, key)
+					continue
+				}
+				walk(child, joinPath(prefix, key))
+			}
+		case []interface{}:
+			if len(v) == 0 && prefix != "" {
+				keys = append(keys, prefix)
+			}
+			for i, child := range v {
+				walk(child, joinPath(prefix, strconv.Itoa(i)))
+			}
+		default:
+			if prefix != "" {
+				keys = append(keys, prefix)
+			}
+		}
+	}
+	walk(d.data, "")
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// splitPath splits a dotted path, ignoring a leading "."
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// lookupPath walks maps by key and lists by index
+func lookupPath(node interface{}, path []string) (interface{}, bool) {
+	for _, part := range path {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			node = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func isTree(node interface{}) bool {
+	switch node.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// render turns a value into the string handed to the environment: scalars as
+// written, null as empty, mappings and lists as JSON
+func render(node interface{}) (string, error) {
+	switch v := node.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	}
+	out, err := json.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// parseDotenv reads KEY=value lines. It accepts what dsops render and
+// docker compose write: comments, 'export ' prefixes, single-quoted literals,
+// and double-quoted values with escapes that may span several lines.
+func parseDotenv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validEnvName(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			// Collect lines until the closing quote
+			body := rest[1:]
+			for closingQuote(body) < 0 {
+				if i+1 >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated double-quoted value for %s", lineNo, key)
+				}
+				i++
+				body += "\n" + lines[i]
+			}
+			end := closingQuote(body)
+			if trailing := strings.TrimSpace(body[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+				return nil, fmt.Errorf("line %d: unexpected text after quoted value for %s", lineNo, key)
+			}
+			values[key] = unescape(body[:end])
+		case strings.HasPrefix(rest, "'"):
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single-quoted value for %s", lineNo, key)
+			}
+			values[key] = rest[1 : end+1]
+		default:
+			if idx := strings.Index(rest, " #"); idx >= 0 {
+				rest = rest[:idx]
+			}
+			values[key] = strings.TrimSpace(rest)
+		}
+	}
+	return values, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\', '$', '`':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		case r == '.' || r == '-':
+			// Tolerated by most loaders
+		default:
+			return false
+		}
+	}
+	return true
+}
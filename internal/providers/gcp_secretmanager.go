@@ -17,6 +17,7 @@ import (
 	"google.golang.org/api/impersonate"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 // GCPSecretManagerClientAPI defines the interface for GCP Secret Manager operations
@@ -25,8 +26,13 @@ type GCPSecretManagerClientAPI interface {
 	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
 	GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error)
 	ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...option.ClientOption) *secretmanager.SecretIterator
+	ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...option.ClientOption) *secretmanager.SecretVersionIterator
 	AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error)
 	DisableSecretVersion(ctx context.Context, req *secretmanagerpb.DisableSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error)
+	GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error)
+	CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error)
+	UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error)
+	DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest, opts ...option.ClientOption) error
 }
 
 // GCPSecretManagerProvider implements the Provider interface for Google Cloud Secret Manager
@@ -140,6 +146,10 @@ func (w *gcpClientWrapper) ListSecrets(ctx context.Context, req *secretmanagerpb
 	return w.client.ListSecrets(ctx, req)
 }
 
+func (w *gcpClientWrapper) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...option.ClientOption) *secretmanager.SecretVersionIterator {
+	return w.client.ListSecretVersions(ctx, req)
+}
+
 func (w *gcpClientWrapper) AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error) {
 	return w.client.AddSecretVersion(ctx, req)
 }
@@ -148,6 +158,22 @@ func (w *gcpClientWrapper) DisableSecretVersion(ctx context.Context, req *secret
 	return w.client.DisableSecretVersion(ctx, req)
 }
 
+func (w *gcpClientWrapper) GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error) {
+	return w.client.GetSecretVersion(ctx, req)
+}
+
+func (w *gcpClientWrapper) CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error) {
+	return w.client.CreateSecret(ctx, req)
+}
+
+func (w *gcpClientWrapper) UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error) {
+	return w.client.UpdateSecret(ctx, req)
+}
+
+func (w *gcpClientWrapper) DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest, opts ...option.ClientOption) error {
+	return w.client.DeleteSecret(ctx, req)
+}
+
 // createGCPSecretManagerClient creates a GCP Secret Manager client
 func createGCPSecretManagerClient(config GCPSecretManagerConfig) (*secretmanager.Client, error) {
 	ctx := context.Background()
@@ -221,10 +247,14 @@ func (p *GCPSecretManagerProvider) Resolve(ctx context.Context, ref provider.Ref
 
 	result, err := p.client.AccessSecretVersion(ctx, req)
 	if err != nil {
+		if isGCPNotFoundError(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
 		return provider.SecretValue{}, dserrors.UserError{
 			Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
 			Details:    err.Error(),
 			Suggestion: getGCPErrorSuggestion(err),
+			Err:        err,
 		}
 	}
 
@@ -360,7 +390,10 @@ func extractJSONPath(jsonStr, path string) (string, error) {
 
 // Describe returns metadata about a secret without fetching its value
 func (p *GCPSecretManagerProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
-	secretName, _, _ := p.parseReference(ref.Key)
+	secretName, version, _ := p.parseReference(ref.Key)
+	if ref.Version != "" {
+		version = ref.Version
+	}
 
 	// Build secret resource name (without version)
 	var resourceName string
@@ -402,6 +435,24 @@ func (p *GCPSecretManagerProvider) Describe(ctx context.Context, ref provider.Re
 		metadata.Tags["label."+key] = value
 	}
 
+	// A pinned version is deprecated once it is disabled or destroyed
+	if version != "latest" && !strings.Contains(secretName, "/versions/") {
+		sv, err := p.client.GetSecretVersion(ctx, &secretmanagerpb.GetSecretVersionRequest{
+			Name: resourceName + "/versions/" + version,
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), "NotFound") {
+				return provider.Metadata{Exists: false}, nil
+			}
+			return provider.Metadata{}, fmt.Errorf("failed to describe secret version: %w", err)
+		}
+		metadata.Version = version
+		metadata.Deprecated = sv.State != secretmanagerpb.SecretVersion_ENABLED
+		if sv.CreateTime != nil {
+			metadata.UpdatedAt = sv.CreateTime.AsTime()
+		}
+	}
+
 	return metadata, nil
 }
 
@@ -512,6 +563,34 @@ func (p *GCPSecretManagerProvider) DeprecateVersion(ctx context.Context, ref pro
 	return nil
 }
 
+// ListVersions returns the enabled versions of a secret
+func (p *GCPSecretManagerProvider) ListVersions(ctx context.Context, ref provider.Reference) ([]string, error) {
+	secretName, _, _ := p.parseReference(ref.Key)
+
+	it := p.client.ListSecretVersions(ctx, &secretmanagerpb.ListSecretVersionsRequest{
+		Parent: p.secretResourceName(secretName),
+		Filter: "state:ENABLED",
+	})
+
+	var versions []string
+	for {
+		version, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			if isGCPNotFoundError(err) {
+				return nil, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+			}
+			return nil, fmt.Errorf("failed to list secret versions: %w", err)
+		}
+		name := version.GetName()
+		versions = append(versions, name[strings.LastIndex(name, "/")+1:])
+	}
+
+	return versions, nil
+}
+
 // GetRotationMetadata returns metadata about rotation capabilities for a secret
 func (p *GCPSecretManagerProvider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
 	secretName, _, _ := p.parseReference(ref.Key)
@@ -559,6 +638,249 @@ func (p *GCPSecretManagerProvider) GetRotationMetadata(ctx context.Context, ref
 	return metadata, nil
 }
 
+// Write support implementation
+
+// PutSecret adds a new version to a secret in GCP Secret Manager, creating
+// the secret with automatic replication if it does not exist. Tags become
+// labels and the description is stored as an annotation.
+func (p *GCPSecretManagerProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	secretName, _, jsonPath := p.parseReference(ref.Key)
+	parent := p.secretResourceName(secretName)
+
+	existing, err := p.client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: parent})
+	if err != nil && !isGCPNotFoundError(err) {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to get secret: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getGCPErrorSuggestion(err),
+		}
+	}
+	exists := err == nil && existing != nil
+
+	if jsonPath != "" {
+		doc := ""
+		if exists {
+			current, err := p.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+				Name: p.buildResourceName(secretName, "latest"),
+			})
+			if err != nil && !isGCPNotFoundError(err) {
+				return "", dserrors.UserError{
+					Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
+					Details:    err.Error(),
+					Suggestion: getGCPErrorSuggestion(err),
+				}
+			}
+			if err == nil && current.Payload != nil {
+				doc = string(current.Payload.Data)
+			}
+		}
+
+		updated, err := setJSONField(doc, jsonPath, string(value))
+		if err != nil {
+			return "", fmt.Errorf("failed to update field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+		value = []byte(updated)
+	}
+
+	annotations := map[string]string{}
+	if opts.Description != "" {
+		annotations["description"] = opts.Description
+	}
+
+	if !exists {
+		_, err := p.client.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+			Parent:   fmt.Sprintf("projects/%s", p.projectID),
+			SecretId: secretName,
+			Secret: &secretmanagerpb.Secret{
+				Replication: &secretmanagerpb.Replication{
+					Replication: &secretmanagerpb.Replication_Automatic_{
+						Automatic: &secretmanagerpb.Replication_Automatic{},
+					},
+				},
+				Labels:      opts.Tags,
+				Annotations: annotations,
+			},
+		})
+		if err != nil {
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to create secret: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getGCPErrorSuggestion(err),
+			}
+		}
+	} else if len(opts.Tags) > 0 || len(annotations) > 0 {
+		secret := &secretmanagerpb.Secret{
+			Name:        parent,
+			Labels:      mergeStringMaps(existing.Labels, opts.Tags),
+			Annotations: mergeStringMaps(existing.Annotations, annotations),
+		}
+		_, err := p.client.UpdateSecret(ctx, &secretmanagerpb.UpdateSecretRequest{
+			Secret:     secret,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels", "annotations"}},
+		})
+		if err != nil {
+			return "", dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to update secret labels: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getGCPErrorSuggestion(err),
+			}
+		}
+	}
+
+	result, err := p.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+		Parent: parent,
+		Payload: &secretmanagerpb.SecretPayload{
+			Data: value,
+		},
+	})
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to add secret version: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getGCPErrorSuggestion(err),
+		}
+	}
+
+	// Format: projects/PROJECT/secrets/SECRET/versions/VERSION
+	parts := strings.Split(result.GetName(), "/")
+	if len(parts) >= 6 {
+		return parts[5], nil
+	}
+	return "latest", nil
+}
+
+// DeleteSecret deletes a secret and all its versions from GCP Secret Manager.
+// A key with a JSON path removes only that field by adding a new version.
+func (p *GCPSecretManagerProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	secretName, _, jsonPath := p.parseReference(ref.Key)
+	parent := p.secretResourceName(secretName)
+
+	if jsonPath != "" {
+		current, err := p.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+			Name: p.buildResourceName(secretName, "latest"),
+		})
+		if err != nil {
+			if isGCPNotFoundError(err) {
+				return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+			}
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to access secret: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getGCPErrorSuggestion(err),
+			}
+		}
+
+		doc := ""
+		if current.Payload != nil {
+			doc = string(current.Payload.Data)
+		}
+		updated, found, err := deleteJSONField(doc, jsonPath)
+		if err != nil {
+			return fmt.Errorf("failed to remove field '%s' of secret '%s': %w", jsonPath, secretName, err)
+		}
+		if !found {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+
+		if _, err := p.client.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+			Parent:  parent,
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte(updated)},
+		}); err != nil {
+			return dserrors.UserError{
+				Message:    fmt.Sprintf("Failed to add secret version: %s", secretName),
+				Details:    err.Error(),
+				Suggestion: getGCPErrorSuggestion(err),
+			}
+		}
+		return nil
+	}
+
+	if err := p.client.DeleteSecret(ctx, &secretmanagerpb.DeleteSecretRequest{Name: parent}); err != nil {
+		if isGCPNotFoundError(err) {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to delete secret: %s", secretName),
+			Details:    err.Error(),
+			Suggestion: getGCPErrorSuggestion(err),
+		}
+	}
+
+	return nil
+}
+
+// gcpListPageSize is used when the caller does not choose a page size
+const gcpListPageSize = 100
+
+// ListSecrets lists secrets in the project whose short names start with
+// opts.Prefix. Labels are returned as tags. The prefix is applied to each
+// page, so a page can hold fewer than opts.PageSize secrets.
+func (p *GCPSecretManagerProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = gcpListPageSize
+	}
+
+	iter := p.client.ListSecrets(ctx, &secretmanagerpb.ListSecretsRequest{
+		Parent: fmt.Sprintf("projects/%s", p.projectID),
+	})
+
+	var secrets []*secretmanagerpb.Secret
+	nextToken, err := iterator.NewPager(iter, pageSize, opts.PageToken).NextPage(&secrets)
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Failed to list secrets in GCP Secret Manager",
+			Details:    err.Error(),
+			Suggestion: getGCPErrorSuggestion(err),
+		}
+	}
+
+	result := provider.ListResult{NextPageToken: nextToken}
+	for _, secret := range secrets {
+		name := secret.GetName()
+		if idx := strings.LastIndex(name, "/"); idx != -1 {
+			name = name[idx+1:]
+		}
+		if !strings.HasPrefix(name, opts.Prefix) {
+			continue
+		}
+
+		info := provider.SecretInfo{
+			Key:         name,
+			Description: secret.GetAnnotations()["description"],
+			Tags:        secret.GetLabels(),
+		}
+		result.Secrets = append(result.Secrets, info)
+	}
+
+	return result, nil
+}
+
+// secretResourceName builds the resource name of a secret (without version)
+func (p *GCPSecretManagerProvider) secretResourceName(secretName string) string {
+	if strings.HasPrefix(secretName, "projects/") {
+		return secretName
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s", p.projectID, secretName)
+}
+
+// isGCPNotFoundError checks if the error is a GCP NotFound error
+func isGCPNotFoundError(err error) bool {
+	return strings.Contains(err.Error(), "NotFound")
+}
+
+// mergeStringMaps returns a copy of base with the entries of overrides applied
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	result := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		result[k] = v
+	}
+	return result
+}
+
 // NewGCPSecretManagerProviderFactory creates a GCP Secret Manager provider factory
 func NewGCPSecretManagerProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
 	return NewGCPSecretManagerProvider(name, config)
@@ -2,6 +2,7 @@ package providers_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/systmms/dsops/pkg/provider"
 	"github.com/systmms/dsops/tests/testutil"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // mockGCPClient implements providers.GCPSecretManagerClientAPI for testing
@@ -30,6 +33,10 @@ func (m *mockGCPClient) ListSecrets(ctx context.Context, req *secretmanagerpb.Li
 	return nil
 }
 
+func (m *mockGCPClient) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...option.ClientOption) *secretmanager.SecretVersionIterator {
+	return nil
+}
+
 func (m *mockGCPClient) AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error) {
 	return nil, nil
 }
@@ -38,6 +45,29 @@ func (m *mockGCPClient) DisableSecretVersion(ctx context.Context, req *secretman
 	return nil, nil
 }
 
+func (m *mockGCPClient) GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.SecretVersion, error) {
+	return nil, nil
+}
+
+func (m *mockGCPClient) CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error) {
+	return nil, nil
+}
+
+func (m *mockGCPClient) UpdateSecret(ctx context.Context, req *secretmanagerpb.UpdateSecretRequest, opts ...option.ClientOption) (*secretmanagerpb.Secret, error) {
+	return nil, nil
+}
+
+func (m *mockGCPClient) DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest, opts ...option.ClientOption) error {
+	return nil
+}
+
+// missingGCPClient reports every secret version as missing
+type missingGCPClient struct{ mockGCPClient }
+
+func (m *missingGCPClient) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...option.ClientOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions", req.Name)
+}
+
 func TestGCPSecretManagerProviderContract(t *testing.T) {
 	if _, exists := os.LookupEnv("DSOPS_TEST_GCP"); !exists {
 		t.Skip("Skipping GCP Secret Manager provider test. Set DSOPS_TEST_GCP=1 to run.")
@@ -102,3 +132,16 @@ func TestGCPSecretManagerKeyFormats(t *testing.T) {
 		assert.NotEmpty(t, key)
 	}
 }
+
+func TestGCPSecretManagerResolveMissingSecret(t *testing.T) {
+	t.Parallel()
+
+	p, err := providers.NewGCPSecretManagerProvider("gcp", map[string]interface{}{"project_id": "my-project"},
+		providers.WithGCPSecretManagerClient(&missingGCPClient{}))
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "missing-secret"})
+	var notFound *provider.NotFoundError
+	require.True(t, errors.As(err, &notFound), "got %v", err)
+	assert.Equal(t, "missing-secret", notFound.Key)
+}
@@ -127,6 +127,26 @@ func (p *InfisicalProvider) Describe(ctx context.Context, ref provider.Reference
 	}, nil
 }
 
+// ListSecrets lists secret names in the configured project and environment
+func (p *InfisicalProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return provider.ListResult{}, fmt.Errorf("failed to authenticate with infisical: %w", err)
+	}
+
+	names, err := p.client.ListSecrets(ctx, token)
+	if err != nil {
+		return provider.ListResult{}, fmt.Errorf("failed to list infisical secrets: %w", err)
+	}
+
+	secrets := make([]provider.SecretInfo, len(names))
+	for i, name := range names {
+		secrets[i] = provider.SecretInfo{Key: name}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
 // Capabilities returns the provider's supported features
 func (p *InfisicalProvider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
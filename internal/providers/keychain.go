@@ -5,59 +5,123 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
+	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/providers/contracts"
 	"github.com/systmms/dsops/pkg/provider"
 )
 
+// Keychain backends selectable with the backend store option
+const (
+	// KeychainBackendAuto uses the OS keychain when it can be reached
+	// without a GUI prompt and the encrypted file keyring otherwise
+	KeychainBackendAuto = "auto"
+	// KeychainBackendSystem is the macOS Keychain or Linux Secret Service
+	KeychainBackendSystem = "system"
+	// KeychainBackendFile is the age-encrypted file keyring
+	KeychainBackendFile = "file"
+)
+
 // KeychainProvider implements the provider interface for OS keychains
-// (macOS Keychain and Linux Secret Service)
+// (macOS Keychain and Linux Secret Service) and the encrypted file keyring
 type KeychainProvider struct {
 	name          string
 	servicePrefix string
 	accessGroup   string
+	backend       string
 	client        contracts.KeychainClient
 }
 
-// NewKeychainProvider creates a new keychain provider
+// NewKeychainProvider creates a new keychain provider. The backend store
+// option selects the OS keychain, the encrypted file keyring, or (by
+// default) whichever is usable in the current session.
 func NewKeychainProvider(name string, config map[string]interface{}) *KeychainProvider {
-	kc := &KeychainProvider{
-		name:   name,
-		client: newPlatformKeychainClient(),
-	}
-
-	if config != nil {
-		if prefix, ok := config["service_prefix"].(string); ok {
-			kc.servicePrefix = prefix
-		}
-		if accessGroup, ok := config["access_group"].(string); ok {
-			kc.accessGroup = accessGroup
-		}
+	cfg := parseKeychainConfig(config)
+	client, backend := selectKeychainClient(cfg, newPlatformKeychainClient())
+
+	return &KeychainProvider{
+		name:          name,
+		servicePrefix: cfg.ServicePrefix,
+		accessGroup:   cfg.AccessGroup,
+		backend:       backend,
+		client:        client,
 	}
-
-	return kc
 }
 
 // NewKeychainProviderWithClient creates a keychain provider with a custom client.
 // This is primarily for testing, allowing the keychain client to be mocked.
 func NewKeychainProviderWithClient(name string, config map[string]interface{}, client contracts.KeychainClient) *KeychainProvider {
-	kc := &KeychainProvider{
-		name:   name,
-		client: client,
+	cfg := parseKeychainConfig(config)
+
+	return &KeychainProvider{
+		name:          name,
+		servicePrefix: cfg.ServicePrefix,
+		accessGroup:   cfg.AccessGroup,
+		backend:       cfg.Backend,
+		client:        client,
 	}
+}
 
-	if config != nil {
-		if prefix, ok := config["service_prefix"].(string); ok {
-			kc.servicePrefix = prefix
-		}
-		if accessGroup, ok := config["access_group"].(string); ok {
-			kc.accessGroup = accessGroup
+// parseKeychainConfig reads the keychain store options
+func parseKeychainConfig(config map[string]interface{}) KeychainConfig {
+	var cfg KeychainConfig
+	if config == nil {
+		return cfg
+	}
+	if prefix, ok := config["service_prefix"].(string); ok {
+		cfg.ServicePrefix = prefix
+	}
+	if accessGroup, ok := config["access_group"].(string); ok {
+		cfg.AccessGroup = accessGroup
+	}
+	if backend, ok := config["backend"].(string); ok {
+		cfg.Backend = backend
+	}
+	if file, ok := config["file"].(string); ok {
+		cfg.File = file
+	}
+	if identityFile, ok := config["identity_file"].(string); ok {
+		cfg.IdentityFile = identityFile
+	}
+	if passphraseEnv, ok := config["passphrase_env"].(string); ok {
+		cfg.PassphraseEnv = passphraseEnv
+	}
+	return cfg
+}
+
+// validateKeychainConfig rejects unknown backends
+func validateKeychainConfig(cfg KeychainConfig) error {
+	switch cfg.Backend {
+	case "", KeychainBackendAuto, KeychainBackendSystem, KeychainBackendFile:
+		return nil
+	default:
+		return dserrors.ConfigError{
+			Field:      "backend",
+			Value:      cfg.Backend,
+			Message:    "unknown keychain backend",
+			Suggestion: "Use auto, system or file",
 		}
 	}
+}
+
+// selectKeychainClient returns the client for the configured backend and
+// the backend chosen. Auto prefers the OS keychain, falling back to the file
+// keyring when it is unavailable or would need a GUI prompt.
+func selectKeychainClient(cfg KeychainConfig, system contracts.KeychainClient) (contracts.KeychainClient, string) {
+	switch cfg.Backend {
+	case KeychainBackendSystem:
+		return system, KeychainBackendSystem
+	case KeychainBackendFile:
+		return newFileKeychainClient(cfg), KeychainBackendFile
+	}
 
-	return kc
+	if system.IsAvailable() && !system.IsHeadless() {
+		return system, KeychainBackendSystem
+	}
+	return newFileKeychainClient(cfg), KeychainBackendFile
 }
 
 // Name returns the provider name
@@ -65,6 +129,12 @@ func (kc *KeychainProvider) Name() string {
 	return kc.name
 }
 
+// Backend returns the backend in use: system or file. It is empty for a
+// provider created with a custom client.
+func (kc *KeychainProvider) Backend() string {
+	return kc.backend
+}
+
 // Platform returns the current platform (darwin, linux, or unsupported)
 func (kc *KeychainProvider) Platform() string {
 	return runtime.GOOS
@@ -110,11 +180,11 @@ func (kc *KeychainProvider) Resolve(ctx context.Context, ref provider.Reference)
 		Value:     string(value),
 		Version:   "", // Keychain doesn't support versioning
 		UpdatedAt: time.Time{},
-		Metadata: map[string]string{
+		Metadata: kc.itemTags(map[string]string{
 			"provider": kc.name,
 			"service":  service,
 			"account":  kcRef.Account,
-		},
+		}),
 	}, nil
 }
 
@@ -140,10 +210,10 @@ func (kc *KeychainProvider) Describe(ctx context.Context, ref provider.Reference
 		Exists:  true,
 		Version: "", // Keychain doesn't support versioning
 		Type:    "password",
-		Tags: map[string]string{
+		Tags: kc.itemTags(map[string]string{
 			"service": service,
 			"account": kcRef.Account,
-		},
+		}),
 	}, nil
 }
 
@@ -166,7 +236,7 @@ func (kc *KeychainProvider) Validate(ctx context.Context) error {
 	}
 
 	if kc.client.IsHeadless() {
-		return fmt.Errorf("keychain requires GUI environment (headless environment detected). Consider using a different secret provider for CI/CD environments")
+		return fmt.Errorf("keychain requires GUI environment (headless environment detected). Set backend: file on the store to use the encrypted file keychain instead")
 	}
 
 	if err := kc.client.Validate(); err != nil {
@@ -176,6 +246,102 @@ func (kc *KeychainProvider) Validate(ctx context.Context) error {
 	return nil
 }
 
+// PutSecret stores a secret in the OS keychain, replacing any existing value.
+// The keychain has no metadata, so description and tags are ignored.
+func (kc *KeychainProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	kcRef, err := ParseKeychainReference(ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("invalid keychain reference '%s': %w", ref.Key, err)
+	}
+
+	service := kc.applyServicePrefix(kcRef.Service)
+
+	if err := kc.client.Set(service, kcRef.Account, value); err != nil {
+		if isKeychainAccessDeniedError(err) {
+			err = ErrKeychainAccessDenied
+		}
+		return "", &KeychainError{
+			Op:      "set",
+			Service: service,
+			Account: kcRef.Account,
+			Err:     err,
+		}
+	}
+
+	return "", nil
+}
+
+// DeleteSecret removes a secret from the OS keychain
+func (kc *KeychainProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	kcRef, err := ParseKeychainReference(ref.Key)
+	if err != nil {
+		return fmt.Errorf("invalid keychain reference '%s': %w", ref.Key, err)
+	}
+
+	service := kc.applyServicePrefix(kcRef.Service)
+
+	if err := kc.client.Delete(service, kcRef.Account); err != nil {
+		if isKeychainNotFoundError(err) {
+			return provider.NotFoundError{
+				Provider: kc.name,
+				Key:      ref.Key,
+			}
+		}
+		if isKeychainAccessDeniedError(err) {
+			err = ErrKeychainAccessDenied
+		}
+		return &KeychainError{
+			Op:      "delete",
+			Service: service,
+			Account: kcRef.Account,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// ListSecrets lists the keychain items as service/account keys. Only the
+// file keyring can be listed; the OS keychains do not enumerate items.
+func (kc *KeychainProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	lister, ok := kc.client.(contracts.KeychainLister)
+	if !ok {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    fmt.Sprintf("Keychain store '%s' cannot list its items", kc.name),
+			Suggestion: "Only the encrypted file keychain (backend: file) supports listing",
+		}
+	}
+
+	items, err := lister.List()
+	if err != nil {
+		return provider.ListResult{}, &KeychainError{Op: "list", Err: err}
+	}
+
+	secrets := make([]provider.SecretInfo, 0, len(items))
+	for _, item := range items {
+		// Items outside the service prefix belong to other stores
+		if kc.servicePrefix != "" && !strings.HasPrefix(item.Service, kc.servicePrefix) {
+			continue
+		}
+		secrets = append(secrets, provider.SecretInfo{
+			Key:       item.Service + "/" + item.Account,
+			Type:      "password",
+			UpdatedAt: item.UpdatedAt,
+		})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Key < secrets[j].Key })
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// itemTags adds the backend to item metadata
+func (kc *KeychainProvider) itemTags(tags map[string]string) map[string]string {
+	if kc.backend != "" {
+		tags["backend"] = kc.backend
+	}
+	return tags
+}
+
 // applyServicePrefix combines the configured prefix with the service name
 func (kc *KeychainProvider) applyServicePrefix(service string) string {
 	if kc.servicePrefix == "" {
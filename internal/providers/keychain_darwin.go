@@ -35,6 +35,31 @@ func (c *darwinKeychainClient) Query(service, account string) ([]byte, error) {
 	return []byte(secret), nil
 }
 
+// Set stores a secret in the macOS keychain
+func (c *darwinKeychainClient) Set(service, account string, value []byte) error {
+	if err := keyring.Set(service, account, string(value)); err != nil {
+		if isAccessDenied(err) {
+			return ErrKeychainAccessDenied
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes a secret from the macOS keychain
+func (c *darwinKeychainClient) Delete(service, account string) error {
+	if err := keyring.Delete(service, account); err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return ErrKeychainItemNotFound
+		}
+		if isAccessDenied(err) {
+			return ErrKeychainAccessDenied
+		}
+		return err
+	}
+	return nil
+}
+
 // Validate checks if the keychain is accessible
 func (c *darwinKeychainClient) Validate() error {
 	// On macOS, keychain is always available if we're running on the platform
@@ -0,0 +1,449 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"golang.org/x/term"
+
+	"github.com/systmms/dsops/internal/atomicfile"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers/contracts"
+)
+
+// DefaultKeychainPassphraseEnv is the environment variable holding the
+// passphrase of the encrypted file keyring
+const DefaultKeychainPassphraseEnv = "DSOPS_KEYCHAIN_PASSPHRASE"
+
+// keychainFileVersion is the format version of the decrypted keyring
+const keychainFileVersion = 1
+
+// keychainFile is the decrypted content of the file keyring
+type keychainFile struct {
+	Version int                `json:"version"`
+	Items   []keychainFileItem `json:"items"`
+}
+
+// keychainFileItem is one entry of the file keyring
+type keychainFileItem struct {
+	Service   string    `json:"service"`
+	Account   string    `json:"account"`
+	Value     []byte    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// fileKeychainClient implements KeychainClient with an age-encrypted file.
+// It is the keychain for machines without a desktop session, such as SSH
+// dev boxes and CI runners.
+//
+// The file is encrypted either with a scrypt passphrase, read from an
+// environment variable or prompted for on a terminal, or with an age X25519
+// key file that is generated on first write.
+type fileKeychainClient struct {
+	path          string
+	identityFile  string
+	passphraseEnv string
+
+	// workFactor is the scrypt work factor for new encryptions; zero keeps
+	// the age default
+	workFactor int
+
+	// prompt reads a passphrase on the terminal; confirm asks twice
+	prompt func(path string, confirm bool) (string, error)
+
+	mu         sync.Mutex
+	passphrase string
+	items      []keychainFileItem
+	loadedMod  time.Time
+	loadedSize int64
+	loaded     bool
+}
+
+// newFileKeychainClient creates a file keyring client from store config
+func newFileKeychainClient(cfg KeychainConfig) *fileKeychainClient {
+	passphraseEnv := cfg.PassphraseEnv
+	if passphraseEnv == "" {
+		passphraseEnv = DefaultKeychainPassphraseEnv
+	}
+	path := expandKeychainPath(cfg.File)
+	if path == "" {
+		path = defaultKeychainFile()
+	}
+	return &fileKeychainClient{
+		path:          path,
+		identityFile:  expandKeychainPath(cfg.IdentityFile),
+		passphraseEnv: passphraseEnv,
+		prompt:        promptKeychainPassphrase,
+	}
+}
+
+// defaultKeychainFile returns the keyring path used when none is configured
+func defaultKeychainFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(".dsops", "keychain.age")
+	}
+	return filepath.Join(dir, "dsops", "keychain.age")
+}
+
+func expandKeychainPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// Query retrieves a secret from the file keyring
+func (c *fileKeychainClient) Query(service, account string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	for _, item := range c.items {
+		if item.Service == service && item.Account == account {
+			return item.Value, nil
+		}
+	}
+	return nil, ErrKeychainItemNotFound
+}
+
+// Set stores a secret in the file keyring, creating the file if needed
+func (c *fileKeychainClient) Set(service, account string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	items := make([]keychainFileItem, 0, len(c.items)+1)
+	for _, item := range c.items {
+		if item.Service != service || item.Account != account {
+			items = append(items, item)
+		}
+	}
+	items = append(items, keychainFileItem{
+		Service:   service,
+		Account:   account,
+		Value:     value,
+		UpdatedAt: time.Now().UTC(),
+	})
+
+	return c.save(items)
+}
+
+// Delete removes a secret from the file keyring
+func (c *fileKeychainClient) Delete(service, account string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	items := make([]keychainFileItem, 0, len(c.items))
+	for _, item := range c.items {
+		if item.Service != service || item.Account != account {
+			items = append(items, item)
+		}
+	}
+	if len(items) == len(c.items) {
+		return ErrKeychainItemNotFound
+	}
+
+	return c.save(items)
+}
+
+// List returns every item in the file keyring
+func (c *fileKeychainClient) List() ([]contracts.KeychainItem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	items := make([]contracts.KeychainItem, 0, len(c.items))
+	for _, item := range c.items {
+		items = append(items, contracts.KeychainItem{
+			Service:   item.Service,
+			Account:   item.Account,
+			UpdatedAt: item.UpdatedAt,
+		})
+	}
+	return items, nil
+}
+
+// Validate checks that an existing keyring can be decrypted
+func (c *fileKeychainClient) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load()
+}
+
+// IsAvailable returns true; the file keyring works everywhere
+func (c *fileKeychainClient) IsAvailable() bool {
+	return true
+}
+
+// IsHeadless returns false; the file keyring never needs a GUI
+func (c *fileKeychainClient) IsHeadless() bool {
+	return false
+}
+
+// load decrypts the keyring unless the cached copy is current. A missing
+// file is an empty keyring. The caller holds c.mu.
+func (c *fileKeychainClient) load() error {
+	info, err := os.Stat(c.path)
+	if os.IsNotExist(err) {
+		c.items, c.loaded = nil, false
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read keychain file: %w", err)
+	}
+	if c.loaded && info.ModTime().Equal(c.loadedMod) && info.Size() == c.loadedSize {
+		return nil
+	}
+
+	ciphertext, err := os.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to read keychain file: %w", err)
+	}
+
+	identity, err := c.identity(false)
+	if err != nil {
+		return err
+	}
+	reader, err := age.Decrypt(bytes.NewReader(ciphertext), identity)
+	if err != nil {
+		// A wrong passphrase must not be cached for the next attempt
+		c.passphrase = ""
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to decrypt keychain file %s", c.path),
+			Details:    err.Error(),
+			Suggestion: c.credentialSuggestion(),
+			Err:        ErrKeychainLocked,
+		}
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt keychain file: %w", err)
+	}
+
+	var file keychainFile
+	if err := json.Unmarshal(plaintext, &file); err != nil {
+		return fmt.Errorf("keychain file %s is corrupt: %w", c.path, err)
+	}
+	if file.Version > keychainFileVersion {
+		return fmt.Errorf("keychain file %s has format version %d; upgrade dsops to read it", c.path, file.Version)
+	}
+
+	c.items = file.Items
+	c.loadedMod, c.loadedSize, c.loaded = info.ModTime(), info.Size(), true
+	return nil
+}
+
+// save encrypts items and atomically replaces the keyring. The caller
+// holds c.mu.
+func (c *fileKeychainClient) save(items []keychainFileItem) error {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Service != items[j].Service {
+			return items[i].Service < items[j].Service
+		}
+		return items[i].Account < items[j].Account
+	})
+
+	plaintext, err := json.Marshal(keychainFile{Version: keychainFileVersion, Items: items})
+	if err != nil {
+		return fmt.Errorf("failed to encode keychain file: %w", err)
+	}
+
+	recipient, err := c.recipient()
+	if err != nil {
+		return err
+	}
+
+	var ciphertext bytes.Buffer
+	w, err := age.Encrypt(&ciphertext, recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keychain file: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return fmt.Errorf("failed to encrypt keychain file: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt keychain file: %w", err)
+	}
+
+	if err := atomicfile.Write(c.path, ciphertext.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write keychain file: %w", err)
+	}
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to read keychain file: %w", err)
+	}
+	c.items = items
+	c.loadedMod, c.loadedSize, c.loaded = info.ModTime(), info.Size(), true
+	return nil
+}
+
+// identity returns the age identity that decrypts the keyring
+func (c *fileKeychainClient) identity(create bool) (age.Identity, error) {
+	if c.identityFile != "" {
+		return c.keyFileIdentity(create)
+	}
+
+	passphrase, err := c.readPassphrase(create)
+	if err != nil {
+		return nil, err
+	}
+	return age.NewScryptIdentity(passphrase)
+}
+
+// recipient returns the age recipient the keyring is encrypted to
+func (c *fileKeychainClient) recipient() (age.Recipient, error) {
+	if c.identityFile != "" {
+		identity, err := c.keyFileIdentity(true)
+		if err != nil {
+			return nil, err
+		}
+		return identity.Recipient(), nil
+	}
+
+	// The first write of a new keyring confirms the passphrase
+	passphrase, err := c.readPassphrase(!c.loaded)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if c.workFactor > 0 {
+		recipient.SetWorkFactor(c.workFactor)
+	}
+	return recipient, nil
+}
+
+// keyFileIdentity reads the X25519 identity from identity_file, generating
+// it when create is set and the file does not exist
+func (c *fileKeychainClient) keyFileIdentity(create bool) (*age.X25519Identity, error) {
+	data, err := os.ReadFile(c.identityFile)
+	if os.IsNotExist(err) && create {
+		identity, err := age.GenerateX25519Identity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate keychain key: %w", err)
+		}
+		content := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+			time.Now().UTC().Format(time.RFC3339), identity.Recipient(), identity)
+		if err := atomicfile.Write(c.identityFile, []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write keychain key file: %w", err)
+		}
+		return identity, nil
+	}
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to read keychain key file %s", c.identityFile),
+			Details:    err.Error(),
+			Suggestion: "Restore the key file, or point identity_file at the age key the keyring was created with",
+		}
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keychain key file %s: %w", c.identityFile, err)
+	}
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			return x25519, nil
+		}
+	}
+	return nil, fmt.Errorf("keychain key file %s holds no X25519 identity", c.identityFile)
+}
+
+// readPassphrase returns the passphrase from the environment, the cache or
+// a terminal prompt
+func (c *fileKeychainClient) readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(c.passphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if c.passphrase != "" {
+		return c.passphrase, nil
+	}
+
+	passphrase, err := c.prompt(c.path, confirm)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", dserrors.UserError{
+			Message:    "Keychain passphrase is empty",
+			Suggestion: c.credentialSuggestion(),
+		}
+	}
+	c.passphrase = passphrase
+	return passphrase, nil
+}
+
+func (c *fileKeychainClient) credentialSuggestion() string {
+	if c.identityFile != "" {
+		return fmt.Sprintf("Check that %s is the key the keychain file was created with", c.identityFile)
+	}
+	return fmt.Sprintf("Set %s to the keychain passphrase, or run dsops in a terminal to enter it", c.passphraseEnv)
+}
+
+// promptKeychainPassphrase asks for the keyring passphrase on the terminal
+func promptKeychainPassphrase(path string, confirm bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("A passphrase is required for keychain file %s", path),
+			Suggestion: fmt.Sprintf("Set %s, set identity_file on the store, or run dsops in a terminal", DefaultKeychainPassphraseEnv),
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Passphrase for %s: ", path)
+	passphrase, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if !confirm {
+		return string(passphrase), nil
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+	again, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if !bytes.Equal(passphrase, again) {
+		return "", dserrors.UserError{
+			Message:    "Passphrases do not match",
+			Suggestion: "Run the command again and enter the same passphrase twice",
+		}
+	}
+	return string(passphrase), nil
+}
+
+// Ensure fileKeychainClient implements the keychain contracts
+var (
+	_ contracts.KeychainClient = (*fileKeychainClient)(nil)
+	_ contracts.KeychainLister = (*fileKeychainClient)(nil)
+)
@@ -0,0 +1,210 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+// newTestFileKeychain returns a file keyring in a temp dir that prompts with
+// the given passphrase and uses a cheap scrypt work factor
+func newTestFileKeychain(t *testing.T, path, passphrase string) *fileKeychainClient {
+	t.Helper()
+
+	c := newFileKeychainClient(KeychainConfig{File: path, PassphraseEnv: "DSOPS_TEST_KEYCHAIN_UNSET"})
+	c.workFactor = 10
+	c.prompt = func(string, bool) (string, error) { return passphrase, nil }
+	return c
+}
+
+func TestFileKeychainClient_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keychain.age")
+	c := newTestFileKeychain(t, path, "correct horse")
+
+	// A missing file is an empty keyring
+	require.NoError(t, c.Validate())
+	_, err := c.Query("myapp", "token")
+	assert.ErrorIs(t, err, ErrKeychainItemNotFound)
+
+	require.NoError(t, c.Set("myapp", "token", []byte("s3cret")))
+	require.NoError(t, c.Set("myapp", "db", []byte("hunter2")))
+	require.NoError(t, c.Set("myapp", "token", []byte("rotated")))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "rotated")
+
+	// A second client reads what the first wrote
+	other := newTestFileKeychain(t, path, "correct horse")
+	value, err := other.Query("myapp", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", string(value))
+
+	items, err := other.List()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "db", items[0].Account)
+	assert.Equal(t, "token", items[1].Account)
+	assert.False(t, items[1].UpdatedAt.IsZero())
+
+	require.NoError(t, other.Delete("myapp", "db"))
+	assert.ErrorIs(t, other.Delete("myapp", "db"), ErrKeychainItemNotFound)
+
+	// The first client notices the file changed
+	_, err = c.Query("myapp", "db")
+	assert.ErrorIs(t, err, ErrKeychainItemNotFound)
+}
+
+func TestFileKeychainClient_WrongPassphrase(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keychain.age")
+	require.NoError(t, newTestFileKeychain(t, path, "correct horse").Set("myapp", "token", []byte("s3cret")))
+
+	c := newTestFileKeychain(t, path, "battery staple")
+	_, err := c.Query("myapp", "token")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrKeychainLocked))
+	assert.Contains(t, err.Error(), "Failed to decrypt keychain file")
+	assert.Empty(t, c.passphrase, "a wrong passphrase is not cached")
+}
+
+func TestFileKeychainClient_PassphraseEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keychain.age")
+	t.Setenv("DSOPS_TEST_KEYCHAIN_PASSPHRASE", "from-env")
+
+	c := newFileKeychainClient(KeychainConfig{File: path, PassphraseEnv: "DSOPS_TEST_KEYCHAIN_PASSPHRASE"})
+	c.workFactor = 10
+	c.prompt = func(string, bool) (string, error) {
+		return "", errors.New("prompted despite the environment variable")
+	}
+	require.NoError(t, c.Set("ci", "deploy-key", []byte("value")))
+
+	value, err := newTestFileKeychain(t, path, "from-env").Query("ci", "deploy-key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(value))
+}
+
+func TestFileKeychainClient_IdentityFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := KeychainConfig{
+		File:         filepath.Join(dir, "keychain.age"),
+		IdentityFile: filepath.Join(dir, "keys", "keychain.key"),
+	}
+
+	c := newFileKeychainClient(cfg)
+	c.prompt = func(string, bool) (string, error) { return "", errors.New("identity files need no passphrase") }
+	require.NoError(t, c.Set("myapp", "token", []byte("s3cret")))
+
+	key, err := os.ReadFile(cfg.IdentityFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(key), "# public key: age1")
+	assert.Contains(t, string(key), "AGE-SECRET-KEY-")
+
+	value, err := newFileKeychainClient(cfg).Query("myapp", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(value))
+
+	// Without the key the keyring cannot be read
+	require.NoError(t, os.Remove(cfg.IdentityFile))
+	_, err = newFileKeychainClient(cfg).Query("myapp", "token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to read keychain key file")
+}
+
+func TestSelectKeychainClient(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		backend   string
+		available bool
+		headless  bool
+		want      string
+	}{
+		"auto with desktop session":   {backend: "", available: true, want: KeychainBackendSystem},
+		"auto headless":               {backend: KeychainBackendAuto, available: true, headless: true, want: KeychainBackendFile},
+		"auto unavailable":            {backend: KeychainBackendAuto, want: KeychainBackendFile},
+		"system forced when headless": {backend: KeychainBackendSystem, available: true, headless: true, want: KeychainBackendSystem},
+		"file forced":                 {backend: KeychainBackendFile, available: true, want: KeychainBackendFile},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			system := fakes.NewFakeKeychainClient()
+			system.Available, system.Headless = tt.available, tt.headless
+
+			client, backend := selectKeychainClient(KeychainConfig{Backend: tt.backend}, system)
+			assert.Equal(t, tt.want, backend)
+			if tt.want == KeychainBackendSystem {
+				assert.Same(t, system, client)
+			} else {
+				assert.IsType(t, &fileKeychainClient{}, client)
+			}
+		})
+	}
+}
+
+func TestValidateKeychainConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range []string{"", KeychainBackendAuto, KeychainBackendSystem, KeychainBackendFile} {
+		assert.NoError(t, validateKeychainConfig(KeychainConfig{Backend: backend}))
+	}
+
+	err := validateKeychainConfig(KeychainConfig{Backend: "kwallet"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown keychain backend")
+
+	_, err = NewKeychainProviderFactory("keychain", map[string]interface{}{"backend": "kwallet"})
+	assert.Error(t, err)
+}
+
+func TestKeychainProvider_FileBackend(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "keychain.age")
+	client := newTestFileKeychain(t, path, "correct horse")
+	p := NewKeychainProviderWithClient("keychain", map[string]interface{}{"backend": "file"}, client)
+	assert.Equal(t, KeychainBackendFile, p.Backend())
+
+	ctx := context.Background()
+	for _, key := range []string{"myapp/token", "myapp/db", "other/key"} {
+		_, err := p.PutSecret(ctx, provider.Reference{Provider: "keychain", Key: key}, []byte("v-"+key), provider.WriteOptions{})
+		require.NoError(t, err)
+	}
+
+	secret, err := p.Resolve(ctx, provider.Reference{Provider: "keychain", Key: "myapp/token"})
+	require.NoError(t, err)
+	assert.Equal(t, "v-myapp/token", secret.Value)
+	assert.Equal(t, KeychainBackendFile, secret.Metadata["backend"])
+
+	result, err := p.ListSecrets(ctx, provider.ListOptions{Prefix: "myapp/"})
+	require.NoError(t, err)
+	keys := make([]string, 0, len(result.Secrets))
+	for _, s := range result.Secrets {
+		keys = append(keys, s.Key)
+	}
+	assert.Equal(t, "myapp/db,myapp/token", strings.Join(keys, ","))
+
+	// The OS keychains cannot be listed
+	_, err = NewKeychainProviderWithClient("keychain", nil, fakes.NewFakeKeychainClient()).
+		ListSecrets(ctx, provider.ListOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot list its items")
+}
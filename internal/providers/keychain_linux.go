@@ -3,16 +3,32 @@
 package providers
 
 import (
+	"context"
 	"errors"
 	"os"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/godbus/dbus/v5"
 	"github.com/zalando/go-keyring"
 
 	"github.com/systmms/dsops/internal/providers/contracts"
 )
 
+// secretServiceName is the D-Bus name of the Secret Service
+const secretServiceName = "org.freedesktop.secrets"
+
+// secretServiceProbeTimeout bounds the D-Bus calls made to detect the
+// Secret Service, so a wedged session bus cannot stall dsops
+const secretServiceProbeTimeout = 2 * time.Second
+
 // linuxKeychainClient implements KeychainClient for Linux (Secret Service)
-type linuxKeychainClient struct{}
+type linuxKeychainClient struct {
+	probeOnce sync.Once
+	reachable bool
+	locked    bool
+}
 
 // newPlatformKeychainClient creates the platform-specific keychain client
 func newPlatformKeychainClient() contracts.KeychainClient {
@@ -31,6 +47,25 @@ func (c *linuxKeychainClient) Query(service, account string) ([]byte, error) {
 	return []byte(secret), nil
 }
 
+// Set stores a secret in Linux Secret Service
+func (c *linuxKeychainClient) Set(service, account string, value []byte) error {
+	if err := keyring.Set(service, account, string(value)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a secret from Linux Secret Service
+func (c *linuxKeychainClient) Delete(service, account string) error {
+	if err := keyring.Delete(service, account); err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return ErrKeychainItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
 // Validate checks if Secret Service is accessible
 func (c *linuxKeychainClient) Validate() error {
 	// On Linux, we need a Secret Service implementation running
@@ -38,30 +73,79 @@ func (c *linuxKeychainClient) Validate() error {
 	return nil
 }
 
-// IsAvailable returns true if Secret Service is available
+// IsAvailable returns true if a Secret Service answers on the session
+// D-Bus. A display is not required: SSH sessions with a running, unlocked
+// gnome-keyring-daemon or KeePassXC work too.
 func (c *linuxKeychainClient) IsAvailable() bool {
-	// Check if DISPLAY is set (X11) or WAYLAND_DISPLAY is set
-	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+	c.probe()
+	return c.reachable
+}
+
+// IsHeadless returns true if the keyring would have to show an unlock
+// prompt and there is no display to show it on
+func (c *linuxKeychainClient) IsHeadless() bool {
+	if os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != "" {
 		return false
 	}
-	return true
+	c.probe()
+	return !c.reachable || c.locked
 }
 
-// IsHeadless returns true if running in headless environment
-func (c *linuxKeychainClient) IsHeadless() bool {
-	// Check for SSH session
-	if os.Getenv("SSH_TTY") != "" {
-		return true
+// probe checks once whether the Secret Service is reachable over the
+// session D-Bus and whether its login collection is locked
+func (c *linuxKeychainClient) probe() {
+	c.probeOnce.Do(func() {
+		c.reachable, c.locked = probeSecretService()
+	})
+}
+
+// probeSecretService connects to the session bus without autolaunching one
+// and asks for the Secret Service, which may be running or D-Bus activatable
+func probeSecretService() (reachable, locked bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretServiceProbeTimeout)
+	defer cancel()
+
+	conn, err := dbus.SessionBusPrivateNoAutoStartup(dbus.WithContext(ctx))
+	if err != nil {
+		return false, false
 	}
-	// Check if no display is available
-	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
-		return true
+	defer func() { _ = conn.Close() }()
+	if err := conn.Auth(nil); err != nil {
+		return false, false
 	}
-	// Check for CI environments
-	if os.Getenv("CI") != "" {
-		return true
+	if err := conn.Hello(); err != nil {
+		return false, false
+	}
+
+	bus := conn.BusObject()
+	var owned bool
+	if err := bus.CallWithContext(ctx, "org.freedesktop.DBus.NameHasOwner", 0, secretServiceName).Store(&owned); err != nil {
+		return false, false
 	}
-	return false
+	if !owned {
+		var activatable []string
+		if err := bus.CallWithContext(ctx, "org.freedesktop.DBus.ListActivatableNames", 0).Store(&activatable); err != nil {
+			return false, false
+		}
+		if !slices.Contains(activatable, secretServiceName) {
+			return false, false
+		}
+	}
+
+	// go-keyring uses the login collection, or the default alias without one
+	for _, path := range []dbus.ObjectPath{"/org/freedesktop/secrets/collection/login", "/org/freedesktop/secrets/aliases/default"} {
+		var value dbus.Variant
+		err := conn.Object(secretServiceName, path).CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0,
+			"org.freedesktop.Secret.Collection", "Locked").Store(&value)
+		if err != nil {
+			continue
+		}
+		isLocked, _ := value.Value().(bool)
+		return true, isLocked
+	}
+
+	// No collection yet; creating one needs a prompt
+	return true, true
 }
 
 // Ensure linuxKeychainClient implements contracts.KeychainClient
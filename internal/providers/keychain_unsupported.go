@@ -19,6 +19,16 @@ func (c *unsupportedKeychainClient) Query(service, account string) ([]byte, erro
 	return nil, ErrKeychainUnsupportedPlatform
 }
 
+// Set returns an error on unsupported platforms
+func (c *unsupportedKeychainClient) Set(service, account string, value []byte) error {
+	return ErrKeychainUnsupportedPlatform
+}
+
+// Delete returns an error on unsupported platforms
+func (c *unsupportedKeychainClient) Delete(service, account string) error {
+	return ErrKeychainUnsupportedPlatform
+}
+
 // Validate returns an error on unsupported platforms
 func (c *unsupportedKeychainClient) Validate() error {
 	return ErrKeychainUnsupportedPlatform
@@ -0,0 +1,382 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTimeout bounds each API request
+const DefaultTimeout = 30 * time.Second
+
+// fieldManager identifies dsops in server-side apply managed fields
+const fieldManager = "dsops"
+
+// Client is a minimal Kubernetes API client for Secrets and server-side
+// apply. It speaks plain HTTPS so dsops does not depend on client-go.
+type Client struct {
+	config *restConfig
+	http   *http.Client
+
+	mu          sync.Mutex
+	execToken   string
+	execExpiry  time.Time
+	fileToken   string
+	fileTokenAt time.Time
+}
+
+// Secret is a v1 Secret. Data values are decoded from base64.
+type Secret struct {
+	Metadata ObjectMeta        `json:"metadata"`
+	Type     string            `json:"type"`
+	Data     map[string][]byte `json:"data"`
+}
+
+// ObjectMeta is the part of object metadata dsops uses
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	ResourceVersion   string            `json:"resourceVersion"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+	Annotations       map[string]string `json:"annotations"`
+	ManagedFields     []struct {
+		Time *time.Time `json:"time"`
+	} `json:"managedFields"`
+}
+
+// UpdatedAt is the last time any field manager changed the object
+func (m ObjectMeta) UpdatedAt() time.Time {
+	updated := m.CreationTimestamp
+	for _, f := range m.ManagedFields {
+		if f.Time != nil && f.Time.After(updated) {
+			updated = *f.Time
+		}
+	}
+	return updated
+}
+
+// StatusError is an error response from the API server
+type StatusError struct {
+	Code    int
+	Reason  string
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("kubernetes API returned %d %s: %s", e.Code, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("kubernetes API returned %d", e.Code)
+}
+
+// IsNotFound reports whether err is a 404 from the API server
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
+
+// NewClient connects to the cluster selected by opts
+func NewClient(opts ConnectionOptions) (*Client, error) {
+	config, err := loadConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(config)
+}
+
+func newClient(config *restConfig) (*Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.Insecure, // #nosec G402 -- only when the kubeconfig asks for it
+	}
+	if len(config.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.CAData) {
+			return nil, fmt.Errorf("invalid certificate authority data in %s", config.Source)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(config.CertData) > 0 || len(config.KeyData) > 0 {
+		cert, err := tls.X509KeyPair(config.CertData, config.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate in %s: %w", config.Source, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &Client{
+		config: config,
+		http: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Namespace is the namespace of the selected context
+func (c *Client) Namespace() string {
+	return c.config.Namespace
+}
+
+// Host is the API server URL
+func (c *Client) Host() string {
+	return c.config.Host
+}
+
+// GetSecret reads one Secret
+func (c *Client) GetSecret(ctx context.Context, namespace, name string) (*Secret, error) {
+	body, err := c.Get(ctx, secretsPath(namespace)+"/"+url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	var secret Secret
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode secret %s/%s: %w", namespace, name, err)
+	}
+	return &secret, nil
+}
+
+// ListSecrets reads every Secret in a namespace, following continue tokens
+func (c *Client) ListSecrets(ctx context.Context, namespace string) ([]Secret, error) {
+	var (
+		secrets []Secret
+		next    string
+	)
+	for {
+		query := url.Values{"limit": {"500"}}
+		if next != "" {
+			query.Set("continue", next)
+		}
+		body, err := c.Get(ctx, secretsPath(namespace)+"?"+query.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var list struct {
+			Metadata struct {
+				Continue string `json:"continue"`
+			} `json:"metadata"`
+			Items []Secret `json:"items"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("failed to decode secret list: %w", err)
+		}
+		secrets = append(secrets, list.Items...)
+		if list.Metadata.Continue == "" {
+			return secrets, nil
+		}
+		next = list.Metadata.Continue
+	}
+}
+
+// Apply creates or updates an object with server-side apply. The manifest
+// is YAML or JSON for a single namespaced object.
+func (c *Client) Apply(ctx context.Context, manifest []byte) error {
+	var object struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal(manifest, &object); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+	if object.APIVersion == "" || object.Kind == "" || object.Metadata.Name == "" {
+		return fmt.Errorf("manifest needs apiVersion, kind and metadata.name")
+	}
+	namespace := object.Metadata.Namespace
+	if namespace == "" {
+		namespace = c.config.Namespace
+	}
+
+	prefix := "/apis/" + object.APIVersion
+	if object.APIVersion == "v1" {
+		prefix = "/api/v1"
+	}
+	path := fmt.Sprintf("%s/namespaces/%s/%s/%s?%s", prefix, url.PathEscape(namespace),
+		strings.ToLower(object.Kind)+"s", url.PathEscape(object.Metadata.Name),
+		url.Values{"fieldManager": {fieldManager}, "force": {"true"}}.Encode())
+
+	_, err := c.do(ctx, http.MethodPatch, path, "application/apply-patch+yaml", manifest)
+	return err
+}
+
+// Get performs a GET against an API path and returns the body
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, path, "", nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.config.Host, "/")+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "dsops")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := c.authorize(ctx, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", c.config.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		statusErr := &StatusError{Code: resp.StatusCode}
+		var status struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &status) == nil {
+			statusErr.Reason, statusErr.Message = status.Reason, status.Message
+		}
+		if statusErr.Reason == "" {
+			statusErr.Reason = http.StatusText(resp.StatusCode)
+		}
+		// A 401 may mean an exec token expired early; fetch a new one next time
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.mu.Lock()
+			c.execToken = ""
+			c.mu.Unlock()
+		}
+		return nil, statusErr
+	}
+	return respBody, nil
+}
+
+// authorize adds the configured credentials to a request
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	token := c.config.Token
+	switch {
+	case c.config.Exec != nil:
+		var err error
+		if token, err = c.execCredential(ctx); err != nil {
+			return err
+		}
+	case c.config.TokenFile != "":
+		var err error
+		if token, err = c.tokenFromFile(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.config.Username != "":
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	return nil
+}
+
+// tokenFromFile re-reads a token file every minute, since projected service
+// account tokens are rotated in place
+func (c *Client) tokenFromFile() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fileToken != "" && time.Since(c.fileTokenAt) < time.Minute {
+		return c.fileToken, nil
+	}
+	data, err := os.ReadFile(c.config.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	c.fileToken = strings.TrimSpace(string(data))
+	c.fileTokenAt = time.Now()
+	return c.fileToken, nil
+}
+
+// execCredential runs the kubeconfig's exec plugin and caches its token
+// until shortly before it expires
+func (c *Client) execCredential(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.execToken != "" && (c.execExpiry.IsZero() || time.Until(c.execExpiry) > 30*time.Second) {
+		return c.execToken, nil
+	}
+
+	plugin := c.config.Exec
+	command := plugin.Command
+	if strings.Contains(command, string(filepath.Separator)) && !filepath.IsAbs(command) {
+		command = filepath.Join(plugin.Dir, command)
+	}
+
+	apiVersion := plugin.APIVersion
+	if apiVersion == "" {
+		apiVersion = "client.authentication.k8s.io/v1"
+	}
+	execInfo, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]interface{}{"interactive": false},
+	})
+
+	cmd := exec.CommandContext(ctx, command, plugin.Args...) // #nosec G204 -- command comes from the user's kubeconfig
+	cmd.Env = append(os.Environ(), "KUBERNETES_EXEC_INFO="+string(execInfo))
+	for _, env := range plugin.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("credential plugin %s failed: %w: %s", plugin.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var credential struct {
+		Status struct {
+			Token               string     `json:"token"`
+			ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(output, &credential); err != nil {
+		return "", fmt.Errorf("credential plugin %s returned invalid output: %w", plugin.Command, err)
+	}
+	if credential.Status.Token == "" {
+		return "", fmt.Errorf("credential plugin %s returned no token; client certificate plugins are not supported", plugin.Command)
+	}
+
+	c.execToken = credential.Status.Token
+	c.execExpiry = time.Time{}
+	if credential.Status.ExpirationTimestamp != nil {
+		c.execExpiry = *credential.Status.ExpirationTimestamp
+	}
+	return c.execToken, nil
+}
+
+func secretsPath(namespace string) string {
+	return "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets"
+}
@@ -0,0 +1,326 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serviceAccountDir holds the credentials Kubernetes mounts into pods
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// ConnectionOptions selects how to reach the API server
+type ConnectionOptions struct {
+	Kubeconfig string // Path to a kubeconfig file (default: $KUBECONFIG, then ~/.kube/config)
+	Context    string // Context to use (default: current-context)
+	InCluster  bool   // Use the pod's service account even if a kubeconfig exists
+}
+
+// restConfig is the resolved connection to one API server
+type restConfig struct {
+	Host       string
+	Namespace  string
+	ServerName string
+	Insecure   bool
+	CAData     []byte
+	CertData   []byte
+	KeyData    []byte
+	Token      string
+	TokenFile  string
+	Username   string
+	Password   string
+	Exec       *execConfig
+	Source     string // Where the configuration came from, for error messages
+}
+
+// execConfig runs a credential plugin such as aws-iam-authenticator or
+// gke-gcloud-auth-plugin
+type execConfig struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	Env        []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+	Dir string `yaml:"-"` // Directory of the kubeconfig, for relative commands
+}
+
+// kubeconfig is the subset of the kubeconfig format dsops understands
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			TLSServerName            string `yaml:"tls-server-name"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string      `yaml:"token"`
+			TokenFile             string      `yaml:"tokenFile"`
+			ClientCertificate     string      `yaml:"client-certificate"`
+			ClientCertificateData string      `yaml:"client-certificate-data"`
+			ClientKey             string      `yaml:"client-key"`
+			ClientKeyData         string      `yaml:"client-key-data"`
+			Username              string      `yaml:"username"`
+			Password              string      `yaml:"password"`
+			Exec                  *execConfig `yaml:"exec"`
+			AuthProvider          interface{} `yaml:"auth-provider"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+
+	dir string
+}
+
+// loadConfig resolves the connection the same way kubectl does: an explicit
+// kubeconfig, then $KUBECONFIG, then ~/.kube/config, and finally the pod's
+// service account when running inside a cluster.
+func loadConfig(opts ConnectionOptions) (*restConfig, error) {
+	if opts.InCluster {
+		return inClusterConfig()
+	}
+
+	var paths []string
+	switch {
+	case opts.Kubeconfig != "":
+		paths = []string{expandHome(opts.Kubeconfig)}
+	case os.Getenv("KUBECONFIG") != "":
+		for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+	default:
+		if home, err := os.UserHomeDir(); err == nil {
+			path := filepath.Join(home, ".kube", "config")
+			if _, err := os.Stat(path); err == nil {
+				paths = []string{path}
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+			return inClusterConfig()
+		}
+		return nil, fmt.Errorf("no kubeconfig found and not running in a cluster")
+	}
+
+	var files []*kubeconfig
+	for _, path := range paths {
+		kc, err := readKubeconfig(path)
+		if err != nil {
+			// KUBECONFIG may list files that do not exist yet
+			if errors.Is(err, os.ErrNotExist) && opts.Kubeconfig == "" {
+				continue
+			}
+			return nil, err
+		}
+		files = append(files, kc)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("none of the kubeconfig files in KUBECONFIG exist")
+	}
+	return resolveContext(files, opts.Context, strings.Join(paths, string(filepath.ListSeparator)))
+}
+
+func readKubeconfig(path string) (*kubeconfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig %s: %w", path, err)
+	}
+	kc.dir = filepath.Dir(path)
+	return &kc, nil
+}
+
+// resolveContext picks the context and merges its cluster and user. As with
+// kubectl, the first file to define a name wins.
+func resolveContext(files []*kubeconfig, contextName, source string) (*restConfig, error) {
+	if contextName == "" {
+		for _, kc := range files {
+			if kc.CurrentContext != "" {
+				contextName = kc.CurrentContext
+				break
+			}
+		}
+	}
+	if contextName == "" {
+		return nil, fmt.Errorf("no current-context set in %s; pass a context explicitly", source)
+	}
+
+	config := &restConfig{Source: fmt.Sprintf("context %q in %s", contextName, source)}
+
+	var clusterName, userName string
+	found := false
+	for _, kc := range files {
+		for _, c := range kc.Contexts {
+			if c.Name == contextName {
+				clusterName, userName, config.Namespace = c.Context.Cluster, c.Context.User, c.Context.Namespace
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("context %q not found in %s", contextName, source)
+	}
+
+	found = false
+	for _, kc := range files {
+		for _, c := range kc.Clusters {
+			if c.Name != clusterName {
+				continue
+			}
+			config.Host = c.Cluster.Server
+			config.ServerName = c.Cluster.TLSServerName
+			config.Insecure = c.Cluster.InsecureSkipTLSVerify
+			ca, err := inlineOrFile(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority, kc.dir)
+			if err != nil {
+				return nil, fmt.Errorf("cluster %q certificate-authority: %w", clusterName, err)
+			}
+			config.CAData = ca
+			found = true
+			break
+		}
+		if found {
+			break
+		}
+	}
+	if !found || config.Host == "" {
+		return nil, fmt.Errorf("cluster %q for context %q has no server", clusterName, contextName)
+	}
+
+	for _, kc := range files {
+		found = false
+		for _, u := range kc.Users {
+			if u.Name != userName {
+				continue
+			}
+			user := u.User
+			if user.AuthProvider != nil {
+				return nil, fmt.Errorf("user %q uses a legacy auth-provider; switch it to an exec credential plugin", userName)
+			}
+			cert, err := inlineOrFile(user.ClientCertificateData, user.ClientCertificate, kc.dir)
+			if err != nil {
+				return nil, fmt.Errorf("user %q client-certificate: %w", userName, err)
+			}
+			key, err := inlineOrFile(user.ClientKeyData, user.ClientKey, kc.dir)
+			if err != nil {
+				return nil, fmt.Errorf("user %q client-key: %w", userName, err)
+			}
+			config.CertData, config.KeyData = cert, key
+			config.Token = user.Token
+			config.TokenFile = resolvePath(user.TokenFile, kc.dir)
+			config.Username, config.Password = user.Username, user.Password
+			if user.Exec != nil {
+				config.Exec = user.Exec
+				config.Exec.Dir = kc.dir
+			}
+			found = true
+			break
+		}
+		if found {
+			break
+		}
+	}
+
+	if config.Namespace == "" {
+		config.Namespace = "default"
+	}
+	return config, nil
+}
+
+// inClusterConfig uses the service account Kubernetes mounts into pods
+func inClusterConfig() (*restConfig, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+
+	tokenFile := filepath.Join(serviceAccountDir, "token")
+	if _, err := os.Stat(tokenFile); err != nil {
+		return nil, fmt.Errorf("service account token not found: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("service account CA not found: %w", err)
+	}
+
+	namespace := "default"
+	if data, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace")); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			namespace = ns
+		}
+	}
+
+	return &restConfig{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		Namespace: namespace,
+		CAData:    ca,
+		TokenFile: tokenFile,
+		Source:    "in-cluster service account",
+	}, nil
+}
+
+// inlineOrFile returns base64 inline data, or the contents of a file
+// relative to the kubeconfig
+func inlineOrFile(data, path, dir string) ([]byte, error) {
+	if data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return decoded, nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(resolvePath(path, dir))
+}
+
+func resolvePath(path, dir string) string {
+	if path == "" {
+		return ""
+	}
+	path = expandHome(path)
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
@@ -0,0 +1,324 @@
+// Package kubernetes implements a secret store for Kubernetes Secrets and the
+// small API client dsops uses to read and apply them.
+//
+// Secrets are addressed as "namespace/name#key"; without a namespace the
+// store's namespace is used, and without a key the whole Secret is returned
+// as a JSON object. The connection comes from a kubeconfig (including exec
+// credential plugins) or, inside a pod, from its service account.
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Config holds the Kubernetes store configuration
+type Config struct {
+	Kubeconfig string `yaml:"kubeconfig"` // Path to a kubeconfig (default: $KUBECONFIG, then ~/.kube/config)
+	Context    string `yaml:"context"`    // kubeconfig context (default: current-context)
+	Namespace  string `yaml:"namespace"`  // Namespace for keys without one (default: the context's)
+	InCluster  bool   `yaml:"in_cluster"` // Always use the pod's service account
+}
+
+// Provider reads Secrets from a Kubernetes cluster
+type Provider struct {
+	name   string
+	config Config
+	logger *logging.Logger
+
+	mu     sync.Mutex
+	client *Client
+}
+
+// NewProvider creates a Kubernetes store. The cluster is contacted on first
+// use, so a missing kubeconfig only matters when the store is used.
+func NewProvider(name string, configMap map[string]interface{}) (*Provider, error) {
+	var config Config
+	if kubeconfig, ok := configMap["kubeconfig"].(string); ok {
+		config.Kubeconfig = kubeconfig
+	}
+	if kubeContext, ok := configMap["context"].(string); ok {
+		config.Context = kubeContext
+	}
+	if namespace, ok := configMap["namespace"].(string); ok {
+		config.Namespace = namespace
+	}
+	if inCluster, ok := configMap["in_cluster"].(bool); ok {
+		config.InCluster = inCluster
+	}
+
+	if config.InCluster && (config.Kubeconfig != "" || config.Context != "") {
+		return nil, dserrors.ConfigError{
+			Field:      "in_cluster",
+			Message:    "in_cluster cannot be combined with kubeconfig or context",
+			Suggestion: "Remove in_cluster to use a kubeconfig, or remove kubeconfig and context",
+		}
+	}
+
+	return &Provider{
+		name:   name,
+		config: config,
+		logger: logging.New(false, false),
+	}, nil
+}
+
+// NewProviderWithClient creates a Kubernetes store with a given client (for testing)
+func NewProviderWithClient(name string, configMap map[string]interface{}, client *Client) (*Provider, error) {
+	p, err := NewProvider(name, configMap)
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the provider's capabilities
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false, // resourceVersion is reported but old versions cannot be read
+		SupportsMetadata:   true,
+		SupportsWatching:   false,
+		SupportsBinary:     true,
+		RequiresAuth:       true,
+		AuthMethods:        []string{"kubeconfig", "exec", "in-cluster"},
+	}
+}
+
+// Validate checks that the API server is reachable with the configured
+// credentials and that Secrets in the namespace can be listed
+func (p *Provider) Validate(ctx context.Context) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	namespace := p.namespace(client)
+	if _, err := client.Get(ctx, secretsPath(namespace)+"?limit=1"); err != nil {
+		return p.apiError(err, fmt.Sprintf("Cannot list secrets in namespace %s", namespace))
+	}
+	return nil
+}
+
+// Resolve reads a Secret key, or the whole Secret as JSON
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+	namespace, name, key, err := p.parseKey(client, ref)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	secret, err := client.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return provider.SecretValue{}, p.apiError(err, fmt.Sprintf("Failed to read secret %s/%s", namespace, name))
+	}
+
+	var value string
+	if key == "" {
+		data := make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+		out, err := json.Marshal(data)
+		if err != nil {
+			return provider.SecretValue{}, fmt.Errorf("failed to marshal secret data: %w", err)
+		}
+		value = string(out)
+	} else {
+		raw, ok := secret.Data[key]
+		if !ok {
+			return provider.SecretValue{}, dserrors.UserError{
+				Message:    fmt.Sprintf("Key '%s' not found in secret %s/%s", key, namespace, name),
+				Suggestion: fmt.Sprintf("Available keys: %s", strings.Join(sortedKeys(secret.Data), ", ")),
+			}
+		}
+		value = string(raw)
+	}
+
+	return provider.SecretValue{
+		Value:     value,
+		Version:   secret.Metadata.ResourceVersion,
+		UpdatedAt: secret.Metadata.UpdatedAt(),
+		Metadata: map[string]string{
+			"provider":  p.name,
+			"namespace": namespace,
+			"name":      name,
+			"type":      secret.Type,
+		},
+	}, nil
+}
+
+// Describe returns Secret metadata without returning its value
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	namespace, name, key, err := p.parseKey(client, ref)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	secret, err := client.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if IsNotFound(err) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, p.apiError(err, fmt.Sprintf("Failed to read secret %s/%s", namespace, name))
+	}
+
+	size := 0
+	if key != "" {
+		raw, ok := secret.Data[key]
+		if !ok {
+			return provider.Metadata{Exists: false}, nil
+		}
+		size = len(raw)
+	} else {
+		for _, v := range secret.Data {
+			size += len(v)
+		}
+	}
+
+	return provider.Metadata{
+		Exists:      true,
+		Version:     secret.Metadata.ResourceVersion,
+		UpdatedAt:   secret.Metadata.UpdatedAt(),
+		Size:        size,
+		Type:        secret.Type,
+		Permissions: []string{"read"},
+		Tags:        secret.Metadata.Labels,
+	}, nil
+}
+
+// ListSecrets lists "namespace/name#key" for every key of every Secret in a
+// namespace. The prefix selects the namespace ("team-a/"); without one the
+// store's namespace is listed.
+func (p *Provider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	namespace := p.namespace(client)
+	if ns, _, ok := strings.Cut(opts.Prefix, "/"); ok && ns != "" {
+		namespace = ns
+	} else if opts.Prefix != "" {
+		opts.Prefix = namespace + "/" + opts.Prefix
+	}
+
+	items, err := client.ListSecrets(ctx, namespace)
+	if err != nil {
+		return provider.ListResult{}, p.apiError(err, fmt.Sprintf("Failed to list secrets in namespace %s", namespace))
+	}
+
+	var secrets []provider.SecretInfo
+	for _, item := range items {
+		for _, key := range sortedKeys(item.Data) {
+			secrets = append(secrets, provider.SecretInfo{
+				Key:       fmt.Sprintf("%s/%s#%s", namespace, item.Metadata.Name, key),
+				Type:      item.Type,
+				Version:   item.Metadata.ResourceVersion,
+				UpdatedAt: item.Metadata.UpdatedAt(),
+			})
+		}
+	}
+	return provider.PageSecrets(secrets, opts)
+}
+
+func (p *Provider) getClient() (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return p.client, nil
+	}
+	client, err := NewClient(ConnectionOptions{
+		Kubeconfig: p.config.Kubeconfig,
+		Context:    p.config.Context,
+		InCluster:  p.config.InCluster,
+	})
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    "Failed to load Kubernetes configuration",
+			Details:    err.Error(),
+			Suggestion: "Check your kubeconfig with 'kubectl config view', or set kubeconfig and context on the store",
+		}
+	}
+	p.client = client
+	return client, nil
+}
+
+func (p *Provider) namespace(client *Client) string {
+	if p.config.Namespace != "" {
+		return p.config.Namespace
+	}
+	return client.Namespace()
+}
+
+// parseKey splits "namespace/name#key" (or "name#key")
+func (p *Provider) parseKey(client *Client, ref provider.Reference) (namespace, name, key string, err error) {
+	path, key, _ := strings.Cut(ref.Key, "#")
+	if key == "" {
+		key = ref.Field
+	}
+
+	namespace = p.namespace(client)
+	name = path
+	if ns, rest, ok := strings.Cut(path, "/"); ok {
+		namespace, name = ns, rest
+	}
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", "", dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid Kubernetes secret reference: %s", ref.Key),
+			Suggestion: "Use 'namespace/name#key' or 'name#key', e.g. 'prod/db-credentials#password'",
+		}
+	}
+	return namespace, name, key, nil
+}
+
+func (p *Provider) apiError(err error, message string) error {
+	userErr := dserrors.UserError{Message: message, Details: err.Error()}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Code {
+		case http.StatusUnauthorized:
+			userErr.Suggestion = "Your Kubernetes credentials were rejected; log in again or refresh the kubeconfig"
+		case http.StatusForbidden:
+			userErr.Suggestion = "Grant get and list on secrets to your user or service account with a Role and RoleBinding"
+		}
+	}
+	if userErr.Suggestion == "" {
+		userErr.Suggestion = "Check that the cluster is reachable with 'kubectl get secrets'"
+	}
+	return userErr
+}
+
+func sortedKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
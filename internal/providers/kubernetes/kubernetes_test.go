@@ -0,0 +1,395 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// fakeAPIServer is an httptest stand-in for the parts of the Kubernetes API
+// dsops uses. Secrets are keyed by "namespace/name".
+type fakeAPIServer struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	secrets  map[string]map[string]string
+	auth     []string
+	applied  map[string]string
+	pageSize int
+}
+
+func newFakeAPIServer(t *testing.T) (*fakeAPIServer, *httptest.Server) {
+	f := &fakeAPIServer{
+		t:       t,
+		secrets: map[string]map[string]string{},
+		applied: map[string]string{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeAPIServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	if r.Header.Get("Authorization") == "Bearer forbidden" {
+		f.status(w, http.StatusForbidden, "Forbidden", `secrets is forbidden: User "dev" cannot list resource "secrets"`)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPatch:
+		assert.Equal(f.t, "application/apply-patch+yaml", r.Header.Get("Content-Type"))
+		assert.Equal(f.t, "dsops", r.URL.Query().Get("fieldManager"))
+		assert.Equal(f.t, "true", r.URL.Query().Get("force"))
+		body, _ := io.ReadAll(r.Body)
+		f.applied[r.URL.Path] = string(body)
+		_, _ = w.Write([]byte(`{}`))
+	case len(parts) == 5 && parts[4] == "secrets":
+		f.list(w, r, parts[3])
+	case len(parts) == 6 && parts[4] == "secrets":
+		data, ok := f.secrets[parts[3]+"/"+parts[5]]
+		if !ok {
+			f.status(w, http.StatusNotFound, "NotFound", `secrets "`+parts[5]+`" not found`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.secret(parts[3], parts[5], data))
+	default:
+		f.status(w, http.StatusNotFound, "NotFound", "unknown path "+r.URL.Path)
+	}
+}
+
+func (f *fakeAPIServer) list(w http.ResponseWriter, r *http.Request, namespace string) {
+	var names []string
+	for key := range f.secrets {
+		if ns, name, _ := strings.Cut(key, "/"); ns == namespace {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// Serve one secret per page when paging is on, to exercise continue tokens
+	start := 0
+	if token := r.URL.Query().Get("continue"); token != "" {
+		for i, name := range names {
+			if name == token {
+				start = i
+			}
+		}
+	}
+	items := []interface{}{}
+	next := ""
+	for i := start; i < len(names); i++ {
+		if f.pageSize > 0 && len(items) == f.pageSize {
+			next = names[i]
+			break
+		}
+		items = append(items, f.secret(namespace, names[i], f.secrets[namespace+"/"+names[i]]))
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"metadata": map[string]interface{}{"continue": next},
+		"items":    items,
+	})
+}
+
+func (f *fakeAPIServer) secret(namespace, name string, data map[string]string) map[string]interface{} {
+	encoded := map[string]string{}
+	for k, v := range data {
+		encoded[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"resourceVersion":   "4711",
+			"creationTimestamp": "2025-01-02T03:04:05Z",
+			"labels":            map[string]string{"app": "web"},
+			"managedFields": []map[string]interface{}{
+				{"manager": "kubectl", "time": "2025-02-03T04:05:06Z"},
+			},
+		},
+		"type": "Opaque",
+		"data": encoded,
+	}
+}
+
+func (f *fakeAPIServer) status(w http.ResponseWriter, code int, reason, message string) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"kind": "Status", "status": "Failure", "reason": reason, "message": message, "code": code,
+	})
+}
+
+func testClient(t *testing.T, server *httptest.Server, token string) *Client {
+	t.Helper()
+	client, err := newClient(&restConfig{Host: server.URL, Namespace: "default", Token: token, Source: "test"})
+	require.NoError(t, err)
+	return client
+}
+
+func TestProvider_Resolve(t *testing.T) {
+	fake, server := newFakeAPIServer(t)
+	fake.secrets["prod/db"] = map[string]string{"password": "hunter2", "username": "app"}
+	fake.secrets["default/api"] = map[string]string{"token": "abc"}
+
+	p, err := NewProviderWithClient("k8s", nil, testClient(t, server, "t0ken"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "prod/db#password"})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value.Value)
+	assert.Equal(t, "4711", value.Version)
+	assert.Equal(t, time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC), value.UpdatedAt.UTC())
+	assert.Equal(t, "Bearer t0ken", fake.auth[0])
+
+	// The store namespace applies to keys without one
+	value, err = p.Resolve(ctx, provider.Reference{Key: "api#token"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", value.Value)
+
+	// Field from a store:// reference
+	value, err = p.Resolve(ctx, provider.Reference{Key: "prod/db", Field: "username"})
+	require.NoError(t, err)
+	assert.Equal(t, "app", value.Value)
+
+	// Whole secret as JSON
+	value, err = p.Resolve(ctx, provider.Reference{Key: "prod/db"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password":"hunter2","username":"app"}`, value.Value)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "prod/missing#password"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "prod/db#email"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Suggestion, "password, username")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "a/b/c#d"})
+	assert.Error(t, err)
+}
+
+func TestProvider_Resolve_Forbidden(t *testing.T) {
+	_, server := newFakeAPIServer(t)
+	p, err := NewProviderWithClient("k8s", nil, testClient(t, server, "forbidden"))
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "prod/db#password"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Details, "cannot list resource")
+	assert.Contains(t, userErr.Suggestion, "RoleBinding")
+
+	err = p.Validate(context.Background())
+	assert.Error(t, err)
+}
+
+func TestProvider_Describe(t *testing.T) {
+	fake, server := newFakeAPIServer(t)
+	fake.secrets["prod/db"] = map[string]string{"password": "hunter2"}
+
+	p, err := NewProviderWithClient("k8s", map[string]interface{}{"namespace": "prod"}, testClient(t, server, ""))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "db#password"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "4711", meta.Version)
+	assert.Equal(t, 7, meta.Size)
+	assert.Equal(t, "Opaque", meta.Type)
+	assert.Equal(t, "web", meta.Tags["app"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "db#other"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "missing#password"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestProvider_ListSecrets(t *testing.T) {
+	fake, server := newFakeAPIServer(t)
+	fake.pageSize = 1
+	fake.secrets["default/api"] = map[string]string{"token": "abc"}
+	fake.secrets["default/db"] = map[string]string{"password": "x", "username": "y"}
+	fake.secrets["prod/db"] = map[string]string{"password": "z"}
+
+	p, err := NewProviderWithClient("k8s", nil, testClient(t, server, ""))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := p.ListSecrets(ctx, provider.ListOptions{})
+	require.NoError(t, err)
+	var keys []string
+	for _, s := range result.Secrets {
+		keys = append(keys, s.Key)
+	}
+	assert.Equal(t, []string{"default/api#token", "default/db#password", "default/db#username"}, keys)
+
+	result, err = p.ListSecrets(ctx, provider.ListOptions{Prefix: "prod/"})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 1)
+	assert.Equal(t, "prod/db#password", result.Secrets[0].Key)
+
+	// A prefix without a namespace is a name prefix in the store namespace
+	result, err = p.ListSecrets(ctx, provider.ListOptions{Prefix: "db"})
+	require.NoError(t, err)
+	assert.Len(t, result.Secrets, 2)
+}
+
+func TestClient_Apply(t *testing.T) {
+	fake, server := newFakeAPIServer(t)
+	client := testClient(t, server, "")
+
+	secret := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: app\n  namespace: prod\ndata:\n  A: Yg==\n"
+	require.NoError(t, client.Apply(context.Background(), []byte(secret)))
+	assert.Equal(t, secret, fake.applied["/api/v1/namespaces/prod/secrets/app"])
+
+	sealed := "apiVersion: bitnami.com/v1alpha1\nkind: SealedSecret\nmetadata:\n  name: app\n"
+	require.NoError(t, client.Apply(context.Background(), []byte(sealed)))
+	assert.Contains(t, fake.applied, "/apis/bitnami.com/v1alpha1/namespaces/default/sealedsecrets/app")
+
+	assert.Error(t, client.Apply(context.Background(), []byte("kind: Secret\n")))
+}
+
+func TestLoadConfig_Kubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("file-token\n"), 0600))
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com:6443
+    insecure-skip-tls-verify: true
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: dev-user
+    namespace: team-a
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: prod-user
+users:
+- name: dev-user
+  user:
+    tokenFile: token
+- name: prod-user
+  user:
+    token: prod-token
+`
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+
+	config, err := loadConfig(ConnectionOptions{Kubeconfig: path})
+	require.NoError(t, err)
+	assert.Equal(t, "https://dev.example.com:6443", config.Host)
+	assert.Equal(t, "team-a", config.Namespace)
+	assert.True(t, config.Insecure)
+	assert.Equal(t, filepath.Join(dir, "token"), config.TokenFile)
+
+	config, err = loadConfig(ConnectionOptions{Kubeconfig: path, Context: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com", config.Host)
+	assert.Equal(t, "default", config.Namespace)
+	assert.Equal(t, "prod-token", config.Token)
+
+	_, err = loadConfig(ConnectionOptions{Kubeconfig: path, Context: "staging"})
+	assert.ErrorContains(t, err, `context "staging" not found`)
+
+	// KUBECONFIG lists several files; missing ones are skipped
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "missing")+string(filepath.ListSeparator)+path)
+	config, err = loadConfig(ConnectionOptions{Context: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "prod-token", config.Token)
+}
+
+func TestLoadConfig_InCluster(t *testing.T) {
+	dir := t.TempDir()
+	serviceAccountDir = dir
+	t.Cleanup(func() { serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount" })
+
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "none"))
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+	_, err := loadConfig(ConnectionOptions{InCluster: true})
+	assert.Error(t, err, "no service account token yet")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("sa-token"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("ca"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "namespace"), []byte("apps\n"), 0600))
+
+	config, err := loadConfig(ConnectionOptions{InCluster: true})
+	require.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.1:443", config.Host)
+	assert.Equal(t, "apps", config.Namespace)
+	assert.Equal(t, filepath.Join(dir, "token"), config.TokenFile)
+}
+
+func TestClient_TokenFileAndExec(t *testing.T) {
+	fake, server := newFakeAPIServer(t)
+	fake.secrets["default/api"] = map[string]string{"token": "abc"}
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("from-file\n"), 0600))
+	client, err := newClient(&restConfig{Host: server.URL, Namespace: "default", TokenFile: tokenFile})
+	require.NoError(t, err)
+	_, err = client.GetSecret(ctx, "default", "api")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer from-file", fake.auth[len(fake.auth)-1])
+
+	plugin := filepath.Join(dir, "plugin.sh")
+	script := `#!/bin/sh
+echo "$KUBERNETES_EXEC_INFO" > "` + filepath.Join(dir, "exec-info") + `"
+echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"from-exec-'$PLUGIN_SUFFIX'"}}'
+`
+	require.NoError(t, os.WriteFile(plugin, []byte(script), 0700))
+	exec := &execConfig{Command: "./plugin.sh", Dir: dir}
+	exec.Env = append(exec.Env, struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	}{Name: "PLUGIN_SUFFIX", Value: "1"})
+
+	client, err = newClient(&restConfig{Host: server.URL, Namespace: "default", Exec: exec})
+	require.NoError(t, err)
+	_, err = client.GetSecret(ctx, "default", "api")
+	require.NoError(t, err)
+	_, err = client.GetSecret(ctx, "default", "api")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer from-exec-1", fake.auth[len(fake.auth)-1])
+
+	info, err := os.ReadFile(filepath.Join(dir, "exec-info"))
+	require.NoError(t, err)
+	assert.Contains(t, string(info), `"kind":"ExecCredential"`)
+}
@@ -0,0 +1,221 @@
+package providers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+	"github.com/systmms/dsops/tests/testutil"
+)
+
+func listedKeys(result provider.ListResult) []string {
+	keys := make([]string, len(result.Secrets))
+	for i, s := range result.Secrets {
+		keys[i] = s.Key
+	}
+	return keys
+}
+
+func TestAWSSecretsManagerListSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSecretsManagerClient()
+	client.AddSecretString("app/db", "x")
+	client.AddSecretString("app/api", "x")
+	client.AddSecretString("other/token", "x")
+	client.Secrets["app/db"].Description = aws.String("Database password")
+	client.Secrets["app/db"].Tags = map[string]string{"team": "platform"}
+
+	p, err := providers.NewAWSSecretsManagerProvider("aws", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSecretsManagerClient(client))
+	require.NoError(t, err)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{Prefix: "app/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app/api", "app/db"}, listedKeys(result))
+	assert.Equal(t, "Database password", result.Secrets[1].Description)
+	assert.Equal(t, "platform", result.Secrets[1].Tags["team"])
+	assert.NotEmpty(t, result.Secrets[1].Version)
+}
+
+func TestAWSSSMListSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSSMClient()
+	client.AddSecureStringParameter("/myapp/db-password", "x")
+	client.AddStringParameter("/myapp/api-url", "x")
+	client.AddStringParameter("/other/value", "x")
+
+	p, err := providers.NewAWSSSMProvider("ssm", map[string]interface{}{
+		"region":           "us-east-1",
+		"parameter_prefix": "/myapp/",
+	}, providers.WithSSMClient(client))
+	require.NoError(t, err)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-url", "db-password"}, listedKeys(result), "configured prefix is stripped")
+	assert.Equal(t, "SecureString", result.Secrets[1].Type)
+
+	result, err = p.ListSecrets(context.Background(), provider.ListOptions{Prefix: "db"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db-password"}, listedKeys(result))
+}
+
+func TestAzureKeyVaultListSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeAzureKeyVaultClient()
+	p, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, name := range []string{"app-db", "app-api", "other"} {
+		_, err := p.PutSecret(ctx, provider.Reference{Key: name}, []byte("x"), provider.WriteOptions{
+			Description: "desc " + name,
+			Tags:        map[string]string{"team": "payments"},
+		})
+		require.NoError(t, err)
+	}
+
+	result, err := p.ListSecrets(ctx, provider.ListOptions{Prefix: "app-", PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-api"}, listedKeys(result))
+	assert.Equal(t, "desc app-api", result.Secrets[0].Description)
+	assert.Equal(t, map[string]string{"team": "payments"}, result.Secrets[0].Tags)
+	require.NotEmpty(t, result.NextPageToken)
+
+	result, err = p.ListSecrets(ctx, provider.ListOptions{Prefix: "app-", PageSize: 1, PageToken: result.NextPageToken})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-db"}, listedKeys(result))
+	assert.Empty(t, result.NextPageToken)
+}
+
+func TestInfisicalAndAkeylessListSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	infClient := fakes.NewFakeInfisicalClient()
+	infClient.SetSecret("DB_PASSWORD", "x")
+	infClient.SetSecret("API_KEY", "x")
+	inf := providers.NewInfisicalProviderWithClient("infisical", nil, infClient)
+
+	result, err := inf.ListSecrets(ctx, provider.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_KEY", "DB_PASSWORD"}, listedKeys(result))
+
+	akClient := fakes.NewFakeAkeylessClient()
+	akClient.SetSecret("/prod/db", "x")
+	akClient.SetSecret("/prod/api", "x")
+	akClient.SetSecret("/dev/db", "x")
+	ak := providers.NewAkeylessProviderWithClient("akeyless", nil, akClient)
+
+	result, err = ak.ListSecrets(ctx, provider.ListOptions{Prefix: "/prod/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/prod/api", "/prod/db"}, listedKeys(result))
+}
+
+func TestDopplerListSecrets(t *testing.T) {
+	t.Parallel()
+
+	mockExec := testutil.NewMockCommandExecutor()
+	mockExec.AddJSONResponse("doppler secrets --only-names --json", `{"DB_URL":{},"API_KEY":{},"DOPPLER_ENVIRONMENT":{}}`)
+	p := providers.NewDopplerProviderWithExecutor(providers.DopplerConfig{}, mockExec)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_KEY", "DB_URL"}, listedKeys(result))
+	assert.NotEmpty(t, result.NextPageToken)
+}
+
+func TestPassListSecrets(t *testing.T) {
+	t.Parallel()
+
+	storeDir := t.TempDir()
+	for _, entry := range []string{"email/gmail.gpg", "work/api.gpg", "work/db.gpg", ".gpg-id", ".git/config"} {
+		path := filepath.Join(storeDir, entry)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte("encrypted"), 0600))
+	}
+
+	mockExec := testutil.NewMockCommandExecutor()
+	p := providers.NewPassProviderWithExecutor(providers.PassConfig{PasswordStore: storeDir}, mockExec)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email/gmail", "work/api", "work/db"}, listedKeys(result))
+
+	result, err = p.ListSecrets(context.Background(), provider.ListOptions{Prefix: "work/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work/api", "work/db"}, listedKeys(result))
+
+	assert.Zero(t, mockExec.CallCount(), "listing must not decrypt entries")
+}
+
+func TestAWSSecretsManagerListVersions(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSecretsManagerClient()
+	client.AddSecretString("app/db", "leaked")
+	p, err := providers.NewAWSSecretsManagerProvider("aws", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSecretsManagerClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ref := provider.Reference{Key: "app/db"}
+	newVersion, err := p.CreateNewVersion(ctx, ref, []byte("rotated"), nil)
+	require.NoError(t, err)
+
+	var lister provider.VersionLister = p
+	versions, err := lister.ListVersions(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1-abc123", newVersion}, versions)
+
+	// Deprecating the previous version removes its AWSPREVIOUS label
+	require.NoError(t, p.DeprecateVersion(ctx, ref, "v1-abc123"))
+	versions, err = p.ListVersions(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{newVersion}, versions)
+
+	_, err = p.ListVersions(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestAzureKeyVaultListVersions(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeAzureKeyVaultClient()
+	p, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ref := provider.Reference{Key: "app-db"}
+	for _, value := range []string{"one", "two", "three"} {
+		_, err := p.CreateNewVersion(ctx, ref, []byte(value), nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, p.DeprecateVersion(ctx, ref, "v1"))
+
+	var lister provider.VersionLister = p
+	versions, err := lister.ListVersions(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v2", "v3"}, versions)
+
+	_, err = p.ListVersions(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
@@ -0,0 +1,431 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/providers/contracts"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// OnePasswordAPIProvider reads 1Password items over an API instead of the
+// op CLI: a Connect server for onepassword.connect stores, or the 1Password
+// SDK with a service account token for onepassword.serviceaccount stores.
+//
+// References name the vault, item, optional section and field by name or
+// ID, "vault/item[/section][/field]", optionally with the op CLI's "op://"
+// prefix. Vault listings, item listings and items are cached for the life
+// of the process, so several fields of one item cost a single fetch.
+type OnePasswordAPIProvider struct {
+	name   string
+	config OnePasswordAPIConfig
+	client contracts.OnePasswordClient
+
+	mu     sync.Mutex
+	vaults []contracts.OnePasswordVault
+	items  map[string][]contracts.OnePasswordItemSummary
+	cache  map[string]*contracts.OnePasswordItem
+}
+
+// onePasswordRef is a parsed 1Password secret reference
+type onePasswordRef struct {
+	Vault   string
+	Item    string
+	Section string
+	Field   string
+}
+
+// NewOnePasswordConnectProvider creates a provider backed by a 1Password Connect server
+func NewOnePasswordConnectProvider(name string, config map[string]interface{}) (*OnePasswordAPIProvider, error) {
+	cfg := parseOnePasswordAPIConfig(config, "OP_CONNECT_TOKEN")
+	if cfg.Host == "" {
+		cfg.Host = os.Getenv("OP_CONNECT_HOST")
+	}
+	if cfg.Host == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "host",
+			Message:    fmt.Sprintf("store '%s' has no 1Password Connect server", name),
+			Suggestion: "Set host on the store or export OP_CONNECT_HOST",
+		}
+	}
+	if cfg.Token == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "token",
+			Message:    fmt.Sprintf("store '%s' has no 1Password Connect token", name),
+			Suggestion: "Set token on the store or export OP_CONNECT_TOKEN",
+		}
+	}
+
+	client, err := newOnePasswordConnectClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newOnePasswordAPIProvider(name, cfg, client), nil
+}
+
+// NewOnePasswordServiceAccountProvider creates a provider authenticated with a
+// 1Password service account token
+func NewOnePasswordServiceAccountProvider(name string, config map[string]interface{}) (*OnePasswordAPIProvider, error) {
+	cfg := parseOnePasswordAPIConfig(config, "OP_SERVICE_ACCOUNT_TOKEN")
+	if cfg.Token == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "token",
+			Message:    fmt.Sprintf("store '%s' has no 1Password service account token", name),
+			Suggestion: "Set token on the store or export OP_SERVICE_ACCOUNT_TOKEN",
+		}
+	}
+	return newOnePasswordAPIProvider(name, cfg, newOnePasswordSDKClient(cfg)), nil
+}
+
+// NewOnePasswordAPIProviderWithClient creates a 1Password API provider with a custom client.
+// This is primarily for testing, allowing the API client to be mocked.
+func NewOnePasswordAPIProviderWithClient(name string, config map[string]interface{}, client contracts.OnePasswordClient) *OnePasswordAPIProvider {
+	return newOnePasswordAPIProvider(name, parseOnePasswordAPIConfig(config, ""), client)
+}
+
+func newOnePasswordAPIProvider(name string, cfg OnePasswordAPIConfig, client contracts.OnePasswordClient) *OnePasswordAPIProvider {
+	return &OnePasswordAPIProvider{
+		name:   name,
+		config: cfg,
+		client: client,
+		items:  make(map[string][]contracts.OnePasswordItemSummary),
+		cache:  make(map[string]*contracts.OnePasswordItem),
+	}
+}
+
+// Name returns the provider name
+func (p *OnePasswordAPIProvider) Name() string {
+	return p.name
+}
+
+// Resolve retrieves a field of a 1Password item
+func (p *OnePasswordAPIProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	opRef, err := parseOnePasswordReference(ref.Key)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	item, vault, err := p.getItem(ctx, opRef)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	value, ok := onePasswordItemField(item, opRef.Section, opRef.Field)
+	if !ok {
+		return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+	}
+
+	return provider.SecretValue{
+		Value:     value,
+		Version:   strconv.Itoa(item.Version),
+		UpdatedAt: item.UpdatedAt,
+		Metadata: map[string]string{
+			"provider": p.name,
+			"vault":    vault.Name,
+			"item_id":  item.ID,
+		},
+	}, nil
+}
+
+// Describe returns metadata about a 1Password item without returning field values
+func (p *OnePasswordAPIProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	opRef, err := parseOnePasswordReference(ref.Key)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	item, _, err := p.getItem(ctx, opRef)
+	if err != nil {
+		var notFound *provider.NotFoundError
+		if errors.As(err, &notFound) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, err
+	}
+
+	tags := make(map[string]string)
+	for i, tag := range item.Tags {
+		tags[fmt.Sprintf("tag_%d", i)] = tag
+	}
+
+	_, exists := onePasswordItemField(item, opRef.Section, opRef.Field)
+	return provider.Metadata{
+		Exists:    exists,
+		Version:   strconv.Itoa(item.Version),
+		UpdatedAt: item.UpdatedAt,
+		Type:      item.Category,
+		Tags:      tags,
+	}, nil
+}
+
+// ListSecrets lists the items of every vault the token can read as
+// "vault/item" references
+func (p *OnePasswordAPIProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	vaults, err := p.listVaults(ctx)
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	var secrets []provider.SecretInfo
+	for _, vault := range vaults {
+		items, err := p.listItems(ctx, vault.ID)
+		if err != nil {
+			return provider.ListResult{}, err
+		}
+		for _, item := range items {
+			secrets = append(secrets, provider.SecretInfo{
+				Key:       vault.Name + "/" + item.Title,
+				Type:      item.Category,
+				UpdatedAt: item.UpdatedAt,
+			})
+		}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// Capabilities returns the provider's supported features
+func (p *OnePasswordAPIProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false,
+		SupportsMetadata:   true,
+		SupportsWatching:   false,
+		SupportsBinary:     false,
+		RequiresAuth:       true,
+		AuthMethods:        []string{"connect_token", "service_account"},
+	}
+}
+
+// Validate checks that the token is accepted by listing vaults
+func (p *OnePasswordAPIProvider) Validate(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Validation always goes to the server
+	p.vaults = nil
+	if _, err := p.listVaults(ctx); err != nil {
+		return fmt.Errorf("1password validation failed: %w", err)
+	}
+	return nil
+}
+
+// parseOnePasswordReference parses "[op://]vault/item[/section][/field]".
+// The field defaults to password.
+func parseOnePasswordReference(key string) (onePasswordRef, error) {
+	var ref onePasswordRef
+
+	parts := strings.Split(strings.TrimPrefix(key, "op://"), "/")
+	for _, part := range parts {
+		if part == "" {
+			return ref, fmt.Errorf("invalid 1password reference '%s': empty path segment", key)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		ref.Vault, ref.Item, ref.Field = parts[0], parts[1], "password"
+	case 3:
+		ref.Vault, ref.Item, ref.Field = parts[0], parts[1], parts[2]
+	case 4:
+		ref.Vault, ref.Item, ref.Section, ref.Field = parts[0], parts[1], parts[2], parts[3]
+	default:
+		return ref, fmt.Errorf("invalid 1password reference '%s': expected vault/item[/section][/field]", key)
+	}
+	return ref, nil
+}
+
+// getItem finds an item by vault and item name or ID, fetching it at most once
+func (p *OnePasswordAPIProvider) getItem(ctx context.Context, ref onePasswordRef) (*contracts.OnePasswordItem, *contracts.OnePasswordVault, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	vault, err := p.findVault(ctx, ref.Vault)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := p.listItems(ctx, vault.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matches []contracts.OnePasswordItemSummary
+	for _, item := range items {
+		if item.ID == ref.Item || item.Title == ref.Item {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil, &provider.NotFoundError{Provider: p.name, Key: ref.Vault + "/" + ref.Item}
+	case 1:
+	default:
+		return nil, nil, dserrors.UserError{
+			Message:    fmt.Sprintf("%d items in vault '%s' are titled '%s'", len(matches), vault.Name, ref.Item),
+			Suggestion: "Reference the item by its ID",
+		}
+	}
+
+	cacheKey := vault.ID + "/" + matches[0].ID
+	if item, ok := p.cache[cacheKey]; ok {
+		return item, vault, nil
+	}
+
+	item, err := p.client.GetItem(ctx, vault.ID, matches[0].ID)
+	if err != nil {
+		if errors.Is(err, ErrOnePasswordNotFound) {
+			return nil, nil, &provider.NotFoundError{Provider: p.name, Key: ref.Vault + "/" + ref.Item}
+		}
+		return nil, nil, err
+	}
+	p.cache[cacheKey] = item
+	return item, vault, nil
+}
+
+// findVault finds a vault by name or ID. Callers hold p.mu.
+func (p *OnePasswordAPIProvider) findVault(ctx context.Context, nameOrID string) (*contracts.OnePasswordVault, error) {
+	vaults, err := p.listVaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vaults {
+		if vaults[i].ID == nameOrID || vaults[i].Name == nameOrID {
+			return &vaults[i], nil
+		}
+	}
+	return nil, dserrors.UserError{
+		Message:    fmt.Sprintf("1Password vault '%s' not found", nameOrID),
+		Suggestion: fmt.Sprintf("Check that the token for store '%s' has access to the vault", p.name),
+	}
+}
+
+// listVaults returns the cached vault listing. Callers hold p.mu.
+func (p *OnePasswordAPIProvider) listVaults(ctx context.Context) ([]contracts.OnePasswordVault, error) {
+	if p.vaults != nil {
+		return p.vaults, nil
+	}
+	vaults, err := p.client.ListVaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if vaults == nil {
+		vaults = []contracts.OnePasswordVault{}
+	}
+	p.vaults = vaults
+	return vaults, nil
+}
+
+// listItems returns the cached item listing of a vault. Callers hold p.mu.
+func (p *OnePasswordAPIProvider) listItems(ctx context.Context, vaultID string) ([]contracts.OnePasswordItemSummary, error) {
+	if items, ok := p.items[vaultID]; ok {
+		return items, nil
+	}
+	items, err := p.client.ListItems(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	p.items[vaultID] = items
+	return items, nil
+}
+
+// onePasswordItemField extracts a field by ID or label, optionally within a
+// section. Without a match the built-in names password, username,
+// notes, url/website and title are recognised.
+func onePasswordItemField(item *contracts.OnePasswordItem, section, field string) (string, bool) {
+	sectionID := ""
+	if section != "" {
+		for _, s := range item.Sections {
+			if s.ID == section || strings.EqualFold(s.Label, section) {
+				sectionID = s.ID
+				break
+			}
+		}
+		if sectionID == "" {
+			return "", false
+		}
+	}
+
+	// Without a section, fields outside sections win over same-named ones inside
+	for _, f := range item.Fields {
+		if f.SectionID != sectionID {
+			continue
+		}
+		if f.ID == field || strings.EqualFold(f.Label, field) {
+			return f.Value, true
+		}
+	}
+	if section != "" {
+		return "", false
+	}
+	for _, f := range item.Fields {
+		if f.ID == field || strings.EqualFold(f.Label, field) {
+			return f.Value, true
+		}
+	}
+
+	switch strings.ToLower(field) {
+	case "password":
+		for _, f := range item.Fields {
+			if f.Purpose == "PASSWORD" || strings.EqualFold(f.Type, "CONCEALED") {
+				return f.Value, true
+			}
+		}
+	case "username":
+		for _, f := range item.Fields {
+			if f.Purpose == "USERNAME" {
+				return f.Value, true
+			}
+		}
+	case "notes", "notesplain":
+		return item.Notes, true
+	case "url", "website":
+		if len(item.URLs) > 0 {
+			return item.URLs[0], true
+		}
+	case "title", "name":
+		return item.Title, true
+	}
+	return "", false
+}
+
+// parseOnePasswordAPIConfig parses configuration map into OnePasswordAPIConfig.
+// tokenEnv names the environment variable holding the token when the store
+// does not set one.
+func parseOnePasswordAPIConfig(config map[string]interface{}, tokenEnv string) OnePasswordAPIConfig {
+	cfg := OnePasswordAPIConfig{
+		Timeout: DefaultTimeout,
+	}
+
+	if host, ok := config["host"].(string); ok {
+		cfg.Host = host
+	}
+
+	if token, ok := config["token"].(string); ok {
+		cfg.Token = token
+	}
+	if cfg.Token == "" && tokenEnv != "" {
+		cfg.Token = os.Getenv(tokenEnv)
+	}
+
+	if timeout, ok := config["timeout"].(string); ok {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			cfg.Timeout = d
+		}
+	}
+
+	if caCert, ok := config["ca_cert"].(string); ok {
+		cfg.CACert = caCert
+	}
+
+	return cfg
+}
@@ -0,0 +1,225 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+const onePasswordTestToken = "connect-token"
+
+// connectServer fakes a 1Password Connect server with one vault
+type connectServer struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	items    map[string]map[string]interface{}
+	requests []string
+}
+
+func newConnectServer(t *testing.T) *connectServer {
+	t.Helper()
+	s := &connectServer{items: map[string]map[string]interface{}{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/vaults", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]string{{"id": "vault1", "name": "Production"}})
+	})
+	mux.HandleFunc("GET /v1/vaults/vault1/items", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		list := []map[string]interface{}{}
+		for _, item := range s.items {
+			list = append(list, map[string]interface{}{"id": item["id"], "title": item["title"], "category": item["category"]})
+		}
+		writeJSON(w, list)
+	})
+	mux.HandleFunc("GET /v1/vaults/vault1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		item, ok := s.items[r.PathValue("id")]
+		s.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]interface{}{"status": 404, "message": "item not found"})
+			return
+		}
+		writeJSON(w, item)
+	})
+
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer "+onePasswordTestToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]interface{}{"status": 401, "message": "Invalid token signature"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *connectServer) addLogin(id, title, username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[id] = map[string]interface{}{
+		"id":       id,
+		"title":    title,
+		"category": "LOGIN",
+		"vault":    map[string]string{"id": "vault1"},
+		"version":  3,
+		"tags":     []string{"prod"},
+		"urls":     []map[string]interface{}{{"href": "https://db.example.com", "primary": true}},
+		"sections": []map[string]string{{"id": "sec1", "label": "Replica"}},
+		"fields": []map[string]interface{}{
+			{"id": "username", "type": "STRING", "purpose": "USERNAME", "label": "username", "value": username},
+			{"id": "password", "type": "CONCEALED", "purpose": "PASSWORD", "label": "password", "value": password},
+			{"id": "notesPlain", "type": "STRING", "purpose": "NOTES", "label": "notesPlain", "value": "rotated monthly"},
+			{"id": "f1", "type": "STRING", "label": "host", "value": "primary.db", "section": map[string]string{"id": "sec1"}},
+			{"id": "f2", "type": "STRING", "label": "host", "value": "top.db"},
+		},
+	}
+}
+
+func (s *connectServer) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, id)
+}
+
+func (s *connectServer) log() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *connectServer) provider(t *testing.T, extra map[string]interface{}) *OnePasswordAPIProvider {
+	t.Helper()
+	config := map[string]interface{}{
+		"host":  s.srv.URL,
+		"token": onePasswordTestToken,
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	p, err := NewOnePasswordConnectProvider("op-connect", config)
+	require.NoError(t, err)
+	return p
+}
+
+func TestOnePasswordConnectProvider_Contract(t *testing.T) {
+	server := newConnectServer(t)
+
+	provider.RunContractTests(t, provider.ContractTest{
+		CreateProvider: func(t *testing.T) provider.Provider {
+			return server.provider(t, nil)
+		},
+		SetupTestSecret: func(t *testing.T, p provider.Provider) (string, func()) {
+			server.addLogin("contract1", "Contract", "app", "s3cret")
+			return "op://Production/Contract/password", func() { server.remove("contract1") }
+		},
+	})
+}
+
+func TestOnePasswordConnectProvider_Fields(t *testing.T) {
+	server := newConnectServer(t)
+	server.addLogin("item1", "Database", "app", "s3cret")
+	p := server.provider(t, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"op://Production/Database/password", "s3cret"},
+		{"op://vault1/item1/username", "app"},
+		{"Production/Database", "s3cret"},
+		{"Production/Database/notes", "rotated monthly"},
+		{"Production/Database/url", "https://db.example.com"},
+		{"Production/Database/host", "top.db"},
+		{"Production/Database/Replica/host", "primary.db"},
+	}
+	for _, tt := range tests {
+		value, err := p.Resolve(ctx, provider.Reference{Key: tt.key})
+		require.NoError(t, err, tt.key)
+		assert.Equal(t, tt.want, value.Value, tt.key)
+	}
+
+	// Every field came from one vault listing, one item listing and one item fetch
+	assert.Equal(t, []string{
+		"GET /v1/vaults",
+		"GET /v1/vaults/vault1/items",
+		"GET /v1/vaults/vault1/items/item1",
+	}, server.log())
+
+	_, err := p.Resolve(ctx, provider.Reference{Key: "Production/Database/missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "Production/Database"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "3", meta.Version)
+	assert.Equal(t, "LOGIN", meta.Type)
+	assert.Equal(t, "prod", meta.Tags["tag_0"])
+
+	result, err := p.ListSecrets(ctx, provider.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 1)
+	assert.Equal(t, "Production/Database", result.Secrets[0].Key)
+}
+
+func TestOnePasswordConnectProvider_Errors(t *testing.T) {
+	server := newConnectServer(t)
+
+	p := server.provider(t, nil)
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "Database"})
+	assert.ErrorContains(t, err, "expected vault/item[/section][/field]")
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "op://Staging/Database/password"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "vault 'Staging' not found")
+
+	p = server.provider(t, map[string]interface{}{"token": "wrong"})
+	err = p.Validate(context.Background())
+	assert.ErrorContains(t, err, "Invalid token signature")
+}
+
+func TestNewOnePasswordAPIProviders_Config(t *testing.T) {
+	t.Setenv("OP_CONNECT_HOST", "")
+	t.Setenv("OP_CONNECT_TOKEN", "")
+	t.Setenv("OP_SERVICE_ACCOUNT_TOKEN", "")
+
+	_, err := NewOnePasswordConnectProvider("op", map[string]interface{}{"token": "x"})
+	var configErr dserrors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "host", configErr.Field)
+
+	_, err = NewOnePasswordConnectProvider("op", map[string]interface{}{"host": "http://localhost:8080"})
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "token", configErr.Field)
+
+	_, err = NewOnePasswordServiceAccountProvider("op", nil)
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "token", configErr.Field)
+
+	t.Setenv("OP_CONNECT_HOST", "http://localhost:8080")
+	t.Setenv("OP_CONNECT_TOKEN", "token")
+	p, err := NewOnePasswordConnectProvider("op", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "token", p.config.Token)
+
+	t.Setenv("OP_SERVICE_ACCOUNT_TOKEN", "ops_token")
+	_, err = NewOnePasswordServiceAccountProvider("op", nil)
+	require.NoError(t, err, "the service account signs in on first use")
+}
@@ -0,0 +1,212 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/systmms/dsops/internal/providers/contracts"
+)
+
+// onePasswordConnectClient implements OnePasswordClient against a
+// 1Password Connect server
+type onePasswordConnectClient struct {
+	httpClient *http.Client
+	host       string
+	token      string
+}
+
+// newOnePasswordConnectClient creates a new HTTP client for 1Password Connect
+func newOnePasswordConnectClient(cfg OnePasswordAPIConfig) (*onePasswordConnectClient, error) {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{},
+	}
+
+	// Configure custom CA if provided
+	if cfg.CACert != "" {
+		caCert, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+
+		transport.TLSClientConfig.RootCAs = caCertPool
+	}
+
+	return &onePasswordConnectClient{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+		},
+		host:  strings.TrimSuffix(cfg.Host, "/"),
+		token: cfg.Token,
+	}, nil
+}
+
+// ListVaults lists the vaults the Connect token can read
+func (c *onePasswordConnectClient) ListVaults(ctx context.Context) ([]contracts.OnePasswordVault, error) {
+	var vaults []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.get(ctx, "list", "/v1/vaults", &vaults); err != nil {
+		return nil, err
+	}
+
+	result := make([]contracts.OnePasswordVault, len(vaults))
+	for i, v := range vaults {
+		result[i] = contracts.OnePasswordVault{ID: v.ID, Name: v.Name}
+	}
+	return result, nil
+}
+
+// ListItems lists the items of a vault
+func (c *onePasswordConnectClient) ListItems(ctx context.Context, vaultID string) ([]contracts.OnePasswordItemSummary, error) {
+	var items []struct {
+		ID        string    `json:"id"`
+		Title     string    `json:"title"`
+		Category  string    `json:"category"`
+		Tags      []string  `json:"tags"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+	if err := c.get(ctx, "list", "/v1/vaults/"+url.PathEscape(vaultID)+"/items", &items); err != nil {
+		return nil, err
+	}
+
+	result := make([]contracts.OnePasswordItemSummary, len(items))
+	for i, item := range items {
+		result[i] = contracts.OnePasswordItemSummary{
+			ID:        item.ID,
+			Title:     item.Title,
+			Category:  item.Category,
+			Tags:      item.Tags,
+			UpdatedAt: item.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+// GetItem retrieves an item with its fields
+func (c *onePasswordConnectClient) GetItem(ctx context.Context, vaultID, itemID string) (*contracts.OnePasswordItem, error) {
+	var item struct {
+		ID       string `json:"id"`
+		Title    string `json:"title"`
+		Category string `json:"category"`
+		Vault    struct {
+			ID string `json:"id"`
+		} `json:"vault"`
+		Version   int       `json:"version"`
+		Tags      []string  `json:"tags"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		URLs      []struct {
+			Href    string `json:"href"`
+			Primary bool   `json:"primary"`
+		} `json:"urls"`
+		Sections []struct {
+			ID    string `json:"id"`
+			Label string `json:"label"`
+		} `json:"sections"`
+		Fields []struct {
+			ID      string `json:"id"`
+			Type    string `json:"type"`
+			Purpose string `json:"purpose"`
+			Label   string `json:"label"`
+			Value   string `json:"value"`
+			Section *struct {
+				ID string `json:"id"`
+			} `json:"section"`
+		} `json:"fields"`
+	}
+	path := "/v1/vaults/" + url.PathEscape(vaultID) + "/items/" + url.PathEscape(itemID)
+	if err := c.get(ctx, "fetch", path, &item); err != nil {
+		return nil, err
+	}
+
+	result := &contracts.OnePasswordItem{
+		ID:        item.ID,
+		Title:     item.Title,
+		Category:  item.Category,
+		VaultID:   item.Vault.ID,
+		Version:   item.Version,
+		Tags:      item.Tags,
+		UpdatedAt: item.UpdatedAt,
+	}
+	for _, u := range item.URLs {
+		// The primary URL comes first
+		if u.Primary {
+			result.URLs = append([]string{u.Href}, result.URLs...)
+		} else {
+			result.URLs = append(result.URLs, u.Href)
+		}
+	}
+	for _, s := range item.Sections {
+		result.Sections = append(result.Sections, contracts.OnePasswordSection{ID: s.ID, Label: s.Label})
+	}
+	for _, f := range item.Fields {
+		field := contracts.OnePasswordItemField{ID: f.ID, Label: f.Label, Type: f.Type, Purpose: f.Purpose, Value: f.Value}
+		if f.Section != nil {
+			field.SectionID = f.Section.ID
+		}
+		if f.Purpose == "NOTES" {
+			result.Notes = f.Value
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	return result, nil
+}
+
+// get sends an authenticated GET request and decodes the JSON response
+func (c *onePasswordConnectClient) get(ctx context.Context, op, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.host+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrOnePasswordNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		message := string(bodyBytes)
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(bodyBytes, &apiErr) == nil && apiErr.Message != "" {
+			message = apiErr.Message
+		}
+		return &OnePasswordAPIError{
+			Op:         op,
+			StatusCode: resp.StatusCode,
+			Message:    message,
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Ensure onePasswordConnectClient implements contracts.OnePasswordClient
+var _ contracts.OnePasswordClient = (*onePasswordConnectClient)(nil)
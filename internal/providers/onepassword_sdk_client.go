@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	onepassword "github.com/1password/onepassword-sdk-go"
+
+	"github.com/systmms/dsops/internal/providers/contracts"
+)
+
+// onePasswordSDKClient implements OnePasswordClient with the 1Password SDK,
+// authenticating as a service account. The SDK client is created on first
+// use because creating it signs in; a failed sign-in is retried on the next
+// call.
+//
+// Service accounts have no public HTTP API: item data is end-to-end
+// encrypted and only the SDK's core (a WebAssembly module run by the pure-Go
+// wazero runtime) or the op CLI can decrypt it. The SDK keeps dsops free of
+// cgo and of an installed op binary. Its core is only loaded by the first
+// call against an onepassword.serviceaccount store; onepassword.connect
+// stores use the Connect REST API and never load it.
+type onePasswordSDKClient struct {
+	token string
+
+	mu     sync.Mutex
+	client *onepassword.Client
+}
+
+// newOnePasswordSDKClient creates a service account client
+func newOnePasswordSDKClient(cfg OnePasswordAPIConfig) *onePasswordSDKClient {
+	return &onePasswordSDKClient{token: cfg.Token}
+}
+
+// sdk returns the signed-in SDK client
+func (c *onePasswordSDKClient) sdk(ctx context.Context) (*onepassword.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := onepassword.NewClient(ctx,
+		onepassword.WithServiceAccountToken(c.token),
+		onepassword.WithIntegrationInfo("dsops", "v1"),
+	)
+	if err != nil {
+		return nil, &OnePasswordAPIError{Op: "auth", Err: err}
+	}
+	c.client = client
+	return client, nil
+}
+
+// ListVaults lists the vaults the service account can read
+func (c *onePasswordSDKClient) ListVaults(ctx context.Context) ([]contracts.OnePasswordVault, error) {
+	client, err := c.sdk(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vaults, err := client.Vaults().List(ctx)
+	if err != nil {
+		return nil, &OnePasswordAPIError{Op: "list", Err: err}
+	}
+
+	result := make([]contracts.OnePasswordVault, len(vaults))
+	for i, v := range vaults {
+		result[i] = contracts.OnePasswordVault{ID: v.ID, Name: v.Title}
+	}
+	return result, nil
+}
+
+// ListItems lists the items of a vault
+func (c *onePasswordSDKClient) ListItems(ctx context.Context, vaultID string) ([]contracts.OnePasswordItemSummary, error) {
+	client, err := c.sdk(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.Items().List(ctx, vaultID)
+	if err != nil {
+		return nil, &OnePasswordAPIError{Op: "list", Err: err}
+	}
+
+	result := make([]contracts.OnePasswordItemSummary, len(items))
+	for i, item := range items {
+		result[i] = contracts.OnePasswordItemSummary{
+			ID:        item.ID,
+			Title:     item.Title,
+			Category:  string(item.Category),
+			Tags:      item.Tags,
+			UpdatedAt: item.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+// GetItem retrieves an item with its fields
+func (c *onePasswordSDKClient) GetItem(ctx context.Context, vaultID, itemID string) (*contracts.OnePasswordItem, error) {
+	client, err := c.sdk(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := client.Items().Get(ctx, vaultID, itemID)
+	if err != nil {
+		return nil, &OnePasswordAPIError{Op: "fetch", Message: fmt.Sprintf("item %s", itemID), Err: err}
+	}
+
+	result := &contracts.OnePasswordItem{
+		ID:        item.ID,
+		Title:     item.Title,
+		Category:  string(item.Category),
+		VaultID:   item.VaultID,
+		Version:   int(item.Version),
+		Tags:      item.Tags,
+		Notes:     item.Notes,
+		UpdatedAt: item.UpdatedAt,
+	}
+	for _, w := range item.Websites {
+		result.URLs = append(result.URLs, w.URL)
+	}
+	for _, s := range item.Sections {
+		result.Sections = append(result.Sections, contracts.OnePasswordSection{ID: s.ID, Label: s.Title})
+	}
+	for _, f := range item.Fields {
+		field := contracts.OnePasswordItemField{ID: f.ID, Label: f.Title, Type: string(f.FieldType), Value: f.Value}
+		if f.SectionID != nil {
+			field.SectionID = *f.SectionID
+		}
+		result.Fields = append(result.Fields, field)
+	}
+	return result, nil
+}
+
+// Ensure onePasswordSDKClient implements contracts.OnePasswordClient
+var _ contracts.OnePasswordClient = (*onePasswordSDKClient)(nil)
@@ -3,8 +3,11 @@ package providers
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -110,7 +113,7 @@ func (p *PassProvider) Resolve(ctx context.Context, ref provider.Reference) (pro
 				Message:    fmt.Sprintf("Secret '%s' not found in pass", secretPath),
 				Suggestion: "Check the secret path with 'pass ls' or 'pass find <keyword>'",
 				Details:    "The secret path might not exist or may be in a different location",
-				Err:        err,
+				Err:        &provider.NotFoundError{Provider: p.Name(), Key: ref.Key},
 			}
 		}
 
@@ -192,9 +195,151 @@ func (p *PassProvider) Describe(ctx context.Context, ref provider.Reference) (pr
 	}, nil
 }
 
+// PutSecret inserts or overwrites an entry in the password store.
+// The value is the first line; the description and tags are stored as
+// "key: value" lines after it, following the pass convention.
+func (p *PassProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	secretPath := ref.Key
+
+	var content strings.Builder
+	content.Write(value)
+	if opts.Description != "" {
+		fmt.Fprintf(&content, "\ndescription: %s", opts.Description)
+	}
+
+	keys := make([]string, 0, len(opts.Tags))
+	for key := range opts.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&content, "\n%s: %s", key, opts.Tags[key])
+	}
+	content.WriteString("\n")
+
+	p.logger.Debug("Writing secret %s to pass", logging.Secret(secretPath))
+
+	_, stderr, err := p.executePassWithInput(ctx, []byte(content.String()), "insert", "--multiline", "--force", secretPath)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    "Failed to write secret to pass",
+			Suggestion: "Check your GPG key setup and that the password store is writable",
+			Details:    strings.TrimSpace(string(stderr)),
+			Err:        err,
+		}
+	}
+
+	return "", nil
+}
+
+// DeleteSecret removes an entry from the password store.
+func (p *PassProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	secretPath := ref.Key
+
+	stdout, stderr, err := p.executePass(ctx, "rm", "--force", secretPath)
+	if err != nil {
+		if strings.Contains(string(stderr), "is not in the password store") ||
+			strings.Contains(string(stdout), "is not in the password store") {
+			return &provider.NotFoundError{Provider: p.Name(), Key: secretPath}
+		}
+		return dserrors.UserError{
+			Message:    "Failed to delete secret from pass",
+			Suggestion: "Check that the password store is writable",
+			Details:    strings.TrimSpace(string(stderr)),
+			Err:        err,
+		}
+	}
+
+	return nil
+}
+
+// ListSecrets lists entries in the password store by walking its directory.
+// Entries are never decrypted.
+func (p *PassProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	storeDir, err := p.storeDir()
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	var secrets []provider.SecretInfo
+	err = filepath.WalkDir(storeDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != storeDir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".gpg") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(storeDir, path)
+		if err != nil {
+			return err
+		}
+		info := provider.SecretInfo{
+			Key: strings.TrimSuffix(filepath.ToSlash(rel), ".gpg"),
+		}
+		if fi, err := d.Info(); err == nil {
+			info.UpdatedAt = fi.ModTime()
+		}
+		secrets = append(secrets, info)
+		return nil
+	})
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Failed to list the password store",
+			Suggestion: "Check that the password store exists and is readable",
+			Details:    storeDir,
+			Err:        err,
+		}
+	}
+
+	return provider.PageSecrets(secrets, opts)
+}
+
+// storeDir returns the password store directory, following the same
+// precedence as pass: config, PASSWORD_STORE_DIR, then ~/.password-store
+func (p *PassProvider) storeDir() (string, error) {
+	if p.config.PasswordStore != "" {
+		return p.config.PasswordStore, nil
+	}
+	if dir := os.Getenv("PASSWORD_STORE_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".password-store"), nil
+}
+
 // executePass runs a pass command with proper environment setup.
-// This method handles environment variable configuration for custom password store paths and GPG keys.
+// When custom environment variables are needed the command is wrapped in a shell.
 func (p *PassProvider) executePass(ctx context.Context, args ...string) (stdout []byte, stderr []byte, err error) {
+	name, cmdArgs := p.passCommand(args...)
+	return p.executor.Execute(ctx, name, cmdArgs...)
+}
+
+// executePassWithInput runs a pass command with input written to its stdin.
+func (p *PassProvider) executePassWithInput(ctx context.Context, input []byte, args ...string) (stdout []byte, stderr []byte, err error) {
+	executor, ok := p.executor.(pkgexec.InputExecutor)
+	if !ok {
+		return nil, nil, fmt.Errorf("command executor does not support standard input")
+	}
+
+	name, cmdArgs := p.passCommand(args...)
+	return executor.ExecuteWithInput(ctx, input, name, cmdArgs...)
+}
+
+// passCommand builds the command name and arguments for a pass invocation
+func (p *PassProvider) passCommand(args ...string) (string, []string) {
 	// If custom environment variables are needed, we wrap the command in a shell
 	if p.config.PasswordStore != "" || p.config.GpgKey != "" {
 		// Build the environment prefix
@@ -214,11 +359,11 @@ func (p *PassProvider) executePass(ctx context.Context, args ...string) (stdout
 		}
 
 		// Execute via shell with environment variables
-		return p.executor.Execute(ctx, "sh", "-c", envPrefix+passCmd)
+		return "sh", []string{"-c", envPrefix + passCmd}
 	}
 
 	// Direct execution without custom environment
-	return p.executor.Execute(ctx, "pass", args...)
+	return "pass", args
 }
 
 // buildCommand creates a pass CLI command with proper environment setup.
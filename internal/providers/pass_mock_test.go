@@ -80,6 +80,7 @@ func TestPassProviderWithMockExecutor_Resolve(t *testing.T) {
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errContains)
+				assert.True(t, provider.IsNotFound(err), "missing pass entries must be a NotFoundError")
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.wantValue, secret.Value)
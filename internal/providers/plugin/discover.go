@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// BinaryPrefix is the file name prefix of plugins found in the plugin
+// directories: dsops-provider-corp provides store type "corp"
+const BinaryPrefix = "dsops-provider-"
+
+// DirEnv overrides the plugin directories, as a path list
+const DirEnv = "DSOPS_PLUGIN_DIR"
+
+// Dirs returns the directories searched for plugins: $DSOPS_PLUGIN_DIR, or
+// dsops/plugins in the user configuration directory
+func Dirs() []string {
+	if env := os.Getenv(DirEnv); env != "" {
+		var dirs []string
+		for _, dir := range filepath.SplitList(env) {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		return dirs
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(dir, "dsops", "plugins")}
+}
+
+// Discover returns the plugin executables in dirs by the store type they
+// provide. When several directories provide a type, the first one wins.
+// Missing directories are skipped.
+func Discover(dirs []string) map[string]string {
+	plugins := make(map[string]string)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, BinaryPrefix) || entry.IsDir() {
+				continue
+			}
+			storeType := strings.TrimPrefix(name, BinaryPrefix)
+			if runtime.GOOS == "windows" {
+				if !strings.EqualFold(filepath.Ext(storeType), ".exe") {
+					continue
+				}
+				storeType = strings.TrimSuffix(storeType, filepath.Ext(storeType))
+			} else {
+				info, err := entry.Info()
+				if err != nil || info.Mode()&0111 == 0 {
+					continue
+				}
+			}
+			if storeType == "" {
+				continue
+			}
+			if _, exists := plugins[storeType]; !exists {
+				plugins[storeType] = filepath.Join(dir, name)
+			}
+		}
+	}
+	return plugins
+}
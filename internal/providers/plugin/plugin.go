@@ -0,0 +1,425 @@
+// Package plugin runs out-of-process provider plugins and exposes each one
+// as a provider.Provider.
+//
+// A plugin process is started on first use, configured with the store
+// settings from dsops.yaml and reached over gRPC (see pkg/plugin for the
+// protocol and the SDK plugins are written with). If the process exits, the
+// calls in flight fail and the next call starts a fresh process.
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	sdk "github.com/systmms/dsops/pkg/plugin"
+	"github.com/systmms/dsops/pkg/provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// StartTimeout bounds how long a plugin may take to start and configure itself
+const StartTimeout = 10 * time.Second
+
+// stderrLines is how much plugin stderr is kept for error messages
+const stderrLines = 20
+
+// Options describes how to run a plugin
+type Options struct {
+	Command string        // Executable, as a path or a name looked up in PATH
+	Args    []string      // Extra arguments
+	Timeout time.Duration // Bound on every call (the store's timeout_ms)
+}
+
+// Provider is a provider.Provider served by a plugin process. It also
+// implements provider.Rotator; plugins without rotation support report it
+// through GetRotationMetadata.
+type Provider struct {
+	name    string
+	config  map[string]interface{}
+	options Options
+
+	mu      sync.Mutex
+	process *process
+}
+
+// NewProvider returns a provider backed by the plugin in options. The
+// plugin is not started until the provider is used.
+func NewProvider(name string, config map[string]interface{}, options Options) (*Provider, error) {
+	if options.Command == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "command",
+			Message:    "plugin stores need a command",
+			Suggestion: "Set command to the plugin executable, e.g. command: /usr/local/bin/dsops-provider-corp",
+		}
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	return &Provider{name: name, config: config, options: options}, nil
+}
+
+// Name returns the store name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the capabilities the plugin reported, starting it if
+// needed. A plugin that fails to start has no capabilities.
+func (p *Provider) Capabilities() provider.Capabilities {
+	ctx, cancel := context.WithTimeout(context.Background(), StartTimeout)
+	defer cancel()
+	client, err := p.client(ctx)
+	if err != nil {
+		return provider.Capabilities{}
+	}
+	return client.Capabilities()
+}
+
+// Validate starts the plugin and forwards Validate to it
+func (p *Provider) Validate(ctx context.Context) error {
+	client, err := p.client(ctx)
+	if err != nil {
+		return err
+	}
+	return p.check(client.Validate(ctx))
+}
+
+// Resolve forwards to the plugin
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+	value, err := client.Resolve(ctx, ref)
+	return value, p.check(err)
+}
+
+// Describe forwards to the plugin
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	meta, err := client.Describe(ctx, ref)
+	return meta, p.check(err)
+}
+
+// CreateNewVersion forwards to the plugin
+func (p *Provider) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	version, err := client.CreateNewVersion(ctx, ref, newValue, meta)
+	return version, p.check(err)
+}
+
+// DeprecateVersion forwards to the plugin
+func (p *Provider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	client, err := p.client(ctx)
+	if err != nil {
+		return err
+	}
+	return p.check(client.DeprecateVersion(ctx, ref, version))
+}
+
+// GetRotationMetadata forwards to the plugin
+func (p *Provider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return provider.RotationMetadata{}, err
+	}
+	meta, err := client.GetRotationMetadata(ctx, ref)
+	return meta, p.check(err)
+}
+
+// Close stops the plugin process, if one is running
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	proc := p.process
+	p.process = nil
+	p.mu.Unlock()
+
+	if proc != nil {
+		proc.stop()
+	}
+	return nil
+}
+
+// client returns the running plugin's client, starting a process when there
+// is none or the previous one exited
+func (p *Provider) client(ctx context.Context) (*sdk.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.process != nil && !p.process.exited() {
+		return p.process.client, nil
+	}
+	if p.process != nil {
+		p.process.stop()
+		p.process = nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, StartTimeout)
+	defer cancel()
+	proc, err := start(ctx, p.name, p.config, p.options)
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to start plugin for store '%s'", p.name),
+			Details:    err.Error(),
+			Suggestion: fmt.Sprintf("Check that '%s' is a dsops provider plugin built for this platform", p.options.Command),
+		}
+	}
+	p.process = proc
+	return proc.client, nil
+}
+
+// check explains errors caused by the plugin process going away
+func (p *Provider) check(err error) error {
+	if err == nil || !errors.Is(err, sdk.ErrUnavailable) {
+		return err
+	}
+
+	p.mu.Lock()
+	proc := p.process
+	p.mu.Unlock()
+
+	details := err.Error()
+	if proc != nil {
+		// Give the exit a moment to be noticed, the connection usually
+		// breaks first
+		select {
+		case <-proc.done:
+		case <-time.After(100 * time.Millisecond):
+		}
+		if proc.exited() {
+			details = proc.exitReason()
+		}
+	}
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("Plugin for store '%s' stopped responding", p.name),
+		Details:    details,
+		Suggestion: "The plugin is restarted on the next call; if this repeats, run the plugin's own diagnostics or report it to its author",
+		Err:        err,
+	}
+}
+
+// process is one running plugin
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.Closer
+	conn   *grpc.ClientConn
+	client *sdk.Client
+	stderr *tail
+
+	done    chan struct{}
+	waitErr error
+}
+
+// start launches the plugin, completes the handshake and configures it
+func start(ctx context.Context, name string, config map[string]interface{}, options Options) (*process, error) {
+	path, err := exec.LookPath(options.Command)
+	if err != nil {
+		return nil, fmt.Errorf("plugin executable not found: %w", err)
+	}
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		return nil, err
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		_ = stdoutR.Close()
+		_ = stdoutW.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(path, options.Args...)
+	cmd.Env = append(os.Environ(),
+		sdk.MagicCookieKey+"="+sdk.MagicCookieValue,
+		sdk.ProtocolVersionsKey+"="+strconv.Itoa(sdk.ProtocolVersion),
+	)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdinR, stdoutW, stderrW
+
+	err = cmd.Start()
+	// The child holds its own copies now
+	_ = stdinR.Close()
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	if err != nil {
+		_ = stdinW.Close()
+		_ = stdoutR.Close()
+		_ = stderrR.Close()
+		return nil, fmt.Errorf("failed to start %s: %w", path, err)
+	}
+
+	proc := &process{cmd: cmd, stdin: stdinW, stderr: newTail(stderrLines), done: make(chan struct{})}
+	stderrDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(proc.stderr, stderrR)
+		_ = stderrR.Close()
+		close(stderrDone)
+	}()
+	go func() {
+		proc.waitErr = cmd.Wait()
+		// Collect the last of stderr for exitReason, unless a child of the
+		// plugin still holds it open
+		select {
+		case <-stderrDone:
+		case <-time.After(time.Second):
+		}
+		close(proc.done)
+	}()
+
+	handshake, err := proc.readHandshake(ctx, stdoutR)
+	if err != nil {
+		proc.stop()
+		return nil, err
+	}
+	if handshake.ProtocolVersion != sdk.ProtocolVersion {
+		proc.stop()
+		return nil, fmt.Errorf("plugin speaks protocol version %d, dsops speaks version %d", handshake.ProtocolVersion, sdk.ProtocolVersion)
+	}
+
+	target := "unix://" + handshake.Address
+	if handshake.Network == "tcp" {
+		target = "passthrough:///" + handshake.Address
+	}
+	proc.conn, err = grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		proc.stop()
+		return nil, fmt.Errorf("failed to connect to plugin: %w", err)
+	}
+
+	proc.client = sdk.NewClient(proc.conn, options.Timeout)
+	if err := proc.client.Configure(ctx, name, config); err != nil {
+		crashed := proc.exited()
+		proc.stop()
+		if crashed {
+			return nil, errors.New(proc.exitReason())
+		}
+		return nil, err
+	}
+	return proc, nil
+}
+
+// readHandshake waits for the handshake line, then keeps draining stdout so
+// a chatty plugin cannot block on a full pipe
+func (proc *process) readHandshake(ctx context.Context, stdout io.ReadCloser) (sdk.Handshake, error) {
+	lines := make(chan string, 1)
+	go func() {
+		defer func() { _ = stdout.Close() }()
+		reader := bufio.NewReader(stdout)
+		line, err := reader.ReadString('\n')
+		if err == nil {
+			lines <- line
+		}
+		close(lines)
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			// stdout closed without a handshake, so the plugin is exiting
+			<-proc.done
+			return sdk.Handshake{}, errors.New(proc.exitReason())
+		}
+		return sdk.ParseHandshake(line)
+	case <-proc.done:
+		return sdk.Handshake{}, errors.New(proc.exitReason())
+	case <-ctx.Done():
+		return sdk.Handshake{}, fmt.Errorf("plugin did not complete the handshake within %s", StartTimeout)
+	}
+}
+
+func (proc *process) exited() bool {
+	select {
+	case <-proc.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// exitReason describes how the process ended, with the end of its stderr
+func (proc *process) exitReason() string {
+	reason := "plugin exited"
+	if proc.waitErr != nil {
+		reason = fmt.Sprintf("plugin exited: %v", proc.waitErr)
+	}
+	if output := proc.stderr.String(); output != "" {
+		reason += "\n" + output
+	}
+	return reason
+}
+
+// stop asks the plugin to exit by closing its stdin, and kills it if it
+// does not within a few seconds
+func (proc *process) stop() {
+	if proc.conn != nil {
+		_ = proc.conn.Close()
+	}
+	_ = proc.stdin.Close()
+	select {
+	case <-proc.done:
+	case <-time.After(3 * time.Second):
+		_ = proc.cmd.Process.Kill()
+		<-proc.done
+	}
+}
+
+// tail keeps the last lines written to it
+type tail struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial string
+}
+
+func newTail(max int) *tail {
+	return &tail{max: max}
+}
+
+func (t *tail) Write(data []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	text := t.partial + string(data)
+	parts := strings.Split(text, "\n")
+	t.partial = parts[len(parts)-1]
+	t.lines = append(t.lines, parts[:len(parts)-1]...)
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+	return len(data), nil
+}
+
+func (t *tail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := t.lines
+	if t.partial != "" {
+		lines = append(append([]string{}, lines...), t.partial)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,222 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	sdk "github.com/systmms/dsops/pkg/plugin"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// testPluginEnv makes the test binary act as a plugin, so the tests run a
+// real plugin process without building one
+const testPluginEnv = "DSOPS_TEST_PLUGIN"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(testPluginEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "serve":
+		sdk.Serve(newTestProvider)
+	case "exit":
+		fmt.Fprintln(os.Stderr, "cannot reach the secrets backend")
+		os.Exit(3)
+	case "future":
+		fmt.Println("2|unix|/nonexistent.sock")
+		_, _ = io.Copy(io.Discard, os.Stdin)
+	case "silent":
+		_, _ = io.Copy(io.Discard, os.Stdin)
+	}
+	os.Exit(0)
+}
+
+type testProvider struct{ name string }
+
+func newTestProvider(name string, config map[string]interface{}) (provider.Provider, error) {
+	if config["token"] != "t0ken" {
+		return nil, errors.New("token is required")
+	}
+	return testProvider{name: name}, nil
+}
+
+func (p testProvider) Name() string { return p.name }
+
+func (p testProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{SupportsMetadata: true, AuthMethods: []string{"token"}}
+}
+
+func (p testProvider) Validate(ctx context.Context) error { return nil }
+
+func (p testProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	switch ref.Key {
+	case "crash":
+		fmt.Fprintln(os.Stderr, "fatal: backend returned garbage")
+		os.Exit(2)
+	case "slow":
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return provider.SecretValue{}, ctx.Err()
+		}
+	case "pid":
+		return provider.SecretValue{Value: fmt.Sprint(os.Getpid())}, nil
+	case "missing":
+		return provider.SecretValue{}, provider.NotFoundError{Provider: p.name, Key: ref.Key}
+	}
+	return provider.SecretValue{Value: "value-of-" + ref.Key}, nil
+}
+
+func (p testProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	return provider.Metadata{Exists: true}, nil
+}
+
+func newTestPlugin(t *testing.T, mode string, config map[string]interface{}, timeout time.Duration) *Provider {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin process tests use Unix sockets")
+	}
+	t.Setenv(testPluginEnv, mode)
+	p, err := NewProvider("corp", config, Options{Command: os.Args[0], Timeout: timeout})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestProvider_Process(t *testing.T) {
+	p := newTestPlugin(t, "serve", map[string]interface{}{"token": "t0ken"}, 10*time.Second)
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "db/password"})
+	require.NoError(t, err)
+	assert.Equal(t, "value-of-db/password", value.Value)
+
+	assert.Equal(t, "corp", p.Name())
+	assert.Equal(t, []string{"token"}, p.Capabilities().AuthMethods)
+	assert.NoError(t, p.Validate(ctx))
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	// Same process for every call
+	first, err := p.Resolve(ctx, provider.Reference{Key: "pid"})
+	require.NoError(t, err)
+	second, err := p.Resolve(ctx, provider.Reference{Key: "pid"})
+	require.NoError(t, err)
+	assert.Equal(t, first.Value, second.Value)
+	assert.NotEqual(t, fmt.Sprint(os.Getpid()), first.Value)
+
+	var rotator provider.Rotator = p
+	meta, err := rotator.GetRotationMetadata(ctx, provider.Reference{Key: "x"})
+	require.NoError(t, err)
+	assert.False(t, meta.SupportsRotation)
+}
+
+func TestProvider_Crash(t *testing.T) {
+	p := newTestPlugin(t, "serve", map[string]interface{}{"token": "t0ken"}, 10*time.Second)
+	ctx := context.Background()
+
+	before, err := p.Resolve(ctx, provider.Reference{Key: "pid"})
+	require.NoError(t, err)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "crash"})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "stopped responding")
+	assert.Contains(t, userErr.Details, "exit status 2")
+	assert.Contains(t, userErr.Details, "backend returned garbage")
+
+	// The next call starts a new process
+	after, err := p.Resolve(ctx, provider.Reference{Key: "pid"})
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Value, after.Value)
+}
+
+func TestProvider_Timeout(t *testing.T) {
+	p := newTestPlugin(t, "serve", map[string]interface{}{"token": "t0ken"}, 200*time.Millisecond)
+
+	start := time.Now()
+	_, err := p.Resolve(context.Background(), provider.Reference{Key: "slow"})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "fast"})
+	require.NoError(t, err)
+	assert.Equal(t, "value-of-fast", value.Value)
+}
+
+func TestProvider_StartErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		config map[string]interface{}
+		errMsg string
+	}{
+		{"bad_config", "serve", map[string]interface{}{}, "invalid plugin configuration: token is required"},
+		{"exits", "exit", nil, "cannot reach the secrets backend"},
+		{"protocol_mismatch", "future", nil, "protocol version 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlugin(t, tt.mode, tt.config, time.Second)
+			_, err := p.Resolve(context.Background(), provider.Reference{Key: "a"})
+			var userErr dserrors.UserError
+			require.ErrorAs(t, err, &userErr)
+			assert.Contains(t, userErr.Message, "Failed to start plugin for store 'corp'")
+			assert.Contains(t, userErr.Details, tt.errMsg)
+		})
+	}
+
+	t.Run("no_handshake", func(t *testing.T) {
+		p := newTestPlugin(t, "silent", nil, time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		_, err := p.Resolve(ctx, provider.Reference{Key: "a"})
+		assert.ErrorContains(t, err, "did not complete the handshake")
+	})
+
+	t.Run("missing_executable", func(t *testing.T) {
+		p, err := NewProvider("corp", nil, Options{Command: filepath.Join(t.TempDir(), "dsops-provider-none")})
+		require.NoError(t, err)
+		_, err = p.Resolve(context.Background(), provider.Reference{Key: "a"})
+		assert.ErrorContains(t, err, "plugin executable not found")
+	})
+
+	_, err := NewProvider("corp", nil, Options{})
+	var configErr dserrors.ConfigError
+	assert.ErrorAs(t, err, &configErr)
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("discovery checks the executable bit")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	write := func(dir, name string, mode os.FileMode) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode))
+	}
+	write(first, "dsops-provider-corp", 0755)
+	write(first, "dsops-provider-notes.txt", 0644)
+	write(first, "other-tool", 0755)
+	write(second, "dsops-provider-corp", 0755)
+	write(second, "dsops-provider-legacy", 0755)
+
+	plugins := Discover([]string{first, filepath.Join(first, "missing"), second})
+	assert.Equal(t, map[string]string{
+		"corp":   filepath.Join(first, "dsops-provider-corp"),
+		"legacy": filepath.Join(second, "dsops-provider-legacy"),
+	}, plugins)
+
+	t.Setenv(DirEnv, first+string(filepath.ListSeparator)+second)
+	assert.Equal(t, []string{first, second}, Dirs())
+}
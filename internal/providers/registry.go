@@ -1,9 +1,19 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/systmms/dsops/internal/agekey"
 	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/providers/consul"
+	"github.com/systmms/dsops/internal/providers/etcd"
+	"github.com/systmms/dsops/internal/providers/file"
+	"github.com/systmms/dsops/internal/providers/kubernetes"
+	"github.com/systmms/dsops/internal/providers/plugin"
+	"github.com/systmms/dsops/internal/providers/sops"
 	"github.com/systmms/dsops/internal/providers/vault"
 	"github.com/systmms/dsops/pkg/provider"
 )
@@ -11,6 +21,8 @@ import (
 // Registry manages provider creation and registration
 type Registry struct {
 	factories map[string]ProviderFactory
+	writable  map[string]bool   // Types whose providers implement provider.Writer
+	plugins   map[string]string // Plugin store types and their executables
 }
 
 // ProviderFactory creates a provider instance from configuration
@@ -20,6 +32,8 @@ type ProviderFactory func(name string, config map[string]interface{}) (provider.
 func NewRegistry() *Registry {
 	registry := &Registry{
 		factories: make(map[string]ProviderFactory),
+		writable:  make(map[string]bool),
+		plugins:   make(map[string]string),
 	}
 
 	// Register built-in providers
@@ -27,23 +41,42 @@ func NewRegistry() *Registry {
 	registry.RegisterFactory("mock", NewMockProviderFactory)
 	registry.RegisterFactory("json", NewJSONProviderFactory)
 	registry.RegisterFactory("bitwarden", NewBitwardenProviderFactory)
-	registry.RegisterFactory("aws.secretsmanager", NewAWSSecretsManagerProviderFactory)
-	registry.RegisterFactory("aws.ssm", NewAWSSSMProviderFactory)
+	registry.RegisterWriterFactory("aws.secretsmanager", NewAWSSecretsManagerProviderFactory)
+	registry.RegisterWriterFactory("aws.ssm", NewAWSSSMProviderFactory)
 	registry.RegisterFactory("aws.sts", NewAWSSTSProviderFactory)
 	registry.RegisterFactory("aws.sso", NewAWSSSOProviderFactory)
 	registry.RegisterFactory("aws", NewAWSUnifiedProviderFactory)
-	registry.RegisterFactory("gcp.secretmanager", NewGCPSecretManagerProviderFactory)
+	registry.RegisterWriterFactory("gcp.secretmanager", NewGCPSecretManagerProviderFactory)
 	registry.RegisterFactory("gcp", NewGCPUnifiedProviderFactory)
-	registry.RegisterFactory("azure.keyvault", NewAzureKeyVaultProviderFactory)
+	registry.RegisterWriterFactory("azure.keyvault", NewAzureKeyVaultProviderFactory)
 	registry.RegisterFactory("azure.identity", NewAzureIdentityProviderFactory)
 	registry.RegisterFactory("azure", NewAzureUnifiedProviderFactory)
 	registry.RegisterFactory("onepassword", NewOnePasswordProviderFactory)
-	registry.RegisterFactory("vault", NewVaultProviderFactory)
+	registry.RegisterWriterFactory("vault", NewVaultProviderFactory)
 	registry.RegisterFactory("doppler", NewDopplerProviderFactory)
-	registry.RegisterFactory("pass", NewPassProviderFactory)
-	registry.RegisterFactory("keychain", NewKeychainProviderFactory)
+	registry.RegisterWriterFactory("pass", NewPassProviderFactory)
+	registry.RegisterWriterFactory("keychain", NewKeychainProviderFactory)
 	registry.RegisterFactory("infisical", NewInfisicalProviderFactory)
 	registry.RegisterFactory("akeyless", NewAkeylessProviderFactory)
+	registry.RegisterWriterFactory("sops", NewSOPSProviderFactory)
+	registry.RegisterFactory("bitwarden.secretsmanager", NewBitwardenSMProviderFactory)
+	registry.RegisterFactory("onepassword.connect", NewOnePasswordConnectProviderFactory)
+	registry.RegisterFactory("onepassword.serviceaccount", NewOnePasswordServiceAccountProviderFactory)
+	registry.RegisterFactory("kubernetes", NewKubernetesProviderFactory)
+	registry.RegisterFactory("consul", NewConsulProviderFactory)
+	registry.RegisterFactory("etcd", NewEtcdProviderFactory)
+	registry.RegisterFactory("file.dotenv", NewFileDotenvProviderFactory)
+	registry.RegisterFactory("file.json", NewFileJSONProviderFactory)
+	registry.RegisterFactory("file.yaml", NewFileYAMLProviderFactory)
+
+	// Out-of-process plugins: "plugin" takes its executable from the
+	// command setting, discovered plugins cannot shadow built-in types
+	registry.plugins["plugin"] = ""
+	for storeType, path := range plugin.Discover(plugin.Dirs()) {
+		if _, builtin := registry.factories[storeType]; !builtin && storeType != "plugin" {
+			registry.plugins[storeType] = path
+		}
+	}
 
 	return registry
 }
@@ -51,10 +84,22 @@ func NewRegistry() *Registry {
 // RegisterFactory registers a provider factory for a given type
 func (r *Registry) RegisterFactory(providerType string, factory ProviderFactory) {
 	r.factories[providerType] = factory
+	delete(r.writable, providerType)
+}
+
+// RegisterWriterFactory registers a factory whose providers implement
+// provider.Writer, so the type is reported by IsWritable
+func (r *Registry) RegisterWriterFactory(providerType string, factory ProviderFactory) {
+	r.RegisterFactory(providerType, factory)
+	r.writable[providerType] = true
 }
 
 // CreateProvider creates a provider instance from configuration
 func (r *Registry) CreateProvider(name string, cfg config.ProviderConfig) (provider.Provider, error) {
+	if path, isPlugin := r.plugins[cfg.Type]; isPlugin {
+		return newPluginProvider(name, path, cfg)
+	}
+
 	factory, exists := r.factories[cfg.Type]
 	if !exists {
 		return nil, fmt.Errorf("unknown provider type: %s", cfg.Type)
@@ -65,17 +110,34 @@ func (r *Registry) CreateProvider(name string, cfg config.ProviderConfig) (provi
 
 // GetSupportedTypes returns a list of supported provider types
 func (r *Registry) GetSupportedTypes() []string {
-	types := make([]string, 0, len(r.factories))
+	types := make([]string, 0, len(r.factories)+len(r.plugins))
 	for providerType := range r.factories {
 		types = append(types, providerType)
 	}
+	for providerType := range r.plugins {
+		types = append(types, providerType)
+	}
 	return types
 }
 
 // IsSupported checks if a provider type is supported
 func (r *Registry) IsSupported(providerType string) bool {
 	_, exists := r.factories[providerType]
-	return exists
+	_, isPlugin := r.plugins[providerType]
+	return exists || isPlugin
+}
+
+// IsWritable reports whether stores of a type accept writes through
+// provider.Writer, without creating a provider. Plugin types are read-only.
+func (r *Registry) IsWritable(providerType string) bool {
+	return r.writable[providerType]
+}
+
+// PluginPath returns the executable of a plugin discovered in the plugin
+// directories, or false for built-in types and "plugin"
+func (r *Registry) PluginPath(providerType string) (string, bool) {
+	path, ok := r.plugins[providerType]
+	return path, ok && path != ""
 }
 
 // Factory functions for built-in providers
@@ -113,9 +175,11 @@ func NewMockProviderFactory(name string, config map[string]interface{}) (provide
 	return mockProvider, nil
 }
 
-// NewJSONProviderFactory creates a JSON provider factory
+// NewJSONProviderFactory creates a store for the deprecated "json" type, an
+// alias of "file.json"
 func NewJSONProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
-	return NewJSONProvider(name), nil
+	logging.New(false, false).Warn("store '%s' uses the deprecated type 'json'; rename it to 'file.json'", name)
+	return NewFileJSONProviderFactory(name, config)
 }
 
 // NewBitwardenProviderFactory creates a Bitwarden provider factory
@@ -187,6 +251,9 @@ func NewPassProviderFactory(name string, config map[string]interface{}) (provide
 
 // NewKeychainProviderFactory creates a keychain provider factory
 func NewKeychainProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	if err := validateKeychainConfig(parseKeychainConfig(config)); err != nil {
+		return nil, err
+	}
 	return NewKeychainProvider(name, config), nil
 }
 
@@ -199,3 +266,94 @@ func NewInfisicalProviderFactory(name string, config map[string]interface{}) (pr
 func NewAkeylessProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
 	return NewAkeylessProvider(name, config)
 }
+
+// NewBitwardenSMProviderFactory creates a Bitwarden Secrets Manager provider factory
+func NewBitwardenSMProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return NewBitwardenSMProvider(name, config)
+}
+
+// NewOnePasswordConnectProviderFactory creates a 1Password Connect provider factory
+func NewOnePasswordConnectProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return NewOnePasswordConnectProvider(name, config)
+}
+
+// NewOnePasswordServiceAccountProviderFactory creates a 1Password service account provider factory
+func NewOnePasswordServiceAccountProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return NewOnePasswordServiceAccountProvider(name, config)
+}
+
+// NewSOPSProviderFactory creates a SOPS/age encrypted file provider factory.
+// The age identity may be kept in the OS keychain via age_key_keychain.
+func NewSOPSProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return sops.NewProvider(name, config, ageKeychainLookup(name))
+}
+
+// ageKeychainLookup reads age identities kept in the OS keychain for the
+// age_key_keychain setting of sops and file stores
+func ageKeychainLookup(name string) agekey.KeychainLookup {
+	return func(ctx context.Context, ref string) (string, error) {
+		value, err := NewKeychainProvider(name, nil).Resolve(ctx, provider.Reference{Key: ref})
+		if err != nil {
+			return "", err
+		}
+		return value.Value, nil
+	}
+}
+
+// NewKubernetesProviderFactory creates a Kubernetes Secrets provider factory
+func NewKubernetesProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return kubernetes.NewProvider(name, config)
+}
+
+// NewConsulProviderFactory creates a Consul KV provider factory
+func NewConsulProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return consul.NewProvider(name, config)
+}
+
+// NewEtcdProviderFactory creates an etcd provider factory
+func NewEtcdProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return etcd.NewProvider(name, config)
+}
+
+// NewFileDotenvProviderFactory creates a .env file store factory
+func NewFileDotenvProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return file.NewProviderWithKeychain(name, file.FormatDotenv, config, ageKeychainLookup(name))
+}
+
+// NewFileJSONProviderFactory creates a JSON file store factory
+func NewFileJSONProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return file.NewProviderWithKeychain(name, file.FormatJSON, config, ageKeychainLookup(name))
+}
+
+// NewFileYAMLProviderFactory creates a YAML file store factory
+func NewFileYAMLProviderFactory(name string, config map[string]interface{}) (provider.Provider, error) {
+	return file.NewProviderWithKeychain(name, file.FormatYAML, config, ageKeychainLookup(name))
+}
+
+// newPluginProvider creates a provider served by a plugin process. Type
+// "plugin" names the executable in command (with optional args); discovered
+// plugins come with their path. The remaining settings go to the plugin, and
+// timeout_ms bounds every call to it.
+func newPluginProvider(name, path string, cfg config.ProviderConfig) (provider.Provider, error) {
+	settings := make(map[string]interface{}, len(cfg.Config))
+	for k, v := range cfg.Config {
+		settings[k] = v
+	}
+
+	options := plugin.Options{
+		Command: path,
+		Timeout: time.Duration(cfg.GetProviderTimeout()) * time.Millisecond,
+	}
+	if path == "" {
+		command, _ := settings["command"].(string)
+		options.Command = command
+		if args, ok := settings["args"].([]interface{}); ok {
+			for _, arg := range args {
+				options.Args = append(options.Args, fmt.Sprint(arg))
+			}
+		}
+		delete(settings, "command")
+		delete(settings, "args")
+	}
+	return plugin.NewProvider(name, settings, options)
+}
@@ -1,12 +1,18 @@
 package providers_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/systmms/dsops/internal/config"
+	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/internal/providers/sops"
+	"github.com/systmms/dsops/internal/providers/vault"
 	"github.com/systmms/dsops/pkg/provider"
 )
 
@@ -121,6 +127,69 @@ func TestRegistryCreateProvider(t *testing.T) {
 	}
 }
 
+// TestRegistryJSONAlias validates that the deprecated json type reads files
+// like file.json
+func TestRegistryJSONAlias(t *testing.T) {
+	t.Parallel()
+
+	registry := providers.NewRegistry()
+
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"db": {"password": "s3cret"}}`), 0600))
+
+	p, err := registry.CreateProvider("legacy", config.ProviderConfig{
+		Type:   "json",
+		Config: map[string]interface{}{"path": path},
+	})
+	require.NoError(t, err)
+
+	value, err := p.Resolve(context.Background(), provider.Reference{Key: "db.password"})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value.Value)
+
+	// The hard-coded test data is gone
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "db-config"})
+	assert.Error(t, err)
+
+	// Like file.json, the alias needs a file
+	_, err = registry.CreateProvider("legacy", config.ProviderConfig{Type: "json", Config: map[string]interface{}{}})
+	assert.Error(t, err)
+}
+
+// TestRegistryFileStoresFromAnotherDirectory validates that file.json and its
+// json alias read the same file when dsops runs outside the config directory
+func TestRegistryFileStoresFromAnotherDirectory(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "secrets.json"), []byte(`{"token": "abc"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "dsops.yaml"), []byte(`version: 0
+secretStores:
+  current:
+    type: file.json
+    path: secrets.json
+  legacy:
+    type: json
+    path: secrets.json
+envs: {}
+`), 0600))
+
+	t.Chdir(t.TempDir())
+
+	cfg := &config.Config{Path: filepath.Join(configDir, "dsops.yaml"), Logger: logging.New(false, true)}
+	require.NoError(t, cfg.Load())
+
+	registry := providers.NewRegistry()
+	for _, store := range []string{"current", "legacy"} {
+		storeConfig, err := cfg.GetProvider(store)
+		require.NoError(t, err)
+		p, err := registry.CreateProvider(store, storeConfig)
+		require.NoError(t, err)
+
+		value, err := p.Resolve(context.Background(), provider.Reference{Key: "token"})
+		require.NoError(t, err, store)
+		assert.Equal(t, "abc", value.Value, store)
+	}
+}
+
 // TestRegistryGetSupportedTypes validates listing supported types
 func TestRegistryGetSupportedTypes(t *testing.T) {
 	t.Parallel()
@@ -145,6 +214,11 @@ func TestRegistryGetSupportedTypes(t *testing.T) {
 		"vault",
 		"doppler",
 		"pass",
+		"consul",
+		"etcd",
+		"file.dotenv",
+		"file.json",
+		"file.yaml",
 	}
 
 	for _, expectedType := range expectedTypes {
@@ -183,6 +257,38 @@ func TestRegistryRegisterFactory(t *testing.T) {
 	assert.True(t, customFactoryCalled, "Custom factory should have been called")
 }
 
+// The providers behind the types registered with RegisterWriterFactory
+var (
+	_ provider.Writer = (*providers.AWSSecretsManagerProvider)(nil)
+	_ provider.Writer = (*providers.AWSSSMProvider)(nil)
+	_ provider.Writer = (*providers.GCPSecretManagerProvider)(nil)
+	_ provider.Writer = (*providers.AzureKeyVaultProvider)(nil)
+	_ provider.Writer = (*vault.VaultProvider)(nil)
+	_ provider.Writer = (*providers.PassProvider)(nil)
+	_ provider.Writer = (*providers.KeychainProvider)(nil)
+	_ provider.Writer = (*sops.Provider)(nil)
+)
+
+// TestRegistryIsWritable validates writability is known from the store type
+func TestRegistryIsWritable(t *testing.T) {
+	t.Parallel()
+
+	registry := providers.NewRegistry()
+	for _, storeType := range []string{"aws.secretsmanager", "aws.ssm", "gcp.secretmanager", "azure.keyvault", "vault", "pass", "keychain", "sops"} {
+		assert.True(t, registry.IsWritable(storeType), storeType)
+	}
+	for _, storeType := range []string{"literal", "json", "aws", "doppler", "file.json", "plugin", "unknown"} {
+		assert.False(t, registry.IsWritable(storeType), storeType)
+	}
+
+	registry.RegisterWriterFactory("custom", providers.NewLiteralProviderFactory)
+	assert.True(t, registry.IsWritable("custom"))
+
+	// Replacing the factory drops what the old one declared
+	registry.RegisterFactory("vault", providers.NewLiteralProviderFactory)
+	assert.False(t, registry.IsWritable("vault"))
+}
+
 // TestRegistryFactoryOverride validates factory replacement
 func TestRegistryFactoryOverride(t *testing.T) {
 	t.Parallel()
@@ -274,3 +380,41 @@ func TestRegistryErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestRegistryPlugins validates plugin stores, declared and discovered
+func TestRegistryPlugins(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dsops-provider-corp")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dsops-provider-vault"), []byte("#!/bin/sh\n"), 0755))
+	t.Setenv("DSOPS_PLUGIN_DIR", dir)
+
+	registry := providers.NewRegistry()
+	assert.True(t, registry.IsSupported("plugin"))
+	assert.True(t, registry.IsSupported("corp"))
+	assert.Contains(t, registry.GetSupportedTypes(), "corp")
+
+	found, ok := registry.PluginPath("corp")
+	assert.True(t, ok)
+	assert.Equal(t, path, found)
+	_, ok = registry.PluginPath("vault")
+	assert.False(t, ok, "plugins cannot shadow built-in types")
+	_, ok = registry.PluginPath("plugin")
+	assert.False(t, ok)
+
+	// Plugins start on first use, so creating them does not run anything
+	p, err := registry.CreateProvider("corp-store", config.ProviderConfig{Type: "corp"})
+	require.NoError(t, err)
+	assert.Equal(t, "corp-store", p.Name())
+
+	p, err = registry.CreateProvider("custom", config.ProviderConfig{
+		Type:   "plugin",
+		Config: map[string]interface{}{"command": path, "args": []interface{}{"--region", "eu"}, "endpoint": "https://corp"},
+	})
+	require.NoError(t, err)
+	_, isRotator := p.(provider.Rotator)
+	assert.True(t, isRotator)
+
+	_, err = registry.CreateProvider("custom", config.ProviderConfig{Type: "plugin"})
+	assert.ErrorContains(t, err, "need a command")
+}
@@ -0,0 +1,748 @@
+package sops
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/systmms/dsops/internal/atomicfile"
+	"gopkg.in/yaml.v3"
+)
+
+// metadataKey is the top-level key holding the SOPS metadata
+const metadataKey = "sops"
+
+// ivSize is the AES-GCM nonce size SOPS uses for new values
+const ivSize = 32
+
+// macOnlyEncryptedInit seeds the MAC of files with mac_only_encrypted, so
+// their MAC differs from one over every value
+var macOnlyEncryptedInit = []byte{0x8a, 0x3f, 0xd2, 0xad, 0x54, 0xce, 0x66, 0x52, 0x7b, 0x10, 0x34, 0xf3, 0xd1, 0x47, 0xbe, 0xb, 0xb, 0x97, 0x5b, 0x3b, 0xf4, 0x4f, 0x72, 0xc6, 0xfd, 0xad, 0xec, 0x81, 0x76, 0xf2, 0x7d, 0x69}
+
+var encryptedValuePattern = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]`)
+
+// document is a SOPS encrypted YAML or JSON file. Values stay encrypted in
+// the node tree; keys, structure and comments are kept as written so the
+// file can be saved with only the changed value and MAC differing.
+type document struct {
+	path    string
+	format  string // "yaml" or "json"
+	root    *yaml.Node
+	data    *yaml.Node // Top-level mapping
+	meta    metadata
+	modTime time.Time
+	size    int64
+}
+
+// metadata is the part of the "sops" section dsops uses
+type metadata struct {
+	node *yaml.Node
+
+	AgeKeys           []ageKey
+	LastModified      string
+	MAC               string
+	UnencryptedSuffix string
+	EncryptedSuffix   string
+	UnencryptedRegex  string
+	EncryptedRegex    string
+	CommentRegex      bool // encrypted_comment_regex or unencrypted_comment_regex is set
+	MACOnlyEncrypted  bool
+	KeyGroups         int
+	ShamirThreshold   int
+}
+
+// ageKey is a data key encrypted to one age recipient
+type ageKey struct {
+	Recipient string `yaml:"recipient"`
+	Enc       string `yaml:"enc"`
+}
+
+// formatFor returns the file format from an explicit setting or the extension
+func formatFor(path, format string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// loadDocument reads and parses a SOPS file without decrypting anything
+func loadDocument(path, format string) (*document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseDocument(content, formatFor(path, format))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	doc.path = path
+	doc.modTime = info.ModTime()
+	doc.size = info.Size()
+	return doc, nil
+}
+
+func parseDocument(content []byte, format string) (*document, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var root yaml.Node
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+	var extra yaml.Node
+	if err := decoder.Decode(&extra); err == nil {
+		return nil, fmt.Errorf("files with several YAML documents are not supported")
+	}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping at the top level")
+	}
+
+	doc := &document{format: format, root: &root, data: root.Content[0]}
+	metaNode := mappingValue(doc.data, metadataKey)
+	if metaNode == nil {
+		return nil, fmt.Errorf("no sops metadata found; is the file encrypted with sops?")
+	}
+	meta, err := parseMetadata(metaNode)
+	if err != nil {
+		return nil, err
+	}
+	doc.meta = meta
+	return doc, nil
+}
+
+func parseMetadata(node *yaml.Node) (metadata, error) {
+	var raw struct {
+		Age               []ageKey `yaml:"age"`
+		LastModified      string   `yaml:"lastmodified"`
+		MAC               string   `yaml:"mac"`
+		UnencryptedSuffix string   `yaml:"unencrypted_suffix"`
+		EncryptedSuffix   string   `yaml:"encrypted_suffix"`
+		UnencryptedRegex  string   `yaml:"unencrypted_regex"`
+		EncryptedRegex    string   `yaml:"encrypted_regex"`
+		UnencryptedCRegex string   `yaml:"unencrypted_comment_regex"`
+		EncryptedCRegex   string   `yaml:"encrypted_comment_regex"`
+		MACOnlyEncrypted  bool     `yaml:"mac_only_encrypted"`
+		ShamirThreshold   int      `yaml:"shamir_threshold"`
+		KeyGroups         []struct {
+			Age []ageKey `yaml:"age"`
+		} `yaml:"key_groups"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return metadata{}, fmt.Errorf("invalid sops metadata: %w", err)
+	}
+
+	meta := metadata{
+		node:              node,
+		AgeKeys:           raw.Age,
+		LastModified:      raw.LastModified,
+		MAC:               raw.MAC,
+		UnencryptedSuffix: raw.UnencryptedSuffix,
+		EncryptedSuffix:   raw.EncryptedSuffix,
+		UnencryptedRegex:  raw.UnencryptedRegex,
+		EncryptedRegex:    raw.EncryptedRegex,
+		CommentRegex:      raw.UnencryptedCRegex != "" || raw.EncryptedCRegex != "",
+		MACOnlyEncrypted:  raw.MACOnlyEncrypted,
+		KeyGroups:         len(raw.KeyGroups),
+		ShamirThreshold:   raw.ShamirThreshold,
+	}
+	for _, group := range raw.KeyGroups {
+		meta.AgeKeys = append(meta.AgeKeys, group.Age...)
+	}
+	return meta, nil
+}
+
+// lookup returns the node at a path. Sequence items are addressed by index.
+func (d *document) lookup(path []string) *yaml.Node {
+	node := d.data
+	for i, part := range path {
+		switch node.Kind {
+		case yaml.MappingNode:
+			if i == 0 && part == metadataKey {
+				return nil
+			}
+			node = mappingValue(node, part)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[index]
+		default:
+			return nil
+		}
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// aadPath returns the key names along path, which SOPS authenticates each
+// value with. Sequence indexes are not part of it.
+func (d *document) aadPath(path []string) []string {
+	var keys []string
+	node := d.data
+	for _, part := range path {
+		if node == nil {
+			keys = append(keys, part)
+			continue
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			keys = append(keys, part)
+			node = mappingValue(node, part)
+		case yaml.SequenceNode:
+			index, _ := strconv.Atoi(part)
+			if index >= 0 && index < len(node.Content) {
+				node = node.Content[index]
+			} else {
+				node = nil
+			}
+		default:
+			node = nil
+		}
+	}
+	return keys
+}
+
+// leaf is a scalar value in the document
+type leaf struct {
+	node *yaml.Node
+	path []string // Lookup path, including sequence indexes
+	keys []string // Key names only, for the additional data
+}
+
+// leaves returns every scalar below node in document order
+func leaves(node *yaml.Node, path, keys []string, top bool) []leaf {
+	var out []leaf
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if top && key == metadataKey {
+				continue
+			}
+			out = append(out, leaves(node.Content[i+1], appendPath(path, key), appendPath(keys, key), false)...)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			out = append(out, leaves(item, appendPath(path, strconv.Itoa(i)), keys, false)...)
+		}
+	case yaml.ScalarNode:
+		out = append(out, leaf{node: node, path: path, keys: keys})
+	}
+	return out
+}
+
+func appendPath(path []string, part string) []string {
+	out := make([]string, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, part)
+}
+
+// shouldEncrypt applies the file's suffix and regex rules to a key path
+func (m metadata) shouldEncrypt(keys []string) bool {
+	encrypted := true
+	if m.UnencryptedSuffix != "" {
+		for _, k := range keys {
+			if strings.HasSuffix(k, m.UnencryptedSuffix) {
+				encrypted = false
+				break
+			}
+		}
+	}
+	if m.EncryptedSuffix != "" {
+		encrypted = false
+		for _, k := range keys {
+			if strings.HasSuffix(k, m.EncryptedSuffix) {
+				encrypted = true
+				break
+			}
+		}
+	}
+	if m.UnencryptedRegex != "" {
+		for _, k := range keys {
+			if matched, _ := regexp.MatchString(m.UnencryptedRegex, k); matched {
+				encrypted = false
+				break
+			}
+		}
+	}
+	if m.EncryptedRegex != "" {
+		encrypted = false
+		for _, k := range keys {
+			if matched, _ := regexp.MatchString(m.EncryptedRegex, k); matched {
+				encrypted = true
+				break
+			}
+		}
+	}
+	return encrypted
+}
+
+// plainValue is a decrypted or unencrypted scalar
+type plainValue struct {
+	text      string // As dsops returns it
+	macBytes  []byte // As SOPS hashes it; nil for null and comments
+	encrypted bool
+	typed     interface{} // int, float64 or bool for encrypted values of those types
+}
+
+// decryptLeaf decrypts a scalar, or converts an unencrypted one
+func decryptLeaf(l leaf, key []byte) (plainValue, error) {
+	node := l.node
+	if !isEncrypted(node.Value) {
+		text, macBytes, err := scalarValue(node)
+		return plainValue{text: text, macBytes: macBytes}, err
+	}
+
+	plaintext, datatype, err := decryptValue(node.Value, key, additionalData(l.keys))
+	if err != nil {
+		return plainValue{}, fmt.Errorf("failed to decrypt %s: %w", strings.Join(l.path, "."), err)
+	}
+
+	value := plainValue{text: string(plaintext), macBytes: plaintext, encrypted: true}
+	switch datatype {
+	case "str", "bytes":
+	case "int":
+		n, err := strconv.Atoi(value.text)
+		if err != nil {
+			return plainValue{}, fmt.Errorf("invalid int at %s: %w", strings.Join(l.path, "."), err)
+		}
+		value.macBytes = []byte(strconv.Itoa(n))
+		value.typed = n
+	case "float":
+		f, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return plainValue{}, fmt.Errorf("invalid float at %s: %w", strings.Join(l.path, "."), err)
+		}
+		value.macBytes = []byte(strconv.FormatFloat(f, 'f', -1, 64))
+		value.typed = f
+	case "bool":
+		b, err := strconv.ParseBool(value.text)
+		if err != nil {
+			return plainValue{}, fmt.Errorf("invalid bool at %s: %w", strings.Join(l.path, "."), err)
+		}
+		value.text = strconv.FormatBool(b)
+		value.macBytes = pythonBool(b)
+		value.typed = b
+	case "time":
+		var t time.Time
+		if err := t.UnmarshalText(plaintext); err != nil {
+			return plainValue{}, fmt.Errorf("invalid time at %s: %w", strings.Join(l.path, "."), err)
+		}
+		value.macBytes, _ = t.MarshalText()
+	case "comment":
+		value.macBytes = nil
+	default:
+		return plainValue{}, fmt.Errorf("unknown value type %q at %s", datatype, strings.Join(l.path, "."))
+	}
+	return value, nil
+}
+
+// scalarValue returns an unencrypted scalar and the bytes SOPS hashes for it
+func scalarValue(node *yaml.Node) (string, []byte, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return "", nil, nil
+	case "!!int":
+		var n int
+		if err := node.Decode(&n); err != nil {
+			return "", nil, err
+		}
+		return node.Value, []byte(strconv.Itoa(n)), nil
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return "", nil, err
+		}
+		return node.Value, []byte(strconv.FormatFloat(f, 'f', -1, 64)), nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return "", nil, err
+		}
+		return strconv.FormatBool(b), pythonBool(b), nil
+	case "!!timestamp":
+		var t time.Time
+		if err := node.Decode(&t); err != nil {
+			return node.Value, []byte(node.Value), nil
+		}
+		text, _ := t.MarshalText()
+		return node.Value, text, nil
+	default:
+		return node.Value, []byte(node.Value), nil
+	}
+}
+
+// pythonBool formats a bool the way SOPS hashes it, for compatibility with
+// the original Python implementation
+func pythonBool(b bool) []byte {
+	if b {
+		return []byte("True")
+	}
+	return []byte("False")
+}
+
+func isEncrypted(value string) bool {
+	return encryptedValuePattern.MatchString(value)
+}
+
+func additionalData(keys []string) string {
+	return strings.Join(keys, ":") + ":"
+}
+
+// decryptValue opens an ENC[AES256_GCM,...] value
+func decryptValue(value string, key []byte, aad string) ([]byte, string, error) {
+	matches := encryptedValuePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, "", fmt.Errorf("value is not in sops format")
+	}
+
+	var parts [3][]byte
+	for i := range parts {
+		decoded, err := base64.StdEncoding.DecodeString(matches[i+1])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 in encrypted value: %w", err)
+		}
+		parts[i] = decoded
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(aad))
+	if err != nil {
+		return nil, "", fmt.Errorf("authentication failed; wrong key or tampered value")
+	}
+	return plaintext, matches[4], nil
+}
+
+// encryptValue seals a string value in the SOPS format
+func encryptValue(plaintext string, key []byte, aad string) (string, error) {
+	if plaintext == "" {
+		return "", nil // SOPS leaves empty strings as they are
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, ivSize)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", fmt.Errorf("failed to generate iv: %w", err)
+	}
+
+	sealed := gcm.Seal(nil, iv, []byte(plaintext), []byte(aad))
+	tagStart := len(sealed) - gcm.Overhead()
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]",
+		base64.StdEncoding.EncodeToString(sealed[:tagStart]),
+		base64.StdEncoding.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(sealed[tagStart:])), nil
+}
+
+// decryptAll decrypts every value and checks the file's MAC. The result maps
+// dotted lookup paths to values.
+func (d *document) decryptAll(key []byte) (map[string]plainValue, error) {
+	values := make(map[string]plainValue)
+	hash := sha512.New()
+	if d.meta.MACOnlyEncrypted {
+		hash.Write(macOnlyEncryptedInit)
+	}
+
+	for _, l := range leaves(d.data, nil, nil, true) {
+		value, err := decryptLeaf(l, key)
+		if err != nil {
+			return nil, err
+		}
+		if value.macBytes != nil && (!d.meta.MACOnlyEncrypted || value.encrypted) {
+			hash.Write(value.macBytes)
+		}
+		values[strings.Join(l.path, ".")] = value
+	}
+
+	if d.meta.MAC == "" {
+		return nil, fmt.Errorf("file has no MAC")
+	}
+	storedMAC, _, err := decryptValue(d.meta.MAC, key, d.meta.LastModified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt MAC: %w", err)
+	}
+	if computed := fmt.Sprintf("%X", hash.Sum(nil)); string(storedMAC) != computed {
+		return nil, fmt.Errorf("MAC mismatch; the file was modified without sops")
+	}
+	return values, nil
+}
+
+// seal recomputes the MAC after a change and stamps lastmodified
+func (d *document) seal(key []byte) error {
+	hash := sha512.New()
+	if d.meta.MACOnlyEncrypted {
+		hash.Write(macOnlyEncryptedInit)
+	}
+	for _, l := range leaves(d.data, nil, nil, true) {
+		value, err := decryptLeaf(l, key)
+		if err != nil {
+			return err
+		}
+		if value.macBytes != nil && (!d.meta.MACOnlyEncrypted || value.encrypted) {
+			hash.Write(value.macBytes)
+		}
+	}
+
+	lastModified := time.Now().UTC().Format(time.RFC3339)
+	mac, err := encryptValue(fmt.Sprintf("%X", hash.Sum(nil)), key, lastModified)
+	if err != nil {
+		return err
+	}
+
+	setMappingValue(d.meta.node, "lastmodified", lastModified)
+	setMappingValue(d.meta.node, "mac", mac)
+	d.meta.LastModified = lastModified
+	d.meta.MAC = mac
+	return nil
+}
+
+// set stores value at path, creating mappings as needed
+func (d *document) set(path []string, value string, key []byte) error {
+	parent := d.data
+	for i, part := range path[:len(path)-1] {
+		next := d.lookup(path[:i+1])
+		if next == nil {
+			if parent.Kind != yaml.MappingNode {
+				return fmt.Errorf("cannot add %q inside a list", part)
+			}
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			parent.Content = append(parent.Content, stringNode(part), next)
+		}
+		if next.Kind != yaml.MappingNode && next.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s is a value, not a mapping", strings.Join(path[:i+1], "."))
+		}
+		parent = next
+	}
+
+	stored := value
+	if d.meta.shouldEncrypt(d.aadPath(path)) {
+		var err error
+		if stored, err = encryptValue(value, key, additionalData(d.aadPath(path))); err != nil {
+			return err
+		}
+	}
+
+	if existing := d.lookup(path); existing != nil {
+		if existing.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s is a mapping or list; set its values individually", strings.Join(path, "."))
+		}
+		existing.Kind, existing.Tag, existing.Value, existing.Style = yaml.ScalarNode, "!!str", stored, 0
+		return nil
+	}
+	if parent.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot add %q inside a list", path[len(path)-1])
+	}
+	parent.Content = append(parent.Content, stringNode(path[len(path)-1]), stringNode(stored))
+	return nil
+}
+
+// remove deletes the value or subtree at path. Reports whether it existed.
+func (d *document) remove(path []string) bool {
+	parent := d.data
+	if len(path) > 1 {
+		parent = d.lookup(path[:len(path)-1])
+	}
+	if parent == nil {
+		return false
+	}
+
+	last := path[len(path)-1]
+	switch parent.Kind {
+	case yaml.MappingNode:
+		if parent == d.data && last == metadataKey {
+			return false
+		}
+		for i := 0; i+1 < len(parent.Content); i += 2 {
+			if parent.Content[i].Value == last {
+				parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+				return true
+			}
+		}
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(last)
+		if err == nil && index >= 0 && index < len(parent.Content) {
+			parent.Content = append(parent.Content[:index], parent.Content[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// encode serializes the document the way sops writes it: YAML with four
+// space indentation, JSON with tabs
+func (d *document) encode() ([]byte, error) {
+	if d.format == "json" {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, d.data); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "\t"); err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(d.root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// save writes the document atomically, keeping the file's permissions
+func (d *document) save() error {
+	content, err := d.encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", d.path, err)
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(d.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return atomicfile.Write(d.path, content, mode)
+}
+
+// writeJSON writes a node tree as compact JSON, keeping key order
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(node.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!int", "!!float", "!!bool":
+			buf.WriteString(node.Value)
+		default:
+			value, _ := json.Marshal(node.Value)
+			buf.Write(value)
+		}
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	default:
+		return fmt.Errorf("unsupported node kind %d", node.Kind)
+	}
+	return nil
+}
+
+// toJSONValue converts a subtree to plain values for JSON output, using the
+// decrypted values
+func toJSONValue(node *yaml.Node, path []string, values map[string]plainValue) interface{} {
+	switch node.Kind {
+	case yaml.MappingNode:
+		out := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			out[key] = toJSONValue(node.Content[i+1], appendPath(path, key), values)
+		}
+		return out
+	case yaml.SequenceNode:
+		out := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			out[i] = toJSONValue(item, appendPath(path, strconv.Itoa(i)), values)
+		}
+		return out
+	default:
+		value := values[strings.Join(path, ".")]
+		if value.typed != nil {
+			return value.typed
+		}
+		if !value.encrypted {
+			var plain interface{}
+			if err := node.Decode(&plain); err == nil {
+				return plain
+			}
+		}
+		return value.text
+	}
+}
+
+// mappingValue returns the value for key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets a string value in a mapping node
+func setMappingValue(node *yaml.Node, key, value string) {
+	if existing := mappingValue(node, key); existing != nil {
+		existing.Kind, existing.Tag, existing.Value = yaml.ScalarNode, "!!str", value
+		return
+	}
+	node.Content = append(node.Content, stringNode(key), stringNode(value))
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
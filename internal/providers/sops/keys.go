@@ -0,0 +1,67 @@
+package sops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	"github.com/systmms/dsops/internal/agekey"
+)
+
+// KeychainLookup reads a secret from the OS keychain by "service/account"
+type KeychainLookup = agekey.KeychainLookup
+
+// identities collects the age identities available to the store; see
+// agekey.Load for the sources and their order
+func (p *Provider) identities(ctx context.Context) ([]age.Identity, error) {
+	identities, sources, err := agekey.Load(ctx, agekey.Sources{
+		KeyFile:  p.config.AgeKeyFile,
+		Keychain: p.config.AgeKeyKeychain,
+		Lookup:   p.keychain,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Debug("Loaded %d age identities from %s", len(identities), strings.Join(sources, ", "))
+	return identities, nil
+}
+
+// dataKey decrypts the file's data key with the first matching identity
+func (p *Provider) dataKey(ctx context.Context, doc *document) ([]byte, error) {
+	if doc.meta.KeyGroups > 1 || doc.meta.ShamirThreshold > 1 {
+		return nil, fmt.Errorf("files split across several key groups (Shamir) are not supported")
+	}
+	if len(doc.meta.AgeKeys) == 0 {
+		return nil, fmt.Errorf("file has no age recipients; only age-encrypted sops files are supported")
+	}
+
+	identities, err := p.identities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range doc.meta.AgeKeys {
+		reader, err := age.Decrypt(armor.NewReader(strings.NewReader(key.Enc)), identities...)
+		if err != nil {
+			continue
+		}
+		dataKey, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data key for %s: %w", key.Recipient, err)
+		}
+		if len(dataKey) != 32 {
+			return nil, fmt.Errorf("data key for %s has invalid length %d", key.Recipient, len(dataKey))
+		}
+		return dataKey, nil
+	}
+
+	recipients := make([]string, len(doc.meta.AgeKeys))
+	for i, key := range doc.meta.AgeKeys {
+		recipients[i] = key.Recipient
+	}
+	return nil, fmt.Errorf("none of the age identities can decrypt the file; it is encrypted for %s", strings.Join(recipients, ", "))
+}
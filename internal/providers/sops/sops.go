@@ -0,0 +1,355 @@
+// Package sops implements a secret store for files encrypted with SOPS and
+// age, such as secrets.enc.yaml kept next to the code that uses them.
+//
+// Files are decrypted in process, fully offline: the data key is unwrapped
+// with a local age identity and each value is opened with AES-GCM. Values are
+// addressed by dotted path ("database.password", "hosts.0"). Writes encrypt
+// the new value with the file's data key and update the MAC, so the result
+// can still be read and edited with sops.
+package sops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the sops store configuration
+type Config struct {
+	File           string `yaml:"file"`             // Encrypted file, e.g. secrets.enc.yaml
+	Format         string `yaml:"format"`           // yaml or json (default: from the file extension)
+	AgeKeyFile     string `yaml:"age_key_file"`     // age identity file
+	AgeKeyKeychain string `yaml:"age_key_keychain"` // Keychain item holding the age identity, as service/account
+}
+
+// Provider reads and writes values in a SOPS encrypted file
+type Provider struct {
+	name     string
+	config   Config
+	logger   *logging.Logger
+	keychain KeychainLookup
+
+	mu     sync.Mutex
+	cached *decrypted
+}
+
+// decrypted is a loaded file with its data key and plaintext values
+type decrypted struct {
+	doc    *document
+	key    []byte
+	values map[string]plainValue
+}
+
+// NewProvider creates a sops store. keychain reads age_key_keychain and may
+// be nil when no keychain is available.
+func NewProvider(name string, configMap map[string]interface{}, keychain KeychainLookup) (*Provider, error) {
+	var config Config
+	if file, ok := configMap["file"].(string); ok {
+		config.File = file
+	}
+	if format, ok := configMap["format"].(string); ok {
+		config.Format = strings.ToLower(format)
+	}
+	if keyFile, ok := configMap["age_key_file"].(string); ok {
+		config.AgeKeyFile = keyFile
+	}
+	if item, ok := configMap["age_key_keychain"].(string); ok {
+		config.AgeKeyKeychain = item
+	}
+
+	if config.File == "" {
+		return nil, dserrors.ConfigError{
+			Field:      "file",
+			Message:    "sops store requires an encrypted file",
+			Suggestion: "Set 'file' to the encrypted file, e.g. file: secrets.enc.yaml",
+		}
+	}
+	if config.Format != "" && config.Format != "yaml" && config.Format != "json" {
+		return nil, dserrors.ConfigError{
+			Field:      "format",
+			Value:      config.Format,
+			Message:    "unsupported sops file format",
+			Suggestion: "Supported formats: yaml, json",
+		}
+	}
+
+	return &Provider{
+		name:     name,
+		config:   config,
+		logger:   logging.New(false, false),
+		keychain: keychain,
+	}, nil
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the provider's capabilities
+func (p *Provider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{
+		SupportsVersioning: false, // History lives in version control
+		SupportsMetadata:   true,
+		SupportsWatching:   false,
+		SupportsBinary:     false,
+		RequiresAuth:       true, // Requires an age identity
+		AuthMethods:        []string{"age"},
+	}
+}
+
+// Validate checks that the file can be decrypted and its MAC verified
+func (p *Provider) Validate(ctx context.Context) error {
+	_, err := p.load(ctx)
+	return err
+}
+
+// Resolve returns the value at a dotted path. A path to a mapping or list
+// returns it as JSON.
+func (p *Provider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	path, err := splitPath(ref.Key)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	dec, err := p.load(ctx)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	node := dec.doc.lookup(path)
+	if node == nil {
+		return provider.SecretValue{}, &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+	}
+
+	value, ok := dec.values[strings.Join(path, ".")]
+	if !ok {
+		out, err := json.Marshal(toJSONValue(node, path, dec.values))
+		if err != nil {
+			return provider.SecretValue{}, fmt.Errorf("failed to encode %s as JSON: %w", ref.Key, err)
+		}
+		value = plainValue{text: string(out)}
+	}
+
+	return provider.SecretValue{
+		Value:     value.text,
+		UpdatedAt: dec.doc.modTime,
+		Metadata: map[string]string{
+			"provider":  p.name,
+			"file":      p.config.File,
+			"encrypted": fmt.Sprintf("%t", value.encrypted),
+		},
+	}, nil
+}
+
+// Describe reports whether a path exists using the file's structure and
+// modification time. Keys are stored in plaintext, so no key is needed.
+func (p *Provider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	path, err := splitPath(ref.Key)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+
+	doc, err := loadDocument(p.config.File, p.config.Format)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return provider.Metadata{Exists: false}, nil
+		}
+		return provider.Metadata{}, p.fileError(err)
+	}
+
+	node := doc.lookup(path)
+	if node == nil {
+		return provider.Metadata{Exists: false}, nil
+	}
+	valueType := "sops-value"
+	if node.Kind != yaml.ScalarNode {
+		valueType = "sops-tree"
+	}
+
+	return provider.Metadata{
+		Exists:      true,
+		UpdatedAt:   doc.modTime,
+		Type:        valueType,
+		Permissions: []string{"read", "write"},
+		Tags: map[string]string{
+			"file":      p.config.File,
+			"format":    doc.format,
+			"encrypted": fmt.Sprintf("%t", node.Kind == yaml.ScalarNode && isEncrypted(node.Value)),
+		},
+	}, nil
+}
+
+// PutSecret sets the value at a dotted path, creating mappings as needed,
+// and re-encrypts the file
+func (p *Provider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	path, err := splitPath(ref.Key)
+	if err != nil {
+		return "", err
+	}
+
+	return "", p.update(ctx, func(doc *document, key []byte) error {
+		if doc.meta.CommentRegex {
+			return dserrors.UserError{
+				Message:    "Cannot write to a sops file that uses comment regexes",
+				Suggestion: "Edit the file with 'sops edit' instead",
+			}
+		}
+		if err := doc.set(path, string(value), key); err != nil {
+			return dserrors.UserError{
+				Message: fmt.Sprintf("Cannot set %s", ref.Key),
+				Details: err.Error(),
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteSecret removes the value or subtree at a dotted path
+func (p *Provider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	path, err := splitPath(ref.Key)
+	if err != nil {
+		return err
+	}
+
+	return p.update(ctx, func(doc *document, key []byte) error {
+		if !doc.remove(path) {
+			return &provider.NotFoundError{Provider: p.name, Key: ref.Key}
+		}
+		return nil
+	})
+}
+
+// ListSecrets lists the dotted paths of all values without decrypting them
+func (p *Provider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	doc, err := loadDocument(p.config.File, p.config.Format)
+	if err != nil {
+		return provider.ListResult{}, p.fileError(err)
+	}
+
+	var secrets []provider.SecretInfo
+	for _, l := range leaves(doc.data, nil, nil, true) {
+		secrets = append(secrets, provider.SecretInfo{
+			Key:       strings.Join(l.path, "."),
+			UpdatedAt: doc.modTime,
+		})
+	}
+	return provider.PageSecrets(secrets, opts)
+}
+
+// load returns the decrypted file, reusing the cached copy while the file is
+// unchanged
+func (p *Provider) load(ctx context.Context) (*decrypted, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.config.File)
+	if err != nil {
+		return nil, p.fileError(err)
+	}
+	if p.cached != nil && p.cached.doc.modTime.Equal(info.ModTime()) && p.cached.doc.size == info.Size() {
+		return p.cached, nil
+	}
+
+	doc, err := loadDocument(p.config.File, p.config.Format)
+	if err != nil {
+		return nil, p.fileError(err)
+	}
+	key, err := p.dataKey(ctx, doc)
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to decrypt %s", p.config.File),
+			Details:    err.Error(),
+			Suggestion: "Set age_key_file, SOPS_AGE_KEY_FILE or SOPS_AGE_KEY to an age identity the file is encrypted for",
+		}
+	}
+	values, err := doc.decryptAll(key)
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to decrypt %s", p.config.File),
+			Details:    err.Error(),
+			Suggestion: "Check the file with 'sops decrypt'; it may have been edited by hand",
+		}
+	}
+
+	p.cached = &decrypted{doc: doc, key: key, values: values}
+	return p.cached, nil
+}
+
+// update applies a change to a freshly loaded file and saves it
+func (p *Provider) update(ctx context.Context, change func(doc *document, key []byte) error) error {
+	if _, err := p.load(ctx); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Work on a fresh parse so a failed change leaves the cache intact
+	doc, err := loadDocument(p.config.File, p.config.Format)
+	if err != nil {
+		return p.fileError(err)
+	}
+	key := p.cached.key
+
+	if err := change(doc, key); err != nil {
+		return err
+	}
+	if err := doc.seal(key); err != nil {
+		return fmt.Errorf("failed to re-encrypt %s: %w", p.config.File, err)
+	}
+	if err := doc.save(); err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to write %s", p.config.File),
+			Details:    err.Error(),
+			Suggestion: "Check that the file and its directory are writable",
+		}
+	}
+
+	p.cached = nil
+	return nil
+}
+
+func (p *Provider) fileError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("sops file not found: %s", p.config.File),
+			Suggestion: "Check the 'file' setting; relative paths are resolved from the directory containing dsops.yaml",
+		}
+	}
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("Failed to read sops file %s", p.config.File),
+		Details:    err.Error(),
+		Suggestion: "Check that the file was encrypted with sops",
+	}
+}
+
+// splitPath splits a dotted path into its parts
+func splitPath(key string) ([]string, error) {
+	var parts []string
+	for _, part := range strings.Split(strings.TrimPrefix(key, "."), ".") {
+		if part == "" {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Invalid sops key: %q", key),
+				Suggestion: "Use a dotted path such as 'database.password' or 'hosts.0'",
+			}
+		}
+		parts = append(parts, part)
+	}
+	if parts[0] == metadataKey {
+		return nil, dserrors.UserError{
+			Message:    "The sops metadata cannot be read or written as a secret",
+			Suggestion: "Use a path to one of the file's values",
+		}
+	}
+	return parts, nil
+}
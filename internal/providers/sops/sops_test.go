@@ -0,0 +1,369 @@
+package sops
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// Encrypted with sops 3.13.3 for testKey, a throwaway identity used only here
+const (
+	testKey = "AGE-SECRET-KEY-1GSXG6N0A8NSPZ02DA9FGDKXFAFSRH83SKESANMCXAVWM7MNGEMUQAXHVUY"
+
+	sopsFixture = `database:
+    password: ENC[AES256_GCM,data:lZn6bYF87w==,iv:zvk4mvsH4TPRlfwjz3ckoWFzCO26ZyrlOK98f2NYHk4=,tag:czsg+4gRAoC3U1oWIUJSoQ==,type:str]
+    port: ENC[AES256_GCM,data:yLXw2Q==,iv:PEAa5z1/2VCxlmXC/L4TFv8vQBs6z88H95XqZwLjswo=,tag:C+ytBOGZhMD034ytVBFwRQ==,type:int]
+hosts:
+    - ENC[AES256_GCM,data:TIs4XvTnYhXexUS2FA==,iv:zyQksOWbV+bmusRK711iw83ToDLIkN7HRrOU0vOZSow=,tag:YLbnxo1eGQ65xA/FDYHrUw==,type:str]
+region_unencrypted: eu-west-1
+sops:
+    age:
+        - enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSB2Y2h4WHJwa3AybnRzdUdU
+            RkpJQXo2ajIrdHViZjJ4eFJWYXRhSkxCSWo0CnJFajlLaTVVNU1Xbm44bzVRWmJS
+            ZEMxcXRGTnBOR3hxdU9kVEp6UU5lS1EKLS0tIG5LOGwzR3BnZXBqdjdPYjM4OTh1
+            SHJZQ0VNOWtVamM2aFhkOXkyWTNQS2sK3uEJgj5e7teMK0WhhDjCd/OxMjeXfZsM
+            VRpVLq5dtg1tB0bKRm/V+g+NSGoZsGAIdXpK3vTGOVhD+lgiDryR6w==
+            -----END AGE ENCRYPTED FILE-----
+          recipient: age1pgwjnl3q5lk5t78calf6lftmw8tlsd2ldv5ktpwe2wkl7gpm2e4seuspud
+    lastmodified: "2026-10-18T14:43:42Z"
+    mac: ENC[AES256_GCM,data:AN4tkNEKnS6ggB3uyuuqT5fpCTk4/wYv2iyODNeRQaSR4GZqCE6Crq4bj5UJwjc3CpNe2jrkTHXtU0Ciss1UXlhGAUGTRRrI9dut6psbmwlZLlmcfI0NPlO7jq9Ck+o1gNDpZCz6lEU0bz1F5abII6J8O4k2l0Cxn/J4tW4yorQ=,iv:0DTIwNxsGXwxZd8eigBnwENfYgpYfa1SheP9EH861S4=,tag:CowwiXauE1OC4WaImFodvg==,type:str]
+    unencrypted_suffix: _unencrypted
+    version: 3.13.3
+`
+)
+
+// writeFixture writes the sops-encrypted fixture and points the key env at an
+// empty config dir so only configured identities are used
+func writeFixture(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("SOPS_AGE_KEY", "")
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+
+	path := filepath.Join(t.TempDir(), "secrets.enc.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sopsFixture), 0600))
+	return path
+}
+
+func writeKeyFile(t *testing.T, key string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# test key\n"+key+"\n"), 0600))
+	return path
+}
+
+// newEncryptedFile builds a sops file from plaintext YAML, encrypting every
+// value the way sops would for the given identity
+func newEncryptedFile(t *testing.T, name, plaintext string, identity *age.X25519Identity) string {
+	t.Helper()
+
+	dataKey := make([]byte, 32)
+	_, err := rand.Read(dataKey)
+	require.NoError(t, err)
+
+	var armored bytes.Buffer
+	aw := armor.NewWriter(&armored)
+	w, err := age.Encrypt(aw, identity.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write(dataKey)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, aw.Close())
+
+	format := formatFor(name, "")
+	doc, err := parseDocument([]byte(plaintext+"sops:\n  unencrypted_suffix: _unencrypted\n  version: 3.13.3\n"), "yaml")
+	require.NoError(t, err)
+	doc.format = format
+	setMappingValue(doc.meta.node, "age", "")
+	ageNode := mappingValue(doc.meta.node, "age")
+	ageNode.Kind, ageNode.Tag, ageNode.Value = 0, "", ""
+	require.NoError(t, ageNode.Encode([]map[string]string{{"recipient": identity.Recipient().String(), "enc": armored.String()}}))
+	doc.meta.UnencryptedSuffix = "_unencrypted"
+
+	for _, l := range leaves(doc.data, nil, nil, true) {
+		if doc.meta.shouldEncrypt(l.keys) {
+			l.node.Value, err = encryptValue(l.node.Value, dataKey, additionalData(l.keys))
+			require.NoError(t, err)
+			l.node.Tag = "!!str"
+		}
+	}
+	require.NoError(t, doc.seal(dataKey))
+
+	doc.path = filepath.Join(t.TempDir(), name)
+	require.NoError(t, doc.save())
+	return doc.path
+}
+
+func TestProvider_Resolve_SopsFixture(t *testing.T) {
+	path := writeFixture(t)
+	p, err := NewProvider("secrets", map[string]interface{}{
+		"file":         path,
+		"age_key_file": writeKeyFile(t, testKey),
+	}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, p.Validate(ctx))
+
+	tests := map[string]string{
+		"database.password":  "hunter2",
+		"database.port":      "5432",
+		"hosts.0":            "a.example.com",
+		"region_unencrypted": "eu-west-1",
+		"database":           `{"password":"hunter2","port":5432}`,
+		"hosts":              `["a.example.com"]`,
+	}
+	for key, want := range tests {
+		t.Run(key, func(t *testing.T) {
+			value, err := p.Resolve(ctx, provider.Reference{Key: key})
+			require.NoError(t, err)
+			assert.Equal(t, want, value.Value)
+			assert.False(t, value.UpdatedAt.IsZero())
+		})
+	}
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "database.user"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "sops.mac"})
+	assert.Error(t, err)
+	_, err = p.Resolve(ctx, provider.Reference{Key: "database..password"})
+	assert.Error(t, err)
+}
+
+func TestProvider_Resolve_KeySources(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SOPS_AGE_KEY", func(t *testing.T) {
+		path := writeFixture(t)
+		t.Setenv("SOPS_AGE_KEY", testKey)
+		p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+		require.NoError(t, err)
+
+		value, err := p.Resolve(ctx, provider.Reference{Key: "database.password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", value.Value)
+	})
+
+	t.Run("SOPS_AGE_KEY_FILE", func(t *testing.T) {
+		path := writeFixture(t)
+		t.Setenv("SOPS_AGE_KEY_FILE", writeKeyFile(t, testKey))
+		p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+		require.NoError(t, err)
+
+		value, err := p.Resolve(ctx, provider.Reference{Key: "database.password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", value.Value)
+	})
+
+	t.Run("default key file", func(t *testing.T) {
+		path := writeFixture(t)
+		keyDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "sops", "age")
+		require.NoError(t, os.MkdirAll(keyDir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(keyDir, "keys.txt"), []byte(testKey+"\n"), 0600))
+		p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+		require.NoError(t, err)
+
+		value, err := p.Resolve(ctx, provider.Reference{Key: "database.password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", value.Value)
+	})
+
+	t.Run("keychain", func(t *testing.T) {
+		path := writeFixture(t)
+		var asked string
+		keychain := func(ctx context.Context, ref string) (string, error) {
+			asked = ref
+			return testKey, nil
+		}
+		p, err := NewProvider("secrets", map[string]interface{}{
+			"file":             path,
+			"age_key_keychain": "dsops/age-key",
+		}, keychain)
+		require.NoError(t, err)
+
+		value, err := p.Resolve(ctx, provider.Reference{Key: "database.password"})
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", value.Value)
+		assert.Equal(t, "dsops/age-key", asked)
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		path := writeFixture(t)
+		p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+		require.NoError(t, err)
+
+		err = p.Validate(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Failed to decrypt")
+	})
+
+	t.Run("wrong identity", func(t *testing.T) {
+		path := writeFixture(t)
+		other, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		p, err := NewProvider("secrets", map[string]interface{}{
+			"file":         path,
+			"age_key_file": writeKeyFile(t, other.String()),
+		}, nil)
+		require.NoError(t, err)
+
+		err = p.Validate(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Failed to decrypt")
+	})
+}
+
+func TestProvider_Resolve_DetectsTampering(t *testing.T) {
+	path := writeFixture(t)
+	// Editing an unencrypted value by hand breaks the MAC
+	tampered := strings.Replace(sopsFixture, "region_unencrypted: eu-west-1", "region_unencrypted: us-east-1", 1)
+	require.NoError(t, os.WriteFile(path, []byte(tampered), 0600))
+
+	p, err := NewProvider("secrets", map[string]interface{}{
+		"file":         path,
+		"age_key_file": writeKeyFile(t, testKey),
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = p.Resolve(context.Background(), provider.Reference{Key: "database.password"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MAC mismatch")
+}
+
+func TestProvider_Describe(t *testing.T) {
+	path := writeFixture(t)
+	modTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	// No key configured: Describe reads the structure only
+	p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "database.password"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.True(t, meta.UpdatedAt.Equal(modTime))
+	assert.Equal(t, "true", meta.Tags["encrypted"])
+	assert.Equal(t, "yaml", meta.Tags["format"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "region_unencrypted"})
+	require.NoError(t, err)
+	assert.Equal(t, "false", meta.Tags["encrypted"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "database.user"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+
+	missing, err := NewProvider("secrets", map[string]interface{}{"file": filepath.Join(t.TempDir(), "none.yaml")}, nil)
+	require.NoError(t, err)
+	meta, err = missing.Describe(ctx, provider.Reference{Key: "a"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestProvider_ListSecrets(t *testing.T) {
+	path := writeFixture(t)
+	p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+	require.NoError(t, err)
+
+	result, err := p.ListSecrets(context.Background(), provider.ListOptions{})
+	require.NoError(t, err)
+
+	var keys []string
+	for _, s := range result.Secrets {
+		keys = append(keys, s.Key)
+	}
+	assert.ElementsMatch(t, []string{"database.password", "database.port", "hosts.0", "region_unencrypted"}, keys)
+
+	result, err = p.ListSecrets(context.Background(), provider.ListOptions{Prefix: "database."})
+	require.NoError(t, err)
+	assert.Len(t, result.Secrets, 2)
+}
+
+func TestProvider_PutAndDelete(t *testing.T) {
+	for _, name := range []string{"secrets.enc.yaml", "secrets.enc.json"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("SOPS_AGE_KEY_FILE", "")
+
+			identity, err := age.GenerateX25519Identity()
+			require.NoError(t, err)
+			t.Setenv("SOPS_AGE_KEY", identity.String())
+
+			path := newEncryptedFile(t, name, "api:\n  token: old\nlog_level_unencrypted: info\n", identity)
+			p, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+			require.NoError(t, err)
+			ctx := context.Background()
+
+			value, err := p.Resolve(ctx, provider.Reference{Key: "api.token"})
+			require.NoError(t, err)
+			assert.Equal(t, "old", value.Value)
+
+			_, err = p.PutSecret(ctx, provider.Reference{Key: "api.token"}, []byte("new"), provider.WriteOptions{})
+			require.NoError(t, err)
+			_, err = p.PutSecret(ctx, provider.Reference{Key: "db.password"}, []byte("s3cret"), provider.WriteOptions{})
+			require.NoError(t, err)
+			_, err = p.PutSecret(ctx, provider.Reference{Key: "db.host_unencrypted"}, []byte("localhost"), provider.WriteOptions{})
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), "s3cret")
+			assert.Contains(t, string(content), "localhost")
+
+			// A fresh provider verifies the recomputed MAC
+			fresh, err := NewProvider("secrets", map[string]interface{}{"file": path}, nil)
+			require.NoError(t, err)
+			for key, want := range map[string]string{
+				"api.token":             "new",
+				"db.password":           "s3cret",
+				"db.host_unencrypted":   "localhost",
+				"log_level_unencrypted": "info",
+			} {
+				value, err := fresh.Resolve(ctx, provider.Reference{Key: key})
+				require.NoError(t, err, key)
+				assert.Equal(t, want, value.Value, key)
+			}
+
+			require.NoError(t, fresh.DeleteSecret(ctx, provider.Reference{Key: "api.token"}, provider.DeleteOptions{}))
+			_, err = fresh.Resolve(ctx, provider.Reference{Key: "api.token"})
+			var notFound *provider.NotFoundError
+			assert.ErrorAs(t, err, &notFound)
+
+			err = fresh.DeleteSecret(ctx, provider.Reference{Key: "api.token"}, provider.DeleteOptions{})
+			assert.ErrorAs(t, err, &notFound)
+
+			_, err = fresh.PutSecret(ctx, provider.Reference{Key: "db"}, []byte("x"), provider.WriteOptions{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewProvider_Config(t *testing.T) {
+	_, err := NewProvider("secrets", map[string]interface{}{}, nil)
+	assert.Error(t, err)
+
+	_, err = NewProvider("secrets", map[string]interface{}{"file": "s.toml", "format": "toml"}, nil)
+	assert.Error(t, err)
+
+	p, err := NewProvider("secrets", map[string]interface{}{"file": "s.yaml"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "secrets", p.Name())
+	assert.True(t, p.Capabilities().RequiresAuth)
+}
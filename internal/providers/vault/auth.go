@@ -0,0 +1,317 @@
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+const (
+	// stsGetCallerIdentityBody is the request Vault replays to AWS STS to
+	// learn the caller's IAM identity
+	stsGetCallerIdentityBody = "Action=GetCallerIdentity&Version=2011-06-15"
+
+	// awsIAMServerIDHeader binds a signed request to one Vault server
+	awsIAMServerIDHeader = "X-Vault-Aws-Iam-Server-Id"
+)
+
+// authMount returns the login path for an auth method. auth_mount overrides
+// the method's default mount, e.g. "approle-ci" for auth/approle-ci/login.
+func (c *HTTPVaultClient) authMount(defaultMount string) string {
+	return c.authPath(defaultMount) + "/login"
+}
+
+// authPath returns the path an auth method is mounted at, e.g. auth/oidc
+func (c *HTTPVaultClient) authPath(defaultMount string) string {
+	mount := defaultMount
+	if c.config.AuthMount != "" {
+		mount = strings.Trim(strings.TrimPrefix(strings.Trim(c.config.AuthMount, "/"), "auth/"), "/")
+	}
+	return "auth/" + mount
+}
+
+// authenticateAWSLocked authenticates with the AWS auth method using IAM.
+// dsops signs an sts:GetCallerIdentity request with the ambient AWS
+// credentials and Vault replays it to STS to verify the caller; the AWS
+// credentials themselves are never sent.
+// Must be called with c.mu held
+func (c *HTTPVaultClient) authenticateAWSLocked(ctx context.Context) error {
+	creds, err := c.awsCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	region := c.config.AWSRegion
+	endpoint := "https://sts.amazonaws.com/"
+	if region == "" {
+		region = "us-east-1" // The global STS endpoint signs as us-east-1
+	} else {
+		endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com/", region)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(stsGetCallerIdentityBody))
+	if err != nil {
+		return fmt.Errorf("failed to create sts request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	if c.config.AWSHeaderValue != "" {
+		req.Header.Set(awsIAMServerIDHeader, c.config.AWSHeaderValue)
+	}
+
+	payloadHash := sha256.Sum256([]byte(stsGetCallerIdentityBody))
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "sts", region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign sts request: %w", err)
+	}
+
+	// Go keeps Host out of the header map; Vault needs it in the signed set
+	headers := req.Header.Clone()
+	headers.Set("Host", req.URL.Host)
+	headerJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode sts headers: %w", err)
+	}
+
+	authData := map[string]interface{}{
+		"iam_http_request_method": "POST",
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(endpoint)),
+		"iam_request_body":        base64.StdEncoding.EncodeToString([]byte(stsGetCallerIdentityBody)),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headerJSON),
+	}
+	if c.config.AWSRole != "" {
+		authData["role"] = c.config.AWSRole
+	}
+
+	return c.performLoginLocked(ctx, c.authMount("aws"), authData)
+}
+
+// awsCredentials returns AWS credentials from the client's provider or the
+// default chain: environment, shared config, SSO, web identity, IMDS
+func (c *HTTPVaultClient) awsCredentials(ctx context.Context) (aws.Credentials, error) {
+	credsProvider := c.awsCredsProvider
+	if credsProvider == nil {
+		var opts []func(*awsconfig.LoadOptions) error
+		if c.config.AWSRegion != "" {
+			opts = append(opts, awsconfig.WithRegion(c.config.AWSRegion))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return aws.Credentials{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+		}
+		credsProvider = cfg.Credentials
+	}
+	if credsProvider == nil {
+		return aws.Credentials{}, fmt.Errorf("no AWS credentials found for Vault AWS auth")
+	}
+
+	creds, err := credsProvider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to retrieve AWS credentials for Vault AWS auth: %w", err)
+	}
+	return creds, nil
+}
+
+// authenticateAppRoleLocked authenticates with a role ID and secret ID. The
+// secret ID is optional for roles created with bind_secret_id=false.
+// Must be called with c.mu held
+func (c *HTTPVaultClient) authenticateAppRoleLocked(ctx context.Context) error {
+	roleID, err := credentialValue(c.config.AppRoleRoleID, c.config.AppRoleRoleIDFile, "VAULT_ROLE_ID")
+	if err != nil {
+		return fmt.Errorf("failed to read approle role_id: %w", err)
+	}
+	if roleID == "" {
+		return fmt.Errorf("no role_id found for approle auth")
+	}
+
+	secretID, err := credentialValue("", c.config.AppRoleSecretIDFile, "VAULT_SECRET_ID")
+	if err != nil {
+		return fmt.Errorf("failed to read approle secret_id: %w", err)
+	}
+
+	authData := map[string]interface{}{"role_id": roleID}
+	if secretID != "" {
+		authData["secret_id"] = secretID
+	}
+
+	return c.performLoginLocked(ctx, c.authMount("approle"), authData)
+}
+
+// authenticateJWTLocked authenticates with a JWT such as a CI job's OIDC
+// token. The method name ("jwt" or "oidc") is the default mount.
+// Must be called with c.mu held
+func (c *HTTPVaultClient) authenticateJWTLocked(ctx context.Context) error {
+	jwt, err := c.jwtToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	authData := map[string]interface{}{"jwt": jwt}
+	if c.config.JWTRole != "" {
+		authData["role"] = c.config.JWTRole
+	}
+
+	return c.performLoginLocked(ctx, c.authMount(c.config.AuthMethod), authData)
+}
+
+// jwtToken finds the JWT to log in with: jwt_token_file, then VAULT_JWT,
+// then a GitHub Actions OIDC token when the job has id-token: write
+func (c *HTTPVaultClient) jwtToken(ctx context.Context) (string, error) {
+	jwt, err := credentialValue("", c.config.JWTTokenFile, "VAULT_JWT")
+	if err != nil {
+		return "", fmt.Errorf("failed to read jwt: %w", err)
+	}
+	if jwt != "" {
+		return jwt, nil
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL != "" && requestToken != "" {
+		return c.githubActionsToken(ctx, requestURL, requestToken)
+	}
+
+	return "", fmt.Errorf("no jwt found: set jwt_token_file or VAULT_JWT, or grant the GitHub Actions job id-token: write")
+}
+
+// githubActionsToken requests an OIDC token from the GitHub Actions runtime
+func (c *HTTPVaultClient) githubActionsToken(ctx context.Context, requestURL, requestToken string) (string, error) {
+	if c.config.JWTAudience != "" {
+		u, err := url.Parse(requestURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("audience", c.config.JWTAudience)
+		u.RawQuery = q.Encode()
+		requestURL = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GitHub OIDC token request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := (&http.Client{Timeout: DefaultTimeout}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request GitHub OIDC token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub OIDC token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub OIDC token: %w", err)
+	}
+	if tokenResp.Value == "" {
+		return "", fmt.Errorf("GitHub returned an empty OIDC token")
+	}
+	return tokenResp.Value, nil
+}
+
+// credentialValue returns an inline value, else the trimmed contents of
+// file, else the environment variable
+func credentialValue(inline, file, envVar string) (string, error) {
+	if inline != "" {
+		return inline, nil
+	}
+	if file != "" {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return os.Getenv(envVar), nil
+}
+
+// reuseTokenLocked reports whether the current token can still be used. A
+// token with a known TTL is used until two thirds of it has passed and then
+// renewed; a token with an unknown TTL is looked up. Otherwise the caller
+// logs in again.
+// Must be called with c.mu held
+func (c *HTTPVaultClient) reuseTokenLocked(ctx context.Context) bool {
+	if c.tokenExpiry.IsZero() {
+		return c.validateTokenLocked(ctx) == nil
+	}
+	if time.Until(c.tokenExpiry) >= c.tokenTTL/3 {
+		return true
+	}
+	return c.tokenRenewable && c.renewTokenLocked(ctx) == nil
+}
+
+// renewTokenLocked extends the current token with auth/token/renew-self
+// Must be called with c.mu held
+func (c *HTTPVaultClient) renewTokenLocked(ctx context.Context) error {
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/auth/token/renew-self"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("failed to create token renewal request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to renew token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("token renewal failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var renewResp struct {
+		Auth vaultAuth `json:"auth"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&renewResp); err != nil {
+		return fmt.Errorf("failed to decode token renewal response: %w", err)
+	}
+
+	c.setTokenLifetime(renewResp.Auth.LeaseDuration, renewResp.Auth.Renewable)
+	return nil
+}
+
+// vaultAuth is the auth block of login and renew-self responses
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"` // Seconds; 0 for tokens that never expire
+	Renewable     bool   `json:"renewable"`
+}
+
+// setTokenLifetime records when the current token expires
+// Must be called with c.mu held
+func (c *HTTPVaultClient) setTokenLifetime(ttlSeconds int, renewable bool) {
+	c.tokenTTL = time.Duration(ttlSeconds) * time.Second
+	c.tokenRenewable = renewable
+	c.tokenExpiry = time.Time{}
+	if ttlSeconds > 0 {
+		c.tokenExpiry = time.Now().Add(c.tokenTTL)
+	}
+}
@@ -0,0 +1,320 @@
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+)
+
+// fakeVault is an httptest stand-in for the Vault auth endpoints. It records
+// each request's path and JSON body.
+type fakeVault struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	logins   []map[string]interface{}
+	paths    []string
+	leaseTTL int
+	renew    bool
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	f := &fakeVault{t: t, leaseTTL: 3600, renew: true}
+	server := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeVault) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.paths = append(f.paths, r.Method+" "+r.URL.Path)
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/login") || strings.Contains(r.URL.Path, "/login/"):
+		var body map[string]interface{}
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		f.logins = append(f.logins, body)
+		f.writeAuth(w, "login-token")
+	case r.URL.Path == "/v1/auth/token/renew-self":
+		assert.Equal(f.t, "login-token", r.Header.Get("X-Vault-Token"))
+		f.writeAuth(w, "login-token")
+	case r.URL.Path == "/v1/auth/token/lookup-self":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"ttl": f.leaseTTL, "renewable": f.renew},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeVault) writeAuth(w http.ResponseWriter, token string) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": f.leaseTTL,
+			"renewable":      f.renew,
+		},
+	})
+}
+
+func (f *fakeVault) requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.paths...)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestHTTPVaultClient_AuthenticateAWS(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{
+			Address:        server.URL,
+			AuthMethod:     "aws",
+			AWSRole:        "dsops",
+			AWSRegion:      "eu-west-1",
+			AWSHeaderValue: "vault.example.com",
+		},
+		awsCredsProvider: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "session"),
+	}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, "login-token", client.token)
+	assert.Equal(t, []string{"POST /v1/auth/aws/login"}, vault.requests())
+
+	login := vault.logins[0]
+	assert.Equal(t, "dsops", login["role"])
+	assert.Equal(t, "POST", login["iam_http_request_method"])
+
+	decode := func(key string) string {
+		data, err := base64.StdEncoding.DecodeString(login[key].(string))
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "https://sts.eu-west-1.amazonaws.com/", decode("iam_request_url"))
+	assert.Equal(t, "Action=GetCallerIdentity&Version=2011-06-15", decode("iam_request_body"))
+
+	var headers map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(decode("iam_request_headers")), &headers))
+	assert.Equal(t, []string{"sts.eu-west-1.amazonaws.com"}, headers["Host"])
+	assert.Equal(t, []string{"session"}, headers["X-Amz-Security-Token"])
+	assert.Equal(t, []string{"vault.example.com"}, headers["X-Vault-Aws-Iam-Server-Id"])
+
+	authorization := headers["Authorization"][0]
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), authorization)
+	assert.Contains(t, authorization, "/eu-west-1/sts/aws4_request")
+	assert.Contains(t, authorization, "x-vault-aws-iam-server-id")
+	assert.NotContains(t, decode("iam_request_headers"), "secret", "the secret key is never sent")
+}
+
+func TestHTTPVaultClient_AuthenticateAWS_GlobalEndpoint(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config:           Config{Address: server.URL, AuthMethod: "aws", AuthMount: "auth/aws-prod/"},
+		awsCredsProvider: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, []string{"POST /v1/auth/aws-prod/login"}, vault.requests())
+
+	url, err := base64.StdEncoding.DecodeString(vault.logins[0]["iam_request_url"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "https://sts.amazonaws.com/", string(url))
+	assert.NotContains(t, vault.logins[0], "role", "Vault defaults the role to the IAM role name")
+}
+
+func TestHTTPVaultClient_AuthenticateAppRole(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{
+			Address:             server.URL,
+			AuthMethod:          "approle",
+			AuthMount:           "approle-ci",
+			AppRoleRoleIDFile:   writeFile(t, "role-id", "role-123\n"),
+			AppRoleSecretIDFile: writeFile(t, "secret-id", "secret-456\n"),
+		},
+	}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, []string{"POST /v1/auth/approle-ci/login"}, vault.requests())
+	assert.Equal(t, map[string]interface{}{"role_id": "role-123", "secret_id": "secret-456"}, vault.logins[0])
+}
+
+func TestHTTPVaultClient_AuthenticateAppRole_FromEnv(t *testing.T) {
+	t.Setenv("VAULT_ROLE_ID", "env-role")
+	t.Setenv("VAULT_SECRET_ID", "env-secret")
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{config: Config{Address: server.URL, AuthMethod: "approle"}}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, []string{"POST /v1/auth/approle/login"}, vault.requests())
+	assert.Equal(t, map[string]interface{}{"role_id": "env-role", "secret_id": "env-secret"}, vault.logins[0])
+}
+
+func TestHTTPVaultClient_AuthenticateAppRole_MissingRoleID(t *testing.T) {
+	t.Setenv("VAULT_ROLE_ID", "")
+
+	client := &HTTPVaultClient{config: Config{Address: "http://127.0.0.1:1", AuthMethod: "approle"}}
+
+	err := client.Authenticate(context.Background())
+	assert.ErrorContains(t, err, "no role_id found")
+}
+
+func TestHTTPVaultClient_AuthenticateJWT(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{
+			Address:      server.URL,
+			AuthMethod:   "jwt",
+			JWTRole:      "ci",
+			JWTTokenFile: writeFile(t, "token.jwt", "header.payload.sig\n"),
+		},
+	}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, []string{"POST /v1/auth/jwt/login"}, vault.requests())
+	assert.Equal(t, map[string]interface{}{"role": "ci", "jwt": "header.payload.sig"}, vault.logins[0])
+}
+
+func TestHTTPVaultClient_AuthenticateJWT_GitHubActions(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bearer runtime-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "https://vault.example.com", r.URL.Query().Get("audience"))
+		assert.Equal(t, "1", r.URL.Query().Get("api-version"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": "github.oidc.token"})
+	}))
+	defer github.Close()
+
+	t.Setenv("VAULT_JWT", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", github.URL+"/token?api-version=1")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "runtime-token")
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{
+			Address:     server.URL,
+			AuthMethod:  "oidc",
+			JWTRole:     "deploy",
+			JWTAudience: "https://vault.example.com",
+		},
+	}
+
+	require.NoError(t, client.Authenticate(context.Background()))
+	assert.Equal(t, []string{"POST /v1/auth/oidc/login"}, vault.requests())
+	assert.Equal(t, "github.oidc.token", vault.logins[0]["jwt"])
+}
+
+func TestHTTPVaultClient_AuthenticateJWT_NoToken(t *testing.T) {
+	t.Setenv("VAULT_JWT", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+
+	client := &HTTPVaultClient{config: Config{Address: "http://127.0.0.1:1", AuthMethod: "jwt"}}
+
+	err := client.Authenticate(context.Background())
+	assert.ErrorContains(t, err, "no jwt found")
+}
+
+func TestHTTPVaultClient_TokenRenewal(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{
+			Address:      server.URL,
+			AuthMethod:   "jwt",
+			JWTTokenFile: writeFile(t, "token.jwt", "jwt"),
+		},
+	}
+	ctx := context.Background()
+
+	require.NoError(t, client.Authenticate(ctx))
+	assert.Equal(t, time.Hour, client.tokenTTL)
+
+	// A fresh token is reused without asking Vault
+	require.NoError(t, client.Authenticate(ctx))
+	assert.Equal(t, []string{"POST /v1/auth/jwt/login"}, vault.requests())
+
+	// Past two thirds of its TTL the token is renewed
+	client.tokenExpiry = time.Now().Add(10 * time.Minute)
+	require.NoError(t, client.Authenticate(ctx))
+	assert.Equal(t, []string{"POST /v1/auth/jwt/login", "POST /v1/auth/token/renew-self"}, vault.requests())
+	assert.True(t, time.Until(client.tokenExpiry) > 50*time.Minute)
+
+	// A token that cannot be renewed is replaced by logging in again
+	client.tokenRenewable = false
+	client.tokenExpiry = time.Now().Add(10 * time.Minute)
+	require.NoError(t, client.Authenticate(ctx))
+	assert.Equal(t, []string{
+		"POST /v1/auth/jwt/login",
+		"POST /v1/auth/token/renew-self",
+		"POST /v1/auth/jwt/login",
+	}, vault.requests())
+}
+
+func TestHTTPVaultClient_TokenLookupLearnsTTL(t *testing.T) {
+	t.Parallel()
+
+	vault, server := newFakeVault(t)
+	client := &HTTPVaultClient{
+		config: Config{Address: server.URL, AuthMethod: "token"},
+		token:  "login-token",
+	}
+	ctx := context.Background()
+
+	require.NoError(t, client.Authenticate(ctx))
+	require.NoError(t, client.Authenticate(ctx))
+	assert.Equal(t, []string{"GET /v1/auth/token/lookup-self"}, vault.requests(), "the TTL from the lookup avoids a second one")
+}
+
+func TestVaultProvider_Validate_AppRoleAuth(t *testing.T) {
+	t.Setenv("VAULT_ROLE_ID", "")
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200", AuthMethod: "approle"},
+		client: &MockVaultClient{},
+		logger: logging.New(false, false),
+	}
+
+	err := p.Validate(context.Background())
+	var configErr dserrors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "approle_role_id", configErr.Field)
+
+	p.config.AppRoleRoleID = "role-123"
+	assert.NoError(t, p.Validate(context.Background()))
+
+	p.config = Config{Address: "http://localhost:8200", AuthMethod: "jwt"}
+	assert.NoError(t, p.Validate(context.Background()))
+}
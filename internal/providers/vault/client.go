@@ -18,13 +18,23 @@ func (c *HTTPVaultClient) Authenticate(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// If we already have a token, validate it
+	// If we already have a token, keep using it while it is valid
 	if c.token != "" {
-		if err := c.validateTokenLocked(ctx); err == nil {
-			return nil // Token is still valid
+		if c.reuseTokenLocked(ctx) {
+			return nil
 		}
-		// Token is invalid, clear it and re-authenticate
+		// Token is invalid or expiring, clear it and re-authenticate
 		c.token = ""
+		c.setTokenLifetime(0, false)
+	}
+
+	// A token saved by dsops login (or vault login) spares the password
+	// prompt or browser round trip
+	switch c.config.AuthMethod {
+	case "userpass", "ldap", "oidc":
+		if c.useSavedTokenLocked(ctx) {
+			return nil
+		}
 	}
 
 	switch c.config.AuthMethod {
@@ -38,6 +48,10 @@ func (c *HTTPVaultClient) Authenticate(ctx context.Context) error {
 		return c.authenticateAWSLocked(ctx)
 	case "k8s", "kubernetes":
 		return c.authenticateKubernetesLocked(ctx)
+	case "approle":
+		return c.authenticateAppRoleLocked(ctx)
+	case "jwt", "oidc":
+		return c.authenticateJWTLocked(ctx)
 	default:
 		return fmt.Errorf("unsupported auth method: %s", c.config.AuthMethod)
 	}
@@ -78,11 +92,122 @@ func (c *HTTPVaultClient) Read(ctx context.Context, path string) (*VaultSecret,
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var response struct {
+		Data          map[string]interface{} `json:"data"`
+		LeaseID       string                 `json:"lease_id"`
+		LeaseDuration int                    `json:"lease_duration"`
+		Renewable     bool                   `json:"renewable"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if response.Data == nil {
+		return nil, nil
+	}
+	return &VaultSecret{
+		Data:          response.Data,
+		LeaseID:       response.LeaseID,
+		LeaseDuration: response.LeaseDuration,
+		Renewable:     response.Renewable,
+	}, nil
+}
+
+// MountInfo looks up the secrets engine mounted at or above path
+func (c *HTTPVaultClient) MountInfo(ctx context.Context, path string) (*MountInfo, error) {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == "" {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/sys/internal/ui/mounts/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	client := c.getHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response struct {
-		Data *VaultSecret `json:"data"`
+		Data MountInfo `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &response.Data, nil
+}
+
+// Write writes data to a Vault path and returns the response data, if any
+func (c *HTTPVaultClient) Write(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == "" {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	client := c.getHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 204 {
+		return nil, nil
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -92,6 +217,164 @@ func (c *HTTPVaultClient) Read(ctx context.Context, path string) (*VaultSecret,
 	return response.Data, nil
 }
 
+// Delete deletes the data at a Vault path
+func (c *HTTPVaultClient) Delete(ctx context.Context, path string) error {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == "" {
+		return fmt.Errorf("not authenticated")
+	}
+
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	client := c.getHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		body, _ := io.ReadAll(resp.Body)
+		return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return nil
+}
+
+// List returns the keys under a Vault path. Keys ending in "/" are folders.
+// A missing path returns no keys.
+func (c *HTTPVaultClient) List(ctx context.Context, path string) ([]string, error) {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == "" {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/" + strings.Trim(path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, "LIST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	client := c.getHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 404 {
+		return nil, nil // Nothing under this path
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var response struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Data.Keys, nil
+}
+
+// RenewLease extends a lease by increment seconds. The returned secret holds
+// the new lease duration and no data.
+func (c *HTTPVaultClient) RenewLease(ctx context.Context, leaseID string, increment int) (*VaultSecret, error) {
+	body, err := c.putJSON(ctx, "sys/leases/renew", map[string]interface{}{
+		"lease_id":  leaseID,
+		"increment": increment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var secret VaultSecret
+	if len(body) == 0 {
+		return &secret, nil
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &secret, nil
+}
+
+// RevokeLease revokes a lease immediately
+func (c *HTTPVaultClient) RevokeLease(ctx context.Context, leaseID string) error {
+	_, err := c.putJSON(ctx, "sys/leases/revoke", map[string]interface{}{"lease_id": leaseID})
+	return err
+}
+
+// putJSON sends a PUT request and returns the raw response body
+func (c *HTTPVaultClient) putJSON(ctx context.Context, path string, data map[string]interface{}) ([]byte, error) {
+	c.mu.RLock()
+	token := c.token
+	c.mu.RUnlock()
+
+	if token == "" {
+		return nil, fmt.Errorf("not authenticated")
+	}
+
+	url := strings.TrimSuffix(c.config.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", token)
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	client := c.getHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return nil, &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
 // Close cleans up the client
 func (c *HTTPVaultClient) Close() error {
 	c.mu.Lock()
@@ -113,7 +396,12 @@ func (c *HTTPVaultClient) authenticateTokenLocked() error {
 		return nil
 	}
 
-	return fmt.Errorf("no vault token found in config or VAULT_TOKEN environment variable")
+	if token, err := readSavedToken(); err == nil && token != "" {
+		c.token = token
+		return nil
+	}
+
+	return fmt.Errorf("no vault token found in config, VAULT_TOKEN or %s", tokenFileName)
 }
 
 // authenticateUserpassLocked authenticates using username/password
@@ -125,14 +413,14 @@ func (c *HTTPVaultClient) authenticateUserpassLocked(ctx context.Context) error
 		password = os.Getenv("VAULT_USERPASS_PASSWORD")
 	}
 	if password == "" {
-		return fmt.Errorf("no password found for userpass auth")
+		return fmt.Errorf("no password found for userpass auth; set VAULT_USERPASS_PASSWORD or run dsops login")
 	}
 
 	authData := map[string]interface{}{
 		"password": password,
 	}
 
-	return c.performLoginLocked(ctx, fmt.Sprintf("auth/userpass/login/%s", c.config.UserpassUsername), authData)
+	return c.performLoginLocked(ctx, c.authMount("userpass")+"/"+c.config.UserpassUsername, authData)
 }
 
 // authenticateLDAPLocked authenticates using LDAP
@@ -144,28 +432,14 @@ func (c *HTTPVaultClient) authenticateLDAPLocked(ctx context.Context) error {
 		password = os.Getenv("VAULT_LDAP_PASSWORD")
 	}
 	if password == "" {
-		return fmt.Errorf("no password found for LDAP auth")
+		return fmt.Errorf("no password found for LDAP auth; set VAULT_LDAP_PASSWORD or run dsops login")
 	}
 
 	authData := map[string]interface{}{
 		"password": password,
 	}
 
-	return c.performLoginLocked(ctx, fmt.Sprintf("auth/ldap/login/%s", c.config.LDAPUsername), authData)
-}
-
-// authenticateAWSLocked authenticates using AWS IAM
-// Must be called with c.mu held
-func (c *HTTPVaultClient) authenticateAWSLocked(ctx context.Context) error {
-	// This is a simplified implementation
-	// In practice, you'd need to generate AWS SigV4 signatures
-	authData := map[string]interface{}{
-		"role": c.config.AWSRole,
-		// AWS auth requires iam_http_request_method, iam_request_url, iam_request_body, iam_request_headers
-		// This would need proper AWS SigV4 implementation
-	}
-
-	return c.performLoginLocked(ctx, "auth/aws/login", authData)
+	return c.performLoginLocked(ctx, c.authMount("ldap")+"/"+c.config.LDAPUsername, authData)
 }
 
 // authenticateKubernetesLocked authenticates using Kubernetes service account
@@ -193,7 +467,7 @@ func (c *HTTPVaultClient) authenticateKubernetesLocked(ctx context.Context) erro
 		"jwt":  string(tokenBytes),
 	}
 
-	return c.performLoginLocked(ctx, "auth/kubernetes/login", authData)
+	return c.performLoginLocked(ctx, c.authMount("kubernetes"), authData)
 }
 
 // performLoginLocked handles the common login workflow
@@ -229,9 +503,7 @@ func (c *HTTPVaultClient) performLoginLocked(ctx context.Context, authPath strin
 	}
 
 	var authResp struct {
-		Auth struct {
-			ClientToken string `json:"client_token"`
-		} `json:"auth"`
+		Auth vaultAuth `json:"auth"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
@@ -243,6 +515,7 @@ func (c *HTTPVaultClient) performLoginLocked(ctx context.Context, authPath strin
 	}
 
 	c.token = authResp.Auth.ClientToken
+	c.setTokenLifetime(authResp.Auth.LeaseDuration, authResp.Auth.Renewable)
 	return nil
 }
 
@@ -272,6 +545,17 @@ func (c *HTTPVaultClient) validateTokenLocked(ctx context.Context) error {
 		return fmt.Errorf("token validation failed with status %d", resp.StatusCode)
 	}
 
+	// Learn the token's TTL so later calls renew it instead of looking it up
+	var lookup struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&lookup); err == nil {
+		c.setTokenLifetime(lookup.Data.TTL, lookup.Data.Renewable)
+	}
+
 	return nil
 }
 
@@ -297,3 +581,18 @@ func (c *HTTPVaultClient) getHTTPClient() *http.Client {
 
 	return client
 }
+
+// statusError is an unexpected HTTP status from the Vault API
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// HTTPStatusCode returns the HTTP status of the failed request
+func (e *statusError) HTTPStatusCode() int {
+	return e.StatusCode
+}
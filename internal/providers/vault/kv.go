@@ -0,0 +1,279 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+)
+
+// MountInfo describes a secrets engine mount
+type MountInfo struct {
+	Path    string            `json:"path"`    // Mount path with trailing slash, e.g. "secret/"
+	Type    string            `json:"type"`    // Engine type, e.g. "kv", "database"
+	Options map[string]string `json:"options"` // Engine options; KV sets "version"
+}
+
+// kvVersion returns the KV engine version of the mount, or 0 for other engines
+func (m *MountInfo) kvVersion() int {
+	if m == nil || (m.Type != "kv" && m.Type != "generic") {
+		return 0
+	}
+	if m.Options["version"] == "2" {
+		return 2
+	}
+	return 1
+}
+
+// kvPath is a secret path split into its mount and the path inside it
+type kvPath struct {
+	mount   string // Mount path with trailing slash; empty when unknown
+	rel     string // Path inside the mount, without the KV v2 "data/" segment
+	version int    // 2 for KV v2; 1 for KV v1 and every other engine
+	dynamic bool   // Path is on an engine that issues leased credentials
+}
+
+// api returns the KV v2 API path for an endpoint such as "data", "metadata",
+// "delete" or "destroy". KV v1 paths have no endpoint segment.
+func (k kvPath) api(endpoint string) string {
+	if k.version != 2 {
+		return k.mount + k.rel
+	}
+	return k.mount + endpoint + "/" + k.rel
+}
+
+func (k kvPath) dataPath() string     { return k.api("data") }
+func (k kvPath) metadataPath() string { return k.api("metadata") }
+
+// secret unwraps a read of dataPath. KV v2 nests the secret under data.data
+// next to data.metadata; KV v1 and other engines return the secret itself.
+func (k kvPath) secret(read *VaultSecret) *VaultSecret {
+	if k.version != 2 || read == nil {
+		return read
+	}
+	data, _ := read.Data["data"].(map[string]interface{})
+	metadata, _ := read.Data["metadata"].(map[string]interface{})
+	return &VaultSecret{
+		Data:          data,
+		Metadata:      metadata,
+		LeaseID:       read.LeaseID,
+		LeaseDuration: read.LeaseDuration,
+		Renewable:     read.Renewable,
+	}
+}
+
+// kvPathFor maps a path as written in dsops.yaml to its KV paths. KV v2
+// mounts accept both "secret/data/app" and "secret/app". When the mount
+// cannot be looked up, a "/data/" segment marks the path as KV v2 and a
+// "/creds/" or "/sts/" segment as a dynamic secret.
+func (v *VaultProvider) kvPathFor(ctx context.Context, path string) kvPath {
+	mount := v.mountFor(ctx, path)
+	if mount == nil || mount.Type == "" {
+		if idx := strings.Index(path, "/data/"); idx >= 0 {
+			return kvPath{mount: path[:idx+1], rel: path[idx+len("/data/"):], version: 2}
+		}
+		dynamic := strings.Contains(path, "/creds/") || strings.Contains(path, "/sts/")
+		return kvPath{rel: path, version: 1, dynamic: dynamic}
+	}
+
+	rel := strings.TrimPrefix(path, mount.Path)
+	switch mount.kvVersion() {
+	case 0:
+		// Engines other than KV (database, aws, ...) issue leased credentials;
+		// cubbyhole is the one other static store
+		return kvPath{mount: mount.Path, rel: rel, version: 1, dynamic: mount.Type != "cubbyhole"}
+	case 1:
+		return kvPath{mount: mount.Path, rel: rel, version: 1}
+	}
+	return kvPath{mount: mount.Path, rel: strings.TrimPrefix(rel, "data/"), version: 2}
+}
+
+// mountFor returns the cached mount for path, looking it up on first use.
+// Lookup failures are cached as a mount with no type so the path heuristic
+// is used without asking Vault again.
+func (v *VaultProvider) mountFor(ctx context.Context, path string) *MountInfo {
+	v.mountsMu.Lock()
+	if cached := longestMount(v.mounts, path); cached != nil {
+		v.mountsMu.Unlock()
+		return cached
+	}
+	v.mountsMu.Unlock()
+
+	info, err := v.client.MountInfo(ctx, path)
+	if err != nil || info == nil || info.Path == "" {
+		if err != nil {
+			v.logger.Debug("Vault mount lookup failed for %s, using path conventions: %v", path, err)
+		}
+		first, _, _ := strings.Cut(path, "/")
+		info = &MountInfo{Path: first + "/"}
+	}
+	if !strings.HasSuffix(info.Path, "/") {
+		info.Path += "/"
+	}
+
+	v.mountsMu.Lock()
+	defer v.mountsMu.Unlock()
+	if v.mounts == nil {
+		v.mounts = make(map[string]*MountInfo)
+	}
+	v.mounts[info.Path] = info
+	return info
+}
+
+func longestMount(mounts map[string]*MountInfo, path string) *MountInfo {
+	var best *MountInfo
+	for mountPath, info := range mounts {
+		if strings.HasPrefix(path, mountPath) && (best == nil || len(mountPath) > len(best.Path)) {
+			best = info
+		}
+	}
+	return best
+}
+
+var inlineVersionPattern = regexp.MustCompile(`@(v?[0-9]+|latest)$`)
+
+// splitVersion removes a trailing "@version" from a path
+func splitVersion(path string) (string, string) {
+	loc := inlineVersionPattern.FindStringIndex(path)
+	if loc == nil {
+		return path, ""
+	}
+	return path[:loc[0]], path[loc[0]+1:]
+}
+
+// normalizeVersion turns "v3" into "3" and "latest" into "". KV v2 versions
+// are positive integers.
+func normalizeVersion(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" || version == "latest" {
+		return "", nil
+	}
+	if n, err := strconv.Atoi(version); err != nil || n < 1 {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Invalid Vault secret version: %s", version),
+			Suggestion: "KV v2 versions are positive numbers, e.g. '@3' or version: 3",
+		}
+	}
+	return version, nil
+}
+
+// pickVersion combines an inline "@version" with Reference.Version
+func pickVersion(inline, refVersion string) (string, error) {
+	inline, err := normalizeVersion(inline)
+	if err != nil {
+		return "", err
+	}
+	refVersion, err = normalizeVersion(refVersion)
+	if err != nil {
+		return "", err
+	}
+	if inline != "" && refVersion != "" && inline != refVersion {
+		return "", dserrors.UserError{
+			Message:    fmt.Sprintf("Conflicting Vault versions: @%s in the key and version %s", inline, refVersion),
+			Suggestion: "Pin the version in one place only",
+		}
+	}
+	if inline != "" {
+		return inline, nil
+	}
+	return refVersion, nil
+}
+
+// kvMetadata is the KV v2 metadata of a secret
+type kvMetadata struct {
+	CurrentVersion int
+	MaxVersions    int
+	CreatedTime    time.Time
+	UpdatedTime    time.Time
+	Versions       map[string]kvVersionInfo
+	CustomMetadata map[string]string
+}
+
+// kvVersionInfo is the metadata of one KV v2 version
+type kvVersionInfo struct {
+	CreatedTime  time.Time
+	DeletionTime time.Time
+	Destroyed    bool
+}
+
+// Deleted reports whether the version was soft-deleted or destroyed
+func (i kvVersionInfo) Deleted() bool {
+	return i.Destroyed || !i.DeletionTime.IsZero()
+}
+
+// readKVMetadata reads the metadata of a KV v2 secret. It returns nil when
+// the secret does not exist.
+func (v *VaultProvider) readKVMetadata(ctx context.Context, kv kvPath) (*kvMetadata, error) {
+	secret, err := v.client.Read(ctx, kv.metadataPath())
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    "Failed to read secret metadata from Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+	return parseKVMetadata(secret.Data), nil
+}
+
+func parseKVMetadata(data map[string]interface{}) *kvMetadata {
+	md := &kvMetadata{
+		CurrentVersion: intValue(data["current_version"]),
+		MaxVersions:    intValue(data["max_versions"]),
+		CreatedTime:    timeValue(data["created_time"]),
+		UpdatedTime:    timeValue(data["updated_time"]),
+		Versions:       make(map[string]kvVersionInfo),
+		CustomMetadata: make(map[string]string),
+	}
+
+	if versions, ok := data["versions"].(map[string]interface{}); ok {
+		for number, raw := range versions {
+			info, _ := raw.(map[string]interface{})
+			destroyed, _ := info["destroyed"].(bool)
+			md.Versions[number] = kvVersionInfo{
+				CreatedTime:  timeValue(info["created_time"]),
+				DeletionTime: timeValue(info["deletion_time"]),
+				Destroyed:    destroyed,
+			}
+		}
+	}
+
+	if custom, ok := data["custom_metadata"].(map[string]interface{}); ok {
+		for k, val := range custom {
+			md.CustomMetadata[k] = fmt.Sprintf("%v", val)
+		}
+	}
+
+	return md
+}
+
+// intValue converts a JSON number to an int
+func intValue(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	default:
+		return 0
+	}
+}
+
+// timeValue parses an RFC 3339 timestamp; Vault uses "" for unset times
+func timeValue(v interface{}) time.Time {
+	s, _ := v.(string)
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,165 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// dynamicRead is the shared result of reading a dynamic secret path. The
+// first caller performs the read; later callers wait on done.
+type dynamicRead struct {
+	done   chan struct{}
+	secret *VaultSecret
+	err    error
+	lease  *provider.Lease // nil when the engine returned no lease
+}
+
+// readDynamic reads a dynamic secret once per path. Dynamic engines issue new
+// credentials on every read, so variables taking different fields of the same
+// path must share one read to get a matching username and password.
+func (v *VaultProvider) readDynamic(ctx context.Context, path string) (*VaultSecret, error) {
+	v.leasesMu.Lock()
+	if v.dynamic == nil {
+		v.dynamic = make(map[string]*dynamicRead)
+	}
+	read, found := v.dynamic[path]
+	if !found {
+		read = &dynamicRead{done: make(chan struct{})}
+		v.dynamic[path] = read
+	}
+	v.leasesMu.Unlock()
+
+	if found {
+		select {
+		case <-read.done:
+			return read.secret, read.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	read.secret, read.err = v.client.Read(ctx, path)
+	if read.err == nil && read.secret != nil && read.secret.LeaseID != "" {
+		read.lease = &provider.Lease{
+			ID:        read.secret.LeaseID,
+			Provider:  v.name,
+			Path:      path,
+			Duration:  time.Duration(read.secret.LeaseDuration) * time.Second,
+			Renewable: read.secret.Renewable,
+			IssuedAt:  time.Now(),
+		}
+		v.logger.Debug("Vault issued lease %s for %s (ttl %s)", read.lease.ID, logging.Secret(path), read.lease.Duration)
+	}
+	if read.err != nil {
+		// Let a later resolve retry instead of caching the failure
+		v.leasesMu.Lock()
+		delete(v.dynamic, path)
+		v.leasesMu.Unlock()
+	}
+	close(read.done)
+
+	return read.secret, read.err
+}
+
+// Leases returns the leases of dynamic secrets read so far, oldest first
+func (v *VaultProvider) Leases() []provider.Lease {
+	v.leasesMu.Lock()
+	defer v.leasesMu.Unlock()
+
+	var leases []provider.Lease
+	for _, read := range v.dynamic {
+		select {
+		case <-read.done:
+		default:
+			continue // Still being read
+		}
+		if read.lease != nil {
+			leases = append(leases, *read.lease)
+		}
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].IssuedAt.Before(leases[j].IssuedAt) })
+	return leases
+}
+
+// RenewLease extends a lease. Vault may grant less than increment when the
+// role's max TTL is near.
+func (v *VaultProvider) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (provider.Lease, error) {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return provider.Lease{}, fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	lease, found := v.findLease(leaseID)
+	if !found {
+		return provider.Lease{}, &provider.NotFoundError{Provider: v.name, Key: leaseID}
+	}
+
+	resp, err := v.client.RenewLease(ctx, leaseID, int(increment.Seconds()))
+	if err != nil {
+		return provider.Lease{}, dserrors.UserError{
+			Message:    "Failed to renew Vault lease",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+
+	lease.Duration = time.Duration(resp.LeaseDuration) * time.Second
+	lease.Renewable = resp.Renewable
+	lease.IssuedAt = time.Now()
+	v.updateLease(lease)
+
+	return *lease, nil
+}
+
+// RevokeLease revokes a lease. A later resolve of the same path reads new
+// credentials.
+func (v *VaultProvider) RevokeLease(ctx context.Context, leaseID string) error {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	if err := v.client.RevokeLease(ctx, leaseID); err != nil {
+		return dserrors.UserError{
+			Message:    "Failed to revoke Vault lease",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+
+	v.leasesMu.Lock()
+	defer v.leasesMu.Unlock()
+	for path, read := range v.dynamic {
+		if read.lease != nil && read.lease.ID == leaseID {
+			delete(v.dynamic, path)
+		}
+	}
+	return nil
+}
+
+// findLease returns a copy of the lease with the given ID
+func (v *VaultProvider) findLease(leaseID string) (*provider.Lease, bool) {
+	for _, lease := range v.Leases() {
+		if lease.ID == leaseID {
+			lease := lease
+			return &lease, true
+		}
+	}
+	return nil, false
+}
+
+// updateLease stores a renewed lease
+func (v *VaultProvider) updateLease(lease *provider.Lease) {
+	v.leasesMu.Lock()
+	defer v.leasesMu.Unlock()
+	for _, read := range v.dynamic {
+		if read.lease != nil && read.lease.ID == lease.ID {
+			updated := *lease
+			read.lease = &updated
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+const (
+	// tokenFileName is where the Vault CLI's default token helper keeps the
+	// token of the last login, in the home directory
+	tokenFileName = ".vault-token"
+
+	// DefaultOIDCCallbackPort is the port of the OIDC redirect URI, the same
+	// as the Vault CLI's
+	DefaultOIDCCallbackPort = 8250
+)
+
+// Login signs in with the store's auth method and saves the token to
+// ~/.vault-token, where later runs of dsops and the Vault CLI find it.
+// Passwords and tokens are prompted for unless configured; OIDC opens the
+// role's login page and waits for the browser to come back.
+func (v *VaultProvider) Login(ctx context.Context, prompt provider.LoginPrompt) error {
+	client, ok := v.client.(*HTTPVaultClient)
+	if !ok {
+		return fmt.Errorf("interactive login is not available for this Vault client")
+	}
+
+	switch v.config.AuthMethod {
+	case "token", "userpass", "ldap", "oidc":
+	default:
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Vault auth method '%s' does not sign in interactively", v.config.AuthMethod),
+			Suggestion: "dsops signs in automatically with this method; use auth_method token, userpass, ldap or oidc for interactive logins",
+		}
+	}
+
+	if err := client.login(ctx, prompt); err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Vault login for store '%s' failed", v.name),
+			Details:    err.Error(),
+			Suggestion: "Check the address, auth_mount and credentials of the store",
+		}
+	}
+	return nil
+}
+
+// login obtains a token interactively and saves it
+func (c *HTTPVaultClient) login(ctx context.Context, prompt provider.LoginPrompt) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = ""
+	c.setTokenLifetime(0, false)
+
+	var err error
+	switch c.config.AuthMethod {
+	case "token":
+		err = c.loginTokenLocked(ctx, prompt)
+	case "userpass":
+		err = c.loginPasswordLocked(ctx, prompt, "userpass", c.config.UserpassUsername, c.config.UserpassPassword, "VAULT_USERPASS_PASSWORD")
+	case "ldap":
+		err = c.loginPasswordLocked(ctx, prompt, "ldap", c.config.LDAPUsername, c.config.LDAPPassword, "VAULT_LDAP_PASSWORD")
+	case "oidc":
+		err = c.loginOIDCLocked(ctx, prompt)
+	}
+	if err != nil {
+		c.token = ""
+		return err
+	}
+
+	return saveToken(c.token)
+}
+
+// loginTokenLocked asks for a token and checks it with Vault
+// Must be called with c.mu held
+func (c *HTTPVaultClient) loginTokenLocked(ctx context.Context, prompt provider.LoginPrompt) error {
+	token, err := prompt.ReadSecret("Vault token")
+	if err != nil {
+		return err
+	}
+	c.token = strings.TrimSpace(token)
+	if c.token == "" {
+		return fmt.Errorf("no token entered")
+	}
+	return c.validateTokenLocked(ctx)
+}
+
+// loginPasswordLocked logs in to a username/password method, asking for the
+// password when it is not configured
+// Must be called with c.mu held
+func (c *HTTPVaultClient) loginPasswordLocked(ctx context.Context, prompt provider.LoginPrompt, method, username, password, envVar string) error {
+	if username == "" {
+		return fmt.Errorf("%s_username is not set on the store", method)
+	}
+	if password == "" {
+		password = os.Getenv(envVar)
+	}
+	if password == "" {
+		var err error
+		password, err = prompt.ReadSecret(fmt.Sprintf("Password for %s", username))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.performLoginLocked(ctx, c.authMount(method)+"/"+username, map[string]interface{}{
+		"password": password,
+	})
+}
+
+// loginOIDCLocked runs Vault's OIDC authorization code flow: Vault returns
+// the provider's login URL for a redirect to a local listener, and the code
+// the browser brings back is exchanged for a token
+// Must be called with c.mu held
+func (c *HTTPVaultClient) loginOIDCLocked(ctx context.Context, prompt provider.LoginPrompt) error {
+	nonce, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+
+	port := c.config.OIDCCallbackPort
+	if port == 0 {
+		port = DefaultOIDCCallbackPort
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for the OIDC callback on port %d: %w", port, err)
+	}
+	redirectURI := fmt.Sprintf("http://localhost:%d/oidc/callback", port)
+
+	mount := c.authPath("oidc")
+	var authURL struct {
+		Data struct {
+			AuthURL string `json:"auth_url"`
+		} `json:"data"`
+	}
+	err = c.authRequestLocked(ctx, http.MethodPost, mount+"/oidc/auth_url", map[string]interface{}{
+		"role":         c.config.JWTRole,
+		"redirect_uri": redirectURI,
+		"client_nonce": nonce,
+	}, &authURL)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+	if authURL.Data.AuthURL == "" {
+		_ = listener.Close()
+		return fmt.Errorf("vault returned no login URL; check that the role allows the redirect URI %s", redirectURI)
+	}
+
+	callbacks := make(chan url.Values, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oidc/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		select {
+		case callbacks <- r.URL.Query():
+		default:
+		}
+		_, _ = io.WriteString(w, "Signed in to Vault. You can close this window and return to dsops.\n")
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	prompt.Visit(authURL.Data.AuthURL, "")
+
+	var query url.Values
+	select {
+	case query = <-callbacks:
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for the browser login: %w", ctx.Err())
+	}
+	if msg := query.Get("error_description"); msg != "" {
+		return fmt.Errorf("OIDC login failed: %s", msg)
+	}
+	if msg := query.Get("error"); msg != "" {
+		return fmt.Errorf("OIDC login failed: %s", msg)
+	}
+
+	params := url.Values{
+		"state":        {query.Get("state")},
+		"code":         {query.Get("code")},
+		"id_token":     {query.Get("id_token")},
+		"client_nonce": {nonce},
+	}
+	var login struct {
+		Auth vaultAuth `json:"auth"`
+	}
+	if err := c.authRequestLocked(ctx, http.MethodGet, mount+"/oidc/callback?"+params.Encode(), nil, &login); err != nil {
+		return err
+	}
+	if login.Auth.ClientToken == "" {
+		return fmt.Errorf("no token received from vault")
+	}
+
+	c.token = login.Auth.ClientToken
+	c.setTokenLifetime(login.Auth.LeaseDuration, login.Auth.Renewable)
+	return nil
+}
+
+// authRequestLocked sends an unauthenticated request to an auth endpoint and
+// decodes the response into out
+// Must be called with c.mu held
+func (c *HTTPVaultClient) authRequestLocked(ctx context.Context, method, path string, data map[string]interface{}, out interface{}) error {
+	var body io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal auth data: %w", err)
+		}
+		body = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.config.Address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return fmt.Errorf("failed to create auth request: %w", err)
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make auth request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode auth response: %w", err)
+	}
+	return nil
+}
+
+// useSavedTokenLocked switches to the saved token if Vault still accepts it
+// Must be called with c.mu held
+func (c *HTTPVaultClient) useSavedTokenLocked(ctx context.Context) bool {
+	token, err := readSavedToken()
+	if err != nil || token == "" {
+		return false
+	}
+
+	c.token = token
+	if c.validateTokenLocked(ctx) == nil {
+		return true
+	}
+	c.token = ""
+	c.setTokenLifetime(0, false)
+	return false
+}
+
+// tokenFilePath returns the path of ~/.vault-token
+func tokenFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, tokenFileName), nil
+}
+
+// readSavedToken reads ~/.vault-token
+func readSavedToken() (string, error) {
+	path, err := tokenFilePath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// saveToken writes ~/.vault-token, readable by the owner only
+func saveToken(token string) error {
+	path, err := tokenFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+		return fmt.Errorf("failed to save the Vault token: %w", err)
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,192 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// fakePrompt answers ReadSecret with secret and records Visit calls. When
+// visit is set it is run for each visited URL, standing in for the browser.
+type fakePrompt struct {
+	secret  string
+	prompts []string
+	visited []string
+	visit   func(url string)
+}
+
+func (p *fakePrompt) Visit(url, code string) {
+	p.visited = append(p.visited, url)
+	if p.visit != nil {
+		p.visit(url)
+	}
+}
+
+func (p *fakePrompt) ReadSecret(prompt string) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.secret, nil
+}
+
+func newLoginProvider(t *testing.T, config map[string]interface{}) *VaultProvider {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_USERPASS_PASSWORD", "")
+	p, err := NewVaultProvider("vault", config)
+	require.NoError(t, err)
+	return p.(*VaultProvider)
+}
+
+func savedToken(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), tokenFileName))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestVaultProvider_LoginUserpass(t *testing.T) {
+	fake, server := newFakeVault(t)
+	p := newLoginProvider(t, map[string]interface{}{
+		"address":           server.URL,
+		"auth_method":       "userpass",
+		"userpass_username": "alice",
+	})
+	prompt := &fakePrompt{secret: "hunter2"}
+
+	require.NoError(t, p.Login(context.Background(), prompt))
+	assert.Equal(t, []string{"Password for alice"}, prompt.prompts)
+	assert.Equal(t, "hunter2", fake.logins[0]["password"])
+	assert.Contains(t, fake.requests(), "POST /v1/auth/userpass/login/alice")
+	assert.Equal(t, "login-token", savedToken(t))
+
+	info, err := os.Stat(filepath.Join(os.Getenv("HOME"), tokenFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A new run uses the saved token instead of asking for the password
+	next, err := NewVaultProvider("vault", map[string]interface{}{
+		"address":           server.URL,
+		"auth_method":       "userpass",
+		"userpass_username": "alice",
+	})
+	require.NoError(t, err)
+	require.NoError(t, next.(*VaultProvider).client.Authenticate(context.Background()))
+	assert.Len(t, fake.logins, 1)
+	assert.Equal(t, "GET /v1/auth/token/lookup-self", fake.requests()[len(fake.requests())-1])
+}
+
+func TestVaultProvider_LoginOIDC(t *testing.T) {
+	port := freePort(t)
+	var authURLBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/oidc/oidc/auth_url":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&authURLBody))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"auth_url": "https://idp.example.com/authorize?state=st4te"},
+			})
+		case "/v1/auth/oidc/oidc/callback":
+			q := r.URL.Query()
+			if q.Get("state") != "st4te" || q.Get("code") != "c0de" || q.Get("client_nonce") != authURLBody["client_nonce"] {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": "oidc-token", "lease_duration": 3600, "renewable": true},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := newLoginProvider(t, map[string]interface{}{
+		"address":            server.URL,
+		"auth_method":        "oidc",
+		"jwt_role":           "developer",
+		"oidc_callback_port": port,
+	})
+
+	// The browser returns to the redirect URI after signing in
+	prompt := &fakePrompt{visit: func(string) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/oidc/callback?state=st4te&code=c0de", port))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, p.Login(ctx, prompt))
+	assert.Equal(t, []string{"https://idp.example.com/authorize?state=st4te"}, prompt.visited)
+	assert.Equal(t, "developer", authURLBody["role"])
+	assert.Equal(t, fmt.Sprintf("http://localhost:%d/oidc/callback", port), authURLBody["redirect_uri"])
+	assert.Equal(t, "oidc-token", savedToken(t))
+}
+
+func TestVaultProvider_LoginOIDC_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"auth_url": "https://idp.example.com/authorize"},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := newLoginProvider(t, map[string]interface{}{
+		"address":            server.URL,
+		"auth_method":        "oidc",
+		"oidc_callback_port": freePort(t),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := p.Login(ctx, &fakePrompt{})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Details, "timed out waiting for the browser login")
+}
+
+func TestVaultProvider_LoginToken(t *testing.T) {
+	fake, server := newFakeVault(t)
+	p := newLoginProvider(t, map[string]interface{}{"address": server.URL})
+
+	require.NoError(t, p.Login(context.Background(), &fakePrompt{secret: "hvs.typed\n"}))
+	assert.Equal(t, "hvs.typed", savedToken(t))
+	assert.Equal(t, []string{"GET /v1/auth/token/lookup-self"}, fake.requests())
+
+	// The token method falls back to the saved token
+	next, err := NewVaultProvider("vault", map[string]interface{}{"address": server.URL})
+	require.NoError(t, err)
+	require.NoError(t, next.(*VaultProvider).client.Authenticate(context.Background()))
+}
+
+func TestVaultProvider_LoginUnsupported(t *testing.T) {
+	p := newLoginProvider(t, map[string]interface{}{"auth_method": "approle"})
+
+	var authenticator provider.Authenticator = p
+	err := authenticator.Login(context.Background(), &fakePrompt{})
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Message, "does not sign in interactively")
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
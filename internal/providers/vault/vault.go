@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+
 	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/pkg/provider"
@@ -26,13 +29,20 @@ type VaultProvider struct {
 	config Config
 	logger *logging.Logger
 	client VaultClient
+
+	mountsMu sync.Mutex
+	mounts   map[string]*MountInfo // Looked-up mounts by path
+
+	leasesMu sync.Mutex
+	dynamic  map[string]*dynamicRead // Dynamic secret reads by path, one lease each
 }
 
 // Config holds Vault-specific configuration
 type Config struct {
 	Address    string `yaml:"address"`     // Vault server address
 	Token      string `yaml:"token"`       // Vault token (discouraged, use env var)
-	AuthMethod string `yaml:"auth_method"` // Authentication method: token, userpass, ldap, aws, k8s
+	AuthMethod string `yaml:"auth_method"` // Authentication method: token, userpass, ldap, aws, k8s, approle, jwt, oidc
+	AuthMount  string `yaml:"auth_mount"`  // Auth method mount path when not the default, e.g. "approle-ci"
 	Namespace  string `yaml:"namespace"`   // Vault namespace (Vault Enterprise)
 
 	// Auth method specific configs
@@ -41,18 +51,39 @@ type Config struct {
 	LDAPUsername     string `yaml:"ldap_username"`     // For LDAP auth
 	LDAPPassword     string `yaml:"ldap_password"`     // For LDAP auth (discouraged)
 	AWSRole          string `yaml:"aws_role"`          // For AWS auth
+	AWSRegion        string `yaml:"aws_region"`        // For AWS auth: STS region (default: global endpoint)
+	AWSHeaderValue   string `yaml:"aws_header_value"`  // For AWS auth: X-Vault-AWS-IAM-Server-ID value
 	K8SRole          string `yaml:"k8s_role"`          // For Kubernetes auth
 
+	AppRoleRoleID       string `yaml:"approle_role_id"`        // For AppRole auth (or VAULT_ROLE_ID)
+	AppRoleRoleIDFile   string `yaml:"approle_role_id_file"`   // For AppRole auth: file holding the role ID
+	AppRoleSecretIDFile string `yaml:"approle_secret_id_file"` // For AppRole auth: file holding the secret ID (or VAULT_SECRET_ID)
+
+	JWTRole      string `yaml:"jwt_role"`       // For JWT/OIDC auth: role to log in as
+	JWTTokenFile string `yaml:"jwt_token_file"` // For JWT/OIDC auth: file holding the JWT (or VAULT_JWT)
+	JWTAudience  string `yaml:"jwt_audience"`   // For JWT/OIDC auth: audience of GitHub Actions OIDC tokens
+
+	OIDCCallbackPort int `yaml:"oidc_callback_port"` // For dsops login with OIDC: local port of the redirect URI (default 8250)
+
 	// Optional settings
 	CACert     string `yaml:"ca_cert"`     // Path to CA certificate
 	ClientCert string `yaml:"client_cert"` // Path to client certificate
 	ClientKey  string `yaml:"client_key"`  // Path to client key
 	TLSSkip    bool   `yaml:"tls_skip"`    // Skip TLS verification (not recommended)
+
+	// Rotation settings
+	VersionDeprecation string `yaml:"version_deprecation"` // How rotation retires KV v2 versions: delete (default) or destroy
 }
 
 // VaultClient interface for testability
 type VaultClient interface {
 	Read(ctx context.Context, path string) (*VaultSecret, error)
+	Write(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error)
+	Delete(ctx context.Context, path string) error
+	List(ctx context.Context, path string) ([]string, error)
+	MountInfo(ctx context.Context, path string) (*MountInfo, error)
+	RenewLease(ctx context.Context, leaseID string, increment int) (*VaultSecret, error)
+	RevokeLease(ctx context.Context, leaseID string) error
 	Authenticate(ctx context.Context) error
 	Close() error
 }
@@ -61,14 +92,27 @@ type VaultClient interface {
 type VaultSecret struct {
 	Data     map[string]interface{} `json:"data"`
 	Metadata map[string]interface{} `json:"metadata"`
+
+	// Lease of a dynamic secret; empty for static secrets
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"` // Seconds
+	Renewable     bool   `json:"renewable"`
 }
 
 // HTTPVaultClient implements VaultClient using HTTP API
 type HTTPVaultClient struct {
 	config Config
 	token  string
-	mu     sync.RWMutex // protects token field for concurrent access
+	mu     sync.RWMutex // protects token fields for concurrent access
 	logger *logging.Logger
+
+	// Lifetime of the current token; zero expiry when unknown
+	tokenExpiry    time.Time
+	tokenTTL       time.Duration
+	tokenRenewable bool
+
+	// AWS credentials for AWS auth; nil uses the default credential chain
+	awsCredsProvider aws.CredentialsProvider
 }
 
 // NewVaultProvider creates a new Vault provider
@@ -112,6 +156,36 @@ func NewVaultProvider(name string, configMap map[string]interface{}) (provider.P
 	if role, ok := configMap["k8s_role"].(string); ok {
 		config.K8SRole = role
 	}
+	if mount, ok := configMap["auth_mount"].(string); ok {
+		config.AuthMount = mount
+	}
+	if region, ok := configMap["aws_region"].(string); ok {
+		config.AWSRegion = region
+	}
+	if header, ok := configMap["aws_header_value"].(string); ok {
+		config.AWSHeaderValue = header
+	}
+	if roleID, ok := configMap["approle_role_id"].(string); ok {
+		config.AppRoleRoleID = roleID
+	}
+	if file, ok := configMap["approle_role_id_file"].(string); ok {
+		config.AppRoleRoleIDFile = file
+	}
+	if file, ok := configMap["approle_secret_id_file"].(string); ok {
+		config.AppRoleSecretIDFile = file
+	}
+	if role, ok := configMap["jwt_role"].(string); ok {
+		config.JWTRole = role
+	}
+	if file, ok := configMap["jwt_token_file"].(string); ok {
+		config.JWTTokenFile = file
+	}
+	if audience, ok := configMap["jwt_audience"].(string); ok {
+		config.JWTAudience = audience
+	}
+	if port, ok := configMap["oidc_callback_port"].(int); ok {
+		config.OIDCCallbackPort = port
+	}
 	if caCert, ok := configMap["ca_cert"].(string); ok {
 		config.CACert = caCert
 	}
@@ -124,6 +198,9 @@ func NewVaultProvider(name string, configMap map[string]interface{}) (provider.P
 	if tlsSkip, ok := configMap["tls_skip"].(bool); ok {
 		config.TLSSkip = tlsSkip
 	}
+	if deprecation, ok := configMap["version_deprecation"].(string); ok {
+		config.VersionDeprecation = deprecation
+	}
 
 	// Override with environment variables
 	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
@@ -175,20 +252,41 @@ func (v *VaultProvider) Resolve(ctx context.Context, ref provider.Reference) (pr
 	}
 
 	// Parse the reference
-	path, field, err := v.parseReference(ref.Key)
+	path, field, inlineVersion, err := v.parseReference(ref.Key)
 	if err != nil {
 		return provider.SecretValue{}, err
 	}
+	version, err := pickVersion(inlineVersion, ref.Version)
+	if err != nil {
+		return provider.SecretValue{}, err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	readPath := kv.dataPath()
+	if version != "" {
+		if kv.version != 2 {
+			return provider.SecretValue{}, kvV1VersionError(path)
+		}
+		readPath += "?version=" + version
+	}
 
-	v.logger.Debug("Fetching secret from Vault path: %s, field: %s", logging.Secret(path), logging.Secret(field))
+	v.logger.Debug("Fetching secret from Vault path: %s, field: %s", logging.Secret(readPath), logging.Secret(field))
 
-	// Read secret from Vault
-	secret, err := v.client.Read(ctx, path)
+	// Read secret from Vault. Dynamic secrets are read once per path so
+	// every field comes from the same lease.
+	var secret *VaultSecret
+	if kv.dynamic {
+		secret, err = v.readDynamic(ctx, readPath)
+	} else {
+		secret, err = v.client.Read(ctx, readPath)
+		secret = kv.secret(secret)
+	}
 	if err != nil {
 		return provider.SecretValue{}, dserrors.UserError{
 			Message:    "Failed to read secret from Vault",
 			Details:    err.Error(),
 			Suggestion: v.getVaultErrorSuggestion(err),
+			Err:        err,
 		}
 	}
 
@@ -196,6 +294,7 @@ func (v *VaultProvider) Resolve(ctx context.Context, ref provider.Reference) (pr
 		return provider.SecretValue{}, dserrors.UserError{
 			Message:    fmt.Sprintf("Secret not found at path: %s", path),
 			Suggestion: "Check that the secret exists and you have read permissions",
+			Err:        &provider.NotFoundError{Provider: v.name, Key: ref.Key},
 		}
 	}
 
@@ -245,33 +344,113 @@ func (v *VaultProvider) Resolve(ctx context.Context, ref provider.Reference) (pr
 		}
 	}
 
-	return provider.SecretValue{
+	result := provider.SecretValue{
 		Value: value,
 		Metadata: map[string]string{
 			"source": fmt.Sprintf("vault:%s", path),
 			"path":   path,
 		},
-	}, nil
+	}
+	if secret.Metadata != nil {
+		if n := intValue(secret.Metadata["version"]); n > 0 {
+			result.Version = strconv.Itoa(n)
+		}
+		result.UpdatedAt = timeValue(secret.Metadata["created_time"])
+	}
+
+	return result, nil
 }
 
-// Describe returns metadata about a secret without fetching its value
+// Describe returns metadata about a secret without fetching its value. KV v2
+// secrets are described from their metadata: the current or pinned version,
+// when it was written, and custom metadata as tags. A pinned version that was
+// deleted or destroyed is reported as deprecated.
 func (v *VaultProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
-	path, field, err := v.parseReference(ref.Key)
+	if err := v.client.Authenticate(ctx); err != nil {
+		return provider.Metadata{}, fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, field, inlineVersion, err := v.parseReference(ref.Key)
+	if err != nil {
+		return provider.Metadata{}, err
+	}
+	version, err := pickVersion(inlineVersion, ref.Version)
 	if err != nil {
 		return provider.Metadata{}, err
 	}
 
-	return provider.Metadata{
-		Type: "vault-secret",
-		Tags: map[string]string{
-			"source":      fmt.Sprintf("vault:%s", path),
-			"path":        path,
-			"field":       field,
-			"address":     v.config.Address,
-			"namespace":   v.config.Namespace,
-			"auth_method": v.config.AuthMethod,
-		},
-	}, nil
+	kv := v.kvPathFor(ctx, path)
+	tags := map[string]string{}
+	meta := provider.Metadata{Type: "vault-secret", Tags: tags}
+
+	switch {
+	case kv.dynamic:
+		// Reading a dynamic path issues new credentials, so it is not read
+		// here. The path is assumed to exist.
+		if version != "" {
+			return provider.Metadata{}, kvV1VersionError(path)
+		}
+		meta.Type = "vault-dynamic-secret"
+		meta.Exists = true
+	case kv.version != 2:
+		if version != "" {
+			return provider.Metadata{}, kvV1VersionError(path)
+		}
+		secret, err := v.client.Read(ctx, kv.dataPath())
+		if err != nil {
+			return provider.Metadata{}, dserrors.UserError{
+				Message:    "Failed to read secret from Vault",
+				Details:    err.Error(),
+				Suggestion: v.getVaultErrorSuggestion(err),
+			}
+		}
+		meta.Exists = secret != nil && secret.Data != nil
+		if meta.Exists && field != "" {
+			_, meta.Exists = secret.Data[field]
+		}
+	default:
+		md, err := v.readKVMetadata(ctx, kv)
+		if err != nil {
+			return provider.Metadata{}, err
+		}
+		if md != nil {
+			for k, val := range md.CustomMetadata {
+				tags[k] = val
+			}
+			if !md.CreatedTime.IsZero() {
+				tags["created_time"] = md.CreatedTime.Format(time.RFC3339)
+			}
+			tags["current_version"] = strconv.Itoa(md.CurrentVersion)
+
+			pinned := version != ""
+			if !pinned {
+				version = strconv.Itoa(md.CurrentVersion)
+			}
+			info, found := md.Versions[version]
+			meta.Version = version
+			meta.UpdatedAt = info.CreatedTime
+			if pinned {
+				meta.Exists = found
+				meta.Deprecated = found && info.Deleted()
+			} else {
+				meta.Exists = found && !info.Deleted()
+			}
+		}
+	}
+
+	tags["source"] = fmt.Sprintf("vault:%s", path)
+	tags["path"] = path
+	tags["field"] = field
+	tags["address"] = v.config.Address
+	tags["namespace"] = v.config.Namespace
+	tags["auth_method"] = v.config.AuthMethod
+	if kv.dynamic {
+		tags["dynamic"] = "true"
+	} else {
+		tags["kv_version"] = strconv.Itoa(kv.version)
+	}
+
+	return meta, nil
 }
 
 // Capabilities returns the provider's capabilities
@@ -282,7 +461,7 @@ func (v *VaultProvider) Capabilities() provider.Capabilities {
 		SupportsWatching:   false, // Future feature
 		SupportsBinary:     true,  // Vault can store binary data
 		RequiresAuth:       true,
-		AuthMethods:        []string{"token", "userpass", "ldap", "aws", "k8s"},
+		AuthMethods:        []string{"token", "userpass", "ldap", "aws", "k8s", "approle", "jwt", "oidc"},
 	}
 }
 
@@ -341,12 +520,23 @@ func (v *VaultProvider) Validate(ctx context.Context) error {
 				Suggestion: "Set 'k8s_role' in provider config",
 			}
 		}
+	case "approle":
+		if v.config.AppRoleRoleID == "" && v.config.AppRoleRoleIDFile == "" && os.Getenv("VAULT_ROLE_ID") == "" {
+			return dserrors.ConfigError{
+				Field:      "approle_role_id",
+				Message:    "Role ID is required for AppRole auth",
+				Suggestion: "Set 'approle_role_id' or 'approle_role_id_file' in provider config, or VAULT_ROLE_ID environment variable",
+			}
+		}
+	case "jwt", "oidc":
+		// The JWT may come from a file, VAULT_JWT or the CI runtime; a missing
+		// role is allowed when the auth mount has a default_role
 	default:
 		return dserrors.ConfigError{
 			Field:      "auth_method",
 			Value:      v.config.AuthMethod,
 			Message:    "unsupported authentication method",
-			Suggestion: "Supported methods: token, userpass, ldap, aws, k8s",
+			Suggestion: "Supported methods: token, userpass, ldap, aws, k8s, approle, jwt, oidc",
 		}
 	}
 
@@ -362,14 +552,470 @@ func (v *VaultProvider) Validate(ctx context.Context) error {
 	return nil
 }
 
-// parseReference parses a Vault reference into path and field
+// PutSecret writes a secret to Vault. A "path#field" key updates one field
+// and keeps the others; a bare path replaces the secret with the JSON object
+// in value. On KV v2 mounts tags and the description are stored as custom
+// metadata.
+func (v *VaultProvider) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return "", fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, field, _, err := v.parseReference(ref.Key)
+	if err != nil {
+		return "", err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	fields, err := v.secretFields(ctx, kv, path, field, value)
+	if err != nil {
+		return "", err
+	}
+
+	payload := fields
+	if kv.version == 2 {
+		payload = map[string]interface{}{"data": fields}
+	}
+
+	v.logger.Debug("Writing secret to Vault path: %s", logging.Secret(kv.dataPath()))
+
+	resp, err := v.client.Write(ctx, kv.dataPath(), payload)
+	if err != nil {
+		return "", dserrors.UserError{
+			Message:    "Failed to write secret to Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+
+	if len(opts.Tags) > 0 || opts.Description != "" {
+		if kv.version != 2 {
+			v.logger.Warn("Vault KV v1 does not support metadata; ignoring tags and description for %s", path)
+		} else {
+			custom := make(map[string]interface{}, len(opts.Tags)+1)
+			for k, val := range opts.Tags {
+				custom[k] = val
+			}
+			if opts.Description != "" {
+				custom["description"] = opts.Description
+			}
+			if _, err := v.client.Write(ctx, kv.metadataPath(), map[string]interface{}{"custom_metadata": custom}); err != nil {
+				return "", dserrors.UserError{
+					Message:    "Secret written but updating Vault metadata failed",
+					Details:    err.Error(),
+					Suggestion: v.getVaultErrorSuggestion(err),
+				}
+			}
+		}
+	}
+
+	if version, ok := resp["version"]; ok {
+		return fmt.Sprintf("%v", version), nil
+	}
+	return "", nil
+}
+
+// DeleteSecret deletes a secret from Vault. A "path#field" key removes one
+// field. For KV v2 the latest version, or the pinned one, is soft-deleted;
+// opts.Force destroys a pinned version, or deletes the metadata and every
+// version.
+func (v *VaultProvider) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, field, inlineVersion, err := v.parseReference(ref.Key)
+	if err != nil {
+		return err
+	}
+	version, err := pickVersion(inlineVersion, ref.Version)
+	if err != nil {
+		return err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+
+	if version != "" {
+		if kv.version != 2 {
+			return kvV1VersionError(path)
+		}
+		if field != "" {
+			return dserrors.UserError{
+				Message:    "Cannot delete a field from a pinned version",
+				Suggestion: "Delete the whole version with '" + path + "@" + version + "', or drop the version to remove the field",
+			}
+		}
+		return v.retireVersion(ctx, kv, version, opts.Force)
+	}
+
+	existing, err := v.client.Read(ctx, kv.dataPath())
+	existing = kv.secret(existing)
+	if err != nil {
+		return dserrors.UserError{
+			Message:    "Failed to read secret from Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+	if existing == nil || existing.Data == nil {
+		return &provider.NotFoundError{Provider: v.name, Key: ref.Key}
+	}
+
+	if field != "" {
+		if _, exists := existing.Data[field]; !exists {
+			return &provider.NotFoundError{Provider: v.name, Key: ref.Key}
+		}
+
+		fields := make(map[string]interface{}, len(existing.Data))
+		for k, val := range existing.Data {
+			if k != field {
+				fields[k] = val
+			}
+		}
+
+		payload := fields
+		if kv.version == 2 {
+			payload = map[string]interface{}{"data": fields}
+		}
+		if _, err := v.client.Write(ctx, kv.dataPath(), payload); err != nil {
+			return dserrors.UserError{
+				Message:    "Failed to write secret to Vault",
+				Details:    err.Error(),
+				Suggestion: v.getVaultErrorSuggestion(err),
+			}
+		}
+		return nil
+	}
+
+	deletePath := kv.dataPath()
+	if kv.version == 2 && opts.Force {
+		deletePath = kv.metadataPath()
+	}
+
+	if err := v.client.Delete(ctx, deletePath); err != nil {
+		return dserrors.UserError{
+			Message:    "Failed to delete secret from Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+
+	return nil
+}
+
+// ListSecrets lists the keys under the folder given by opts.Prefix, such as
+// "secret/data/myapp/". KV v2 folders are listed through their metadata
+// path. Returned keys keep the prefix as given; keys ending in "/" are
+// folders.
+func (v *VaultProvider) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	folder := strings.Trim(opts.Prefix, "/")
+	if folder == "" {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Vault listing needs a path",
+			Suggestion: "List a mount or folder, e.g. 'secret/data/' for KV v2 or 'secret/' for KV v1",
+		}
+	}
+
+	if err := v.client.Authenticate(ctx); err != nil {
+		return provider.ListResult{}, fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	listPath := folder
+	if kv := v.kvPathFor(ctx, folder+"/"); kv.version == 2 {
+		listPath = kv.metadataPath()
+	}
+
+	keys, err := v.client.List(ctx, listPath)
+	if err != nil {
+		return provider.ListResult{}, dserrors.UserError{
+			Message:    "Failed to list secrets in Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+
+	secrets := make([]provider.SecretInfo, 0, len(keys))
+	for _, key := range keys {
+		secrets = append(secrets, provider.SecretInfo{Key: folder + "/" + key})
+	}
+
+	return provider.PageSecrets(secrets, provider.ListOptions{
+		PageSize:  opts.PageSize,
+		PageToken: opts.PageToken,
+	})
+}
+
+// secretFields builds the fields to write: the current fields with one
+// replaced for "path#field" keys, or the JSON object in value
+func (v *VaultProvider) secretFields(ctx context.Context, kv kvPath, path, field string, value []byte) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if field == "" {
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return nil, dserrors.UserError{
+				Message:    "Vault secrets must be a JSON object when no field is given",
+				Details:    err.Error(),
+				Suggestion: fmt.Sprintf("Write a single field with '%s#<field>' or pass a JSON object", path),
+			}
+		}
+		return fields, nil
+	}
+
+	existing, err := v.client.Read(ctx, kv.dataPath())
+	existing = kv.secret(existing)
+	if err != nil {
+		return nil, dserrors.UserError{
+			Message:    "Failed to read secret from Vault",
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+	if existing != nil {
+		for k, val := range existing.Data {
+			fields[k] = val
+		}
+	}
+	fields[field] = string(value)
+	return fields, nil
+}
+
+// Rotation support implementation
+
+// CreateNewVersion writes a new KV v2 version of a secret. The write uses
+// check-and-set against the version read first, so a concurrent change fails
+// the rotation instead of being overwritten. Entries in meta are merged into
+// the secret's custom metadata.
+func (v *VaultProvider) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return "", fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, field, _, err := v.parseReference(ref.Key)
+	if err != nil {
+		return "", err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	if kv.version != 2 {
+		return "", kvV1RotationError(path)
+	}
+
+	md, err := v.readKVMetadata(ctx, kv)
+	if err != nil {
+		return "", err
+	}
+	cas := 0
+	if md != nil {
+		cas = md.CurrentVersion
+	}
+
+	fields, err := v.secretFields(ctx, kv, path, field, newValue)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := v.client.Write(ctx, kv.dataPath(), map[string]interface{}{
+		"options": map[string]interface{}{"cas": cas},
+		"data":    fields,
+	})
+	if err != nil {
+		suggestion := v.getVaultErrorSuggestion(err)
+		if strings.Contains(err.Error(), "check-and-set") {
+			suggestion = "The secret changed during rotation. Retry once the other writer has finished"
+		}
+		return "", dserrors.UserError{
+			Message:    "Failed to create new secret version in Vault",
+			Details:    err.Error(),
+			Suggestion: suggestion,
+		}
+	}
+
+	if len(meta) > 0 {
+		custom := make(map[string]interface{})
+		if md != nil {
+			for k, val := range md.CustomMetadata {
+				custom[k] = val
+			}
+		}
+		for k, val := range meta {
+			custom[k] = val
+		}
+		if _, err := v.client.Write(ctx, kv.metadataPath(), map[string]interface{}{"custom_metadata": custom}); err != nil {
+			v.logger.Warn("New version of %s written but updating its metadata failed: %v", path, err)
+		}
+	}
+
+	if n := intValue(resp["version"]); n > 0 {
+		return strconv.Itoa(n), nil
+	}
+	return strconv.Itoa(cas + 1), nil
+}
+
+// DeprecateVersion retires an old KV v2 version. It is soft-deleted, and can
+// be undeleted, unless version_deprecation is "destroy".
+func (v *VaultProvider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	return v.retire(ctx, ref, version, v.config.VersionDeprecation == "destroy")
+}
+
+// DestroyVersion permanently destroys a KV v2 version whatever
+// version_deprecation is set to, so it cannot be undeleted
+func (v *VaultProvider) DestroyVersion(ctx context.Context, ref provider.Reference, version string) error {
+	return v.retire(ctx, ref, version, true)
+}
+
+// retire deletes or destroys a KV v2 version addressed by ref
+func (v *VaultProvider) retire(ctx context.Context, ref provider.Reference, version string, destroy bool) error {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, _, _, err := v.parseReference(ref.Key)
+	if err != nil {
+		return err
+	}
+	version, err = normalizeVersion(version)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return dserrors.UserError{
+			Message:    "A version number is required to deprecate a Vault secret version",
+			Suggestion: "Pass the version returned when the new version was created",
+		}
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	if kv.version != 2 {
+		return kvV1RotationError(path)
+	}
+
+	return v.retireVersion(ctx, kv, version, destroy)
+}
+
+// ListVersions returns the KV v2 versions of a secret that are neither
+// deleted nor destroyed
+func (v *VaultProvider) ListVersions(ctx context.Context, ref provider.Reference) ([]string, error) {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return nil, fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, _, _, err := v.parseReference(ref.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	if kv.version != 2 {
+		return nil, kvV1RotationError(path)
+	}
+
+	md, err := v.readKVMetadata(ctx, kv)
+	if err != nil {
+		return nil, err
+	}
+	if md == nil {
+		return nil, &provider.NotFoundError{Provider: v.name, Key: ref.Key}
+	}
+
+	var numbers []int
+	for number, info := range md.Versions {
+		n, err := strconv.Atoi(number)
+		if err != nil || info.Deleted() {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	versions := make([]string, len(numbers))
+	for i, n := range numbers {
+		versions[i] = strconv.Itoa(n)
+	}
+	return versions, nil
+}
+
+// GetRotationMetadata returns metadata about rotation capabilities for a secret
+func (v *VaultProvider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	if err := v.client.Authenticate(ctx); err != nil {
+		return provider.RotationMetadata{}, fmt.Errorf("vault authentication failed: %w", err)
+	}
+
+	path, _, _, err := v.parseReference(ref.Key)
+	if err != nil {
+		return provider.RotationMetadata{}, err
+	}
+
+	kv := v.kvPathFor(ctx, path)
+	metadata := provider.RotationMetadata{
+		Constraints: map[string]string{
+			"provider":   "vault",
+			"mount":      kv.mount,
+			"kv_version": strconv.Itoa(kv.version),
+		},
+	}
+
+	if kv.version != 2 {
+		metadata.Constraints["reason"] = "KV v1 does not keep versions"
+		return metadata, nil
+	}
+
+	metadata.SupportsRotation = true
+	metadata.SupportsVersioning = true
+	metadata.Constraints["version_deprecation"] = "delete"
+	if v.config.VersionDeprecation == "destroy" {
+		metadata.Constraints["version_deprecation"] = "destroy"
+	}
+
+	md, err := v.readKVMetadata(ctx, kv)
+	if err != nil {
+		return provider.RotationMetadata{}, err
+	}
+	if md != nil {
+		if !md.UpdatedTime.IsZero() {
+			updated := md.UpdatedTime
+			metadata.LastRotated = &updated
+		}
+		if md.MaxVersions > 0 {
+			metadata.Constraints["max_versions"] = strconv.Itoa(md.MaxVersions)
+		}
+	}
+
+	return metadata, nil
+}
+
+// retireVersion soft-deletes a KV v2 version, or destroys it permanently
+func (v *VaultProvider) retireVersion(ctx context.Context, kv kvPath, version string, destroy bool) error {
+	endpoint := "delete"
+	if destroy {
+		endpoint = "destroy"
+	}
+
+	number, _ := strconv.Atoi(version)
+	if _, err := v.client.Write(ctx, kv.api(endpoint), map[string]interface{}{"versions": []int{number}}); err != nil {
+		return dserrors.UserError{
+			Message:    fmt.Sprintf("Failed to %s version %s in Vault", endpoint, version),
+			Details:    err.Error(),
+			Suggestion: v.getVaultErrorSuggestion(err),
+		}
+	}
+	return nil
+}
+
+// kvV1RotationError explains that rotation needs a versioned mount
+func kvV1RotationError(path string) error {
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("Vault path %s is not on a KV v2 mount", path),
+		Suggestion: "Rotation creates and retires versions, which only KV v2 keeps. Enable versioning with 'vault kv enable-versioning'",
+	}
+}
+
+// parseReference parses a Vault reference into path, field and version
 // Supports formats:
 // - "secret/data/myapp" (returns entire secret as JSON)
 // - "secret/data/myapp#password" (returns specific field)
-// - "secret/data/myapp@v2#password" (returns specific version and field)
-func (v *VaultProvider) parseReference(key string) (string, string, error) {
+// - "secret/data/myapp@2#password" (returns specific version and field)
+func (v *VaultProvider) parseReference(key string) (string, string, string, error) {
 	if key == "" {
-		return "", "", dserrors.UserError{
+		return "", "", "", dserrors.UserError{
 			Message:    "Empty vault key",
 			Suggestion: "Provide a vault path like 'secret/data/myapp' or 'secret/data/myapp#field'",
 		}
@@ -381,9 +1027,9 @@ func (v *VaultProvider) parseReference(key string) (string, string, error) {
 	field := ""
 
 	if len(parts) > 2 {
-		return "", "", dserrors.UserError{
+		return "", "", "", dserrors.UserError{
 			Message:    "Invalid vault key format",
-			Suggestion: "Use format: 'path' or 'path#field'",
+			Suggestion: "Use format: 'path', 'path#field' or 'path@version#field'",
 		}
 	}
 
@@ -391,16 +1037,24 @@ func (v *VaultProvider) parseReference(key string) (string, string, error) {
 		field = parts[1]
 	}
 
-	// TODO: Handle versioning (@v2 syntax) in future version
+	path, version := splitVersion(path)
 
 	if path == "" {
-		return "", "", dserrors.UserError{
+		return "", "", "", dserrors.UserError{
 			Message:    "Empty vault path",
 			Suggestion: "Provide a vault path like 'secret/data/myapp'",
 		}
 	}
 
-	return path, field, nil
+	return path, field, version, nil
+}
+
+// kvV1VersionError explains that a version was pinned on an unversioned path
+func kvV1VersionError(path string) error {
+	return dserrors.UserError{
+		Message:    fmt.Sprintf("Vault path %s does not support versions", path),
+		Suggestion: "Only KV v2 mounts keep versions. Remove the version pin or move the secret to a KV v2 mount",
+	}
 }
 
 // getVaultErrorSuggestion provides helpful suggestions based on Vault errors
@@ -3,12 +3,18 @@ package vault
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	dserrors "github.com/systmms/dsops/internal/errors"
 	"github.com/systmms/dsops/internal/logging"
 	"github.com/systmms/dsops/pkg/provider"
 )
@@ -16,6 +22,12 @@ import (
 // MockVaultClient implements VaultClient for testing
 type MockVaultClient struct {
 	ReadFunc         func(ctx context.Context, path string) (*VaultSecret, error)
+	WriteFunc        func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error)
+	DeleteFunc       func(ctx context.Context, path string) error
+	ListFunc         func(ctx context.Context, path string) ([]string, error)
+	MountInfoFunc    func(ctx context.Context, path string) (*MountInfo, error)
+	RenewLeaseFunc   func(ctx context.Context, leaseID string, increment int) (*VaultSecret, error)
+	RevokeLeaseFunc  func(ctx context.Context, leaseID string) error
 	AuthenticateFunc func(ctx context.Context) error
 	CloseFunc        func() error
 }
@@ -27,6 +39,48 @@ func (m *MockVaultClient) Read(ctx context.Context, path string) (*VaultSecret,
 	return nil, nil
 }
 
+func (m *MockVaultClient) Write(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+	if m.WriteFunc != nil {
+		return m.WriteFunc(ctx, path, data)
+	}
+	return nil, nil
+}
+
+func (m *MockVaultClient) Delete(ctx context.Context, path string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, path)
+	}
+	return nil
+}
+
+func (m *MockVaultClient) List(ctx context.Context, path string) ([]string, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, path)
+	}
+	return nil, nil
+}
+
+func (m *MockVaultClient) MountInfo(ctx context.Context, path string) (*MountInfo, error) {
+	if m.MountInfoFunc != nil {
+		return m.MountInfoFunc(ctx, path)
+	}
+	return nil, nil
+}
+
+func (m *MockVaultClient) RenewLease(ctx context.Context, leaseID string, increment int) (*VaultSecret, error) {
+	if m.RenewLeaseFunc != nil {
+		return m.RenewLeaseFunc(ctx, leaseID, increment)
+	}
+	return &VaultSecret{LeaseID: leaseID, LeaseDuration: increment, Renewable: true}, nil
+}
+
+func (m *MockVaultClient) RevokeLease(ctx context.Context, leaseID string) error {
+	if m.RevokeLeaseFunc != nil {
+		return m.RevokeLeaseFunc(ctx, leaseID)
+	}
+	return nil
+}
+
 func (m *MockVaultClient) Authenticate(ctx context.Context) error {
 	if m.AuthenticateFunc != nil {
 		return m.AuthenticateFunc(ctx)
@@ -41,6 +95,12 @@ func (m *MockVaultClient) Close() error {
 	return nil
 }
 
+// kvV2Read returns a read of a KV v2 data path as Vault sends it, with the
+// secret nested under data.data
+func kvV2Read(data map[string]interface{}, metadata map[string]interface{}) *VaultSecret {
+	return &VaultSecret{Data: map[string]interface{}{"data": data, "metadata": metadata}}
+}
+
 func TestVaultProvider_Resolve_Success(t *testing.T) {
 	t.Parallel()
 
@@ -49,12 +109,10 @@ func TestVaultProvider_Resolve_Success(t *testing.T) {
 			return nil
 		},
 		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
-			return &VaultSecret{
-				Data: map[string]interface{}{
-					"password": "secret123",
-					"username": "admin",
-				},
-			}, nil
+			return kvV2Read(map[string]interface{}{
+				"password": "secret123",
+				"username": "admin",
+			}, nil), nil
 		},
 	}
 
@@ -80,12 +138,10 @@ func TestVaultProvider_Resolve_EntireSecret(t *testing.T) {
 	mockClient := &MockVaultClient{
 		AuthenticateFunc: func(ctx context.Context) error { return nil },
 		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
-			return &VaultSecret{
-				Data: map[string]interface{}{
-					"password": "secret123",
-					"username": "admin",
-				},
-			}, nil
+			return kvV2Read(map[string]interface{}{
+				"password": "secret123",
+				"username": "admin",
+			}, nil), nil
 		},
 	}
 
@@ -116,11 +172,9 @@ func TestVaultProvider_Resolve_FieldNotFound(t *testing.T) {
 	mockClient := &MockVaultClient{
 		AuthenticateFunc: func(ctx context.Context) error { return nil },
 		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
-			return &VaultSecret{
-				Data: map[string]interface{}{
-					"password": "secret123",
-				},
-			}, nil
+			return kvV2Read(map[string]interface{}{
+				"password": "secret123",
+			}, nil), nil
 		},
 	}
 
@@ -162,6 +216,11 @@ func TestVaultProvider_Resolve_SecretNotFound(t *testing.T) {
 	_, err := p.Resolve(ctx, ref)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Secret not found")
+
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "secret/data/nonexistent", notFound.Key)
+	assert.True(t, provider.IsNotFound(err))
 }
 
 func TestVaultProvider_Resolve_TypeConversions(t *testing.T) {
@@ -212,7 +271,7 @@ func TestVaultProvider_Resolve_TypeConversions(t *testing.T) {
 			mockClient := &MockVaultClient{
 				AuthenticateFunc: func(ctx context.Context) error { return nil },
 				ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
-					return &VaultSecret{Data: tc.data}, nil
+					return kvV2Read(tc.data, nil), nil
 				},
 			}
 
@@ -236,6 +295,21 @@ func TestVaultProvider_Resolve_TypeConversions(t *testing.T) {
 func TestVaultProvider_Describe(t *testing.T) {
 	t.Parallel()
 
+	mockClient := &MockVaultClient{
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			assert.Equal(t, "secret/metadata/myapp", path)
+			return &VaultSecret{Data: map[string]interface{}{
+				"current_version": float64(3),
+				"created_time":    "2026-01-01T00:00:00Z",
+				"custom_metadata": map[string]interface{}{"team": "payments"},
+				"versions": map[string]interface{}{
+					"2": map[string]interface{}{"created_time": "2026-02-01T00:00:00Z", "deletion_time": "2026-03-01T00:00:00Z", "destroyed": false},
+					"3": map[string]interface{}{"created_time": "2026-03-01T00:00:00Z", "deletion_time": "", "destroyed": false},
+				},
+			}}, nil
+		},
+	}
+
 	p := &VaultProvider{
 		name: "test-vault",
 		config: Config{
@@ -243,6 +317,8 @@ func TestVaultProvider_Describe(t *testing.T) {
 			Namespace:  "test-ns",
 			AuthMethod: "token",
 		},
+		client: mockClient,
+		logger: logging.New(false, false),
 	}
 
 	ctx := context.Background()
@@ -251,12 +327,27 @@ func TestVaultProvider_Describe(t *testing.T) {
 	metadata, err := p.Describe(ctx, ref)
 	require.NoError(t, err)
 
+	assert.True(t, metadata.Exists)
+	assert.Equal(t, "3", metadata.Version)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), metadata.UpdatedAt)
+	assert.False(t, metadata.Deprecated)
+	assert.Equal(t, "payments", metadata.Tags["team"])
+	assert.Equal(t, "2026-01-01T00:00:00Z", metadata.Tags["created_time"])
 	assert.Equal(t, "vault-secret", metadata.Type)
 	assert.Equal(t, "secret/data/myapp", metadata.Tags["path"])
 	assert.Equal(t, "password", metadata.Tags["field"])
 	assert.Equal(t, "http://localhost:8200", metadata.Tags["address"])
 	assert.Equal(t, "test-ns", metadata.Tags["namespace"])
 	assert.Equal(t, "token", metadata.Tags["auth_method"])
+
+	pinned, err := p.Describe(ctx, provider.Reference{Key: "secret/data/myapp@2#password"})
+	require.NoError(t, err)
+	assert.True(t, pinned.Exists)
+	assert.True(t, pinned.Deprecated, "version 2 was soft-deleted")
+
+	missing, err := p.Describe(ctx, provider.Reference{Key: "secret/data/myapp#password", Version: "9"})
+	require.NoError(t, err)
+	assert.False(t, missing.Exists)
 }
 
 func TestVaultProvider_ParseReference(t *testing.T) {
@@ -265,11 +356,12 @@ func TestVaultProvider_ParseReference(t *testing.T) {
 	p := &VaultProvider{}
 
 	testCases := []struct {
-		name          string
-		key           string
-		expectedPath  string
-		expectedField string
-		expectError   bool
+		name            string
+		key             string
+		expectedPath    string
+		expectedField   string
+		expectedVersion string
+		expectError     bool
 	}{
 		{
 			name:          "path only",
@@ -283,6 +375,24 @@ func TestVaultProvider_ParseReference(t *testing.T) {
 			expectedPath:  "secret/data/myapp",
 			expectedField: "password",
 		},
+		{
+			name:            "path with version and field",
+			key:             "secret/data/myapp@v2#password",
+			expectedPath:    "secret/data/myapp",
+			expectedField:   "password",
+			expectedVersion: "v2",
+		},
+		{
+			name:            "path with latest",
+			key:             "secret/data/myapp@latest",
+			expectedPath:    "secret/data/myapp",
+			expectedVersion: "latest",
+		},
+		{
+			name:         "at sign inside path",
+			key:          "secret/data/user@example.com",
+			expectedPath: "secret/data/user@example.com",
+		},
 		{
 			name:        "empty key",
 			key:         "",
@@ -299,18 +409,617 @@ func TestVaultProvider_ParseReference(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			path, field, err := p.parseReference(tc.key)
+			path, field, version, err := p.parseReference(tc.key)
 			if tc.expectError {
 				assert.Error(t, err)
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tc.expectedPath, path)
 				assert.Equal(t, tc.expectedField, field)
+				assert.Equal(t, tc.expectedVersion, version)
 			}
 		})
 	}
 }
 
+func TestVaultProvider_PutSecret_Field(t *testing.T) {
+	t.Parallel()
+
+	writes := map[string]map[string]interface{}{}
+	mockClient := &MockVaultClient{
+		AuthenticateFunc: func(ctx context.Context) error { return nil },
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			return kvV2Read(map[string]interface{}{"username": "admin", "password": "old"}, nil), nil
+		},
+		WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+			writes[path] = data
+			return map[string]interface{}{"version": 4}, nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+
+	version, err := p.PutSecret(context.Background(), provider.Reference{Key: "secret/data/myapp#password"}, []byte("new"), provider.WriteOptions{
+		Description: "App credentials",
+		Tags:        map[string]string{"team": "payments"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "4", version)
+
+	assert.Equal(t, map[string]interface{}{
+		"data": map[string]interface{}{"username": "admin", "password": "new"},
+	}, writes["secret/data/myapp"])
+	assert.Equal(t, map[string]interface{}{
+		"custom_metadata": map[string]interface{}{"team": "payments", "description": "App credentials"},
+	}, writes["secret/metadata/myapp"])
+}
+
+func TestVaultProvider_PutSecret_RequiresJSONObject(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: &MockVaultClient{AuthenticateFunc: func(ctx context.Context) error { return nil }},
+		logger: logging.New(false, false),
+	}
+
+	_, err := p.PutSecret(context.Background(), provider.Reference{Key: "secret/data/myapp"}, []byte("plain"), provider.WriteOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be a JSON object")
+}
+
+func TestVaultProvider_DeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	var deleted []string
+	var written map[string]interface{}
+	mockClient := &MockVaultClient{
+		AuthenticateFunc: func(ctx context.Context) error { return nil },
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			if path == "secret/data/missing" {
+				return nil, nil
+			}
+			return kvV2Read(map[string]interface{}{"token": "abc", "legacy": "x"}, nil), nil
+		},
+		WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+			written = data
+			return nil, nil
+		},
+		DeleteFunc: func(ctx context.Context, path string) error {
+			deleted = append(deleted, path)
+			return nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "secret/data/myapp#legacy"}, provider.DeleteOptions{}))
+	assert.Equal(t, map[string]interface{}{"data": map[string]interface{}{"token": "abc"}}, written)
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "secret/data/myapp"}, provider.DeleteOptions{}))
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "secret/data/myapp"}, provider.DeleteOptions{Force: true}))
+	assert.Equal(t, []string{"secret/data/myapp", "secret/metadata/myapp"}, deleted)
+
+	err := p.DeleteSecret(ctx, provider.Reference{Key: "secret/data/missing"}, provider.DeleteOptions{})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	err = p.DeleteSecret(ctx, provider.Reference{Key: "secret/data/myapp#nope"}, provider.DeleteOptions{})
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestVaultProvider_ListSecrets(t *testing.T) {
+	t.Parallel()
+
+	var listed []string
+	mockClient := &MockVaultClient{
+		AuthenticateFunc: func(ctx context.Context) error { return nil },
+		ListFunc: func(ctx context.Context, path string) ([]string, error) {
+			listed = append(listed, path)
+			return []string{"db", "api", "nested/"}, nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	result, err := p.ListSecrets(ctx, provider.ListOptions{Prefix: "secret/data/myapp/", PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 2)
+	assert.Equal(t, "secret/data/myapp/api", result.Secrets[0].Key)
+	assert.Equal(t, "secret/data/myapp/db", result.Secrets[1].Key)
+	assert.NotEmpty(t, result.NextPageToken)
+
+	result, err = p.ListSecrets(ctx, provider.ListOptions{Prefix: "secret/data/myapp", PageToken: result.NextPageToken})
+	require.NoError(t, err)
+	require.Len(t, result.Secrets, 1)
+	assert.Equal(t, "secret/data/myapp/nested/", result.Secrets[0].Key)
+
+	_, err = p.ListSecrets(ctx, provider.ListOptions{Prefix: "kv/app"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"secret/metadata/myapp/", "secret/metadata/myapp/", "kv/app"}, listed)
+
+	_, err = p.ListSecrets(ctx, provider.ListOptions{})
+	assert.ErrorContains(t, err, "needs a path")
+}
+
+func kvMounts() func(ctx context.Context, path string) (*MountInfo, error) {
+	return func(ctx context.Context, path string) (*MountInfo, error) {
+		switch {
+		case strings.HasPrefix(path, "secret/"):
+			return &MountInfo{Path: "secret/", Type: "kv", Options: map[string]string{"version": "2"}}, nil
+		case strings.HasPrefix(path, "kv/"):
+			return &MountInfo{Path: "kv/", Type: "kv", Options: map[string]string{"version": "1"}}, nil
+		default:
+			return nil, errors.New("permission denied")
+		}
+	}
+}
+
+func TestVaultProvider_Resolve_Versions(t *testing.T) {
+	t.Parallel()
+
+	mountLookups := 0
+	var reads []string
+	mockClient := &MockVaultClient{
+		MountInfoFunc: func(ctx context.Context, path string) (*MountInfo, error) {
+			mountLookups++
+			return kvMounts()(ctx, path)
+		},
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			reads = append(reads, path)
+			return kvV2Read(
+				map[string]interface{}{"password": "pw"},
+				map[string]interface{}{"version": float64(3), "created_time": "2026-03-01T00:00:00Z"},
+			), nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "secret/myapp@3#password"})
+	require.NoError(t, err)
+	assert.Equal(t, "pw", value.Value)
+	assert.Equal(t, "3", value.Version)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), value.UpdatedAt)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "secret/data/myapp#password", Version: "v2"})
+	require.NoError(t, err)
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "secret/data/myapp#password", Version: "latest"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"secret/data/myapp?version=3",
+		"secret/data/myapp?version=2",
+		"secret/data/myapp",
+	}, reads)
+	assert.Equal(t, 1, mountLookups, "mounts are looked up once")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "kv/app@2#password"})
+	assert.ErrorContains(t, err, "does not support versions")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "secret/myapp@3", Version: "4"})
+	assert.ErrorContains(t, err, "Conflicting Vault versions")
+
+	_, err = p.Resolve(ctx, provider.Reference{Key: "secret/myapp", Version: "abc"})
+	assert.ErrorContains(t, err, "Invalid Vault secret version")
+}
+
+func TestVaultProvider_KVPathFor(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		client: &MockVaultClient{MountInfoFunc: kvMounts()},
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		path     string
+		data     string
+		metadata string
+		version  int
+	}{
+		{"secret/myapp", "secret/data/myapp", "secret/metadata/myapp", 2},
+		{"secret/data/myapp", "secret/data/myapp", "secret/metadata/myapp", 2},
+		{"kv/myapp", "kv/myapp", "kv/myapp", 1},
+		// Unknown mounts fall back to the "/data/" convention
+		{"other/data/app", "other/data/app", "other/metadata/app", 2},
+		{"other/app", "other/app", "other/app", 1},
+	}
+
+	for _, tt := range tests {
+		kv := p.kvPathFor(ctx, tt.path)
+		assert.Equal(t, tt.data, kv.dataPath(), tt.path)
+		assert.Equal(t, tt.metadata, kv.metadataPath(), tt.path)
+		assert.Equal(t, tt.version, kv.version, tt.path)
+	}
+}
+
+func TestKVPath_Secret(t *testing.T) {
+	t.Parallel()
+
+	// A KV v1 secret whose fields happen to be named data and metadata
+	read := &VaultSecret{Data: map[string]interface{}{
+		"data":     map[string]interface{}{"token": "abc"},
+		"metadata": map[string]interface{}{"owner": "ops"},
+	}}
+	assert.Same(t, read, kvPath{mount: "kv/", rel: "myapp", version: 1}.secret(read))
+
+	secret := kvPath{mount: "secret/", rel: "myapp", version: 2}.secret(kvV2Read(
+		map[string]interface{}{"token": "abc"},
+		map[string]interface{}{"version": float64(2)},
+	))
+	assert.Equal(t, map[string]interface{}{"token": "abc"}, secret.Data)
+	assert.Equal(t, map[string]interface{}{"version": float64(2)}, secret.Metadata)
+
+	// A deleted KV v2 version has no data
+	deleted := kvPath{mount: "secret/", rel: "myapp", version: 2}.secret(kvV2Read(nil, map[string]interface{}{"version": float64(3)}))
+	assert.Nil(t, deleted.Data)
+	assert.Nil(t, kvPath{version: 2}.secret(nil))
+}
+
+func TestVaultProvider_Describe_KVv1(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			MountInfoFunc: kvMounts(),
+			ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+				if path == "kv/app" {
+					return &VaultSecret{Data: map[string]interface{}{"token": "x"}}, nil
+				}
+				return nil, nil
+			},
+		},
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	meta, err := p.Describe(ctx, provider.Reference{Key: "kv/app#token"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "1", meta.Tags["kv_version"])
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "kv/app#nope"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+
+	meta, err = p.Describe(ctx, provider.Reference{Key: "kv/missing"})
+	require.NoError(t, err)
+	assert.False(t, meta.Exists)
+}
+
+func TestVaultProvider_CreateNewVersion(t *testing.T) {
+	t.Parallel()
+
+	writes := map[string]map[string]interface{}{}
+	mockClient := &MockVaultClient{
+		MountInfoFunc: kvMounts(),
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			switch path {
+			case "secret/metadata/myapp":
+				return &VaultSecret{Data: map[string]interface{}{
+					"current_version": float64(4),
+					"custom_metadata": map[string]interface{}{"team": "payments"},
+				}}, nil
+			case "secret/data/myapp":
+				return kvV2Read(map[string]interface{}{"username": "admin", "password": "old"}, nil), nil
+			}
+			return nil, nil
+		},
+		WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+			writes[path] = data
+			if path == "secret/data/myapp" {
+				return map[string]interface{}{"version": float64(5)}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	version, err := p.CreateNewVersion(ctx, provider.Reference{Key: "secret/myapp#password"}, []byte("new"), map[string]string{"rotated_by": "dsops"})
+	require.NoError(t, err)
+	assert.Equal(t, "5", version)
+
+	assert.Equal(t, map[string]interface{}{
+		"options": map[string]interface{}{"cas": 4},
+		"data":    map[string]interface{}{"username": "admin", "password": "new"},
+	}, writes["secret/data/myapp"])
+	assert.Equal(t, map[string]interface{}{
+		"custom_metadata": map[string]interface{}{"team": "payments", "rotated_by": "dsops"},
+	}, writes["secret/metadata/myapp"])
+
+	_, err = p.CreateNewVersion(ctx, provider.Reference{Key: "kv/app#password"}, []byte("new"), nil)
+	assert.ErrorContains(t, err, "not on a KV v2 mount")
+}
+
+func TestVaultProvider_CreateNewVersion_CASConflict(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			MountInfoFunc: kvMounts(),
+			WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+				return nil, errors.New("vault returned status 400: check-and-set parameter did not match the current version")
+			},
+		},
+		logger: logging.New(false, false),
+	}
+
+	_, err := p.CreateNewVersion(context.Background(), provider.Reference{Key: "secret/myapp"}, []byte(`{"a":"b"}`), nil)
+	require.Error(t, err)
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, userErr.Suggestion, "changed during rotation")
+}
+
+func TestVaultProvider_DeprecateVersion(t *testing.T) {
+	t.Parallel()
+
+	writes := map[string]map[string]interface{}{}
+	mockClient := &MockVaultClient{
+		MountInfoFunc: kvMounts(),
+		WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+			writes[path] = data
+			return nil, nil
+		},
+	}
+	ctx := context.Background()
+
+	p := &VaultProvider{name: "test-vault", client: mockClient, logger: logging.New(false, false)}
+	require.NoError(t, p.DeprecateVersion(ctx, provider.Reference{Key: "secret/myapp#password"}, "v2"))
+	assert.Equal(t, map[string]interface{}{"versions": []int{2}}, writes["secret/delete/myapp"])
+
+	p = &VaultProvider{name: "test-vault", config: Config{VersionDeprecation: "destroy"}, client: mockClient, logger: logging.New(false, false)}
+	require.NoError(t, p.DeprecateVersion(ctx, provider.Reference{Key: "secret/data/myapp"}, "3"))
+	assert.Equal(t, map[string]interface{}{"versions": []int{3}}, writes["secret/destroy/myapp"])
+
+	// DestroyVersion ignores version_deprecation so nothing can be undeleted
+	p = &VaultProvider{name: "test-vault", client: mockClient, logger: logging.New(false, false)}
+	var destroyer provider.VersionDestroyer = p
+	require.NoError(t, destroyer.DestroyVersion(ctx, provider.Reference{Key: "secret/myapp"}, "4"))
+	assert.Equal(t, map[string]interface{}{"versions": []int{4}}, writes["secret/destroy/myapp"])
+
+	assert.ErrorContains(t, p.DeprecateVersion(ctx, provider.Reference{Key: "secret/myapp"}, "latest"), "version number is required")
+	assert.ErrorContains(t, p.DeprecateVersion(ctx, provider.Reference{Key: "kv/app"}, "1"), "not on a KV v2 mount")
+
+	var rotator provider.Rotator = p
+	meta, err := rotator.GetRotationMetadata(ctx, provider.Reference{Key: "kv/app"})
+	require.NoError(t, err)
+	assert.False(t, meta.SupportsRotation)
+	assert.Equal(t, "1", meta.Constraints["kv_version"])
+}
+
+func TestVaultProvider_ListVersions(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			MountInfoFunc: kvMounts(),
+			ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+				if path != "secret/metadata/myapp" {
+					return nil, nil
+				}
+				return &VaultSecret{Data: map[string]interface{}{
+					"current_version": float64(11),
+					"versions": map[string]interface{}{
+						"1":  map[string]interface{}{"deletion_time": "", "destroyed": true},
+						"2":  map[string]interface{}{"deletion_time": "2026-03-01T00:00:00Z", "destroyed": false},
+						"9":  map[string]interface{}{"deletion_time": "", "destroyed": false},
+						"11": map[string]interface{}{"deletion_time": "", "destroyed": false},
+					},
+				}}, nil
+			},
+		},
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	var lister provider.VersionLister = p
+	versions, err := lister.ListVersions(ctx, provider.Reference{Key: "secret/myapp#password"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9", "11"}, versions)
+
+	_, err = p.ListVersions(ctx, provider.Reference{Key: "secret/missing"})
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = p.ListVersions(ctx, provider.Reference{Key: "kv/app"})
+	assert.ErrorContains(t, err, "not on a KV v2 mount")
+}
+
+func TestVaultProvider_GetRotationMetadata(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			MountInfoFunc: kvMounts(),
+			ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+				return &VaultSecret{Data: map[string]interface{}{
+					"current_version": float64(2),
+					"max_versions":    float64(10),
+					"updated_time":    "2026-04-01T12:00:00Z",
+				}}, nil
+			},
+		},
+		logger: logging.New(false, false),
+	}
+
+	meta, err := p.GetRotationMetadata(context.Background(), provider.Reference{Key: "secret/myapp"})
+	require.NoError(t, err)
+	assert.True(t, meta.SupportsRotation)
+	assert.True(t, meta.SupportsVersioning)
+	require.NotNil(t, meta.LastRotated)
+	assert.Equal(t, time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC), *meta.LastRotated)
+	assert.Equal(t, "10", meta.Constraints["max_versions"])
+	assert.Equal(t, "secret/", meta.Constraints["mount"])
+	assert.Equal(t, "delete", meta.Constraints["version_deprecation"])
+}
+
+func TestVaultProvider_DeleteSecret_Version(t *testing.T) {
+	t.Parallel()
+
+	writes := map[string]map[string]interface{}{}
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			MountInfoFunc: kvMounts(),
+			WriteFunc: func(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+				writes[path] = data
+				return nil, nil
+			},
+		},
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "secret/myapp@2"}, provider.DeleteOptions{}))
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "secret/myapp", Version: "3"}, provider.DeleteOptions{Force: true}))
+	assert.Equal(t, map[string]interface{}{"versions": []int{2}}, writes["secret/delete/myapp"])
+	assert.Equal(t, map[string]interface{}{"versions": []int{3}}, writes["secret/destroy/myapp"])
+
+	err := p.DeleteSecret(ctx, provider.Reference{Key: "secret/myapp@2#password"}, provider.DeleteOptions{})
+	assert.ErrorContains(t, err, "pinned version")
+}
+
+func TestVaultProvider_Resolve_DynamicSharesLease(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	reads := 0
+	revoked := []string{}
+	mockClient := &MockVaultClient{
+		MountInfoFunc: func(ctx context.Context, path string) (*MountInfo, error) {
+			return &MountInfo{Path: "database/", Type: "database"}, nil
+		},
+		ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+			mu.Lock()
+			reads++
+			n := reads
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			return &VaultSecret{
+				Data:          map[string]interface{}{"username": fmt.Sprintf("v-app-%d", n), "password": "pw"},
+				LeaseID:       fmt.Sprintf("database/creds/app/lease%d", n),
+				LeaseDuration: 3600,
+				Renewable:     true,
+			}, nil
+		},
+		RenewLeaseFunc: func(ctx context.Context, leaseID string, increment int) (*VaultSecret, error) {
+			return &VaultSecret{LeaseID: leaseID, LeaseDuration: 1800, Renewable: true}, nil
+		},
+		RevokeLeaseFunc: func(ctx context.Context, leaseID string) error {
+			revoked = append(revoked, leaseID)
+			return nil
+		},
+	}
+
+	p := &VaultProvider{
+		name:   "test-vault",
+		config: Config{Address: "http://localhost:8200"},
+		client: mockClient,
+		logger: logging.New(false, false),
+	}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	values := make([]string, 2)
+	for i, field := range []string{"username", "password"} {
+		wg.Add(1)
+		go func(i int, field string) {
+			defer wg.Done()
+			value, err := p.Resolve(ctx, provider.Reference{Key: "database/creds/app#" + field})
+			assert.NoError(t, err)
+			values[i] = value.Value
+		}(i, field)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, reads, "fields of one dynamic secret share a single read")
+	assert.Equal(t, []string{"v-app-1", "pw"}, values)
+
+	leases := p.Leases()
+	require.Len(t, leases, 1)
+	assert.Equal(t, "database/creds/app/lease1", leases[0].ID)
+	assert.Equal(t, "database/creds/app", leases[0].Path)
+	assert.Equal(t, time.Hour, leases[0].Duration)
+	assert.True(t, leases[0].Renewable)
+
+	renewed, err := p.RenewLease(ctx, leases[0].ID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, renewed.Duration)
+	assert.Equal(t, 30*time.Minute, p.Leases()[0].Duration)
+
+	_, err = p.RenewLease(ctx, "unknown", time.Hour)
+	var notFound *provider.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	require.NoError(t, p.RevokeLease(ctx, leases[0].ID))
+	assert.Equal(t, []string{"database/creds/app/lease1"}, revoked)
+	assert.Empty(t, p.Leases())
+
+	value, err := p.Resolve(ctx, provider.Reference{Key: "database/creds/app#username"})
+	require.NoError(t, err)
+	assert.Equal(t, "v-app-2", value.Value, "a revoked lease is not reused")
+}
+
+func TestVaultProvider_Describe_Dynamic(t *testing.T) {
+	t.Parallel()
+
+	p := &VaultProvider{
+		name: "test-vault",
+		client: &MockVaultClient{
+			ReadFunc: func(ctx context.Context, path string) (*VaultSecret, error) {
+				t.Errorf("describe must not read dynamic path %s", path)
+				return nil, nil
+			},
+		},
+		logger: logging.New(false, false),
+	}
+
+	meta, err := p.Describe(context.Background(), provider.Reference{Key: "aws/creds/deploy#access_key"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, "true", meta.Tags["dynamic"])
+	assert.Empty(t, p.Leases())
+}
+
 func TestVaultProvider_Validate_TokenAuth(t *testing.T) {
 	t.Parallel()
 
@@ -591,6 +1300,158 @@ func TestHTTPVaultClient_Read_Success(t *testing.T) {
 	assert.NotNil(t, secret)
 }
 
+func TestHTTPVaultClient_List(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "LIST", r.Method)
+		assert.Equal(t, "test-token", r.Header.Get("X-Vault-Token"))
+		if r.URL.Path != "/v1/secret/metadata/myapp" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"keys": []string{"db", "nested/"},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &HTTPVaultClient{
+		config: Config{Address: server.URL},
+		token:  "test-token",
+	}
+
+	ctx := context.Background()
+	keys, err := client.List(ctx, "secret/metadata/myapp/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "nested/"}, keys)
+
+	keys, err = client.List(ctx, "secret/metadata/missing")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestHTTPVaultClient_Read_KVv1(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{"token": "abc", "data": "not nested"},
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &HTTPVaultClient{
+		config: Config{Address: server.URL},
+		token:  "test-token",
+	}
+
+	secret, err := client.Read(context.Background(), "kv/myapp")
+	require.NoError(t, err)
+	require.NotNil(t, secret)
+	assert.Equal(t, map[string]interface{}{"token": "abc", "data": "not nested"}, secret.Data)
+	assert.Nil(t, secret.Metadata)
+}
+
+func TestHTTPVaultClient_MountInfo(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		if r.URL.Path != "/v1/sys/internal/ui/mounts/secret/myapp" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"path":    "secret/",
+				"type":    "kv",
+				"options": map[string]interface{}{"version": "2"},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &HTTPVaultClient{
+		config: Config{Address: server.URL},
+		token:  "test-token",
+	}
+	ctx := context.Background()
+
+	info, err := client.MountInfo(ctx, "secret/myapp")
+	require.NoError(t, err)
+	assert.Equal(t, "secret/", info.Path)
+	assert.Equal(t, 2, info.kvVersion())
+
+	_, err = client.MountInfo(ctx, "other/app")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestHTTPVaultClient_Leases(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+fmt.Sprint(body["lease_id"]))
+
+		switch r.URL.Path {
+		case "/v1/database/creds/app":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       "database/creds/app/abc",
+				"lease_duration": 3600,
+				"renewable":      true,
+				"data":           map[string]interface{}{"username": "v-app", "password": "pw"},
+			})
+		case "/v1/sys/leases/renew":
+			assert.Equal(t, float64(600), body["increment"])
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       "database/creds/app/abc",
+				"lease_duration": 600,
+				"renewable":      true,
+			})
+		case "/v1/sys/leases/revoke":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &HTTPVaultClient{
+		config: Config{Address: server.URL},
+		token:  "test-token",
+	}
+	ctx := context.Background()
+
+	secret, err := client.Read(ctx, "database/creds/app")
+	require.NoError(t, err)
+	assert.Equal(t, "database/creds/app/abc", secret.LeaseID)
+	assert.Equal(t, 3600, secret.LeaseDuration)
+	assert.True(t, secret.Renewable)
+	assert.Equal(t, "v-app", secret.Data["username"])
+
+	renewed, err := client.RenewLease(ctx, "database/creds/app/abc", 600)
+	require.NoError(t, err)
+	assert.Equal(t, 600, renewed.LeaseDuration)
+
+	require.NoError(t, client.RevokeLease(ctx, "database/creds/app/abc"))
+
+	assert.Equal(t, []string{
+		"GET /v1/database/creds/app <nil>",
+		"PUT /v1/sys/leases/renew database/creds/app/abc",
+		"PUT /v1/sys/leases/revoke database/creds/app/abc",
+	}, requests)
+}
+
 func TestHTTPVaultClient_Read_NotFound(t *testing.T) {
 	t.Parallel()
 
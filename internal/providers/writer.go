@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Helpers shared by providers implementing provider.Writer
+
+// splitJSONPath splits a dotted JSON path (".a.b" or "a.b") into its parts
+func splitJSONPath(path string) ([]string, error) {
+	var parts []string
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty JSON path")
+	}
+	return parts, nil
+}
+
+// parseJSONObject parses doc as a JSON object. An empty doc is an empty object.
+func parseJSONObject(doc string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if strings.TrimSpace(doc) == "" {
+		return data, nil
+	}
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		return nil, fmt.Errorf("existing secret is not a JSON object: %w", err)
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return data, nil
+}
+
+// setJSONField sets the string value at a dotted JSON path within doc,
+// creating intermediate objects as needed
+func setJSONField(doc, path, value string) (string, error) {
+	parts, err := splitJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := parseJSONObject(doc)
+	if err != nil {
+		return "", err
+	}
+
+	current := data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			if _, exists := current[part]; exists {
+				return "", fmt.Errorf("cannot set field inside non-object at '%s'", part)
+			}
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal secret: %w", err)
+	}
+	return string(out), nil
+}
+
+// deleteJSONField removes the field at a dotted JSON path within doc.
+// Reports whether the field existed.
+func deleteJSONField(doc, path string) (string, bool, error) {
+	parts, err := splitJSONPath(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	data, err := parseJSONObject(doc)
+	if err != nil {
+		return "", false, err
+	}
+
+	current := data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return doc, false, nil
+		}
+		current = next
+	}
+
+	last := parts[len(parts)-1]
+	if _, exists := current[last]; !exists {
+		return doc, false, nil
+	}
+	delete(current, last)
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to marshal secret: %w", err)
+	}
+	return string(out), true, nil
+}
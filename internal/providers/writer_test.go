@@ -0,0 +1,224 @@
+package providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+	"github.com/systmms/dsops/tests/testutil"
+)
+
+func TestAWSSecretsManagerPutSecret(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSecretsManagerClient()
+	p, err := providers.NewAWSSecretsManagerProvider("aws", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSecretsManagerClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Create
+	version, err := p.PutSecret(ctx, provider.Reference{Key: "app/api-key"}, []byte("first"), provider.WriteOptions{
+		Description: "Partner API key",
+		Tags:        map[string]string{"team": "payments"},
+		Metadata:    map[string]string{"kms_key_id": "alias/secrets"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, version)
+
+	created := client.Secrets["app/api-key"]
+	require.NotNil(t, created)
+	assert.Equal(t, "first", aws.ToString(created.SecretString))
+	assert.Equal(t, "Partner API key", aws.ToString(created.Description))
+	assert.Equal(t, "alias/secrets", aws.ToString(created.KmsKeyId))
+	assert.Equal(t, "payments", created.Tags["team"])
+
+	// Update
+	_, err = p.PutSecret(ctx, provider.Reference{Key: "app/api-key"}, []byte("second"), provider.WriteOptions{
+		Tags: map[string]string{"owner": "ops"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "second", aws.ToString(client.Secrets["app/api-key"].SecretString))
+	assert.Equal(t, "ops", client.Secrets["app/api-key"].Tags["owner"])
+
+	// JSON field update keeps the other fields
+	client.AddSecretString("app/db", `{"user":"admin","password":"old"}`)
+	_, err = p.PutSecret(ctx, provider.Reference{Key: "app/db#.password"}, []byte("new"), provider.WriteOptions{})
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(aws.ToString(client.Secrets["app/db"].SecretString)), &doc))
+	assert.Equal(t, map[string]interface{}{"user": "admin", "password": "new"}, doc)
+}
+
+func TestAWSSecretsManagerDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSecretsManagerClient()
+	client.AddSecretString("app/old", "value")
+	client.AddSecretString("app/leaked", "value")
+	client.AddSecretString("app/db", `{"user":"admin","legacy":"x"}`)
+
+	p, err := providers.NewAWSSecretsManagerProvider("aws", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSecretsManagerClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "app/old"}, provider.DeleteOptions{}))
+	assert.False(t, client.DeletedSecrets["app/old"], "deletion should keep the recovery window")
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "app/leaked"}, provider.DeleteOptions{Force: true}))
+	assert.True(t, client.DeletedSecrets["app/leaked"], "--force should skip recovery")
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "app/db#.legacy"}, provider.DeleteOptions{}))
+	assert.JSONEq(t, `{"user":"admin"}`, aws.ToString(client.Secrets["app/db"].SecretString))
+
+	err = p.DeleteSecret(ctx, provider.Reference{Key: "app/db#.missing"}, provider.DeleteOptions{})
+	var notFound *provider.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestAWSSSMPutAndDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeSSMClient()
+	p, err := providers.NewAWSSSMProvider("ssm", map[string]interface{}{
+		"region":           "us-east-1",
+		"parameter_prefix": "/myapp/",
+	}, providers.WithSSMClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	version, err := p.PutSecret(ctx, provider.Reference{Key: "db-password"}, []byte("s3cr3t"), provider.WriteOptions{
+		Description: "Database password",
+		Tags:        map[string]string{"env": "prod"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", version)
+
+	param := client.Parameters["/myapp/db-password"]
+	require.NotNil(t, param)
+	assert.Equal(t, "s3cr3t", aws.ToString(param.Value))
+	assert.Equal(t, ssmtypes.ParameterTypeSecureString, param.Type)
+	assert.Equal(t, "Database password", aws.ToString(param.Description))
+	assert.Equal(t, "prod", param.Tags["env"])
+
+	version, err = p.PutSecret(ctx, provider.Reference{Key: "db-password"}, []byte("rotated"), provider.WriteOptions{
+		Metadata: map[string]string{"type": "String"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2", version)
+	assert.Equal(t, ssmtypes.ParameterTypeString, client.Parameters["/myapp/db-password"].Type)
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "db-password"}, provider.DeleteOptions{}))
+	assert.NotContains(t, client.Parameters, "/myapp/db-password")
+
+	err = p.DeleteSecret(ctx, provider.Reference{Key: "db-password"}, provider.DeleteOptions{})
+	var notFound *provider.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestAzureKeyVaultPutAndDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeAzureKeyVaultClient()
+	p, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(client))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	version, err := p.PutSecret(ctx, provider.Reference{Key: "api-key"}, []byte("value"), provider.WriteOptions{
+		Description: "API key",
+		Tags:        map[string]string{"team": "payments"},
+		Metadata:    map[string]string{"content_type": "text/plain"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", version)
+
+	secret := client.Secrets["api-key"]
+	require.NotNil(t, secret)
+	assert.Equal(t, "value", *secret.Value)
+	assert.Equal(t, "text/plain", *secret.ContentType)
+	assert.Equal(t, "API key", *secret.Tags["description"])
+	assert.Equal(t, "payments", *secret.Tags["team"])
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "api-key"}, provider.DeleteOptions{}))
+	assert.False(t, client.DeletedSecrets["api-key"], "secret should be soft-deleted")
+
+	_, err = p.PutSecret(ctx, provider.Reference{Key: "leaked"}, []byte("value"), provider.WriteOptions{})
+	require.NoError(t, err)
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "leaked"}, provider.DeleteOptions{Force: true}))
+	assert.True(t, client.DeletedSecrets["leaked"], "--force should purge the secret")
+}
+
+func TestKeychainPutAndDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	client := fakes.NewFakeKeychainClient()
+	p := providers.NewKeychainProviderWithClient("keychain", map[string]interface{}{
+		"service_prefix": "com.example",
+	}, client)
+
+	ctx := context.Background()
+
+	_, err := p.PutSecret(ctx, provider.Reference{Key: "myapp/api-key"}, []byte("secret123"), provider.WriteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret123"), client.Secrets["com.example.myapp"]["api-key"])
+
+	result, err := p.Resolve(ctx, provider.Reference{Key: "myapp/api-key"})
+	require.NoError(t, err)
+	assert.Equal(t, "secret123", result.Value)
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "myapp/api-key"}, provider.DeleteOptions{}))
+
+	err = p.DeleteSecret(ctx, provider.Reference{Key: "myapp/api-key"}, provider.DeleteOptions{})
+	var notFound provider.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+
+	client.SetErr = fakes.ErrFakeKeychainAccessDenied
+	_, err = p.PutSecret(ctx, provider.Reference{Key: "myapp/api-key"}, []byte("x"), provider.WriteOptions{})
+	var kcErr *providers.KeychainError
+	require.True(t, errors.As(err, &kcErr))
+	assert.Equal(t, "set", kcErr.Op)
+}
+
+func TestPassPutAndDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	mockExec := testutil.NewMockCommandExecutor()
+	mockExec.AddErrorResponse("pass rm --force missing", "Error: missing is not in the password store.\n", 1)
+	p := providers.NewPassProviderWithExecutor(providers.PassConfig{}, mockExec)
+
+	ctx := context.Background()
+
+	_, err := p.PutSecret(ctx, provider.Reference{Key: "work/api"}, []byte("s3cr3t"), provider.WriteOptions{
+		Description: "Work API",
+		Tags:        map[string]string{"url": "https://example.com", "user": "me"},
+	})
+	require.NoError(t, err)
+
+	calls := mockExec.GetCalls("pass")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []string{"insert", "--multiline", "--force", "work/api"}, calls[0].Args)
+	assert.Equal(t, "s3cr3t\ndescription: Work API\nurl: https://example.com\nuser: me\n", string(calls[0].Stdin))
+
+	require.NoError(t, p.DeleteSecret(ctx, provider.Reference{Key: "work/api"}, provider.DeleteOptions{}))
+
+	err = p.DeleteSecret(ctx, provider.Reference{Key: "missing"}, provider.DeleteOptions{})
+	var notFound *provider.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
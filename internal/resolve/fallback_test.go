@@ -0,0 +1,264 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+	"github.com/systmms/dsops/tests/fakes"
+)
+
+// newFallbackResolver registers vault, aws and cache stores holding the given
+// keys. vault fails to answer for db/password as if it were down, denies
+// access to admin/token and wraps the not-found answer for wrapped/key.
+func newFallbackResolver(t *testing.T, vault, aws, cache map[string]string) (*Resolver, map[string]*fakes.FakeProvider) {
+	t.Helper()
+
+	cfg := &config.Config{
+		Logger: logging.New(false, true),
+		Definition: &config.Definition{
+			SecretStores: map[string]config.SecretStoreConfig{
+				"vault": {Type: "vault"},
+				"aws":   {Type: "aws.secretsmanager"},
+				"cache": {Type: "file.json"},
+			},
+		},
+	}
+	resolver := New(cfg)
+
+	stores := map[string]*fakes.FakeProvider{}
+	for name, values := range map[string]map[string]string{"vault": vault, "aws": aws, "cache": cache} {
+		fake := fakes.NewFakeProvider(name)
+		for key, value := range values {
+			fake.WithSecret(key, provider.SecretValue{Value: value, Version: name + "-v1"})
+		}
+		stores[name] = fake
+		resolver.RegisterProvider(name, fake)
+	}
+	stores["vault"].WithError("db/password", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	stores["vault"].WithError("admin/token", provider.AuthError{Provider: "vault", Message: "permission denied"})
+	stores["vault"].WithError("wrapped/key", dserrors.UserError{
+		Message: "Secret lookup failed",
+		Err:     &provider.NotFoundError{Provider: "vault", Key: "wrapped/key"},
+	})
+
+	return resolver, stores
+}
+
+func chain(refs ...config.Reference) config.Variable {
+	return config.Variable{From: &refs[0], Fallbacks: refs[1:]}
+}
+
+func TestResolverFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		vault          map[string]string
+		variable       config.Variable
+		expectedValue  string
+		expectedSource string
+		expectedError  string
+		untouched      []string
+	}{
+		{
+			name:  "primary answers",
+			vault: map[string]string{"api/key": "from-vault"},
+			variable: chain(
+				config.Reference{Store: "store://vault/api/key"},
+				config.Reference{Store: "store://aws/api/key"},
+			),
+			expectedValue:  "from-vault",
+			expectedSource: "vault:api/key@vault-v1",
+			untouched:      []string{"aws"},
+		},
+		{
+			name: "unavailable primary falls back",
+			variable: chain(
+				config.Reference{Store: "store://vault/db/password"},
+				config.Reference{Store: "store://aws/db/password", On: config.FallbackOnUnavailable},
+			),
+			expectedValue:  "from-aws",
+			expectedSource: "aws:db/password@aws-v1",
+		},
+		{
+			name: "not found skips an unavailable-only fallback",
+			variable: chain(
+				config.Reference{Store: "store://vault/api/key"},
+				config.Reference{Store: "store://aws/api/key", On: config.FallbackOnUnavailable},
+				config.Reference{Store: "store://cache/api.key", On: config.FallbackOnNotFound},
+			),
+			expectedValue:  "from-cache",
+			expectedSource: "cache:api.key@cache-v1",
+			untouched:      []string{"aws"},
+		},
+		{
+			name: "unavailable primary does not use a not_found fallback",
+			variable: chain(
+				config.Reference{Store: "store://vault/db/password"},
+				config.Reference{Store: "store://cache/db.password", On: config.FallbackOnNotFound},
+			),
+			expectedError: "skipped (on: not_found)",
+			untouched:     []string{"cache"},
+		},
+		{
+			name: "wrapped not found uses a not_found fallback",
+			variable: chain(
+				config.Reference{Store: "store://vault/wrapped/key"},
+				config.Reference{Store: "store://aws/api/key", On: config.FallbackOnUnavailable},
+				config.Reference{Store: "store://cache/api.key", On: config.FallbackOnNotFound},
+			),
+			expectedValue:  "from-cache",
+			expectedSource: "cache:api.key@cache-v1",
+			untouched:      []string{"aws"},
+		},
+		{
+			name: "denied access is not unavailable",
+			variable: chain(
+				config.Reference{Store: "store://vault/admin/token"},
+				config.Reference{Store: "store://aws/admin/token", On: config.FallbackOnUnavailable},
+				config.Reference{Store: "store://cache/admin.token", On: config.FallbackOnNotFound},
+			),
+			expectedError: "aws:admin/token: skipped (on: unavailable); cache:admin.token: skipped (on: not_found)",
+			untouched:     []string{"aws", "cache"},
+		},
+		{
+			name: "denied access still uses an any fallback",
+			variable: chain(
+				config.Reference{Store: "store://vault/admin/token"},
+				config.Reference{Store: "store://cache/api.key"},
+			),
+			expectedValue:  "from-cache",
+			expectedSource: "cache:api.key@cache-v1",
+		},
+		{
+			name: "every source fails",
+			variable: chain(
+				config.Reference{Store: "store://vault/db/password"},
+				config.Reference{Store: "store://aws/missing"},
+				config.Reference{Store: "store://unknown/db"},
+			),
+			expectedError: "vault:db/password: dial tcp: connection refused; aws:missing: secret not found: missing in aws; unknown:db: Configuration error in field 'provider' (value: unknown): provider not found in configuration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver, stores := newFallbackResolver(t, tt.vault,
+				map[string]string{"db/password": "from-aws"},
+				map[string]string{"api.key": "from-cache", "db.password": "from-cache"},
+			)
+
+			resolved := resolver.resolveVariable(context.Background(), "VAR", tt.variable)
+			if tt.expectedError != "" {
+				require.Error(t, resolved.Error)
+				var userErr dserrors.UserError
+				require.ErrorAs(t, resolved.Error, &userErr)
+				assert.Contains(t, userErr.Details, tt.expectedError)
+			} else {
+				require.NoError(t, resolved.Error)
+				assert.Equal(t, tt.expectedValue, resolved.Value)
+				assert.Equal(t, tt.expectedSource, resolved.Source)
+			}
+
+			for _, name := range tt.untouched {
+				assert.Zero(t, stores[name].GetCallCount("Resolve"), "%s should not be called", name)
+			}
+		})
+	}
+}
+
+func TestResolverFallbackSingleSourceError(t *testing.T) {
+	t.Parallel()
+
+	resolver, _ := newFallbackResolver(t, nil, nil, nil)
+	resolved := resolver.resolveVariable(context.Background(), "VAR", config.Variable{
+		From: &config.Reference{Store: "store://vault/missing"},
+	})
+
+	// A single source keeps its own error rather than a chain summary
+	require.Error(t, resolved.Error)
+	assert.True(t, provider.IsNotFound(resolved.Error))
+	assert.Contains(t, resolved.Error.Error(), "vault provider error during resolve")
+}
+
+func TestResolverPlanFallbacks(t *testing.T) {
+	t.Parallel()
+
+	resolver, _ := newFallbackResolver(t, nil, nil, nil)
+	resolver.config.Definition.Envs = map[string]config.Environment{
+		"production": {
+			"DB_PASSWORD": chain(
+				config.Reference{Store: "store://vault/db/password"},
+				config.Reference{Store: "store://aws/db/password", On: config.FallbackOnUnavailable},
+				config.Reference{Store: "store://missing/db.password"},
+			),
+		},
+	}
+
+	plan, err := resolver.Plan(context.Background(), "production")
+	require.NoError(t, err)
+	require.Len(t, plan.Variables, 1)
+
+	planned := plan.Variables[0]
+	assert.Equal(t, "provider:vault key:db/password", planned.Source)
+	require.Len(t, planned.Fallbacks, 2)
+	assert.Equal(t, "provider:aws key:db/password", planned.Fallbacks[0].Source)
+	assert.Equal(t, config.FallbackOnUnavailable, planned.Fallbacks[0].On)
+	assert.NoError(t, planned.Fallbacks[0].Error)
+	assert.Equal(t, config.FallbackOnAny, planned.Fallbacks[1].On)
+	assert.ErrorContains(t, planned.Fallbacks[1].Error, "provider 'missing' not registered")
+	assert.Error(t, planned.Error)
+	assert.Len(t, plan.Errors, 1)
+}
+
+func TestFailureCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"not found", provider.NotFoundError{Key: "k"}, config.FallbackOnNotFound},
+		{"wrapped not found", dserrors.ProviderError("vault", "resolve", dserrors.UserError{
+			Message: "lookup failed", Err: &provider.NotFoundError{Key: "k"},
+		}), config.FallbackOnNotFound},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, config.FallbackOnUnavailable},
+		{"dns failure", fmt.Errorf("lookup: %w", &net.DNSError{Err: "no such host", Name: "vault.internal"}), config.FallbackOnUnavailable},
+		{"timeout", isTimeoutError(context.DeadlineExceeded, "vault", 5000), config.FallbackOnUnavailable},
+		{"server error", dserrors.UserError{Message: "failed", Err: statusError(503)}, config.FallbackOnUnavailable},
+		{"provider unavailable", dserrors.UserError{Message: "failed", Err: provider.UnavailableError{Provider: "azure", Err: errors.New("502")}}, config.FallbackOnUnavailable},
+		{"provider unavailable pointer", &provider.UnavailableError{Provider: "plugin"}, config.FallbackOnUnavailable},
+		{"client error", dserrors.UserError{Message: "failed", Err: statusError(400)}, ""},
+		{"forbidden", statusError(403), ""},
+		{"auth failure", provider.AuthError{Provider: "aws", Message: "AccessDenied"}, ""},
+		{"auth failure over network error", fmt.Errorf("%w: %w", provider.AuthError{Provider: "aws"}, &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), ""},
+		{"config error", dserrors.ConfigError{Field: "provider", Message: "provider not found in configuration"}, ""},
+		{"plain error", errors.New("connection refused"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, failureCondition(tt.err))
+		})
+	}
+}
+
+// statusError is an SDK response error with an HTTP status
+type statusError int
+
+func (e statusError) Error() string       { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatusCode() int { return int(e) }
@@ -2,7 +2,9 @@ package resolve
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
 
@@ -100,6 +102,7 @@ type ResolvedVariable struct {
 	Name        string
 	Value       string
 	Source      string
+	Version     string
 	Transformed bool
 	Error       error
 }
@@ -114,11 +117,19 @@ type PlanResult struct {
 type PlannedVariable struct {
 	Name      string
 	Source    string
+	Fallbacks []PlannedFallback `json:",omitempty"`
 	Transform string
 	Optional  bool
 	Error     error
 }
 
+// PlannedFallback is a source tried when the ones before it fail
+type PlannedFallback struct {
+	Source string
+	On     string
+	Error  error
+}
+
 // Plan shows what variables would be resolved without fetching actual values
 func (r *Resolver) Plan(ctx context.Context, envName string) (*PlanResult, error) {
 	env, err := r.config.GetEnvironment(envName)
@@ -142,24 +153,24 @@ func (r *Resolver) Plan(ctx context.Context, envName string) (*PlanResult, error
 		if variable.Literal != "" {
 			planned.Source = "literal"
 		} else if variable.From != nil {
-			// Check if this is a service reference
-			if variable.From.IsServiceReference() {
-				planned.Source = variable.From.Service
-				planned.Error = fmt.Errorf("service references (svc://) are for credential rotation, not secret retrieval")
+			planned.Source, planned.Error = r.planSource(variable.From)
+			if planned.Error != nil {
 				result.Errors = append(result.Errors, planned.Error)
-			} else {
-				providerName := variable.From.GetEffectiveProvider()
-				legacyRef := variable.From.ToLegacyProviderRef()
-				planned.Source = fmt.Sprintf("provider:%s key:%s", providerName, legacyRef.Key)
-
-				// Check if provider exists
-				r.mu.RLock()
-				_, exists := r.providers[providerName]
-				r.mu.RUnlock()
-				if !exists {
-					planned.Error = fmt.Errorf("provider '%s' not registered", providerName)
-					result.Errors = append(result.Errors, planned.Error)
+			}
+			for i := range variable.Fallbacks {
+				fallback := PlannedFallback{On: variable.Fallbacks[i].On}
+				if fallback.On == "" {
+					fallback.On = config.FallbackOnAny
+				}
+				fallback.Source, fallback.Error = r.planSource(&variable.Fallbacks[i])
+				if fallback.Error != nil {
+					fallback.Error = fmt.Errorf("fallback %d of '%s': %w", i+1, varName, fallback.Error)
+					result.Errors = append(result.Errors, fallback.Error)
+					if planned.Error == nil {
+						planned.Error = fallback.Error
+					}
 				}
+				planned.Fallbacks = append(planned.Fallbacks, fallback)
 			}
 		} else {
 			planned.Error = fmt.Errorf("variable '%s' has no source (literal or from)", varName)
@@ -172,6 +183,28 @@ func (r *Resolver) Plan(ctx context.Context, envName string) (*PlanResult, error
 	return result, nil
 }
 
+// planSource describes a reference for a plan and checks that its store is
+// registered
+func (r *Resolver) planSource(ref *config.Reference) (string, error) {
+	// Check if this is a service reference
+	if ref.IsServiceReference() {
+		return ref.Service, fmt.Errorf("service references (svc://) are for credential rotation, not secret retrieval")
+	}
+
+	providerName := ref.GetEffectiveProvider()
+	legacyRef := ref.ToLegacyProviderRef()
+	source := fmt.Sprintf("provider:%s key:%s", providerName, legacyRef.Key)
+
+	// Check if provider exists
+	r.mu.RLock()
+	_, exists := r.providers[providerName]
+	r.mu.RUnlock()
+	if !exists {
+		return source, fmt.Errorf("provider '%s' not registered", providerName)
+	}
+	return source, nil
+}
+
 // Resolve fetches and processes all variables for an environment
 func (r *Resolver) Resolve(ctx context.Context, envName string) (map[string]ResolvedVariable, error) {
 	env, err := r.config.GetEnvironment(envName)
@@ -281,14 +314,15 @@ func (r *Resolver) resolveVariable(ctx context.Context, varName string, variable
 		resolved.Value = variable.Literal
 		resolved.Source = "literal"
 	} else if variable.From != nil {
-		// Fetch from provider
-		value, source, err := r.resolveFromProvider(ctx, variable.From)
+		// Fetch from provider, falling back through the 'from' list
+		secret, source, err := r.resolveSources(ctx, varName, variable.Sources())
 		if err != nil {
 			resolved.Error = err
 			return resolved
 		}
-		resolved.Value = value
+		resolved.Value = secret.Value
 		resolved.Source = source
+		resolved.Version = secret.Version
 	} else {
 		resolved.Error = dserrors.ConfigError{
 			Field:      varName,
@@ -317,11 +351,121 @@ func (r *Resolver) resolveVariable(ctx context.Context, varName string, variable
 	return resolved
 }
 
+// resolveSources tries a variable's sources in order until one answers. A
+// fallback is only tried when its 'on' condition matches how the last source
+// that was tried failed.
+func (r *Resolver) resolveSources(ctx context.Context, varName string, sources []config.Reference) (provider.SecretValue, string, error) {
+	var (
+		lastErr  error
+		failures []string
+	)
+
+	for i := range sources {
+		ref := &sources[i]
+		if i > 0 {
+			if ctx.Err() != nil {
+				break
+			}
+			if !ref.FallbackApplies(failureCondition(lastErr)) {
+				failures = append(failures, fmt.Sprintf("%s: skipped (on: %s)", describeReference(ref), ref.On))
+				continue
+			}
+		}
+
+		secret, source, err := r.resolveFromProvider(ctx, ref)
+		if err == nil {
+			if i > 0 {
+				r.logger.Warn("%s resolved from fallback %s (%s)", varName, source, strings.Join(failures, "; "))
+			}
+			return secret, source, nil
+		}
+		lastErr = err
+		failures = append(failures, fmt.Sprintf("%s: %s", describeReference(ref), failureReason(err)))
+	}
+
+	if len(sources) == 1 {
+		return provider.SecretValue{}, "", lastErr
+	}
+	return provider.SecretValue{}, "", dserrors.UserError{
+		Message:    fmt.Sprintf("None of the %d sources could resolve the value", len(sources)),
+		Details:    strings.Join(failures, "; "),
+		Suggestion: "Check the first source with 'dsops doctor', or add 'on: any' to fallbacks that were skipped",
+		Err:        lastErr,
+	}
+}
+
+// describeReference names a reference's store and key for messages
+func describeReference(ref *config.Reference) string {
+	if ref.IsServiceReference() {
+		return ref.Service
+	}
+	legacyRef := ref.ToLegacyProviderRef()
+	return fmt.Sprintf("%s:%s", ref.GetEffectiveProvider(), legacyRef.Key)
+}
+
+// failureReason is the underlying cause of a resolve error, on one line.
+// Provider errors carry the cause in Err rather than in their message.
+func failureReason(err error) string {
+	var userErr dserrors.UserError
+	for errors.As(err, &userErr) && userErr.Err != nil {
+		err = userErr.Err
+	}
+	reason, _, _ := strings.Cut(err.Error(), "\n")
+	return reason
+}
+
+// failureCondition classifies a failed source as the fallback condition it
+// meets. Failures that are neither, such as denied access or configuration
+// errors, return an empty string.
+func failureCondition(err error) string {
+	switch {
+	case provider.IsNotFound(err):
+		return config.FallbackOnNotFound
+	case isUnavailable(err):
+		return config.FallbackOnUnavailable
+	default:
+		return ""
+	}
+}
+
+// isUnavailable reports whether a store could not be reached or could not
+// answer: network errors, timeouts and 5xx responses. Providers classify
+// vendor errors as provider.UnavailableError; denied access is an answer from
+// the store and does not count.
+func isUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var authErr provider.AuthError
+	var authErrPtr *provider.AuthError
+	if errors.As(err, &authErr) || errors.As(err, &authErrPtr) {
+		return false
+	}
+
+	if provider.IsUnavailable(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// AWS SDK and Vault response errors carry their HTTP status
+	var withStatus interface{ HTTPStatusCode() int }
+	if errors.As(err, &withStatus) {
+		return withStatus.HTTPStatusCode() >= 500
+	}
+
+	return false
+}
+
 // resolveFromProvider fetches a value from the specified provider
-func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Reference) (string, string, error) {
+func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Reference) (provider.SecretValue, string, error) {
 	// Check if this is a service reference
 	if ref.IsServiceReference() {
-		return "", "", dserrors.ConfigError{
+		return provider.SecretValue{}, "", dserrors.ConfigError{
 			Field:      "reference",
 			Value:      ref.Service,
 			Message:    "service references (svc://) are for credential rotation, not secret retrieval",
@@ -331,7 +475,7 @@ func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Referenc
 
 	providerName := ref.GetEffectiveProvider()
 	if providerName == "" {
-		return "", "", dserrors.ConfigError{
+		return provider.SecretValue{}, "", dserrors.ConfigError{
 			Field:      "provider",
 			Value:      "unknown",
 			Message:    "could not determine provider from reference",
@@ -343,7 +487,7 @@ func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Referenc
 	prov, exists := r.providers[providerName]
 	r.mu.RUnlock()
 	if !exists {
-		return "", "", dserrors.ConfigError{
+		return provider.SecretValue{}, "", dserrors.ConfigError{
 			Field:      "provider",
 			Value:      providerName,
 			Message:    "provider not found in configuration",
@@ -354,7 +498,7 @@ func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Referenc
 	// Get provider configuration for timeout
 	providerConfig, err := r.config.GetProvider(providerName)
 	if err != nil {
-		return "", "", err
+		return provider.SecretValue{}, "", err
 	}
 
 	// Create context with timeout
@@ -375,9 +519,9 @@ func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Referenc
 	if err != nil {
 		// Check if it's a timeout error and enhance the message
 		if timeoutErr := isTimeoutError(err, providerName, timeoutMs); timeoutErr != err {
-			return "", "", timeoutErr
+			return provider.SecretValue{}, "", timeoutErr
 		}
-		return "", "", dserrors.ProviderError(providerName, "resolve", err)
+		return provider.SecretValue{}, "", dserrors.ProviderError(providerName, "resolve", err)
 	}
 
 	source := fmt.Sprintf("%s:%s", providerName, legacyRef.Key)
@@ -385,7 +529,7 @@ func (r *Resolver) resolveFromProvider(ctx context.Context, ref *config.Referenc
 		source += "@" + secret.Version
 	}
 
-	return secret.Value, source, nil
+	return secret, source, nil
 }
 
 // applyTransform applies a transform string to a value
@@ -461,11 +605,11 @@ func (r *Resolver) enforcePolicies(envName string, env config.Environment) error
 		return err
 	}
 
-	// Validate each variable's provider
+	// Validate the provider of each variable source, fallbacks included
 	for varName, variable := range env {
-		if variable.From != nil {
+		for _, source := range variable.Sources() {
 			// Get provider configuration to check type
-			providerConfig, err := r.config.GetProvider(variable.From.Provider)
+			providerConfig, err := r.config.GetProvider(source.Provider)
 			if err != nil {
 				continue // Provider validation will catch this later
 			}
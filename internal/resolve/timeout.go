@@ -21,6 +21,7 @@ func isTimeoutError(err error, providerName string, timeoutMs int) error {
 			Message:    "Provider operation timed out",
 			Details:    fmt.Sprintf("Operation exceeded %dms timeout", timeoutMs),
 			Suggestion: getTimeoutSuggestion(providerName, timeoutMs),
+			Err:        err,
 		}
 	}
 	return err
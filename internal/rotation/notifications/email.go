@@ -216,6 +216,9 @@ func (p *EmailProvider) buildMessage(event RotationEvent) (subject, htmlBody, te
 	// Sanitize service and environment for subject (prevent header injection)
 	service := sanitizeHeader(event.Service)
 	environment := sanitizeHeader(event.Environment)
+	if event.Type.IsIncident() {
+		environment = sanitizeHeader(event.Severity)
+	}
 
 	// Build subject
 	subject = fmt.Sprintf("[dsops] %s: %s (%s)", p.getEventTitle(event.Type, event.Status), service, environment)
@@ -241,6 +244,16 @@ func (p *EmailProvider) getEventTitle(eventType EventType, status RotationStatus
 		return "Rotation Failed"
 	case EventTypeRollback:
 		return "Rotation Rolled Back"
+	case EventTypeIncidentOpened:
+		return "Security Incident Opened"
+	case EventTypeIncidentUpdated:
+		return "Security Incident Updated"
+	case EventTypeIncidentResolved:
+		return "Security Incident Resolved"
+	case EventTypeCertificateExpiring:
+		return "Certificate Expiring"
+	case EventTypeEmergencyRotation:
+		return "Emergency Rotation"
 	default:
 		return "Rotation Event"
 	}
@@ -260,6 +273,16 @@ func (p *EmailProvider) getEventEmoji(eventType EventType, status RotationStatus
 		return "&#x274C;" // ❌
 	case EventTypeRollback:
 		return "&#x23EA;" // ⏪
+	case EventTypeIncidentOpened:
+		return "&#x1F6A8;" // 🚨
+	case EventTypeIncidentUpdated:
+		return "&#x1F50D;" // 🔍
+	case EventTypeIncidentResolved:
+		return "&#x2705;" // ✅
+	case EventTypeCertificateExpiring:
+		return "&#x23F3;" // ⏳
+	case EventTypeEmergencyRotation:
+		return "&#x1F6A8;" // 🚨
 	default:
 		return "&#x1F514;" // 🔔
 	}
@@ -277,6 +300,12 @@ func (p *EmailProvider) getStatusColor(eventType EventType, status RotationStatu
 		return "#dc3545" // red
 	case EventTypeRollback:
 		return "#fd7e14" // orange
+	case EventTypeIncidentOpened, EventTypeEmergencyRotation:
+		return "#dc3545" // red
+	case EventTypeIncidentResolved:
+		return "#28a745" // green
+	case EventTypeCertificateExpiring:
+		return "#ffc107" // yellow
 	default:
 		return "#6c757d" // gray
 	}
@@ -313,16 +342,59 @@ func (p *EmailProvider) buildHTMLBody(event RotationEvent) string {
 	// Service and Environment
 	buf.WriteString(`<table style="width: 100%; border-collapse: collapse; margin-bottom: 20px;">
 `)
-	buf.WriteString(fmt.Sprintf(`<tr>
+	if event.Type.IsIncident() {
+		buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Incident:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Service)))
+		buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Severity:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Severity)))
+		if event.Summary != "" {
+			buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Summary:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Summary)))
+		}
+	} else if event.Type == EventTypeCertificateExpiring {
+		buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Variable:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Service)))
+		buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Environment:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Environment)))
+		buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Severity:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Severity)))
+		if event.Summary != "" {
+			buf.WriteString(fmt.Sprintf(`<tr>
+<td style="padding: 8px 0;"><strong>Summary:</strong></td>
+<td style="padding: 8px 0;">%s</td>
+</tr>
+`, html.EscapeString(event.Summary)))
+		}
+	} else {
+		buf.WriteString(fmt.Sprintf(`<tr>
 <td style="padding: 8px 0;"><strong>Service:</strong></td>
 <td style="padding: 8px 0;">%s</td>
 </tr>
 `, html.EscapeString(event.Service)))
-	buf.WriteString(fmt.Sprintf(`<tr>
+		buf.WriteString(fmt.Sprintf(`<tr>
 <td style="padding: 8px 0;"><strong>Environment:</strong></td>
 <td style="padding: 8px 0;">%s</td>
 </tr>
 `, html.EscapeString(event.Environment)))
+	}
 
 	if event.Strategy != "" {
 		buf.WriteString(fmt.Sprintf(`<tr>
@@ -376,8 +448,8 @@ func (p *EmailProvider) buildHTMLBody(event RotationEvent) string {
 	buf.WriteString(`</div>
 
 <div style="margin-top: 20px; font-size: 12px; color: #6c757d; text-align: center;">
-<p>This notification was sent by dsops rotation system.</p>
-<p>Run <code>dsops rotation history --service ` + html.EscapeString(event.Service) + `</code> for details.</p>
+<p>This notification was sent by dsops ` + p.getSystemName(event.Type) + ` system.</p>
+<p>Run <code>` + html.EscapeString(p.getDetailsCommand(event)) + `</code> for details.</p>
 </div>
 </body>
 </html>`)
@@ -395,8 +467,23 @@ func (p *EmailProvider) buildTextBody(event RotationEvent) string {
 	buf.WriteString(strings.Repeat("=", len(title)))
 	buf.WriteString("\n\n")
 
-	buf.WriteString(fmt.Sprintf("Service: %s\n", event.Service))
-	buf.WriteString(fmt.Sprintf("Environment: %s\n", event.Environment))
+	if event.Type.IsIncident() {
+		buf.WriteString(fmt.Sprintf("Incident: %s\n", event.Service))
+		buf.WriteString(fmt.Sprintf("Severity: %s\n", event.Severity))
+		if event.Summary != "" {
+			buf.WriteString(fmt.Sprintf("Summary: %s\n", event.Summary))
+		}
+	} else if event.Type == EventTypeCertificateExpiring {
+		buf.WriteString(fmt.Sprintf("Variable: %s\n", event.Service))
+		buf.WriteString(fmt.Sprintf("Environment: %s\n", event.Environment))
+		buf.WriteString(fmt.Sprintf("Severity: %s\n", event.Severity))
+		if event.Summary != "" {
+			buf.WriteString(fmt.Sprintf("Summary: %s\n", event.Summary))
+		}
+	} else {
+		buf.WriteString(fmt.Sprintf("Service: %s\n", event.Service))
+		buf.WriteString(fmt.Sprintf("Environment: %s\n", event.Environment))
+	}
 
 	if event.Strategy != "" {
 		buf.WriteString(fmt.Sprintf("Strategy: %s\n", event.Strategy))
@@ -420,12 +507,34 @@ func (p *EmailProvider) buildTextBody(event RotationEvent) string {
 	}
 
 	buf.WriteString("\n---\n")
-	buf.WriteString("This notification was sent by dsops rotation system.\n")
-	buf.WriteString(fmt.Sprintf("Run `dsops rotation history --service %s` for details.\n", event.Service))
+	buf.WriteString(fmt.Sprintf("This notification was sent by dsops %s system.\n", p.getSystemName(event.Type)))
+	buf.WriteString(fmt.Sprintf("Run `%s` for details.\n", p.getDetailsCommand(event)))
 
 	return buf.String()
 }
 
+// getSystemName returns the dsops subsystem that produced the event.
+func (p *EmailProvider) getSystemName(eventType EventType) string {
+	if eventType.IsIncident() {
+		return "incident response"
+	}
+	if eventType == EventTypeCertificateExpiring {
+		return "certificate monitoring"
+	}
+	return "rotation"
+}
+
+// getDetailsCommand returns the dsops command that shows details for the event.
+func (p *EmailProvider) getDetailsCommand(event RotationEvent) string {
+	if event.Type.IsIncident() {
+		return fmt.Sprintf("dsops leak show %s", event.Service)
+	}
+	if event.Type == EventTypeCertificateExpiring {
+		return fmt.Sprintf("dsops certs scan --env %s", event.Environment)
+	}
+	return fmt.Sprintf("dsops rotation history --service %s", event.Service)
+}
+
 // sanitizeHeader removes newlines and header injection patterns to prevent
 // both SMTP header injection and confusing subject lines.
 func sanitizeHeader(s string) string {
@@ -303,6 +303,24 @@ func TestEmailProvider_BuildMessage(t *testing.T) {
 				"development",
 			},
 		},
+		{
+			name: "incident opened event",
+			event: RotationEvent{
+				Type:      EventTypeIncidentOpened,
+				Service:   "incident-20251204-103000",
+				Status:    StatusFailure,
+				Severity:  "critical",
+				Summary:   "AWS keys committed to main",
+				Timestamp: time.Now(),
+			},
+			wantSubj: "[dsops] Security Incident Opened: incident-20251204-103000 (critical)",
+			wantBody: []string{
+				"Security Incident Opened",
+				"AWS keys committed to main",
+				"dsops leak show incident-20251204-103000",
+			},
+			dontWant: []string{"rotation history", "Environment:"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -19,8 +19,36 @@ const (
 
 	// EventTypeRollback indicates a rollback has occurred.
 	EventTypeRollback EventType = "rollback"
+
+	// EventTypeIncidentOpened indicates a security incident has been reported.
+	EventTypeIncidentOpened EventType = "incident_opened"
+
+	// EventTypeIncidentUpdated indicates a security incident has been updated.
+	EventTypeIncidentUpdated EventType = "incident_updated"
+
+	// EventTypeIncidentResolved indicates a security incident has been resolved.
+	EventTypeIncidentResolved EventType = "incident_resolved"
+
+	// EventTypeCertificateExpiring indicates a certificate is expiring or
+	// has expired.
+	EventTypeCertificateExpiring EventType = "certificate_expiring"
+
+	// EventTypeEmergencyRotation indicates a compromised secret is being
+	// rotated with every old version revoked and no grace period.
+	EventTypeEmergencyRotation EventType = "emergency_rotation"
 )
 
+// IsIncident returns true if the event type describes a security incident
+// rather than a rotation.
+func (t EventType) IsIncident() bool {
+	switch t {
+	case EventTypeIncidentOpened, EventTypeIncidentUpdated, EventTypeIncidentResolved:
+		return true
+	default:
+		return false
+	}
+}
+
 // RotationStatus represents the outcome status of a rotation.
 type RotationStatus string
 
@@ -36,11 +64,17 @@ const (
 )
 
 // RotationEvent represents a rotation lifecycle event for notifications.
+// Incident events reuse the same structure: Service holds the incident ID,
+// Severity and Summary describe the incident. Certificate events set Service
+// to the variable holding the certificate and Severity to warning, critical
+// or expired.
 type RotationEvent struct {
-	// Type is the type of event (started, completed, failed, rollback).
+	// Type is the type of event (started, completed, failed, rollback,
+	// incident_opened, incident_updated, incident_resolved,
+	// certificate_expiring).
 	Type EventType
 
-	// Service is the name of the service being rotated.
+	// Service is the name of the service being rotated, or the incident ID.
 	Service string
 
 	// Environment is the environment name (e.g., "production", "staging").
@@ -75,6 +109,15 @@ type RotationEvent struct {
 
 	// InitiatedBy indicates who or what initiated the rotation.
 	InitiatedBy string
+
+	// Severity is the incident severity (critical, high, medium, low) or the
+	// certificate expiry level (warning, critical, expired). Empty for
+	// rotation events.
+	Severity string
+
+	// Summary is a one-line description of the incident or certificate.
+	// Empty for rotation events.
+	Summary string
 }
 
 // AllEventTypes returns all valid event types.
@@ -84,5 +127,10 @@ func AllEventTypes() []EventType {
 		EventTypeCompleted,
 		EventTypeFailed,
 		EventTypeRollback,
+		EventTypeIncidentOpened,
+		EventTypeIncidentUpdated,
+		EventTypeIncidentResolved,
+		EventTypeCertificateExpiring,
+		EventTypeEmergencyRotation,
 	}
 }
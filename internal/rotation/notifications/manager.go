@@ -109,6 +109,37 @@ func (m *Manager) Send(event RotationEvent) {
 	}
 }
 
+// DeliveryResult records the outcome of delivering an event to one provider.
+type DeliveryResult struct {
+	// Provider is the name of the provider the event was sent to.
+	Provider string
+
+	// Err is the delivery error, or nil on success.
+	Err error
+}
+
+// Deliver sends an event synchronously to all providers that support it and
+// returns the outcome for each. Unlike Send, it does not require Start and
+// is meant for short-lived commands that must report delivery status.
+func (m *Manager) Deliver(ctx context.Context, event RotationEvent) []DeliveryResult {
+	m.mu.RLock()
+	providers := m.providers
+	m.mu.RUnlock()
+
+	var results []DeliveryResult
+	for _, provider := range providers {
+		if !provider.SupportsEvent(event.Type) {
+			continue
+		}
+		results = append(results, DeliveryResult{
+			Provider: provider.Name(),
+			Err:      provider.Send(ctx, event),
+		})
+	}
+
+	return results
+}
+
 // DroppedCount returns the number of events that were dropped due to queue overflow.
 func (m *Manager) DroppedCount() int64 {
 	m.droppedMu.Lock()
@@ -240,6 +240,39 @@ func TestManager_Send_DropsWhenQueueFull(t *testing.T) {
 	assert.Greater(t, dropped, int64(0), "Some events should have been dropped")
 }
 
+func TestManager_Deliver(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager(10)
+	incidents := newFakeProvider("incidents")
+	incidents.supportedEvts = []EventType{EventTypeIncidentOpened}
+	failing := newFakeProvider("failing")
+	failing.sendFunc = func(ctx context.Context, event RotationEvent) error {
+		return assert.AnError
+	}
+	rotationsOnly := newFakeProvider("rotations")
+	rotationsOnly.supportedEvts = []EventType{EventTypeCompleted}
+
+	m.RegisterProvider(incidents)
+	m.RegisterProvider(failing)
+	m.RegisterProvider(rotationsOnly)
+
+	// Deliver works without Start and reports each provider's outcome
+	results := m.Deliver(context.Background(), RotationEvent{
+		Type:      EventTypeIncidentOpened,
+		Service:   "incident-1",
+		Timestamp: time.Now(),
+	})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "incidents", results[0].Provider)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "failing", results[1].Provider)
+	assert.ErrorIs(t, results[1].Err, assert.AnError)
+	assert.Len(t, incidents.getSentEvents(), 1)
+	assert.Empty(t, rotationsOnly.getSentEvents())
+}
+
 func TestManager_Send_NotRunning(t *testing.T) {
 	t.Parallel()
 
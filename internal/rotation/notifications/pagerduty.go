@@ -110,8 +110,9 @@ func (p *PagerDutyProvider) Validate(ctx context.Context) error {
 func (p *PagerDutyProvider) Send(ctx context.Context, event RotationEvent) error {
 	action := p.determineAction(event)
 
-	// If this is a resolve action but AutoResolve is disabled, skip
-	if action == "resolve" && !p.config.AutoResolve {
+	// If this is a resolve action but AutoResolve is disabled, skip.
+	// Incidents resolved by a person are always resolved in PagerDuty.
+	if action == "resolve" && !p.config.AutoResolve && event.Type != EventTypeIncidentResolved {
 		return nil
 	}
 
@@ -152,6 +153,8 @@ func (p *PagerDutyProvider) determineAction(event RotationEvent) string {
 		return "trigger"
 	case EventTypeFailed, EventTypeRollback:
 		return "trigger"
+	case EventTypeIncidentResolved:
+		return "resolve"
 	default:
 		return "trigger"
 	}
@@ -170,10 +173,14 @@ func (p *PagerDutyProvider) buildPayload(event RotationEvent, action string) map
 		payload["payload"] = p.buildEventPayload(event)
 	} else {
 		// For resolve, still include minimal payload
+		summary := fmt.Sprintf("dsops rotation completed: %s (%s)", event.Service, event.Environment)
+		if event.Type.IsIncident() {
+			summary = p.buildSummary(event)
+		}
 		payload["payload"] = map[string]interface{}{
-			"summary":  fmt.Sprintf("dsops rotation completed: %s (%s)", event.Service, event.Environment),
-			"severity": p.getSeverity(),
-			"source":   "dsops-rotation",
+			"summary":  summary,
+			"severity": p.getEventSeverity(event),
+			"source":   p.getSource(event),
 		}
 	}
 
@@ -192,6 +199,20 @@ func (p *PagerDutyProvider) buildEventPayload(event RotationEvent) map[string]in
 		"timestamp":   event.Timestamp.Format(time.RFC3339),
 	}
 
+	if event.Type.IsIncident() {
+		delete(customDetails, "service")
+		delete(customDetails, "status")
+		customDetails["incident_id"] = event.Service
+		customDetails["incident_severity"] = event.Severity
+	}
+
+	if event.Type == EventTypeCertificateExpiring {
+		delete(customDetails, "service")
+		delete(customDetails, "status")
+		customDetails["variable"] = event.Service
+		customDetails["certificate_severity"] = event.Severity
+	}
+
 	if event.Strategy != "" {
 		customDetails["strategy"] = event.Strategy
 	}
@@ -211,8 +232,8 @@ func (p *PagerDutyProvider) buildEventPayload(event RotationEvent) map[string]in
 
 	payload := map[string]interface{}{
 		"summary":        summary,
-		"severity":       p.getSeverity(),
-		"source":         "dsops-rotation",
+		"severity":       p.getEventSeverity(event),
+		"source":         p.getSource(event),
 		"custom_details": customDetails,
 	}
 
@@ -246,6 +267,32 @@ func (p *PagerDutyProvider) buildSummary(event RotationEvent) string {
 
 	summary := fmt.Sprintf("dsops rotation %s: %s (%s)", action, event.Service, event.Environment)
 
+	if event.Type.IsIncident() {
+		switch event.Type {
+		case EventTypeIncidentOpened:
+			action = "opened"
+		case EventTypeIncidentUpdated:
+			action = "updated"
+		case EventTypeIncidentResolved:
+			action = "resolved"
+		}
+		summary = fmt.Sprintf("dsops security incident %s: %s [%s]", action, event.Service, event.Severity)
+		if event.Summary != "" {
+			summary = fmt.Sprintf("%s %s", summary, event.Summary)
+		}
+	}
+
+	if event.Type == EventTypeEmergencyRotation {
+		summary = fmt.Sprintf("dsops emergency rotation of compromised secret: %s (%s)", event.Service, event.Environment)
+	}
+
+	if event.Type == EventTypeCertificateExpiring {
+		summary = fmt.Sprintf("dsops certificate expiring: %s (%s) [%s]", event.Service, event.Environment, event.Severity)
+		if event.Summary != "" {
+			summary = fmt.Sprintf("%s %s", summary, event.Summary)
+		}
+	}
+
 	if event.Error != nil {
 		summary = fmt.Sprintf("%s - %s", summary, event.Error.Error())
 	}
@@ -261,6 +308,16 @@ func (p *PagerDutyProvider) buildSummary(event RotationEvent) string {
 // buildDedupKey creates a deduplication key for the event.
 // This ensures related events (trigger, resolve) are grouped together.
 func (p *PagerDutyProvider) buildDedupKey(event RotationEvent) string {
+	// All events for one incident share a key so resolving closes the page
+	if event.Type.IsIncident() {
+		return fmt.Sprintf("dsops-incident-%s", event.Service)
+	}
+
+	// Repeated scans of the same certificate update one alert
+	if event.Type == EventTypeCertificateExpiring {
+		return fmt.Sprintf("dsops-certificate-%s-%s", event.Environment, event.Service)
+	}
+
 	parts := []string{"dsops", event.Service, event.Environment}
 
 	// Include rotation_id if available
@@ -279,6 +336,49 @@ func (p *PagerDutyProvider) getSeverity() string {
 	return strings.ToLower(p.config.Severity)
 }
 
+// getEventSeverity returns the PagerDuty severity for the event. Incident
+// and certificate severities are mapped onto PagerDuty levels, emergency
+// rotations are always critical, and other rotation events use the
+// configured severity.
+func (p *PagerDutyProvider) getEventSeverity(event RotationEvent) string {
+	if event.Type == EventTypeEmergencyRotation {
+		return string(SeverityCritical)
+	}
+	if event.Type == EventTypeCertificateExpiring {
+		if event.Severity == "warning" {
+			return string(SeverityWarning)
+		}
+		return string(SeverityCritical)
+	}
+	if !event.Type.IsIncident() {
+		return p.getSeverity()
+	}
+
+	switch strings.ToLower(event.Severity) {
+	case "critical":
+		return string(SeverityCritical)
+	case "high":
+		return string(SeverityError)
+	case "medium":
+		return string(SeverityWarning)
+	case "low":
+		return string(SeverityInfo)
+	default:
+		return p.getSeverity()
+	}
+}
+
+// getSource returns the PagerDuty event source for the event.
+func (p *PagerDutyProvider) getSource(event RotationEvent) string {
+	if event.Type.IsIncident() {
+		return "dsops-incident"
+	}
+	if event.Type == EventTypeCertificateExpiring {
+		return "dsops-certs"
+	}
+	return "dsops-rotation"
+}
+
 // PagerDutyNotificationConfig mirrors the config package type for internal use.
 type PagerDutyNotificationConfig struct {
 	IntegrationKey string
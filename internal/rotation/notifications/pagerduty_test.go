@@ -502,6 +502,124 @@ func TestPagerDutyProvider_AutoResolveDisabled(t *testing.T) {
 	// The behavior depends on implementation - this documents expected behavior
 }
 
+func TestPagerDutyProvider_IncidentEvents(t *testing.T) {
+	t.Parallel()
+
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	provider := NewPagerDutyProvider(PagerDutyConfig{
+		IntegrationKey: "test-key",
+		Severity:       "warning",
+		AutoResolve:    false,
+	})
+	provider.apiURL = server.URL
+
+	opened := RotationEvent{
+		Type:      EventTypeIncidentOpened,
+		Service:   "incident-1",
+		Status:    StatusFailure,
+		Severity:  "critical",
+		Summary:   "Database password leaked",
+		Timestamp: time.Now(),
+	}
+	require.NoError(t, provider.Send(context.Background(), opened))
+
+	resolved := opened
+	resolved.Type = EventTypeIncidentResolved
+	resolved.Status = StatusSuccess
+	require.NoError(t, provider.Send(context.Background(), resolved))
+
+	// Resolving an incident is sent even with AutoResolve disabled
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "trigger", payloads[0]["event_action"])
+	assert.Equal(t, "resolve", payloads[1]["event_action"])
+	assert.Equal(t, "dsops-incident-incident-1", payloads[0]["dedup_key"])
+	assert.Equal(t, payloads[0]["dedup_key"], payloads[1]["dedup_key"])
+
+	payload, ok := payloads[0]["payload"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "critical", payload["severity"])
+	assert.Equal(t, "dsops-incident", payload["source"])
+	assert.Contains(t, payload["summary"], "Database password leaked")
+
+	details, ok := payload["custom_details"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "incident-1", details["incident_id"])
+}
+
+func TestPagerDutyProvider_IncidentSeverityMapping(t *testing.T) {
+	t.Parallel()
+
+	provider := NewPagerDutyProvider(PagerDutyConfig{IntegrationKey: "test-key", Severity: "warning"})
+
+	tests := map[string]string{
+		"critical": "critical",
+		"high":     "error",
+		"medium":   "warning",
+		"low":      "info",
+		"":         "warning",
+	}
+	for severity, want := range tests {
+		event := RotationEvent{Type: EventTypeIncidentOpened, Severity: severity}
+		assert.Equal(t, want, provider.getEventSeverity(event), "severity %q", severity)
+	}
+
+	// Rotation events keep the configured severity
+	assert.Equal(t, "warning", provider.getEventSeverity(RotationEvent{Type: EventTypeFailed, Severity: "critical"}))
+}
+
+func TestPagerDutyProvider_CertificateEvents(t *testing.T) {
+	t.Parallel()
+
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	provider := NewPagerDutyProvider(PagerDutyConfig{IntegrationKey: "test-key", Severity: "info"})
+	provider.apiURL = server.URL
+
+	event := RotationEvent{
+		Type:        EventTypeCertificateExpiring,
+		Service:     "TLS_CERT",
+		Environment: "production",
+		Status:      StatusFailure,
+		Severity:    "expired",
+		Summary:     "CN=api.example.com expired 2 day(s) ago",
+		Timestamp:   time.Now(),
+	}
+	require.NoError(t, provider.Send(context.Background(), event))
+
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "trigger", payloads[0]["event_action"])
+	assert.Equal(t, "dsops-certificate-production-TLS_CERT", payloads[0]["dedup_key"])
+
+	payload, ok := payloads[0]["payload"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "critical", payload["severity"])
+	assert.Equal(t, "dsops-certs", payload["source"])
+	assert.Contains(t, payload["summary"], "CN=api.example.com expired")
+
+	details, ok := payload["custom_details"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "TLS_CERT", details["variable"])
+
+	// Warnings are not escalated
+	event.Severity = "warning"
+	assert.Equal(t, "warning", provider.getEventSeverity(event))
+}
+
 func TestCreatePagerDutyProvider(t *testing.T) {
 	t.Parallel()
 
@@ -132,20 +132,74 @@ func (p *SlackProvider) buildMessage(event RotationEvent) map[string]interface{}
 		},
 	})
 
-	// Service and environment info
-	blocks = append(blocks, map[string]interface{}{
-		"type": "section",
-		"fields": []map[string]interface{}{
-			{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("*Service:*\n%s", event.Service),
+	if event.Type.IsIncident() {
+		// Incident ID, severity and summary
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"fields": []map[string]interface{}{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Incident:*\n%s", event.Service),
+				},
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Severity:*\n%s", event.Severity),
+				},
 			},
-			{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("*Environment:*\n%s", event.Environment),
+		})
+		if event.Summary != "" {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": event.Summary,
+				},
+			})
+		}
+	} else if event.Type == EventTypeCertificateExpiring {
+		// Variable, environment, and the certificate summary
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"fields": []map[string]interface{}{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Variable:*\n%s", event.Service),
+				},
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Environment:*\n%s", event.Environment),
+				},
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Severity:*\n%s", event.Severity),
+				},
 			},
-		},
-	})
+		})
+		if event.Summary != "" {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": event.Summary,
+				},
+			})
+		}
+	} else {
+		// Service and environment info
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"fields": []map[string]interface{}{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Service:*\n%s", event.Service),
+				},
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*Environment:*\n%s", event.Environment),
+				},
+			},
+		})
+	}
 
 	// Strategy and duration
 	if event.Strategy != "" || event.Duration > 0 {
@@ -236,6 +290,16 @@ func (p *SlackProvider) getEventEmoji(eventType EventType, status RotationStatus
 		return ":x:"
 	case EventTypeRollback:
 		return ":rewind:"
+	case EventTypeIncidentOpened:
+		return ":rotating_light:"
+	case EventTypeIncidentUpdated:
+		return ":mag:"
+	case EventTypeIncidentResolved:
+		return ":white_check_mark:"
+	case EventTypeCertificateExpiring:
+		return ":hourglass_flowing_sand:"
+	case EventTypeEmergencyRotation:
+		return ":rotating_light:"
 	default:
 		return ":bell:"
 	}
@@ -255,6 +319,16 @@ func (p *SlackProvider) getEventTitle(eventType EventType, status RotationStatus
 		return "Rotation Failed"
 	case EventTypeRollback:
 		return "Rotation Rolled Back"
+	case EventTypeIncidentOpened:
+		return "Security Incident Opened"
+	case EventTypeIncidentUpdated:
+		return "Security Incident Updated"
+	case EventTypeIncidentResolved:
+		return "Security Incident Resolved"
+	case EventTypeCertificateExpiring:
+		return "Certificate Expiring"
+	case EventTypeEmergencyRotation:
+		return "Emergency Rotation"
 	default:
 		return "Rotation Event"
 	}
@@ -269,10 +343,15 @@ func (p *SlackProvider) getMentions(event RotationEvent) string {
 	var mentions []string
 
 	switch event.Type {
-	case EventTypeFailed:
+	case EventTypeFailed, EventTypeIncidentOpened, EventTypeEmergencyRotation:
 		mentions = p.config.Mentions.OnFailure
 	case EventTypeRollback:
 		mentions = p.config.Mentions.OnRollback
+	case EventTypeCertificateExpiring:
+		// Only certificates past the critical threshold page people
+		if event.Severity == "critical" || event.Severity == "expired" {
+			mentions = p.config.Mentions.OnFailure
+		}
 	}
 
 	if len(mentions) == 0 {
@@ -298,6 +298,24 @@ func TestSlackProvider_BlockKitFormat(t *testing.T) {
 			status:    StatusRolledBack,
 			wantEmoji: ":rewind:",
 		},
+		{
+			name:      "incident opened",
+			eventType: EventTypeIncidentOpened,
+			status:    StatusFailure,
+			wantEmoji: ":rotating_light:",
+		},
+		{
+			name:      "incident resolved",
+			eventType: EventTypeIncidentResolved,
+			status:    StatusSuccess,
+			wantEmoji: ":white_check_mark:",
+		},
+		{
+			name:      "certificate expiring",
+			eventType: EventTypeCertificateExpiring,
+			status:    StatusFailure,
+			wantEmoji: ":hourglass_flowing_sand:",
+		},
 	}
 
 	for _, tt := range tests {
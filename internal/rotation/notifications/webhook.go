@@ -236,6 +236,8 @@ type webhookTemplateData struct {
 	Error       string
 	Duration    string
 	Timestamp   string
+	Severity    string
+	Summary     string
 	Metadata    map[string]string
 }
 
@@ -249,6 +251,8 @@ func (p *WebhookProvider) buildCustomPayload(event RotationEvent) ([]byte, error
 		Status:      string(event.Status),
 		Duration:    event.Duration.String(),
 		Timestamp:   event.Timestamp.Format(time.RFC3339),
+		Severity:    event.Severity,
+		Summary:     event.Summary,
 		Metadata:    event.Metadata,
 	}
 
@@ -287,6 +291,14 @@ func (p *WebhookProvider) buildDefaultPayload(event RotationEvent) ([]byte, erro
 		payload["error"] = event.Error.Error()
 	}
 
+	if event.Severity != "" {
+		payload["severity"] = event.Severity
+	}
+
+	if event.Summary != "" {
+		payload["summary"] = event.Summary
+	}
+
 	if len(event.Metadata) > 0 {
 		payload["metadata"] = event.Metadata
 	}
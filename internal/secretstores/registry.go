@@ -28,7 +28,10 @@ func NewRegistry() *Registry {
 		"mock",
 		"json",
 		"bitwarden",
+		"bitwarden.secretsmanager",
 		"onepassword",
+		"onepassword.connect",
+		"onepassword.serviceaccount",
 		"vault",
 		"aws.secretsmanager",
 		"aws.ssm",
@@ -42,12 +45,27 @@ func NewRegistry() *Registry {
 		"azure",
 		"doppler",
 		"pass",
+		"sops",
+		"kubernetes",
+		"consul",
+		"etcd",
+		"file.dotenv",
+		"file.json",
+		"file.yaml",
+		"plugin",
 	}
 
 	for _, storeType := range secretStoreTypes {
 		registry.supportedTypes[storeType] = true
 	}
 
+	// Plugins found in the plugin directories are stores too
+	for _, storeType := range registry.providerRegistry.GetSupportedTypes() {
+		if _, ok := registry.providerRegistry.PluginPath(storeType); ok {
+			registry.supportedTypes[storeType] = true
+		}
+	}
+
 	return registry
 }
 
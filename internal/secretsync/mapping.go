@@ -0,0 +1,83 @@
+package secretsync
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Key case conversions supported by Job.KeyCase
+const (
+	CaseUpper = "upper"
+	CaseLower = "lower"
+	CaseKebab = "kebab"
+	CaseSnake = "snake"
+)
+
+// RenameRule rewrites source keys that match a regular expression
+type RenameRule struct {
+	Match   *regexp.Regexp
+	Replace string
+}
+
+// matches reports whether key passes the job's include and exclude patterns
+func (j Job) matches(key string) bool {
+	for _, pattern := range j.Exclude {
+		if ok, _ := path.Match(pattern, key); ok {
+			return false
+		}
+	}
+	if len(j.Include) == 0 {
+		return true
+	}
+	for _, pattern := range j.Include {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// destinationKey maps a source key (relative to the from prefix) to its full
+// key in the destination store
+func (j Job) destinationKey(key string) string {
+	if mapped, ok := j.Map[key]; ok {
+		return j.To.Prefix + mapped
+	}
+
+	mapped := key
+	for _, rule := range j.Rename {
+		if rule.Match.MatchString(mapped) {
+			mapped = rule.Match.ReplaceAllString(mapped, rule.Replace)
+			break
+		}
+	}
+
+	return j.To.Prefix + convertCase(mapped, j.KeyCase)
+}
+
+func convertCase(key, keyCase string) string {
+	switch keyCase {
+	case CaseUpper:
+		return strings.ToUpper(key)
+	case CaseLower:
+		return strings.ToLower(key)
+	case CaseKebab:
+		return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+	case CaseSnake:
+		return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	default:
+		return key
+	}
+}
+
+// validatePatterns checks that include and exclude globs are well formed
+func validatePatterns(field string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid %s pattern %q: %w", field, pattern, err)
+		}
+	}
+	return nil
+}
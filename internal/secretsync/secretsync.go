@@ -0,0 +1,435 @@
+// Package secretsync copies secrets from one secret store to another.
+//
+// A Job describes which keys to copy and how to name them in the
+// destination. Syncer.Plan reads both sides through the resolver and
+// compares value fingerprints, so re-running a sync only writes what
+// changed. Syncer.Apply writes the planned changes through the destination's
+// provider.Writer. Secret values never leave the package: plans expose only
+// keys, versions and fingerprints.
+package secretsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/policy"
+	"github.com/systmms/dsops/internal/resolve"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// ConflictPolicy decides what happens when the destination already holds a
+// different value
+type ConflictPolicy string
+
+const (
+	// ConflictFail refuses to apply the sync while any conflict exists
+	ConflictFail ConflictPolicy = "fail"
+	// ConflictSkip leaves conflicting destination secrets untouched
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces destination values with the source values
+	ConflictOverwrite ConflictPolicy = "overwrite"
+)
+
+// Action is what a sync does with a single key
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+	ActionSkip      Action = "skip"
+	ActionConflict  Action = "conflict"
+)
+
+// Endpoint is a store name with an optional key prefix
+type Endpoint struct {
+	Store  string
+	Prefix string
+}
+
+// ParseEndpoint parses store[/prefix]
+func ParseEndpoint(s string) Endpoint {
+	store, prefix, _ := strings.Cut(s, "/")
+	return Endpoint{Store: store, Prefix: prefix}
+}
+
+func (e Endpoint) String() string {
+	if e.Prefix == "" {
+		return e.Store
+	}
+	return e.Store + "/" + e.Prefix
+}
+
+// Job is a validated sync definition
+type Job struct {
+	Name       string
+	From       Endpoint
+	To         Endpoint
+	Keys       []string
+	Include    []string
+	Exclude    []string
+	Map        map[string]string
+	Rename     []RenameRule
+	KeyCase    string
+	Transform  string
+	OnConflict ConflictPolicy
+	Tags       map[string]string
+	Env        string
+}
+
+// JobFromConfig validates a sync section entry and converts it to a Job
+func JobFromConfig(name string, sc config.SyncConfig) (Job, error) {
+	invalid := func(field string, value interface{}, message, suggestion string) error {
+		return dserrors.ConfigError{
+			Field:      fmt.Sprintf("sync.%s.%s", name, field),
+			Value:      value,
+			Message:    message,
+			Suggestion: suggestion,
+		}
+	}
+
+	job := Job{
+		Name:       name,
+		From:       ParseEndpoint(sc.From),
+		To:         ParseEndpoint(sc.To),
+		Keys:       sc.Keys,
+		Include:    sc.Include,
+		Exclude:    sc.Exclude,
+		Map:        sc.Map,
+		KeyCase:    sc.KeyCase,
+		Transform:  sc.Transform,
+		OnConflict: ConflictPolicy(sc.OnConflict),
+		Tags:       sc.Tags,
+		Env:        sc.Env,
+	}
+
+	if job.From.Store == "" {
+		return Job{}, invalid("from", sc.From, "source store is required", "Set 'from: <store>[/prefix]'")
+	}
+	if job.To.Store == "" {
+		return Job{}, invalid("to", sc.To, "destination store is required", "Set 'to: <store>[/prefix]'")
+	}
+	if job.From == job.To {
+		return Job{}, invalid("to", sc.To, "source and destination are the same", "Sync to a different store or prefix")
+	}
+
+	switch job.OnConflict {
+	case "":
+		job.OnConflict = ConflictFail
+	case ConflictFail, ConflictSkip, ConflictOverwrite:
+	default:
+		return Job{}, invalid("on_conflict", sc.OnConflict, "unknown conflict policy", "Use fail, skip or overwrite")
+	}
+
+	switch job.KeyCase {
+	case "", CaseUpper, CaseLower, CaseKebab, CaseSnake:
+	default:
+		return Job{}, invalid("key_case", sc.KeyCase, "unknown key case", "Use upper, lower, kebab or snake")
+	}
+
+	if err := validatePatterns("include", job.Include); err != nil {
+		return Job{}, invalid("include", sc.Include, err.Error(), "Use glob patterns such as 'DB_*'")
+	}
+	if err := validatePatterns("exclude", job.Exclude); err != nil {
+		return Job{}, invalid("exclude", sc.Exclude, err.Error(), "Use glob patterns such as 'DOPPLER_*'")
+	}
+
+	for i, rule := range sc.Rename {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return Job{}, invalid(fmt.Sprintf("rename[%d].match", i), rule.Match, "invalid regular expression", err.Error())
+		}
+		job.Rename = append(job.Rename, RenameRule{Match: re, Replace: rule.Replace})
+	}
+
+	return job, nil
+}
+
+// Item is the planned sync of a single key. It carries no secret values.
+type Item struct {
+	SourceKey         string `json:"source_key"`
+	DestinationKey    string `json:"destination_key"`
+	Action            Action `json:"action"`
+	SourceVersion     string `json:"source_version,omitempty"`
+	DestVersion       string `json:"destination_version,omitempty"`
+	SourceFingerprint string `json:"source_fingerprint"`
+	DestFingerprint   string `json:"destination_fingerprint,omitempty"`
+
+	value string
+}
+
+// Plan is the set of changes a sync would make
+type Plan struct {
+	Job   Job
+	Items []Item
+}
+
+// Count returns the number of items with the given action
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, item := range p.Items {
+		if item.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges reports whether applying the plan would write anything
+func (p *Plan) HasChanges() bool {
+	return p.Count(ActionCreate)+p.Count(ActionUpdate) > 0
+}
+
+// Summary describes the plan in one line
+func (p *Plan) Summary() string {
+	return fmt.Sprintf("%d to create, %d to update, %d unchanged, %d skipped, %d conflicts",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionUnchanged),
+		p.Count(ActionSkip), p.Count(ActionConflict))
+}
+
+// Result reports what Apply wrote
+type Result struct {
+	Created int
+	Updated int
+}
+
+// Syncer plans and applies a single Job
+type Syncer struct {
+	job      Job
+	resolver *resolve.Resolver
+	source   provider.Provider
+	writer   provider.Writer
+	enforcer *policy.PolicyEnforcer
+}
+
+// New creates a Syncer for job. Both stores must already be registered with
+// the resolver, and the destination must implement provider.Writer.
+func New(resolver *resolve.Resolver, job Job, enforcer *policy.PolicyEnforcer) (*Syncer, error) {
+	source, ok := resolver.GetProvider(job.From.Store)
+	if !ok {
+		return nil, fmt.Errorf("source store %s is not registered", job.From.Store)
+	}
+	dest, ok := resolver.GetProvider(job.To.Store)
+	if !ok {
+		return nil, fmt.Errorf("destination store %s is not registered", job.To.Store)
+	}
+
+	writer, ok := dest.(provider.Writer)
+	if !ok {
+		suggestion := "Sync to a store whose provider supports writing secrets"
+		if writable := writableStores(resolver); len(writable) > 0 {
+			suggestion = "Sync to a writable store: " + strings.Join(writable, ", ")
+		}
+		return nil, dserrors.UserError{
+			Message:    fmt.Sprintf("Destination store '%s' is read-only", job.To.Store),
+			Suggestion: suggestion,
+		}
+	}
+
+	if enforcer == nil {
+		enforcer = policy.NewPolicyEnforcer(nil)
+	}
+
+	return &Syncer{job: job, resolver: resolver, source: source, writer: writer, enforcer: enforcer}, nil
+}
+
+// writableStores returns the registered stores that implement provider.Writer
+func writableStores(resolver *resolve.Resolver) []string {
+	var names []string
+	for name, p := range resolver.GetRegisteredProviders() {
+		if _, ok := p.(provider.Writer); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plan reads the source and destination and works out what to write
+func (s *Syncer) Plan(ctx context.Context) (*Plan, error) {
+	keys, err := s.sourceKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Job: s.job}
+	if len(keys) == 0 {
+		return plan, nil
+	}
+
+	// Map keys and catch collisions before reading anything
+	destKeys := make(map[string]string, len(keys))
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		dest := s.job.destinationKey(key)
+		if other, ok := seen[dest]; ok {
+			return nil, dserrors.ConfigError{
+				Field:      fmt.Sprintf("sync.%s", s.job.Name),
+				Value:      dest,
+				Message:    fmt.Sprintf("source keys %q and %q map to the same destination key", other, key),
+				Suggestion: "Adjust the map, rename or key_case rules so every key has a unique destination",
+			}
+		}
+		seen[dest] = key
+		destKeys[key] = dest
+	}
+
+	sourceEnv := make(config.Environment, len(keys))
+	destEnv := make(config.Environment, len(keys))
+	for _, key := range keys {
+		sourceEnv[key] = config.Variable{
+			From:      &config.Reference{Provider: s.job.From.Store, Key: s.job.From.Prefix + key},
+			Transform: s.job.Transform,
+		}
+		destEnv[destKeys[key]] = config.Variable{
+			From:     &config.Reference{Provider: s.job.To.Store, Key: destKeys[key]},
+			Optional: true,
+		}
+	}
+
+	sourceValues, err := s.resolver.ResolveVariablesConcurrently(ctx, sourceEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.job.From, err)
+	}
+	destValues, _ := s.resolver.ResolveVariablesConcurrently(ctx, destEnv)
+
+	for _, key := range keys {
+		src := sourceValues[key]
+		if err := s.enforcer.ValidateSecretValue(src.Value); err != nil {
+			return nil, fmt.Errorf("source secret %s: %w", key, err)
+		}
+
+		item := Item{
+			SourceKey:         key,
+			DestinationKey:    destKeys[key],
+			SourceVersion:     src.Version,
+			SourceFingerprint: Fingerprint(src.Value),
+			value:             src.Value,
+		}
+
+		dst := destValues[destKeys[key]]
+		switch {
+		case dst.Error != nil && provider.IsNotFound(dst.Error):
+			item.Action = ActionCreate
+		case dst.Error != nil:
+			return nil, fmt.Errorf("failed to read %s from %s: %w", destKeys[key], s.job.To.Store, dst.Error)
+		default:
+			item.DestVersion = dst.Version
+			item.DestFingerprint = Fingerprint(dst.Value)
+			item.Action = s.changeAction(item)
+		}
+
+		plan.Items = append(plan.Items, item)
+	}
+
+	return plan, nil
+}
+
+// changeAction decides the action for a key that exists on both sides
+func (s *Syncer) changeAction(item Item) Action {
+	if item.SourceFingerprint == item.DestFingerprint {
+		return ActionUnchanged
+	}
+	switch s.job.OnConflict {
+	case ConflictOverwrite:
+		return ActionUpdate
+	case ConflictSkip:
+		return ActionSkip
+	default:
+		return ActionConflict
+	}
+}
+
+// Apply writes the planned creates and updates. It refuses to write anything
+// while the plan has conflicts. On error, the returned Result counts what was
+// written before the failure; re-running the sync picks up the rest.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) (Result, error) {
+	var result Result
+
+	if n := plan.Count(ActionConflict); n > 0 {
+		return result, dserrors.UserError{
+			Message:    fmt.Sprintf("Sync '%s' has %d conflicting secrets", s.job.Name, n),
+			Details:    "The destination already holds different values",
+			Suggestion: "Set on_conflict to overwrite or skip, or pass --on-conflict",
+		}
+	}
+
+	for _, item := range plan.Items {
+		if item.Action != ActionCreate && item.Action != ActionUpdate {
+			continue
+		}
+
+		ref := provider.Reference{Provider: s.job.To.Store, Key: item.DestinationKey}
+		if _, err := s.writer.PutSecret(ctx, ref, []byte(item.value), provider.WriteOptions{Tags: s.job.Tags}); err != nil {
+			return result, fmt.Errorf("failed to write %s to %s: %w", item.DestinationKey, s.job.To.Store, err)
+		}
+
+		if item.Action == ActionCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// sourceKeys returns the keys to sync, relative to the source prefix
+func (s *Syncer) sourceKeys(ctx context.Context) ([]string, error) {
+	var candidates []string
+
+	if len(s.job.Keys) > 0 {
+		candidates = s.job.Keys
+	} else {
+		lister, ok := s.source.(provider.Lister)
+		if !ok {
+			return nil, dserrors.UserError{
+				Message:    fmt.Sprintf("Source store '%s' cannot list secrets", s.job.From.Store),
+				Suggestion: "List the keys to sync under 'keys:' in the sync job",
+			}
+		}
+
+		opts := provider.ListOptions{Prefix: s.job.From.Prefix}
+		for {
+			page, err := lister.ListSecrets(ctx, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", s.job.From, err)
+			}
+			for _, info := range page.Secrets {
+				// Folder entries from hierarchical stores are not secrets
+				if strings.HasSuffix(info.Key, "/") {
+					continue
+				}
+				candidates = append(candidates, strings.TrimPrefix(info.Key, s.job.From.Prefix))
+			}
+			if page.NextPageToken == "" || page.NextPageToken == opts.PageToken {
+				break
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	var keys []string
+	for _, key := range candidates {
+		if s.job.matches(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Fingerprint returns a short, non-reversible identifier for a secret value
+// that is safe to display
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,349 @@
+package secretsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/systmms/dsops/internal/config"
+	dserrors "github.com/systmms/dsops/internal/errors"
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/providers"
+	"github.com/systmms/dsops/internal/resolve"
+	"github.com/systmms/dsops/tests/fakes"
+	"github.com/systmms/dsops/tests/testutil"
+)
+
+type syncFixture struct {
+	source *fakes.FakeInfisicalClient
+	dest   *fakes.FakeSecretsManagerClient
+	ssm    *fakes.FakeSSMClient
+	azure  *fakes.FakeAzureKeyVaultClient
+	pass   *testutil.MockCommandExecutor
+	res    *resolve.Resolver
+}
+
+func newSyncFixture(t *testing.T) *syncFixture {
+	t.Helper()
+
+	cfg := &config.Config{
+		Logger: logging.New(false, true),
+		Definition: &config.Definition{
+			SecretStores: map[string]config.SecretStoreConfig{
+				"doppler": {Type: "infisical"},
+				"aws":     {Type: "aws.secretsmanager"},
+				"ssm":     {Type: "aws.ssm"},
+				"azure":   {Type: "azure.keyvault"},
+				"pass":    {Type: "pass"},
+			},
+		},
+	}
+
+	f := &syncFixture{
+		source: fakes.NewFakeInfisicalClient(),
+		dest:   fakes.NewFakeSecretsManagerClient(),
+		ssm:    fakes.NewFakeSSMClient(),
+		azure:  fakes.NewFakeAzureKeyVaultClient(),
+		pass:   testutil.NewMockCommandExecutor(),
+		res:    resolve.New(cfg),
+	}
+
+	dest, err := providers.NewAWSSecretsManagerProvider("aws", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSecretsManagerClient(f.dest))
+	require.NoError(t, err)
+
+	ssm, err := providers.NewAWSSSMProvider("ssm", map[string]interface{}{"region": "us-east-1"},
+		providers.WithSSMClient(f.ssm))
+	require.NoError(t, err)
+	azure, err := providers.NewAzureKeyVaultProvider("azure", map[string]interface{}{
+		"vault_url": "https://test-vault.vault.azure.net/",
+	}, providers.WithAzureKeyVaultClient(f.azure))
+	require.NoError(t, err)
+
+	f.res.RegisterProvider("doppler", providers.NewInfisicalProviderWithClient("doppler", nil, f.source))
+	f.res.RegisterProvider("aws", dest)
+	f.res.RegisterProvider("ssm", ssm)
+	f.res.RegisterProvider("azure", azure)
+	f.res.RegisterProvider("pass", providers.NewPassProviderWithExecutor(providers.PassConfig{}, f.pass))
+	return f
+}
+
+func (f *syncFixture) syncer(t *testing.T, sc config.SyncConfig) *Syncer {
+	t.Helper()
+
+	job, err := JobFromConfig("test", sc)
+	require.NoError(t, err)
+	s, err := New(f.res, job, nil)
+	require.NoError(t, err)
+	return s
+}
+
+func actions(plan *Plan) map[string]Action {
+	result := make(map[string]Action, len(plan.Items))
+	for _, item := range plan.Items {
+		result[item.DestinationKey] = item.Action
+	}
+	return result
+}
+
+func TestSyncPlanAndApply(t *testing.T) {
+	t.Parallel()
+
+	f := newSyncFixture(t)
+	f.source.SetSecret("DB_URL", "postgres://db")
+	f.source.SetSecret("API_KEY", "key-1")
+	f.source.SetSecret("DOPPLER_CONFIG", "dev")
+
+	s := f.syncer(t, config.SyncConfig{
+		From:    "doppler",
+		To:      "aws/myapp/",
+		Exclude: []string{"DOPPLER_*"},
+		KeyCase: CaseKebab,
+		Map:     map[string]string{"DB_URL": "database/url"},
+		Tags:    map[string]string{"migrated-from": "doppler"},
+	})
+	ctx := context.Background()
+
+	plan, err := s.Plan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Action{
+		"myapp/api-key":      ActionCreate,
+		"myapp/database/url": ActionCreate,
+	}, actions(plan))
+	assert.Equal(t, Fingerprint("key-1"), plan.Items[0].SourceFingerprint)
+	assert.Empty(t, f.dest.Secrets, "planning must not write")
+
+	result, err := s.Apply(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: 2}, result)
+	assert.Equal(t, "postgres://db", aws.ToString(f.dest.Secrets["myapp/database/url"].SecretString))
+	assert.Equal(t, "doppler", f.dest.Secrets["myapp/api-key"].Tags["migrated-from"])
+
+	// A second run is a no-op
+	plan, err = s.Plan(ctx)
+	require.NoError(t, err)
+	assert.False(t, plan.HasChanges())
+	assert.Equal(t, 2, plan.Count(ActionUnchanged))
+}
+
+func TestSyncToOtherDestinations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		store string
+		value func(f *syncFixture, key string) string
+	}{
+		{store: "ssm", value: func(f *syncFixture, key string) string {
+			if p, ok := f.ssm.Parameters[key]; ok {
+				return aws.ToString(p.Value)
+			}
+			return ""
+		}},
+		{store: "azure", value: func(f *syncFixture, key string) string {
+			if s, ok := f.azure.Secrets[key]; ok {
+				return aws.ToString(s.Value)
+			}
+			return ""
+		}},
+	} {
+		t.Run(tc.store, func(t *testing.T) {
+			t.Parallel()
+
+			f := newSyncFixture(t)
+			f.source.SetSecret("DB_URL", "postgres://db")
+			f.source.SetSecret("API_KEY", "key-1")
+
+			s := f.syncer(t, config.SyncConfig{From: "doppler", To: tc.store, KeyCase: CaseKebab})
+
+			// Missing destination secrets are planned as creates
+			plan, err := s.Plan(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]Action{
+				"api-key": ActionCreate,
+				"db-url":  ActionCreate,
+			}, actions(plan))
+
+			result, err := s.Apply(ctx, plan)
+			require.NoError(t, err)
+			assert.Equal(t, Result{Created: 2}, result)
+			assert.Equal(t, "postgres://db", tc.value(f, "db-url"))
+
+			plan, err = s.Plan(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 2, plan.Count(ActionUnchanged))
+		})
+	}
+}
+
+func TestSyncToPassPlansMissingKeysAsCreates(t *testing.T) {
+	t.Parallel()
+
+	f := newSyncFixture(t)
+	f.source.SetSecret("DB_URL", "postgres://db")
+	f.source.SetSecret("API_KEY", "key-1")
+	f.pass.AddJSONResponse("pass show db-url", "postgres://db\n")
+	f.pass.AddErrorResponse("pass show api-key", "Error: api-key is not in the password store.\n", 1)
+
+	s := f.syncer(t, config.SyncConfig{From: "doppler", To: "pass", KeyCase: CaseKebab})
+	ctx := context.Background()
+
+	plan, err := s.Plan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Action{
+		"api-key": ActionCreate,
+		"db-url":  ActionUnchanged,
+	}, actions(plan))
+
+	result, err := s.Apply(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: 1}, result)
+
+	inserts := f.pass.GetCalls("pass")
+	require.NotEmpty(t, inserts)
+	last := inserts[len(inserts)-1]
+	assert.Equal(t, []string{"insert", "--multiline", "--force", "api-key"}, last.Args)
+	assert.Equal(t, "key-1\n", string(last.Stdin))
+}
+
+func TestSyncConflictPolicies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		policy string
+		action Action
+		value  string
+	}{
+		{policy: "", action: ActionConflict, value: "old"},
+		{policy: "skip", action: ActionSkip, value: "old"},
+		{policy: "overwrite", action: ActionUpdate, value: "new"},
+	} {
+		t.Run("policy_"+tc.policy, func(t *testing.T) {
+			f := newSyncFixture(t)
+			f.source.SetSecret("TOKEN", "new")
+			f.dest.AddSecretString("TOKEN", "old")
+
+			s := f.syncer(t, config.SyncConfig{From: "doppler", To: "aws", OnConflict: tc.policy})
+			plan, err := s.Plan(ctx)
+			require.NoError(t, err)
+			require.Len(t, plan.Items, 1)
+			assert.Equal(t, tc.action, plan.Items[0].Action)
+			assert.Equal(t, Fingerprint("old"), plan.Items[0].DestFingerprint)
+
+			_, err = s.Apply(ctx, plan)
+			if tc.action == ActionConflict {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "conflicting")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.value, aws.ToString(f.dest.Secrets["TOKEN"].SecretString))
+		})
+	}
+}
+
+func TestSyncExplicitKeysAndTransform(t *testing.T) {
+	t.Parallel()
+
+	f := newSyncFixture(t)
+	f.source.SetSecret("CERT", "  cert-data  ")
+	f.source.SetSecret("IGNORED", "x")
+
+	s := f.syncer(t, config.SyncConfig{
+		From:      "doppler",
+		To:        "aws/certs/",
+		Keys:      []string{"CERT"},
+		Transform: "trim",
+		Rename:    []config.SyncRenameRule{{Match: "^CERT$", Replace: "tls-cert"}},
+	})
+
+	plan, err := s.Plan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, "certs/tls-cert", plan.Items[0].DestinationKey)
+
+	_, err = s.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, "cert-data", aws.ToString(f.dest.Secrets["certs/tls-cert"].SecretString))
+}
+
+func TestSyncRejectsKeyCollisions(t *testing.T) {
+	t.Parallel()
+
+	f := newSyncFixture(t)
+	f.source.SetSecret("API_KEY", "a")
+	f.source.SetSecret("api_key", "b")
+
+	s := f.syncer(t, config.SyncConfig{From: "doppler", To: "aws", KeyCase: CaseLower})
+	_, err := s.Plan(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "same destination key")
+}
+
+func TestNewRequiresWritableDestination(t *testing.T) {
+	t.Parallel()
+
+	f := newSyncFixture(t)
+	job, err := JobFromConfig("reverse", config.SyncConfig{From: "aws", To: "doppler"})
+	require.NoError(t, err)
+
+	_, err = New(f.res, job, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+
+	var userErr dserrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "Sync to a writable store: aws, azure, pass, ssm", userErr.Suggestion)
+}
+
+func TestJobFromConfig_Validation(t *testing.T) {
+	t.Parallel()
+
+	for name, sc := range map[string]config.SyncConfig{
+		"missing_from":     {To: "aws"},
+		"missing_to":       {From: "doppler"},
+		"same_endpoint":    {From: "aws/app/", To: "aws/app/"},
+		"bad_conflict":     {From: "doppler", To: "aws", OnConflict: "merge"},
+		"bad_key_case":     {From: "doppler", To: "aws", KeyCase: "camel"},
+		"bad_glob":         {From: "doppler", To: "aws", Include: []string{"["}},
+		"bad_rename_regex": {From: "doppler", To: "aws", Rename: []config.SyncRenameRule{{Match: "("}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := JobFromConfig("job", sc)
+			assert.Error(t, err)
+		})
+	}
+
+	job, err := JobFromConfig("job", config.SyncConfig{From: "doppler", To: "aws/app/"})
+	require.NoError(t, err)
+	assert.Equal(t, ConflictFail, job.OnConflict)
+	assert.Equal(t, Endpoint{Store: "aws", Prefix: "app/"}, job.To)
+}
+
+func TestDestinationKey(t *testing.T) {
+	t.Parallel()
+
+	job, err := JobFromConfig("job", config.SyncConfig{
+		From:    "vault/secret/data/app/",
+		To:      "gcp/dr-",
+		Map:     map[string]string{"special": "Exact_Name"},
+		Rename:  []config.SyncRenameRule{{Match: `^db/(.*)$`, Replace: "database_$1"}},
+		KeyCase: CaseKebab,
+		Include: []string{"db/*", "api*", "special"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "dr-database-password", job.destinationKey("db/password"))
+	assert.Equal(t, "dr-api-key", job.destinationKey("API_KEY"))
+	assert.Equal(t, "dr-Exact_Name", job.destinationKey("special"))
+
+	assert.True(t, job.matches("db/password"))
+	assert.False(t, job.matches("db/nested/password"), "* does not cross /")
+	assert.False(t, job.matches("other"))
+}
@@ -0,0 +1,251 @@
+package template
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sealing scopes, as understood by the sealed-secrets controller
+const (
+	SealedScopeStrict        = "strict"
+	SealedScopeNamespaceWide = "namespace-wide"
+	SealedScopeClusterWide   = "cluster-wide"
+)
+
+// KubernetesOptions configures the k8s-secret and sealed formats
+type KubernetesOptions struct {
+	Name        string            // Secret name (required)
+	Namespace   string            // Secret namespace; omitted from the manifest when empty
+	Type        string            // Secret type (default: Opaque)
+	Labels      map[string]string // Extra labels
+	Annotations map[string]string // Extra annotations
+	SealingCert []byte            // PEM certificate of the sealed-secrets controller (sealed format)
+	SealedScope string            // strict (default), namespace-wide or cluster-wide
+}
+
+var (
+	// DNS subdomain names, as required for Secret names
+	kubernetesNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	secretKeyPattern      = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+type kubernetesObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type kubernetesSecret struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Metadata   kubernetesObjectMeta `yaml:"metadata"`
+	Type       string               `yaml:"type"`
+	Data       map[string]string    `yaml:"data"`
+}
+
+type sealedSecret struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Metadata   kubernetesObjectMeta `yaml:"metadata"`
+	Spec       struct {
+		EncryptedData map[string]string `yaml:"encryptedData"`
+		Template      struct {
+			Metadata kubernetesObjectMeta `yaml:"metadata"`
+			Type     string               `yaml:"type"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// renderKubernetesSecret renders variables as a v1 Secret manifest
+func (r *Renderer) renderKubernetesSecret(variables map[string]string, opts KubernetesOptions) ([]byte, error) {
+	meta, err := kubernetesMetadata(variables, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := kubernetesSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   meta,
+		Type:       secretType(opts),
+		Data:       make(map[string]string, len(variables)),
+	}
+	for key, value := range variables {
+		secret.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	return marshalManifest(secret)
+}
+
+// renderSealedSecret renders variables as a Bitnami SealedSecret, encrypting
+// each value for the controller's certificate the same way kubeseal does
+func (r *Renderer) renderSealedSecret(variables map[string]string, opts KubernetesOptions) ([]byte, error) {
+	meta, err := kubernetesMetadata(variables, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.SealingCert) == 0 {
+		return nil, fmt.Errorf("sealed format needs the sealed-secrets controller certificate")
+	}
+	publicKey, err := parseSealingCert(opts.SealingCert)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := opts.SealedScope
+	if scope == "" {
+		scope = SealedScopeStrict
+	}
+	var label string
+	annotations := map[string]string{}
+	switch scope {
+	case SealedScopeStrict:
+		if meta.Namespace == "" {
+			return nil, fmt.Errorf("strict sealing needs a namespace")
+		}
+		label = meta.Namespace + "/" + meta.Name
+	case SealedScopeNamespaceWide:
+		if meta.Namespace == "" {
+			return nil, fmt.Errorf("namespace-wide sealing needs a namespace")
+		}
+		label = meta.Namespace
+		annotations["sealedsecrets.bitnami.com/namespace-wide"] = "true"
+	case SealedScopeClusterWide:
+		annotations["sealedsecrets.bitnami.com/cluster-wide"] = "true"
+	default:
+		return nil, fmt.Errorf("unknown sealing scope %q (use strict, namespace-wide or cluster-wide)", scope)
+	}
+
+	sealed := sealedSecret{
+		APIVersion: "bitnami.com/v1alpha1",
+		Kind:       "SealedSecret",
+		Metadata: kubernetesObjectMeta{
+			Name:        meta.Name,
+			Namespace:   meta.Namespace,
+			Annotations: annotations,
+		},
+	}
+	sealed.Spec.EncryptedData = make(map[string]string, len(variables))
+	for key, value := range variables {
+		ciphertext, err := hybridEncrypt(rand.Reader, publicKey, []byte(value), []byte(label))
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal %s: %w", key, err)
+		}
+		sealed.Spec.EncryptedData[key] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+	sealed.Spec.Template.Metadata = meta
+	sealed.Spec.Template.Type = secretType(opts)
+
+	return marshalManifest(sealed)
+}
+
+// kubernetesMetadata validates the Secret name and keys and builds its metadata
+func kubernetesMetadata(variables map[string]string, opts KubernetesOptions) (kubernetesObjectMeta, error) {
+	if opts.Name == "" {
+		return kubernetesObjectMeta{}, fmt.Errorf("a Secret name is required")
+	}
+	if len(opts.Name) > 253 || !kubernetesNamePattern.MatchString(opts.Name) {
+		return kubernetesObjectMeta{}, fmt.Errorf("invalid Secret name %q: use lowercase letters, digits, '-' and '.'", opts.Name)
+	}
+	if opts.Namespace != "" && (len(opts.Namespace) > 63 || !kubernetesNamePattern.MatchString(opts.Namespace) || strings.Contains(opts.Namespace, ".")) {
+		return kubernetesObjectMeta{}, fmt.Errorf("invalid namespace %q", opts.Namespace)
+	}
+	for key := range variables {
+		if !secretKeyPattern.MatchString(key) {
+			return kubernetesObjectMeta{}, fmt.Errorf("invalid Secret key %q: use letters, digits, '-', '_' and '.'", key)
+		}
+	}
+
+	labels := map[string]string{"app.kubernetes.io/managed-by": "dsops"}
+	for k, v := range opts.Labels {
+		labels[k] = v
+	}
+	return kubernetesObjectMeta{
+		Name:        opts.Name,
+		Namespace:   opts.Namespace,
+		Labels:      labels,
+		Annotations: opts.Annotations,
+	}, nil
+}
+
+func secretType(opts KubernetesOptions) string {
+	if opts.Type == "" {
+		return "Opaque"
+	}
+	return opts.Type
+}
+
+func marshalManifest(v interface{}) ([]byte, error) {
+	var buf strings.Builder
+	buf.WriteString("# Generated by dsops\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
+
+// parseSealingCert returns the RSA public key of a sealed-secrets certificate
+func parseSealingCert(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("sealing certificate is not a PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealing certificate: %w", err)
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("sealing certificate does not hold an RSA key")
+	}
+	return publicKey, nil
+}
+
+// hybridEncrypt implements the sealed-secrets encryption scheme: a random
+// AES-256-GCM session key encrypted with RSA-OAEP (SHA-256, with the scope
+// label), prefixed by its two-byte length, followed by the GCM ciphertext
+// sealed with a zero nonce (safe because each session key is used once).
+func hybridEncrypt(rnd io.Reader, publicKey *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := io.ReadFull(rnd, sessionKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaCiphertext, err := rsa.EncryptOAEP(sha256.New(), rnd, publicKey, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, 2, 2+len(rsaCiphertext)+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint16(ciphertext, uint16(len(rsaCiphertext)))
+	ciphertext = append(ciphertext, rsaCiphertext...)
+	zeroNonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ciphertext, zeroNonce, plaintext, nil), nil
+}
@@ -0,0 +1,179 @@
+package template
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/tests/testutil"
+	"gopkg.in/yaml.v3"
+)
+
+// testSealingKey returns a throwaway controller key and its PEM certificate
+func testSealingKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	cert := testutil.NewCertificate(t, testutil.CertificateOptions{CommonName: "sealed-secret", Key: key})
+	return key, cert.CertPEM()
+}
+
+// hybridDecrypt is what the sealed-secrets controller does to unseal a value
+func hybridDecrypt(t *testing.T, key *rsa.PrivateKey, ciphertext, label []byte) string {
+	t.Helper()
+	require.Greater(t, len(ciphertext), 2)
+	rsaLen := int(binary.BigEndian.Uint16(ciphertext))
+	require.Greater(t, len(ciphertext), 2+rsaLen)
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext[2:2+rsaLen], label)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(sessionKey)
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[2+rsaLen:], nil)
+	require.NoError(t, err)
+	return string(plaintext)
+}
+
+func TestRenderer_renderKubernetesSecret(t *testing.T) {
+	t.Parallel()
+	renderer := createTestRenderer()
+
+	out, err := renderer.RenderContent(RenderOptions{
+		Format:    "k8s-secret",
+		Variables: map[string]string{"DATABASE_URL": "postgres://db", "API_KEY": "s3cr3t"},
+		Kubernetes: KubernetesOptions{
+			Name:      "app-secrets",
+			Namespace: "prod",
+			Labels:    map[string]string{"team": "payments"},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "# Generated by dsops\n"))
+
+	var manifest kubernetesSecret
+	require.NoError(t, yaml.Unmarshal(out, &manifest))
+	assert.Equal(t, "v1", manifest.APIVersion)
+	assert.Equal(t, "Secret", manifest.Kind)
+	assert.Equal(t, "Opaque", manifest.Type)
+	assert.Equal(t, "app-secrets", manifest.Metadata.Name)
+	assert.Equal(t, "prod", manifest.Metadata.Namespace)
+	assert.Equal(t, "dsops", manifest.Metadata.Labels["app.kubernetes.io/managed-by"])
+	assert.Equal(t, "payments", manifest.Metadata.Labels["team"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("postgres://db")), manifest.Data["DATABASE_URL"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("s3cr3t")), manifest.Data["API_KEY"])
+
+	out, err = renderer.RenderContent(RenderOptions{
+		Format:     "k8s-secret",
+		Variables:  map[string]string{"tls.crt": "cert"},
+		Kubernetes: KubernetesOptions{Name: "tls", Type: "kubernetes.io/tls"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "type: kubernetes.io/tls")
+	assert.NotContains(t, string(out), "namespace:")
+}
+
+func TestRenderer_renderKubernetesSecret_Validation(t *testing.T) {
+	t.Parallel()
+	renderer := createTestRenderer()
+
+	tests := []struct {
+		name      string
+		variables map[string]string
+		opts      KubernetesOptions
+		errMsg    string
+	}{
+		{"missing_name", map[string]string{"A": "b"}, KubernetesOptions{}, "Secret name is required"},
+		{"uppercase_name", map[string]string{"A": "b"}, KubernetesOptions{Name: "App"}, "invalid Secret name"},
+		{"bad_namespace", map[string]string{"A": "b"}, KubernetesOptions{Name: "app", Namespace: "a.b"}, "invalid namespace"},
+		{"bad_key", map[string]string{"MY KEY": "b"}, KubernetesOptions{Name: "app"}, "invalid Secret key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := renderer.RenderContent(RenderOptions{Format: "k8s-secret", Variables: tt.variables, Kubernetes: tt.opts})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestRenderer_renderSealedSecret(t *testing.T) {
+	t.Parallel()
+	renderer := createTestRenderer()
+	key, cert := testSealingKey(t)
+	variables := map[string]string{"DATABASE_URL": "postgres://db", "API_KEY": "s3cr3t"}
+
+	tests := []struct {
+		scope      string
+		label      string
+		annotation string
+	}{
+		{"", "prod/app-secrets", ""},
+		{SealedScopeNamespaceWide, "prod", "sealedsecrets.bitnami.com/namespace-wide"},
+		{SealedScopeClusterWide, "", "sealedsecrets.bitnami.com/cluster-wide"},
+	}
+	for _, tt := range tests {
+		t.Run("scope_"+tt.scope, func(t *testing.T) {
+			out, err := renderer.RenderContent(RenderOptions{
+				Format:    "sealed",
+				Variables: variables,
+				Kubernetes: KubernetesOptions{
+					Name:        "app-secrets",
+					Namespace:   "prod",
+					SealingCert: cert,
+					SealedScope: tt.scope,
+				},
+			})
+			require.NoError(t, err)
+			assert.NotContains(t, string(out), "s3cr3t")
+
+			var manifest sealedSecret
+			require.NoError(t, yaml.Unmarshal(out, &manifest))
+			assert.Equal(t, "bitnami.com/v1alpha1", manifest.APIVersion)
+			assert.Equal(t, "SealedSecret", manifest.Kind)
+			assert.Equal(t, "app-secrets", manifest.Spec.Template.Metadata.Name)
+			assert.Equal(t, "Opaque", manifest.Spec.Template.Type)
+			if tt.annotation != "" {
+				assert.Equal(t, "true", manifest.Metadata.Annotations[tt.annotation])
+			}
+
+			for name, value := range variables {
+				ciphertext, err := base64.StdEncoding.DecodeString(manifest.Spec.EncryptedData[name])
+				require.NoError(t, err)
+				assert.Equal(t, value, hybridDecrypt(t, key, ciphertext, []byte(tt.label)))
+			}
+		})
+	}
+}
+
+func TestRenderer_renderSealedSecret_Errors(t *testing.T) {
+	t.Parallel()
+	renderer := createTestRenderer()
+	_, cert := testSealingKey(t)
+	variables := map[string]string{"A": "b"}
+
+	_, err := renderer.RenderContent(RenderOptions{Format: "sealed", Variables: variables,
+		Kubernetes: KubernetesOptions{Name: "app", Namespace: "prod"}})
+	assert.ErrorContains(t, err, "certificate")
+
+	_, err = renderer.RenderContent(RenderOptions{Format: "sealed", Variables: variables,
+		Kubernetes: KubernetesOptions{Name: "app", SealingCert: cert}})
+	assert.ErrorContains(t, err, "strict sealing needs a namespace")
+
+	_, err = renderer.RenderContent(RenderOptions{Format: "sealed", Variables: variables,
+		Kubernetes: KubernetesOptions{Name: "app", Namespace: "prod", SealingCert: cert, SealedScope: "global"}})
+	assert.ErrorContains(t, err, "unknown sealing scope")
+
+	_, err = renderer.RenderContent(RenderOptions{Format: "sealed", Variables: variables,
+		Kubernetes: KubernetesOptions{Name: "app", Namespace: "prod", SealingCert: []byte("not a cert")}})
+	assert.ErrorContains(t, err, "not a PEM certificate")
+}
@@ -29,12 +29,13 @@ func New(logger *logging.Logger) *Renderer {
 
 // RenderOptions configures template rendering
 type RenderOptions struct {
-	Format      string            // dotenv, json, yaml, template
+	Format      string            // dotenv, json, yaml, template, k8s-secret, sealed
 	Variables   map[string]string // Variables to render
 	OutputPath  string            // File path to write to
 	Template    string            // Template content (for template format)
 	TTL         time.Duration     // Auto-delete after this duration
 	Permissions os.FileMode       // File permissions (default 0600)
+	Kubernetes  KubernetesOptions // Secret manifest settings (k8s-secret and sealed formats)
 }
 
 // Render renders variables to the specified format and output
@@ -50,9 +51,9 @@ func (r *Renderer) Render(options RenderOptions) error {
 	}
 
 	// Render content
-	content, err := r.renderContent(options)
+	content, err := r.RenderContent(options)
 	if err != nil {
-		return fmt.Errorf("failed to render content: %w", err)
+		return err
 	}
 
 	// Write to file
@@ -72,6 +73,19 @@ func (r *Renderer) Render(options RenderOptions) error {
 	return nil
 }
 
+// RenderContent renders variables without writing them to a file, for
+// output that is sent somewhere else (e.g. applied to a cluster)
+func (r *Renderer) RenderContent(options RenderOptions) ([]byte, error) {
+	if options.Format == "" {
+		options.Format = r.detectFormat(options.OutputPath)
+	}
+	content, err := r.renderContent(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render content: %w", err)
+	}
+	return content, nil
+}
+
 // renderContent renders variables according to the specified format
 func (r *Renderer) renderContent(options RenderOptions) ([]byte, error) {
 	switch options.Format {
@@ -83,6 +97,10 @@ func (r *Renderer) renderContent(options RenderOptions) ([]byte, error) {
 		return r.renderYAML(options.Variables)
 	case "template":
 		return r.renderTemplate(options.Template, options.Variables)
+	case "k8s-secret":
+		return r.renderKubernetesSecret(options.Variables, options.Kubernetes)
+	case "sealed":
+		return r.renderSealedSecret(options.Variables, options.Kubernetes)
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", options.Format)
 	}
@@ -37,13 +37,7 @@ func (a *ProviderToSecretStoreAdapter) Name() string {
 
 func (a *ProviderToSecretStoreAdapter) Resolve(ctx context.Context, ref secretstore.SecretRef) (secretstore.SecretValue, error) {
 	// Convert SecretRef to legacy Reference
-	legacyRef := provider.Reference{
-		Provider: ref.Store,
-		Key:      ref.Path,
-		Field:    ref.Field,
-		Version:  ref.Version,
-		Path:     ref.Path,
-	}
+	legacyRef := ConvertSecretRefToProviderRef(ref)
 
 	// Call legacy provider
 	value, err := a.provider.Resolve(ctx, legacyRef)
@@ -62,13 +56,7 @@ func (a *ProviderToSecretStoreAdapter) Resolve(ctx context.Context, ref secretst
 
 func (a *ProviderToSecretStoreAdapter) Describe(ctx context.Context, ref secretstore.SecretRef) (secretstore.SecretMetadata, error) {
 	// Convert SecretRef to legacy Reference
-	legacyRef := provider.Reference{
-		Provider: ref.Store,
-		Key:      ref.Path,
-		Field:    ref.Field,
-		Version:  ref.Version,
-		Path:     ref.Path,
-	}
+	legacyRef := ConvertSecretRefToProviderRef(ref)
 
 	// Call legacy provider
 	metadata, err := a.provider.Describe(ctx, legacyRef)
@@ -85,6 +73,7 @@ func (a *ProviderToSecretStoreAdapter) Describe(ctx context.Context, ref secrets
 		Type:        metadata.Type,
 		Permissions: metadata.Permissions,
 		Tags:        metadata.Tags,
+		Deprecated:  metadata.Deprecated,
 	}, nil
 }
 
@@ -117,6 +106,125 @@ func (a *ProviderToSecretStoreAdapter) Validate(ctx context.Context) error {
 	return a.provider.Validate(ctx)
 }
 
+// PutSecret forwards to the wrapped provider when it implements provider.Writer
+func (a *ProviderToSecretStoreAdapter) PutSecret(ctx context.Context, ref secretstore.SecretRef, value []byte, opts secretstore.WriteOptions) (string, error) {
+	writer, ok := a.provider.(provider.Writer)
+	if !ok {
+		return "", secretstore.ValidationError{
+			Store:   a.provider.Name(),
+			Message: "store does not support writing secrets",
+		}
+	}
+
+	return writer.PutSecret(ctx, ConvertSecretRefToProviderRef(ref), value, provider.WriteOptions{
+		Description: opts.Description,
+		Tags:        opts.Tags,
+		Metadata:    opts.Metadata,
+	})
+}
+
+// DeleteSecret forwards to the wrapped provider when it implements provider.Writer
+func (a *ProviderToSecretStoreAdapter) DeleteSecret(ctx context.Context, ref secretstore.SecretRef, opts secretstore.DeleteOptions) error {
+	writer, ok := a.provider.(provider.Writer)
+	if !ok {
+		return secretstore.ValidationError{
+			Store:   a.provider.Name(),
+			Message: "store does not support deleting secrets",
+		}
+	}
+
+	return writer.DeleteSecret(ctx, ConvertSecretRefToProviderRef(ref), provider.DeleteOptions{Force: opts.Force})
+}
+
+// ListSecrets forwards to the wrapped provider when it implements provider.Lister
+func (a *ProviderToSecretStoreAdapter) ListSecrets(ctx context.Context, opts secretstore.ListOptions) (secretstore.ListResult, error) {
+	lister, ok := a.provider.(provider.Lister)
+	if !ok {
+		return secretstore.ListResult{}, secretstore.ValidationError{
+			Store:   a.provider.Name(),
+			Message: "store does not support listing secrets",
+		}
+	}
+
+	result, err := lister.ListSecrets(ctx, provider.ListOptions{
+		Prefix:    opts.Prefix,
+		PageSize:  opts.PageSize,
+		PageToken: opts.PageToken,
+	})
+	if err != nil {
+		return secretstore.ListResult{}, err
+	}
+
+	secrets := make([]secretstore.SecretInfo, len(result.Secrets))
+	for i, s := range result.Secrets {
+		secrets[i] = secretstore.SecretInfo{
+			Path:        s.Key,
+			Description: s.Description,
+			Type:        s.Type,
+			Version:     s.Version,
+			UpdatedAt:   s.UpdatedAt,
+			Tags:        s.Tags,
+		}
+	}
+	return secretstore.ListResult{Secrets: secrets, NextPageToken: result.NextPageToken}, nil
+}
+
+// Leases forwards to the wrapped provider when it implements provider.LeaseManager
+func (a *ProviderToSecretStoreAdapter) Leases() []secretstore.Lease {
+	manager, ok := a.provider.(provider.LeaseManager)
+	if !ok {
+		return nil
+	}
+
+	leases := manager.Leases()
+	result := make([]secretstore.Lease, len(leases))
+	for i, l := range leases {
+		result[i] = convertProviderLease(l)
+	}
+	return result
+}
+
+// RenewLease forwards to the wrapped provider when it implements provider.LeaseManager
+func (a *ProviderToSecretStoreAdapter) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (secretstore.Lease, error) {
+	manager, ok := a.provider.(provider.LeaseManager)
+	if !ok {
+		return secretstore.Lease{}, secretstore.ValidationError{
+			Store:   a.provider.Name(),
+			Message: "store does not issue leases",
+		}
+	}
+
+	lease, err := manager.RenewLease(ctx, leaseID, increment)
+	if err != nil {
+		return secretstore.Lease{}, err
+	}
+	return convertProviderLease(lease), nil
+}
+
+// RevokeLease forwards to the wrapped provider when it implements provider.LeaseManager
+func (a *ProviderToSecretStoreAdapter) RevokeLease(ctx context.Context, leaseID string) error {
+	manager, ok := a.provider.(provider.LeaseManager)
+	if !ok {
+		return secretstore.ValidationError{
+			Store:   a.provider.Name(),
+			Message: "store does not issue leases",
+		}
+	}
+
+	return manager.RevokeLease(ctx, leaseID)
+}
+
+func convertProviderLease(l provider.Lease) secretstore.Lease {
+	return secretstore.Lease{
+		ID:        l.ID,
+		Store:     l.Provider,
+		Path:      l.Path,
+		Duration:  l.Duration,
+		Renewable: l.Renewable,
+		IssuedAt:  l.IssuedAt,
+	}
+}
+
 // ProviderToServiceAdapter wraps a legacy Provider to implement Service interface
 // This is for providers that support rotation (implement Rotator interface)
 type ProviderToServiceAdapter struct {
@@ -316,7 +424,7 @@ func ConvertProviderRefToSecretRef(ref provider.Reference) secretstore.SecretRef
 // ConvertSecretRefToProviderRef converts new SecretRef to legacy provider Reference
 func ConvertSecretRefToProviderRef(ref secretstore.SecretRef) provider.Reference {
 	key := ref.Path
-	if legacyKey, ok := ref.Options["key"]; ok {
+	if legacyKey := ref.Options["key"]; legacyKey != "" {
 		key = legacyKey
 	}
 
@@ -434,6 +542,7 @@ func (a *SecretStoreToProviderAdapter) Describe(ctx context.Context, ref provide
 		Type:        metadata.Type,
 		Permissions: metadata.Permissions,
 		Tags:        metadata.Tags,
+		Deprecated:  metadata.Deprecated,
 	}, nil
 }
 
@@ -453,3 +562,107 @@ func (a *SecretStoreToProviderAdapter) Capabilities() provider.Capabilities {
 func (a *SecretStoreToProviderAdapter) Validate(ctx context.Context) error {
 	return a.secretStore.Validate(ctx)
 }
+
+// PutSecret forwards to the wrapped secret store when it implements secretstore.Writer
+func (a *SecretStoreToProviderAdapter) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	writer, ok := a.secretStore.(secretstore.Writer)
+	if !ok {
+		return "", fmt.Errorf("secret store %s does not support writing secrets", a.secretStore.Name())
+	}
+
+	return writer.PutSecret(ctx, ConvertProviderRefToSecretRef(ref), value, secretstore.WriteOptions{
+		Description: opts.Description,
+		Tags:        opts.Tags,
+		Metadata:    opts.Metadata,
+	})
+}
+
+// DeleteSecret forwards to the wrapped secret store when it implements secretstore.Writer
+func (a *SecretStoreToProviderAdapter) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	writer, ok := a.secretStore.(secretstore.Writer)
+	if !ok {
+		return fmt.Errorf("secret store %s does not support deleting secrets", a.secretStore.Name())
+	}
+
+	return writer.DeleteSecret(ctx, ConvertProviderRefToSecretRef(ref), secretstore.DeleteOptions{Force: opts.Force})
+}
+
+// ListSecrets forwards to the wrapped secret store when it implements secretstore.Lister
+func (a *SecretStoreToProviderAdapter) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	lister, ok := a.secretStore.(secretstore.Lister)
+	if !ok {
+		return provider.ListResult{}, fmt.Errorf("secret store %s does not support listing secrets", a.secretStore.Name())
+	}
+
+	result, err := lister.ListSecrets(ctx, secretstore.ListOptions{
+		Prefix:    opts.Prefix,
+		PageSize:  opts.PageSize,
+		PageToken: opts.PageToken,
+	})
+	if err != nil {
+		return provider.ListResult{}, err
+	}
+
+	secrets := make([]provider.SecretInfo, len(result.Secrets))
+	for i, s := range result.Secrets {
+		secrets[i] = provider.SecretInfo{
+			Key:         s.Path,
+			Description: s.Description,
+			Type:        s.Type,
+			Version:     s.Version,
+			UpdatedAt:   s.UpdatedAt,
+			Tags:        s.Tags,
+		}
+	}
+	return provider.ListResult{Secrets: secrets, NextPageToken: result.NextPageToken}, nil
+}
+
+// Leases forwards to the wrapped secret store when it implements secretstore.LeaseManager
+func (a *SecretStoreToProviderAdapter) Leases() []provider.Lease {
+	manager, ok := a.secretStore.(secretstore.LeaseManager)
+	if !ok {
+		return nil
+	}
+
+	leases := manager.Leases()
+	result := make([]provider.Lease, len(leases))
+	for i, l := range leases {
+		result[i] = convertSecretStoreLease(l)
+	}
+	return result
+}
+
+// RenewLease forwards to the wrapped secret store when it implements secretstore.LeaseManager
+func (a *SecretStoreToProviderAdapter) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (provider.Lease, error) {
+	manager, ok := a.secretStore.(secretstore.LeaseManager)
+	if !ok {
+		return provider.Lease{}, fmt.Errorf("secret store %s does not issue leases", a.secretStore.Name())
+	}
+
+	lease, err := manager.RenewLease(ctx, leaseID, increment)
+	if err != nil {
+		return provider.Lease{}, err
+	}
+	return convertSecretStoreLease(lease), nil
+}
+
+// RevokeLease forwards to the wrapped secret store when it implements secretstore.LeaseManager
+func (a *SecretStoreToProviderAdapter) RevokeLease(ctx context.Context, leaseID string) error {
+	manager, ok := a.secretStore.(secretstore.LeaseManager)
+	if !ok {
+		return fmt.Errorf("secret store %s does not issue leases", a.secretStore.Name())
+	}
+
+	return manager.RevokeLease(ctx, leaseID)
+}
+
+func convertSecretStoreLease(l secretstore.Lease) provider.Lease {
+	return provider.Lease{
+		ID:        l.ID,
+		Provider:  l.Store,
+		Path:      l.Path,
+		Duration:  l.Duration,
+		Renewable: l.Renewable,
+		IssuedAt:  l.IssuedAt,
+	}
+}
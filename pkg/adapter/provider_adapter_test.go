@@ -84,6 +84,71 @@ func (m *mockRotator) GetRotationMetadata(ctx context.Context, ref provider.Refe
 	}, nil
 }
 
+// Mock provider with write support
+type mockWriter struct {
+	mockProvider
+	written map[string]string
+	opts    provider.WriteOptions
+	deleted map[string]bool
+}
+
+func (m *mockWriter) PutSecret(ctx context.Context, ref provider.Reference, value []byte, opts provider.WriteOptions) (string, error) {
+	m.written[ref.Key] = string(value)
+	m.opts = opts
+	return "v3", nil
+}
+
+func (m *mockWriter) DeleteSecret(ctx context.Context, ref provider.Reference, opts provider.DeleteOptions) error {
+	m.deleted[ref.Key] = opts.Force
+	return nil
+}
+
+type mockLister struct {
+	mockProvider
+	opts provider.ListOptions
+}
+
+func (m *mockLister) ListSecrets(ctx context.Context, opts provider.ListOptions) (provider.ListResult, error) {
+	m.opts = opts
+	return provider.ListResult{
+		Secrets:       []provider.SecretInfo{{Key: "app/db", Version: "3", Tags: map[string]string{"team": "platform"}}},
+		NextPageToken: "next",
+	}, nil
+}
+
+type recordingProvider struct {
+	mockProvider
+	refs []provider.Reference
+}
+
+func (m *recordingProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	m.refs = append(m.refs, ref)
+	return provider.SecretValue{Value: "v"}, nil
+}
+
+func (m *recordingProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	m.refs = append(m.refs, ref)
+	return provider.Metadata{Exists: true, Version: ref.Version, Deprecated: true}, nil
+}
+
+// leasingProvider implements provider.LeaseManager
+type leasingProvider struct {
+	mockProvider
+	leases  []provider.Lease
+	revoked []string
+}
+
+func (m *leasingProvider) Leases() []provider.Lease { return m.leases }
+
+func (m *leasingProvider) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (provider.Lease, error) {
+	return provider.Lease{ID: leaseID, Provider: m.name, Duration: increment, Renewable: true}, nil
+}
+
+func (m *leasingProvider) RevokeLease(ctx context.Context, leaseID string) error {
+	m.revoked = append(m.revoked, leaseID)
+	return nil
+}
+
 // Mock secret store for testing
 type mockSecretStore struct {
 	name string
@@ -207,6 +272,134 @@ func TestProviderToSecretStoreAdapterWithRotation(t *testing.T) {
 	})
 }
 
+func TestAdapterWriteForwarding(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RoundTripsThroughBothAdapters", func(t *testing.T) {
+		writer := &mockWriter{
+			mockProvider: mockProvider{name: "test-writer"},
+			written:      make(map[string]string),
+			deleted:      make(map[string]bool),
+		}
+		wrapped := NewSecretStoreToProviderAdapter(NewProviderToSecretStoreAdapter(writer))
+
+		var p provider.Provider = wrapped
+		w, ok := p.(provider.Writer)
+		require.True(t, ok)
+
+		ref := provider.Reference{Provider: "test-writer", Key: "app/db#password"}
+		version, err := w.PutSecret(ctx, ref, []byte("s3cr3t"), provider.WriteOptions{
+			Description: "Database password",
+			Tags:        map[string]string{"team": "platform"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "v3", version)
+		assert.Equal(t, "s3cr3t", writer.written["app/db#password"])
+		assert.Equal(t, "Database password", writer.opts.Description)
+		assert.Equal(t, "platform", writer.opts.Tags["team"])
+
+		err = w.DeleteSecret(ctx, ref, provider.DeleteOptions{Force: true})
+		require.NoError(t, err)
+		assert.True(t, writer.deleted["app/db#password"])
+	})
+
+	t.Run("ReadOnlyProviderReturnsError", func(t *testing.T) {
+		store := NewProviderToSecretStoreAdapter(&mockProvider{name: "read-only"})
+
+		_, err := store.PutSecret(ctx, secretstore.SecretRef{Path: "key"}, []byte("value"), secretstore.WriteOptions{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support writing")
+
+		err = store.DeleteSecret(ctx, secretstore.SecretRef{Path: "key"}, secretstore.DeleteOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("ReadOnlySecretStoreReturnsError", func(t *testing.T) {
+		p := NewSecretStoreToProviderAdapter(&mockSecretStore{name: "read-only"})
+
+		_, err := p.PutSecret(ctx, provider.Reference{Key: "key"}, []byte("value"), provider.WriteOptions{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support writing")
+	})
+}
+
+func TestAdapterListForwarding(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RoundTripsThroughBothAdapters", func(t *testing.T) {
+		lister := &mockLister{mockProvider: mockProvider{name: "test-lister"}}
+		var p provider.Provider = NewSecretStoreToProviderAdapter(NewProviderToSecretStoreAdapter(lister))
+
+		l, ok := p.(provider.Lister)
+		require.True(t, ok)
+
+		result, err := l.ListSecrets(ctx, provider.ListOptions{Prefix: "app/", PageSize: 10, PageToken: "tok"})
+		require.NoError(t, err)
+		assert.Equal(t, provider.ListOptions{Prefix: "app/", PageSize: 10, PageToken: "tok"}, lister.opts)
+		require.Len(t, result.Secrets, 1)
+		assert.Equal(t, "app/db", result.Secrets[0].Key)
+		assert.Equal(t, "3", result.Secrets[0].Version)
+		assert.Equal(t, "platform", result.Secrets[0].Tags["team"])
+		assert.Equal(t, "next", result.NextPageToken)
+	})
+
+	t.Run("UnsupportedReturnsError", func(t *testing.T) {
+		store := NewProviderToSecretStoreAdapter(&mockProvider{name: "no-list"})
+		_, err := store.ListSecrets(ctx, secretstore.ListOptions{})
+		assert.ErrorContains(t, err, "does not support listing")
+
+		p := NewSecretStoreToProviderAdapter(&mockSecretStore{name: "no-list"})
+		_, err = p.ListSecrets(ctx, provider.ListOptions{})
+		assert.ErrorContains(t, err, "does not support listing")
+	})
+}
+
+func TestAdapterRoundTripKeepsKey(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingProvider{mockProvider: mockProvider{name: "rec"}}
+	p := NewSecretStoreToProviderAdapter(NewProviderToSecretStoreAdapter(rec))
+
+	ref := provider.Reference{Provider: "rec", Key: "app/db#.password", Version: "v3"}
+	_, err := p.Resolve(ctx, ref)
+	require.NoError(t, err)
+	meta, err := p.Describe(ctx, ref)
+	require.NoError(t, err)
+
+	require.Len(t, rec.refs, 2)
+	for _, got := range rec.refs {
+		assert.Equal(t, "app/db#.password", got.Key)
+		assert.Equal(t, "v3", got.Version)
+	}
+	assert.True(t, meta.Deprecated)
+}
+
+func TestAdapterRoundTripForwardsLeases(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	issued := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	leaser := &leasingProvider{
+		mockProvider: mockProvider{name: "vault"},
+		leases:       []provider.Lease{{ID: "database/creds/app/abc", Provider: "vault", Path: "database/creds/app", Duration: time.Hour, Renewable: true, IssuedAt: issued}},
+	}
+	var p provider.Provider = NewSecretStoreToProviderAdapter(NewProviderToSecretStoreAdapter(leaser))
+
+	manager, ok := p.(provider.LeaseManager)
+	require.True(t, ok)
+	assert.Equal(t, leaser.leases, manager.Leases())
+
+	renewed, err := manager.RenewLease(ctx, "database/creds/app/abc", 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, renewed.Duration)
+
+	require.NoError(t, manager.RevokeLease(ctx, "database/creds/app/abc"))
+	assert.Equal(t, []string{"database/creds/app/abc"}, leaser.revoked)
+
+	plain := NewSecretStoreToProviderAdapter(NewProviderToSecretStoreAdapter(&mockProvider{name: "plain"}))
+	assert.Empty(t, plain.Leases())
+	assert.Error(t, plain.RevokeLease(ctx, "x"))
+}
+
 func TestSecretStoreToProviderAdapter(t *testing.T) {
 	ctx := context.Background()
 	mockStore := &mockSecretStore{name: "test-store"}
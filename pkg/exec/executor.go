@@ -35,3 +35,23 @@ func (r *RealCommandExecutor) Execute(ctx context.Context, name string, args ...
 func DefaultExecutor() CommandExecutor {
 	return &RealCommandExecutor{}
 }
+
+// InputExecutor is implemented by executors that can feed data to a command's stdin.
+// Use it for CLI tools that read secret values from stdin so the values never
+// appear in process arguments.
+type InputExecutor interface {
+	// ExecuteWithInput runs a command with input written to its stdin.
+	// Returns stdout, stderr, and any error that occurred.
+	ExecuteWithInput(ctx context.Context, input []byte, name string, args ...string) (stdout []byte, stderr []byte, err error)
+}
+
+// ExecuteWithInput runs an actual shell command with input on stdin.
+func (r *RealCommandExecutor) ExecuteWithInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
@@ -114,3 +114,12 @@ func TestRealCommandExecutor_StderrCapture(t *testing.T) {
 	assert.Equal(t, "stdout\n", string(stdout))
 	assert.Equal(t, "stderr\n", string(stderr))
 }
+
+func TestRealCommandExecutor_ExecuteWithInput(t *testing.T) {
+	t.Parallel()
+
+	var executor InputExecutor = &RealCommandExecutor{}
+	stdout, _, err := executor.ExecuteWithInput(context.Background(), []byte("from stdin"), "cat")
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin", string(stdout))
+}
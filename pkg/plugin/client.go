@@ -0,0 +1,244 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/systmms/dsops/pkg/plugin/proto"
+	"github.com/systmms/dsops/pkg/provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Client is the dsops side of a plugin connection: a provider.Provider and
+// provider.Rotator that forwards every call over gRPC.
+//
+// Client always implements provider.Rotator; for plugins without rotation
+// support GetRotationMetadata reports SupportsRotation false and the other
+// rotation calls fail.
+type Client struct {
+	client  pb.ProviderClient
+	timeout time.Duration
+
+	name             string
+	capabilities     provider.Capabilities
+	supportsRotation bool
+}
+
+// NewClient wraps a connection to a plugin. Every call is bounded by timeout
+// in addition to its context; zero means no extra bound. Call Configure
+// before using the client.
+func NewClient(conn grpc.ClientConnInterface, timeout time.Duration) *Client {
+	return &Client{client: pb.NewProviderClient(conn), timeout: timeout}
+}
+
+// Configure creates the plugin's provider from the store configuration
+func (c *Client) Configure(ctx context.Context, name string, config map[string]interface{}) error {
+	cfg, err := structpb.NewStruct(config)
+	if err != nil {
+		return fmt.Errorf("store configuration cannot be sent to the plugin: %w", err)
+	}
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.client.Configure(ctx, &pb.ConfigureRequest{Name: name, Config: cfg})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return fmt.Errorf("invalid plugin configuration: %s", status.Convert(err).Message())
+		}
+		return c.fromStatus(ctx, err, "")
+	}
+
+	c.name = name
+	if resp.GetName() != "" {
+		c.name = resp.GetName()
+	}
+	caps := resp.GetCapabilities()
+	c.capabilities = provider.Capabilities{
+		SupportsVersioning: caps.GetSupportsVersioning(),
+		SupportsMetadata:   caps.GetSupportsMetadata(),
+		SupportsWatching:   caps.GetSupportsWatching(),
+		SupportsBinary:     caps.GetSupportsBinary(),
+		RequiresAuth:       caps.GetRequiresAuth(),
+		AuthMethods:        caps.GetAuthMethods(),
+	}
+	c.supportsRotation = resp.GetSupportsRotation()
+	return nil
+}
+
+// Name returns the provider name reported by the plugin
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Capabilities returns the capabilities reported by the plugin
+func (c *Client) Capabilities() provider.Capabilities {
+	return c.capabilities
+}
+
+// SupportsRotation reports whether the plugin's provider implements provider.Rotator
+func (c *Client) SupportsRotation() bool {
+	return c.supportsRotation
+}
+
+// Validate forwards provider.Provider.Validate
+func (c *Client) Validate(ctx context.Context) error {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if _, err := c.client.Validate(ctx, &pb.ValidateRequest{}); err != nil {
+		return c.fromStatus(ctx, err, "")
+	}
+	return nil
+}
+
+// Resolve forwards provider.Provider.Resolve
+func (c *Client) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.client.Resolve(ctx, &pb.ResolveRequest{Ref: toProtoRef(ref)})
+	if err != nil {
+		return provider.SecretValue{}, c.fromStatus(ctx, err, ref.Key)
+	}
+	value := provider.SecretValue{
+		Value:    resp.GetValue(),
+		Version:  resp.GetVersion(),
+		Metadata: resp.GetMetadata(),
+	}
+	if resp.GetUpdatedAt() != nil {
+		value.UpdatedAt = resp.GetUpdatedAt().AsTime()
+	}
+	return value, nil
+}
+
+// Describe forwards provider.Provider.Describe
+func (c *Client) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.client.Describe(ctx, &pb.DescribeRequest{Ref: toProtoRef(ref)})
+	if err != nil {
+		return provider.Metadata{}, c.fromStatus(ctx, err, ref.Key)
+	}
+	meta := provider.Metadata{
+		Exists:      resp.GetExists(),
+		Version:     resp.GetVersion(),
+		Size:        int(resp.GetSize()),
+		Type:        resp.GetType(),
+		Permissions: resp.GetPermissions(),
+		Tags:        resp.GetTags(),
+		Deprecated:  resp.GetDeprecated(),
+	}
+	if resp.GetUpdatedAt() != nil {
+		meta.UpdatedAt = resp.GetUpdatedAt().AsTime()
+	}
+	return meta, nil
+}
+
+// CreateNewVersion forwards provider.Rotator.CreateNewVersion
+func (c *Client) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	if !c.supportsRotation {
+		return "", fmt.Errorf("plugin provider %s does not support rotation", c.name)
+	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.client.CreateNewVersion(ctx, &pb.CreateNewVersionRequest{Ref: toProtoRef(ref), NewValue: newValue, Metadata: meta})
+	if err != nil {
+		return "", c.fromStatus(ctx, err, ref.Key)
+	}
+	return resp.GetVersion(), nil
+}
+
+// DeprecateVersion forwards provider.Rotator.DeprecateVersion
+func (c *Client) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	if !c.supportsRotation {
+		return fmt.Errorf("plugin provider %s does not support rotation", c.name)
+	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if _, err := c.client.DeprecateVersion(ctx, &pb.DeprecateVersionRequest{Ref: toProtoRef(ref), Version: version}); err != nil {
+		return c.fromStatus(ctx, err, ref.Key)
+	}
+	return nil
+}
+
+// GetRotationMetadata forwards provider.Rotator.GetRotationMetadata
+func (c *Client) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	if !c.supportsRotation {
+		return provider.RotationMetadata{SupportsRotation: false}, nil
+	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.client.GetRotationMetadata(ctx, &pb.GetRotationMetadataRequest{Ref: toProtoRef(ref)})
+	if err != nil {
+		return provider.RotationMetadata{}, c.fromStatus(ctx, err, ref.Key)
+	}
+	meta := provider.RotationMetadata{
+		SupportsRotation:   resp.GetSupportsRotation(),
+		SupportsVersioning: resp.GetSupportsVersioning(),
+		MaxValueLength:     int(resp.GetMaxValueLength()),
+		MinValueLength:     int(resp.GetMinValueLength()),
+		AllowedCharacters:  resp.GetAllowedCharacters(),
+		RotationInterval:   resp.GetRotationInterval(),
+		Constraints:        resp.GetConstraints(),
+	}
+	if resp.GetLastRotated() != nil {
+		t := resp.GetLastRotated().AsTime()
+		meta.LastRotated = &t
+	}
+	if resp.GetNextRotation() != nil {
+		t := resp.GetNextRotation().AsTime()
+		meta.NextRotation = &t
+	}
+	return meta, nil
+}
+
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// fromStatus turns a gRPC error back into the provider error types
+func (c *Client) fromStatus(ctx context.Context, err error, key string) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.NotFound:
+		return &provider.NotFoundError{Provider: c.name, Key: key}
+	case codes.Unauthenticated:
+		return provider.AuthError{Provider: c.name, Message: st.Message()}
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	case codes.Canceled:
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return context.Canceled
+	case codes.Unavailable:
+		return &provider.UnavailableError{Provider: c.name, Err: fmt.Errorf("%w: %s", ErrUnavailable, st.Message())}
+	case codes.Internal:
+		return &provider.UnavailableError{Provider: c.name, Err: errors.New(st.Message())}
+	default:
+		return errors.New(st.Message())
+	}
+}
+
+// ErrUnavailable is returned when the plugin cannot be reached, typically
+// because its process exited
+var ErrUnavailable = errors.New("plugin unavailable")
+
+func toProtoRef(ref provider.Reference) *pb.Reference {
+	return &pb.Reference{
+		Provider: ref.Provider,
+		Key:      ref.Key,
+		Version:  ref.Version,
+		Path:     ref.Path,
+		Field:    ref.Field,
+	}
+}
@@ -0,0 +1,56 @@
+// Package plugin lets secret store providers run outside the dsops binary.
+//
+// A plugin is an executable that serves one provider.Provider (and,
+// optionally, provider.Rotator) over gRPC. dsops starts it on first use,
+// talks to it over a local socket and stops it when dsops exits, so a plugin
+// that crashes or hangs only fails the calls made to it.
+//
+// # Writing a Plugin
+//
+// Implement provider.Provider as for a built-in provider and hand a factory
+// to Serve:
+//
+//	package main
+//
+//	import (
+//	    "github.com/systmms/dsops/pkg/plugin"
+//	    "github.com/systmms/dsops/pkg/provider"
+//	)
+//
+//	func main() {
+//	    plugin.Serve(func(name string, config map[string]interface{}) (provider.Provider, error) {
+//	        return NewMyProvider(name, config)
+//	    })
+//	}
+//
+// Return provider.NotFoundError, provider.AuthError and
+// provider.UnavailableError as usual; they reach dsops as the same types. Log
+// to stderr only: stdout carries the handshake, and dsops shows the end of
+// stderr when the plugin fails.
+//
+// Test the provider through the plugin protocol with the plugintest package.
+//
+// # Using a Plugin
+//
+// Declare the executable on a secret store:
+//
+//	secretStores:
+//	  corp:
+//	    type: plugin
+//	    command: /usr/local/bin/dsops-provider-corp
+//	    args: ["--region", "eu"]
+//	    endpoint: https://secrets.corp.example  # passed to the factory
+//
+// or install it as dsops-provider-<type> in the plugins directory
+// ($DSOPS_PLUGIN_DIR, by default the dsops/plugins folder in the user
+// configuration directory) and use <type> as the store type.
+//
+// # Protocol
+//
+// dsops starts the plugin with MagicCookieKey and ProtocolVersionsKey in its
+// environment. The plugin answers with one Handshake line on stdout naming
+// the protocol version and socket, then serves the Provider service from
+// proto/provider.proto. dsops calls Configure with the store configuration
+// before any other call, and bounds every call with the store's timeout_ms.
+// The plugin exits when its stdin is closed.
+package plugin
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the plugin protocol version this package speaks.
+// It changes only when provider.proto changes incompatibly.
+const ProtocolVersion = 1
+
+const (
+	// MagicCookieKey and MagicCookieValue are set in the environment of
+	// plugins started by dsops. They are not a security measure; they stop a
+	// plugin binary run by hand from waiting for a connection forever.
+	MagicCookieKey   = "DSOPS_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "b4a5b7cc3a0e4cb0a33c5f52a7bd38c1"
+
+	// ProtocolVersionsKey lists the protocol versions dsops accepts,
+	// comma-separated
+	ProtocolVersionsKey = "DSOPS_PLUGIN_PROTOCOL_VERSIONS"
+)
+
+// Handshake is the first line a plugin writes to stdout, telling dsops the
+// negotiated protocol version and where the gRPC server listens:
+//
+//	1|unix|/tmp/dsops-plugin-123/plugin.sock
+type Handshake struct {
+	ProtocolVersion int
+	Network         string // "unix" or "tcp"
+	Address         string
+}
+
+// String formats the handshake line, without the trailing newline
+func (h Handshake) String() string {
+	return fmt.Sprintf("%d|%s|%s", h.ProtocolVersion, h.Network, h.Address)
+}
+
+// ParseHandshake parses a handshake line written by a plugin
+func ParseHandshake(line string) (Handshake, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 3 {
+		return Handshake{}, fmt.Errorf("invalid plugin handshake %q: expected version|network|address", line)
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Handshake{}, fmt.Errorf("invalid plugin handshake %q: bad protocol version", line)
+	}
+	if parts[1] != "unix" && parts[1] != "tcp" {
+		return Handshake{}, fmt.Errorf("invalid plugin handshake %q: unsupported network %q", line, parts[1])
+	}
+	if parts[2] == "" {
+		return Handshake{}, fmt.Errorf("invalid plugin handshake %q: empty address", line)
+	}
+	return Handshake{ProtocolVersion: version, Network: parts[1], Address: parts[2]}, nil
+}
+
+// negotiateVersion picks the highest protocol version offered by dsops that
+// this package also supports
+func negotiateVersion() (int, error) {
+	offered := os.Getenv(ProtocolVersionsKey)
+	if offered == "" {
+		return ProtocolVersion, nil
+	}
+
+	var versions []int
+	for _, v := range strings.Split(offered, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", ProtocolVersionsKey, offered)
+		}
+		versions = append(versions, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	for _, v := range versions {
+		if v == ProtocolVersion {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("dsops offers plugin protocol versions %s but this plugin speaks version %d; rebuild it against a matching dsops release", offered, ProtocolVersion)
+}
@@ -0,0 +1,236 @@
+package plugin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systmms/dsops/pkg/plugin"
+	"github.com/systmms/dsops/pkg/plugin/plugintest"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// memoryProvider is a small in-memory provider with rotation support
+type memoryProvider struct {
+	name string
+
+	mu       sync.Mutex
+	versions map[string][]string
+}
+
+func newMemoryProvider(name string, config map[string]interface{}) (provider.Provider, error) {
+	p := &memoryProvider{name: name, versions: map[string][]string{}}
+	if seed, ok := config["seed"].(map[string]interface{}); ok {
+		for k, v := range seed {
+			p.versions[k] = []string{fmt.Sprint(v)}
+		}
+	}
+	if _, ok := config["fail"]; ok {
+		return nil, errors.New("fail is set")
+	}
+	return p, nil
+}
+
+func (p *memoryProvider) Name() string { return p.name }
+
+func (p *memoryProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{SupportsVersioning: true, SupportsMetadata: true, RequiresAuth: true, AuthMethods: []string{"token"}}
+}
+
+func (p *memoryProvider) Validate(ctx context.Context) error { return ctx.Err() }
+
+func (p *memoryProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	if err := ctx.Err(); err != nil {
+		return provider.SecretValue{}, err
+	}
+	switch ref.Key {
+	case "denied":
+		return provider.SecretValue{}, provider.AuthError{Provider: p.name, Message: "token expired"}
+	case "outage":
+		return provider.SecretValue{}, provider.UnavailableError{Provider: p.name, Err: errors.New("backend returned 503")}
+	case "panic":
+		panic("boom")
+	case "slow":
+		<-ctx.Done()
+		return provider.SecretValue{}, ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	versions, ok := p.versions[ref.Key]
+	if !ok {
+		return provider.SecretValue{}, provider.NotFoundError{Provider: p.name, Key: ref.Key}
+	}
+	return provider.SecretValue{
+		Value:     versions[len(versions)-1],
+		Version:   strconv.Itoa(len(versions)),
+		UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metadata:  map[string]string{"source": "memory"},
+	}, nil
+}
+
+func (p *memoryProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	versions, ok := p.versions[ref.Key]
+	if !ok {
+		return provider.Metadata{Exists: false}, nil
+	}
+	return provider.Metadata{Exists: true, Version: strconv.Itoa(len(versions)), Size: len(versions[len(versions)-1]), Tags: map[string]string{"env": "test"}}, nil
+}
+
+func (p *memoryProvider) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.versions[ref.Key] = append(p.versions[ref.Key], string(newValue))
+	return strconv.Itoa(len(p.versions[ref.Key])), nil
+}
+
+func (p *memoryProvider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	if version == "" {
+		return errors.New("version is required")
+	}
+	return nil
+}
+
+func (p *memoryProvider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	last := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return provider.RotationMetadata{SupportsRotation: true, SupportsVersioning: true, MinValueLength: 8, RotationInterval: "720h", LastRotated: &last}, nil
+}
+
+// readOnlyProvider hides the rotation methods of memoryProvider
+type readOnlyProvider struct{ provider.Provider }
+
+var seedConfig = map[string]interface{}{"seed": map[string]interface{}{"api/token": "s3cr3t", "port": 5432}}
+
+func TestConformance(t *testing.T) {
+	plugintest.RunConformance(t, newMemoryProvider, plugintest.Options{
+		Config: seedConfig,
+		SetupTestSecret: func(t *testing.T, p provider.Provider) (string, func()) {
+			return "api/token", func() {}
+		},
+	})
+}
+
+func TestConformance_WithoutRotation(t *testing.T) {
+	factory := func(name string, config map[string]interface{}) (provider.Provider, error) {
+		p, err := newMemoryProvider(name, config)
+		return readOnlyProvider{p}, err
+	}
+	plugintest.RunConformance(t, factory, plugintest.Options{Config: seedConfig})
+}
+
+func TestClient_RoundTrip(t *testing.T) {
+	client := plugintest.Connect(t, newMemoryProvider, "corp", seedConfig)
+	ctx := context.Background()
+
+	assert.Equal(t, "corp", client.Name())
+	assert.Equal(t, []string{"token"}, client.Capabilities().AuthMethods)
+	assert.True(t, client.SupportsRotation())
+
+	value, err := client.Resolve(ctx, provider.Reference{Key: "port"})
+	require.NoError(t, err)
+	assert.Equal(t, "5432", value.Value)
+	assert.Equal(t, "1", value.Version)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), value.UpdatedAt)
+	assert.Equal(t, "memory", value.Metadata["source"])
+
+	meta, err := client.Describe(ctx, provider.Reference{Key: "api/token"})
+	require.NoError(t, err)
+	assert.True(t, meta.Exists)
+	assert.Equal(t, 6, meta.Size)
+	assert.Equal(t, "test", meta.Tags["env"])
+
+	version, err := client.CreateNewVersion(ctx, provider.Reference{Key: "api/token"}, []byte("n3w"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2", version)
+	value, err = client.Resolve(ctx, provider.Reference{Key: "api/token"})
+	require.NoError(t, err)
+	assert.Equal(t, "n3w", value.Value)
+
+	assert.Error(t, client.DeprecateVersion(ctx, provider.Reference{Key: "api/token"}, ""))
+	rotation, err := client.GetRotationMetadata(ctx, provider.Reference{Key: "api/token"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, rotation.MinValueLength)
+	require.NotNil(t, rotation.LastRotated)
+	assert.Nil(t, rotation.NextRotation)
+}
+
+func TestClient_Errors(t *testing.T) {
+	client := plugintest.Connect(t, newMemoryProvider, "corp", nil)
+	ctx := context.Background()
+
+	_, err := client.Resolve(ctx, provider.Reference{Key: "missing"})
+	var notFound *provider.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "missing", notFound.Key)
+	assert.Equal(t, "corp", notFound.Provider)
+
+	_, err = client.Resolve(ctx, provider.Reference{Key: "denied"})
+	var authErr provider.AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Equal(t, "token expired", authErr.Message)
+
+	_, err = client.Resolve(ctx, provider.Reference{Key: "outage"})
+	assert.True(t, provider.IsUnavailable(err), "got %v", err)
+	assert.ErrorIs(t, err, plugin.ErrUnavailable)
+
+	// A panic fails the call, not the plugin
+	_, err = client.Resolve(ctx, provider.Reference{Key: "panic"})
+	assert.ErrorContains(t, err, "plugin panicked: boom")
+	assert.True(t, provider.IsUnavailable(err), "got %v", err)
+	_, err = client.Resolve(ctx, provider.Reference{Key: "missing"})
+	assert.ErrorAs(t, err, &notFound)
+
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Resolve(shortCtx, provider.Reference{Key: "slow"})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	cancelled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	_, err = client.Resolve(cancelled, provider.Reference{Key: "port"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_ConfigureError(t *testing.T) {
+	client := plugintest.Connect(t, newMemoryProvider, "corp", nil)
+	err := client.Configure(context.Background(), "corp", map[string]interface{}{"fail": true})
+	assert.ErrorContains(t, err, "invalid plugin configuration: fail is set")
+
+	err = client.Configure(context.Background(), "corp", map[string]interface{}{"bad": make(chan int)})
+	assert.ErrorContains(t, err, "cannot be sent to the plugin")
+}
+
+func TestClient_WithoutRotation(t *testing.T) {
+	factory := func(name string, config map[string]interface{}) (provider.Provider, error) {
+		p, err := newMemoryProvider(name, config)
+		return readOnlyProvider{p}, err
+	}
+	client := plugintest.Connect(t, factory, "corp", nil)
+
+	assert.False(t, client.SupportsRotation())
+	meta, err := client.GetRotationMetadata(context.Background(), provider.Reference{Key: "x"})
+	require.NoError(t, err)
+	assert.False(t, meta.SupportsRotation)
+	_, err = client.CreateNewVersion(context.Background(), provider.Reference{Key: "x"}, []byte("v"), nil)
+	assert.ErrorContains(t, err, "does not support rotation")
+}
+
+func TestParseHandshake(t *testing.T) {
+	h, err := plugin.ParseHandshake("1|unix|/tmp/dsops-plugin-1/plugin.sock\n")
+	require.NoError(t, err)
+	assert.Equal(t, plugin.Handshake{ProtocolVersion: 1, Network: "unix", Address: "/tmp/dsops-plugin-1/plugin.sock"}, h)
+	assert.Equal(t, "1|unix|/tmp/dsops-plugin-1/plugin.sock", h.String())
+
+	for _, line := range []string{"", "hello", "x|unix|/a", "1|udp|/a", "1|tcp|"} {
+		_, err := plugin.ParseHandshake(line)
+		assert.Error(t, err, line)
+	}
+}
@@ -0,0 +1,150 @@
+// Package plugintest checks provider plugins against the provider contract.
+//
+// The provider is served in process over an in-memory gRPC connection, so
+// the tests exercise the same protocol dsops uses without building or
+// starting the plugin binary:
+//
+//	func TestPlugin(t *testing.T) {
+//	    plugintest.RunConformance(t, newProvider, plugintest.Options{
+//	        Config: map[string]interface{}{"endpoint": server.URL},
+//	        SetupTestSecret: func(t *testing.T, p provider.Provider) (string, func()) {
+//	            return "test/secret", func() {}
+//	        },
+//	    })
+//	}
+package plugintest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/systmms/dsops/pkg/plugin"
+	"github.com/systmms/dsops/pkg/provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Options configures RunConformance
+type Options struct {
+	// Name is the store name passed to the factory (default: "plugin-test")
+	Name string
+
+	// Config is the store configuration passed to the factory
+	Config map[string]interface{}
+
+	// SetupTestSecret creates a secret through the plugin and returns its
+	// key and a cleanup function. Resolve and Describe tests are skipped
+	// without it.
+	SetupTestSecret func(t *testing.T, p provider.Provider) (key string, cleanup func())
+
+	// Skip certain tests if the provider doesn't support them
+	SkipValidation bool
+	SkipMetadata   bool
+}
+
+// RunConformance runs provider.RunContractTests against the provider served
+// through the plugin protocol, plus checks that errors and rotation support
+// survive the connection
+func RunConformance(t *testing.T, factory plugin.Factory, opts Options) {
+	if opts.Name == "" {
+		opts.Name = "plugin-test"
+	}
+
+	provider.RunContractTests(t, provider.ContractTest{
+		CreateProvider: func(t *testing.T) provider.Provider {
+			return Connect(t, factory, opts.Name, opts.Config)
+		},
+		SetupTestSecret: opts.SetupTestSecret,
+		SkipValidation:  opts.SkipValidation,
+		SkipMetadata:    opts.SkipMetadata,
+	})
+
+	t.Run("Plugin", func(t *testing.T) {
+		t.Run("NotFoundError", func(t *testing.T) {
+			testNotFoundError(t, factory, opts)
+		})
+		t.Run("Rotation", func(t *testing.T) {
+			testRotation(t, factory, opts)
+		})
+	})
+}
+
+// Connect serves the provider created by factory over an in-memory
+// connection and returns a configured client. The server is stopped when
+// the test ends.
+func Connect(t *testing.T, factory plugin.Factory, name string, config map[string]interface{}) *plugin.Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := plugin.NewGRPCServer(factory)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///plugintest",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect to plugin: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := plugin.NewClient(conn, 10*time.Second)
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	if err := client.Configure(context.Background(), name, config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+	return client
+}
+
+// testNotFoundError checks that a NotFoundError from the provider arrives as
+// a provider.NotFoundError
+func testNotFoundError(t *testing.T, factory plugin.Factory, opts Options) {
+	direct, err := factory(opts.Name, opts.Config)
+	if err != nil {
+		t.Fatalf("factory failed: %v", err)
+	}
+	client := Connect(t, factory, opts.Name, opts.Config)
+
+	ref := provider.Reference{Key: "this-secret-definitely-does-not-exist-" + time.Now().Format("20060102150405")}
+	_, directErr := direct.Resolve(context.Background(), ref)
+	if !provider.IsNotFound(directErr) {
+		t.Skip("provider does not return NotFoundError for missing secrets")
+	}
+
+	_, err = client.Resolve(context.Background(), ref)
+	if !provider.IsNotFound(err) {
+		t.Errorf("Resolve() through the plugin returned %v, want a NotFoundError", err)
+	}
+}
+
+// testRotation checks that rotation support is reported as the provider
+// implements it
+func testRotation(t *testing.T, factory plugin.Factory, opts Options) {
+	direct, err := factory(opts.Name, opts.Config)
+	if err != nil {
+		t.Fatalf("factory failed: %v", err)
+	}
+	client := Connect(t, factory, opts.Name, opts.Config)
+
+	_, rotator := direct.(provider.Rotator)
+	if client.SupportsRotation() != rotator {
+		t.Errorf("SupportsRotation() = %v, but the provider implements Rotator: %v", client.SupportsRotation(), rotator)
+	}
+	if !rotator || opts.SetupTestSecret == nil {
+		return
+	}
+
+	key, cleanup := opts.SetupTestSecret(t, client)
+	defer cleanup()
+	if _, err := client.GetRotationMetadata(context.Background(), provider.Reference{Key: key}); err != nil {
+		t.Errorf("GetRotationMetadata() failed: %v", err)
+	}
+}
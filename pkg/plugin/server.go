@@ -0,0 +1,354 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
+
+	pb "github.com/systmms/dsops/pkg/plugin/proto"
+	"github.com/systmms/dsops/pkg/provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Factory creates the provider a plugin serves from the secret store
+// configuration in dsops.yaml. It has the same shape as the factories of the
+// built-in providers.
+type Factory func(name string, config map[string]interface{}) (provider.Provider, error)
+
+// Serve runs a provider plugin. Call it from the plugin's main function:
+//
+//	func main() {
+//	    plugin.Serve(func(name string, config map[string]interface{}) (provider.Provider, error) {
+//	        return NewMyProvider(name, config)
+//	    })
+//	}
+//
+// Serve negotiates the protocol with dsops, serves the provider over gRPC on
+// a local socket and returns when dsops closes the plugin's stdin or sends
+// SIGTERM. Stdout belongs to the handshake, so plugins must log to stderr;
+// dsops includes the last lines of stderr in its errors when a plugin fails.
+func Serve(factory Factory) {
+	if err := serve(factory); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func serve(factory Factory) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return fmt.Errorf("this is a dsops provider plugin and is started by dsops; declare it in dsops.yaml with 'type: plugin' and 'command:'")
+	}
+	version, err := negotiateVersion()
+	if err != nil {
+		return err
+	}
+
+	listener, handshake, cleanup, err := listen(version)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	server := NewGRPCServer(factory)
+
+	// Stop when dsops goes away: it holds our stdin open for as long as it
+	// needs the plugin, so EOF means dsops exited or crashed
+	stop := make(chan struct{})
+	var once sync.Once
+	shutdown := func() { once.Do(func() { close(stop) }) }
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		shutdown()
+	}()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		shutdown()
+	}()
+	go func() {
+		<-stop
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			server.Stop()
+		}
+	}()
+
+	out := bufio.NewWriter(os.Stdout)
+	_, _ = fmt.Fprintln(out, handshake.String())
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write plugin handshake: %w", err)
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("plugin server failed: %w", err)
+	}
+	return nil
+}
+
+// listen opens a Unix socket in a private temporary directory, or a loopback
+// TCP port on Windows
+func listen(version int) (net.Listener, Handshake, func(), error) {
+	if runtime.GOOS == "windows" {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, Handshake{}, nil, fmt.Errorf("failed to listen: %w", err)
+		}
+		return listener, Handshake{ProtocolVersion: version, Network: "tcp", Address: listener.Addr().String()}, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "dsops-plugin-")
+	if err != nil {
+		return nil, Handshake{}, nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	path := filepath.Join(dir, "plugin.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, Handshake{}, nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	return listener, Handshake{ProtocolVersion: version, Network: "unix", Address: path}, func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// recoverInterceptor turns a panic in the provider into an error for that
+// call, so one bad request does not take the plugin down
+func recoverInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "plugin panicked: %v", r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// server adapts a provider.Provider to the gRPC service
+type server struct {
+	pb.UnimplementedProviderServer
+
+	factory Factory
+
+	mu       sync.RWMutex
+	provider provider.Provider
+}
+
+// NewGRPCServer returns a gRPC server with the Provider service for the
+// providers created by factory. Serve uses it; it is exported for tests that
+// run a plugin in process.
+func NewGRPCServer(factory Factory) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(recoverInterceptor))
+	pb.RegisterProviderServer(grpcServer, &server{factory: factory})
+	return grpcServer
+}
+
+func (s *server) Configure(ctx context.Context, req *pb.ConfigureRequest) (*pb.ConfigureResponse, error) {
+	config := map[string]interface{}{}
+	if req.GetConfig() != nil {
+		config = req.GetConfig().AsMap()
+	}
+	p, err := s.factory(req.GetName(), config)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.mu.Lock()
+	s.provider = p
+	s.mu.Unlock()
+
+	caps := p.Capabilities()
+	_, rotator := p.(provider.Rotator)
+	return &pb.ConfigureResponse{
+		Name: p.Name(),
+		Capabilities: &pb.Capabilities{
+			SupportsVersioning: caps.SupportsVersioning,
+			SupportsMetadata:   caps.SupportsMetadata,
+			SupportsWatching:   caps.SupportsWatching,
+			SupportsBinary:     caps.SupportsBinary,
+			RequiresAuth:       caps.RequiresAuth,
+			AuthMethods:        caps.AuthMethods,
+		},
+		SupportsRotation: rotator,
+	}, nil
+}
+
+func (s *server) Validate(ctx context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	p, err := s.configured()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(ctx); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ValidateResponse{}, nil
+}
+
+func (s *server) Resolve(ctx context.Context, req *pb.ResolveRequest) (*pb.ResolveResponse, error) {
+	p, err := s.configured()
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.Resolve(ctx, fromProtoRef(req.GetRef()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ResolveResponse{
+		Value:     value.Value,
+		Version:   value.Version,
+		UpdatedAt: toTimestamp(value.UpdatedAt),
+		Metadata:  value.Metadata,
+	}, nil
+}
+
+func (s *server) Describe(ctx context.Context, req *pb.DescribeRequest) (*pb.DescribeResponse, error) {
+	p, err := s.configured()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := p.Describe(ctx, fromProtoRef(req.GetRef()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DescribeResponse{
+		Exists:      meta.Exists,
+		Version:     meta.Version,
+		UpdatedAt:   toTimestamp(meta.UpdatedAt),
+		Size:        int64(meta.Size),
+		Type:        meta.Type,
+		Permissions: meta.Permissions,
+		Tags:        meta.Tags,
+		Deprecated:  meta.Deprecated,
+	}, nil
+}
+
+func (s *server) CreateNewVersion(ctx context.Context, req *pb.CreateNewVersionRequest) (*pb.CreateNewVersionResponse, error) {
+	rotator, err := s.rotator()
+	if err != nil {
+		return nil, err
+	}
+	version, err := rotator.CreateNewVersion(ctx, fromProtoRef(req.GetRef()), req.GetNewValue(), req.GetMetadata())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateNewVersionResponse{Version: version}, nil
+}
+
+func (s *server) DeprecateVersion(ctx context.Context, req *pb.DeprecateVersionRequest) (*pb.DeprecateVersionResponse, error) {
+	rotator, err := s.rotator()
+	if err != nil {
+		return nil, err
+	}
+	if err := rotator.DeprecateVersion(ctx, fromProtoRef(req.GetRef()), req.GetVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeprecateVersionResponse{}, nil
+}
+
+func (s *server) GetRotationMetadata(ctx context.Context, req *pb.GetRotationMetadataRequest) (*pb.GetRotationMetadataResponse, error) {
+	rotator, err := s.rotator()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := rotator.GetRotationMetadata(ctx, fromProtoRef(req.GetRef()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.GetRotationMetadataResponse{
+		SupportsRotation:   meta.SupportsRotation,
+		SupportsVersioning: meta.SupportsVersioning,
+		MaxValueLength:     int64(meta.MaxValueLength),
+		MinValueLength:     int64(meta.MinValueLength),
+		AllowedCharacters:  meta.AllowedCharacters,
+		RotationInterval:   meta.RotationInterval,
+		Constraints:        meta.Constraints,
+	}
+	if meta.LastRotated != nil {
+		resp.LastRotated = timestamppb.New(*meta.LastRotated)
+	}
+	if meta.NextRotation != nil {
+		resp.NextRotation = timestamppb.New(*meta.NextRotation)
+	}
+	return resp, nil
+}
+
+func (s *server) configured() (provider.Provider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.provider == nil {
+		return nil, status.Error(codes.FailedPrecondition, "plugin is not configured")
+	}
+	return s.provider, nil
+}
+
+func (s *server) rotator() (provider.Rotator, error) {
+	p, err := s.configured()
+	if err != nil {
+		return nil, err
+	}
+	rotator, ok := p.(provider.Rotator)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "provider %s does not support rotation", p.Name())
+	}
+	return rotator, nil
+}
+
+// toStatus maps the provider error types onto gRPC codes, so the client can
+// turn them back into the same types
+func toStatus(err error) error {
+	var notFound provider.NotFoundError
+	var notFoundPtr *provider.NotFoundError
+	var authErr provider.AuthError
+	var authErrPtr *provider.AuthError
+	switch {
+	case errors.As(err, &notFound), errors.As(err, &notFoundPtr):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &authErr):
+		return status.Error(codes.Unauthenticated, authErr.Message)
+	case errors.As(err, &authErrPtr):
+		return status.Error(codes.Unauthenticated, authErrPtr.Message)
+	case provider.IsUnavailable(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+func fromProtoRef(ref *pb.Reference) provider.Reference {
+	return provider.Reference{
+		Provider: ref.GetProvider(),
+		Key:      ref.GetKey(),
+		Version:  ref.GetVersion(),
+		Path:     ref.GetPath(),
+		Field:    ref.GetField(),
+	}
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -54,13 +54,14 @@ type CertificateResult struct {
 
 // CertificateInfo contains certificate metadata
 type CertificateInfo struct {
-	SerialNumber string
-	Subject      string
-	Issuer       string
-	NotBefore    time.Time
-	NotAfter     time.Time
-	DNSNames     []string
-	Status       string
+	SerialNumber string    `json:"serial_number"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	DNSNames     []string  `json:"dns_names,omitempty"`
+	SANs         []string  `json:"sans,omitempty"` // Every subject alternative name, e.g. "DNS:example.com", "IP:10.0.0.1"
+	Status       string    `json:"status"`
 }
 
 // NewCertificateAdapter creates a new certificate protocol adapter
@@ -89,6 +89,7 @@
 // The package defines standardized error types:
 //   - NotFoundError: Secret doesn't exist in the provider
 //   - AuthError: Authentication failed
+//   - UnavailableError: The provider could not be reached or could not answer
 //   - General Go errors: For other failure cases
 //
 // This standardization enables consistent error handling across the application
@@ -113,6 +114,17 @@
 // rotation at the storage level (creating new versions, deprecating old ones).
 // This is distinct from service-level rotation handled by other packages.
 //
+// ## Writer Interface
+//
+// Providers can optionally implement the Writer interface to create, update
+// and delete secrets. This backs the dsops set and dsops delete commands.
+//
+// ## Lister Interface
+//
+// Providers can optionally implement the Lister interface to enumerate secret
+// keys and metadata without reading values. This backs the dsops ls command.
+// PageSecrets helps providers whose APIs return everything in one response.
+//
 // ## Custom Authentication
 //
 // Providers can implement custom authentication methods by leveraging the
@@ -22,8 +22,12 @@
 //
 //  1. Implement the Provider interface
 //  2. Optionally implement Rotator for rotation support
-//  3. Register your provider in the provider registry
-//  4. Add configuration support
+//  3. Optionally implement Writer for dsops set and dsops delete
+//  4. Optionally implement Lister for dsops ls
+//  5. Optionally implement LeaseManager for leased, short-lived secrets
+//  6. Optionally implement Authenticator for dsops login
+//  7. Register your provider in the provider registry
+//  8. Add configuration support
 //
 // Example:
 //
@@ -54,8 +58,9 @@
 // # Error Handling
 //
 // Providers should use the standard error types defined in this package:
-//   - NotFoundError for missing secrets
+//   - NotFoundError for missing secrets (check with IsNotFound)
 //   - AuthError for authentication failures
+//   - UnavailableError when the store cannot be reached or cannot answer
 //   - Standard Go errors for other cases
 //
 // # Security Considerations
@@ -76,6 +81,11 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -378,6 +388,11 @@ type Metadata struct {
 	// Common keys include environment, owner, purpose, etc.
 	// Empty map if not supported.
 	Tags map[string]string
+
+	// Deprecated indicates that the version pinned by the reference is no
+	// longer current and is disabled, unlabelled or scheduled for removal.
+	// Always false for unpinned references or when not supported.
+	Deprecated bool
 }
 
 // Capabilities describes what features and operations a provider supports.
@@ -455,6 +470,16 @@ func (e NotFoundError) Error() string {
 	return "secret not found: " + e.Key + " in " + e.Provider
 }
 
+// IsNotFound reports whether err is, or wraps, a NotFoundError.
+//
+// Both value and pointer forms are recognised, so callers do not need to know
+// which one a provider returned or how many layers of wrapping it went through.
+func IsNotFound(err error) bool {
+	var notFound NotFoundError
+	var notFoundPtr *NotFoundError
+	return errors.As(err, &notFound) || errors.As(err, &notFoundPtr)
+}
+
 // AuthError indicates that authentication to the provider failed.
 //
 // This error should be returned when:
@@ -484,6 +509,49 @@ func (e AuthError) Error() string {
 	return "authentication failed for " + e.Provider + ": " + e.Message
 }
 
+// UnavailableError indicates that the provider could not be reached or could
+// not answer, for example because of a server error or a dropped connection.
+//
+// Providers wrap vendor errors in it so callers can tell an outage from an
+// answer such as "not found" or "access denied" without knowing the SDK.
+// Fallback chains with "on: unavailable" rely on it.
+//
+// Example:
+//
+//	if resp.StatusCode >= 500 {
+//	    return SecretValue{}, UnavailableError{
+//	        Provider: p.Name(),
+//	        Err:      err,
+//	    }
+//	}
+type UnavailableError struct {
+	// Provider is the name of the provider that could not answer.
+	Provider string
+
+	// Err is the underlying error reported by the client or SDK.
+	Err error
+}
+
+// Error implements the error interface.
+func (e UnavailableError) Error() string {
+	if e.Err == nil {
+		return e.Provider + " is unavailable"
+	}
+	return e.Provider + " is unavailable: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// IsUnavailable reports whether err is, or wraps, an UnavailableError.
+func IsUnavailable(err error) bool {
+	var unavailable UnavailableError
+	var unavailablePtr *UnavailableError
+	return errors.As(err, &unavailable) || errors.As(err, &unavailablePtr)
+}
+
 // Rotator defines the interface for providers that support secret rotation within the storage system.
 //
 // This interface extends the basic Provider functionality to enable providers to
@@ -665,3 +733,327 @@ type RotationMetadata struct {
 	//   - "notification": Required notification methods
 	Constraints map[string]string `json:"constraints,omitempty"`
 }
+
+// VersionLister defines the interface for providers that can enumerate the
+// versions of a secret.
+//
+// Like Rotator, VersionLister is optional and found with a type assertion.
+// Emergency rotation relies on it to revoke every version of a compromised
+// secret rather than only the current one.
+//
+// Example:
+//
+//	versions, err := lister.ListVersions(ctx, ref)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, version := range versions {
+//	    if err := rotator.DeprecateVersion(ctx, ref, version); err != nil {
+//	        return err
+//	    }
+//	}
+type VersionLister interface {
+	// ListVersions returns the versions of the secret that can still be
+	// read. Disabled, deleted and destroyed versions are left out. A missing
+	// secret returns NotFoundError.
+	ListVersions(ctx context.Context, ref Reference) ([]string, error)
+}
+
+// VersionDestroyer defines the interface for providers whose DeprecateVersion
+// leaves versions recoverable, for example by soft-deleting them, but that can
+// also remove a version for good.
+//
+// Like VersionLister, VersionDestroyer is optional and found with a type
+// assertion. Emergency rotation prefers it over DeprecateVersion so a
+// compromised version cannot be restored.
+type VersionDestroyer interface {
+	// DestroyVersion permanently removes a version so it can neither be read
+	// nor recovered.
+	DestroyVersion(ctx context.Context, ref Reference, version string) error
+}
+
+// Writer defines the interface for providers that can create, update and delete secrets.
+//
+// Like Rotator, Writer is optional: callers discover support with a type
+// assertion. Unlike Rotator, Writer is meant for operator-driven changes
+// (dsops set, dsops delete) and creates secrets that do not exist yet.
+//
+// When ref addresses a single field of a structured secret (for example
+// "secret/data/app#password" in Vault or "app-config#.password" in AWS
+// Secrets Manager), implementations should update or remove only that field
+// and keep the rest of the secret intact.
+//
+// Example:
+//
+//	writer, ok := p.(Writer)
+//	if !ok {
+//	    return fmt.Errorf("provider %s is read-only", p.Name())
+//	}
+//	version, err := writer.PutSecret(ctx, ref, []byte("s3cr3t"), WriteOptions{
+//	    Description: "Stripe API key",
+//	    Tags:        map[string]string{"team": "payments"},
+//	})
+type Writer interface {
+	// PutSecret creates the secret if it does not exist, or stores value as its
+	// new current version if it does.
+	//
+	// Returns the version identifier of the stored value when the provider
+	// supports versioning, or an empty string otherwise. Implementations must
+	// never log the value.
+	PutSecret(ctx context.Context, ref Reference, value []byte, opts WriteOptions) (string, error)
+
+	// DeleteSecret removes a secret, or a single field when ref addresses one.
+	//
+	// Providers with soft deletion or recovery windows keep the secret
+	// recoverable unless opts.Force is set. Deleting a secret that does not
+	// exist returns NotFoundError.
+	DeleteSecret(ctx context.Context, ref Reference, opts DeleteOptions) error
+}
+
+// WriteOptions carries optional attributes stored alongside a secret value.
+//
+// Providers apply the attributes they support and ignore the rest.
+type WriteOptions struct {
+	// Description is a human-readable description of the secret.
+	Description string
+
+	// Tags are key/value labels attached to the secret itself, such as AWS
+	// resource tags, GCP labels, Azure tags or Vault custom metadata.
+	Tags map[string]string
+
+	// Metadata holds provider-specific write settings. Common keys include:
+	//   - "kms_key_id": Encryption key for AWS Secrets Manager and SSM
+	//   - "type": SSM parameter type (String, StringList, SecureString)
+	//   - "tier": SSM parameter tier (Standard, Advanced)
+	//   - "content_type": Azure Key Vault content type
+	Metadata map[string]string
+}
+
+// DeleteOptions controls how a secret is deleted.
+type DeleteOptions struct {
+	// Force deletes the secret immediately, skipping recovery windows and
+	// soft deletion where the provider supports them.
+	Force bool
+}
+
+// Lister defines the interface for providers that can enumerate the secrets
+// they hold.
+//
+// Lister is optional and, like Writer, discovered with a type assertion.
+// Listing returns metadata only: implementations must never fetch or return
+// secret values.
+//
+// Example:
+//
+//	lister, ok := p.(Lister)
+//	if !ok {
+//	    return fmt.Errorf("provider %s does not support listing", p.Name())
+//	}
+//	opts := ListOptions{Prefix: "myapp/"}
+//	for {
+//	    page, err := lister.ListSecrets(ctx, opts)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    for _, s := range page.Secrets {
+//	        fmt.Println(s.Key)
+//	    }
+//	    if page.NextPageToken == "" {
+//	        break
+//	    }
+//	    opts.PageToken = page.NextPageToken
+//	}
+type Lister interface {
+	// ListSecrets returns one page of secrets whose keys start with
+	// opts.Prefix, sorted by key where the provider allows it.
+	//
+	// Keys are returned in the same form Resolve accepts, so they can be
+	// used in dsops.yaml as-is. For hierarchical stores (Vault, pass,
+	// Akeyless) a key ending in "/" is a folder that can be listed by
+	// passing it as the prefix.
+	ListSecrets(ctx context.Context, opts ListOptions) (ListResult, error)
+}
+
+// ListOptions filters and pages a secret listing.
+type ListOptions struct {
+	// Prefix limits results to keys starting with this value. For
+	// hierarchical stores it is the path to list, e.g. "secret/data/myapp/".
+	Prefix string
+
+	// PageSize is the maximum number of secrets per page. Zero lets the
+	// provider choose.
+	PageSize int
+
+	// PageToken continues a previous listing. It is opaque and only valid
+	// for the provider that returned it.
+	PageToken string
+}
+
+// ListResult is one page of a secret listing.
+type ListResult struct {
+	// Secrets holds the secrets on this page.
+	Secrets []SecretInfo
+
+	// NextPageToken is set when more results are available.
+	NextPageToken string
+}
+
+// SecretInfo describes a listed secret without its value.
+type SecretInfo struct {
+	// Key is the reference key for the secret within the provider.
+	Key string
+
+	// Description is the human-readable description, if the provider stores one.
+	Description string
+
+	// Type is a provider-specific type such as an SSM parameter type.
+	Type string
+
+	// Version is the current version identifier, when known.
+	Version string
+
+	// UpdatedAt is when the secret last changed, when known.
+	UpdatedAt time.Time
+
+	// Tags are the labels attached to the secret.
+	Tags map[string]string
+}
+
+// LeaseManager defines the interface for providers whose secrets are leased:
+// time-limited credentials issued on read, such as Vault dynamic secrets.
+//
+// Like Writer and Lister, LeaseManager is optional and found with a type
+// assertion. dsops exec renews the leases returned by Leases while its child
+// process runs and revokes them when the child exits.
+//
+// Reads of the same leased path within one provider instance must share a
+// single lease, so that several variables taking different fields (username
+// and password) receive matching credentials.
+type LeaseManager interface {
+	// Leases returns the leases issued by Resolve so far and not yet revoked.
+	Leases() []Lease
+
+	// RenewLease extends a lease by increment, which the provider may cap.
+	// Returns the lease with its new duration.
+	RenewLease(ctx context.Context, leaseID string, increment time.Duration) (Lease, error)
+
+	// RevokeLease revokes a lease so its credentials stop working.
+	RevokeLease(ctx context.Context, leaseID string) error
+}
+
+// Lease describes a leased secret. It never holds the secret value, so it is
+// safe to log and record for audit.
+type Lease struct {
+	// ID is the provider's lease identifier.
+	ID string
+
+	// Provider is the name of the provider that issued the lease.
+	Provider string
+
+	// Path is the path the leased secret was read from.
+	Path string
+
+	// Duration is how long the lease is valid from IssuedAt.
+	Duration time.Duration
+
+	// Renewable reports whether RenewLease can extend the lease.
+	Renewable bool
+
+	// IssuedAt is when the lease was issued or last renewed.
+	IssuedAt time.Time
+}
+
+// ExpiresAt returns when the lease ends unless renewed.
+func (l Lease) ExpiresAt() time.Time {
+	return l.IssuedAt.Add(l.Duration)
+}
+
+// Watcher defines the interface for providers that can wait for a secret to
+// change, such as Consul blocking queries or etcd watches.
+//
+// Like the other optional interfaces, Watcher is found with a type
+// assertion. Providers implementing it report SupportsWatching in their
+// Capabilities.
+//
+// Example:
+//
+//	meta, err := p.Describe(ctx, ref)
+//	if err != nil {
+//	    return err
+//	}
+//	for {
+//	    meta, err = watcher.Watch(ctx, ref, meta.Version)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println("changed, now at version", meta.Version)
+//	}
+type Watcher interface {
+	// Watch blocks until the version of the secret differs from version,
+	// then returns its current metadata. Pass the Version from Describe; a
+	// missing secret has an empty version, so watching it waits for it to
+	// be created, and a deletion returns Metadata with Exists false.
+	//
+	// Watch returns ctx.Err() when ctx is cancelled first.
+	Watch(ctx context.Context, ref Reference, version string) (Metadata, error)
+}
+
+// Authenticator defines the interface for providers that can sign the user in
+// interactively, for example with a device code or a browser redirect.
+//
+// Like the other optional interfaces, Authenticator is found with a type
+// assertion. dsops login calls Login, and the provider saves the session
+// where later runs of dsops (and, where there is one, the vendor CLI) find
+// it. Resolve must keep working without Login for non-interactive
+// credentials such as CI tokens.
+type Authenticator interface {
+	// Login signs the user in, using prompt for anything the user has to
+	// see or type.
+	Login(ctx context.Context, prompt LoginPrompt) error
+}
+
+// LoginPrompt is how an Authenticator interacts with the user.
+type LoginPrompt interface {
+	// Visit asks the user to open url in a browser and, when code is not
+	// empty, to enter or confirm code there.
+	Visit(url, code string)
+
+	// ReadSecret asks for a value such as a password without echoing it.
+	ReadSecret(prompt string) (string, error)
+}
+
+// PageSecrets applies prefix filtering and offset pagination to a complete
+// listing. It is meant for providers whose APIs return all secrets at once or
+// do not expose continuation tokens. Secrets are sorted by key.
+func PageSecrets(secrets []SecretInfo, opts ListOptions) (ListResult, error) {
+	filtered := make([]SecretInfo, 0, len(secrets))
+	for _, s := range secrets {
+		if strings.HasPrefix(s.Key, opts.Prefix) {
+			filtered = append(filtered, s)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].Key < filtered[j].Key })
+
+	start := 0
+	if opts.PageToken != "" {
+		offset, err := strconv.Atoi(opts.PageToken)
+		if err != nil || offset < 0 {
+			return ListResult{}, fmt.Errorf("invalid page token %q", opts.PageToken)
+		}
+		start = offset
+	}
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+
+	end := len(filtered)
+	if opts.PageSize > 0 && start+opts.PageSize < end {
+		end = start + opts.PageSize
+	}
+
+	result := ListResult{Secrets: filtered[start:end]}
+	if end < len(filtered) {
+		result.NextPageToken = strconv.Itoa(end)
+	}
+	return result, nil
+}
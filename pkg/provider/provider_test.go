@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -95,6 +96,60 @@ func TestNotFoundErrorTypeChecking(t *testing.T) {
 	}
 }
 
+// TestIsNotFound tests that IsNotFound sees through wrapping and pointer forms
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	base := NotFoundError{Provider: "test-provider", Key: "missing-secret"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "value", err: base, want: true},
+		{name: "pointer", err: &base, want: true},
+		{name: "wrapped value", err: fmt.Errorf("resolve: %w", base), want: true},
+		{name: "wrapped pointer", err: fmt.Errorf("resolve: %w", &base), want: true},
+		{name: "auth error", err: AuthError{Provider: "test-provider"}, want: false},
+		{name: "plain error", err: errors.New(base.Error()), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUnavailableError tests the UnavailableError error type and IsUnavailable
+func TestUnavailableError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("503 Service Unavailable")
+	err := UnavailableError{Provider: "vault", Err: cause}
+
+	if got, want := err.Error(), "vault is unavailable: 503 Service Unavailable"; got != want {
+		t.Errorf("UnavailableError.Error() = %q, want %q", got, want)
+	}
+	if got, want := (UnavailableError{Provider: "vault"}).Error(), "vault is unavailable"; got != want {
+		t.Errorf("UnavailableError.Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("UnavailableError should unwrap to its cause")
+	}
+
+	if !IsUnavailable(err) || !IsUnavailable(&err) || !IsUnavailable(fmt.Errorf("resolve: %w", &err)) {
+		t.Error("IsUnavailable should identify value, pointer and wrapped forms")
+	}
+	if IsUnavailable(cause) || IsUnavailable(NotFoundError{}) || IsUnavailable(nil) {
+		t.Error("IsUnavailable should not identify other errors")
+	}
+}
+
 // TestAuthError tests the AuthError error type
 func TestAuthError(t *testing.T) {
 	t.Parallel()
@@ -928,3 +983,57 @@ func (p *SimpleTestProvider) Validate(ctx context.Context) error {
 }
 
 var _ Provider = (*SimpleTestProvider)(nil)
+
+// TestPageSecrets tests prefix filtering and offset pagination
+func TestPageSecrets(t *testing.T) {
+	t.Parallel()
+
+	secrets := []SecretInfo{
+		{Key: "app/db"},
+		{Key: "other/token"},
+		{Key: "app/api"},
+		{Key: "app/cache"},
+	}
+
+	keys := func(result ListResult) []string {
+		out := make([]string, len(result.Secrets))
+		for i, s := range result.Secrets {
+			out[i] = s.Key
+		}
+		return out
+	}
+
+	first, err := PageSecrets(secrets, ListOptions{Prefix: "app/", PageSize: 2})
+	if err != nil {
+		t.Fatalf("PageSecrets() error = %v", err)
+	}
+	if got := keys(first); len(got) != 2 || got[0] != "app/api" || got[1] != "app/cache" {
+		t.Errorf("first page = %v, want [app/api app/cache]", got)
+	}
+	if first.NextPageToken == "" {
+		t.Fatal("first page should have a next page token")
+	}
+
+	second, err := PageSecrets(secrets, ListOptions{Prefix: "app/", PageSize: 2, PageToken: first.NextPageToken})
+	if err != nil {
+		t.Fatalf("PageSecrets() error = %v", err)
+	}
+	if got := keys(second); len(got) != 1 || got[0] != "app/db" {
+		t.Errorf("second page = %v, want [app/db]", got)
+	}
+	if second.NextPageToken != "" {
+		t.Errorf("last page token = %q, want empty", second.NextPageToken)
+	}
+
+	all, err := PageSecrets(secrets, ListOptions{})
+	if err != nil {
+		t.Fatalf("PageSecrets() error = %v", err)
+	}
+	if len(all.Secrets) != 4 || all.NextPageToken != "" {
+		t.Errorf("unpaged listing = %v (token %q), want 4 secrets", keys(all), all.NextPageToken)
+	}
+
+	if _, err := PageSecrets(secrets, ListOptions{PageToken: "bogus"}); err == nil {
+		t.Error("PageSecrets() with invalid token should fail")
+	}
+}
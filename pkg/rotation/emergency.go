@@ -0,0 +1,387 @@
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/rotation/notifications"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// EmergencyRotator rotates compromised secrets. It creates a new value in the
+// secret store, immediately revokes every old version without any grace
+// period, and pages on-call through PagerDuty when a pager is configured.
+// Old versions are destroyed through provider.VersionDestroyer when the
+// provider implements it, so a soft-deleted version cannot be restored, and
+// retired through provider.Rotator.DeprecateVersion otherwise.
+//
+// Old versions are enumerated with provider.VersionLister, so only providers
+// implementing it are supported; anything else could leave a compromised
+// version readable.
+type EmergencyRotator struct {
+	logger    *logging.Logger
+	generator *RandomRotator
+	providers map[string]provider.Provider
+	pager     notifications.NotificationProvider
+	mu        sync.RWMutex
+}
+
+// NewEmergencyRotator creates a new emergency rotation strategy
+func NewEmergencyRotator(logger *logging.Logger) *EmergencyRotator {
+	return &EmergencyRotator{
+		logger:    logger,
+		generator: NewRandomRotator(logger),
+		providers: make(map[string]provider.Provider),
+	}
+}
+
+// Name returns the strategy name
+func (r *EmergencyRotator) Name() string {
+	return string(StrategyEmergency)
+}
+
+// SetProviders sets the secret store providers used to write and revoke versions
+func (r *EmergencyRotator) SetProviders(providers map[string]provider.Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers = providers
+}
+
+// SetPager sets the notification provider used to page on-call, typically a
+// notifications.PagerDutyProvider
+func (r *EmergencyRotator) SetPager(pager notifications.NotificationProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pager = pager
+}
+
+// SupportsSecret checks if the secret's provider can create, list and revoke versions
+func (r *EmergencyRotator) SupportsSecret(ctx context.Context, secret SecretInfo) bool {
+	_, err := r.rotatorFor(secret)
+	return err == nil
+}
+
+// Rotate creates a new secret version and revokes all old versions immediately
+func (r *EmergencyRotator) Rotate(ctx context.Context, request RotationRequest) (*RotationResult, error) {
+	auditTrail := []AuditEntry{
+		createAuditEntry("emergency_rotation_started", "emergency_rotator", "warning",
+			"Starting emergency rotation of compromised secret", map[string]interface{}{
+				"secret_key": logging.Secret(request.Secret.Key),
+				"dry_run":    request.DryRun,
+			}),
+	}
+
+	fail := func(err error) (*RotationResult, error) {
+		auditTrail = append(auditTrail, createAuditEntry("emergency_rotation_failed", "emergency_rotator", "error",
+			"Emergency rotation failed", map[string]interface{}{"error": err.Error()}))
+		return &RotationResult{
+			Secret:     request.Secret,
+			Status:     StatusFailed,
+			Error:      err.Error(),
+			AuditTrail: auditTrail,
+		}, err
+	}
+
+	rotator, err := r.rotatorFor(request.Secret)
+	if err != nil {
+		return fail(err)
+	}
+	lister := rotator.(provider.VersionLister)
+
+	ref := request.Secret.ProviderRef
+	ref.Version = ""
+
+	// Collect the versions to revoke: every readable version plus any listed explicitly
+	oldVersions := emergencyOldVersions(request)
+	listed, err := lister.ListVersions(ctx, ref)
+	if err != nil && !provider.IsNotFound(err) {
+		return fail(fmt.Errorf("failed to list versions to revoke: %w", err))
+	}
+	for _, version := range listed {
+		oldVersions = appendUnique(oldVersions, version)
+	}
+
+	r.logger.Warn("Starting emergency rotation for %s (%d old version(s) to revoke)",
+		logging.Secret(request.Secret.Key), len(oldVersions))
+
+	newValue, err := r.generator.generateRandomValue(request.NewValue)
+	if err != nil {
+		return fail(fmt.Errorf("failed to generate new value: %w", err))
+	}
+
+	if request.DryRun {
+		auditTrail = append(auditTrail, createAuditEntry("dry_run_simulation", "emergency_rotator", "info",
+			fmt.Sprintf("Would create a new version and revoke %d old version(s)", len(oldVersions)), nil))
+		return &RotationResult{
+			Secret:     request.Secret,
+			Status:     StatusPending,
+			AuditTrail: auditTrail,
+		}, nil
+	}
+
+	// Page before touching the store so on-call is engaged even if rotation fails
+	var warnings []string
+	if err := r.page(ctx, request, oldVersions); err != nil {
+		r.logger.Warn("Failed to page on-call for emergency rotation: %v", err)
+		warnings = append(warnings, fmt.Sprintf("failed to page on-call: %v", err))
+	} else {
+		auditTrail = append(auditTrail, createAuditEntry("on_call_paged", "emergency_rotator", "info",
+			"Paged on-call for emergency rotation", nil))
+	}
+
+	meta := map[string]string{
+		"rotation_strategy": string(StrategyEmergency),
+		"rotated_at":        time.Now().UTC().Format(time.RFC3339),
+	}
+	if incidentID := request.Secret.Metadata["incident_id"]; incidentID != "" {
+		meta["incident_id"] = incidentID
+	}
+
+	newVersion, err := rotator.CreateNewVersion(ctx, ref, newValue, meta)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create new version: %w", err))
+	}
+
+	auditTrail = append(auditTrail, createAuditEntry("new_version_created", "emergency_rotator", "info",
+		"Created new secret version", map[string]interface{}{"version": newVersion}))
+
+	// No grace period: every old version is revoked right away
+	if request.Secret.Constraints != nil && request.Secret.Constraints.GracePeriod > 0 {
+		auditTrail = append(auditTrail, createAuditEntry("grace_period_skipped", "emergency_rotator", "warning",
+			fmt.Sprintf("Skipping configured grace period of %s", request.Secret.Constraints.GracePeriod), nil))
+	}
+
+	revoke := rotator.DeprecateVersion
+	if destroyer, ok := rotator.(provider.VersionDestroyer); ok {
+		revoke = destroyer.DestroyVersion
+	}
+
+	var revoked, failed []string
+	unrevoked := make(map[string]bool)
+	for _, version := range oldVersions {
+		if version == newVersion {
+			continue
+		}
+		if err := revoke(ctx, ref, version); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", version, err))
+			unrevoked[version] = true
+			continue
+		}
+		revoked = append(revoked, version)
+	}
+
+	// Anything still readable apart from the new version is a compromised
+	// version left active, including versions written during the rotation
+	remaining, err := lister.ListVersions(ctx, ref)
+	if err != nil {
+		failed = append(failed, fmt.Sprintf("could not confirm revocation: %v", err))
+	}
+	for _, version := range remaining {
+		if version != newVersion && !unrevoked[version] {
+			failed = append(failed, fmt.Sprintf("%s: still active after revocation", version))
+		}
+	}
+
+	auditTrail = append(auditTrail, createAuditEntry("old_versions_revoked", "emergency_rotator", "info",
+		fmt.Sprintf("Revoked %d old version(s)", len(revoked)), map[string]interface{}{"versions": revoked}))
+
+	newRef := &SecretReference{
+		Provider: request.Secret.Provider,
+		Key:      ref.Key,
+		Version:  newVersion,
+		Metadata: map[string]string{
+			"strategy":         string(StrategyEmergency),
+			"revoked_versions": strings.Join(revoked, ","),
+		},
+	}
+
+	var oldRef *SecretReference
+	if len(oldVersions) > 0 {
+		oldRef = &SecretReference{
+			Provider: request.Secret.Provider,
+			Key:      ref.Key,
+			Version:  oldVersions[len(oldVersions)-1],
+		}
+	}
+
+	// A compromised version that is still valid means the rotation did not achieve its goal
+	if len(failed) > 0 {
+		err := fmt.Errorf("new version %s created but failed to revoke: %s", newVersion, strings.Join(failed, "; "))
+		result, _ := fail(err)
+		result.NewSecretRef = newRef
+		result.OldSecretRef = oldRef
+		result.Warnings = warnings
+		return result, err
+	}
+
+	rotatedAt := time.Now()
+	auditTrail = append(auditTrail, createAuditEntry("emergency_rotation_completed", "emergency_rotator", "info",
+		"Emergency rotation completed", nil))
+
+	r.logger.Info("Emergency rotation completed for %s", logging.Secret(request.Secret.Key))
+
+	return &RotationResult{
+		Secret:       request.Secret,
+		Status:       StatusCompleted,
+		NewSecretRef: newRef,
+		OldSecretRef: oldRef,
+		RotatedAt:    &rotatedAt,
+		Warnings:     warnings,
+		AuditTrail:   auditTrail,
+	}, nil
+}
+
+// Verify checks that the new version can be resolved from the secret store
+func (r *EmergencyRotator) Verify(ctx context.Context, request VerificationRequest) error {
+	r.mu.RLock()
+	p, ok := r.providers[request.Secret.Provider]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("provider '%s' not configured", request.Secret.Provider)
+	}
+
+	ref := request.Secret.ProviderRef
+	ref.Version = request.NewSecretRef.Version
+
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve new version: %w", err)
+	}
+	if secret.Value == "" {
+		return fmt.Errorf("new version %s is empty", request.NewSecretRef.Version)
+	}
+
+	return nil
+}
+
+// Rollback is not possible because old versions were revoked
+func (r *EmergencyRotator) Rollback(ctx context.Context, request RollbackRequest) error {
+	return fmt.Errorf("emergency rotations cannot be rolled back: old versions of %s were revoked", request.Secret.Key)
+}
+
+// GetStatus returns the rotation status for the secret
+func (r *EmergencyRotator) GetStatus(ctx context.Context, secret SecretInfo) (*RotationStatusInfo, error) {
+	if _, err := r.rotatorFor(secret); err != nil {
+		return &RotationStatusInfo{
+			Status:    StatusPending,
+			CanRotate: false,
+			Reason:    err.Error(),
+		}, nil
+	}
+
+	return &RotationStatusInfo{
+		Status:    StatusPending,
+		CanRotate: true,
+		Reason:    "Provider supports version creation and revocation",
+	}, nil
+}
+
+// rotatorFor returns the provider.Rotator for the secret's provider
+func (r *EmergencyRotator) rotatorFor(secret SecretInfo) (provider.Rotator, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[secret.Provider]
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' not configured for emergency rotation", secret.Provider)
+	}
+
+	rotator, ok := p.(provider.Rotator)
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' does not support creating and revoking versions", secret.Provider)
+	}
+	if _, ok := p.(provider.VersionLister); !ok {
+		return nil, fmt.Errorf("provider '%s' cannot list secret versions, so old versions cannot all be revoked; revoke them in the secret store instead", secret.Provider)
+	}
+
+	return rotator, nil
+}
+
+// page sends an emergency rotation event to the configured pager
+func (r *EmergencyRotator) page(ctx context.Context, request RotationRequest, oldVersions []string) error {
+	r.mu.RLock()
+	pager := r.pager
+	r.mu.RUnlock()
+
+	if pager == nil {
+		return nil
+	}
+
+	environment := request.Secret.Metadata["environment"]
+	if env, ok := request.Config["environment"].(string); ok && env != "" {
+		environment = env
+	}
+
+	metadata := map[string]string{
+		"secret_key":       request.Secret.Key,
+		"provider":         request.Secret.Provider,
+		"revoking":         strings.Join(oldVersions, ","),
+		"rotation_id":      fmt.Sprintf("emergency-%s-%d", request.Secret.Key, time.Now().Unix()),
+		"compromised":      "true",
+		"grace_period":     "none",
+		"rotation_trigger": "emergency",
+	}
+	if incidentID := request.Secret.Metadata["incident_id"]; incidentID != "" {
+		metadata["incident_id"] = incidentID
+	}
+
+	event := notifications.RotationEvent{
+		Type:        notifications.EventTypeEmergencyRotation,
+		Service:     request.Secret.Key,
+		Environment: environment,
+		Strategy:    string(StrategyEmergency),
+		Severity:    "critical",
+		Summary:     "Compromised secret is being rotated; all old versions will be revoked",
+		Timestamp:   time.Now(),
+		InitiatedBy: os.Getenv("USER"),
+		Metadata:    metadata,
+	}
+
+	if !pager.SupportsEvent(event.Type) {
+		return nil
+	}
+
+	return pager.Send(ctx, event)
+}
+
+// emergencyOldVersions returns versions listed in request.Config["revoke_versions"]
+func emergencyOldVersions(request RotationRequest) []string {
+	var versions []string
+
+	switch v := request.Config["revoke_versions"].(type) {
+	case []string:
+		for _, version := range v {
+			versions = appendUnique(versions, version)
+		}
+	case []interface{}:
+		for _, version := range v {
+			if s, ok := version.(string); ok {
+				versions = appendUnique(versions, s)
+			}
+		}
+	case string:
+		for _, version := range strings.Split(v, ",") {
+			versions = appendUnique(versions, strings.TrimSpace(version))
+		}
+	}
+
+	return versions
+}
+
+// appendUnique appends value if it is non-empty and not already present
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
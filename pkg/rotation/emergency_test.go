@@ -0,0 +1,428 @@
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/internal/rotation/notifications"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// versionedFakeProvider is an in-memory provider.Provider, provider.Rotator
+// and provider.VersionLister that keeps every version of every key
+type versionedFakeProvider struct {
+	mu          sync.Mutex
+	versions    map[string][]string // key -> values, version N is index N-1
+	deprecated  map[string][]string // key -> deprecated versions
+	deprecateFn func(version string) error
+	createFn    func(key string) error
+}
+
+// rotatorOnlyProvider hides the VersionLister of the wrapped provider
+type rotatorOnlyProvider struct {
+	provider.Provider
+	provider.Rotator
+}
+
+// destroyingFakeProvider adds provider.VersionDestroyer to the wrapped
+// provider, recording which versions were destroyed
+type destroyingFakeProvider struct {
+	*versionedFakeProvider
+	destroyed []string
+}
+
+func (f *destroyingFakeProvider) DestroyVersion(ctx context.Context, ref provider.Reference, version string) error {
+	f.destroyed = append(f.destroyed, version)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deprecated[ref.Key] = append(f.deprecated[ref.Key], version)
+	return nil
+}
+
+func newVersionedFakeProvider() *versionedFakeProvider {
+	return &versionedFakeProvider{
+		versions:   make(map[string][]string),
+		deprecated: make(map[string][]string),
+	}
+}
+
+func (f *versionedFakeProvider) put(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.versions[key] = append(f.versions[key], value)
+}
+
+func (f *versionedFakeProvider) Name() string { return "fake" }
+
+func (f *versionedFakeProvider) Resolve(ctx context.Context, ref provider.Reference) (provider.SecretValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := f.versions[ref.Key]
+	if len(values) == 0 {
+		return provider.SecretValue{}, provider.NotFoundError{Provider: "fake", Key: ref.Key}
+	}
+
+	idx := len(values)
+	if ref.Version != "" {
+		if _, err := fmt.Sscanf(ref.Version, "v%d", &idx); err != nil || idx < 1 || idx > len(values) {
+			return provider.SecretValue{}, provider.NotFoundError{Provider: "fake", Key: ref.Key}
+		}
+	}
+
+	return provider.SecretValue{Value: values[idx-1], Version: fmt.Sprintf("v%d", idx)}, nil
+}
+
+func (f *versionedFakeProvider) Describe(ctx context.Context, ref provider.Reference) (provider.Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := f.versions[ref.Key]
+	if len(values) == 0 {
+		return provider.Metadata{Exists: false}, nil
+	}
+	return provider.Metadata{Exists: true, Version: fmt.Sprintf("v%d", len(values))}, nil
+}
+
+func (f *versionedFakeProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{SupportsVersioning: true}
+}
+
+func (f *versionedFakeProvider) Validate(ctx context.Context) error { return nil }
+
+func (f *versionedFakeProvider) CreateNewVersion(ctx context.Context, ref provider.Reference, newValue []byte, meta map[string]string) (string, error) {
+	if f.createFn != nil {
+		if err := f.createFn(ref.Key); err != nil {
+			return "", err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.versions[ref.Key] = append(f.versions[ref.Key], string(newValue))
+	return fmt.Sprintf("v%d", len(f.versions[ref.Key])), nil
+}
+
+func (f *versionedFakeProvider) DeprecateVersion(ctx context.Context, ref provider.Reference, version string) error {
+	if f.deprecateFn != nil {
+		if err := f.deprecateFn(version); err != nil {
+			return err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deprecated[ref.Key] = append(f.deprecated[ref.Key], version)
+	return nil
+}
+
+func (f *versionedFakeProvider) ListVersions(ctx context.Context, ref provider.Reference) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := f.versions[ref.Key]
+	if len(values) == 0 {
+		return nil, &provider.NotFoundError{Provider: "fake", Key: ref.Key}
+	}
+
+	var versions []string
+	for i := range values {
+		version := fmt.Sprintf("v%d", i+1)
+		deprecated := false
+		for _, d := range f.deprecated[ref.Key] {
+			if d == version {
+				deprecated = true
+			}
+		}
+		if !deprecated {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+func (f *versionedFakeProvider) GetRotationMetadata(ctx context.Context, ref provider.Reference) (provider.RotationMetadata, error) {
+	return provider.RotationMetadata{SupportsRotation: true, SupportsVersioning: true}, nil
+}
+
+func emergencyRequest() RotationRequest {
+	return RotationRequest{
+		Secret: SecretInfo{
+			Key:         "DB_PASSWORD",
+			Provider:    "vault",
+			ProviderRef: provider.Reference{Provider: "vault", Key: "prod/db"},
+			SecretType:  SecretTypePassword,
+			Metadata:    map[string]string{"incident_id": "INC-1", "compromised": "true"},
+			Constraints: &RotationConstraints{GracePeriod: time.Hour},
+		},
+		Strategy: string(StrategyEmergency),
+		Config:   map[string]interface{}{"environment": "production"},
+	}
+}
+
+func TestEmergencyRotator_SupportsSecret(t *testing.T) {
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	secret := emergencyRequest().Secret
+
+	if rotator.SupportsSecret(context.Background(), secret) {
+		t.Error("expected no support without providers")
+	}
+
+	rotator.SetProviders(map[string]provider.Provider{"vault": newVersionedFakeProvider()})
+	if !rotator.SupportsSecret(context.Background(), secret) {
+		t.Error("expected support for a provider implementing Rotator and VersionLister")
+	}
+}
+
+func TestEmergencyRotator_RevokesEveryVersion(t *testing.T) {
+	fake := newVersionedFakeProvider()
+	for _, value := range []string{"leaked-v1", "leaked-v2", "leaked-v3", "leaked-v4"} {
+		fake.put("prod/db", value)
+	}
+	fake.deprecated["prod/db"] = []string{"v2"}
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	result, err := rotator.Rotate(context.Background(), emergencyRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.NewSecretRef.Metadata["revoked_versions"]; got != "v1,v3,v4" {
+		t.Errorf("expected every readable old version revoked, got %q", got)
+	}
+
+	remaining, _ := fake.ListVersions(context.Background(), provider.Reference{Key: "prod/db"})
+	if len(remaining) != 1 || remaining[0] != "v5" {
+		t.Errorf("expected only the new version to remain, got %v", remaining)
+	}
+}
+
+func TestEmergencyRotator_DestroysVersions(t *testing.T) {
+	fake := &destroyingFakeProvider{versionedFakeProvider: newVersionedFakeProvider()}
+	fake.put("prod/db", "leaked-v1")
+	fake.put("prod/db", "leaked-v2")
+	fake.deprecateFn = func(version string) error {
+		t.Errorf("version %s was deprecated instead of destroyed", version)
+		return nil
+	}
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	if _, err := rotator.Rotate(context.Background(), emergencyRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fake.destroyed, ",") != "v1,v2" {
+		t.Errorf("expected old versions destroyed, got %v", fake.destroyed)
+	}
+}
+
+func TestEmergencyRotator_RequiresVersionLister(t *testing.T) {
+	fake := newVersionedFakeProvider()
+	fake.put("prod/db", "leaked")
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": rotatorOnlyProvider{fake, fake}})
+
+	if rotator.SupportsSecret(context.Background(), emergencyRequest().Secret) {
+		t.Error("expected no support for a provider that cannot list versions")
+	}
+
+	result, err := rotator.Rotate(context.Background(), emergencyRequest())
+	if err == nil || !strings.Contains(err.Error(), "cannot list secret versions") {
+		t.Fatalf("expected version listing error, got %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failed status, got %s", result.Status)
+	}
+	if len(fake.versions["prod/db"]) != 1 {
+		t.Error("no new version may be written when old versions cannot be revoked")
+	}
+}
+
+func TestEmergencyRotator_Rotate(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		pages []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		pages = append(pages, payload)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pager := notifications.NewPagerDutyProvider(notifications.PagerDutyConfig{
+		IntegrationKey: "test-key",
+		Severity:       "critical",
+	})
+	pager.SetAPIURL(server.URL)
+
+	fake := newVersionedFakeProvider()
+	fake.put("prod/db", "leaked-v1")
+	fake.put("prod/db", "leaked-v2")
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+	rotator.SetPager(pager)
+
+	request := emergencyRequest()
+	request.Config["revoke_versions"] = []string{"v1"}
+
+	result, err := rotator.Rotate(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != StatusCompleted {
+		t.Fatalf("expected completed, got %s (%s)", result.Status, result.Error)
+	}
+	if result.NewSecretRef == nil || result.NewSecretRef.Version != "v3" {
+		t.Fatalf("expected new version v3, got %+v", result.NewSecretRef)
+	}
+	if got := result.NewSecretRef.Metadata["revoked_versions"]; got != "v1,v2" {
+		t.Errorf("expected v1,v2 revoked, got %q", got)
+	}
+	if len(fake.deprecated["prod/db"]) != 2 {
+		t.Errorf("expected 2 deprecated versions, got %v", fake.deprecated["prod/db"])
+	}
+
+	// Grace period must be skipped and recorded
+	foundSkip := false
+	for _, entry := range result.AuditTrail {
+		if entry.Action == "grace_period_skipped" {
+			foundSkip = true
+		}
+	}
+	if !foundSkip {
+		t.Error("expected grace_period_skipped audit entry")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pages) != 1 {
+		t.Fatalf("expected 1 page, got %d", len(pages))
+	}
+	if pages[0]["event_action"] != "trigger" {
+		t.Errorf("expected trigger action, got %v", pages[0]["event_action"])
+	}
+	payload, _ := pages[0]["payload"].(map[string]interface{})
+	if payload["severity"] != "critical" {
+		t.Errorf("expected critical severity, got %v", payload["severity"])
+	}
+	if summary, _ := payload["summary"].(string); !strings.Contains(summary, "emergency rotation") {
+		t.Errorf("expected emergency rotation summary, got %q", summary)
+	}
+	details, _ := payload["custom_details"].(map[string]interface{})
+	if details["event_type"] != string(notifications.EventTypeEmergencyRotation) {
+		t.Errorf("expected emergency_rotation event, got %v", details["event_type"])
+	}
+	if details["incident_id"] != "INC-1" {
+		t.Errorf("expected incident_id in page, got %v", details["incident_id"])
+	}
+
+	// Verify resolves the new version
+	verifyErr := rotator.Verify(context.Background(), VerificationRequest{
+		Secret:       request.Secret,
+		NewSecretRef: *result.NewSecretRef,
+	})
+	if verifyErr != nil {
+		t.Errorf("unexpected verify error: %v", verifyErr)
+	}
+}
+
+func TestEmergencyRotator_RevocationFailure(t *testing.T) {
+	fake := newVersionedFakeProvider()
+	fake.put("prod/db", "leaked")
+	fake.deprecateFn = func(version string) error {
+		return errors.New("permission denied")
+	}
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	result, err := rotator.Rotate(context.Background(), emergencyRequest())
+	if err == nil {
+		t.Fatal("expected error when revocation fails")
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failed status, got %s", result.Status)
+	}
+	if result.NewSecretRef == nil || result.NewSecretRef.Version != "v2" {
+		t.Errorf("expected new version to be reported, got %+v", result.NewSecretRef)
+	}
+}
+
+func TestEmergencyRotator_DryRun(t *testing.T) {
+	fake := newVersionedFakeProvider()
+	fake.put("prod/db", "leaked")
+
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	request := emergencyRequest()
+	request.DryRun = true
+
+	result, err := rotator.Rotate(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPending {
+		t.Errorf("expected pending, got %s", result.Status)
+	}
+	if len(fake.versions["prod/db"]) != 1 || len(fake.deprecated["prod/db"]) != 0 {
+		t.Error("dry run must not modify the store")
+	}
+}
+
+func TestEmergencyRotator_Rollback(t *testing.T) {
+	rotator := NewEmergencyRotator(logging.New(false, true))
+	err := rotator.Rollback(context.Background(), RollbackRequest{Secret: emergencyRequest().Secret})
+	if err == nil {
+		t.Error("expected rollback to be refused")
+	}
+}
+
+func TestEngine_SetProvidersPropagates(t *testing.T) {
+	t.Setenv("DSOPS_ROTATION_DIR", t.TempDir())
+
+	logger := logging.New(false, true)
+	engine := NewRotationEngine(logger)
+	fake := newVersionedFakeProvider()
+	fake.put("prod/db", "leaked")
+
+	// Providers set before and after registration both reach the strategy
+	emergency := NewEmergencyRotator(logger)
+	if err := engine.RegisterStrategy(emergency); err != nil {
+		t.Fatal(err)
+	}
+	engine.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	oauth := NewOAuthRefreshRotator(logger)
+	if err := engine.RegisterStrategy(oauth); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := engine.Rotate(context.Background(), emergencyRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusCompleted {
+		t.Errorf("expected completed, got %s (%s)", result.Status, result.Error)
+	}
+
+	if _, _, err := oauth.providerFor(SecretInfo{Provider: "vault"}); err != nil {
+		t.Errorf("expected providers on strategy registered after SetProviders: %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/systmms/dsops/internal/rotation/notifications"
 	rotationstorage "github.com/systmms/dsops/internal/rotation/storage"
 	"github.com/systmms/dsops/internal/validation"
+	"github.com/systmms/dsops/pkg/provider"
 )
 
 // DefaultRotationEngine implements the RotationEngine interface
@@ -21,6 +22,7 @@ type DefaultRotationEngine struct {
 	storage           RotationStorage
 	persistentStorage rotationstorage.Storage
 	repository        *dsopsdata.Repository
+	providers         map[string]provider.Provider
 	notifier          *notifications.Manager
 	metrics           *health.RotationMetrics
 	logger            *logging.Logger
@@ -86,6 +88,23 @@ func (e *DefaultRotationEngine) SetRepository(repository *dsopsdata.Repository)
 	e.logger.Debug("Schema repository updated with %d service types", len(repository.ServiceTypes))
 }
 
+// SetProviders sets the secret store providers used by provider-aware strategies
+func (e *DefaultRotationEngine) SetProviders(providers map[string]provider.Provider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.providers = providers
+
+	// Propagate providers to provider-aware strategies
+	for name, strategy := range e.strategies {
+		if providerAware, ok := strategy.(ProviderAwareRotator); ok {
+			providerAware.SetProviders(providers)
+			e.logger.Debug("Updated providers for strategy: %s", name)
+		}
+	}
+
+	e.logger.Debug("Configured %d providers for rotation engine", len(providers))
+}
+
 // RegisterStrategy adds a rotation strategy
 func (e *DefaultRotationEngine) RegisterStrategy(strategy SecretValueRotator) error {
 	e.mu.Lock()
@@ -106,6 +125,14 @@ func (e *DefaultRotationEngine) RegisterStrategy(strategy SecretValueRotator) er
 		}
 	}
 
+	// Set providers on provider-aware strategies
+	if e.providers != nil {
+		if providerAware, ok := strategy.(ProviderAwareRotator); ok {
+			providerAware.SetProviders(e.providers)
+			e.logger.Debug("Set providers for newly registered strategy: %s", name)
+		}
+	}
+
 	e.logger.Debug("Registered rotation strategy: %s", name)
 	return nil
 }
@@ -208,7 +235,11 @@ func (e *DefaultRotationEngine) Rotate(ctx context.Context, request RotationRequ
 	}
 
 	// Check if the strategy supports this secret
-	if !strategy.SupportsSecret(ctx, request.Secret) {
+	supported := strategy.SupportsSecret(ctx, request.Secret)
+	if supporter, ok := strategy.(RequestSupporter); ok {
+		supported = supporter.SupportsRequest(ctx, request)
+	}
+	if !supported {
 		return &RotationResult{
 			Secret: request.Secret,
 			Status: StatusFailed,
@@ -598,6 +598,40 @@ type SchemaAwareRotator interface {
 	SetRepository(repository *dsopsdata.Repository)
 }
 
+// ProviderAwareRotator is implemented by rotators that read and write secret
+// values directly in the secret stores configured in dsops.yaml.
+//
+// The rotation engine passes its secret store providers to every registered
+// rotator implementing this interface, in the same way SchemaAwareRotator
+// receives the dsops-data repository. Rotators look up the provider for a
+// secret by SecretInfo.Provider and type-assert it to provider.Rotator when
+// they need to create or deprecate versions.
+//
+// Example:
+//
+//	engine := NewRotationEngine(logger)
+//	engine.SetProviders(map[string]provider.Provider{
+//	    "aws-prod": awsProvider,
+//	})
+//	engine.RegisterStrategy(NewEmergencyRotator(logger))
+type ProviderAwareRotator interface {
+	// SetProviders sets the secret store providers keyed by their configured name.
+	SetProviders(providers map[string]provider.Provider)
+}
+
+// RequestSupporter is implemented by rotators whose support for a secret
+// depends on the rotation request as well as the secret, typically because
+// settings may be given in RotationRequest.Config instead of the secret's
+// metadata.
+//
+// When a rotator implements it, the rotation engine checks SupportsRequest
+// instead of SupportsSecret before rotating. SupportsSecret is still used when
+// the engine picks a strategy for a secret on its own.
+type RequestSupporter interface {
+	// SupportsRequest determines if this rotator can carry out the request.
+	SupportsRequest(ctx context.Context, request RotationRequest) bool
+}
+
 // SecretInfo contains comprehensive information about the secret to be rotated.
 //
 // This structure provides all the context needed for rotation strategies to:
@@ -0,0 +1,357 @@
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+// OAuthRefreshRotator rotates OAuth access tokens by exchanging a stored
+// refresh token at the configured token endpoint (RFC 6749 section 6) and
+// storing the returned access token, plus the refresh token when the
+// authorization server rotates it, as new versions in the secret store.
+//
+// Settings are read from RotationRequest.Config, falling back to the secret's
+// metadata in dsops.yaml:
+//   - token_url: token endpoint URL (required)
+//   - refresh_token_key: provider key holding the refresh token (required)
+//   - client_id: OAuth client ID (optional)
+//   - client_secret_key: provider key holding the client secret (optional)
+//   - scope: space separated scopes to request (optional)
+type OAuthRefreshRotator struct {
+	logger    *logging.Logger
+	client    *http.Client
+	providers map[string]provider.Provider
+	mu        sync.RWMutex
+}
+
+// oauthTokenResponse is the token endpoint response
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	TokenType        string `json:"token_type,omitempty"`
+	ExpiresIn        int64  `json:"expires_in,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// NewOAuthRefreshRotator creates a new OAuth refresh rotation strategy
+func NewOAuthRefreshRotator(logger *logging.Logger) *OAuthRefreshRotator {
+	return &OAuthRefreshRotator{
+		logger: logger,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		providers: make(map[string]provider.Provider),
+	}
+}
+
+// Name returns the strategy name
+func (o *OAuthRefreshRotator) Name() string {
+	return string(StrategyOAuthRefresh)
+}
+
+// SetProviders sets the secret store providers holding the tokens
+func (o *OAuthRefreshRotator) SetProviders(providers map[string]provider.Provider) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.providers = providers
+}
+
+// SupportsSecret checks if the secret's metadata has a token endpoint, a
+// refresh token reference and a provider that can store new versions
+func (o *OAuthRefreshRotator) SupportsSecret(ctx context.Context, secret SecretInfo) bool {
+	return o.SupportsRequest(ctx, RotationRequest{Secret: secret})
+}
+
+// SupportsRequest is SupportsSecret with the settings in the request Config
+// taking precedence over the secret's metadata
+func (o *OAuthRefreshRotator) SupportsRequest(ctx context.Context, request RotationRequest) bool {
+	if oauthSetting(request, "token_url") == "" || oauthSetting(request, "refresh_token_key") == "" {
+		return false
+	}
+	_, _, err := o.providerFor(request.Secret)
+	return err == nil
+}
+
+// Rotate exchanges the refresh token and stores the new token pair
+func (o *OAuthRefreshRotator) Rotate(ctx context.Context, request RotationRequest) (*RotationResult, error) {
+	auditTrail := []AuditEntry{
+		createAuditEntry("oauth_refresh_started", "oauth_refresh_rotator", "info",
+			"Starting OAuth token refresh", map[string]interface{}{
+				"secret_key": logging.Secret(request.Secret.Key),
+				"dry_run":    request.DryRun,
+			}),
+	}
+
+	fail := func(err error) (*RotationResult, error) {
+		auditTrail = append(auditTrail, createAuditEntry("oauth_refresh_failed", "oauth_refresh_rotator", "error",
+			"OAuth token refresh failed", map[string]interface{}{"error": err.Error()}))
+		return &RotationResult{
+			Secret:     request.Secret,
+			Status:     StatusFailed,
+			Error:      err.Error(),
+			AuditTrail: auditTrail,
+		}, err
+	}
+
+	tokenURL := oauthSetting(request, "token_url")
+	refreshKey := oauthSetting(request, "refresh_token_key")
+	if tokenURL == "" || refreshKey == "" {
+		return fail(fmt.Errorf("token_url and refresh_token_key are required for OAuth refresh"))
+	}
+
+	parsedURL, err := url.Parse(tokenURL)
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return fail(fmt.Errorf("invalid token_url: %s", tokenURL))
+	}
+
+	p, rotator, err := o.providerFor(request.Secret)
+	if err != nil {
+		return fail(err)
+	}
+
+	accessRef := request.Secret.ProviderRef
+	accessRef.Version = ""
+	refreshRef := provider.Reference{Provider: accessRef.Provider, Key: refreshKey}
+
+	refreshToken, err := p.Resolve(ctx, refreshRef)
+	if err != nil {
+		return fail(fmt.Errorf("failed to resolve refresh token: %w", err))
+	}
+	if refreshToken.Value == "" {
+		return fail(fmt.Errorf("refresh token at %s is empty", refreshKey))
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken.Value)
+	if clientID := oauthSetting(request, "client_id"); clientID != "" {
+		form.Set("client_id", clientID)
+	}
+	if scope := oauthSetting(request, "scope"); scope != "" {
+		form.Set("scope", scope)
+	}
+	if clientSecretKey := oauthSetting(request, "client_secret_key"); clientSecretKey != "" {
+		clientSecret, err := p.Resolve(ctx, provider.Reference{Provider: accessRef.Provider, Key: clientSecretKey})
+		if err != nil {
+			return fail(fmt.Errorf("failed to resolve client secret: %w", err))
+		}
+		form.Set("client_secret", clientSecret.Value)
+	}
+
+	if request.DryRun {
+		auditTrail = append(auditTrail, createAuditEntry("dry_run_simulation", "oauth_refresh_rotator", "info",
+			fmt.Sprintf("Would exchange refresh token at %s", parsedURL.Host), nil))
+		return &RotationResult{
+			Secret:     request.Secret,
+			Status:     StatusPending,
+			AuditTrail: auditTrail,
+		}, nil
+	}
+
+	o.logger.Info("Refreshing OAuth token for %s", logging.Secret(request.Secret.Key))
+
+	token, err := o.exchange(ctx, tokenURL, form)
+	if err != nil {
+		return fail(err)
+	}
+
+	auditTrail = append(auditTrail, createAuditEntry("token_exchanged", "oauth_refresh_rotator", "info",
+		"Exchanged refresh token for new access token", map[string]interface{}{
+			"token_type":      token.TokenType,
+			"refresh_rotated": token.RefreshToken != "",
+			"expires_in":      token.ExpiresIn,
+		}))
+
+	meta := map[string]string{
+		"rotation_strategy": string(StrategyOAuthRefresh),
+		"rotated_at":        time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// Authorization servers that rotate refresh tokens invalidate the old one,
+	// so the new refresh token is stored first. If that fails nothing has
+	// changed in the store; if storing the access token fails afterwards the
+	// next refresh still works.
+	var refreshVersion string
+	if token.RefreshToken != "" && token.RefreshToken != refreshToken.Value {
+		refreshVersion, err = rotator.CreateNewVersion(ctx, refreshRef, []byte(token.RefreshToken), meta)
+		if err != nil {
+			return fail(fmt.Errorf("failed to store rotated refresh token: %w", err))
+		}
+	}
+
+	newVersion, err := rotator.CreateNewVersion(ctx, accessRef, []byte(token.AccessToken), meta)
+	if err != nil {
+		if refreshVersion != "" {
+			err = fmt.Errorf("stored rotated refresh token (version %s) but failed to store new access token; run the refresh again: %w", refreshVersion, err)
+		} else {
+			err = fmt.Errorf("failed to store new access token: %w", err)
+		}
+		return fail(err)
+	}
+
+	newRef := &SecretReference{
+		Provider: request.Secret.Provider,
+		Key:      accessRef.Key,
+		Version:  newVersion,
+		Metadata: map[string]string{
+			"strategy": string(StrategyOAuthRefresh),
+		},
+	}
+	if refreshVersion != "" {
+		newRef.Metadata["refresh_token_version"] = refreshVersion
+	}
+
+	rotatedAt := time.Now()
+	result := &RotationResult{
+		Secret:       request.Secret,
+		Status:       StatusCompleted,
+		NewSecretRef: newRef,
+		RotatedAt:    &rotatedAt,
+	}
+
+	if request.Secret.ProviderRef.Version != "" {
+		result.OldSecretRef = &SecretReference{
+			Provider: request.Secret.Provider,
+			Key:      accessRef.Key,
+			Version:  request.Secret.ProviderRef.Version,
+		}
+	}
+
+	if token.ExpiresIn > 0 {
+		expiresAt := rotatedAt.Add(time.Duration(token.ExpiresIn) * time.Second)
+		result.ExpiresAt = &expiresAt
+	}
+
+	auditTrail = append(auditTrail, createAuditEntry("oauth_refresh_completed", "oauth_refresh_rotator", "info",
+		"OAuth token refresh completed", map[string]interface{}{"version": newVersion}))
+	result.AuditTrail = auditTrail
+
+	o.logger.Info("Successfully refreshed OAuth token for %s", logging.Secret(request.Secret.Key))
+
+	return result, nil
+}
+
+// Verify checks that the new access token can be resolved from the secret store
+func (o *OAuthRefreshRotator) Verify(ctx context.Context, request VerificationRequest) error {
+	p, _, err := o.providerFor(request.Secret)
+	if err != nil {
+		return err
+	}
+
+	ref := request.Secret.ProviderRef
+	ref.Version = request.NewSecretRef.Version
+
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve new access token: %w", err)
+	}
+	if secret.Value == "" {
+		return fmt.Errorf("new access token version %s is empty", request.NewSecretRef.Version)
+	}
+
+	return nil
+}
+
+// Rollback is not supported because the authorization server may have
+// invalidated the previous tokens
+func (o *OAuthRefreshRotator) Rollback(ctx context.Context, request RollbackRequest) error {
+	return fmt.Errorf("OAuth token refresh cannot be rolled back; run the refresh again to obtain a valid token for %s", request.Secret.Key)
+}
+
+// GetStatus returns the rotation status for the token
+func (o *OAuthRefreshRotator) GetStatus(ctx context.Context, secret SecretInfo) (*RotationStatusInfo, error) {
+	if !o.SupportsSecret(ctx, secret) {
+		return &RotationStatusInfo{
+			Status:    StatusPending,
+			CanRotate: false,
+			Reason:    "token_url, refresh_token_key and a writable provider are required",
+		}, nil
+	}
+
+	return &RotationStatusInfo{
+		Status:    StatusPending,
+		CanRotate: true,
+		Reason:    "Token can be refreshed",
+	}, nil
+}
+
+// exchange posts the refresh grant to the token endpoint
+func (o *OAuthRefreshRotator) exchange(ctx context.Context, tokenURL string, form url.Values) (*oauthTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var token oauthTokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("token endpoint returned status %d with invalid JSON", resp.StatusCode)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || token.Error != "" {
+		// Only the OAuth error fields are reported, never the response body
+		if token.Error != "" {
+			if token.ErrorDescription != "" {
+				return nil, fmt.Errorf("token endpoint returned %s: %s", token.Error, token.ErrorDescription)
+			}
+			return nil, fmt.Errorf("token endpoint returned %s", token.Error)
+		}
+		return nil, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint response did not include an access_token")
+	}
+
+	return &token, nil
+}
+
+// providerFor returns the provider holding the tokens and its Rotator
+func (o *OAuthRefreshRotator) providerFor(secret SecretInfo) (provider.Provider, provider.Rotator, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	p, ok := o.providers[secret.Provider]
+	if !ok {
+		return nil, nil, fmt.Errorf("provider '%s' not configured for OAuth refresh", secret.Provider)
+	}
+
+	rotator, ok := p.(provider.Rotator)
+	if !ok {
+		return nil, nil, fmt.Errorf("provider '%s' does not support creating new versions", secret.Provider)
+	}
+
+	return p, rotator, nil
+}
+
+// oauthSetting reads a setting from the request config, falling back to secret metadata
+func oauthSetting(request RotationRequest, name string) string {
+	if v, ok := request.Config[name].(string); ok && v != "" {
+		return v
+	}
+	return request.Secret.Metadata[name]
+}
@@ -0,0 +1,271 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/systmms/dsops/internal/logging"
+	"github.com/systmms/dsops/pkg/provider"
+)
+
+func oauthRequest(tokenURL string) RotationRequest {
+	return RotationRequest{
+		Secret: SecretInfo{
+			Key:         "API_TOKEN",
+			Provider:    "vault",
+			ProviderRef: provider.Reference{Provider: "vault", Key: "oauth/access"},
+			SecretType:  SecretTypeOAuth,
+			Metadata: map[string]string{
+				"token_url":         tokenURL,
+				"refresh_token_key": "oauth/refresh",
+				"client_id":         "dsops",
+				"client_secret_key": "oauth/client-secret",
+			},
+		},
+		Strategy: string(StrategyOAuthRefresh),
+	}
+}
+
+func newOAuthFake() *versionedFakeProvider {
+	fake := newVersionedFakeProvider()
+	fake.put("oauth/access", "old-access")
+	fake.put("oauth/refresh", "refresh-1")
+	fake.put("oauth/client-secret", "client-secret")
+	return fake
+}
+
+func TestOAuthRefreshRotator_SupportsSecret(t *testing.T) {
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": newOAuthFake()})
+
+	request := oauthRequest("https://auth.example.com/token")
+	if !rotator.SupportsSecret(context.Background(), request.Secret) {
+		t.Error("expected support with token_url and refresh_token_key")
+	}
+
+	delete(request.Secret.Metadata, "token_url")
+	if rotator.SupportsSecret(context.Background(), request.Secret) {
+		t.Error("expected no support without token_url")
+	}
+
+	// Settings in the request Config count as well as metadata
+	request.Config = map[string]interface{}{"token_url": "https://auth.example.com/token"}
+	if !rotator.SupportsRequest(context.Background(), request) {
+		t.Error("expected support with token_url in the request config")
+	}
+}
+
+func TestOAuthRefreshRotator_EngineUsesRequestConfig(t *testing.T) {
+	t.Setenv("DSOPS_ROTATION_DIR", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"new-access","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	logger := logging.New(false, true)
+	engine := NewRotationEngine(logger)
+	if err := engine.RegisterStrategy(NewOAuthRefreshRotator(logger)); err != nil {
+		t.Fatal(err)
+	}
+	engine.SetProviders(map[string]provider.Provider{"vault": newOAuthFake()})
+
+	request := oauthRequest("")
+	request.Secret.Metadata = nil
+	request.Config = map[string]interface{}{
+		"token_url":         server.URL,
+		"refresh_token_key": "oauth/refresh",
+	}
+
+	result, err := engine.Rotate(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusCompleted {
+		t.Errorf("expected completed, got %s (%s)", result.Status, result.Error)
+	}
+}
+
+func TestOAuthRefreshRotator_StoresRefreshTokenFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"new-access","refresh_token":"refresh-2"}`))
+	}))
+	defer server.Close()
+
+	// The access token write fails after the rotated refresh token is stored
+	fake := newOAuthFake()
+	fake.createFn = func(key string) error {
+		if key == "oauth/access" {
+			return errors.New("write quota exceeded")
+		}
+		return nil
+	}
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	result, err := rotator.Rotate(context.Background(), oauthRequest(server.URL))
+	if err == nil || !strings.Contains(err.Error(), "run the refresh again") {
+		t.Fatalf("expected access token storage error, got %v", err)
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failed, got %s", result.Status)
+	}
+	refresh, _ := fake.Resolve(context.Background(), provider.Reference{Key: "oauth/refresh"})
+	if refresh.Value != "refresh-2" {
+		t.Errorf("rotated refresh token must be kept for the next refresh, got %q", refresh.Value)
+	}
+
+	// The refresh token write fails, so the access token is left alone
+	fake = newOAuthFake()
+	fake.createFn = func(key string) error {
+		if key == "oauth/refresh" {
+			return errors.New("write quota exceeded")
+		}
+		return nil
+	}
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	if _, err := rotator.Rotate(context.Background(), oauthRequest(server.URL)); err == nil {
+		t.Fatal("expected refresh token storage error")
+	}
+	if len(fake.versions["oauth/access"]) != 1 {
+		t.Error("access token must not be stored when the rotated refresh token could not be")
+	}
+}
+
+func TestOAuthRefreshRotator_Rotate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+		if r.Form.Get("refresh_token") != "refresh-1" {
+			t.Errorf("unexpected refresh_token %q", r.Form.Get("refresh_token"))
+		}
+		if r.Form.Get("client_id") != "dsops" || r.Form.Get("client_secret") != "client-secret" {
+			t.Errorf("unexpected client credentials")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"new-access","refresh_token":"refresh-2","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	fake := newOAuthFake()
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	request := oauthRequest(server.URL)
+	result, err := rotator.Rotate(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != StatusCompleted {
+		t.Fatalf("expected completed, got %s (%s)", result.Status, result.Error)
+	}
+	if result.ExpiresAt == nil {
+		t.Error("expected ExpiresAt from expires_in")
+	}
+	if result.NewSecretRef.Version != "v2" {
+		t.Errorf("expected access token version v2, got %s", result.NewSecretRef.Version)
+	}
+	if result.NewSecretRef.Metadata["refresh_token_version"] != "v2" {
+		t.Errorf("expected rotated refresh token to be stored")
+	}
+
+	access, _ := fake.Resolve(context.Background(), provider.Reference{Key: "oauth/access"})
+	refresh, _ := fake.Resolve(context.Background(), provider.Reference{Key: "oauth/refresh"})
+	if access.Value != "new-access" || refresh.Value != "refresh-2" {
+		t.Errorf("unexpected stored tokens: access=%q refresh=%q", access.Value, refresh.Value)
+	}
+
+	if err := rotator.Verify(context.Background(), VerificationRequest{
+		Secret:       request.Secret,
+		NewSecretRef: *result.NewSecretRef,
+	}); err != nil {
+		t.Errorf("unexpected verify error: %v", err)
+	}
+}
+
+func TestOAuthRefreshRotator_RefreshTokenNotRotated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"new-access","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	fake := newOAuthFake()
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	result, err := rotator.Rotate(context.Background(), oauthRequest(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.NewSecretRef.Metadata["refresh_token_version"]; ok {
+		t.Error("refresh token must not be rewritten when the server does not rotate it")
+	}
+	if len(fake.versions["oauth/refresh"]) != 1 {
+		t.Errorf("expected 1 refresh token version, got %d", len(fake.versions["oauth/refresh"]))
+	}
+}
+
+func TestOAuthRefreshRotator_EndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token expired"}`))
+	}))
+	defer server.Close()
+
+	fake := newOAuthFake()
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": fake})
+
+	result, err := rotator.Rotate(context.Background(), oauthRequest(server.URL))
+	if err == nil {
+		t.Fatal("expected error from token endpoint")
+	}
+	if result.Status != StatusFailed {
+		t.Errorf("expected failed, got %s", result.Status)
+	}
+	if !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("expected OAuth error code in message, got %v", err)
+	}
+	if strings.Contains(err.Error(), "refresh-1") {
+		t.Error("error must not contain the refresh token")
+	}
+	if len(fake.versions["oauth/access"]) != 1 {
+		t.Error("access token must not change on failure")
+	}
+}
+
+func TestOAuthRefreshRotator_DryRun(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	rotator := NewOAuthRefreshRotator(logging.New(false, true))
+	rotator.SetProviders(map[string]provider.Provider{"vault": newOAuthFake()})
+
+	request := oauthRequest(server.URL)
+	request.DryRun = true
+
+	result, err := rotator.Rotate(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusPending {
+		t.Errorf("expected pending, got %s", result.Status)
+	}
+	if called {
+		t.Error("dry run must not call the token endpoint")
+	}
+}
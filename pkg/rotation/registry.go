@@ -42,10 +42,20 @@ func (r *StrategyRegistry) registerBuiltinStrategies() {
 		return NewScriptRotator(logger)
 	}
 
+	// Emergency strategy for compromised secrets (immediate revocation)
+	r.strategies[string(StrategyEmergency)] = func(logger *logging.Logger) SecretValueRotator {
+		return NewEmergencyRotator(logger)
+	}
+
+	// OAuth refresh token exchange strategy
+	r.strategies[string(StrategyOAuthRefresh)] = func(logger *logging.Logger) SecretValueRotator {
+		return NewOAuthRefreshRotator(logger)
+	}
+
 	// NOTE: Database and service-specific rotation strategies are now
 	// implemented in the dsops-data repository as data-driven configurations.
-	// Only generic, reusable strategies (random, webhook, script) are
-	// implemented here in the core codebase.
+	// Only generic, reusable strategies (random, webhook, script, emergency,
+	// oauth-refresh) are implemented here in the core codebase.
 
 	r.logger.Debug("Registered %d built-in rotation strategies", len(r.strategies))
 }
@@ -16,7 +16,7 @@ func TestNewStrategyRegistry(t *testing.T) {
 	}
 
 	// Verify built-in strategies are registered
-	builtinStrategies := []string{"random", "webhook", "script"}
+	builtinStrategies := []string{"random", "webhook", "script", "emergency", "oauth-refresh"}
 	for _, name := range builtinStrategies {
 		if !registry.HasStrategy(name) {
 			t.Errorf("Built-in strategy %s not registered", name)
@@ -217,8 +217,10 @@ func (s *DefaultStrategySelector) ValidateStrategy(strategy RotationStrategy, ca
 		}
 
 	case StrategyOAuthRefresh:
-		// This would need specific OAuth support
-		return fmt.Errorf("OAuth refresh strategy requires OAuth-specific implementation")
+		// New token pairs are stored as new versions of the secret
+		if !capabilities.SupportsVersioning {
+			return fmt.Errorf("provider does not support versioning needed to store refreshed tokens")
+		}
 
 	case StrategyEmergency:
 		if !capabilities.SupportsRevocation {
@@ -39,7 +39,10 @@
 //  1. Implement the SecretStore interface
 //  2. Handle URI parsing for your store's format
 //  3. Provide appropriate capabilities
-//  4. Register with the secret store registry
+//  4. Optionally implement Writer to support dsops set and dsops delete
+//  5. Optionally implement Lister to support dsops ls
+//  6. Optionally implement LeaseManager for leased, short-lived secrets
+//  7. Register with the secret store registry
 //
 // Example:
 //
@@ -426,6 +429,10 @@ type SecretMetadata struct {
 	// Common keys include environment, owner, purpose, etc.
 	// Empty map if not supported.
 	Tags map[string]string
+
+	// Deprecated indicates that the pinned version is disabled or scheduled
+	// for removal. Always false for unpinned references.
+	Deprecated bool
 }
 
 // SecretStoreCapabilities describes what features and operations a secret store supports.
@@ -534,6 +541,126 @@ type RotationCapabilities struct {
 	Constraints map[string]string
 }
 
+// Writer is an optional interface for secret stores that can create, update
+// and delete secrets.
+//
+// Stores that do not implement Writer are read-only. Callers check for it
+// with a type assertion.
+type Writer interface {
+	// PutSecret creates the secret if it does not exist, or stores value as
+	// its new current version. Returns the stored version when the store
+	// supports versioning, or an empty string otherwise.
+	PutSecret(ctx context.Context, ref SecretRef, value []byte, opts WriteOptions) (string, error)
+
+	// DeleteSecret removes a secret, or a single field when ref.Field is set.
+	// Deleting a secret that does not exist returns NotFoundError.
+	DeleteSecret(ctx context.Context, ref SecretRef, opts DeleteOptions) error
+}
+
+// WriteOptions carries optional attributes stored alongside a secret value.
+type WriteOptions struct {
+	// Description is a human-readable description of the secret.
+	Description string
+
+	// Tags are key/value labels attached to the secret.
+	Tags map[string]string
+
+	// Metadata holds store-specific write settings such as "kms_key_id".
+	Metadata map[string]string
+}
+
+// DeleteOptions controls how a secret is deleted.
+type DeleteOptions struct {
+	// Force skips recovery windows and soft deletion where supported.
+	Force bool
+}
+
+// Lister is an optional interface for secret stores that can enumerate the
+// secrets they hold. Listing returns metadata only, never values.
+type Lister interface {
+	// ListSecrets returns one page of secrets whose paths start with
+	// opts.Prefix. A path ending in "/" is a folder in hierarchical stores.
+	ListSecrets(ctx context.Context, opts ListOptions) (ListResult, error)
+}
+
+// ListOptions filters and pages a secret listing.
+type ListOptions struct {
+	// Prefix limits results to paths starting with this value.
+	Prefix string
+
+	// PageSize is the maximum number of secrets per page; zero lets the
+	// store choose.
+	PageSize int
+
+	// PageToken continues a previous listing.
+	PageToken string
+}
+
+// ListResult is one page of a secret listing.
+type ListResult struct {
+	// Secrets holds the secrets on this page.
+	Secrets []SecretInfo
+
+	// NextPageToken is set when more results are available.
+	NextPageToken string
+}
+
+// SecretInfo describes a listed secret without its value.
+type SecretInfo struct {
+	// Path is the secret's path within the store.
+	Path string
+
+	// Description is the human-readable description, if stored.
+	Description string
+
+	// Type is a store-specific type.
+	Type string
+
+	// Version is the current version identifier, when known.
+	Version string
+
+	// UpdatedAt is when the secret last changed, when known.
+	UpdatedAt time.Time
+
+	// Tags are the labels attached to the secret.
+	Tags map[string]string
+}
+
+// LeaseManager is an optional interface for secret stores whose secrets are
+// leased, such as Vault dynamic secrets. Reads of the same path share one
+// lease. Callers check for it with a type assertion.
+type LeaseManager interface {
+	// Leases returns the leases issued so far and not yet revoked.
+	Leases() []Lease
+
+	// RenewLease extends a lease by increment and returns it updated.
+	RenewLease(ctx context.Context, leaseID string, increment time.Duration) (Lease, error)
+
+	// RevokeLease revokes a lease so its credentials stop working.
+	RevokeLease(ctx context.Context, leaseID string) error
+}
+
+// Lease describes a leased secret without its value.
+type Lease struct {
+	// ID is the store's lease identifier.
+	ID string
+
+	// Store is the name of the store that issued the lease.
+	Store string
+
+	// Path is the path the leased secret was read from.
+	Path string
+
+	// Duration is how long the lease is valid from IssuedAt.
+	Duration time.Duration
+
+	// Renewable reports whether the lease can be extended.
+	Renewable bool
+
+	// IssuedAt is when the lease was issued or last renewed.
+	IssuedAt time.Time
+}
+
 // Error types for secret store operations
 
 // NotFoundError indicates that a requested secret does not exist in the store.
@@ -3,6 +3,9 @@ package fakes
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,6 +13,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 )
 
 // SecretsManagerAPI defines the interface for AWS Secrets Manager operations
@@ -20,6 +25,9 @@ type SecretsManagerAPI interface {
 	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
 	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
 	UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error)
 }
 
 // SSMAPI defines the interface for AWS SSM Parameter Store operations
@@ -27,6 +35,9 @@ type SecretsManagerAPI interface {
 type SSMAPI interface {
 	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 	DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
+	PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
+	DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error)
+	AddTagsToResource(ctx context.Context, params *ssm.AddTagsToResourceInput, optFns ...func(*ssm.Options)) (*ssm.AddTagsToResourceOutput, error)
 }
 
 // FakeSecretsManagerClient is a mock implementation of SecretsManagerAPI
@@ -45,6 +56,8 @@ type FakeSecretsManagerClient struct {
 	UpdateSecretFunc func(ctx context.Context, params *secretsmanager.UpdateSecretInput) (*secretsmanager.UpdateSecretOutput, error)
 	// UpdateSecretVersionStageFunc allows custom behavior for UpdateSecretVersionStage
 	UpdateSecretVersionStageFunc func(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+	// DeletedSecrets records secrets removed by DeleteSecret and whether the deletion was forced
+	DeletedSecrets map[string]bool
 }
 
 // SecretData holds the data for a mock secret
@@ -62,13 +75,15 @@ type SecretData struct {
 	LastChangedDate    *time.Time
 	VersionIdsToStages map[string][]string
 	ReplicationStatus  []types.ReplicationStatusType
+	Tags               map[string]string
 }
 
 // NewFakeSecretsManagerClient creates a new mock Secrets Manager client
 func NewFakeSecretsManagerClient() *FakeSecretsManagerClient {
 	return &FakeSecretsManagerClient{
-		Secrets: make(map[string]*SecretData),
-		Errors:  make(map[string]error),
+		Secrets:        make(map[string]*SecretData),
+		Errors:         make(map[string]error),
+		DeletedSecrets: make(map[string]bool),
 	}
 }
 
@@ -190,9 +205,38 @@ func (f *FakeSecretsManagerClient) ListSecrets(ctx context.Context, params *secr
 		return f.ListSecretsFunc(ctx, params)
 	}
 
-	// Return empty list for validation purposes
+	prefix := ""
+	for _, filter := range params.Filters {
+		if filter.Key == types.FilterNameStringTypeName && len(filter.Values) > 0 {
+			prefix = filter.Values[0]
+		}
+	}
+
+	names := make([]string, 0, len(f.Secrets))
+	for name := range f.Secrets {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	entries := make([]types.SecretListEntry, 0, len(names))
+	for _, name := range names {
+		data := f.Secrets[name]
+		entry := types.SecretListEntry{
+			Name:                   aws.String(name),
+			Description:            data.Description,
+			LastChangedDate:        data.LastChangedDate,
+			SecretVersionsToStages: data.VersionIdsToStages,
+		}
+		for key, value := range data.Tags {
+			entry.Tags = append(entry.Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		entries = append(entries, entry)
+	}
+
 	return &secretsmanager.ListSecretsOutput{
-		SecretList: []types.SecretListEntry{},
+		SecretList: entries,
 	}, nil
 }
 
@@ -224,9 +268,32 @@ func (f *FakeSecretsManagerClient) UpdateSecret(ctx context.Context, params *sec
 	if params.SecretBinary != nil {
 		data.SecretBinary = params.SecretBinary
 	}
+	if params.Description != nil {
+		data.Description = params.Description
+	}
+	if params.KmsKeyId != nil {
+		data.KmsKeyId = params.KmsKeyId
+	}
+	if data.VersionIdsToStages == nil {
+		data.VersionIdsToStages = make(map[string][]string)
+	}
 
-	// Generate new version ID
+	// Generate new version ID; like AWS, the old current version becomes
+	// AWSPREVIOUS and the version that held AWSPREVIOUS loses it
 	newVersionId := fmt.Sprintf("v%d-xyz789", len(data.VersionIdsToStages)+1)
+	for version, stages := range data.VersionIdsToStages {
+		var kept []string
+		for _, stage := range stages {
+			switch stage {
+			case "AWSPREVIOUS":
+			case "AWSCURRENT":
+				kept = append(kept, "AWSPREVIOUS")
+			default:
+				kept = append(kept, stage)
+			}
+		}
+		data.VersionIdsToStages[version] = kept
+	}
 	data.VersionId = aws.String(newVersionId)
 	data.VersionIdsToStages[newVersionId] = []string{"AWSCURRENT"}
 
@@ -255,19 +322,126 @@ func (f *FakeSecretsManagerClient) UpdateSecretVersionStage(ctx context.Context,
 	}
 
 	// Check if secret exists
-	_, exists := f.Secrets[secretName]
+	data, exists := f.Secrets[secretName]
 	if !exists {
 		return nil, &types.ResourceNotFoundException{
 			Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret: %s", secretName)),
 		}
 	}
 
+	stage := aws.ToString(params.VersionStage)
+	if from := aws.ToString(params.RemoveFromVersionId); from != "" && data.VersionIdsToStages != nil {
+		var kept []string
+		for _, s := range data.VersionIdsToStages[from] {
+			if s != stage {
+				kept = append(kept, s)
+			}
+		}
+		data.VersionIdsToStages[from] = kept
+	}
+	if to := aws.ToString(params.MoveToVersionId); to != "" {
+		if data.VersionIdsToStages == nil {
+			data.VersionIdsToStages = make(map[string][]string)
+		}
+		data.VersionIdsToStages[to] = append(data.VersionIdsToStages[to], stage)
+	}
+
 	return &secretsmanager.UpdateSecretVersionStageOutput{
 		ARN:  aws.String(fmt.Sprintf("arn:aws:secretsmanager:us-east-1:123456789012:secret:%s", secretName)),
 		Name: params.SecretId,
 	}, nil
 }
 
+// CreateSecret mocks the CreateSecret operation
+func (f *FakeSecretsManagerClient) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	secretName := aws.ToString(params.Name)
+
+	// Check for configured errors
+	if err, exists := f.Errors[secretName]; exists {
+		return nil, err
+	}
+
+	if _, exists := f.Secrets[secretName]; exists {
+		return nil, &types.ResourceExistsException{
+			Message: aws.String(fmt.Sprintf("The operation failed because the secret %s already exists.", secretName)),
+		}
+	}
+
+	now := time.Now()
+	versionId := "v1-abc123"
+	data := &SecretData{
+		SecretString:    params.SecretString,
+		SecretBinary:    params.SecretBinary,
+		VersionId:       aws.String(versionId),
+		VersionStages:   []string{"AWSCURRENT"},
+		CreatedDate:     &now,
+		LastChangedDate: &now,
+		Description:     params.Description,
+		KmsKeyId:        params.KmsKeyId,
+		VersionIdsToStages: map[string][]string{
+			versionId: {"AWSCURRENT"},
+		},
+		Tags: make(map[string]string),
+	}
+	for _, tag := range params.Tags {
+		data.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	f.Secrets[secretName] = data
+
+	return &secretsmanager.CreateSecretOutput{
+		ARN:       aws.String(fmt.Sprintf("arn:aws:secretsmanager:us-east-1:123456789012:secret:%s", secretName)),
+		Name:      params.Name,
+		VersionId: data.VersionId,
+	}, nil
+}
+
+// DeleteSecret mocks the DeleteSecret operation
+func (f *FakeSecretsManagerClient) DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	secretName := aws.ToString(params.SecretId)
+
+	// Check for configured errors
+	if err, exists := f.Errors[secretName]; exists {
+		return nil, err
+	}
+
+	if _, exists := f.Secrets[secretName]; !exists {
+		return nil, &types.ResourceNotFoundException{
+			Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret: %s", secretName)),
+		}
+	}
+
+	delete(f.Secrets, secretName)
+	f.DeletedSecrets[secretName] = aws.ToBool(params.ForceDeleteWithoutRecovery)
+
+	now := time.Now()
+	return &secretsmanager.DeleteSecretOutput{
+		ARN:          aws.String(fmt.Sprintf("arn:aws:secretsmanager:us-east-1:123456789012:secret:%s", secretName)),
+		Name:         params.SecretId,
+		DeletionDate: &now,
+	}, nil
+}
+
+// TagResource mocks the TagResource operation
+func (f *FakeSecretsManagerClient) TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error) {
+	secretName := aws.ToString(params.SecretId)
+
+	data, exists := f.Secrets[secretName]
+	if !exists {
+		return nil, &types.ResourceNotFoundException{
+			Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret: %s", secretName)),
+		}
+	}
+
+	if data.Tags == nil {
+		data.Tags = make(map[string]string)
+	}
+	for _, tag := range params.Tags {
+		data.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return &secretsmanager.TagResourceOutput{}, nil
+}
+
 // FakeSSMClient is a mock implementation of SSMAPI
 type FakeSSMClient struct {
 	// Parameters maps parameter names to their data
@@ -290,6 +464,9 @@ type ParameterData struct {
 	ARN              *string
 	DataType         *string
 	Tier             ssmtypes.ParameterTier
+	Description      *string
+	KeyId            *string
+	Tags             map[string]string
 }
 
 // NewFakeSSMClient creates a new mock SSM client
@@ -397,6 +574,31 @@ func (f *FakeSSMClient) DescribeParameters(ctx context.Context, params *ssm.Desc
 
 	// Filter by parameter name
 	for _, filter := range params.ParameterFilters {
+		if aws.ToString(filter.Key) == "Name" && aws.ToString(filter.Option) == "BeginsWith" && len(filter.Values) > 0 {
+			names := make([]string, 0, len(f.Parameters))
+			for name := range f.Parameters {
+				if strings.HasPrefix(name, filter.Values[0]) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			paramList := make([]ssmtypes.ParameterMetadata, 0, len(names))
+			for _, name := range names {
+				data := f.Parameters[name]
+				paramList = append(paramList, ssmtypes.ParameterMetadata{
+					Name:             data.Name,
+					Type:             data.Type,
+					Version:          data.Version,
+					LastModifiedDate: data.LastModifiedDate,
+					Tier:             data.Tier,
+					Description:      data.Description,
+				})
+			}
+			return &ssm.DescribeParametersOutput{
+				Parameters: paramList,
+			}, nil
+		}
 		if aws.ToString(filter.Key) == "Name" && len(filter.Values) > 0 {
 			paramName := filter.Values[0]
 			data, exists := f.Parameters[paramName]
@@ -424,3 +626,233 @@ func (f *FakeSSMClient) DescribeParameters(ctx context.Context, params *ssm.Desc
 		Parameters: []ssmtypes.ParameterMetadata{},
 	}, nil
 }
+
+// PutParameter mocks the PutParameter operation
+func (f *FakeSSMClient) PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	paramName := aws.ToString(params.Name)
+
+	// Check for configured errors
+	if err, exists := f.Errors[paramName]; exists {
+		return nil, err
+	}
+
+	now := time.Now()
+	data, exists := f.Parameters[paramName]
+	if exists && !aws.ToBool(params.Overwrite) {
+		return nil, &ssmtypes.ParameterAlreadyExists{
+			Message: aws.String(fmt.Sprintf("Parameter %s already exists", paramName)),
+		}
+	}
+	if !exists {
+		data = &ParameterData{
+			Name: aws.String(paramName),
+			ARN:  aws.String(fmt.Sprintf("arn:aws:ssm:us-east-1:123456789012:parameter%s", paramName)),
+			Tier: ssmtypes.ParameterTierStandard,
+		}
+		f.Parameters[paramName] = data
+	}
+
+	data.Value = params.Value
+	data.Type = params.Type
+	data.Version++
+	data.LastModifiedDate = &now
+	if params.Description != nil {
+		data.Description = params.Description
+	}
+	if params.KeyId != nil {
+		data.KeyId = params.KeyId
+	}
+	if params.Tier != "" {
+		data.Tier = params.Tier
+	}
+
+	return &ssm.PutParameterOutput{
+		Version: data.Version,
+		Tier:    data.Tier,
+	}, nil
+}
+
+// DeleteParameter mocks the DeleteParameter operation
+func (f *FakeSSMClient) DeleteParameter(ctx context.Context, params *ssm.DeleteParameterInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParameterOutput, error) {
+	paramName := aws.ToString(params.Name)
+
+	// Check for configured errors
+	if err, exists := f.Errors[paramName]; exists {
+		return nil, err
+	}
+
+	if _, exists := f.Parameters[paramName]; !exists {
+		return nil, &ssmtypes.ParameterNotFound{
+			Message: aws.String(fmt.Sprintf("Parameter %s not found", paramName)),
+		}
+	}
+
+	delete(f.Parameters, paramName)
+	return &ssm.DeleteParameterOutput{}, nil
+}
+
+// AddTagsToResource mocks the AddTagsToResource operation
+func (f *FakeSSMClient) AddTagsToResource(ctx context.Context, params *ssm.AddTagsToResourceInput, optFns ...func(*ssm.Options)) (*ssm.AddTagsToResourceOutput, error) {
+	paramName := aws.ToString(params.ResourceId)
+
+	data, exists := f.Parameters[paramName]
+	if !exists {
+		return nil, &ssmtypes.InvalidResourceId{
+			Message: aws.String(fmt.Sprintf("Parameter %s not found", paramName)),
+		}
+	}
+
+	if data.Tags == nil {
+		data.Tags = make(map[string]string)
+	}
+	for _, tag := range params.Tags {
+		data.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return &ssm.AddTagsToResourceOutput{}, nil
+}
+
+// STSAPI defines the interface for AWS STS operations
+// This matches the subset of methods used by AWSSTSProvider
+type STSAPI interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// FakeSTSClient is a mock implementation of STSAPI. The credentials it issues
+// identify the role they belong to, so a role chain can be followed through
+// the calls: each role only trusts the caller named in its TrustedCaller.
+type FakeSTSClient struct {
+	mu sync.Mutex
+
+	// Roles maps role ARNs to the roles that can be assumed
+	Roles map[string]*FakeSTSRole
+	// Calls records each AssumeRole call in order
+	Calls []FakeAssumeRoleCall
+	// Identity is the ARN GetCallerIdentity returns for the source credentials
+	Identity string
+	// CallerIdentityErr is returned by GetCallerIdentity when set
+	CallerIdentityErr error
+
+	issued map[string]string // access key ID -> role ARN
+}
+
+// FakeSTSRole holds the trust settings of a mock role
+type FakeSTSRole struct {
+	// TrustedCaller is the role ARN whose sessions may assume this role;
+	// empty trusts the source credentials
+	TrustedCaller string
+	ExternalID    string
+	MFASerial     string
+	MFACode       string
+	// SessionDuration caps the lifetime of issued sessions when set
+	SessionDuration time.Duration
+}
+
+// FakeAssumeRoleCall records an AssumeRole call
+type FakeAssumeRoleCall struct {
+	Input *sts.AssumeRoleInput
+	// Caller is the role ARN of the calling session, or empty for the source
+	// credentials
+	Caller string
+}
+
+// NewFakeSTSClient creates a new mock STS client
+func NewFakeSTSClient() *FakeSTSClient {
+	return &FakeSTSClient{
+		Roles:    make(map[string]*FakeSTSRole),
+		Identity: "arn:aws:iam::111111111111:user/developer",
+		issued:   make(map[string]string),
+	}
+}
+
+// AddRole adds a role that can be assumed
+func (f *FakeSTSClient) AddRole(arn string, role FakeSTSRole) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Roles[arn] = &role
+}
+
+// AssumeRole issues a session for a role when the caller, external ID and MFA
+// code match the role's trust settings
+func (f *FakeSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	var opts sts.Options
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	caller := ""
+	if opts.Credentials != nil {
+		creds, err := opts.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.mu.Lock()
+		caller = f.issued[creds.AccessKeyID]
+		f.mu.Unlock()
+		if caller == "" {
+			return nil, fmt.Errorf("InvalidClientTokenId: the security token included in the request is invalid")
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	arn := aws.ToString(params.RoleArn)
+	f.Calls = append(f.Calls, FakeAssumeRoleCall{Input: params, Caller: caller})
+
+	role, ok := f.Roles[arn]
+	if !ok || role.TrustedCaller != caller {
+		return nil, fmt.Errorf("AccessDenied: not authorized to perform sts:AssumeRole on resource %s", arn)
+	}
+	if role.ExternalID != "" && aws.ToString(params.ExternalId) != role.ExternalID {
+		return nil, fmt.Errorf("AccessDenied: not authorized to perform sts:AssumeRole on resource %s", arn)
+	}
+	if role.MFASerial != "" && (aws.ToString(params.SerialNumber) != role.MFASerial || aws.ToString(params.TokenCode) != role.MFACode) {
+		return nil, fmt.Errorf("AccessDenied: MultiFactorAuthentication failed with invalid MFA one time pass code")
+	}
+
+	duration := time.Hour
+	if params.DurationSeconds != nil {
+		duration = time.Duration(*params.DurationSeconds) * time.Second
+	}
+	if role.SessionDuration > 0 && role.SessionDuration < duration {
+		duration = role.SessionDuration
+	}
+
+	accessKeyID := fmt.Sprintf("ASIAFAKE%04d", len(f.issued)+1)
+	f.issued[accessKeyID] = arn
+
+	sessionName := aws.ToString(params.RoleSessionName)
+	return &sts.AssumeRoleOutput{
+		Credentials: &ststypes.Credentials{
+			AccessKeyId:     aws.String(accessKeyID),
+			SecretAccessKey: aws.String("secret-" + accessKeyID),
+			SessionToken:    aws.String("token-" + accessKeyID),
+			Expiration:      aws.Time(time.Now().Add(duration)),
+		},
+		AssumedRoleUser: &ststypes.AssumedRoleUser{
+			Arn:           aws.String(arn + "/" + sessionName),
+			AssumedRoleId: aws.String("AROAFAKE:" + sessionName),
+		},
+		SourceIdentity: params.SourceIdentity,
+	}, nil
+}
+
+// GetCallerIdentity returns Identity
+func (f *FakeSTSClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	if f.CallerIdentityErr != nil {
+		return nil, f.CallerIdentityErr
+	}
+	return &sts.GetCallerIdentityOutput{Arn: aws.String(f.Identity)}, nil
+}
+
+// RolesAssumed returns the role ARNs of the AssumeRole calls in order
+func (f *FakeSTSClient) RolesAssumed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	roles := make([]string, 0, len(f.Calls))
+	for _, call := range f.Calls {
+		roles = append(roles, aws.ToString(call.Input.RoleArn))
+	}
+	return roles
+}
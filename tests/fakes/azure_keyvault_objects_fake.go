@@ -0,0 +1,241 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azcertificates"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// FakeAzureCertificatesClient is a mock implementation of the Key Vault
+// certificates operations used by AzureKeyVaultProvider
+type FakeAzureCertificatesClient struct {
+	// Certificates maps certificate names to their versions, oldest first
+	Certificates map[string][]*azcertificates.Certificate
+	// Policies maps certificate names to their policies
+	Policies map[string]*azcertificates.CertificatePolicy
+	// PendingPolls is how many times a new certificate operation reports
+	// inProgress before it completes
+	PendingPolls int
+	// OnIssue returns the version issued when an operation completes. By
+	// default the latest version is copied.
+	OnIssue func(name string, version string) *azcertificates.Certificate
+	// Created records the parameters of every CreateCertificate call
+	Created []azcertificates.CreateCertificateParameters
+
+	pending map[string]int
+}
+
+// NewFakeAzureCertificatesClient creates a new mock certificates client
+func NewFakeAzureCertificatesClient() *FakeAzureCertificatesClient {
+	return &FakeAzureCertificatesClient{
+		Certificates: make(map[string][]*azcertificates.Certificate),
+		Policies:     make(map[string]*azcertificates.CertificatePolicy),
+		pending:      make(map[string]int),
+	}
+}
+
+// AddCertificate adds a certificate version with its DER encoding
+func (f *FakeAzureCertificatesClient) AddCertificate(name, version string, der []byte, attrs *azcertificates.CertificateAttributes) {
+	if attrs == nil {
+		attrs = &azcertificates.CertificateAttributes{Enabled: to.Ptr(true)}
+	}
+	f.Certificates[name] = append(f.Certificates[name], &azcertificates.Certificate{
+		ID:         certificateID(name, version),
+		CER:        der,
+		Attributes: attrs,
+	})
+}
+
+func certificateID(name, version string) *azcertificates.ID {
+	return to.Ptr(azcertificates.ID(fmt.Sprintf("https://test-vault.vault.azure.net/certificates/%s/%s", name, version)))
+}
+
+func certificateNotFound() error {
+	return &azcore.ResponseError{StatusCode: 404, ErrorCode: "CertificateNotFound"}
+}
+
+// find returns a certificate version; an empty version is the latest
+func (f *FakeAzureCertificatesClient) find(name, version string) *azcertificates.Certificate {
+	versions := f.Certificates[name]
+	if len(versions) == 0 {
+		return nil
+	}
+	if version == "" {
+		return versions[len(versions)-1]
+	}
+	for _, cert := range versions {
+		if cert.ID.Version() == version {
+			return cert
+		}
+	}
+	return nil
+}
+
+// GetCertificate mocks the GetCertificate operation
+func (f *FakeAzureCertificatesClient) GetCertificate(ctx context.Context, name string, version string, options *azcertificates.GetCertificateOptions) (azcertificates.GetCertificateResponse, error) {
+	cert := f.find(name, version)
+	if cert == nil {
+		return azcertificates.GetCertificateResponse{}, certificateNotFound()
+	}
+	result := *cert
+	result.Policy = f.Policies[name]
+	return azcertificates.GetCertificateResponse{Certificate: result}, nil
+}
+
+// GetCertificatePolicy mocks the GetCertificatePolicy operation
+func (f *FakeAzureCertificatesClient) GetCertificatePolicy(ctx context.Context, name string, options *azcertificates.GetCertificatePolicyOptions) (azcertificates.GetCertificatePolicyResponse, error) {
+	policy, exists := f.Policies[name]
+	if !exists {
+		return azcertificates.GetCertificatePolicyResponse{}, certificateNotFound()
+	}
+	return azcertificates.GetCertificatePolicyResponse{CertificatePolicy: *policy}, nil
+}
+
+// CreateCertificate mocks starting a certificate operation
+func (f *FakeAzureCertificatesClient) CreateCertificate(ctx context.Context, name string, parameters azcertificates.CreateCertificateParameters, options *azcertificates.CreateCertificateOptions) (azcertificates.CreateCertificateResponse, error) {
+	f.Created = append(f.Created, parameters)
+	f.pending[name] = f.PendingPolls
+	return azcertificates.CreateCertificateResponse{
+		CertificateOperation: azcertificates.CertificateOperation{Status: to.Ptr("inProgress")},
+	}, nil
+}
+
+// GetCertificateOperation mocks polling a certificate operation. The
+// operation completes after PendingPolls polls and issues a new version.
+func (f *FakeAzureCertificatesClient) GetCertificateOperation(ctx context.Context, name string, options *azcertificates.GetCertificateOperationOptions) (azcertificates.GetCertificateOperationResponse, error) {
+	remaining, exists := f.pending[name]
+	if !exists {
+		return azcertificates.GetCertificateOperationResponse{}, certificateNotFound()
+	}
+	if remaining > 0 {
+		f.pending[name] = remaining - 1
+		return azcertificates.GetCertificateOperationResponse{
+			CertificateOperation: azcertificates.CertificateOperation{Status: to.Ptr("inProgress")},
+		}, nil
+	}
+	delete(f.pending, name)
+
+	version := fmt.Sprintf("v%d", len(f.Certificates[name])+1)
+	var issued *azcertificates.Certificate
+	if f.OnIssue != nil {
+		issued = f.OnIssue(name, version)
+	} else if latest := f.find(name, ""); latest != nil {
+		copied := *latest
+		issued = &copied
+	} else {
+		issued = &azcertificates.Certificate{}
+	}
+	issued.ID = certificateID(name, version)
+	if params := f.Created[len(f.Created)-1]; params.Tags != nil {
+		issued.Tags = params.Tags
+	}
+	f.Certificates[name] = append(f.Certificates[name], issued)
+
+	return azcertificates.GetCertificateOperationResponse{
+		CertificateOperation: azcertificates.CertificateOperation{Status: to.Ptr("completed")},
+	}, nil
+}
+
+// UpdateCertificate mocks updating the attributes of a certificate version
+func (f *FakeAzureCertificatesClient) UpdateCertificate(ctx context.Context, name string, version string, parameters azcertificates.UpdateCertificateParameters, options *azcertificates.UpdateCertificateOptions) (azcertificates.UpdateCertificateResponse, error) {
+	cert := f.find(name, version)
+	if cert == nil {
+		return azcertificates.UpdateCertificateResponse{}, certificateNotFound()
+	}
+	if attrs := parameters.CertificateAttributes; attrs != nil && attrs.Enabled != nil {
+		updated := azcertificates.CertificateAttributes{}
+		if cert.Attributes != nil {
+			updated = *cert.Attributes
+		}
+		updated.Enabled = attrs.Enabled
+		cert.Attributes = &updated
+	}
+	return azcertificates.UpdateCertificateResponse{Certificate: *cert}, nil
+}
+
+// FakeAzureKeysClient is a mock implementation of the Key Vault key
+// operations used by AzureKeyVaultProvider
+type FakeAzureKeysClient struct {
+	// Keys maps key names to their versions, oldest first
+	Keys map[string][]*azkeys.KeyBundle
+}
+
+// NewFakeAzureKeysClient creates a new mock keys client
+func NewFakeAzureKeysClient() *FakeAzureKeysClient {
+	return &FakeAzureKeysClient{Keys: make(map[string][]*azkeys.KeyBundle)}
+}
+
+// AddKey adds a key version
+func (f *FakeAzureKeysClient) AddKey(name, version string, key azkeys.JSONWebKey) {
+	key.KID = keyID(name, version)
+	f.Keys[name] = append(f.Keys[name], &azkeys.KeyBundle{
+		Key:        &key,
+		Attributes: &azkeys.KeyAttributes{Enabled: to.Ptr(true)},
+	})
+}
+
+func keyID(name, version string) *azkeys.ID {
+	return to.Ptr(azkeys.ID(fmt.Sprintf("https://test-vault.vault.azure.net/keys/%s/%s", name, version)))
+}
+
+func keyNotFound() error {
+	return &azcore.ResponseError{StatusCode: 404, ErrorCode: "KeyNotFound"}
+}
+
+// find returns a key version; an empty version is the latest
+func (f *FakeAzureKeysClient) find(name, version string) *azkeys.KeyBundle {
+	versions := f.Keys[name]
+	if len(versions) == 0 {
+		return nil
+	}
+	if version == "" {
+		return versions[len(versions)-1]
+	}
+	for _, bundle := range versions {
+		if bundle.Key.KID.Version() == version {
+			return bundle
+		}
+	}
+	return nil
+}
+
+// GetKey mocks the GetKey operation
+func (f *FakeAzureKeysClient) GetKey(ctx context.Context, name string, version string, options *azkeys.GetKeyOptions) (azkeys.GetKeyResponse, error) {
+	bundle := f.find(name, version)
+	if bundle == nil {
+		return azkeys.GetKeyResponse{}, keyNotFound()
+	}
+	return azkeys.GetKeyResponse{KeyBundle: *bundle}, nil
+}
+
+// RotateKey mocks rotating a key by copying the latest version
+func (f *FakeAzureKeysClient) RotateKey(ctx context.Context, name string, options *azkeys.RotateKeyOptions) (azkeys.RotateKeyResponse, error) {
+	latest := f.find(name, "")
+	if latest == nil {
+		return azkeys.RotateKeyResponse{}, keyNotFound()
+	}
+	key := *latest.Key
+	f.AddKey(name, fmt.Sprintf("v%d", len(f.Keys[name])+1), key)
+	return azkeys.RotateKeyResponse{KeyBundle: *f.find(name, "")}, nil
+}
+
+// UpdateKey mocks updating the attributes of a key version
+func (f *FakeAzureKeysClient) UpdateKey(ctx context.Context, name string, version string, parameters azkeys.UpdateKeyParameters, options *azkeys.UpdateKeyOptions) (azkeys.UpdateKeyResponse, error) {
+	bundle := f.find(name, version)
+	if bundle == nil {
+		return azkeys.UpdateKeyResponse{}, keyNotFound()
+	}
+	if attrs := parameters.KeyAttributes; attrs != nil && attrs.Enabled != nil {
+		updated := azkeys.KeyAttributes{}
+		if bundle.Attributes != nil {
+			updated = *bundle.Attributes
+		}
+		updated.Enabled = attrs.Enabled
+		bundle.Attributes = &updated
+	}
+	return azkeys.UpdateKeyResponse{KeyBundle: *bundle}, nil
+}
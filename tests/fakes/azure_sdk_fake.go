@@ -3,9 +3,11 @@ package fakes
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 )
@@ -14,6 +16,12 @@ import (
 // This matches the subset of methods used by AzureKeyVaultProvider
 type AzureKeyVaultAPI interface {
 	GetSecret(ctx context.Context, name string, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+	SetSecret(ctx context.Context, name string, parameters azsecrets.SetSecretParameters, options *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error)
+	DeleteSecret(ctx context.Context, name string, options *azsecrets.DeleteSecretOptions) (azsecrets.DeleteSecretResponse, error)
+	PurgeDeletedSecret(ctx context.Context, name string, options *azsecrets.PurgeDeletedSecretOptions) (azsecrets.PurgeDeletedSecretResponse, error)
+	UpdateSecretProperties(ctx context.Context, name string, version string, parameters azsecrets.UpdateSecretPropertiesParameters, options *azsecrets.UpdateSecretPropertiesOptions) (azsecrets.UpdateSecretPropertiesResponse, error)
+	NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse]
+	NewListSecretPropertiesVersionsPager(name string, options *azsecrets.ListSecretPropertiesVersionsOptions) *runtime.Pager[azsecrets.ListSecretPropertiesVersionsResponse]
 }
 
 // FakeAzureKeyVaultClient is a mock implementation of AzureKeyVaultAPI
@@ -22,6 +30,8 @@ type FakeAzureKeyVaultClient struct {
 	Secrets map[string]*AzureSecretData
 	// Errors maps secret names to errors to return
 	Errors map[string]error
+	// DeletedSecrets records soft-deleted secrets; true once purged
+	DeletedSecrets map[string]bool
 	// GetSecretFunc allows custom behavior for GetSecret
 	GetSecretFunc func(ctx context.Context, name string, version string) (azsecrets.GetSecretResponse, error)
 	// ListSecretsFunc allows custom behavior for listing secrets
@@ -48,8 +58,9 @@ type AzureSecretVersion struct {
 // NewFakeAzureKeyVaultClient creates a new mock Azure Key Vault client
 func NewFakeAzureKeyVaultClient() *FakeAzureKeyVaultClient {
 	return &FakeAzureKeyVaultClient{
-		Secrets: make(map[string]*AzureSecretData),
-		Errors:  make(map[string]error),
+		Secrets:        make(map[string]*AzureSecretData),
+		Errors:         make(map[string]error),
+		DeletedSecrets: make(map[string]bool),
 	}
 }
 
@@ -180,6 +191,203 @@ func (f *FakeAzureKeyVaultClient) GetSecret(ctx context.Context, name string, ve
 	}, nil
 }
 
+// SetSecret mocks the SetSecret operation, creating a new version
+func (f *FakeAzureKeyVaultClient) SetSecret(ctx context.Context, name string, parameters azsecrets.SetSecretParameters, options *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error) {
+	// Check for configured errors
+	if err, exists := f.Errors[name]; exists {
+		return azsecrets.SetSecretResponse{}, err
+	}
+
+	now := time.Now()
+	data, exists := f.Secrets[name]
+	if !exists {
+		data = &AzureSecretData{
+			Attributes: &azsecrets.SecretAttributes{
+				Enabled:       to.Ptr(true),
+				Created:       &now,
+				RecoveryLevel: to.Ptr("Recoverable+Purgeable"),
+			},
+			Versions: make(map[string]*AzureSecretVersion),
+		}
+		f.Secrets[name] = data
+	}
+	if data.Versions == nil {
+		data.Versions = make(map[string]*AzureSecretVersion)
+	}
+
+	version := fmt.Sprintf("v%d", len(data.Versions)+1)
+	data.Value = parameters.Value
+	data.ID = to.Ptr(fmt.Sprintf("https://test-vault.vault.azure.net/secrets/%s/%s", name, version))
+	data.Tags = parameters.Tags
+	data.ContentType = parameters.ContentType
+	data.Attributes.Updated = &now
+	data.Versions[version] = &AzureSecretVersion{
+		Value:      parameters.Value,
+		Attributes: data.Attributes,
+	}
+	delete(f.DeletedSecrets, name)
+
+	return azsecrets.SetSecretResponse{
+		Secret: azsecrets.Secret{
+			ID:          (*azsecrets.ID)(data.ID),
+			Value:       data.Value,
+			Attributes:  data.Attributes,
+			Tags:        data.Tags,
+			ContentType: data.ContentType,
+		},
+	}, nil
+}
+
+// DeleteSecret mocks the DeleteSecret operation (soft delete)
+func (f *FakeAzureKeyVaultClient) DeleteSecret(ctx context.Context, name string, options *azsecrets.DeleteSecretOptions) (azsecrets.DeleteSecretResponse, error) {
+	// Check for configured errors
+	if err, exists := f.Errors[name]; exists {
+		return azsecrets.DeleteSecretResponse{}, err
+	}
+
+	if _, exists := f.Secrets[name]; !exists {
+		return azsecrets.DeleteSecretResponse{}, &azcore.ResponseError{
+			StatusCode: 404,
+			ErrorCode:  "SecretNotFound",
+		}
+	}
+
+	delete(f.Secrets, name)
+	f.DeletedSecrets[name] = false
+
+	return azsecrets.DeleteSecretResponse{}, nil
+}
+
+// PurgeDeletedSecret mocks the PurgeDeletedSecret operation
+func (f *FakeAzureKeyVaultClient) PurgeDeletedSecret(ctx context.Context, name string, options *azsecrets.PurgeDeletedSecretOptions) (azsecrets.PurgeDeletedSecretResponse, error) {
+	if _, exists := f.DeletedSecrets[name]; !exists {
+		return azsecrets.PurgeDeletedSecretResponse{}, &azcore.ResponseError{
+			StatusCode: 404,
+			ErrorCode:  "SecretNotFound",
+		}
+	}
+
+	f.DeletedSecrets[name] = true
+	return azsecrets.PurgeDeletedSecretResponse{}, nil
+}
+
+// UpdateSecretProperties mocks updating the attributes of a secret version
+func (f *FakeAzureKeyVaultClient) UpdateSecretProperties(ctx context.Context, name string, version string, parameters azsecrets.UpdateSecretPropertiesParameters, options *azsecrets.UpdateSecretPropertiesOptions) (azsecrets.UpdateSecretPropertiesResponse, error) {
+	if err, exists := f.Errors[name]; exists {
+		return azsecrets.UpdateSecretPropertiesResponse{}, err
+	}
+
+	data, exists := f.Secrets[name]
+	if !exists {
+		return azsecrets.UpdateSecretPropertiesResponse{}, &azcore.ResponseError{StatusCode: 404, ErrorCode: "SecretNotFound"}
+	}
+	versionData, exists := data.Versions[version]
+	if !exists {
+		return azsecrets.UpdateSecretPropertiesResponse{}, &azcore.ResponseError{StatusCode: 404, ErrorCode: "SecretNotFound"}
+	}
+
+	// Versions may share attributes with the secret, so update a copy
+	if attrs := parameters.SecretAttributes; attrs != nil {
+		updated := azsecrets.SecretAttributes{}
+		if versionData.Attributes != nil {
+			updated = *versionData.Attributes
+		}
+		if attrs.Enabled != nil {
+			updated.Enabled = attrs.Enabled
+		}
+		if attrs.Expires != nil {
+			updated.Expires = attrs.Expires
+		}
+		versionData.Attributes = &updated
+	}
+
+	return azsecrets.UpdateSecretPropertiesResponse{
+		Secret: azsecrets.Secret{
+			ID:         (*azsecrets.ID)(to.Ptr(fmt.Sprintf("https://test-vault.vault.azure.net/secrets/%s/%s", name, version))),
+			Attributes: versionData.Attributes,
+		},
+	}, nil
+}
+
+// NewListSecretPropertiesPager mocks listing secret properties. Secrets are
+// returned in a single page sorted by name, or from ListSecretsFunc if set.
+func (f *FakeAzureKeyVaultClient) NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse] {
+	return runtime.NewPager(runtime.PagingHandler[azsecrets.ListSecretPropertiesResponse]{
+		More: func(page azsecrets.ListSecretPropertiesResponse) bool {
+			return false
+		},
+		Fetcher: func(ctx context.Context, page *azsecrets.ListSecretPropertiesResponse) (azsecrets.ListSecretPropertiesResponse, error) {
+			var props []azsecrets.SecretProperties
+			if f.ListSecretsFunc != nil {
+				var err error
+				if props, err = f.ListSecretsFunc(ctx); err != nil {
+					return azsecrets.ListSecretPropertiesResponse{}, err
+				}
+			} else {
+				names := make([]string, 0, len(f.Secrets))
+				for name := range f.Secrets {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					data := f.Secrets[name]
+					props = append(props, azsecrets.SecretProperties{
+						ID:          (*azsecrets.ID)(to.Ptr(fmt.Sprintf("https://test-vault.vault.azure.net/secrets/%s", name))),
+						Attributes:  data.Attributes,
+						Tags:        data.Tags,
+						ContentType: data.ContentType,
+					})
+				}
+			}
+
+			value := make([]*azsecrets.SecretProperties, len(props))
+			for i := range props {
+				value[i] = &props[i]
+			}
+			return azsecrets.ListSecretPropertiesResponse{
+				SecretPropertiesListResult: azsecrets.SecretPropertiesListResult{Value: value},
+			}, nil
+		},
+	})
+}
+
+// NewListSecretPropertiesVersionsPager mocks listing the versions of a
+// secret. Versions are returned in a single page sorted by version.
+func (f *FakeAzureKeyVaultClient) NewListSecretPropertiesVersionsPager(name string, options *azsecrets.ListSecretPropertiesVersionsOptions) *runtime.Pager[azsecrets.ListSecretPropertiesVersionsResponse] {
+	return runtime.NewPager(runtime.PagingHandler[azsecrets.ListSecretPropertiesVersionsResponse]{
+		More: func(page azsecrets.ListSecretPropertiesVersionsResponse) bool {
+			return false
+		},
+		Fetcher: func(ctx context.Context, page *azsecrets.ListSecretPropertiesVersionsResponse) (azsecrets.ListSecretPropertiesVersionsResponse, error) {
+			if err, exists := f.Errors[name]; exists {
+				return azsecrets.ListSecretPropertiesVersionsResponse{}, err
+			}
+			data, exists := f.Secrets[name]
+			if !exists {
+				return azsecrets.ListSecretPropertiesVersionsResponse{}, &azcore.ResponseError{StatusCode: 404, ErrorCode: "SecretNotFound"}
+			}
+
+			versions := make([]string, 0, len(data.Versions))
+			for version := range data.Versions {
+				versions = append(versions, version)
+			}
+			sort.Strings(versions)
+
+			var value []*azsecrets.SecretProperties
+			for _, version := range versions {
+				value = append(value, &azsecrets.SecretProperties{
+					ID:         (*azsecrets.ID)(to.Ptr(fmt.Sprintf("https://test-vault.vault.azure.net/secrets/%s/%s", name, version))),
+					Attributes: data.Versions[version].Attributes,
+				})
+			}
+			return azsecrets.ListSecretPropertiesVersionsResponse{
+				SecretPropertiesListResult: azsecrets.SecretPropertiesListResult{Value: value},
+			}, nil
+		},
+	})
+}
+
 // FakeAzureKeyVaultPager is a simplified mock pager for testing
 type FakeAzureKeyVaultPager struct {
 	secrets []azsecrets.SecretProperties
@@ -20,6 +20,12 @@ type FakeKeychainClient struct {
 
 	// QueryErr is returned by Query() if set (overrides Secrets lookup)
 	QueryErr error
+
+	// SetErr is returned by Set() if set
+	SetErr error
+
+	// DeleteErr is returned by Delete() if set
+	DeleteErr error
 }
 
 // NewFakeKeychainClient creates a new fake keychain client with defaults
@@ -56,6 +62,29 @@ func (f *FakeKeychainClient) Query(service, account string) ([]byte, error) {
 	return nil, ErrFakeKeychainItemNotFound
 }
 
+// Set stores a secret in the fake keychain
+func (f *FakeKeychainClient) Set(service, account string, value []byte) error {
+	if f.SetErr != nil {
+		return f.SetErr
+	}
+	f.SetSecret(service, account, value)
+	return nil
+}
+
+// Delete removes a secret from the fake keychain
+func (f *FakeKeychainClient) Delete(service, account string) error {
+	if f.DeleteErr != nil {
+		return f.DeleteErr
+	}
+	if accounts, ok := f.Secrets[service]; ok {
+		if _, ok := accounts[account]; ok {
+			delete(accounts, account)
+			return nil
+		}
+	}
+	return ErrFakeKeychainItemNotFound
+}
+
 // Validate checks if the keychain is accessible
 func (f *FakeKeychainClient) Validate() error {
 	return f.ValidateErr
@@ -0,0 +1,113 @@
+package testutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// CertificateOptions describes a certificate created by NewCertificate.
+// Zero values get defaults suitable for most tests.
+type CertificateOptions struct {
+	CommonName   string
+	DNSNames     []string
+	IPAddresses  []net.IP
+	Emails       []string
+	URIs         []*url.URL
+	SerialNumber int64     // Defaults to 1
+	NotBefore    time.Time // Defaults to an hour ago
+	NotAfter     time.Time // Defaults to an hour from now
+	IsCA         bool      // Marks the certificate as a CA allowed to sign others
+	ExtKeyUsage  []x509.ExtKeyUsage
+
+	// Key is the certificate's key pair. Defaults to a new P-256 key.
+	Key crypto.Signer
+
+	// Parent issues the certificate. It is self-signed when Parent is nil.
+	Parent *Certificate
+}
+
+// Certificate is a parsed test certificate together with its private key.
+type Certificate struct {
+	*x509.Certificate
+	Key crypto.Signer
+}
+
+// NewCertificate creates a certificate for tests, self-signed unless
+// opts.Parent is set.
+//
+// Example usage:
+//
+//	ca := testutil.NewCertificate(t, testutil.CertificateOptions{CommonName: "Test CA", IsCA: true})
+//	leaf := testutil.NewCertificate(t, testutil.CertificateOptions{CommonName: "api.example.com", Parent: ca})
+func NewCertificate(t *testing.T, opts CertificateOptions) *Certificate {
+	t.Helper()
+
+	key := opts.Key
+	if key == nil {
+		var err error
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(opts.SerialNumber),
+		Subject:        pkix.Name{CommonName: opts.CommonName},
+		DNSNames:       opts.DNSNames,
+		IPAddresses:    opts.IPAddresses,
+		EmailAddresses: opts.Emails,
+		URIs:           opts.URIs,
+		NotBefore:      opts.NotBefore,
+		NotAfter:       opts.NotAfter,
+		ExtKeyUsage:    opts.ExtKeyUsage,
+	}
+	if opts.SerialNumber == 0 {
+		template.SerialNumber = big.NewInt(1)
+	}
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	if opts.IsCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+
+	parent, signer := template, key
+	if opts.Parent != nil {
+		parent, signer = opts.Parent.Certificate, opts.Parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &Certificate{Certificate: cert, Key: key}
+}
+
+// CertPEM returns the certificate as a PEM CERTIFICATE block.
+func (c *Certificate) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+}
+
+// KeyPEM returns the private key as a PKCS#8 PEM PRIVATE KEY block.
+func (c *Certificate) KeyPEM(t *testing.T) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
@@ -46,6 +46,7 @@ type RecordedCall struct {
 	Command string
 	Args    []string
 	Context context.Context
+	Stdin   []byte // Set by ExecuteWithInput
 }
 
 // NewMockCommandExecutor creates a new mock executor with empty responses.
@@ -58,6 +59,16 @@ func NewMockCommandExecutor() *MockCommandExecutor {
 
 // Execute returns the mocked response for the given command.
 func (m *MockCommandExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	return m.execute(ctx, nil, name, args)
+}
+
+// ExecuteWithInput returns the mocked response for the given command and
+// records the stdin input for verification.
+func (m *MockCommandExecutor) ExecuteWithInput(ctx context.Context, input []byte, name string, args ...string) ([]byte, []byte, error) {
+	return m.execute(ctx, append([]byte{}, input...), name, args)
+}
+
+func (m *MockCommandExecutor) execute(ctx context.Context, input []byte, name string, args []string) ([]byte, []byte, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -66,6 +77,7 @@ func (m *MockCommandExecutor) Execute(ctx context.Context, name string, args ...
 		Command: name,
 		Args:    args,
 		Context: ctx,
+		Stdin:   input,
 	})
 
 	// Build the command key for lookup